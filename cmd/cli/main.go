@@ -17,6 +17,7 @@ package main
 import (
 	"fmt"
 	"math"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -74,6 +75,13 @@ var (
 		Envar("HOSTMGR_URL").
 		URL()
 
+	placementURL = app.Flag(
+		"placement",
+		"name of the placement engine address to use (grpc), used by dry-run "+
+			"placement (set $PLACEMENT_URL to override)").
+		Envar("PLACEMENT_URL").
+		URL()
+
 	clusterName = app.Flag(
 		"clusterName",
 		"name of the cluster you want to connect to."+
@@ -124,6 +132,8 @@ var (
 	jobCreateConfig     = jobCreate.Arg("config", "YAML job configuration").Required().ExistingFile()
 	jobCreateSecretPath = jobCreate.Flag("secret-path", "secret mount path").Default("").String()
 	jobCreateSecret     = jobCreate.Flag("secret-data", "secret data string").Default("").String()
+	jobCreateDryRun     = jobCreate.Flag("dry-run", "show where the job would be placed "+
+		"without creating it, requires --placement").Default("false").Bool()
 
 	jobDelete     = job.Command("delete", "delete a job")
 	jobDeleteName = jobDelete.Arg("job", "job identifier").Required().String()
@@ -170,6 +180,10 @@ var (
 	jobUpdateSecretPath = jobUpdate.Flag("secret-path", "secret mount path").Default("").String()
 	jobUpdateSecret     = jobUpdate.Flag("secret-data", "secret data string").Default("").String()
 
+	jobScale              = job.Command("scale", "change the number of instances of a running batch job")
+	jobScaleName          = jobScale.Arg("job", "job identifier").Required().String()
+	jobScaleInstanceCount = jobScale.Arg("instances", "new number of instances of the job").Required().Uint32()
+
 	jobRestart                = job.Command("rolling-restart", "restart instances in a job using rolling-restart")
 	jobRestartName            = jobRestart.Arg("job", "job identifier").Required().String()
 	jobRestartBatchSize       = jobRestart.Arg("batch-size", "batch size for the restart").Required().Uint32()
@@ -193,6 +207,10 @@ var (
 
 	jobGetActiveJobs = job.Command("active-list", "get a list of active jobs")
 
+	jobDisruptions     = job.Command("disruptions", "report the instance hours a job lost to disruptions by Peloton, by cause")
+	jobDisruptionsName = jobDisruptions.Arg("job", "job identifier").Required().String()
+	jobDisruptionsDays = jobDisruptions.Flag("days", "number of days to look back for disruptions, at most 90").Default("7").Uint32()
+
 	// Top level job command for stateless jobs
 	stateless = job.Command("stateless", "manage stateless jobs")
 
@@ -386,10 +404,13 @@ var (
 	podStart        = pod.Command("start", "start a pod")
 	podStartPodName = podStart.Arg("name", "pod name").Required().String()
 
-	podLogsGet         = pod.Command("logs", "show pod logs")
-	podLogsGetFileName = podLogsGet.Flag("filename", "log filename to browse").Default("stdout").Short('f').String()
-	podLogsGetPodName  = podLogsGet.Arg("name", "pod name").Required().String()
-	podLogsGetPodID    = podLogsGet.Flag("id", "pod identifier").Short('p').String()
+	podLogsGet           = pod.Command("logs", "show pod logs")
+	podLogsGetFileName   = podLogsGet.Flag("filename", "log filename to browse").Default("stdout").String()
+	podLogsGetPodName    = podLogsGet.Arg("name", "pod name").Required().String()
+	podLogsGetPodID      = podLogsGet.Flag("id", "pod identifier").Short('p').String()
+	podLogsGetFollow     = podLogsGet.Flag("follow", "keep streaming the logs until the pod terminates").Short('f').Default("false").Bool()
+	podLogsGetOffset     = podLogsGet.Flag("offset", "byte offset to start reading from, negative values are relative to the end of the file").Short('o').Default("0").Int64()
+	podLogsGetLimitBytes = podLogsGet.Flag("limit-bytes", "maximum number of bytes to read, 0 means no limit").Short('l').Default("0").Int64()
 
 	podRestart     = pod.Command("restart", "restart a pod")
 	podRestartName = podRestart.Arg("name", "pod name").Required().String()
@@ -529,6 +550,10 @@ var (
 	resPoolDeletePath = resPoolDelete.Arg("respool", "complete path of the "+
 		"resource pool starting from the root").Required().String()
 
+	resPoolForecast     = resPool.Command("forecast", "capacity forecast and alerts of a resource pool")
+	resPoolForecastPath = resPoolForecast.Arg("respool", "complete path of the "+
+		"resource pool starting from the root").Default("/").String()
+
 	// Top level host manager command
 	host            = app.Command("host", "manage hosts")
 	hostMaintenance = host.Command("maintenance", "host maintenance")
@@ -551,6 +576,47 @@ var (
 	volumeDelete         = volume.Command("delete", "delete a volume")
 	volumeDeleteVolumeID = volumeDelete.Arg("volume", "volume identifier").Required().String()
 
+	// Top level job template command
+	jobTemplate = app.Command("template", "manage job templates")
+
+	templateCreate     = jobTemplate.Command("create", "create a job template")
+	templateCreateSpec = templateCreate.Arg("spec", "YAML template specification").Required().ExistingFile()
+
+	templateUpdate        = jobTemplate.Command("update", "create a new version of a job template")
+	templateUpdateSpec    = templateUpdate.Arg("spec", "YAML template specification").Required().ExistingFile()
+	templateUpdateVersion = templateUpdate.Arg("version", "current version of the template for concurrency control").Required().Uint64()
+	templateUpdateRollout = templateUpdate.Flag("rollout",
+		"roll out the new version to the jobs created from a stateless template").Default("false").Bool()
+	templateUpdateBatchSize = templateUpdate.Flag("batch-size", "batch size for the rollout").Default("0").Uint32()
+
+	templateGet        = jobTemplate.Command("get", "get a job template")
+	templateGetName    = templateGet.Arg("name", "template name").Required().String()
+	templateGetVersion = templateGet.Flag("version", "template version, default the latest version").Default("0").Short('v').Uint64()
+
+	templateList = jobTemplate.Command("list", "list job templates")
+
+	templateDelete     = jobTemplate.Command("delete", "delete a job template")
+	templateDeleteName = templateDelete.Arg("name", "template name").Required().String()
+
+	templateInstantiate        = jobTemplate.Command("instantiate", "create a stateless or batch job from a job template")
+	templateInstantiateName    = templateInstantiate.Arg("name", "template name").Required().String()
+	templateInstantiateVersion = templateInstantiate.Flag("version", "template version, default the latest version").Default("0").Short('v').Uint64()
+	templateInstantiateParams  = templateInstantiate.Flag("param",
+		"template parameter value (specify multiple times) (name=value syntax)").Short('p').StringMap()
+	templateInstantiateID          = templateInstantiate.Flag("jobID", "optional job identifier, must be UUID format").Short('i').String()
+	templateInstantiateResPoolPath = templateInstantiate.Flag("respool", "complete path of the "+
+		"resource pool starting from the root, default the one in the template").Default("").String()
+	templateInstantiateBatchSize = templateInstantiate.Flag("batch-size", "batch size for the create process").Default("0").Uint32()
+
+	// Top level audit command
+	audit = app.Command("audit", "query the audit log of the mutating API calls")
+
+	auditList       = audit.Command("list", "list the audit records of a resource, most recent first")
+	auditListTarget = auditList.Arg("target", "targeted resource, such as a job ID, a pod name or a hostname").Required().String()
+	auditListLimit  = auditList.Flag("limit", "maximum number of records to list, 0 for all").Default("0").Short('n').Uint32()
+	auditListStart  = auditList.Flag("start", "start of the time range in RFC3339 format, defaults to 7 days before the end").Default("").String()
+	auditListEnd    = auditList.Flag("end", "end of the time range in RFC3339 format, defaults to now").Default("").String()
+
 	// Top level job update command
 	update = app.Command("update", "manage job updates")
 
@@ -608,6 +674,12 @@ var (
 	updateResumeOpaqueData = updateResume.Flag("opaque-data",
 		"opaque data provided by the user").Default("").String()
 
+	// command to rollback an update
+	updateRollback           = update.Command("rollback", "rollback a job update")
+	updateRollbackID         = updateRollback.Arg("update-id", "update identifier").Required().String()
+	updateRollbackOpaqueData = updateRollback.Flag("opaque-data",
+		"opaque data provided by the user").Default("").String()
+
 	// Top level hostmgr command
 	hostmgr = app.Command("hostmgr", "top level command for hostmgr")
 
@@ -769,7 +841,16 @@ func main() {
 		basicAuthConfigPtr = &basicAuthConfig
 	}
 
-	client, err := pc.New(discovery, *timeout, basicAuthConfigPtr, *jsonFormat)
+	// placement engines are not leader elected, so their address can't be
+	// found by service discovery and is always static.
+	var placement *url.URL
+	if *placementURL != nil {
+		u := **placementURL
+		u.Host = u.String()
+		placement = &u
+	}
+
+	client, err := pc.New(discovery, placement, *timeout, basicAuthConfigPtr, *jsonFormat)
 	if err != nil {
 		app.FatalIfError(err, "Fail to initialize client")
 	}
@@ -778,7 +859,8 @@ func main() {
 	switch cmd {
 	case jobCreate.FullCommand():
 		err = client.JobCreateAction(*jobCreateID, *jobCreateResPoolPath,
-			*jobCreateConfig, *jobCreateSecretPath, []byte(*jobCreateSecret))
+			*jobCreateConfig, *jobCreateSecretPath, []byte(*jobCreateSecret),
+			*jobCreateDryRun)
 	case jobDelete.FullCommand():
 		err = client.JobDeleteAction(*jobDeleteName)
 	case jobStop.FullCommand():
@@ -800,6 +882,8 @@ func main() {
 	case jobUpdate.FullCommand():
 		err = client.JobUpdateAction(*jobUpdateID, *jobUpdateConfig,
 			*jobUpdateSecretPath, []byte(*jobUpdateSecret))
+	case jobScale.FullCommand():
+		err = client.JobScaleAction(*jobScaleName, *jobScaleInstanceCount)
 	case jobRestart.FullCommand():
 		err = client.JobRestartAction(*jobRestartName, *jobRestartResourceVersion, *jobRestartInstanceRanges, *jobRestartBatchSize)
 	case jobStart.FullCommand():
@@ -810,6 +894,8 @@ func main() {
 		err = client.JobGetCacheAction(*jobGetCacheName)
 	case jobGetActiveJobs.FullCommand():
 		err = client.JobGetActiveJobsAction()
+	case jobDisruptions.FullCommand():
+		err = client.JobDisruptionsAction(*jobDisruptionsName, *jobDisruptionsDays)
 	case taskGet.FullCommand():
 		err = client.TaskGetAction(*taskGetJobName, *taskGetInstanceID)
 	case taskGetCache.FullCommand():
@@ -854,10 +940,39 @@ func main() {
 		err = client.ResPoolDumpAction(*resPoolDumpFormat)
 	case resPoolDelete.FullCommand():
 		err = client.ResPoolDeleteAction(*resPoolDeletePath)
+	case resPoolForecast.FullCommand():
+		err = client.ResPoolForecastAction(*resPoolForecastPath)
 	case volumeList.FullCommand():
 		err = client.VolumeListAction(*volumeListJobName)
 	case volumeDelete.FullCommand():
 		err = client.VolumeDeleteAction(*volumeDeleteVolumeID)
+	case templateCreate.FullCommand():
+		err = client.TemplateCreateAction(*templateCreateSpec)
+	case templateUpdate.FullCommand():
+		err = client.TemplateUpdateAction(
+			*templateUpdateSpec,
+			*templateUpdateVersion,
+			*templateUpdateRollout,
+			*templateUpdateBatchSize,
+		)
+	case templateGet.FullCommand():
+		err = client.TemplateGetAction(*templateGetName, *templateGetVersion)
+	case templateList.FullCommand():
+		err = client.TemplateListAction()
+	case templateDelete.FullCommand():
+		err = client.TemplateDeleteAction(*templateDeleteName)
+	case templateInstantiate.FullCommand():
+		err = client.TemplateInstantiateAction(
+			*templateInstantiateName,
+			*templateInstantiateVersion,
+			*templateInstantiateParams,
+			*templateInstantiateID,
+			*templateInstantiateResPoolPath,
+			*templateInstantiateBatchSize,
+		)
+	case auditList.FullCommand():
+		err = client.AuditListAction(
+			*auditListTarget, *auditListLimit, *auditListStart, *auditListEnd)
 	case updateCreate.FullCommand():
 		err = client.UpdateCreateAction(
 			*updateJobID,
@@ -885,6 +1000,8 @@ func main() {
 		err = client.UpdatePauseAction(*updatePauseID, *updatePauseOpaqueData)
 	case updateResume.FullCommand():
 		err = client.UpdateResumeAction(*updateResumeID, *updateResumeOpaqueData)
+	case updateRollback.FullCommand():
+		err = client.UpdateRollbackAction(*updateRollbackID, *updateRollbackOpaqueData)
 	case offers.FullCommand():
 		err = client.OffersGetAction()
 	case getHosts.FullCommand():
@@ -988,7 +1105,14 @@ func main() {
 			*workflowEventsJob,
 			*workflowEventsInstance)
 	case podLogsGet.FullCommand():
-		err = client.PodLogsGetAction(*podLogsGetFileName, *podLogsGetPodName, *podLogsGetPodID)
+		err = client.PodLogsGetAction(
+			*podLogsGetFileName,
+			*podLogsGetPodName,
+			*podLogsGetPodID,
+			*podLogsGetFollow,
+			*podLogsGetOffset,
+			*podLogsGetLimitBytes,
+		)
 	case podRestart.FullCommand():
 		err = client.PodRestartAction(*podRestartName)
 	case podStop.FullCommand():
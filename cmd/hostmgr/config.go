@@ -15,6 +15,7 @@
 package main
 
 import (
+	"github.com/uber/peloton/pkg/audit"
 	"github.com/uber/peloton/pkg/auth"
 	"github.com/uber/peloton/pkg/common/health"
 	"github.com/uber/peloton/pkg/common/leader"
@@ -22,6 +23,7 @@ import (
 	"github.com/uber/peloton/pkg/common/metrics"
 	"github.com/uber/peloton/pkg/hostmgr/config"
 	"github.com/uber/peloton/pkg/hostmgr/mesos"
+	"github.com/uber/peloton/pkg/ratelimit"
 	storage "github.com/uber/peloton/pkg/storage/config"
 )
 
@@ -35,4 +37,6 @@ type Config struct {
 	Health       health.Config         `yaml:"health"`
 	SentryConfig logging.SentryConfig  `yaml:"sentry"`
 	Auth         auth.Config           `yaml:"auth"`
+	Audit        audit.Config          `yaml:"audit"`
+	RateLimit    ratelimit.Config      `yaml:"rate_limit"`
 }
@@ -22,6 +22,7 @@ import (
 
 	"github.com/uber/peloton/.gen/peloton/private/resmgrsvc"
 
+	audit_impl "github.com/uber/peloton/pkg/audit/impl"
 	"github.com/uber/peloton/pkg/auth"
 	auth_impl "github.com/uber/peloton/pkg/auth/impl"
 	"github.com/uber/peloton/pkg/common"
@@ -48,6 +49,7 @@ import (
 	"github.com/uber/peloton/pkg/hostmgr/task"
 	"github.com/uber/peloton/pkg/middleware/inbound"
 	"github.com/uber/peloton/pkg/middleware/outbound"
+	"github.com/uber/peloton/pkg/ratelimit"
 	"github.com/uber/peloton/pkg/storage/stores"
 
 	log "github.com/sirupsen/logrus"
@@ -420,6 +422,36 @@ func main() {
 
 	authInboundMiddleware := inbound.NewAuthInboundMiddleware(securityManager)
 
+	auditSink, err := audit_impl.CreateNewSink(
+		&cfg.Audit,
+		&cfg.Storage.Cassandra,
+		rootScope,
+	)
+	if err != nil {
+		log.WithError(err).
+			Fatal("Could not enable audit feature")
+	}
+	auditInboundMiddleware := inbound.NewAuditInboundMiddleware(
+		&cfg.Audit,
+		auditSink,
+		rootScope.SubScope("audit"),
+	)
+
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled() {
+		limiter, err = ratelimit.NewLimiter(
+			&cfg.RateLimit,
+			rootScope.SubScope("rate_limit"),
+		)
+		if err != nil {
+			log.WithError(err).
+				Fatal("Could not enable rate limit feature")
+		}
+		limiter.Start()
+		defer limiter.Stop()
+	}
+	rateLimitInboundMiddleware := inbound.NewRateLimitInboundMiddleware(limiter)
+
 	securityClient, err := auth_impl.CreateNewSecurityClient(&cfg.Auth)
 	if err != nil {
 		log.WithError(err).
@@ -436,9 +468,9 @@ func main() {
 			Tally: rootScope,
 		},
 		InboundMiddleware: yarpc.InboundMiddleware{
-			Unary:  authInboundMiddleware,
-			Oneway: authInboundMiddleware,
-			Stream: authInboundMiddleware,
+			Unary:  yarpc.UnaryInboundMiddleware(authInboundMiddleware, rateLimitInboundMiddleware, auditInboundMiddleware),
+			Oneway: yarpc.OnewayInboundMiddleware(authInboundMiddleware, rateLimitInboundMiddleware, auditInboundMiddleware),
+			Stream: yarpc.StreamInboundMiddleware(authInboundMiddleware, rateLimitInboundMiddleware, auditInboundMiddleware),
 		},
 		OutboundMiddleware: yarpc.OutboundMiddleware{
 			Unary:  authOutboundMiddleware,
@@ -549,6 +581,7 @@ func main() {
 		bin_packing.CreateRanker(cfg.HostManager.BinPacking),
 		cfg.HostManager.BinPackingRefreshIntervalSec,
 		cfg.HostManager.HostPlacingOfferStatusTimeout,
+		maintenanceHostInfoMap,
 	)
 
 	maintenanceQueue := queue.NewMaintenanceQueue()
@@ -20,8 +20,10 @@ import (
 	"time"
 
 	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+	"github.com/uber/peloton/.gen/peloton/api/v0/respool"
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 
+	audit_impl "github.com/uber/peloton/pkg/audit/impl"
 	"github.com/uber/peloton/pkg/auth"
 	auth_impl "github.com/uber/peloton/pkg/auth/impl"
 	"github.com/uber/peloton/pkg/common"
@@ -35,26 +37,32 @@ import (
 	"github.com/uber/peloton/pkg/common/rpc"
 	"github.com/uber/peloton/pkg/hostmgr/mesos/yarpc/peer"
 	"github.com/uber/peloton/pkg/jobmgr"
+	"github.com/uber/peloton/pkg/jobmgr/auditsvc"
 	"github.com/uber/peloton/pkg/jobmgr/cached"
 	"github.com/uber/peloton/pkg/jobmgr/goalstate"
 	"github.com/uber/peloton/pkg/jobmgr/jobsvc"
 	"github.com/uber/peloton/pkg/jobmgr/jobsvc/private"
+	"github.com/uber/peloton/pkg/jobmgr/jobsvc/stateful"
 	"github.com/uber/peloton/pkg/jobmgr/jobsvc/stateless"
 	"github.com/uber/peloton/pkg/jobmgr/logmanager"
 	"github.com/uber/peloton/pkg/jobmgr/podsvc"
 	"github.com/uber/peloton/pkg/jobmgr/task/activermtask"
 	"github.com/uber/peloton/pkg/jobmgr/task/deadline"
+	"github.com/uber/peloton/pkg/jobmgr/task/disruption"
 	"github.com/uber/peloton/pkg/jobmgr/task/event"
 	"github.com/uber/peloton/pkg/jobmgr/task/launcher"
 	"github.com/uber/peloton/pkg/jobmgr/task/placement"
 	"github.com/uber/peloton/pkg/jobmgr/task/preemptor"
 	"github.com/uber/peloton/pkg/jobmgr/tasksvc"
+	"github.com/uber/peloton/pkg/jobmgr/templatesvc"
 	"github.com/uber/peloton/pkg/jobmgr/updatesvc"
+	handlerutil "github.com/uber/peloton/pkg/jobmgr/util/handler"
 	"github.com/uber/peloton/pkg/jobmgr/volumesvc"
 	"github.com/uber/peloton/pkg/jobmgr/watchsvc"
 	"github.com/uber/peloton/pkg/jobmgr/workflow/progress"
 	"github.com/uber/peloton/pkg/middleware/inbound"
 	"github.com/uber/peloton/pkg/middleware/outbound"
+	"github.com/uber/peloton/pkg/ratelimit"
 	ormobjects "github.com/uber/peloton/pkg/storage/objects"
 	"github.com/uber/peloton/pkg/storage/stores"
 
@@ -399,6 +407,36 @@ func main() {
 	authInboundMiddleware := inbound.NewAuthInboundMiddleware(securityManager)
 	yarpcMetricsMiddleware := &inbound.YAPRCMetricsInboundMiddleware{Scope: rootScope.SubScope("yarpc")}
 
+	auditSink, err := audit_impl.CreateNewSink(
+		&cfg.Audit,
+		&cfg.Storage.Cassandra,
+		rootScope,
+	)
+	if err != nil {
+		log.WithError(err).
+			Fatal("Could not enable audit feature")
+	}
+	auditInboundMiddleware := inbound.NewAuditInboundMiddleware(
+		&cfg.Audit,
+		auditSink,
+		rootScope.SubScope("audit"),
+	)
+
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled() {
+		limiter, err = ratelimit.NewLimiter(
+			&cfg.RateLimit,
+			rootScope.SubScope("rate_limit"),
+		)
+		if err != nil {
+			log.WithError(err).
+				Fatal("Could not enable rate limit feature")
+		}
+		limiter.Start()
+		defer limiter.Stop()
+	}
+	rateLimitInboundMiddleware := inbound.NewRateLimitInboundMiddleware(limiter)
+
 	securityClient, err := auth_impl.CreateNewSecurityClient(&cfg.Auth)
 	if err != nil {
 		log.WithError(err).
@@ -415,9 +453,9 @@ func main() {
 			Tally: rootScope,
 		},
 		InboundMiddleware: yarpc.InboundMiddleware{
-			Unary:  yarpc.UnaryInboundMiddleware(authInboundMiddleware, yarpcMetricsMiddleware),
-			Stream: yarpc.StreamInboundMiddleware(authInboundMiddleware, yarpcMetricsMiddleware),
-			Oneway: yarpc.OnewayInboundMiddleware(authInboundMiddleware, yarpcMetricsMiddleware),
+			Unary:  yarpc.UnaryInboundMiddleware(authInboundMiddleware, rateLimitInboundMiddleware, auditInboundMiddleware, yarpcMetricsMiddleware),
+			Stream: yarpc.StreamInboundMiddleware(authInboundMiddleware, rateLimitInboundMiddleware, auditInboundMiddleware, yarpcMetricsMiddleware),
+			Oneway: yarpc.OnewayInboundMiddleware(authInboundMiddleware, rateLimitInboundMiddleware, auditInboundMiddleware, yarpcMetricsMiddleware),
 		},
 		OutboundMiddleware: yarpc.OutboundMiddleware{
 			Unary:  authOutboundMiddleware,
@@ -447,6 +485,8 @@ func main() {
 		cfg.JobManager.Watch,
 	)
 
+	disruptionTracker := disruption.NewTracker(ormStore, rootScope)
+
 	jobFactory := cached.InitJobFactory(
 		store, // store implements JobStore
 		store, // store implements TaskStore
@@ -454,7 +494,17 @@ func main() {
 		store, // store implements VolumeStore
 		ormStore,
 		rootScope,
-		[]cached.JobTaskListener{watchsvc.NewWatchListener(watchProcessor)},
+		[]cached.JobTaskListener{
+			watchsvc.NewWatchListener(watchProcessor),
+			disruptionTracker,
+		},
+	)
+	rateLimitInboundMiddleware.SetRespoolResolver(
+		handlerutil.NewRespoolPathResolver(
+			jobFactory,
+			respool.NewResourceManagerYARPCClient(
+				dispatcher.ClientConfig(common.PelotonResourceManager)),
+		),
 	)
 
 	// Register WorkflowProgressCheck
@@ -472,6 +522,7 @@ func main() {
 	launcher.InitTaskLauncher(
 		dispatcher,
 		common.PelotonHostManager,
+		common.PelotonResourceManager,
 		jobFactory,
 		store, // store implements TaskStore
 		store, // store implements VolumeStore
@@ -536,6 +587,7 @@ func main() {
 		store, // store implements VolumeStore
 		jobFactory,
 		goalStateDriver,
+		disruptionTracker,
 		[]event.Listener{},
 		rootScope,
 	)
@@ -563,7 +615,7 @@ func main() {
 		log.Fatalf("Unable to create leader candidate: %v", err)
 	}
 
-	jobsvc.InitServiceHandler(
+	jobHandler := jobsvc.InitServiceHandler(
 		dispatcher,
 		rootScope,
 		store, // store implements JobStore
@@ -587,7 +639,7 @@ func main() {
 		candidate,
 	)
 
-	stateless.InitV1AlphaJobServiceHandler(
+	statelessHandler := stateless.InitV1AlphaJobServiceHandler(
 		dispatcher,
 		store,
 		store,
@@ -600,6 +652,28 @@ func main() {
 		activeJobCache,
 	)
 
+	stateful.InitV1AlphaJobServiceHandler(
+		dispatcher,
+		store, // store implements TaskStore
+		store, // store implements VolumeStore
+		statelessHandler,
+	)
+
+	templatesvc.InitServiceHandler(
+		dispatcher,
+		rootScope,
+		ormStore,
+		statelessHandler,
+		jobHandler,
+		candidate,
+	)
+
+	auditsvc.InitServiceHandler(
+		dispatcher,
+		rootScope,
+		ormStore,
+	)
+
 	tasksvc.InitServiceHandler(
 		dispatcher,
 		rootScope,
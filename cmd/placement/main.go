@@ -32,13 +32,12 @@ import (
 	"github.com/uber/peloton/pkg/middleware/outbound"
 	"github.com/uber/peloton/pkg/placement"
 	"github.com/uber/peloton/pkg/placement/config"
+	"github.com/uber/peloton/pkg/placement/dryrun"
 	"github.com/uber/peloton/pkg/placement/hosts"
 	tally_metrics "github.com/uber/peloton/pkg/placement/metrics"
 	"github.com/uber/peloton/pkg/placement/offers"
 	"github.com/uber/peloton/pkg/placement/plugins"
-	"github.com/uber/peloton/pkg/placement/plugins/batch"
-	mimir_strategy "github.com/uber/peloton/pkg/placement/plugins/mimir"
-	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/algorithms"
+	"github.com/uber/peloton/pkg/placement/plugins/registry"
 	"github.com/uber/peloton/pkg/placement/tasks"
 
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
@@ -225,6 +224,13 @@ func main() {
 		overridePlacementStrategy(*taskType, &cfg)
 	}
 
+	// The strategy configured for the task type of the engine takes
+	// precedence over the default strategy.
+	if strategy := cfg.Placement.Strategies.Value(
+		cfg.Placement.TaskType); strategy != "" {
+		cfg.Placement.Strategy = strategy
+	}
+
 	// Parse and setup peloton auth
 	if len(*authType) != 0 {
 		cfg.Auth.AuthType = auth.Type(*authType)
@@ -339,12 +345,6 @@ func main() {
 		},
 	})
 
-	log.Debug("Starting YARPC dispatcher")
-	if err := dispatcher.Start(); err != nil {
-		log.Fatalf("Unable to start dispatcher: %v", err)
-	}
-	defer dispatcher.Stop()
-
 	tallyMetrics := tally_metrics.NewMetrics(
 		rootScope.SubScope("placement"))
 	resourceManager := resmgrsvc.NewResourceManagerServiceYARPCClient(
@@ -367,8 +367,25 @@ func main() {
 		tallyMetrics,
 	)
 
+	registry.Init()
 	strategy := initPlacementStrategy(cfg)
 
+	// The dry-run handler uses its own strategy instance as the strategy
+	// of the engine may not be concurrency safe.
+	dryrun.InitServiceHandler(
+		dispatcher,
+		&cfg.Placement,
+		offerService,
+		resourceManager,
+		initPlacementStrategy(cfg),
+	)
+
+	log.Debug("Starting YARPC dispatcher")
+	if err := dispatcher.Start(); err != nil {
+		log.Fatalf("Unable to start dispatcher: %v", err)
+	}
+	defer dispatcher.Stop()
+
 	pool := async.NewPool(async.PoolOptions{
 		MaxWorkers: cfg.Placement.Concurrency,
 	}, nil)
@@ -401,15 +418,10 @@ func main() {
 }
 
 func initPlacementStrategy(cfg config.Config) plugins.Strategy {
-	var strategy plugins.Strategy
-	switch cfg.Placement.Strategy {
-	case config.Batch:
-		strategy = batch.New()
-	case config.Mimir:
-		// TODO avyas check mimir concurrency parameters
-		cfg.Placement.Concurrency = 1
-		placer := algorithms.NewPlacer(4, 300)
-		strategy = mimir_strategy.New(placer, &cfg.Placement)
+	strategy := registry.CreateStrategy(&cfg.Placement)
+	if strategy == nil {
+		log.WithField("strategy", cfg.Placement.Strategy).
+			Fatal("Invalid placement strategy")
 	}
 	return strategy
 }
@@ -15,11 +15,13 @@
 package main
 
 import (
+	"github.com/uber/peloton/pkg/audit"
 	"github.com/uber/peloton/pkg/auth"
 	"github.com/uber/peloton/pkg/common/health"
 	"github.com/uber/peloton/pkg/common/leader"
 	"github.com/uber/peloton/pkg/common/logging"
 	"github.com/uber/peloton/pkg/common/metrics"
+	"github.com/uber/peloton/pkg/ratelimit"
 	"github.com/uber/peloton/pkg/resmgr"
 	storage "github.com/uber/peloton/pkg/storage/config"
 )
@@ -33,4 +35,6 @@ type Config struct {
 	Health       health.Config         `yaml:"health"`
 	SentryConfig logging.SentryConfig  `yaml:"sentry"`
 	Auth         auth.Config           `yaml:"auth"`
+	Audit        audit.Config          `yaml:"audit"`
+	RateLimit    ratelimit.Config      `yaml:"rate_limit"`
 }
@@ -19,6 +19,7 @@ import (
 
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 
+	audit_impl "github.com/uber/peloton/pkg/audit/impl"
 	"github.com/uber/peloton/pkg/auth"
 	auth_impl "github.com/uber/peloton/pkg/auth/impl"
 	"github.com/uber/peloton/pkg/common"
@@ -32,13 +33,16 @@ import (
 	"github.com/uber/peloton/pkg/hostmgr/mesos/yarpc/peer"
 	"github.com/uber/peloton/pkg/middleware/inbound"
 	"github.com/uber/peloton/pkg/middleware/outbound"
+	"github.com/uber/peloton/pkg/ratelimit"
 	"github.com/uber/peloton/pkg/resmgr"
 	"github.com/uber/peloton/pkg/resmgr/entitlement"
+	"github.com/uber/peloton/pkg/resmgr/forecast"
 	maintenance "github.com/uber/peloton/pkg/resmgr/host"
 	"github.com/uber/peloton/pkg/resmgr/preemption"
 	"github.com/uber/peloton/pkg/resmgr/respool"
 	"github.com/uber/peloton/pkg/resmgr/respool/respoolsvc"
 	"github.com/uber/peloton/pkg/resmgr/task"
+	ormobjects "github.com/uber/peloton/pkg/storage/objects"
 	"github.com/uber/peloton/pkg/storage/stores"
 
 	log "github.com/sirupsen/logrus"
@@ -227,6 +231,10 @@ func getConfig(cfgFiles ...string) Config {
 		cfg.Auth.Path = *authConfigFile
 	}
 
+	if err := cfg.ResManager.Validate(); err != nil {
+		log.WithError(err).Fatal("Invalid Resource Manager config")
+	}
+
 	log.
 		WithField("config", cfg).
 		Info("Loaded Resource Manager config")
@@ -270,6 +278,12 @@ func main() {
 	mux.HandleFunc(buildversion.Get, buildversion.Handler(version))
 
 	store := stores.MustCreateStore(&cfg.Storage, rootScope)
+	ormStore, ormErr := ormobjects.NewCassandraStore(
+		&cfg.Storage.Cassandra,
+		rootScope)
+	if ormErr != nil {
+		log.WithError(ormErr).Fatal("Failed to create ORM store for Cassandra")
+	}
 
 	// Create both HTTP and GRPC inbounds
 	inbounds := rpc.NewInbounds(
@@ -315,6 +329,36 @@ func main() {
 	authInboundMiddleware := inbound.NewAuthInboundMiddleware(securityManager)
 	yarpcMetricsMiddleware := &inbound.YAPRCMetricsInboundMiddleware{Scope: rootScope.SubScope("yarpc")}
 
+	auditSink, err := audit_impl.CreateNewSink(
+		&cfg.Audit,
+		&cfg.Storage.Cassandra,
+		rootScope,
+	)
+	if err != nil {
+		log.WithError(err).
+			Fatal("Could not enable audit feature")
+	}
+	auditInboundMiddleware := inbound.NewAuditInboundMiddleware(
+		&cfg.Audit,
+		auditSink,
+		rootScope.SubScope("audit"),
+	)
+
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled() {
+		limiter, err = ratelimit.NewLimiter(
+			&cfg.RateLimit,
+			rootScope.SubScope("rate_limit"),
+		)
+		if err != nil {
+			log.WithError(err).
+				Fatal("Could not enable rate limit feature")
+		}
+		limiter.Start()
+		defer limiter.Stop()
+	}
+	rateLimitInboundMiddleware := inbound.NewRateLimitInboundMiddleware(limiter)
+
 	securityClient, err := auth_impl.CreateNewSecurityClient(&cfg.Auth)
 	if err != nil {
 		log.WithError(err).
@@ -332,9 +376,9 @@ func main() {
 			Tally: rootScope,
 		},
 		InboundMiddleware: yarpc.InboundMiddleware{
-			Unary:  yarpc.UnaryInboundMiddleware(authInboundMiddleware, leaderCheckMiddleware, yarpcMetricsMiddleware),
-			Oneway: yarpc.OnewayInboundMiddleware(authInboundMiddleware, leaderCheckMiddleware, yarpcMetricsMiddleware),
-			Stream: yarpc.StreamInboundMiddleware(authInboundMiddleware, leaderCheckMiddleware, yarpcMetricsMiddleware),
+			Unary:  yarpc.UnaryInboundMiddleware(authInboundMiddleware, leaderCheckMiddleware, rateLimitInboundMiddleware, auditInboundMiddleware, yarpcMetricsMiddleware),
+			Oneway: yarpc.OnewayInboundMiddleware(authInboundMiddleware, leaderCheckMiddleware, rateLimitInboundMiddleware, auditInboundMiddleware, yarpcMetricsMiddleware),
+			Stream: yarpc.StreamInboundMiddleware(authInboundMiddleware, leaderCheckMiddleware, rateLimitInboundMiddleware, auditInboundMiddleware, yarpcMetricsMiddleware),
 		},
 		OutboundMiddleware: yarpc.OutboundMiddleware{
 			Unary:  authOutboundMiddleware,
@@ -355,6 +399,15 @@ func main() {
 		store, // store implements JobStore
 		store, // store implements TaskStore
 		*cfg.ResManager.PreemptionConfig)
+	rateLimitInboundMiddleware.SetRespoolResolver(respool.NewPathResolver(tree))
+
+	// Initializing the capacity forecaster
+	forecaster := forecast.NewForecaster(
+		rootScope,
+		tree,
+		ormobjects.NewCapacityHistoryOps(ormStore),
+		cfg.ResManager.ForecastConfig,
+	)
 
 	// Initialize resource pool service handlers
 	respoolsvc.InitServiceHandler(
@@ -362,6 +415,7 @@ func main() {
 		rootScope,
 		tree,
 		store, // store implements RespoolStore
+		forecaster,
 	)
 
 	// Initializing the rmtasks in-memory tracker
@@ -385,6 +439,7 @@ func main() {
 		hostmgrClient,
 		tree,
 	)
+	tree.SetEntitlementCalculator(calculator)
 
 	// Initializing the task reconciler
 	reconciler := task.NewReconciler(
@@ -400,6 +455,7 @@ func main() {
 		cfg.ResManager.PreemptionConfig,
 		task.GetTracker(),
 		tree,
+		hostmgrClient,
 	)
 
 	// Initializing the host drainer
@@ -420,6 +476,8 @@ func main() {
 		hostmgrClient,
 		cfg.ResManager,
 	)
+	tree.SetMoveListener(serviceHandler)
+	forecaster.SetAlertListener(serviceHandler)
 
 	// Initialize recovery
 	recoveryHandler := resmgr.NewRecovery(
@@ -442,6 +500,7 @@ func main() {
 		reconciler,
 		preemptor,
 		drainer,
+		forecaster,
 	)
 	// Set nomination for leader check middleware
 	leaderCheckMiddleware.SetNomination(server)
@@ -0,0 +1,173 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/uber/peloton/pkg/hostmgr/binpacking"
+	placementconfig "github.com/uber/peloton/pkg/placement/config"
+	"github.com/uber/peloton/pkg/placement/plugins/registry"
+	rc "github.com/uber/peloton/pkg/resmgr/common"
+	"github.com/uber/peloton/pkg/simulator"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/uber-go/tally"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	version string
+	app     = kingpin.New(
+		"peloton-simulator",
+		"Replays a recorded cluster trace against the Peloton scheduling "+
+			"policies in virtual time")
+
+	debug = app.Flag(
+		"debug", "enable debug logging of the simulated components").
+		Short('d').
+		Default("false").
+		Bool()
+
+	traceFile = app.Arg("trace", "YAML trace of hosts, resource pools and jobs").
+			Required().
+			ExistingFile()
+
+	strategy = app.Flag(
+		"strategy", "placement strategy").
+		Default(string(placementconfig.Batch)).
+		Enum(
+			string(placementconfig.Batch),
+			string(placementconfig.Mimir),
+			string(placementconfig.Scoring))
+
+	scoringPolicy = app.Flag(
+		"scoring-policy", "host scoring policy of the scoring strategy").
+		Default(string(placementconfig.BestFit)).
+		Enum(
+			string(placementconfig.BestFit),
+			string(placementconfig.LeastAllocated))
+
+	ranker = app.Flag(
+		"ranker", "host manager bin packing ranker").
+		Default(binpacking.FirstFit).
+		Enum(binpacking.FirstFit, binpacking.DeFrag)
+
+	offerDequeueLimit = app.Flag(
+		"offer-dequeue-limit", "maximum hosts acquired per placement round by the mimir strategy").
+		Default("10").
+		Int()
+
+	tick = app.Flag(
+		"tick", "virtual time between admission and placement rounds").
+		Default("1s").
+		Duration()
+
+	entitlementPeriod = app.Flag(
+		"entitlement-period", "virtual time between entitlement calculations").
+		Default("60s").
+		Duration()
+
+	dequeueLimit = app.Flag(
+		"dequeue-limit", "maximum gangs admitted per resource pool and tick").
+		Default("1000").
+		Int()
+
+	preemption = app.Flag(
+		"preemption", "enable preemption of over-allocated resource pools").
+		Default("false").
+		Bool()
+
+	preemptionPeriod = app.Flag(
+		"preemption-period", "virtual time between preemption cycles").
+		Default("60s").
+		Duration()
+
+	sustainedOverAllocationCount = app.Flag(
+		"sustained-over-allocation-count",
+		"preemption cycles a resource pool has to be over-allocated before tasks are preempted").
+		Default("5").
+		Int()
+
+	maxDuration = app.Flag(
+		"max-duration", "maximum virtual time of the simulation").
+		Default("720h").
+		Duration()
+
+	output = app.Flag(
+		"output", "report format").
+		Short('o').
+		Default("text").
+		Enum("text", "json")
+)
+
+func main() {
+	app.Version(version)
+	app.HelpFlag.Short('h')
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	log.SetLevel(log.WarnLevel)
+	if *debug {
+		log.SetLevel(log.DebugLevel)
+	}
+
+	binpacking.Init()
+	registry.Init()
+
+	trace, err := simulator.LoadTrace(*traceFile)
+	if err != nil {
+		log.WithError(err).Fatal("Cannot load trace")
+	}
+
+	sim, err := simulator.New(trace, simulator.Config{
+		Strategy:          placementconfig.PlacementStrategy(*strategy),
+		Ranker:            *ranker,
+		OfferDequeueLimit: *offerDequeueLimit,
+		Scoring: placementconfig.ScoringConfig{
+			Policy: placementconfig.ScoringPolicy(*scoringPolicy),
+		},
+		Tick:              *tick,
+		EntitlementPeriod: *entitlementPeriod,
+		DequeueLimit:      *dequeueLimit,
+		Preemption: rc.PreemptionConfig{
+			Enabled:                      *preemption,
+			TaskPreemptionPeriod:         *preemptionPeriod,
+			SustainedOverAllocationCount: *sustainedOverAllocationCount,
+		},
+		MaxDuration: *maxDuration,
+	}, tally.NoopScope)
+	if err != nil {
+		log.WithError(err).Fatal("Cannot create simulator")
+	}
+
+	report, err := sim.Run(context.Background())
+	if err != nil {
+		log.WithError(err).Fatal("Simulation failed")
+	}
+
+	switch *output {
+	case "json":
+		buffer, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.WithError(err).Fatal("Cannot marshal report")
+		}
+		fmt.Println(string(buffer))
+	default:
+		report.Print(os.Stdout)
+	}
+}
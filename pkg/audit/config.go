@@ -0,0 +1,154 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import "time"
+
+const (
+	// _defaultQueueSize is the default number of audit records which can
+	// be waiting to be written to the sink
+	_defaultQueueSize = 10000
+	// _defaultWriteTimeout is the default timeout to write an audit record
+	_defaultWriteTimeout = time.Second
+)
+
+// Config is audit specific configuration
+type Config struct {
+	// SinkType is the type of sink the audit records are written to
+	SinkType Type `yaml:"sink_type"`
+	// Path is the path to the audit log file for FILE sink
+	Path string `yaml:"path"`
+	// Procedures is the list of the full names of the procedures to
+	// audit, such as peloton.api.v0.job.JobManager::Create.
+	// DefaultMutatingProcedures is used if it is empty.
+	Procedures []string `yaml:"procedures"`
+	// QueueSize is the maximum number of audit records waiting to be
+	// written to the sink. The records are dropped once it is full, so
+	// that a slow sink does not stall the API calls.
+	QueueSize int `yaml:"queue_size"`
+	// WriteTimeout is the timeout to write an audit record to the sink
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+}
+
+// DefaultMutatingProcedures is the list of the public API procedures
+// which mutate the state of Peloton. The private procedures, which are
+// called continuously by the Peloton components to each other, such as
+// EnqueueGangs, SetPlacements and LaunchTasks, are left out.
+var DefaultMutatingProcedures = []string{
+	// jobmgr v0 APIs
+	"peloton.api.v0.job.JobManager::Create",
+	"peloton.api.v0.job.JobManager::Delete",
+	"peloton.api.v0.job.JobManager::Refresh",
+	"peloton.api.v0.job.JobManager::Restart",
+	"peloton.api.v0.job.JobManager::Scale",
+	"peloton.api.v0.job.JobManager::Start",
+	"peloton.api.v0.job.JobManager::Stop",
+	"peloton.api.v0.job.JobManager::Update",
+	"peloton.api.v0.job.svc.JobService::CreateJob",
+	"peloton.api.v0.job.svc.JobService::DeleteJob",
+	"peloton.api.v0.job.svc.JobService::RefreshJob",
+	"peloton.api.v0.job.svc.JobService::RestartJob",
+	"peloton.api.v0.job.svc.JobService::StartJob",
+	"peloton.api.v0.job.svc.JobService::StopJob",
+	"peloton.api.v0.job.svc.JobService::UpdateJob",
+	"peloton.api.v0.task.TaskManager::DeletePodEvents",
+	"peloton.api.v0.task.TaskManager::Refresh",
+	"peloton.api.v0.task.TaskManager::Restart",
+	"peloton.api.v0.task.TaskManager::Start",
+	"peloton.api.v0.task.TaskManager::Stop",
+	"peloton.api.v0.task.svc.TaskService::RefreshTasks",
+	"peloton.api.v0.task.svc.TaskService::RestartTasks",
+	"peloton.api.v0.task.svc.TaskService::StartTasks",
+	"peloton.api.v0.task.svc.TaskService::StopTasks",
+	"peloton.api.v0.update.svc.UpdateService::AbortUpdate",
+	"peloton.api.v0.update.svc.UpdateService::CreateUpdate",
+	"peloton.api.v0.update.svc.UpdateService::PauseUpdate",
+	"peloton.api.v0.update.svc.UpdateService::ResumeUpdate",
+	"peloton.api.v0.update.svc.UpdateService::RollbackUpdate",
+	"peloton.api.v0.volume.svc.VolumeService::DeleteVolume",
+
+	// jobmgr v1alpha APIs
+	"peloton.api.v1alpha.job.stateful.svc.JobService::CreateJob",
+	"peloton.api.v1alpha.job.stateful.svc.JobService::DeleteJob",
+	"peloton.api.v1alpha.job.stateful.svc.JobService::ReplaceJob",
+	"peloton.api.v1alpha.job.stateless.svc.JobService::AbortJobWorkflow",
+	"peloton.api.v1alpha.job.stateless.svc.JobService::CreateJob",
+	"peloton.api.v1alpha.job.stateless.svc.JobService::DeleteJob",
+	"peloton.api.v1alpha.job.stateless.svc.JobService::PatchJob",
+	"peloton.api.v1alpha.job.stateless.svc.JobService::PauseJobWorkflow",
+	"peloton.api.v1alpha.job.stateless.svc.JobService::RefreshJob",
+	"peloton.api.v1alpha.job.stateless.svc.JobService::ReplaceJob",
+	"peloton.api.v1alpha.job.stateless.svc.JobService::RestartJob",
+	"peloton.api.v1alpha.job.stateless.svc.JobService::ResumeJobWorkflow",
+	"peloton.api.v1alpha.job.stateless.svc.JobService::StartJob",
+	"peloton.api.v1alpha.job.stateless.svc.JobService::StopJob",
+	"peloton.api.v1alpha.job.template.svc.TemplateService::CreateTemplate",
+	"peloton.api.v1alpha.job.template.svc.TemplateService::DeleteTemplate",
+	"peloton.api.v1alpha.job.template.svc.TemplateService::InstantiateTemplate",
+	"peloton.api.v1alpha.job.template.svc.TemplateService::UpdateTemplate",
+	"peloton.api.v1alpha.pod.svc.PodService::DeletePodEvents",
+	"peloton.api.v1alpha.pod.svc.PodService::RefreshPod",
+	"peloton.api.v1alpha.pod.svc.PodService::RestartPod",
+	"peloton.api.v1alpha.pod.svc.PodService::StartPod",
+	"peloton.api.v1alpha.pod.svc.PodService::StopPod",
+	"peloton.api.v1alpha.volume.svc.VolumeService::DeleteVolume",
+
+	// resmgr APIs
+	"peloton.api.v0.respool.ResourceManager::CreateResourcePool",
+	"peloton.api.v0.respool.ResourceManager::DeleteResourcePool",
+	"peloton.api.v0.respool.ResourceManager::UpdateResourcePool",
+	"peloton.api.v0.respool.ResourcePoolService::CreateResourcePool",
+	"peloton.api.v0.respool.ResourcePoolService::DeleteResourcePool",
+	"peloton.api.v0.respool.ResourcePoolService::UpdateResourcePool",
+	"peloton.api.v1alpha.respool.ResourcePoolService::CreateResourcePool",
+	"peloton.api.v1alpha.respool.ResourcePoolService::DeleteResourcePool",
+	"peloton.api.v1alpha.respool.ResourcePoolService::UpdateResourcePool",
+
+	// hostmgr APIs
+	"peloton.api.v0.host.svc.HostService::CompleteMaintenance",
+	"peloton.api.v0.host.svc.HostService::StartMaintenance",
+	"peloton.api.v1alpha.host.svc.HostService::CompleteMaintenance",
+	"peloton.api.v1alpha.host.svc.HostService::StartMaintenance",
+}
+
+// GetProcedures returns the full names of the procedures to audit.
+func (c *Config) GetProcedures() []string {
+	if len(c.Procedures) == 0 {
+		return DefaultMutatingProcedures
+	}
+	return c.Procedures
+}
+
+// GetQueueSize returns the maximum number of audit records waiting to
+// be written to the sink.
+func (c *Config) GetQueueSize() int {
+	if c.QueueSize <= 0 {
+		return _defaultQueueSize
+	}
+	return c.QueueSize
+}
+
+// GetWriteTimeout returns the timeout to write an audit record.
+func (c *Config) GetWriteTimeout() time.Duration {
+	if c.WriteTimeout <= 0 {
+		return _defaultWriteTimeout
+	}
+	return c.WriteTimeout
+}
+
+// Enabled returns whether the mutating API calls should be audited.
+func (c *Config) Enabled() bool {
+	return c.SinkType != NOOP && c.SinkType != UNDEFINED
+}
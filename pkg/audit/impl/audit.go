@@ -0,0 +1,51 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+import (
+	"github.com/uber/peloton/pkg/audit"
+	"github.com/uber/peloton/pkg/audit/impl/file"
+	"github.com/uber/peloton/pkg/audit/impl/noop"
+	"github.com/uber/peloton/pkg/audit/impl/storage"
+	"github.com/uber/peloton/pkg/storage/cassandra"
+	ormobjects "github.com/uber/peloton/pkg/storage/objects"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// CreateNewSink creates audit Sink based on type. The storage sink
+// writes to the cassandra store configured by cassandraConfig.
+func CreateNewSink(
+	config *audit.Config,
+	cassandraConfig *cassandra.Config,
+	scope tally.Scope,
+) (audit.Sink, error) {
+	switch config.SinkType {
+	case audit.NOOP, audit.UNDEFINED:
+		return noop.NewNoopSink(), nil
+	case audit.FILE:
+		return file.NewFileSink(config.Path)
+	case audit.STORAGE:
+		ormStore, err := ormobjects.NewCassandraStore(cassandraConfig, scope)
+		if err != nil {
+			return nil, err
+		}
+		return storage.NewStorageSink(ormStore), nil
+	default:
+		return nil,
+			yarpcerrors.InvalidArgumentErrorf("unknown audit sink type provided: %s", config.SinkType)
+	}
+}
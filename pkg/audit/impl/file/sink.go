@@ -0,0 +1,81 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sync"
+
+	"github.com/uber/peloton/.gen/peloton/private/auditsvc"
+
+	"github.com/uber/peloton/pkg/audit"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/pkg/errors"
+)
+
+// Sink appends the audit records to a local file, one JSON encoded
+// record per line.
+type Sink struct {
+	sync.Mutex
+
+	file    *os.File
+	encoder jsonpb.Marshaler
+}
+
+var _ audit.Sink = &Sink{}
+
+// Write appends an audit record to the file
+func (s *Sink) Write(ctx context.Context, record *auditsvc.AuditRecord) error {
+	var buf bytes.Buffer
+	if err := s.encoder.Marshal(&buf, record); err != nil {
+		return errors.Wrap(err, "failed to marshal audit record")
+	}
+	buf.WriteByte('\n')
+
+	s.Lock()
+	defer s.Unlock()
+	if _, err := s.file.Write(buf.Bytes()); err != nil {
+		return errors.Wrap(err, "failed to write audit record")
+	}
+	return nil
+}
+
+// Close closes the underlying file
+func (s *Sink) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	return s.file.Close()
+}
+
+// NewFileSink returns Sink which appends the audit records to the
+// file at path
+func NewFileSink(path string) (*Sink, error) {
+	if len(path) == 0 {
+		return nil, errors.New("audit log file path is not provided")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open audit log file")
+	}
+
+	return &Sink{
+		file:    f,
+		encoder: jsonpb.Marshaler{},
+	}, nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/uber/peloton/.gen/peloton/private/auditsvc"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	sink, err := NewFileSink(path)
+	assert.NoError(t, err)
+
+	records := []*auditsvc.AuditRecord{
+		{Principal: "alice", Target: "job1", Result: "ok"},
+		{Principal: "bob", Target: "job2", Result: "not-found"},
+	}
+	for _, record := range records {
+		assert.NoError(t, sink.Write(context.Background(), record))
+	}
+	assert.NoError(t, sink.Close())
+
+	f, err := os.Open(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	var result []*auditsvc.AuditRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		record := &auditsvc.AuditRecord{}
+		assert.NoError(t, jsonpb.Unmarshal(strings.NewReader(scanner.Text()), record))
+		result = append(result, record)
+	}
+	assert.Len(t, result, len(records))
+	for i := range records {
+		assert.Equal(t, records[i].GetPrincipal(), result[i].GetPrincipal())
+		assert.Equal(t, records[i].GetTarget(), result[i].GetTarget())
+		assert.Equal(t, records[i].GetResult(), result[i].GetResult())
+	}
+}
+
+func TestNewFileSinkInvalidPath(t *testing.T) {
+	_, err := NewFileSink("")
+	assert.Error(t, err)
+
+	_, err = NewFileSink("/nonexistent/dir/audit.log")
+	assert.Error(t, err)
+}
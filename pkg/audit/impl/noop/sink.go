@@ -0,0 +1,38 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package noop
+
+import (
+	"context"
+
+	"github.com/uber/peloton/.gen/peloton/private/auditsvc"
+
+	"github.com/uber/peloton/pkg/audit"
+)
+
+// Sink drops all the audit records
+type Sink struct{}
+
+var _ audit.Sink = &Sink{}
+
+// Write is noop
+func (s *Sink) Write(ctx context.Context, record *auditsvc.AuditRecord) error {
+	return nil
+}
+
+// NewNoopSink returns Sink
+func NewNoopSink() *Sink {
+	return &Sink{}
+}
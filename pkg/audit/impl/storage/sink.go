@@ -0,0 +1,43 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/uber/peloton/.gen/peloton/private/auditsvc"
+
+	"github.com/uber/peloton/pkg/audit"
+	ormobjects "github.com/uber/peloton/pkg/storage/objects"
+)
+
+// Sink persists the audit records in the audit_log table
+type Sink struct {
+	auditLogOps ormobjects.AuditLogOps
+}
+
+var _ audit.Sink = &Sink{}
+
+// Write adds an audit record to the audit_log table
+func (s *Sink) Write(ctx context.Context, record *auditsvc.AuditRecord) error {
+	return s.auditLogOps.Add(ctx, record)
+}
+
+// NewStorageSink returns Sink backed by the ORM store
+func NewStorageSink(ormStore *ormobjects.Store) *Sink {
+	return &Sink{
+		auditLogOps: ormobjects.NewAuditLogOps(ormStore),
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+
+	"github.com/uber/peloton/.gen/peloton/private/auditsvc"
+)
+
+// Type is the audit sink type used
+type Type string
+
+const (
+	// UNDEFINED is the undefined type which would behave the same as NOOP
+	UNDEFINED = Type("")
+	// NOOP would effectively disable audit feature
+	NOOP = Type("NOOP")
+	// FILE would append audit records to a local file
+	FILE = Type("FILE")
+	// STORAGE would persist audit records in the audit_log table
+	STORAGE = Type("STORAGE")
+)
+
+// Sink persists the audit records of the mutating API calls
+type Sink interface {
+	// Write persists an audit record
+	Write(ctx context.Context, record *auditsvc.AuditRecord) error
+}
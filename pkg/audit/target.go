@@ -0,0 +1,136 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"reflect"
+
+	"github.com/uber/peloton/pkg/common/stringset"
+	yarpcutil "github.com/uber/peloton/pkg/common/util/yarpc"
+
+	"go.uber.org/yarpc/api/transport"
+)
+
+const (
+	// _maxTargets is the maximum number of targets extracted from a
+	// request, so that a call on a large number of tasks does not
+	// result in as many audit records.
+	_maxTargets = 100
+)
+
+// _targetGetters is the list of getters, in order of preference, used
+// to extract the targeted resources from a request. The first getter
+// returning a non-empty value wins.
+var _targetGetters = []string{
+	"GetJobId",
+	"GetId",
+	"GetPodName",
+	"GetUpdateId",
+	"GetHostname",
+	"GetHostnames",
+	"GetTaskIds",
+	"GetTasks",
+	"GetPath",
+}
+
+// _defaultMutatingProcedures is the set of DefaultMutatingProcedures
+var _defaultMutatingProcedures = NewProcedureSet(DefaultMutatingProcedures)
+
+// valueGetter is implemented by the peloton and mesos identifiers,
+// such as JobID, PodName and TaskID.
+type valueGetter interface {
+	GetValue() string
+}
+
+// IsMutating returns whether a procedure is one of the mutating
+// procedures of Peloton listed in DefaultMutatingProcedures.
+func IsMutating(procedure string) bool {
+	return _defaultMutatingProcedures.Contains(procedure)
+}
+
+// NewProcedureSet returns the set of the given procedure names.
+func NewProcedureSet(procedures []string) stringset.StringSet {
+	set := stringset.New()
+	for _, procedure := range procedures {
+		set.Add(procedure)
+	}
+	return set
+}
+
+// RequestTargets decodes the request body of a procedure, and returns
+// the resources targeted by the request, such as the job ID or the
+// hostnames. It returns nil if the request cannot be decoded or does not
+// target any known resource.
+func RequestTargets(
+	encoding transport.Encoding,
+	procedure string,
+	body []byte,
+) []string {
+	msg := yarpcutil.DecodeRequest(encoding, procedure, body)
+	if msg == nil {
+		return nil
+	}
+
+	v := reflect.ValueOf(msg)
+	for _, getter := range _targetGetters {
+		m := v.MethodByName(getter)
+		if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+			continue
+		}
+		if targets := toTargets(m.Call(nil)[0]); len(targets) != 0 {
+			return targets
+		}
+	}
+	return nil
+}
+
+// toTargets converts the value returned by a target getter to targets.
+func toTargets(v reflect.Value) []string {
+	var targets []string
+	addTarget := func(target string) {
+		if len(target) == 0 || len(targets) >= _maxTargets {
+			return
+		}
+		for _, t := range targets {
+			if t == target {
+				return
+			}
+		}
+		targets = append(targets, target)
+	}
+
+	addValue := func(v reflect.Value) {
+		if v.Kind() == reflect.String {
+			addTarget(v.String())
+			return
+		}
+		if !v.CanInterface() {
+			return
+		}
+		// the generated getters are safe to call on nil messages
+		if getter, ok := v.Interface().(valueGetter); ok {
+			addTarget(getter.GetValue())
+		}
+	}
+
+	if v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			addValue(v.Index(i))
+		}
+	} else {
+		addValue(v)
+	}
+	return targets
+}
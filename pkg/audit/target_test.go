@@ -0,0 +1,136 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/respool"
+	hostsvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/host/svc"
+	statelesssvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless/svc"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+
+	yarpcutil "github.com/uber/peloton/pkg/common/util/yarpc"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsMutating(t *testing.T) {
+	assert.True(t, IsMutating(
+		"peloton.api.v1alpha.job.stateless.svc.JobService::StopJob"))
+	assert.True(t, IsMutating(
+		"peloton.api.v1alpha.job.stateless.svc.JobService::PatchJob"))
+	assert.True(t, IsMutating(
+		"peloton.api.v0.respool.ResourceManager::CreateResourcePool"))
+	assert.True(t, IsMutating(
+		"peloton.api.v0.job.JobManager::Scale"))
+	assert.False(t, IsMutating(
+		"peloton.api.v1alpha.job.stateless.svc.JobService::GetJob"))
+	// procedures are matched by their full name
+	assert.False(t, IsMutating(
+		"peloton.api.v1alpha.job.stateless.svc.JobService::StopJobs"))
+	// private procedures called by the peloton components are not audited
+	assert.False(t, IsMutating(
+		"peloton.private.resmgr.ResourceManagerService::SetPlacements"))
+	assert.False(t, IsMutating(
+		"peloton.private.hostmgr.hostsvc.InternalHostService::LaunchTasks"))
+	assert.False(t, IsMutating("Scheduler::Update"))
+	assert.False(t, IsMutating("invalid"))
+}
+
+func TestNewProcedureSet(t *testing.T) {
+	set := NewProcedureSet([]string{
+		"peloton.api.v0.job.JobManager::Create",
+	})
+	assert.True(t, set.Contains("peloton.api.v0.job.JobManager::Create"))
+	assert.False(t, set.Contains("peloton.api.v0.job.JobManager::Delete"))
+	assert.False(t, NewProcedureSet(nil).Contains(
+		"peloton.api.v0.job.JobManager::Create"))
+}
+
+func TestRequestTargetsProto(t *testing.T) {
+	jobID := "b64fd26b-0e39-41b7-b22a-205b69f247bd"
+	body, err := proto.Marshal(&statelesssvc.StopJobRequest{
+		JobId: &v1alphapeloton.JobID{Value: jobID},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{jobID}, RequestTargets(
+		yarpcutil.ProtoEncoding,
+		"peloton.api.v1alpha.job.stateless.svc.JobService::StopJob",
+		body,
+	))
+
+	// the request message name drops the trailing words of the method
+	body, err = proto.Marshal(&respool.UpdateRequest{
+		Id: &peloton.ResourcePoolID{Value: "respool1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"respool1"}, RequestTargets(
+		yarpcutil.ProtoEncoding,
+		"peloton.api.v0.respool.ResourceManager::UpdateResourcePool",
+		body,
+	))
+
+	// multiple targets are deduplicated
+	body, err = proto.Marshal(&hostsvc.StartMaintenanceRequest{
+		Hostnames: []string{"host1", "host2", "host1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"host1", "host2"}, RequestTargets(
+		yarpcutil.ProtoEncoding,
+		"peloton.api.v1alpha.host.svc.HostService::StartMaintenance",
+		body,
+	))
+}
+
+func TestRequestTargetsJSON(t *testing.T) {
+	jobID := "b64fd26b-0e39-41b7-b22a-205b69f247bd"
+	marshaler := jsonpb.Marshaler{}
+	body, err := marshaler.MarshalToString(&statelesssvc.StopJobRequest{
+		JobId: &v1alphapeloton.JobID{Value: jobID},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{jobID}, RequestTargets(
+		yarpcutil.JSONEncoding,
+		"peloton.api.v1alpha.job.stateless.svc.JobService::StopJob",
+		[]byte(body),
+	))
+}
+
+func TestRequestTargetsUnknown(t *testing.T) {
+	// unknown procedure
+	assert.Nil(t, RequestTargets(
+		yarpcutil.ProtoEncoding,
+		"peloton.api.v1alpha.job.stateless.svc.JobService::UnknownMethod",
+		nil,
+	))
+
+	// invalid body
+	assert.Nil(t, RequestTargets(
+		yarpcutil.ProtoEncoding,
+		"peloton.api.v1alpha.job.stateless.svc.JobService::StopJob",
+		[]byte("invalid"),
+	))
+
+	// unknown encoding
+	assert.Nil(t, RequestTargets(
+		"thrift",
+		"peloton.api.v1alpha.job.stateless.svc.JobService::StopJob",
+		nil,
+	))
+}
@@ -25,7 +25,10 @@ import (
 	"github.com/uber/peloton/pkg/aurorabridge/label"
 )
 
-// NewJobSpecFromJobUpdateRequest creates a new JobSpec.
+// NewJobSpecFromJobUpdateRequest creates a new JobSpec. Aurora does not
+// send per-instance task configs, the instances keep their own configs when
+// they are not covered by UpdateOnlyTheseInstances, and are pinned to them
+// by the handler.
 func NewJobSpecFromJobUpdateRequest(
 	r *api.JobUpdateRequest,
 	respoolID *peloton.ResourcePoolID,
@@ -61,7 +64,7 @@ func NewJobSpecFromJobUpdateRequest(
 		InstanceCount: uint32(r.GetInstanceCount()),
 		Sla:           newSLASpec(r.GetTaskConfig(), r.GetSettings().GetMaxFailedInstances()),
 		DefaultSpec:   p,
-		InstanceSpec:  nil, // TODO(codyg): Pinned instance support.
+		InstanceSpec:  nil, // Pinned instances are set by the handler.
 		RespoolId:     respoolID,
 	}, nil
 }
@@ -959,12 +959,19 @@ func (h *ServiceHandler) getJobUpdateDiff(
 		return nil, auroraErrorf("new job spec: %s", err)
 	}
 
+	// Diff against the same job spec StartJobUpdate would replace the job
+	// with, so that pinned instances are reported as unchanged.
+	updateJobSpec, err := h.createJobSpecForUpdate(ctx, request, jobID, jobSpec)
+	if err != nil {
+		return nil, auroraErrorf("create job spec for update: %s", err)
+	}
+
 	resp, err := h.jobClient.GetReplaceJobDiff(
 		ctx,
 		&statelesssvc.GetReplaceJobDiffRequest{
 			JobId:   jobID,
 			Version: jobSummary.GetStatus().GetVersion(),
-			Spec:    jobSpec,
+			Spec:    updateJobSpec,
 		})
 	if err != nil {
 		return nil, auroraErrorf("get replace job diff: %s", err)
@@ -586,6 +586,7 @@ func (suite *ServiceHandlerTestSuite) TestGetJobUpdateDiff() {
 
 	suite.expectGetJobIDFromJobName(jobKey, jobID)
 	suite.expectGetJobVersion(jobID, entityVersion)
+	suite.expectListPods(jobID, []*pod.PodSummary{})
 	suite.jobClient.EXPECT().
 		GetReplaceJobDiff(
 			gomock.Any(),
@@ -628,6 +629,7 @@ func (suite *ServiceHandlerTestSuite) TestGetJobUpdateDiffFailure() {
 
 	suite.expectGetJobIDFromJobName(jobKey, jobID)
 	suite.expectGetJobVersion(jobID, entityVersion)
+	suite.expectListPods(jobID, []*pod.PodSummary{})
 
 	suite.jobClient.EXPECT().
 		GetReplaceJobDiff(
@@ -646,6 +648,89 @@ func (suite *ServiceHandlerTestSuite) TestGetJobUpdateDiffFailure() {
 	suite.Equal(api.ResponseCodeError, resp.GetResponseCode())
 }
 
+// Tests get job update diff keeps the instances which are not covered by
+// UpdateOnlyTheseInstances on their current pod spec
+func (suite *ServiceHandlerTestSuite) TestGetJobUpdateDiff_WithPinned() {
+	defer goleak.VerifyNoLeaks(suite.T())
+
+	respoolID := fixture.PelotonResourcePoolID()
+	jobUpdateRequest := fixture.AuroraJobUpdateRequest()
+	jobUpdateRequest.InstanceCount = ptr.Int32(2)
+	jobUpdateRequest.Settings = &api.JobUpdateSettings{
+		UpdateOnlyTheseInstances: []*api.Range{
+			{First: ptr.Int32(0), Last: ptr.Int32(0)},
+		},
+	}
+	jobID := fixture.PelotonJobID()
+	jobKey := jobUpdateRequest.GetTaskConfig().GetJob()
+	entityVersion := fixture.PelotonEntityVersion()
+	podVersion := &peloton.EntityVersion{Value: "1-0-0"}
+	podSpec := &pod.PodSpec{
+		Labels: []*peloton.Label{
+			{Key: "k1", Value: "v1"},
+		},
+	}
+
+	jobSpec, _ := atop.NewJobSpecFromJobUpdateRequest(
+		jobUpdateRequest,
+		respoolID,
+		suite.config.ThermosExecutor,
+	)
+	jobSpec.InstanceSpec = map[uint32]*pod.PodSpec{
+		1: podSpec,
+	}
+
+	suite.respoolLoader.EXPECT().Load(gomock.Any()).Return(respoolID, nil)
+
+	suite.expectGetJobIDFromJobName(jobKey, jobID)
+	suite.expectGetJobVersion(jobID, entityVersion)
+	suite.expectListPods(jobID, []*pod.PodSummary{
+		{
+			PodName: &peloton.PodName{
+				Value: util.CreatePelotonTaskID(jobID.GetValue(), 1),
+			},
+			Status: &pod.PodStatus{
+				State:   pod.PodState_POD_STATE_RUNNING,
+				Version: podVersion,
+			},
+		},
+	})
+	suite.jobClient.EXPECT().
+		GetJob(gomock.Any(), &statelesssvc.GetJobRequest{
+			JobId:   jobID,
+			Version: podVersion,
+		}).
+		Return(&statelesssvc.GetJobResponse{
+			JobInfo: &stateless.JobInfo{
+				Spec: &stateless.JobSpec{
+					DefaultSpec: podSpec,
+				},
+			},
+		}, nil)
+	suite.jobClient.EXPECT().
+		GetReplaceJobDiff(
+			gomock.Any(),
+			&statelesssvc.GetReplaceJobDiffRequest{
+				JobId:   jobID,
+				Version: entityVersion,
+				Spec:    jobSpec,
+			}).Return(&statelesssvc.GetReplaceJobDiffResponse{
+		InstancesAdded:     []*pod.InstanceIDRange{{From: 0, To: 0}},
+		InstancesUnchanged: []*pod.InstanceIDRange{{From: 1, To: 1}},
+	}, nil)
+
+	resp, err := suite.handler.GetJobUpdateDiff(
+		suite.ctx,
+		jobUpdateRequest,
+	)
+	suite.NoError(err)
+	suite.Equal(api.ResponseCodeOk, resp.GetResponseCode())
+	result := resp.GetResult().GetGetJobUpdateDiffResult()
+	suite.Equal(int32(0), result.GetAdd()[0].GetInstances()[0].GetFirst())
+	suite.Equal(int32(1), result.GetUnchanged()[0].GetInstances()[0].GetFirst())
+	suite.Nil(result.GetUpdate())
+}
+
 func (suite *ServiceHandlerTestSuite) TestGetJobUpdateDiff_JobNotFound() {
 	defer goleak.VerifyNoLeaks(suite.T())
 
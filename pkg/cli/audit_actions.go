@@ -0,0 +1,81 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/uber/peloton/.gen/peloton/private/auditsvc"
+)
+
+const (
+	auditListFormatHeader = "Time\tPrincipal\tCaller\tProcedure\tResult\tLatency(ms)\t\n"
+	auditListFormatBody   = "%s\t%s\t%s\t%s\t%s\t%d\t\n"
+)
+
+// AuditListAction is the action for listing the audit records of a
+// resource, such as a job ID, a pod name or a hostname, received
+// between startTime and endTime
+func (c *Client) AuditListAction(
+	target string,
+	limit uint32,
+	startTime string,
+	endTime string,
+) error {
+	resp, err := c.auditClient.ListAuditRecords(
+		c.ctx,
+		&auditsvc.ListAuditRecordsRequest{
+			Target:    target,
+			Limit:     limit,
+			StartTime: startTime,
+			EndTime:   endTime,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printAuditListResponse(resp, c.Debug)
+	return nil
+}
+
+func printAuditListResponse(
+	r *auditsvc.ListAuditRecordsResponse,
+	debug bool,
+) {
+	if debug {
+		printResponseJSON(r)
+		return
+	}
+	if len(r.GetRecords()) == 0 {
+		fmt.Fprintf(tabWriter, "No audit record was found\n")
+		tabWriter.Flush()
+		return
+	}
+	fmt.Fprintf(tabWriter, auditListFormatHeader)
+	for _, record := range r.GetRecords() {
+		fmt.Fprintf(
+			tabWriter,
+			auditListFormatBody,
+			record.GetTime(),
+			record.GetPrincipal(),
+			record.GetCaller(),
+			record.GetProcedure(),
+			record.GetResult(),
+			record.GetLatencyMs(),
+		)
+	}
+	tabWriter.Flush()
+}
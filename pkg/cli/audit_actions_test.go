@@ -0,0 +1,93 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/uber/peloton/.gen/peloton/private/auditsvc"
+	auditmocks "github.com/uber/peloton/.gen/peloton/private/auditsvc/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+type auditActionsTestSuite struct {
+	suite.Suite
+	ctx         context.Context
+	ctrl        *gomock.Controller
+	auditClient *auditmocks.MockAuditServiceYARPCClient
+	client      Client
+}
+
+func TestAuditActions(t *testing.T) {
+	suite.Run(t, new(auditActionsTestSuite))
+}
+
+func (suite *auditActionsTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.auditClient = auditmocks.NewMockAuditServiceYARPCClient(suite.ctrl)
+	suite.client = Client{
+		Debug:       false,
+		auditClient: suite.auditClient,
+		dispatcher:  nil,
+		ctx:         suite.ctx,
+	}
+}
+
+func (suite *auditActionsTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+// TestAuditListAction tests listing the audit records of a job
+func (suite *auditActionsTestSuite) TestAuditListAction() {
+	jobID := "481d565e-28da-457d-8434-f6bb7faa0e95"
+	suite.auditClient.EXPECT().
+		ListAuditRecords(gomock.Any(), &auditsvc.ListAuditRecordsRequest{
+			Target:    jobID,
+			Limit:     10,
+			StartTime: "2019-05-10T11:00:00Z",
+			EndTime:   "2019-05-10T12:00:00Z",
+		}).
+		Return(&auditsvc.ListAuditRecordsResponse{
+			Records: []*auditsvc.AuditRecord{
+				{
+					Principal: "alice",
+					Procedure: "peloton.api.v1alpha.job.stateless.svc.JobService::StopJob",
+					Target:    jobID,
+					Result:    "ok",
+				},
+			},
+		}, nil)
+	suite.NoError(suite.client.AuditListAction(
+		jobID, 10, "2019-05-10T11:00:00Z", "2019-05-10T12:00:00Z"))
+
+	// no audit record
+	suite.auditClient.EXPECT().
+		ListAuditRecords(gomock.Any(), gomock.Any()).
+		Return(&auditsvc.ListAuditRecordsResponse{}, nil)
+	suite.NoError(suite.client.AuditListAction(jobID, 0, "", ""))
+}
+
+// TestAuditListActionFailure tests the failure to list the audit records
+func (suite *auditActionsTestSuite) TestAuditListActionFailure() {
+	suite.auditClient.EXPECT().
+		ListAuditRecords(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("test error"))
+	suite.Error(suite.client.AuditListAction("job", 0, "", ""))
+}
@@ -17,6 +17,7 @@ package cli
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"time"
 
 	"go.uber.org/yarpc"
@@ -30,10 +31,13 @@ import (
 	updatesvc "github.com/uber/peloton/.gen/peloton/api/v0/update/svc"
 	volume_svc "github.com/uber/peloton/.gen/peloton/api/v0/volume/svc"
 	statelesssvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless/svc"
+	templatesvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template/svc"
 	podsvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/pod/svc"
 	watchsvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/watch/svc"
+	"github.com/uber/peloton/.gen/peloton/private/auditsvc"
 	hostmgr_svc "github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 	"github.com/uber/peloton/.gen/peloton/private/jobmgrsvc"
+	"github.com/uber/peloton/.gen/peloton/private/placementsvc"
 	"github.com/uber/peloton/.gen/peloton/private/resmgrsvc"
 
 	"github.com/uber/peloton/pkg/cli/middleware"
@@ -47,6 +51,7 @@ type Client struct {
 	taskClient      task.TaskManagerYARPCClient
 	podClient       podsvc.PodServiceYARPCClient
 	statelessClient statelesssvc.JobServiceYARPCClient
+	templateClient  templatesvc.TemplateServiceYARPCClient
 	watchClient     watchsvc.WatchServiceYARPCClient
 	resClient       respool.ResourceManagerYARPCClient
 	resMgrClient    resmgrsvc.ResourceManagerServiceYARPCClient
@@ -55,6 +60,8 @@ type Client struct {
 	hostMgrClient   hostmgr_svc.InternalHostServiceYARPCClient
 	hostClient      hostsvc.HostServiceYARPCClient
 	jobmgrClient    jobmgrsvc.JobManagerServiceYARPCClient
+	auditClient     auditsvc.AuditServiceYARPCClient
+	placementClient placementsvc.PlacementServiceYARPCClient
 	dispatcher      *yarpc.Dispatcher
 	ctx             context.Context
 	cancelFunc      context.CancelFunc
@@ -62,9 +69,12 @@ type Client struct {
 	Debug bool
 }
 
-// New returns a new RPC client given a framework URL and timeout and error
+// New returns a new RPC client given a framework URL and timeout and error.
+// The placement engine client is only created if placementURL is set, since
+// placement engines are not found by service discovery.
 func New(
 	discovery leader.Discovery,
+	placementURL *url.URL,
 	timeout time.Duration,
 	authConfig *middleware.BasicAuthConfig,
 	debug bool) (*Client, error) {
@@ -88,20 +98,27 @@ func New(
 
 	authMiddleware := middleware.NewBasicAuthOutboundMiddleware(authConfig)
 
-	dispatcher := yarpc.NewDispatcher(yarpc.Config{
-		Name: common.PelotonCLI,
-		Outbounds: yarpc.Outbounds{
-			common.PelotonJobManager: transport.Outbounds{
-				Unary:  t.NewSingleOutbound(jobmgrURL.Host),
-				Stream: t.NewSingleOutbound(jobmgrURL.Host),
-			},
-			common.PelotonResourceManager: transport.Outbounds{
-				Unary: t.NewSingleOutbound(resmgrURL.Host),
-			},
-			common.PelotonHostManager: transport.Outbounds{
-				Unary: t.NewSingleOutbound(hostmgrURL.Host),
-			},
+	outbounds := yarpc.Outbounds{
+		common.PelotonJobManager: transport.Outbounds{
+			Unary:  t.NewSingleOutbound(jobmgrURL.Host),
+			Stream: t.NewSingleOutbound(jobmgrURL.Host),
+		},
+		common.PelotonResourceManager: transport.Outbounds{
+			Unary: t.NewSingleOutbound(resmgrURL.Host),
+		},
+		common.PelotonHostManager: transport.Outbounds{
+			Unary: t.NewSingleOutbound(hostmgrURL.Host),
 		},
+	}
+	if placementURL != nil {
+		outbounds[common.PelotonPlacement] = transport.Outbounds{
+			Unary: t.NewSingleOutbound(placementURL.Host),
+		}
+	}
+
+	dispatcher := yarpc.NewDispatcher(yarpc.Config{
+		Name:      common.PelotonCLI,
+		Outbounds: outbounds,
 		OutboundMiddleware: yarpc.OutboundMiddleware{
 			Unary:  authMiddleware,
 			Oneway: authMiddleware,
@@ -145,16 +162,27 @@ func New(
 		statelessClient: statelesssvc.NewJobServiceYARPCClient(
 			dispatcher.ClientConfig(common.PelotonJobManager),
 		),
+		templateClient: templatesvc.NewTemplateServiceYARPCClient(
+			dispatcher.ClientConfig(common.PelotonJobManager),
+		),
 		watchClient: watchsvc.NewWatchServiceYARPCClient(
 			dispatcher.ClientConfig(common.PelotonJobManager),
 		),
 		jobmgrClient: jobmgrsvc.NewJobManagerServiceYARPCClient(
 			dispatcher.ClientConfig(common.PelotonJobManager),
 		),
+		auditClient: auditsvc.NewAuditServiceYARPCClient(
+			dispatcher.ClientConfig(common.PelotonJobManager),
+		),
 		dispatcher: dispatcher,
 		ctx:        ctx,
 		cancelFunc: cancelFunc,
 	}
+	if placementURL != nil {
+		client.placementClient = placementsvc.NewPlacementServiceYARPCClient(
+			dispatcher.ClientConfig(common.PelotonPlacement),
+		)
+	}
 	return &client, nil
 }
 
@@ -27,6 +27,7 @@ import (
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
 	"github.com/uber/peloton/.gen/peloton/api/v0/query"
 	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/private/placementsvc"
 
 	"github.com/uber/peloton/pkg/common/stringset"
 	"github.com/uber/peloton/pkg/common/util"
@@ -55,11 +56,15 @@ const (
 
 	jobStopConfirmationMessage = "The above jobs will be stopped. " +
 		"Are you sure you want to continue?"
+
+	jobDisruptionsFormatHeader = "Cause\tCount\tInstance Hours\tAvailable Instance Hours(%)\t\n"
+	jobDisruptionsFormatBody   = "%s\t%d\t%.2f\t%.2f\t\n"
+	terminationReasonPrefix    = "TERMINATION_STATUS_REASON_"
 )
 
 // JobCreateAction is the action for creating a job
 func (c *Client) JobCreateAction(
-	jobID, respoolPath, cfg, secretPath string, secret []byte,
+	jobID, respoolPath, cfg, secretPath string, secret []byte, dryRun bool,
 ) error {
 	respoolID, err := c.LookupResourcePoolID(respoolPath)
 	if err != nil {
@@ -83,6 +88,10 @@ func (c *Client) JobCreateAction(
 	// set the resource pool ID
 	jobConfig.RespoolID = respoolID
 
+	if dryRun {
+		return c.jobCreateDryRun(&jobConfig)
+	}
+
 	var request = &job.CreateRequest{
 		Id: &peloton.JobID{
 			Value: jobID,
@@ -103,6 +112,23 @@ func (c *Client) JobCreateAction(
 	return nil
 }
 
+// jobCreateDryRun asks a placement engine where the instances of the job
+// would be placed, without creating the job.
+func (c *Client) jobCreateDryRun(jobConfig *job.JobConfig) error {
+	if c.placementClient == nil {
+		return errors.New("placement engine address is not set")
+	}
+
+	response, err := c.placementClient.DryRunPlacement(
+		c.ctx,
+		&placementsvc.DryRunPlacementRequest{Config: jobConfig})
+	if err != nil {
+		return err
+	}
+	printDryRunPlacementResponse(response, c.Debug)
+	return nil
+}
+
 // JobDeleteAction is the action for deleting a job
 func (c *Client) JobDeleteAction(jobID string) error {
 	var request = &job.DeleteRequest{
@@ -163,6 +189,55 @@ func (c *Client) JobGetActiveJobsAction() error {
 	return nil
 }
 
+// JobDisruptionsAction is the action for reporting the instance hours
+// a job lost to disruptions by Peloton, by cause
+func (c *Client) JobDisruptionsAction(jobID string, days uint32) error {
+	r, err := c.jobClient.GetDisruptions(c.ctx, &job.GetDisruptionsRequest{
+		Id:   &peloton.JobID{Value: jobID},
+		Days: days,
+	})
+	if err != nil {
+		return err
+	}
+
+	printJobDisruptionsResponse(r, c.Debug)
+	return nil
+}
+
+func printJobDisruptionsResponse(
+	r *job.GetDisruptionsResponse,
+	debug bool,
+) {
+	if debug {
+		printResponseJSON(r)
+		return
+	}
+	if len(r.GetDisruptions()) == 0 {
+		fmt.Fprintf(tabWriter, "No disruption in the last %d days\n", r.GetDays())
+		tabWriter.Flush()
+		return
+	}
+
+	availableHours := float64(r.GetInstanceCount()) * float64(r.GetDays()) * 24
+	fmt.Fprintf(tabWriter, jobDisruptionsFormatHeader)
+	for _, d := range r.GetDisruptions() {
+		hours := d.GetDurationSeconds() / time.Hour.Seconds()
+		var percent float64
+		if availableHours > 0 {
+			percent = hours / availableHours * 100
+		}
+		fmt.Fprintf(
+			tabWriter,
+			jobDisruptionsFormatBody,
+			strings.TrimPrefix(d.GetCause().String(), terminationReasonPrefix),
+			d.GetCount(),
+			hours,
+			percent,
+		)
+	}
+	tabWriter.Flush()
+}
+
 // JobRefreshAction calls the refresh API for a job
 func (c *Client) JobRefreshAction(jobID string) error {
 	var request = &job.RefreshRequest{
@@ -327,6 +402,36 @@ func (c *Client) JobUpdateAction(
 	return nil
 }
 
+// JobScaleAction is the action for changing the number of instances
+// of a running batch job
+func (c *Client) JobScaleAction(jobID string, instanceCount uint32) error {
+	response, err := c.jobClient.Scale(c.ctx, &job.ScaleRequest{
+		Id:            &peloton.JobID{Value: jobID},
+		InstanceCount: instanceCount,
+	})
+	if err != nil {
+		return err
+	}
+
+	printJobScaleResponse(response, c.Debug)
+	return nil
+}
+
+func printJobScaleResponse(r *job.ScaleResponse, jsonFormat bool) {
+	if jsonFormat {
+		printResponseJSON(r)
+		return
+	}
+	fmt.Fprintf(tabWriter, "Job %s scaled\n", r.GetId().GetValue())
+	if len(r.GetInstancesAdded()) > 0 {
+		fmt.Fprintf(tabWriter, "Instances added: %v\n", r.GetInstancesAdded())
+	}
+	if len(r.GetInstancesRemoved()) > 0 {
+		fmt.Fprintf(tabWriter, "Instances removed: %v\n", r.GetInstancesRemoved())
+	}
+	tabWriter.Flush()
+}
+
 // JobStopAction is the action of stopping job(s) by jobID,
 // owner and labels
 func (c *Client) JobStopAction(
@@ -768,6 +873,36 @@ func printJobCreateResponse(r *job.CreateResponse, jsonFormat bool) {
 	}
 }
 
+func printDryRunPlacementResponse(
+	r *placementsvc.DryRunPlacementResponse,
+	jsonFormat bool) {
+	if jsonFormat {
+		printResponseJSON(r)
+		return
+	}
+
+	fmt.Fprintf(tabWriter, "Placement strategy: %s\n", r.GetStrategy())
+	if r.GetAdmission().GetAdmitted() {
+		fmt.Fprint(tabWriter, "Resource pool admission: admitted\n")
+	} else {
+		fmt.Fprintf(tabWriter, "Resource pool admission: not admitted: %s\n",
+			r.GetAdmission().GetReason())
+	}
+
+	var placed int
+	fmt.Fprint(tabWriter, "Instance\tHost\tReason\t\n")
+	for _, p := range r.GetPlacements() {
+		if p.GetHostname() != "" {
+			placed++
+		}
+		fmt.Fprintf(tabWriter, "%d\t%s\t%s\t\n",
+			p.GetInstanceId(), p.GetHostname(), p.GetReason())
+	}
+	fmt.Fprintf(tabWriter, "%d of %d instances placed\n",
+		placed, len(r.GetPlacements()))
+	tabWriter.Flush()
+}
+
 func printJobGetResponse(r *job.GetResponse, jsonFormat bool) {
 	if r.GetJobInfo() == nil {
 		fmt.Fprint(tabWriter, "Unable to get job \n")
@@ -31,6 +31,8 @@ import (
 	respoolmocks "github.com/uber/peloton/.gen/peloton/api/v0/respool/mocks"
 	"github.com/uber/peloton/.gen/peloton/api/v0/task"
 	taskmocks "github.com/uber/peloton/.gen/peloton/api/v0/task/mocks"
+	"github.com/uber/peloton/.gen/peloton/private/placementsvc"
+	placementmocks "github.com/uber/peloton/.gen/peloton/private/placementsvc/mocks"
 
 	jobmgrtask "github.com/uber/peloton/pkg/jobmgr/task"
 
@@ -50,12 +52,13 @@ const (
 
 type jobActionsTestSuite struct {
 	suite.Suite
-	mockCtrl    *gomock.Controller
-	mockJob     *jobmocks.MockJobManagerYARPCClient
-	mockTask    *taskmocks.MockTaskManagerYARPCClient
-	mockRespool *respoolmocks.MockResourceManagerYARPCClient
-	ctx         context.Context
-	client      Client
+	mockCtrl      *gomock.Controller
+	mockJob       *jobmocks.MockJobManagerYARPCClient
+	mockTask      *taskmocks.MockTaskManagerYARPCClient
+	mockRespool   *respoolmocks.MockResourceManagerYARPCClient
+	mockPlacement *placementmocks.MockPlacementServiceYARPCClient
+	ctx           context.Context
+	client        Client
 }
 
 func (suite *jobActionsTestSuite) SetupTest() {
@@ -64,14 +67,17 @@ func (suite *jobActionsTestSuite) SetupTest() {
 	suite.mockTask = taskmocks.NewMockTaskManagerYARPCClient(suite.mockCtrl)
 	suite.mockRespool = respoolmocks.NewMockResourceManagerYARPCClient(
 		suite.mockCtrl)
+	suite.mockPlacement = placementmocks.NewMockPlacementServiceYARPCClient(
+		suite.mockCtrl)
 	suite.ctx = context.Background()
 	suite.client = Client{
-		Debug:      false,
-		resClient:  suite.mockRespool,
-		taskClient: suite.mockTask,
-		jobClient:  suite.mockJob,
-		dispatcher: nil,
-		ctx:        suite.ctx,
+		Debug:           false,
+		resClient:       suite.mockRespool,
+		taskClient:      suite.mockTask,
+		jobClient:       suite.mockJob,
+		placementClient: suite.mockPlacement,
+		dispatcher:      nil,
+		ctx:             suite.ctx,
 	}
 }
 
@@ -448,7 +454,7 @@ func (suite *jobActionsTestSuite) TestClientJobCreateAction() {
 			)
 		}
 
-		err := suite.client.JobCreateAction(t.jobID, path, testJobConfig, t.secretPath, t.secret)
+		err := suite.client.JobCreateAction(t.jobID, path, testJobConfig, t.secretPath, t.secret, false)
 		if t.createError != nil {
 			suite.EqualError(err, t.createError.Error())
 		} else if t.respoolError != nil {
@@ -461,6 +467,52 @@ func (suite *jobActionsTestSuite) TestClientJobCreateAction() {
 	}
 }
 
+// TestClientJobCreateActionDryRun tests a dry run of creating a job
+func (suite *jobActionsTestSuite) TestClientJobCreateActionDryRun() {
+	path := "/a/b/c/d"
+	respoolID := &peloton.ResourcePoolID{Value: uuid.New()}
+	config := suite.getConfig()
+	config.RespoolID = respoolID
+
+	for _, debug := range []bool{true, false} {
+		suite.client.Debug = debug
+		suite.withMockResourcePoolLookup(
+			&respool.LookupRequest{
+				Path: &respool.ResourcePoolPath{Value: path},
+			},
+			&respool.LookupResponse{Id: respoolID},
+			nil,
+		)
+		suite.mockPlacement.EXPECT().
+			DryRunPlacement(
+				suite.ctx,
+				&placementsvc.DryRunPlacementRequest{Config: config}).
+			Return(&placementsvc.DryRunPlacementResponse{
+				Placements: []*placementsvc.InstancePlacement{
+					{InstanceId: 0, Hostname: "host0"},
+					{InstanceId: 1, Reason: "no offers from the cluster"},
+				},
+				Admission: &placementsvc.AdmissionResult{Admitted: true},
+				Strategy:  "batch",
+			}, nil)
+
+		suite.NoError(suite.client.JobCreateAction(
+			"", path, testJobConfig, "", nil, true))
+	}
+
+	// no placement engine address
+	suite.client.placementClient = nil
+	suite.withMockResourcePoolLookup(
+		&respool.LookupRequest{
+			Path: &respool.ResourcePoolPath{Value: path},
+		},
+		&respool.LookupResponse{Id: respoolID},
+		nil,
+	)
+	suite.Error(suite.client.JobCreateAction(
+		"", path, testJobConfig, "", nil, true))
+}
+
 // TestClientJobUpdateAction tests updating a job
 func (suite *jobActionsTestSuite) TestClientJobUpdateAction() {
 	id := uuid.New()
@@ -1039,6 +1091,61 @@ func (suite *jobActionsTestSuite) TestClientJobGetActiveJobsAction() {
 
 }
 
+// TestClientJobDisruptionsAction tests reporting the disruptions of a job
+func (suite *jobActionsTestSuite) TestClientJobDisruptionsAction() {
+	req := &job.GetDisruptionsRequest{
+		Id:   &peloton.JobID{Value: testJobID},
+		Days: 7,
+	}
+	resp := &job.GetDisruptionsResponse{
+		Disruptions: []*job.DisruptionSummary{
+			{
+				Cause:           task.TerminationStatus_TERMINATION_STATUS_REASON_KILLED_HOST_MAINTENANCE,
+				Count:           10,
+				DurationSeconds: 14 * 3600,
+			},
+		},
+		InstanceCount: 10,
+		Days:          7,
+	}
+
+	suite.mockJob.EXPECT().
+		GetDisruptions(gomock.Any(), req).
+		Return(resp, nil)
+	suite.NoError(suite.client.JobDisruptionsAction(testJobID, 7))
+
+	suite.mockJob.EXPECT().
+		GetDisruptions(gomock.Any(), req).
+		Return(&job.GetDisruptionsResponse{Days: 7}, nil)
+	suite.NoError(suite.client.JobDisruptionsAction(testJobID, 7))
+
+	suite.mockJob.EXPECT().
+		GetDisruptions(gomock.Any(), req).
+		Return(nil, errors.New("unable to get disruptions"))
+	suite.Error(suite.client.JobDisruptionsAction(testJobID, 7))
+}
+
+// TestClientJobScaleAction tests scaling a batch job
+func (suite *jobActionsTestSuite) TestClientJobScaleAction() {
+	req := &job.ScaleRequest{
+		Id:            &peloton.JobID{Value: testJobID},
+		InstanceCount: 2,
+	}
+
+	suite.mockJob.EXPECT().
+		Scale(gomock.Any(), req).
+		Return(&job.ScaleResponse{
+			Id:               req.GetId(),
+			InstancesRemoved: []uint32{2, 3},
+		}, nil)
+	suite.NoError(suite.client.JobScaleAction(testJobID, 2))
+
+	suite.mockJob.EXPECT().
+		Scale(gomock.Any(), req).
+		Return(nil, errors.New("unable to scale job"))
+	suite.Error(suite.client.JobScaleAction(testJobID, 2))
+}
+
 // TestClientJobRefreshAction tests refreshing a job
 func (suite *jobActionsTestSuite) TestClientJobRefreshAction() {
 	resp := &job.RefreshResponse{}
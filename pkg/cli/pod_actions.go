@@ -15,10 +15,12 @@
 package cli
 
 import (
+	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
-	"strings"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
 
 	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
 	podsvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/pod/svc"
@@ -91,54 +93,76 @@ func (c *Client) PodRefreshAction(podName string) error {
 	return nil
 }
 
-// PodLogsGetAction is the action to get logs files for given pod
-func (c *Client) PodLogsGetAction(filename string, podName string, podID string) error {
-	request := &podsvc.BrowsePodSandboxRequest{
+// PodLogsGetAction is the action to stream the content of a sandbox
+// file, such as stdout or stderr, of a given pod
+func (c *Client) PodLogsGetAction(
+	filename string,
+	podName string,
+	podID string,
+	follow bool,
+	offset int64,
+	limitBytes int64,
+) error {
+	request := &podsvc.GetPodLogsRequest{
 		PodName: &v1alphapeloton.PodName{
 			Value: podName,
 		},
 		PodId: &v1alphapeloton.PodID{
 			Value: podID,
 		},
-	}
-	response, err := c.podClient.BrowsePodSandbox(c.ctx, request)
-	if err != nil {
-		return err
+		Filename:   filename,
+		Offset:     offset,
+		LimitBytes: limitBytes,
+		Follow:     follow,
 	}
 
-	var filePath string
-	for _, path := range response.GetPaths() {
-		if strings.HasSuffix(path, filename) {
-			filePath = path
-		}
-	}
-
-	if len(filePath) == 0 {
-		return fmt.Errorf(
-			"filename:%s not found in sandbox files: %s",
-			filename,
-			response.GetPaths())
+	ctx := c.ctx
+	if follow {
+		// a followed file is streamed until the user interrupts it,
+		// so the stream is not bounded by the timeout of the client
+		var cancel context.CancelFunc
+		ctx, cancel = newInterruptibleContext()
+		defer cancel()
 	}
 
-	logFileDownloadURL := fmt.Sprintf(
-		"http://%s:%s/files/download?path=%s",
-		response.GetHostname(),
-		response.GetPort(),
-		filePath)
-
-	resp, err := http.Get(logFileDownloadURL)
+	stream, err := c.podClient.GetPodLogs(ctx, request)
 	if err != nil {
 		return err
 	}
 
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if follow && ctx.Err() == context.Canceled {
+				return nil
+			}
+			return err
+		}
+
+		if _, err := os.Stdout.Write(resp.GetData()); err != nil {
+			return err
+		}
 	}
-	fmt.Printf("\n\n%s", body)
+}
 
-	return nil
+// newInterruptibleContext returns a context without deadline which is
+// cancelled when the CLI is interrupted or terminated
+func newInterruptibleContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
 }
 
 func printPodGetEventsV1AlphaResponse(r *podsvc.GetPodEventsResponse, debug bool) {
@@ -16,6 +16,8 @@ package cli
 
 import (
 	"context"
+	"io"
+	"syscall"
 	"testing"
 
 	"github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
@@ -25,6 +27,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/yarpc"
 	"go.uber.org/yarpc/yarpcerrors"
 )
 
@@ -177,80 +180,113 @@ func (suite *podActionsTestSuite) TestClientPodStartFail() {
 	suite.Error(suite.client.PodStartAction(testPodName))
 }
 
-// TestPodLogsGetActionSuccess tests failure of getting pod logs
-// due to file not found error
-func (suite *podActionsTestSuite) TestPodLogsGetActionFileNotFound() {
-	podname := &peloton.PodName{
-		Value: "podname",
-	}
-	podID := &peloton.PodID{
-		Value: "podID",
-	}
-	req := &podsvc.BrowsePodSandboxRequest{
-		PodName: podname,
-		PodId:   podID,
-	}
-	resp := &podsvc.BrowsePodSandboxResponse{
-		Hostname: "host1",
-		Port:     "8000",
+// TestPodLogsGetActionSuccess tests the success case of getting pod logs
+func (suite *podActionsTestSuite) TestPodLogsGetActionSuccess() {
+	req := &podsvc.GetPodLogsRequest{
+		PodName:    &peloton.PodName{Value: testPodName},
+		PodId:      &peloton.PodID{Value: testPodID},
+		Filename:   "stderr",
+		Offset:     -1024,
+		LimitBytes: 2048,
+		Follow:     true,
 	}
+	stream := mocks.NewMockPodServiceServiceGetPodLogsYARPCClient(suite.ctrl)
+
+	gomock.InOrder(
+		suite.podClient.EXPECT().
+			GetPodLogs(suite.ctx, req).
+			Return(stream, nil),
+		stream.EXPECT().
+			Recv().
+			Return(&podsvc.GetPodLogsResponse{Data: []byte("hello\n")}, nil),
+		stream.EXPECT().
+			Recv().
+			Return(nil, io.EOF),
+	)
 
-	suite.podClient.EXPECT().
-		BrowsePodSandbox(suite.ctx, req).
-		Return(resp, nil)
-	suite.Error(
+	suite.NoError(
+		suite.client.PodLogsGetAction(
+			"stderr",
+			testPodName,
+			testPodID,
+			false,
+			-1024,
+			2048,
+		),
+	)
+}
+
+// TestPodLogsGetActionFollow tests that following pod logs is not bounded
+// by the timeout of the client, and stops when the CLI is interrupted
+func (suite *podActionsTestSuite) TestPodLogsGetActionFollow() {
+	var streamCtx context.Context
+	stream := mocks.NewMockPodServiceServiceGetPodLogsYARPCClient(suite.ctrl)
+
+	gomock.InOrder(
+		suite.podClient.EXPECT().
+			GetPodLogs(gomock.Any(), gomock.Any()).
+			Do(func(
+				ctx context.Context,
+				req *podsvc.GetPodLogsRequest,
+				opts ...yarpc.CallOption) {
+				_, ok := ctx.Deadline()
+				suite.False(ok)
+				suite.True(req.GetFollow())
+				streamCtx = ctx
+			}).
+			Return(stream, nil),
+		stream.EXPECT().
+			Recv().
+			Return(&podsvc.GetPodLogsResponse{Data: []byte("hello\n")}, nil),
+		stream.EXPECT().
+			Recv().
+			DoAndReturn(func() (*podsvc.GetPodLogsResponse, error) {
+				suite.NoError(
+					syscall.Kill(syscall.Getpid(), syscall.SIGINT))
+				<-streamCtx.Done()
+				return nil, yarpcerrors.CancelledErrorf("stream cancelled")
+			}),
+	)
+
+	suite.NoError(
 		suite.client.PodLogsGetAction(
+			"stdout",
+			testPodName,
 			"",
-			podname.GetValue(),
-			podID.GetValue(),
+			true,
+			0,
+			0,
 		),
 	)
 }
 
-// TestPodLogsGetActionSuccess tests failure of getting pod logs
-// due to BrowsePodSandbox API error
-func (suite *podActionsTestSuite) TestPodLogsGetActionBrowsePodSandboxFailure() {
+// TestPodLogsGetActionFailure tests failure of getting pod logs
+// due to GetPodLogs API error
+func (suite *podActionsTestSuite) TestPodLogsGetActionFailure() {
 	suite.podClient.EXPECT().
-		BrowsePodSandbox(suite.ctx, gomock.Any()).
+		GetPodLogs(suite.ctx, gomock.Any()).
 		Return(nil, yarpcerrors.InternalErrorf("test error"))
 	suite.Error(
-		suite.client.PodLogsGetAction(
-			"",
-			"",
-			"",
-		),
+		suite.client.PodLogsGetAction("", "", "", false, 0, 0),
 	)
 }
 
-// TestPodLogsGetActionSuccess tests failure of getting pod logs
-// due to error while downloading file
-func (suite *podActionsTestSuite) TestPodLogsGetActionFileGetFailure() {
-	podname := &peloton.PodName{
-		Value: "podname",
-	}
-	podID := &peloton.PodID{
-		Value: "podID",
-	}
-	filename := "filename"
-	req := &podsvc.BrowsePodSandboxRequest{
-		PodName: podname,
-		PodId:   podID,
-	}
-	resp := &podsvc.BrowsePodSandboxResponse{
-		Hostname: "host1",
-		Port:     "8000",
-		Paths:    []string{filename},
-	}
+// TestPodLogsGetActionStreamFailure tests failure of getting pod logs
+// due to error while receiving from the stream
+func (suite *podActionsTestSuite) TestPodLogsGetActionStreamFailure() {
+	stream := mocks.NewMockPodServiceServiceGetPodLogsYARPCClient(suite.ctrl)
+
+	gomock.InOrder(
+		suite.podClient.EXPECT().
+			GetPodLogs(suite.ctx, gomock.Any()).
+			Return(stream, nil),
+		stream.EXPECT().
+			Recv().
+			Return(nil, yarpcerrors.NotFoundErrorf("file not found")),
+	)
 
-	suite.podClient.EXPECT().
-		BrowsePodSandbox(suite.ctx, req).
-		Return(resp, nil)
 	suite.Error(
-		suite.client.PodLogsGetAction(
-			filename,
-			podname.GetValue(),
-			podID.GetValue(),
-		),
+		suite.client.PodLogsGetAction("stdout", testPodName, "", false, 0, 0),
 	)
 }
 
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
@@ -145,6 +146,67 @@ func (c *Client) ResPoolDeleteAction(respoolPath string) error {
 	return nil
 }
 
+// ResPoolForecastAction is the action for getting the capacity forecast
+// of a resource pool
+func (c *Client) ResPoolForecastAction(respoolPath string) error {
+	respoolID, err := c.LookupResourcePoolID(respoolPath)
+	if err != nil {
+		return err
+	}
+	if respoolID == nil {
+		return fmt.Errorf("unable to find resource pool ID for "+
+			":%s", respoolPath)
+	}
+
+	response, err := c.resClient.GetCapacityForecast(
+		c.ctx,
+		&respool.GetCapacityForecastRequest{Id: respoolID},
+	)
+	if err != nil {
+		return err
+	}
+	printResPoolForecastResponse(response, respoolPath, c.Debug)
+	return nil
+}
+
+func printResPoolForecastResponse(
+	r *respool.GetCapacityForecastResponse,
+	respoolPath string,
+	debug bool) {
+	if debug {
+		printResponseJSON(r)
+		return
+	}
+
+	fmt.Fprintf(tabWriter, "Capacity forecast of %s from %d samples\n",
+		respoolPath, len(r.GetSamples()))
+	fmt.Fprint(tabWriter,
+		"Kind\tSupply\tUsage\tHeadroom\tGrowth/h\tExhaustion\t\n")
+	for _, f := range r.GetForecasts() {
+		exhaustion := "never"
+		switch {
+		case f.GetSecondsToExhaustion() == 0:
+			exhaustion = "exceeded"
+		case f.GetSecondsToExhaustion() > 0:
+			exhaustion = (time.Duration(f.GetSecondsToExhaustion()) *
+				time.Second).String()
+		}
+		fmt.Fprintf(tabWriter, "%s\t%.2f\t%.2f\t%.2f\t%.2f\t%s\t\n",
+			f.GetKind(), f.GetSupply(), f.GetUsage(), f.GetHeadroom(),
+			f.GetGrowthPerHour(), exhaustion)
+	}
+
+	if len(r.GetAlerts()) > 0 {
+		fmt.Fprint(tabWriter, "Cluster alerts:\n")
+		fmt.Fprint(tabWriter, "Time\tKind\tType\tMessage\t\n")
+		for _, a := range r.GetAlerts() {
+			fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\t\n",
+				a.GetTime(), a.GetKind(), a.GetType().String(), a.GetMessage())
+		}
+	}
+	tabWriter.Flush()
+}
+
 func readResourcePoolConfig(cfgFile string) (respool.ResourcePoolConfig, error) {
 	var respoolConfig respool.ResourcePoolConfig
 	buffer, err := ioutil.ReadFile(cfgFile)
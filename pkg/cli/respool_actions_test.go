@@ -872,6 +872,84 @@ func (suite *resPoolActions) TestClientResPoolDeleteRoot() {
 	suite.Error(c.ResPoolDeleteAction(path))
 }
 
+func (suite *resPoolActions) TestClientResPoolForecastAction() {
+	c := Client{
+		Debug:      false,
+		resClient:  suite.mockRespool,
+		dispatcher: nil,
+		ctx:        suite.ctx,
+	}
+
+	path := "/"
+	respoolID := &peloton.ResourcePoolID{Value: "root"}
+	lookupRequest := &respool.LookupRequest{
+		Path: &respool.ResourcePoolPath{
+			Value: path,
+		},
+	}
+	forecastRequest := &respool.GetCapacityForecastRequest{Id: respoolID}
+	forecastResponse := &respool.GetCapacityForecastResponse{
+		Id: respoolID,
+		Samples: []*respool.CapacitySample{
+			{Time: "2019-01-01T00:00:00Z"},
+		},
+		Forecasts: []*respool.CapacityForecast{
+			{
+				Kind:                "cpu",
+				Supply:              100,
+				Usage:               90,
+				Headroom:            10,
+				GrowthPerHour:       5,
+				SecondsToExhaustion: 7200,
+			},
+			{
+				Kind:                "memory",
+				Supply:              1000,
+				Usage:               100,
+				Headroom:            900,
+				SecondsToExhaustion: -1,
+			},
+		},
+		Alerts: []*respool.CapacityAlert{
+			{
+				Time:    "2019-01-01T00:00:00Z",
+				Kind:    "cpu",
+				Type:    respool.CapacityAlert_EXHAUSTION,
+				Message: "reservations expected to exceed capacity in 2h0m0s",
+			},
+		},
+	}
+
+	for _, debug := range []bool{false, true} {
+		c.Debug = debug
+		suite.withMockResourcePoolLookup(
+			lookupRequest,
+			&respool.LookupResponse{Id: respoolID},
+			nil)
+		suite.mockRespool.EXPECT().
+			GetCapacityForecast(suite.ctx, gomock.Eq(forecastRequest)).
+			Return(forecastResponse, nil)
+		suite.NoError(c.ResPoolForecastAction(path))
+	}
+
+	// lookup failure
+	suite.withMockResourcePoolLookup(
+		lookupRequest,
+		nil,
+		errors.New("lookup failed"))
+	suite.Error(c.ResPoolForecastAction(path))
+
+	// forecast failure
+	suite.withMockResourcePoolLookup(
+		lookupRequest,
+		&respool.LookupResponse{Id: respoolID},
+		nil)
+	suite.mockRespool.EXPECT().
+		GetCapacityForecast(suite.ctx, gomock.Eq(forecastRequest)).
+		Return(nil, errors.New("no capacity history"))
+	suite.Error(c.ResPoolForecastAction(path))
+}
+
 func (suite *resPoolActions) withMockUpdateResponse(
 	req *respool.UpdateRequest,
 	resp *respool.UpdateResponse,
@@ -0,0 +1,239 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template"
+	templatesvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template/svc"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	templateListFormatHeader = "Name\tVersion\tOwningTeam\tParameters\tCreationTime\t\n"
+	templateListFormatBody   = "%s\t%d\t%s\t%d\t%s\t\n"
+)
+
+// readTemplateSpec reads a YAML template specification from a file
+func readTemplateSpec(file string) (*template.TemplateSpec, error) {
+	var spec template.TemplateSpec
+	buffer, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file %s: %v", file, err)
+	}
+	if err := yaml.Unmarshal(buffer, &spec); err != nil {
+		return nil, fmt.Errorf("unable to parse file %s: %v", file, err)
+	}
+	return &spec, nil
+}
+
+// TemplateCreateAction is the action for creating a job template
+func (c *Client) TemplateCreateAction(file string) error {
+	spec, err := readTemplateSpec(file)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.templateClient.CreateTemplate(
+		c.ctx,
+		&templatesvc.CreateTemplateRequest{Spec: spec},
+	)
+	if err != nil {
+		return err
+	}
+
+	if c.Debug {
+		printResponseJSON(resp)
+		return nil
+	}
+	fmt.Printf("Template %s created with version %d\n",
+		spec.GetName(), resp.GetVersion())
+	return nil
+}
+
+// TemplateUpdateAction is the action for updating a job template, and
+// optionally rolling out the new version to the jobs of the template
+func (c *Client) TemplateUpdateAction(
+	file string,
+	version uint64,
+	rollout bool,
+	batchSize uint32,
+) error {
+	spec, err := readTemplateSpec(file)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.templateClient.UpdateTemplate(
+		c.ctx,
+		&templatesvc.UpdateTemplateRequest{
+			Spec:    spec,
+			Version: version,
+			Rollout: rollout,
+			UpdateSpec: &stateless.UpdateSpec{
+				BatchSize: batchSize,
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if c.Debug {
+		printResponseJSON(resp)
+		return nil
+	}
+	fmt.Printf("Template %s updated to version %d\n",
+		spec.GetName(), resp.GetVersion())
+	for _, jobID := range resp.GetRolledOutJobs() {
+		fmt.Printf("Rolled out to job %s\n", jobID.GetValue())
+	}
+	for _, jobID := range resp.GetFailedJobs() {
+		fmt.Printf("Failed to roll out to job %s\n", jobID.GetValue())
+	}
+	return nil
+}
+
+// TemplateGetAction is the action for getting a job template
+func (c *Client) TemplateGetAction(name string, version uint64) error {
+	resp, err := c.templateClient.GetTemplate(
+		c.ctx,
+		&templatesvc.GetTemplateRequest{
+			Name:    name,
+			Version: version,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	out, err := marshallResponse(defaultResponseFormat, resp)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%v\n", string(out))
+	return nil
+}
+
+// TemplateListAction is the action for listing the job templates
+func (c *Client) TemplateListAction() error {
+	resp, err := c.templateClient.ListTemplates(
+		c.ctx,
+		&templatesvc.ListTemplatesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printTemplateListResponse(resp, c.Debug)
+	return nil
+}
+
+func printTemplateListResponse(
+	r *templatesvc.ListTemplatesResponse,
+	debug bool,
+) {
+	if debug {
+		printResponseJSON(r)
+		return
+	}
+	if len(r.GetTemplates()) == 0 {
+		fmt.Fprintf(tabWriter, "No template was found\n")
+		tabWriter.Flush()
+		return
+	}
+	fmt.Fprintf(tabWriter, templateListFormatHeader)
+	for _, t := range r.GetTemplates() {
+		fmt.Fprintf(
+			tabWriter,
+			templateListFormatBody,
+			t.GetSpec().GetName(),
+			t.GetVersion(),
+			t.GetSpec().GetOwningTeam(),
+			len(t.GetSpec().GetParameters()),
+			t.GetCreationTime(),
+		)
+	}
+	tabWriter.Flush()
+}
+
+// TemplateDeleteAction is the action for deleting a job template
+func (c *Client) TemplateDeleteAction(name string) error {
+	resp, err := c.templateClient.DeleteTemplate(
+		c.ctx,
+		&templatesvc.DeleteTemplateRequest{Name: name},
+	)
+	if err != nil {
+		return err
+	}
+
+	printResponseJSON(resp)
+	return nil
+}
+
+// TemplateInstantiateAction is the action for creating a stateless job
+// from a job template
+func (c *Client) TemplateInstantiateAction(
+	name string,
+	version uint64,
+	params map[string]string,
+	jobID string,
+	respoolPath string,
+	batchSize uint32,
+) error {
+	request := &templatesvc.InstantiateTemplateRequest{
+		Name:       name,
+		Version:    version,
+		Parameters: params,
+		JobId:      &v1alphapeloton.JobID{Value: jobID},
+		CreateSpec: &stateless.CreateSpec{
+			BatchSize: batchSize,
+		},
+	}
+
+	if len(respoolPath) != 0 {
+		respoolID, err := c.LookupResourcePoolID(respoolPath)
+		if err != nil {
+			return err
+		}
+		if respoolID == nil {
+			return fmt.Errorf("unable to find resource pool ID for "+
+				":%s", respoolPath)
+		}
+		request.RespoolId = &v1alphapeloton.ResourcePoolID{
+			Value: respoolID.GetValue(),
+		}
+	}
+
+	resp, err := c.templateClient.InstantiateTemplate(c.ctx, request)
+	if err != nil {
+		return err
+	}
+
+	if c.Debug {
+		printResponseJSON(resp)
+		return nil
+	}
+	fmt.Printf("Job %s created from template %s version %d\n",
+		resp.GetJobId().GetValue(),
+		resp.GetTemplate().GetName(),
+		resp.GetTemplate().GetVersion())
+	return nil
+}
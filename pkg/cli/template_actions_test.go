@@ -0,0 +1,171 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template"
+	templatesvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template/svc"
+	templatemocks "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template/svc/mocks"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+const testTemplateSpec = "../../example/stateless/template.yaml"
+
+type templateActionsTestSuite struct {
+	suite.Suite
+	ctx            context.Context
+	ctrl           *gomock.Controller
+	templateClient *templatemocks.MockTemplateServiceYARPCClient
+	client         Client
+}
+
+func TestTemplateActions(t *testing.T) {
+	suite.Run(t, new(templateActionsTestSuite))
+}
+
+func (suite *templateActionsTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.templateClient = templatemocks.NewMockTemplateServiceYARPCClient(suite.ctrl)
+	suite.client = Client{
+		Debug:          false,
+		templateClient: suite.templateClient,
+		dispatcher:     nil,
+		ctx:            suite.ctx,
+	}
+}
+
+func (suite *templateActionsTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+// TestTemplateCreateAction tests creating a template from the example spec
+func (suite *templateActionsTestSuite) TestTemplateCreateAction() {
+	suite.templateClient.EXPECT().
+		CreateTemplate(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *templatesvc.CreateTemplateRequest) {
+			spec := req.GetSpec()
+			suite.Equal("echo_service", spec.GetName())
+			suite.Len(spec.GetParameters(), 4)
+			suite.Equal(
+				template.ParameterType_PARAMETER_TYPE_INT,
+				spec.GetParameters()[1].GetType())
+			suite.Equal("3", spec.GetParameters()[1].GetDefaultValue())
+			suite.NotEmpty(spec.GetBody())
+		}).
+		Return(&templatesvc.CreateTemplateResponse{Version: 1}, nil)
+
+	suite.NoError(suite.client.TemplateCreateAction(testTemplateSpec))
+}
+
+// TestTemplateCreateActionFailure tests failures of creating a template
+func (suite *templateActionsTestSuite) TestTemplateCreateActionFailure() {
+	suite.Error(suite.client.TemplateCreateAction("not-exist.yaml"))
+
+	suite.templateClient.EXPECT().
+		CreateTemplate(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("create failed"))
+	suite.Error(suite.client.TemplateCreateAction(testTemplateSpec))
+}
+
+// TestTemplateUpdateAction tests updating a template with rollout
+func (suite *templateActionsTestSuite) TestTemplateUpdateAction() {
+	suite.templateClient.EXPECT().
+		UpdateTemplate(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *templatesvc.UpdateTemplateRequest) {
+			suite.Equal(uint64(2), req.GetVersion())
+			suite.True(req.GetRollout())
+			suite.Equal(uint32(5), req.GetUpdateSpec().GetBatchSize())
+		}).
+		Return(&templatesvc.UpdateTemplateResponse{
+			Version: 3,
+			RolledOutJobs: []*v1alphapeloton.JobID{
+				{Value: "job1"},
+			},
+			FailedJobs: []*v1alphapeloton.JobID{
+				{Value: "job2"},
+			},
+		}, nil)
+
+	suite.NoError(
+		suite.client.TemplateUpdateAction(testTemplateSpec, 2, true, 5))
+}
+
+// TestTemplateGetListDeleteActions tests getting, listing and deleting
+// templates
+func (suite *templateActionsTestSuite) TestTemplateGetListDeleteActions() {
+	info := &template.TemplateInfo{
+		Spec:    &template.TemplateSpec{Name: "echo_service"},
+		Version: 1,
+	}
+
+	suite.templateClient.EXPECT().
+		GetTemplate(gomock.Any(), &templatesvc.GetTemplateRequest{
+			Name:    "echo_service",
+			Version: 1,
+		}).
+		Return(&templatesvc.GetTemplateResponse{Template: info}, nil)
+	suite.NoError(suite.client.TemplateGetAction("echo_service", 1))
+
+	suite.templateClient.EXPECT().
+		ListTemplates(gomock.Any(), gomock.Any()).
+		Return(&templatesvc.ListTemplatesResponse{
+			Templates: []*template.TemplateInfo{info},
+		}, nil)
+	suite.NoError(suite.client.TemplateListAction())
+
+	suite.templateClient.EXPECT().
+		ListTemplates(gomock.Any(), gomock.Any()).
+		Return(&templatesvc.ListTemplatesResponse{}, nil)
+	suite.NoError(suite.client.TemplateListAction())
+
+	suite.templateClient.EXPECT().
+		DeleteTemplate(gomock.Any(), &templatesvc.DeleteTemplateRequest{
+			Name: "echo_service",
+		}).
+		Return(nil, errors.New("delete failed"))
+	suite.Error(suite.client.TemplateDeleteAction("echo_service"))
+}
+
+// TestTemplateInstantiateAction tests creating a job from a template
+func (suite *templateActionsTestSuite) TestTemplateInstantiateAction() {
+	params := map[string]string{"environment": "production"}
+
+	suite.templateClient.EXPECT().
+		InstantiateTemplate(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *templatesvc.InstantiateTemplateRequest) {
+			suite.Equal("echo_service", req.GetName())
+			suite.Equal(params, req.GetParameters())
+			suite.Nil(req.GetRespoolId())
+			suite.Equal(uint32(1), req.GetCreateSpec().GetBatchSize())
+		}).
+		Return(&templatesvc.InstantiateTemplateResponse{
+			JobId: &v1alphapeloton.JobID{Value: "job1"},
+			Template: &template.TemplateReference{
+				Name:    "echo_service",
+				Version: 1,
+			},
+		}, nil)
+
+	suite.NoError(suite.client.TemplateInstantiateAction(
+		"echo_service", 0, params, "", "", 1))
+}
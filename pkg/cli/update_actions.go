@@ -293,6 +293,28 @@ func (c *Client) UpdatePauseAction(updateID string, opaqueData string) error {
 	return nil
 }
 
+// UpdateRollbackAction rolls back a given update
+func (c *Client) UpdateRollbackAction(updateID string, opaqueData string) error {
+	var opaque *peloton.OpaqueData
+	if len(opaqueData) > 0 {
+		opaque = &peloton.OpaqueData{Data: opaqueData}
+	}
+
+	var request = &updatesvc.RollbackUpdateRequest{
+		UpdateId: &peloton.UpdateID{
+			Value: updateID,
+		},
+		OpaqueData: opaque,
+	}
+
+	resp, err := c.updateClient.RollbackUpdate(c.ctx, request)
+	if err != nil {
+		return err
+	}
+	printUpdateRollbackResponse(resp, c.Debug)
+	return nil
+}
+
 // printUpdateCreateResponse prints the update identifier returned in the
 // create job update response.
 func printUpdateCreateResponse(resp *updatesvc.CreateUpdateResponse, debug bool) {
@@ -310,6 +332,23 @@ func printUpdateCreateResponse(resp *updatesvc.CreateUpdateResponse, debug bool)
 	return
 }
 
+// printUpdateRollbackResponse prints the identifier of the update
+// rolling back the job returned in the rollback update response.
+func printUpdateRollbackResponse(resp *updatesvc.RollbackUpdateResponse, debug bool) {
+	defer tabWriter.Flush()
+
+	if debug {
+		printResponseJSON(resp)
+		return
+	}
+
+	if resp.GetUpdateID() != nil {
+		fmt.Fprintf(tabWriter, "Job update %s rolling back\n",
+			resp.GetUpdateID().GetValue())
+	}
+	return
+}
+
 // printUpdate prints the update status information for a single update
 func printUpdate(u *update.UpdateInfo) {
 	status := u.GetStatus()
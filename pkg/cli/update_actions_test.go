@@ -708,6 +708,52 @@ func (suite *updateActionsTestSuite) TestClientUpdateAbort() {
 	}
 }
 
+// TestClientUpdateRollback tests rolling back a job update
+func (suite *updateActionsTestSuite) TestClientUpdateRollback() {
+	c := Client{
+		Debug:        false,
+		updateClient: suite.mockUpdate,
+		dispatcher:   nil,
+		ctx:          suite.ctx,
+	}
+
+	resp := &svc.RollbackUpdateResponse{
+		UpdateID: suite.updateID,
+	}
+	tt := []struct {
+		debug bool
+		err   error
+	}{
+		{
+			err: nil,
+		},
+		{
+			debug: true,
+			err:   nil,
+		},
+		{
+			err: errors.New("update already rolled back"),
+		},
+	}
+
+	for _, t := range tt {
+		c.Debug = t.debug
+		suite.mockUpdate.EXPECT().
+			RollbackUpdate(context.Background(), gomock.Any()).
+			Do(func(_ context.Context, req *svc.RollbackUpdateRequest) {
+				suite.Equal(suite.updateID.GetValue(), req.GetUpdateId().GetValue())
+				suite.Equal("opaque", req.GetOpaqueData().GetData())
+			}).
+			Return(resp, t.err)
+
+		if t.err != nil {
+			suite.Error(c.UpdateRollbackAction(suite.updateID.GetValue(), "opaque"))
+		} else {
+			suite.NoError(c.UpdateRollbackAction(suite.updateID.GetValue(), "opaque"))
+		}
+	}
+}
+
 // TestClientUpdatePause tests pausing a job update
 func (suite *updateActionsTestSuite) TestClientUpdatePause() {
 	c := Client{
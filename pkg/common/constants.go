@@ -67,6 +67,10 @@ const (
 	MEMORY = "memory"
 	// DISK resource type
 	DISK = "disk"
+	// PORTS resource type
+	PORTS = "ports"
+	// NETWORK resource type, network bandwidth in Mbps
+	NETWORK = "network"
 
 	// RootResPoolID is the ID for Root node
 	RootResPoolID = "root"
@@ -101,6 +105,8 @@ const (
 	MesosGPU = "gpus"
 	// MesosPorts resource kind
 	MesosPorts = "ports"
+	// MesosNetworkBandwidth resource kind
+	MesosNetworkBandwidth = "network_bandwidth"
 )
 
 const (
@@ -118,6 +124,15 @@ const (
 	SystemLabelJobType = "job_type"
 	// SystemLabelCluster is the system label key name for cluster
 	SystemLabelCluster = "cluster"
+	// SystemLabelTemplateName is the system label key name for the job
+	// template a job is instantiated from
+	SystemLabelTemplateName = "template_name"
+	// SystemLabelTemplateVersion is the system label key name for the job
+	// template version a job is instantiated from
+	SystemLabelTemplateVersion = "template_version"
+	// SystemLabelVolumeRetentionPolicy is the system label key name for the
+	// policy of the persistent volumes of a stateful job on job deletion
+	SystemLabelVolumeRetentionPolicy = "volume_retention_policy"
 	// ClusterEnvVar is the cluster environment variable
 	ClusterEnvVar = "CLUSTER"
 	// PelotonExclusiveAttributeName is the name of Mesos agent attribute
@@ -0,0 +1,243 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package constraints
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+)
+
+// ErrUnknownAttributeCondition is the error when unknown
+// AttributeConstraint.Condition enum is processed.
+var ErrUnknownAttributeCondition = errors.New(
+	"unknown enum value for AttributeConstraint.Condition")
+
+// FormatRange returns the label value of a range of a ranges attribute.
+func FormatRange(begin, end uint64) string {
+	return fmt.Sprintf("[%d-%d]", begin, end)
+}
+
+// compareFunc compares two attribute values and returns -1, 0 or 1 if
+// the first is lower than, equal to or greater than the second. Returns
+// false if either value cannot be compared.
+type compareFunc func(a, b string) (int, bool)
+
+// compareNumbers compares two attribute values as numbers, so that "4.5"
+// is greater than "4.14".
+func compareNumbers(a, b string) (int, bool) {
+	x, err := strconv.ParseFloat(a, _bitsize)
+	if err != nil {
+		return 0, false
+	}
+	y, err := strconv.ParseFloat(b, _bitsize)
+	if err != nil {
+		return 0, false
+	}
+	switch {
+	case x < y:
+		return -1, true
+	case x > y:
+		return 1, true
+	}
+	return 0, true
+}
+
+// compareVersions compares two attribute values as dotted versions, so
+// that "4.9" is lower than "4.14".
+func compareVersions(a, b string) (int, bool) {
+	x, ok := parseVersion(normalizeScalar(a))
+	if !ok {
+		return 0, false
+	}
+	y, ok := parseVersion(normalizeScalar(b))
+	if !ok {
+		return 0, false
+	}
+	return x.compare(y), true
+}
+
+// version is a dotted attribute value such as "4.14.0-1", whose
+// segments are compared numerically one after the other.
+type version []uint64
+
+// parseVersion parses a dotted value into its numeric segments, "-" is
+// also accepted as a separator so that "4.14.0-1" is 4.14.0.1.
+// Returns false if a segment is not a non-negative integer.
+func parseVersion(value string) (version, bool) {
+	segments := strings.Split(strings.Replace(value, "-", ".", -1), ".")
+	result := make(version, 0, len(segments))
+	for _, segment := range segments {
+		v, err := strconv.ParseUint(segment, 10, _bitsize)
+		if err != nil {
+			return nil, false
+		}
+		result = append(result, v)
+	}
+	return result, true
+}
+
+// compare returns -1, 0 or 1 if v is lower than, equal to or greater
+// than other. Missing segments are zero, so that "4.14" equals "4.14.0".
+func (v version) compare(other version) int {
+	for i := 0; i < len(v) || i < len(other); i++ {
+		var a, b uint64
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(other) {
+			b = other[i]
+		}
+		if a < b {
+			return -1
+		}
+		if a > b {
+			return 1
+		}
+	}
+	return 0
+}
+
+// normalizeScalar trims the trailing zeros of a scalar attribute value
+// formatted by GetHostLabelValues, so that "4.140000" compares as "4.14".
+func normalizeScalar(value string) string {
+	dot := strings.Index(value, ".")
+	if dot < 0 || len(value)-dot-1 != _precision {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, _bitsize); err != nil {
+		return value
+	}
+	return strings.TrimSuffix(strings.TrimRight(value, "0"), ".")
+}
+
+// valueRange is an inclusive range of attribute values.
+type valueRange struct {
+	begin string
+	end   string
+}
+
+// parseAttributeValue parses the ranges represented by the value of an
+// attribute: a single value is the range of that value, and ranges are
+// formatted as "[b-e]", separated by ";" if there are several of them.
+// Returns false if the value looks like ranges but cannot be parsed.
+func parseAttributeValue(value string) ([]valueRange, bool) {
+	if !strings.HasPrefix(value, "[") {
+		return []valueRange{{begin: value, end: value}}, true
+	}
+
+	var result []valueRange
+	for _, r := range strings.Split(value, ";") {
+		if !strings.HasPrefix(r, "[") || !strings.HasSuffix(r, "]") {
+			return nil, false
+		}
+		bounds := strings.SplitN(r[1:len(r)-1], "-", 2)
+		if len(bounds) != 2 {
+			return nil, false
+		}
+		result = append(result, valueRange{begin: bounds[0], end: bounds[1]})
+	}
+	return result, true
+}
+
+// MatchAttributeValues returns true if any of the values of a host
+// attribute satisfies the given attribute constraint. Values are compared
+// as numbers, or as dotted versions, segment by segment, if the constraint
+// is on a version, so that "4.9" is lower than "4.14".
+func MatchAttributeValues(
+	constraint *task.AttributeConstraint,
+	values []string,
+) (bool, error) {
+	switch constraint.GetCondition() {
+	case task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL,
+		task.AttributeConstraint_CONDITION_LESS_THAN_OR_EQUAL,
+		task.AttributeConstraint_CONDITION_IN_RANGE,
+		task.AttributeConstraint_CONDITION_IN_SET:
+	default:
+		return false, ErrUnknownAttributeCondition
+	}
+
+	for _, value := range values {
+		if matchAttributeValue(constraint, value) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func matchAttributeValue(
+	constraint *task.AttributeConstraint,
+	value string,
+) bool {
+	if constraint.GetCondition() == task.AttributeConstraint_CONDITION_IN_SET {
+		for _, v := range constraint.GetValues() {
+			if v == value {
+				return true
+			}
+		}
+	}
+
+	ranges, ok := parseAttributeValue(value)
+	if !ok {
+		return false
+	}
+
+	compare := compareFunc(compareNumbers)
+	if constraint.GetVersion() {
+		compare = compareVersions
+	}
+
+	for _, r := range ranges {
+		switch constraint.GetCondition() {
+		case task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL:
+			c, ok := compare(r.end, constraint.GetValue())
+			if ok && c >= 0 {
+				return true
+			}
+		case task.AttributeConstraint_CONDITION_LESS_THAN_OR_EQUAL:
+			c, ok := compare(r.begin, constraint.GetValue())
+			if ok && c <= 0 {
+				return true
+			}
+		case task.AttributeConstraint_CONDITION_IN_RANGE:
+			beforeEnd, ok := compare(r.begin, constraint.GetRangeEnd())
+			if !ok {
+				return false
+			}
+			afterBegin, ok := compare(r.end, constraint.GetRangeBegin())
+			if !ok {
+				return false
+			}
+			if beforeEnd <= 0 && afterBegin >= 0 {
+				return true
+			}
+		case task.AttributeConstraint_CONDITION_IN_SET:
+			for _, v := range constraint.GetValues() {
+				afterBegin, ok := compare(v, r.begin)
+				if !ok {
+					continue
+				}
+				beforeEnd, ok := compare(v, r.end)
+				if ok && afterBegin >= 0 && beforeEnd <= 0 {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -19,9 +19,11 @@ import (
 
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
 	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 
 	"github.com/uber/peloton/pkg/common"
 
+	"github.com/gogo/protobuf/proto"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -64,12 +66,26 @@ var (
 
 // evaluator implements Evaluator by filtering out any constraint which has a
 // different kind.
-type evaluator task.LabelConstraint_Kind
+type evaluator struct {
+	kind task.LabelConstraint_Kind
+	// topologyCounts are the number of tasks per topology domain of the
+	// topology constraints with limits.
+	topologyCounts []*hostsvc.TopologyDomainCounts
+}
 
 // NewEvaluator return a new instance of evaluator which filters out constraints
 // of different kind.
 func NewEvaluator(kind task.LabelConstraint_Kind) Evaluator {
-	return evaluator(kind)
+	return evaluator{kind: kind}
+}
+
+// NewTopologyEvaluator returns a new instance of evaluator which filters out
+// constraints of different kind, and enforces the limits of the topology
+// constraints with the given number of tasks per topology domain.
+func NewTopologyEvaluator(
+	kind task.LabelConstraint_Kind,
+	topologyCounts []*hostsvc.TopologyDomainCounts) Evaluator {
+	return evaluator{kind: kind, topologyCounts: topologyCounts}
 }
 
 // Evaluate takes given constraints and labels, and evaluate whether all parts
@@ -88,6 +104,16 @@ func (e evaluator) Evaluate(
 	case task.Constraint_LABEL_CONSTRAINT:
 		return e.evaluateLabelConstraint(
 			constraint.GetLabelConstraint(), labelValues)
+	case task.Constraint_ATTRIBUTE_CONSTRAINT:
+		return e.evaluateAttributeConstraint(
+			constraint.GetAttributeConstraint(), labelValues)
+	case task.Constraint_TOPOLOGY_CONSTRAINT:
+		return e.evaluateTopologyConstraint(
+			constraint.GetTopologyConstraint(), labelValues)
+	case task.Constraint_PREFERENCE_CONSTRAINT:
+		// Preferences only order the hosts which satisfy the other
+		// constraints, so they never exclude a host.
+		return EvaluateResultNotApplicable, nil
 	}
 
 	log.WithField("type", constraint.GetType()).
@@ -146,7 +172,7 @@ func (e evaluator) evaluateLabelConstraint(
 
 	// If kind of LabelConstraint does not match, returns not applicable
 	// which will not short-circuit any And/Or constraint evaluation.
-	if labelConstraint.GetKind() != task.LabelConstraint_Kind(e) {
+	if labelConstraint.GetKind() != e.kind {
 		return EvaluateResultNotApplicable, nil
 	}
 
@@ -174,6 +200,109 @@ func (e evaluator) evaluateLabelConstraint(
 	return EvaluateResultMismatch, nil
 }
 
+// evaluateAttributeConstraint evaluates a constraint on the value of a host
+// attribute, which only applies to host labels. A host without the
+// attribute does not satisfy the constraint.
+func (e evaluator) evaluateAttributeConstraint(
+	attributeConstraint *task.AttributeConstraint,
+	labelValues LabelValues,
+) (EvaluateResult, error) {
+
+	if e.kind != task.LabelConstraint_HOST {
+		return EvaluateResultNotApplicable, nil
+	}
+
+	var values []string
+	for value := range labelValues[attributeConstraint.GetName()] {
+		values = append(values, value)
+	}
+
+	match, err := MatchAttributeValues(attributeConstraint, values)
+	if err != nil {
+		log.WithField("type", attributeConstraint.GetCondition()).
+			Error(err.Error())
+		return EvaluateResultNotApplicable, err
+	}
+	if match {
+		return EvaluateResultMatch, nil
+	}
+
+	return EvaluateResultMismatch, nil
+}
+
+// evaluateTopologyConstraint evaluates that the host belongs to a
+// topology domain of a topology constraint, and that placing the task on
+// the host keeps the number of tasks carrying the label of the constraint
+// in the domain within the maximum per domain and within the maximum skew
+// of the domain with the fewest tasks. The limits are only enforced if the
+// evaluator has the number of tasks per domain of the constraint.
+func (e evaluator) evaluateTopologyConstraint(
+	topologyConstraint *task.TopologyConstraint,
+	labelValues LabelValues,
+) (EvaluateResult, error) {
+
+	if e.kind != task.LabelConstraint_HOST {
+		return EvaluateResultNotApplicable, nil
+	}
+
+	domains := labelValues[topologyConstraint.GetTopologyKey()]
+	if len(domains) == 0 {
+		return EvaluateResultMismatch, nil
+	}
+
+	topologyCounts := e.getTopologyCounts(topologyConstraint)
+	if !hasLimits(topologyConstraint) ||
+		!topologyCounts.GetCountsPlacedTask() {
+		return EvaluateResultMatch, nil
+	}
+
+	counts := topologyCounts.GetCounts()
+	min := -1
+	for _, count := range counts {
+		if min < 0 || int(count) < min {
+			min = int(count)
+		}
+	}
+	for domain := range domains {
+		// the domains of the host without tasks have the fewest
+		if _, ok := counts[domain]; !ok {
+			min = 0
+		}
+	}
+
+	maxPerDomain := int(topologyConstraint.GetMaxPerDomain())
+	maxSkew := int(topologyConstraint.GetMaxSkew())
+	for domain := range domains {
+		count := int(counts[domain]) + 1
+		if maxPerDomain > 0 && count > maxPerDomain {
+			return EvaluateResultMismatch, nil
+		}
+		if maxSkew > 0 && count-min > maxSkew {
+			return EvaluateResultMismatch, nil
+		}
+	}
+
+	return EvaluateResultMatch, nil
+}
+
+// getTopologyCounts returns the number of tasks per topology domain of the
+// topology constraint, or nil if the evaluator doesn't have them.
+func (e evaluator) getTopologyCounts(
+	topologyConstraint *task.TopologyConstraint,
+) *hostsvc.TopologyDomainCounts {
+	for _, topologyCounts := range e.topologyCounts {
+		if topologyCounts.GetTopologyKey() ==
+			topologyConstraint.GetTopologyKey() &&
+			topologyCounts.GetLabel().GetKey() ==
+				topologyConstraint.GetLabel().GetKey() &&
+			topologyCounts.GetLabel().GetValue() ==
+				topologyConstraint.GetLabel().GetValue() {
+			return topologyCounts
+		}
+	}
+	return nil
+}
+
 func valueCount(label *peloton.Label, labelValues LabelValues) uint32 {
 	return labelValues[label.GetKey()][label.GetValue()]
 }
@@ -206,3 +335,135 @@ func IsNonExclusiveConstraint(constraint *task.Constraint) bool {
 	}
 	return true
 }
+
+// HasTopologyLimits returns true if any component of the constraint
+// specification is a topology constraint which limits the number of tasks
+// per topology domain.
+func HasTopologyLimits(constraint *task.Constraint) bool {
+	switch constraint.GetType() {
+	case task.Constraint_AND_CONSTRAINT:
+		for _, c := range constraint.GetAndConstraint().GetConstraints() {
+			if HasTopologyLimits(c) {
+				return true
+			}
+		}
+	case task.Constraint_OR_CONSTRAINT:
+		for _, c := range constraint.GetOrConstraint().GetConstraints() {
+			if HasTopologyLimits(c) {
+				return true
+			}
+		}
+	case task.Constraint_TOPOLOGY_CONSTRAINT:
+		return hasLimits(constraint.GetTopologyConstraint())
+	}
+	return false
+}
+
+// GetTopologyLimits returns the topology constraints of the constraint
+// specification which limit the number of tasks per topology domain.
+func GetTopologyLimits(constraint *task.Constraint) []*task.TopologyConstraint {
+	var result []*task.TopologyConstraint
+	switch constraint.GetType() {
+	case task.Constraint_AND_CONSTRAINT:
+		for _, c := range constraint.GetAndConstraint().GetConstraints() {
+			result = append(result, GetTopologyLimits(c)...)
+		}
+	case task.Constraint_OR_CONSTRAINT:
+		for _, c := range constraint.GetOrConstraint().GetConstraints() {
+			result = append(result, GetTopologyLimits(c)...)
+		}
+	case task.Constraint_TOPOLOGY_CONSTRAINT:
+		if hasLimits(constraint.GetTopologyConstraint()) {
+			result = append(result, constraint.GetTopologyConstraint())
+		}
+	}
+	return result
+}
+
+// WithoutTopologyLimits returns a copy of the constraint specification
+// where the topology constraints only require the host to be in a topology
+// domain, so that the hosts of all the domains satisfy it.
+func WithoutTopologyLimits(constraint *task.Constraint) *task.Constraint {
+	if !HasTopologyLimits(constraint) {
+		return constraint
+	}
+
+	result := proto.Clone(constraint).(*task.Constraint)
+	switch constraint.GetType() {
+	case task.Constraint_AND_CONSTRAINT:
+		result.AndConstraint = &task.AndConstraint{
+			Constraints: withoutTopologyLimits(
+				constraint.GetAndConstraint().GetConstraints()),
+		}
+	case task.Constraint_OR_CONSTRAINT:
+		result.OrConstraint = &task.OrConstraint{
+			Constraints: withoutTopologyLimits(
+				constraint.GetOrConstraint().GetConstraints()),
+		}
+	case task.Constraint_TOPOLOGY_CONSTRAINT:
+		result.TopologyConstraint = &task.TopologyConstraint{
+			TopologyKey: constraint.GetTopologyConstraint().GetTopologyKey(),
+			Label:       constraint.GetTopologyConstraint().GetLabel(),
+		}
+	}
+	return result
+}
+
+func withoutTopologyLimits(constraints []*task.Constraint) []*task.Constraint {
+	result := make([]*task.Constraint, 0, len(constraints))
+	for _, c := range constraints {
+		result = append(result, WithoutTopologyLimits(c))
+	}
+	return result
+}
+
+// Relax returns a copy of the constraint specification which only keeps
+// the requirements on the host itself: the label constraints on the
+// labels of the tasks running on the host are removed, as well as the
+// limits of the topology constraints on the number of tasks per domain.
+// The host label, attribute and preference constraints are kept. Nil is
+// returned if nothing is left of the constraint.
+func Relax(constraint *task.Constraint) *task.Constraint {
+	switch constraint.GetType() {
+	case task.Constraint_AND_CONSTRAINT:
+		var relaxed []*task.Constraint
+		for _, c := range constraint.GetAndConstraint().GetConstraints() {
+			if r := Relax(c); r != nil {
+				relaxed = append(relaxed, r)
+			}
+		}
+		if len(relaxed) == 0 {
+			return nil
+		}
+		return &task.Constraint{
+			Type:          task.Constraint_AND_CONSTRAINT,
+			AndConstraint: &task.AndConstraint{Constraints: relaxed},
+		}
+	case task.Constraint_OR_CONSTRAINT:
+		var relaxed []*task.Constraint
+		for _, c := range constraint.GetOrConstraint().GetConstraints() {
+			r := Relax(c)
+			if r == nil {
+				// the removed alternative is always satisfied
+				return nil
+			}
+			relaxed = append(relaxed, r)
+		}
+		return &task.Constraint{
+			Type:         task.Constraint_OR_CONSTRAINT,
+			OrConstraint: &task.OrConstraint{Constraints: relaxed},
+		}
+	case task.Constraint_LABEL_CONSTRAINT:
+		if constraint.GetLabelConstraint().GetKind() == task.LabelConstraint_TASK {
+			return nil
+		}
+	case task.Constraint_TOPOLOGY_CONSTRAINT:
+		return WithoutTopologyLimits(constraint)
+	}
+	return constraint
+}
+
+func hasLimits(topologyConstraint *task.TopologyConstraint) bool {
+	return topologyConstraint.GetMaxPerDomain() > 0 ||
+		topologyConstraint.GetMaxSkew() > 0
+}
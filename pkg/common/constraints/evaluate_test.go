@@ -19,6 +19,7 @@ import (
 
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
 	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 
 	"github.com/uber/peloton/pkg/common"
 
@@ -316,6 +317,477 @@ func (suite *EvaluatorTestSuite) TestKindEvaluator() {
 	}
 }
 
+// TestAttributeConstraint tests evaluating attribute constraints
+func (suite *EvaluatorTestSuite) TestAttributeConstraint() {
+	hostLabels := LabelValues(map[string]map[string]uint32{
+		HostNameKey: {_testHost1: 1},
+		"kernel":    {"4.9": 1},
+		"release":   {"4.14.0-1": 1},
+		"cores":     {"24.000000": 1},
+		"ratio":     {"0.500000": 1},
+		"speed":     {"4.5": 1},
+		"numa":      {"[0-1]": 1, "[4-5]": 1},
+		"zone":      {"dca1": 1},
+	})
+
+	attributeConstraint := func(
+		name string,
+		condition task.AttributeConstraint_Condition,
+		value string,
+		rangeBegin string,
+		rangeEnd string,
+		values ...string,
+	) *task.Constraint {
+		return &task.Constraint{
+			Type: task.Constraint_ATTRIBUTE_CONSTRAINT,
+			AttributeConstraint: &task.AttributeConstraint{
+				Name:       name,
+				Condition:  condition,
+				Value:      value,
+				RangeBegin: rangeBegin,
+				RangeEnd:   rangeEnd,
+				Values:     values,
+			},
+		}
+	}
+	versionConstraint := func(
+		name string,
+		condition task.AttributeConstraint_Condition,
+		value string,
+		rangeBegin string,
+		rangeEnd string,
+	) *task.Constraint {
+		constraint := attributeConstraint(
+			name, condition, value, rangeBegin, rangeEnd)
+		constraint.AttributeConstraint.Version = true
+		return constraint
+	}
+
+	table := []testCase{
+		{
+			msg:      "version greater than or equal match",
+			expected: EvaluateResultMatch,
+			constraint: versionConstraint("kernel",
+				task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL, "4.9", "", ""),
+		},
+		{
+			msg:      "version segments are compared numerically",
+			expected: EvaluateResultMismatch,
+			constraint: versionConstraint("kernel",
+				task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL, "4.14", "", ""),
+		},
+		{
+			msg:      "version less than or equal match",
+			expected: EvaluateResultMatch,
+			constraint: versionConstraint("kernel",
+				task.AttributeConstraint_CONDITION_LESS_THAN_OR_EQUAL, "4.14", "", ""),
+		},
+		{
+			msg:      "release version greater than shorter version",
+			expected: EvaluateResultMatch,
+			constraint: versionConstraint("release",
+				task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL, "4.14.0", "", ""),
+		},
+		{
+			msg:      "release version lower than next release",
+			expected: EvaluateResultMismatch,
+			constraint: versionConstraint("release",
+				task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL, "4.14.0-2", "", ""),
+		},
+		{
+			msg:      "release version in range",
+			expected: EvaluateResultMatch,
+			constraint: versionConstraint("release",
+				task.AttributeConstraint_CONDITION_IN_RANGE, "", "4.9", "4.15"),
+		},
+		{
+			msg:      "scalar greater than or equal match",
+			expected: EvaluateResultMatch,
+			constraint: attributeConstraint("cores",
+				task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL, "24", "", ""),
+		},
+		{
+			msg:      "scalar greater than or equal mismatch",
+			expected: EvaluateResultMismatch,
+			constraint: attributeConstraint("cores",
+				task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL, "25", "", ""),
+		},
+		{
+			msg:      "scalar compared as number",
+			expected: EvaluateResultMatch,
+			constraint: attributeConstraint("speed",
+				task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL, "4.14", "", ""),
+		},
+		{
+			msg:      "scalar compared as version",
+			expected: EvaluateResultMismatch,
+			constraint: versionConstraint("speed",
+				task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL, "4.14", "", ""),
+		},
+		{
+			msg:      "fractional scalar in set match",
+			expected: EvaluateResultMatch,
+			constraint: attributeConstraint("ratio",
+				task.AttributeConstraint_CONDITION_IN_SET, "", "", "", "0.5"),
+		},
+		{
+			msg:      "ranges in range match",
+			expected: EvaluateResultMatch,
+			constraint: attributeConstraint("numa",
+				task.AttributeConstraint_CONDITION_IN_RANGE, "", "3", "4"),
+		},
+		{
+			msg:      "ranges in range mismatch",
+			expected: EvaluateResultMismatch,
+			constraint: attributeConstraint("numa",
+				task.AttributeConstraint_CONDITION_IN_RANGE, "", "2", "3"),
+		},
+		{
+			msg:      "ranges in set match",
+			expected: EvaluateResultMatch,
+			constraint: attributeConstraint("numa",
+				task.AttributeConstraint_CONDITION_IN_SET, "", "", "", "3", "5"),
+		},
+		{
+			msg:      "text in set match",
+			expected: EvaluateResultMatch,
+			constraint: attributeConstraint("zone",
+				task.AttributeConstraint_CONDITION_IN_SET, "", "", "", "dca1", "sjc1"),
+		},
+		{
+			msg:      "text in set mismatch",
+			expected: EvaluateResultMismatch,
+			constraint: attributeConstraint("zone",
+				task.AttributeConstraint_CONDITION_IN_SET, "", "", "", "sjc1"),
+		},
+		{
+			msg:      "text is not compared as version",
+			expected: EvaluateResultMismatch,
+			constraint: attributeConstraint("zone",
+				task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL, "0", "", ""),
+		},
+		{
+			msg:      "invalid constraint value mismatch",
+			expected: EvaluateResultMismatch,
+			constraint: attributeConstraint("kernel",
+				task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL, "4..9", "", ""),
+		},
+		{
+			msg:      "missing attribute mismatch",
+			expected: EvaluateResultMismatch,
+			constraint: attributeConstraint("rack",
+				task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL, "0", "", ""),
+		},
+		{
+			msg:         "unknown condition",
+			expected:    EvaluateResultNotApplicable,
+			expectedErr: ErrUnknownAttributeCondition,
+			constraint: attributeConstraint("kernel",
+				task.AttributeConstraint_Condition(-1), "", "", ""),
+		},
+	}
+
+	e := NewEvaluator(task.LabelConstraint_HOST)
+	for _, tt := range table {
+		actual, err := e.Evaluate(tt.constraint, hostLabels)
+		if tt.expectedErr != nil {
+			suite.Equal(tt.expectedErr, err, tt.msg)
+		} else {
+			suite.NoError(err, tt.msg)
+		}
+		suite.Equal(tt.expected, actual, tt.msg)
+	}
+
+	// attribute constraints do not apply to task labels
+	actual, err := NewEvaluator(task.LabelConstraint_TASK).Evaluate(
+		attributeConstraint("kernel",
+			task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL, "5", "", ""),
+		hostLabels)
+	suite.NoError(err)
+	suite.Equal(EvaluateResultNotApplicable, actual)
+}
+
+// TestTopologyConstraint tests evaluating topology constraints
+func (suite *EvaluatorTestSuite) TestTopologyConstraint() {
+	hostLabels := LabelValues(map[string]map[string]uint32{
+		HostNameKey: {_testHost1: 1},
+		"rack":      {"r1": 1},
+	})
+
+	topologyConstraint := func(
+		topologyKey string,
+		maxPerDomain uint32) *task.Constraint {
+		return &task.Constraint{
+			Type: task.Constraint_TOPOLOGY_CONSTRAINT,
+			TopologyConstraint: &task.TopologyConstraint{
+				TopologyKey: topologyKey,
+				Label: &peloton.Label{
+					Key:   "job",
+					Value: "cassandra",
+				},
+				MaxPerDomain: maxPerDomain,
+			},
+		}
+	}
+
+	e := NewEvaluator(task.LabelConstraint_HOST)
+	actual, err := e.Evaluate(topologyConstraint("rack", 0), hostLabels)
+	suite.NoError(err)
+	suite.Equal(EvaluateResultMatch, actual)
+
+	// hosts without the topology attribute are not in any domain
+	actual, err = e.Evaluate(topologyConstraint("zone", 0), hostLabels)
+	suite.NoError(err)
+	suite.Equal(EvaluateResultMismatch, actual)
+
+	// only the domain of the host is required without the task counts
+	actual, err = e.Evaluate(topologyConstraint("rack", 1), hostLabels)
+	suite.NoError(err)
+	suite.Equal(EvaluateResultMatch, actual)
+
+	topologyCounts := func(
+		counts map[string]uint32,
+		countsPlacedTask bool) []*hostsvc.TopologyDomainCounts {
+		return []*hostsvc.TopologyDomainCounts{
+			{
+				TopologyKey: "rack",
+				Label: &peloton.Label{
+					Key:   "job",
+					Value: "cassandra",
+				},
+				Counts:           counts,
+				CountsPlacedTask: countsPlacedTask,
+			},
+		}
+	}
+
+	// the domain of the host has reached the maximum per domain
+	e = NewTopologyEvaluator(
+		task.LabelConstraint_HOST,
+		topologyCounts(map[string]uint32{"r1": 1, "r2": 0}, true))
+	actual, err = e.Evaluate(topologyConstraint("rack", 1), hostLabels)
+	suite.NoError(err)
+	suite.Equal(EvaluateResultMismatch, actual)
+
+	actual, err = e.Evaluate(topologyConstraint("rack", 2), hostLabels)
+	suite.NoError(err)
+	suite.Equal(EvaluateResultMatch, actual)
+
+	// the limits only apply to the tasks carrying the label
+	actual, err = NewTopologyEvaluator(
+		task.LabelConstraint_HOST,
+		topologyCounts(map[string]uint32{"r1": 1}, false),
+	).Evaluate(topologyConstraint("rack", 1), hostLabels)
+	suite.NoError(err)
+	suite.Equal(EvaluateResultMatch, actual)
+
+	skewConstraint := &task.Constraint{
+		Type: task.Constraint_TOPOLOGY_CONSTRAINT,
+		TopologyConstraint: &task.TopologyConstraint{
+			TopologyKey: "rack",
+			Label: &peloton.Label{
+				Key:   "job",
+				Value: "cassandra",
+			},
+			MaxSkew: 1,
+		},
+	}
+
+	// the domain of the host has one task more than the domain with the
+	// fewest tasks
+	actual, err = NewTopologyEvaluator(
+		task.LabelConstraint_HOST,
+		topologyCounts(map[string]uint32{"r1": 2, "r2": 1}, true),
+	).Evaluate(skewConstraint, hostLabels)
+	suite.NoError(err)
+	suite.Equal(EvaluateResultMismatch, actual)
+
+	actual, err = NewTopologyEvaluator(
+		task.LabelConstraint_HOST,
+		topologyCounts(map[string]uint32{"r1": 2, "r2": 2}, true),
+	).Evaluate(skewConstraint, hostLabels)
+	suite.NoError(err)
+	suite.Equal(EvaluateResultMatch, actual)
+
+	// a domain of the host without tasks has the fewest tasks
+	actual, err = NewTopologyEvaluator(
+		task.LabelConstraint_HOST,
+		topologyCounts(map[string]uint32{"r2": 3}, true),
+	).Evaluate(skewConstraint, hostLabels)
+	suite.NoError(err)
+	suite.Equal(EvaluateResultMatch, actual)
+
+	// topology constraints do not apply to task labels
+	actual, err = NewEvaluator(task.LabelConstraint_TASK).Evaluate(
+		topologyConstraint("rack", 1), hostLabels)
+	suite.NoError(err)
+	suite.Equal(EvaluateResultNotApplicable, actual)
+}
+
+// TestWithoutTopologyLimits tests removing the limits of the topology
+// constraints nested in a constraint specification
+func (suite *EvaluatorTestSuite) TestWithoutTopologyLimits() {
+	label := &task.Constraint{
+		Type: task.Constraint_LABEL_CONSTRAINT,
+		LabelConstraint: &task.LabelConstraint{
+			Kind: task.LabelConstraint_HOST,
+			Label: &peloton.Label{
+				Key:   "zone",
+				Value: "us-west-1",
+			},
+			Condition:   task.LabelConstraint_CONDITION_EQUAL,
+			Requirement: 1,
+		},
+	}
+	topology := &task.Constraint{
+		Type: task.Constraint_TOPOLOGY_CONSTRAINT,
+		TopologyConstraint: &task.TopologyConstraint{
+			TopologyKey: "rack",
+			MaxSkew:     1,
+		},
+	}
+	constraint := &task.Constraint{
+		Type: task.Constraint_AND_CONSTRAINT,
+		AndConstraint: &task.AndConstraint{
+			Constraints: []*task.Constraint{label, topology},
+		},
+	}
+
+	suite.False(HasTopologyLimits(nil))
+	suite.False(HasTopologyLimits(label))
+	suite.True(HasTopologyLimits(constraint))
+	suite.True(WithoutTopologyLimits(label) == label)
+	suite.Empty(GetTopologyLimits(label))
+	suite.Equal(
+		[]*task.TopologyConstraint{topology.GetTopologyConstraint()},
+		GetTopologyLimits(constraint))
+
+	result := WithoutTopologyLimits(constraint)
+	suite.False(HasTopologyLimits(result))
+	suite.Len(result.GetAndConstraint().GetConstraints(), 2)
+	suite.Equal(label, result.GetAndConstraint().GetConstraints()[0])
+	suite.Equal("rack", result.GetAndConstraint().GetConstraints()[1].
+		GetTopologyConstraint().GetTopologyKey())
+
+	// the original constraint is not modified
+	suite.Equal(uint32(1), topology.GetTopologyConstraint().GetMaxSkew())
+}
+
+// TestRelax tests relaxing a constraint specification to the requirements
+// on the host itself
+func (suite *EvaluatorTestSuite) TestRelax() {
+	hostLabel := &task.Constraint{
+		Type: task.Constraint_LABEL_CONSTRAINT,
+		LabelConstraint: &task.LabelConstraint{
+			Kind: task.LabelConstraint_HOST,
+			Label: &peloton.Label{
+				Key:   "zone",
+				Value: "us-west-1",
+			},
+			Condition:   task.LabelConstraint_CONDITION_EQUAL,
+			Requirement: 1,
+		},
+	}
+	taskLabel := &task.Constraint{
+		Type: task.Constraint_LABEL_CONSTRAINT,
+		LabelConstraint: &task.LabelConstraint{
+			Kind: task.LabelConstraint_TASK,
+			Label: &peloton.Label{
+				Key:   "job",
+				Value: "cassandra",
+			},
+			Condition:   task.LabelConstraint_CONDITION_LESS_THAN,
+			Requirement: 1,
+		},
+	}
+	topology := &task.Constraint{
+		Type: task.Constraint_TOPOLOGY_CONSTRAINT,
+		TopologyConstraint: &task.TopologyConstraint{
+			TopologyKey: "rack",
+			MaxSkew:     1,
+		},
+	}
+
+	suite.Nil(Relax(nil))
+	suite.Nil(Relax(taskLabel))
+	suite.True(Relax(hostLabel) == hostLabel)
+
+	result := Relax(&task.Constraint{
+		Type: task.Constraint_AND_CONSTRAINT,
+		AndConstraint: &task.AndConstraint{
+			Constraints: []*task.Constraint{hostLabel, taskLabel, topology},
+		},
+	})
+	suite.Len(result.GetAndConstraint().GetConstraints(), 2)
+	suite.Equal(hostLabel, result.GetAndConstraint().GetConstraints()[0])
+	suite.False(HasTopologyLimits(result))
+	suite.Equal("rack", result.GetAndConstraint().GetConstraints()[1].
+		GetTopologyConstraint().GetTopologyKey())
+
+	// an alternative which is removed is always satisfied
+	suite.Nil(Relax(&task.Constraint{
+		Type: task.Constraint_OR_CONSTRAINT,
+		OrConstraint: &task.OrConstraint{
+			Constraints: []*task.Constraint{hostLabel, taskLabel},
+		},
+	}))
+
+	// the original constraint is not modified
+	suite.Equal(uint32(1), topology.GetTopologyConstraint().GetMaxSkew())
+}
+
+// TestPreferenceConstraint tests that preference constraints never exclude
+// a host
+func (suite *EvaluatorTestSuite) TestPreferenceConstraint() {
+	hostLabels := LabelValues(map[string]map[string]uint32{
+		HostNameKey: {_testHost1: 1},
+	})
+	preference := &task.Constraint{
+		Type: task.Constraint_PREFERENCE_CONSTRAINT,
+		PreferenceConstraint: &task.PreferenceConstraint{
+			Preferences: []*task.PlacementPreference{
+				{
+					Kind:   task.PlacementPreference_HOST_LABEL,
+					Weight: 1,
+					Label: &peloton.Label{
+						Key:   "ssd",
+						Value: "true",
+					},
+				},
+			},
+		},
+	}
+
+	for _, kind := range []task.LabelConstraint_Kind{
+		task.LabelConstraint_HOST, task.LabelConstraint_TASK} {
+		actual, err := NewEvaluator(kind).Evaluate(preference, hostLabels)
+		suite.NoError(err)
+		suite.Equal(EvaluateResultNotApplicable, actual)
+	}
+
+	// a preference within an and constraint does not affect the result
+	actual, err := NewEvaluator(task.LabelConstraint_HOST).Evaluate(
+		&task.Constraint{
+			Type: task.Constraint_AND_CONSTRAINT,
+			AndConstraint: &task.AndConstraint{
+				Constraints: []*task.Constraint{
+					preference,
+					{
+						Type: task.Constraint_LABEL_CONSTRAINT,
+						LabelConstraint: &task.LabelConstraint{
+							Kind:        task.LabelConstraint_HOST,
+							Condition:   task.LabelConstraint_CONDITION_EQUAL,
+							Label:       &peloton.Label{Key: HostNameKey, Value: _testHost1},
+							Requirement: 1,
+						},
+					},
+				},
+			},
+		}, hostLabels)
+	suite.NoError(err)
+	suite.Equal(EvaluateResultMatch, actual)
+}
+
 // TestIsNonExclusiveConstraint tests the function IsNonExclusiveConstraint
 func (suite *EvaluatorTestSuite) TestIsNonExclusiveConstraint() {
 	labelExcl := &task.Constraint{
@@ -63,8 +63,11 @@ OUTER:
 			for _, value := range attr.GetSet().GetItem() {
 				values = append(values, value)
 			}
+		case mesos.Value_RANGES:
+			for _, r := range attr.GetRanges().GetRange() {
+				values = append(values, FormatRange(r.GetBegin(), r.GetEnd()))
+			}
 		default:
-			// TODO: Add support for range attributes.
 			log.WithFields(log.Fields{
 				"key":  key,
 				"type": attr.GetType(),
@@ -94,8 +94,8 @@ func (suite *LabelValuesTestSuite) TestGetHostLabelValues() {
 	hostname := "test-host"
 
 	res := GetHostLabelValues(hostname, attributes)
-	// hostname, text, set and scalar.
-	suite.Equal(4, len(res), "result: ", res)
+	// hostname, range, text, set and scalar.
+	suite.Equal(5, len(res), "result: ", res)
 	suite.Equal(map[string]uint32{hostname: 1}, res[HostNameKey])
 	suite.Equal(
 		map[string]uint32{
@@ -105,6 +105,7 @@ func (suite *LabelValuesTestSuite) TestGetHostLabelValues() {
 		res[setName])
 	suite.Equal(map[string]uint32{tv: 1}, res[textName])
 	suite.Equal(map[string]uint32{"1.000000": 1}, res[scalarName])
+	suite.Equal(map[string]uint32{"[100-200]": 1}, res[rangeName])
 }
 
 func TestLabelValuesTestSuite(t *testing.T) {
@@ -0,0 +1,153 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gpu parses the GPU device topology of hosts from their attributes
+// and matches it against the GPU requirements of tasks.
+package gpu
+
+import (
+	"strconv"
+	"strings"
+
+	mesos "github.com/uber/peloton/.gen/mesos/v1"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+)
+
+const (
+	// ModelAttribute is the text attribute of a host with its GPU model.
+	ModelAttribute = "gpu.model"
+	// MemoryAttribute is the scalar attribute of a host with the memory of
+	// each of its GPU devices in MB.
+	MemoryAttribute = "gpu.memory_mb"
+	// NUMAAttributePrefix is the prefix of the scalar attributes of a host
+	// with the number of GPU devices attached to a NUMA node, e.g.
+	// gpu.numa.0 is the number of devices on NUMA node 0.
+	NUMAAttributePrefix = "gpu.numa."
+
+	// _modelKindPrefix is the prefix of the resource kinds used to account
+	// GPUs of a specific model, e.g. gpu.tesla-v100.
+	_modelKindPrefix = "gpu."
+)
+
+// ModelKind returns the resource kind used to account GPUs of the model.
+func ModelKind(model string) string {
+	return _modelKindPrefix + model
+}
+
+// ModelFromKind returns the GPU model of a resource kind created by
+// ModelKind and whether the kind is a GPU model kind.
+func ModelFromKind(kind string) (string, bool) {
+	if !strings.HasPrefix(kind, _modelKindPrefix) ||
+		len(kind) == len(_modelKindPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(kind, _modelKindPrefix), true
+}
+
+// Topology is the GPU device topology of a host.
+type Topology struct {
+	// Model is the model of the GPU devices of the host.
+	Model string
+	// MemoryMb is the memory per GPU device in MB.
+	MemoryMb float64
+	// NUMANodes is the number of GPU devices per NUMA node.
+	NUMANodes map[string]float64
+	// Count is the total number of GPU devices of the host.
+	Count float64
+}
+
+// NewTopology creates the topology of a host with the given total number of
+// GPU devices.
+func NewTopology(count float64) *Topology {
+	return &Topology{
+		NUMANodes: map[string]float64{},
+		Count:     count,
+	}
+}
+
+// FromAttributes parses the topology of a host from its attributes and the
+// total number of GPU devices of the host.
+func FromAttributes(attributes []*mesos.Attribute, count float64) *Topology {
+	topology := NewTopology(count)
+	for _, attribute := range attributes {
+		switch attribute.GetType() {
+		case mesos.Value_TEXT:
+			topology.SetAttribute(
+				attribute.GetName(), attribute.GetText().GetValue())
+		case mesos.Value_SCALAR:
+			topology.SetAttribute(
+				attribute.GetName(),
+				strconv.FormatFloat(attribute.GetScalar().GetValue(), 'f', -1, 64))
+		}
+	}
+	return topology
+}
+
+// SetAttribute updates the topology from a host attribute, attributes which
+// are not GPU attributes are ignored.
+func (t *Topology) SetAttribute(name, value string) {
+	switch {
+	case name == ModelAttribute:
+		t.Model = value
+	case name == MemoryAttribute:
+		if memory, err := strconv.ParseFloat(value, 64); err == nil {
+			t.MemoryMb = memory
+		}
+	case strings.HasPrefix(name, NUMAAttributePrefix):
+		if devices, err := strconv.ParseFloat(value, 64); err == nil {
+			t.NUMANodes[strings.TrimPrefix(name, NUMAAttributePrefix)] = devices
+		}
+	}
+}
+
+// Match returns true if the requested number of GPUs with the given config
+// can be placed on the host when free of its GPUs are unused.
+// The devices in use are not known per NUMA node, so for sameNumaNode we
+// conservatively assume they are all on the node being checked.
+func (t *Topology) Match(config *task.GPUConfig, gpus, free float64) bool {
+	if config == nil || gpus <= 0 {
+		return true
+	}
+	if t.Count <= 0 || free < gpus {
+		return false
+	}
+	if len(config.GetModels()) > 0 && !contains(config.GetModels(), t.Model) {
+		return false
+	}
+	if t.MemoryMb < config.GetMinMemoryMb() {
+		return false
+	}
+	if config.GetWholeHost() && (t.Count != gpus || free < t.Count) {
+		return false
+	}
+	if config.GetSameNumaNode() {
+		used := t.Count - free
+		for _, devices := range t.NUMANodes {
+			if devices-used >= gpus {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gpu
+
+import (
+	"testing"
+
+	mesos "github.com/uber/peloton/.gen/mesos/v1"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func textAttribute(name, value string) *mesos.Attribute {
+	return &mesos.Attribute{
+		Name: proto.String(name),
+		Type: mesos.Value_TEXT.Enum(),
+		Text: &mesos.Value_Text{Value: proto.String(value)},
+	}
+}
+
+func scalarAttribute(name string, value float64) *mesos.Attribute {
+	return &mesos.Attribute{
+		Name:   proto.String(name),
+		Type:   mesos.Value_SCALAR.Enum(),
+		Scalar: &mesos.Value_Scalar{Value: proto.Float64(value)},
+	}
+}
+
+func testTopology() *Topology {
+	return FromAttributes([]*mesos.Attribute{
+		textAttribute(ModelAttribute, "tesla-v100"),
+		scalarAttribute(MemoryAttribute, 16000),
+		scalarAttribute(NUMAAttributePrefix+"0", 4),
+		scalarAttribute(NUMAAttributePrefix+"1", 4),
+		textAttribute("rack", "rack1"),
+	}, 8)
+}
+
+func TestModelKind(t *testing.T) {
+	kind := ModelKind("tesla-v100")
+	assert.Equal(t, "gpu.tesla-v100", kind)
+
+	model, ok := ModelFromKind(kind)
+	assert.True(t, ok)
+	assert.Equal(t, "tesla-v100", model)
+
+	_, ok = ModelFromKind("gpu")
+	assert.False(t, ok)
+	_, ok = ModelFromKind("gpu.")
+	assert.False(t, ok)
+}
+
+func TestFromAttributes(t *testing.T) {
+	topology := testTopology()
+	assert.Equal(t, "tesla-v100", topology.Model)
+	assert.Equal(t, 16000.0, topology.MemoryMb)
+	assert.Equal(t, map[string]float64{"0": 4, "1": 4}, topology.NUMANodes)
+	assert.Equal(t, 8.0, topology.Count)
+}
+
+func TestMatch(t *testing.T) {
+	topology := testTopology()
+
+	testCases := []struct {
+		name   string
+		config *task.GPUConfig
+		gpus   float64
+		free   float64
+		match  bool
+	}{
+		{"no config", nil, 2, 0, true},
+		{"not enough free", &task.GPUConfig{}, 2, 1, false},
+		{"model match", &task.GPUConfig{Models: []string{"p100", "tesla-v100"}}, 2, 8, true},
+		{"model mismatch", &task.GPUConfig{Models: []string{"p100"}}, 2, 8, false},
+		{"memory match", &task.GPUConfig{MinMemoryMb: 16000}, 2, 8, true},
+		{"memory mismatch", &task.GPUConfig{MinMemoryMb: 32000}, 2, 8, false},
+		{"whole host", &task.GPUConfig{WholeHost: true}, 8, 8, true},
+		{"whole host partially used", &task.GPUConfig{WholeHost: true}, 7, 7, false},
+		{"whole host fewer gpus", &task.GPUConfig{WholeHost: true}, 4, 8, false},
+		{"same numa node", &task.GPUConfig{SameNumaNode: true}, 4, 8, true},
+		{"same numa node used", &task.GPUConfig{SameNumaNode: true}, 4, 6, false},
+		{"same numa node too many", &task.GPUConfig{SameNumaNode: true}, 5, 8, false},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.match, topology.Match(tc.config, tc.gpus, tc.free), tc.name)
+	}
+
+	noNUMA := NewTopology(4)
+	assert.False(t, noNUMA.Match(&task.GPUConfig{SameNumaNode: true}, 1, 4))
+	assert.False(t, NewTopology(0).Match(&task.GPUConfig{}, 1, 0))
+}
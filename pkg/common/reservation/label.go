@@ -27,6 +27,7 @@ var (
 	_jobKey      = "job"
 	_instanceKey = "instance"
 	_hostnameKey = "hostname"
+	_explicitKey = "explicit"
 )
 
 // CreateReservationLabels creates reservation labels for stateful task.
@@ -68,3 +69,32 @@ func ParseReservationLabels(labels *mesos.Labels) (string, uint32, error) {
 	}
 	return jobID, uint32(instanceID), nil
 }
+
+// CreateExplicitReservationLabels returns a copy of the given reservation
+// labels, marking the reservation as explicitly managed through the host
+// manager reservation API. Such reservations are not recycled by the
+// reservation cleaner when they are not used.
+func CreateExplicitReservationLabels(labels *mesos.Labels) *mesos.Labels {
+	result := &mesos.Labels{}
+	for _, label := range labels.GetLabels() {
+		result.Labels = append(result.Labels, label)
+	}
+	if !IsExplicitReservation(labels) {
+		result.Labels = append(result.Labels, &mesos.Label{
+			Key:   &_explicitKey,
+			Value: util.PtrPrintf("true"),
+		})
+	}
+	return result
+}
+
+// IsExplicitReservation returns true if the reservation labels belong to an
+// explicitly managed reservation.
+func IsExplicitReservation(labels *mesos.Labels) bool {
+	for _, label := range labels.GetLabels() {
+		if label.GetKey() == _explicitKey {
+			return true
+		}
+	}
+	return false
+}
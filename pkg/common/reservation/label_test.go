@@ -65,3 +65,24 @@ func (suite *LabelTestSuite) TestParseReservationLabelWithInstanceIDMissingError
 	_, _, err := ParseReservationLabels(reservationLabels)
 	suite.Error(err)
 }
+
+func (suite *LabelTestSuite) TestCreateExplicitReservationLabels() {
+	reservationLabels := CreateReservationLabels(_testJob, _testInstance, _testHostname)
+	suite.False(IsExplicitReservation(reservationLabels))
+
+	explicitLabels := CreateExplicitReservationLabels(reservationLabels)
+	suite.True(IsExplicitReservation(explicitLabels))
+	suite.Len(explicitLabels.GetLabels(), 4)
+	// the original labels are not modified
+	suite.Len(reservationLabels.GetLabels(), 3)
+
+	// the labels of an explicit reservation are returned as is
+	suite.Equal(
+		explicitLabels.String(),
+		CreateExplicitReservationLabels(explicitLabels).String())
+
+	jobID, instanceID, err := ParseReservationLabels(explicitLabels)
+	suite.NoError(err)
+	suite.Equal(_testJob, jobID)
+	suite.Equal(uint32(_testInstance), instanceID)
+}
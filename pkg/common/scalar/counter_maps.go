@@ -23,10 +23,12 @@ type CounterMaps map[resourceKey]tally.Counter
 // NewCounterMaps returns the CounterMaps initialized at given tally scope.
 func NewCounterMaps(scope tally.Scope) CounterMaps {
 	return CounterMaps{
-		cpu:  scope.Counter("cpu"),
-		mem:  scope.Counter("mem"),
-		disk: scope.Counter("disk"),
-		gpu:  scope.Counter("gpu"),
+		cpu:     scope.Counter("cpu"),
+		mem:     scope.Counter("mem"),
+		disk:    scope.Counter("disk"),
+		gpu:     scope.Counter("gpu"),
+		ports:   scope.Counter("ports"),
+		network: scope.Counter("network"),
 	}
 }
 
@@ -36,4 +38,6 @@ func (g CounterMaps) Inc(resources Resources) {
 	g[mem].Inc(int64(resources.GetMem()))
 	g[disk].Inc(int64(resources.GetDisk()))
 	g[gpu].Inc(int64(resources.GetGPU()))
+	g[ports].Inc(int64(resources.GetPorts()))
+	g[network].Inc(int64(resources.GetNetwork()))
 }
@@ -23,6 +23,8 @@ const (
 	mem
 	disk
 	gpu
+	ports
+	network
 )
 
 // GaugeMaps wraps around a group of metrics which can be used for reporting
@@ -32,10 +34,12 @@ type GaugeMaps map[resourceKey]tally.Gauge
 // NewGaugeMaps returns the GaugeMaps initialized at given tally scope.
 func NewGaugeMaps(scope tally.Scope) GaugeMaps {
 	return GaugeMaps{
-		cpu:  scope.Gauge("cpu"),
-		mem:  scope.Gauge("mem"),
-		disk: scope.Gauge("disk"),
-		gpu:  scope.Gauge("gpu"),
+		cpu:     scope.Gauge("cpu"),
+		mem:     scope.Gauge("mem"),
+		disk:    scope.Gauge("disk"),
+		gpu:     scope.Gauge("gpu"),
+		ports:   scope.Gauge("ports"),
+		network: scope.Gauge("network"),
 	}
 }
 
@@ -45,4 +49,6 @@ func (g GaugeMaps) Update(resources Resources) {
 	g[mem].Update(resources.GetMem())
 	g[disk].Update(resources.GetDisk())
 	g[gpu].Update(resources.GetGPU())
+	g[ports].Update(resources.GetPorts())
+	g[network].Update(resources.GetNetwork())
 }
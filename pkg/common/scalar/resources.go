@@ -14,10 +14,13 @@
 
 package scalar
 
-// Resources represents a scalar resource having CPU, Memory, Disk and GPU
+// Resources represents a scalar resource having CPU, Memory, Disk, GPU,
+// Ports and Network bandwidth
 type Resources interface {
 	GetCPU() float64
 	GetMem() float64
 	GetDisk() float64
 	GetGPU() float64
+	GetPorts() float64
+	GetNetwork() float64
 }
@@ -45,7 +45,8 @@ func Merge(base *task.TaskConfig, override *task.TaskConfig) *task.TaskConfig {
 }
 
 // MergePodSpec returns the merged v1 pod spec between a base and an override. The
-// merge will only happen of top-level fields, not recursively.
+// merge will only happen of top-level fields, not recursively, so the
+// containers of an override replace the base containers as a whole.
 // If any of the arguments is nil, no merge will happen, and the non-nil
 // argument (if exists) is returned.
 func MergePodSpec(base *pod.PodSpec, override *pod.PodSpec) *pod.PodSpec {
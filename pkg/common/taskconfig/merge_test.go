@@ -79,6 +79,36 @@ func TestMergeInstanceOverride(t *testing.T) {
 		instanceConfig).Controller)
 }
 
+// TestMergeInstanceOverrideZeroResource tests that the resource of an
+// instance config replaces the default one as a whole, including the limits
+// which are set to 0.
+func TestMergeInstanceOverrideZeroResource(t *testing.T) {
+	defaultConfig := &task.TaskConfig{
+		Resource: &task.ResourceConfig{
+			CpuLimit:    0.8,
+			MemLimitMb:  800,
+			DiskLimitMb: 1500,
+			GpuLimit:    1,
+		},
+		Command: &mesos_v1.CommandInfo{
+			Value: util.PtrPrintf("echo Hello"),
+		},
+	}
+	instanceConfig := &task.TaskConfig{
+		Resource: &task.ResourceConfig{
+			CpuLimit:    0.8,
+			MemLimitMb:  1600,
+			DiskLimitMb: 1500,
+			GpuLimit:    0,
+		},
+	}
+
+	merged := Merge(defaultConfig, instanceConfig)
+	assert.Equal(t, instanceConfig.GetResource(), merged.GetResource())
+	assert.Equal(t, float64(0), merged.GetResource().GetGpuLimit())
+	assert.Equal(t, defaultConfig.GetCommand(), merged.GetCommand())
+}
+
 // TestMergeInstanceOverrideGracePeriod tests if the merged
 // task config reflects the expected killgraceperiodseconds
 func TestMergeInstanceOverrideGracePeriod(t *testing.T) {
@@ -263,3 +293,51 @@ func TestMergeSpecOverrideGracePeriod(t *testing.T) {
 		&pod.PodSpec{KillGracePeriodSeconds: uint32(0)})
 	assert.Equal(t, uint32(20), cfg.GetKillGracePeriodSeconds())
 }
+
+// TestMergeSpecOverrideContainer checks MergePodSpec replaces the
+// containers of the default spec with the ones of the instance spec as a
+// whole, as for the existing jobs.
+func TestMergeSpecOverrideContainer(t *testing.T) {
+	defaultSpec := &pod.PodSpec{
+		Containers: []*pod.ContainerSpec{
+			{
+				Name: "container",
+				Resource: &pod.ResourceSpec{
+					CpuLimit:   0.8,
+					MemLimitMb: 800,
+				},
+				Command: &mesos_v1.CommandInfo{
+					Value: util.PtrPrintf("echo Hello"),
+				},
+			},
+		},
+	}
+	instanceSpec := &pod.PodSpec{
+		Containers: []*pod.ContainerSpec{
+			{
+				Resource: &pod.ResourceSpec{
+					MemLimitMb: 1600,
+				},
+				Command: &mesos_v1.CommandInfo{
+					Value: util.PtrPrintf("echo Leader"),
+				},
+			},
+		},
+	}
+
+	merged := MergePodSpec(defaultSpec, instanceSpec)
+	assert.Equal(t, instanceSpec.GetContainers(), merged.GetContainers())
+	// the fields of the default container which are not set in the
+	// instance container are not kept
+	assert.Empty(t, merged.GetContainers()[0].GetName())
+	assert.Equal(t,
+		float64(0),
+		merged.GetContainers()[0].GetResource().GetCpuLimit())
+
+	// the default containers are kept without instance containers
+	merged = MergePodSpec(defaultSpec, &pod.PodSpec{
+		KillGracePeriodSeconds: uint32(20),
+	})
+	assert.Equal(t, defaultSpec.GetContainers(), merged.GetContainers())
+	assert.Equal(t, uint32(20), merged.GetKillGracePeriodSeconds())
+}
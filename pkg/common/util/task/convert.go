@@ -23,6 +23,7 @@ import (
 	"github.com/uber/peloton/.gen/peloton/private/resmgr"
 	"github.com/uber/peloton/.gen/peloton/private/resmgrsvc"
 
+	"github.com/uber/peloton/pkg/common/constraints"
 	"github.com/uber/peloton/pkg/common/util"
 	jobmgrcommon "github.com/uber/peloton/pkg/jobmgr/common"
 )
@@ -105,6 +106,12 @@ func ConvertTaskToResMgrTask(
 		preemptible = slaConfig.GetPreemptible()
 	}
 
+	constraint := taskInfo.GetConfig().GetConstraint()
+	if taskInfo.GetRuntime().GetReason() == jobmgrcommon.ReasonRelaxedConstraint {
+		// the task is requeued after exceeding a state timeout
+		constraint = constraints.Relax(constraint)
+	}
+
 	resmgrTask := &resmgr.Task{
 		Id:           taskID,
 		JobId:        taskInfo.GetJobId(),
@@ -114,13 +121,15 @@ func ConvertTaskToResMgrTask(
 		Priority:     slaConfig.GetPriority(),
 		MinInstances: minInstances,
 		Resource:     taskInfo.GetConfig().GetResource(),
-		Constraint:   taskInfo.GetConfig().GetConstraint(),
+		Constraint:   constraint,
 		NumPorts:     uint32(numPorts),
 		Type:         getTaskType(taskInfo.GetConfig(), jobConfig.GetType()),
 		Labels:       util.ConvertLabels(taskInfo.GetConfig().GetLabels()),
 		Controller:   taskInfo.GetConfig().GetController(),
 		Revocable:    taskInfo.GetConfig().GetRevocable(),
 		DesiredHost:  taskInfo.GetRuntime().GetDesiredHost(),
+
+		MaxUnavailableInstances: slaConfig.GetMaximumUnavailableInstances(),
 	}
 
 	taskState := taskInfo.GetRuntime().GetState()
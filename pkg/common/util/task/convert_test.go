@@ -24,6 +24,8 @@ import (
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
 	"github.com/uber/peloton/.gen/peloton/api/v0/task"
 	"github.com/uber/peloton/.gen/peloton/private/resmgr"
+
+	jobmgrcommon "github.com/uber/peloton/pkg/jobmgr/common"
 )
 
 func TestGetTaskType(t *testing.T) {
@@ -125,11 +127,14 @@ func TestConvertTaskToResMgrTask(t *testing.T) {
 	}
 
 	jobConfig := &job.JobConfig{
-		SLA: &job.SlaConfig{},
+		SLA: &job.SlaConfig{
+			MaximumUnavailableInstances: 2,
+		},
 	}
 	for _, taskInfo := range taskInfos {
 		rmTask := ConvertTaskToResMgrTask(taskInfo, jobConfig)
 		assert.Equal(t, taskInfo.JobId.Value, rmTask.JobId.Value)
+		assert.Equal(t, uint32(2), rmTask.GetMaxUnavailableInstances())
 		assert.Equal(t, uint32(len(taskInfo.Config.Ports)), rmTask.NumPorts)
 		taskState := taskInfo.Runtime.GetState()
 		if taskState == task.TaskState_LAUNCHED ||
@@ -266,3 +271,29 @@ func TestConvertTaskToResMgrTaskPreemptible(t *testing.T) {
 		assert.Equal(t, test.preemptible, r.Preemptible, test.name)
 	}
 }
+
+// TestConvertTaskToResMgrTaskRelaxedConstraint tests that the constraint
+// of a task requeued after exceeding a state timeout is relaxed
+func TestConvertTaskToResMgrTaskRelaxedConstraint(t *testing.T) {
+	constraint := &task.Constraint{
+		Type: task.Constraint_LABEL_CONSTRAINT,
+		LabelConstraint: &task.LabelConstraint{
+			Kind:        task.LabelConstraint_TASK,
+			Condition:   task.LabelConstraint_CONDITION_LESS_THAN,
+			Label:       &peloton.Label{Key: "job", Value: "cassandra"},
+			Requirement: 1,
+		},
+	}
+	taskInfo := &task.TaskInfo{
+		JobId:   &peloton.JobID{Value: uuid.New()},
+		Config:  &task.TaskConfig{Constraint: constraint},
+		Runtime: &task.RuntimeInfo{State: task.TaskState_INITIALIZED},
+	}
+
+	rmTask := ConvertTaskToResMgrTask(taskInfo, &job.JobConfig{})
+	assert.Equal(t, constraint, rmTask.GetConstraint())
+
+	taskInfo.Runtime.Reason = jobmgrcommon.ReasonRelaxedConstraint
+	rmTask = ConvertTaskToResMgrTask(taskInfo, &job.JobConfig{})
+	assert.Nil(t, rmTask.GetConstraint())
+}
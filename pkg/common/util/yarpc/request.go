@@ -0,0 +1,124 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yarpc
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/yarpc/api/transport"
+)
+
+const (
+	_procedureSeparator = "::"
+	_requestSuffix      = "Request"
+
+	// JSONEncoding is the encoding of the requests sent with json
+	JSONEncoding = transport.Encoding("json")
+	// ProtoEncoding is the encoding of the requests sent with protobuf
+	ProtoEncoding = transport.Encoding("proto")
+)
+
+// _requestTypes caches the request message type of each procedure.
+var _requestTypes sync.Map
+
+// DecodeRequest decodes the raw request body of a procedure into its
+// request message, so that inbound middlewares can inspect the request.
+// It returns nil if the request message of the procedure is unknown, or
+// the body cannot be decoded.
+func DecodeRequest(
+	encoding transport.Encoding,
+	procedure string,
+	body []byte,
+) proto.Message {
+	msg := newRequestMessage(procedure)
+	if msg == nil {
+		return nil
+	}
+
+	var err error
+	switch encoding {
+	case ProtoEncoding:
+		err = proto.Unmarshal(body, msg)
+	case JSONEncoding:
+		unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+		err = unmarshaler.Unmarshal(bytes.NewReader(body), msg)
+	default:
+		return nil
+	}
+	if err != nil {
+		return nil
+	}
+	return msg
+}
+
+// newRequestMessage returns an empty request message of a procedure.
+// Peloton procedures are named <package>.<Service>::<Method>, and their
+// request message is usually <package>.<Method>Request. Some of the older
+// APIs drop the trailing words of the method name, e.g. the request of
+// ResourceManager::CreateResourcePool is CreateRequest, so shorter method
+// name prefixes are tried as well.
+func newRequestMessage(procedure string) proto.Message {
+	var t reflect.Type
+	if cached, ok := _requestTypes.Load(procedure); ok {
+		t, _ = cached.(reflect.Type)
+	} else {
+		t = lookupRequestType(procedure)
+		_requestTypes.Store(procedure, t)
+	}
+	if t == nil {
+		return nil
+	}
+
+	msg, ok := reflect.New(t.Elem()).Interface().(proto.Message)
+	if !ok {
+		return nil
+	}
+	return msg
+}
+
+// lookupRequestType looks up the request message type of a procedure
+// in the proto registry.
+func lookupRequestType(procedure string) reflect.Type {
+	parts := strings.SplitN(procedure, _procedureSeparator, 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	idx := strings.LastIndex(parts[0], ".")
+	if idx < 0 {
+		return nil
+	}
+	pkg := parts[0][:idx+1]
+
+	method := parts[1]
+	for len(method) != 0 {
+		t := proto.MessageType(pkg + method + _requestSuffix)
+		if t != nil && t.Kind() == reflect.Ptr {
+			return t
+		}
+		// drop the last word of the camel cased method name
+		end := strings.LastIndexFunc(method, unicode.IsUpper)
+		if end <= 0 {
+			break
+		}
+		method = method[:end]
+	}
+	return nil
+}
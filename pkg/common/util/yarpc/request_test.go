@@ -0,0 +1,68 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yarpc
+
+import (
+	"testing"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/respool"
+	statelesssvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless/svc"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeRequest(t *testing.T) {
+	req := &statelesssvc.StopJobRequest{
+		JobId: &v1alphapeloton.JobID{Value: "job1"},
+	}
+	body, err := proto.Marshal(req)
+	assert.NoError(t, err)
+
+	msg := DecodeRequest(
+		ProtoEncoding,
+		"peloton.api.v1alpha.job.stateless.svc.JobService::StopJob",
+		body,
+	)
+	assert.True(t, proto.Equal(req, msg))
+
+	// the request message of an older API drops the trailing words
+	// of the method name
+	msg = DecodeRequest(
+		ProtoEncoding,
+		"peloton.api.v0.respool.ResourceManager::DeleteResourcePool",
+		nil,
+	)
+	_, ok := msg.(*respool.DeleteRequest)
+	assert.True(t, ok)
+}
+
+func TestDecodeRequestFailure(t *testing.T) {
+	// unknown procedure
+	assert.Nil(t, DecodeRequest(ProtoEncoding, "Scheduler::Update", nil))
+	// invalid body
+	assert.Nil(t, DecodeRequest(
+		ProtoEncoding,
+		"peloton.api.v1alpha.job.stateless.svc.JobService::StopJob",
+		[]byte("invalid"),
+	))
+	// unknown encoding
+	assert.Nil(t, DecodeRequest(
+		"thrift",
+		"peloton.api.v1alpha.job.stateless.svc.JobService::StopJob",
+		nil,
+	))
+}
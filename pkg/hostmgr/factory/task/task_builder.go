@@ -646,10 +646,11 @@ func (tb *Builder) extractScalarResources(
 			continue
 		}
 		rs := util.CreateMesosScalarResources(map[string]float64{
-			common.MesosCPU:  minimum.CPU,
-			common.MesosMem:  minimum.Mem,
-			common.MesosDisk: minimum.Disk,
-			common.MesosGPU:  minimum.GPU,
+			common.MesosCPU:              minimum.CPU,
+			common.MesosMem:              minimum.Mem,
+			common.MesosDisk:             minimum.Disk,
+			common.MesosGPU:              minimum.GPU,
+			common.MesosNetworkBandwidth: minimum.Network,
 		}, role)
 
 		launchResources = append(launchResources, rs...)
@@ -280,7 +280,7 @@ func (suite *BuilderTestSuite) TestPortTasks() {
 		suite.Equal(tid[i], info.GetTaskId())
 		sc := scalar.FromMesosResources(info.GetResources())
 		suite.Equal(
-			scalar.Resources{CPU: _cpu, Mem: _mem, Disk: _disk},
+			scalar.Resources{CPU: _cpu, Mem: _mem, Disk: _disk, Ports: 3},
 			sc)
 		discoveryInfo := info.GetDiscovery()
 		suite.NotNil(discoveryInfo)
@@ -17,6 +17,7 @@ package hostmgr
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -31,6 +32,7 @@ import (
 
 	"github.com/uber/peloton/pkg/common"
 	"github.com/uber/peloton/pkg/common/constraints"
+	"github.com/uber/peloton/pkg/common/gpu"
 	"github.com/uber/peloton/pkg/common/queue"
 	"github.com/uber/peloton/pkg/common/reservation"
 	"github.com/uber/peloton/pkg/common/stringset"
@@ -46,6 +48,7 @@ import (
 	"github.com/uber/peloton/pkg/hostmgr/offer"
 	"github.com/uber/peloton/pkg/hostmgr/offer/offerpool"
 	mqueue "github.com/uber/peloton/pkg/hostmgr/queue"
+	hmreservation "github.com/uber/peloton/pkg/hostmgr/reservation"
 	"github.com/uber/peloton/pkg/hostmgr/reserver"
 	"github.com/uber/peloton/pkg/hostmgr/scalar"
 	"github.com/uber/peloton/pkg/hostmgr/summary"
@@ -53,6 +56,7 @@ import (
 	hmutil "github.com/uber/peloton/pkg/hostmgr/util"
 	"github.com/uber/peloton/pkg/storage"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/uber-go/tally"
@@ -82,6 +86,11 @@ var (
 	errOfferOperationNotSupported        = errors.New("offer operation not supported")
 	errInvalidOfferOperation             = errors.New("invalid offer operation")
 	errReservationNotFound               = errors.New("reservation could not be made")
+	errEmptyResources                    = errors.New("empty resources")
+	errEmptyReservationLabels            = errors.New("empty reservation labels")
+	errEmptyVolumes                      = errors.New("empty volumes")
+	errInvalidVolume                     = errors.New("volume must have persistence id and container path")
+	errReservationHasVolumes             = errors.New("reservation has volumes which need to be destroyed first")
 )
 
 // ServiceHandler implements peloton.private.hostmgr.InternalHostService.
@@ -90,6 +99,7 @@ type ServiceHandler struct {
 	operatorMasterClient   mpb.MasterOperatorClient
 	metrics                *metrics.Metrics
 	offerPool              offerpool.Pool
+	offerEventHandler      offer.EventHandler
 	frameworkInfoProvider  hostmgr_mesos.FrameworkInfoProvider
 	volumeStore            storage.PersistentVolumeStore
 	roleName               string
@@ -124,6 +134,7 @@ func NewServiceHandler(
 		operatorMasterClient:   masterOperatorClient,
 		metrics:                metrics.NewMetrics(parent),
 		offerPool:              offer.GetEventHandler().GetOfferPool(),
+		offerEventHandler:      offer.GetEventHandler(),
 		frameworkInfoProvider:  frameworkInfoProvider,
 		volumeStore:            volumeStore,
 		roleName:               mesosConfig.Framework.Role,
@@ -292,7 +303,12 @@ func (h *ServiceHandler) AcquireHostOffers(
 		}, errors.Wrap(err, "invalid filter")
 	}
 
-	result, resultCount, err := h.offerPool.ClaimForPlace(body.GetFilter())
+	claimForPlace := h.offerPool.ClaimForPlace
+	if body.GetDryRun() {
+		claimForPlace = h.offerPool.PeekForPlace
+	}
+
+	result, resultCount, err := claimForPlace(body.GetFilter())
 	if err != nil {
 		h.metrics.AcquireHostOffersFail.Inc(1)
 		log.WithField("filter", body.GetFilter()).
@@ -343,6 +359,7 @@ func (h *ServiceHandler) AcquireHostOffers(
 			"hostname":      hostname,
 			"agent_id":      offers[0].GetAgentId().GetValue(),
 			"host_offer_id": hostOffer.ID,
+			"dry_run":       body.GetDryRun(),
 		}).Info("Acquired Host")
 	}
 
@@ -378,7 +395,9 @@ func (h *ServiceHandler) GetHosts(
 
 	matcher := host.NewMatcher(
 		body.GetFilter(),
-		constraints.NewEvaluator(pb_task.LabelConstraint_HOST),
+		constraints.NewTopologyEvaluator(
+			pb_task.LabelConstraint_HOST,
+			body.GetFilter().GetTopologyDomainCounts()),
 		func(resourceType string) bool {
 			return hmutil.IsSlackResourceType(resourceType, h.slackResourceTypes)
 		})
@@ -457,6 +476,80 @@ func (h *ServiceHandler) ReleaseHostOffers(
 	return &hostsvc.ReleaseHostOffersResponse{}, nil
 }
 
+// validateReservationHostAndLabels validates the host and the reservation
+// labels of a reservation request.
+func validateReservationHostAndLabels(
+	hostname string,
+	labels *mesos.Labels) error {
+	if len(hostname) == 0 {
+		return errEmptyHostName
+	}
+	if len(labels.GetLabels()) == 0 {
+		return errEmptyReservationLabels
+	}
+	return nil
+}
+
+// validateReserveResourcesRequest validates a reserve resources request.
+func validateReserveResourcesRequest(
+	request *hostsvc.ReserveResourcesRequest) error {
+	if err := validateReservationHostAndLabels(
+		request.GetHostname(),
+		request.GetReservationLabels(),
+	); err != nil {
+		return err
+	}
+	if len(request.GetId().GetValue()) == 0 {
+		return errEmptyHostOfferID
+	}
+	if len(request.GetResources()) == 0 {
+		return errEmptyResources
+	}
+	return nil
+}
+
+// validateCreateVolumesRequest validates a create volumes request.
+func validateCreateVolumesRequest(
+	request *hostsvc.CreateVolumesRequest) error {
+	if err := validateReservationHostAndLabels(
+		request.GetHostname(),
+		request.GetReservationLabels(),
+	); err != nil {
+		return err
+	}
+	if _, _, err := reservation.ParseReservationLabels(
+		request.GetReservationLabels()); err != nil {
+		return err
+	}
+	if len(request.GetVolumes()) == 0 {
+		return errEmptyVolumes
+	}
+	for _, vol := range request.GetVolumes() {
+		if len(vol.GetDisk().GetPersistence().GetId()) == 0 ||
+			len(vol.GetDisk().GetVolume().GetContainerPath()) == 0 {
+			return errInvalidVolume
+		}
+	}
+	return nil
+}
+
+// validateDestroyVolumesRequest validates a destroy volumes request.
+func validateDestroyVolumesRequest(
+	request *hostsvc.DestroyVolumesRequest) error {
+	if len(request.GetHostname()) == 0 {
+		return errEmptyHostName
+	}
+	if len(request.GetVolumes()) == 0 {
+		return errEmptyVolumes
+	}
+	for _, vol := range request.GetVolumes() {
+		if len(vol.GetDisk().GetPersistence().GetId()) == 0 {
+			return errInvalidVolume
+		}
+	}
+	return nil
+}
+
 // validateOfferOperation ensures offer operations sequences are valid.
 func validateOfferOperationsRequest(
 	request *hostsvc.OfferOperationsRequest) error {
@@ -1208,43 +1301,517 @@ func (h *ServiceHandler) killTasks(
 }
 
 // ReserveResources implements InternalHostService.ReserveResources.
+// The resources are reserved from the unreserved offers of the host, which
+// must have been acquired with AcquireHostOffers. Reserving resources which
+// are already reserved with the same labels is a no-op.
 func (h *ServiceHandler) ReserveResources(
 	ctx context.Context,
 	body *hostsvc.ReserveResourcesRequest) (
 	*hostsvc.ReserveResourcesResponse, error) {
 
-	log.Debug("ReserveResources called.")
-	return nil, fmt.Errorf("Unimplemented")
+	log.WithField("request", body).Debug("ReserveResources called.")
+
+	if err := validateReserveResourcesRequest(body); err != nil {
+		h.metrics.ReserveResourcesInvalid.Inc(1)
+		return &hostsvc.ReserveResourcesResponse{
+			Error: &hostsvc.ReserveResourcesResponse_Error{
+				InvalidArgument: &hostsvc.InvalidArgument{
+					Message: err.Error(),
+				},
+			},
+		}, nil
+	}
+
+	labels := reservation.CreateExplicitReservationLabels(
+		body.GetReservationLabels())
+
+	reservedResources, err := h.getReservedResources(body.GetHostname())
+	if err == nil {
+		if res, ok := reservedResources[labels.String()]; ok &&
+			res.Resources.Contains(scalar.FromMesosResources(body.GetResources())) {
+			log.WithFields(log.Fields{
+				"hostname": body.GetHostname(),
+				"labels":   labels.String(),
+			}).Info("resources already reserved")
+			if err := h.offerPool.ReturnUnusedOffers(body.GetHostname()); err != nil {
+				log.WithError(err).
+					WithField("hostname", body.GetHostname()).
+					Warn("failed to return unused offers")
+			}
+			h.metrics.ReservationOperationsNoop.Inc(1)
+			return &hostsvc.ReserveResourcesResponse{}, nil
+		}
+	}
+
+	operations := []*hostsvc.OfferOperation{
+		{
+			Type:              hostsvc.OfferOperation_RESERVE,
+			ReservationLabels: labels,
+			Reserve: &hostsvc.OfferOperation_Reserve{
+				Resources: body.GetResources(),
+			},
+		},
+	}
+	if opErr := h.acceptReservationOperations(
+		ctx,
+		body.GetHostname(),
+		body.GetId().GetValue(),
+		false, /* useReservedOffers */
+		nil,
+		operations,
+	); opErr != nil {
+		h.metrics.ReserveResourcesFail.Inc(1)
+		return &hostsvc.ReserveResourcesResponse{
+			Error: &hostsvc.ReserveResourcesResponse_Error{
+				Failure:         opErr.GetFailure(),
+				InvalidArgument: opErr.GetInvalidArgument(),
+				InvalidOffers:   opErr.GetInvalidOffers(),
+			},
+		}, nil
+	}
+
+	h.metrics.ReserveResources.Inc(1)
+	return &hostsvc.ReserveResourcesResponse{}, nil
 }
 
 // UnreserveResources implements InternalHostService.UnreserveResources.
+// All the resources reserved with the reservation labels are unreserved,
+// which requires the volumes created on them to be destroyed first.
+// Unreserving resources which are not reserved on the host is a no-op.
 func (h *ServiceHandler) UnreserveResources(
 	ctx context.Context,
 	body *hostsvc.UnreserveResourcesRequest) (
 	*hostsvc.UnreserveResourcesResponse, error) {
 
-	log.Debug("UnreserveResources called.")
-	return nil, fmt.Errorf("Unimplemented")
+	log.WithField("request", body).Debug("UnreserveResources called.")
+
+	invalidArgument := func(err error) *hostsvc.UnreserveResourcesResponse {
+		h.metrics.UnreserveResourcesInvalid.Inc(1)
+		return &hostsvc.UnreserveResourcesResponse{
+			Error: &hostsvc.UnreserveResourcesResponse_Error{
+				InvalidArgument: &hostsvc.InvalidArgument{
+					Message: err.Error(),
+				},
+			},
+		}
+	}
+
+	if err := validateReservationHostAndLabels(
+		body.GetHostname(),
+		body.GetReservationLabels(),
+	); err != nil {
+		return invalidArgument(err), nil
+	}
+
+	labels := reservation.CreateExplicitReservationLabels(
+		body.GetReservationLabels())
+
+	reservedResources, err := h.getReservedResources(body.GetHostname())
+	if err != nil {
+		h.metrics.UnreserveResourcesFail.Inc(1)
+		return &hostsvc.UnreserveResourcesResponse{
+			Error: &hostsvc.UnreserveResourcesResponse_Error{
+				InvalidOffers: &hostsvc.InvalidOffers{
+					Message: err.Error(),
+				},
+			},
+		}, nil
+	}
+
+	res, ok := reservedResources[labels.String()]
+	if !ok {
+		log.WithFields(log.Fields{
+			"hostname": body.GetHostname(),
+			"labels":   labels.String(),
+		}).Info("resources already unreserved")
+		h.metrics.ReservationOperationsNoop.Inc(1)
+		return &hostsvc.UnreserveResourcesResponse{}, nil
+	}
+	if len(res.Volumes) != 0 {
+		return invalidArgument(errReservationHasVolumes), nil
+	}
+
+	operations := []*hostsvc.OfferOperation{
+		{
+			Type: hostsvc.OfferOperation_UNRESERVE,
+			Unreserve: &hostsvc.OfferOperation_Unreserve{
+				Label: labels.String(),
+			},
+		},
+	}
+	if opErr := h.acceptReservationOperations(
+		ctx,
+		body.GetHostname(),
+		"",
+		true, /* useReservedOffers */
+		labels,
+		operations,
+	); opErr != nil {
+		h.metrics.UnreserveResourcesFail.Inc(1)
+		return &hostsvc.UnreserveResourcesResponse{
+			Error: &hostsvc.UnreserveResourcesResponse_Error{
+				Failure:         opErr.GetFailure(),
+				InvalidArgument: opErr.GetInvalidArgument(),
+				InvalidOffers:   opErr.GetInvalidOffers(),
+			},
+		}, nil
+	}
+
+	h.metrics.UnreserveResources.Inc(1)
+	return &hostsvc.UnreserveResourcesResponse{}, nil
 }
 
 // CreateVolumes implements InternalHostService.CreateVolumes.
+// The volumes are created on the resources reserved with the reservation
+// labels, and persisted in the volume store. Volumes which already exist
+// on the host are skipped.
 func (h *ServiceHandler) CreateVolumes(
 	ctx context.Context,
 	body *hostsvc.CreateVolumesRequest) (
 	*hostsvc.CreateVolumesResponse, error) {
 
-	log.Debug("CreateVolumes called.")
-	return nil, fmt.Errorf("Unimplemented")
+	log.WithField("request", body).Debug("CreateVolumes called.")
+
+	if err := validateCreateVolumesRequest(body); err != nil {
+		h.metrics.CreateVolumesInvalid.Inc(1)
+		return &hostsvc.CreateVolumesResponse{
+			Error: &hostsvc.CreateVolumesResponse_Error{
+				InvalidArgument: &hostsvc.InvalidArgument{
+					Message: err.Error(),
+				},
+			},
+		}, nil
+	}
+
+	labels := reservation.CreateExplicitReservationLabels(
+		body.GetReservationLabels())
+
+	existingVolumes := make(map[string]bool)
+	reservedResources, err := h.getReservedResources(body.GetHostname())
+	if err == nil {
+		if res, ok := reservedResources[labels.String()]; ok {
+			for _, volumeID := range res.Volumes {
+				existingVolumes[volumeID] = true
+			}
+		}
+	}
+
+	var operations []*hostsvc.OfferOperation
+	for _, vol := range body.GetVolumes() {
+		volumeID := vol.GetDisk().GetPersistence().GetId()
+		if existingVolumes[volumeID] {
+			log.WithFields(log.Fields{
+				"hostname":  body.GetHostname(),
+				"volume_id": volumeID,
+			}).Info("volume already created")
+			continue
+		}
+
+		// reservation and persistence are set by the create operation.
+		volumeRes := proto.Clone(vol).(*mesos.Resource)
+		volumeRes.Role = nil
+		volumeRes.Reservation = nil
+		volumeRes.Disk = nil
+
+		operations = append(operations, &hostsvc.OfferOperation{
+			Type:              hostsvc.OfferOperation_CREATE,
+			ReservationLabels: labels,
+			Create: &hostsvc.OfferOperation_Create{
+				Volume: &hostsvc.Volume{
+					Id:            &peloton.VolumeID{Value: volumeID},
+					ContainerPath: vol.GetDisk().GetVolume().GetContainerPath(),
+					Resource:      volumeRes,
+				},
+			},
+		})
+	}
+
+	if len(operations) == 0 {
+		h.metrics.ReservationOperationsNoop.Inc(1)
+		return &hostsvc.CreateVolumesResponse{}, nil
+	}
+
+	if opErr := h.acceptReservationOperations(
+		ctx,
+		body.GetHostname(),
+		"",
+		true, /* useReservedOffers */
+		labels,
+		operations,
+	); opErr != nil {
+		h.metrics.CreateVolumesFail.Inc(1)
+		return &hostsvc.CreateVolumesResponse{
+			Error: &hostsvc.CreateVolumesResponse_Error{
+				Failure:         opErr.GetFailure(),
+				InvalidArgument: opErr.GetInvalidArgument(),
+				InvalidOffers:   opErr.GetInvalidOffers(),
+			},
+		}, nil
+	}
+
+	h.metrics.CreateVolumes.Inc(1)
+	return &hostsvc.CreateVolumesResponse{}, nil
 }
 
 // DestroyVolumes implements InternalHostService.DestroyVolumes.
+// The goal state of the volumes is set to deleted in the volume store, and
+// the volumes which are offered by the host are destroyed right away. The
+// remaining volumes are destroyed by the reservation cleaner once they are
+// offered again.
 func (h *ServiceHandler) DestroyVolumes(
 	ctx context.Context,
 	body *hostsvc.DestroyVolumesRequest) (
 	*hostsvc.DestroyVolumesResponse, error) {
 
-	log.Debug("DestroyVolumes called.")
-	return nil, fmt.Errorf("Unimplemented")
+	log.WithField("request", body).Debug("DestroyVolumes called.")
+
+	if err := validateDestroyVolumesRequest(body); err != nil {
+		h.metrics.DestroyVolumesInvalid.Inc(1)
+		return &hostsvc.DestroyVolumesResponse{
+			Error: &hostsvc.DestroyVolumesResponse_Error{
+				InvalidArgument: &hostsvc.InvalidArgument{
+					Message: err.Error(),
+				},
+			},
+		}, nil
+	}
+
+	offeredVolumes := make(map[string]bool)
+	reservedResources, err := h.getReservedResources(body.GetHostname())
+	if err == nil {
+		for _, res := range reservedResources {
+			for _, volumeID := range res.Volumes {
+				offeredVolumes[volumeID] = true
+			}
+		}
+	}
+
+	var operations []*hostsvc.OfferOperation
+	var volumeInfos []*volume.PersistentVolumeInfo
+	for _, vol := range body.GetVolumes() {
+		volumeID := vol.GetDisk().GetPersistence().GetId()
+
+		volumeInfo, err := h.markVolumeDeleted(ctx, volumeID)
+		if err != nil {
+			h.metrics.DestroyVolumesFail.Inc(1)
+			return &hostsvc.DestroyVolumesResponse{
+				Error: &hostsvc.DestroyVolumesResponse_Error{
+					Failure: &hostsvc.OperationsFailure{
+						Message: err.Error(),
+					},
+				},
+			}, nil
+		}
+		if volumeInfo != nil {
+			volumeInfos = append(volumeInfos, volumeInfo)
+		}
+
+		if !offeredVolumes[volumeID] {
+			log.WithFields(log.Fields{
+				"hostname":  body.GetHostname(),
+				"volume_id": volumeID,
+			}).Info("volume not offered, skip destroying it")
+			continue
+		}
+
+		operations = append(operations, &hostsvc.OfferOperation{
+			Type: hostsvc.OfferOperation_DESTROY,
+			Destroy: &hostsvc.OfferOperation_Destroy{
+				VolumeID: volumeID,
+			},
+		})
+	}
+
+	if len(operations) == 0 {
+		h.metrics.ReservationOperationsNoop.Inc(1)
+		return &hostsvc.DestroyVolumesResponse{}, nil
+	}
+
+	if opErr := h.acceptReservationOperations(
+		ctx,
+		body.GetHostname(),
+		"",
+		true, /* useReservedOffers */
+		nil,
+		operations,
+	); opErr != nil {
+		h.metrics.DestroyVolumesFail.Inc(1)
+		return &hostsvc.DestroyVolumesResponse{
+			Error: &hostsvc.DestroyVolumesResponse_Error{
+				Failure:         opErr.GetFailure(),
+				InvalidArgument: opErr.GetInvalidArgument(),
+				InvalidOffers:   opErr.GetInvalidOffers(),
+			},
+		}, nil
+	}
+
+	for _, volumeInfo := range volumeInfos {
+		if !offeredVolumes[volumeInfo.GetId().GetValue()] {
+			continue
+		}
+		volumeInfo.State = volume.VolumeState_DELETED
+		if err := h.volumeStore.UpdatePersistentVolume(
+			ctx, volumeInfo); err != nil {
+			log.WithError(err).
+				WithField("volume_id", volumeInfo.GetId().GetValue()).
+				Warn("failed to update volume state")
+		}
+	}
+
+	h.metrics.DestroyVolumes.Inc(1)
+	return &hostsvc.DestroyVolumesResponse{}, nil
+}
+
+// markVolumeDeleted sets the goal state of the volume to deleted in the
+// volume store, and returns the volume info. A nil volume info is returned
+// if the volume is not in the volume store.
+func (h *ServiceHandler) markVolumeDeleted(
+	ctx context.Context,
+	volumeID string,
+) (*volume.PersistentVolumeInfo, error) {
+	volumeInfo, err := h.volumeStore.GetPersistentVolume(
+		ctx,
+		&peloton.VolumeID{Value: volumeID})
+	if err != nil {
+		if _, ok := err.(*storage.VolumeNotFoundError); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if volumeInfo.GetGoalState() == volume.VolumeState_DELETED {
+		return volumeInfo, nil
+	}
+
+	volumeInfo.GoalState = volume.VolumeState_DELETED
+	if err := h.volumeStore.UpdatePersistentVolume(ctx, volumeInfo); err != nil {
+		return nil, err
+	}
+	return volumeInfo, nil
+}
+
+// getReservedResources returns the resources reserved on the host, by
+// reservation labels, from the reserved offers of the host in the pool.
+func (h *ServiceHandler) getReservedResources(
+	hostname string,
+) (map[string]*hmreservation.ReservedResources, error) {
+	hostSummary, err := h.offerPool.GetHostSummary(hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	var offers []*mesos.Offer
+	for _, offer := range hostSummary.GetOffers(summary.Reserved) {
+		offers = append(offers, offer)
+	}
+	return hmreservation.GetLabeledReservedResources(offers), nil
+}
+
+// acceptReservationOperations claims the offers of the host and accepts them
+// with the given offer operations. If useReservedOffers is set, the reserved
+// offers of the host are used, otherwise the unreserved offers held with the
+// host offer id. If reservationLabels is set, only the resources reserved
+// with the labels are used for the operations.
+func (h *ServiceHandler) acceptReservationOperations(
+	ctx context.Context,
+	hostname string,
+	hostOfferID string,
+	useReservedOffers bool,
+	reservationLabels *mesos.Labels,
+	operations []*hostsvc.OfferOperation,
+) *hostsvc.OfferOperationsResponse_Error {
+	offers, err := h.offerPool.ClaimForLaunch(
+		hostname,
+		useReservedOffers,
+		hostOfferID,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"hostname":      hostname,
+			"host_offer_id": hostOfferID,
+		}).WithError(err).Error("claim offer for reservation operations failed")
+		return &hostsvc.OfferOperationsResponse_Error{
+			InvalidOffers: &hostsvc.InvalidOffers{
+				Message: err.Error(),
+			},
+		}
+	}
+
+	var offerIds []*mesos.OfferID
+	var mesosResources []*mesos.Resource
+	var agentID *mesos.AgentID
+	for _, offer := range offers {
+		offerIds = append(offerIds, offer.GetId())
+		agentID = offer.GetAgentId()
+		for _, res := range offer.GetResources() {
+			if reservationLabels == nil ||
+				reservationLabels.String() == res.GetReservation().GetLabels().String() {
+				mesosResources = append(mesosResources, res)
+			}
+		}
+	}
+
+	factory := operation.NewOfferOperationsFactory(
+		operations,
+		mesosResources,
+		hostname,
+		agentID,
+	)
+	offerOperations, err := factory.GetOfferOperations()
+	if err == nil {
+		// write the info of each created volume into db.
+		for _, op := range offerOperations {
+			if err = h.persistVolumeInfo(
+				ctx,
+				[]*mesos.Offer_Operation{op},
+				hostname); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"operations": operations,
+			"offers":     offers,
+		}).Error("get reservation operations failed")
+		// return the claimed offers to Mesos, so that they are offered again
+		if err := h.offerPool.DeclineOffers(ctx, offerIds); err != nil {
+			log.WithError(err).
+				WithField("offers", offerIds).
+				Warn("Cannot decline offers")
+		}
+		return &hostsvc.OfferOperationsResponse_Error{
+			InvalidArgument: &hostsvc.InvalidArgument{
+				Message: "Cannot get offer operations: " + err.Error(),
+			},
+		}
+	}
+
+	callType := sched.Call_ACCEPT
+	msg := &sched.Call{
+		FrameworkId: h.frameworkInfoProvider.GetFrameworkID(ctx),
+		Type:        &callType,
+		Accept: &sched.Call_Accept{
+			OfferIds:   offerIds,
+			Operations: offerOperations,
+		},
+	}
+
+	log.WithField("call", msg).Debug("Accepting offer with reservation operations.")
+
+	msid := h.frameworkInfoProvider.GetMesosStreamID(ctx)
+	if err := h.schedulerClient.Call(msid, msg); err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"operations": offerOperations,
+			"offers":     offerIds,
+		}).Warn("Reservation operations failure")
+		return &hostsvc.OfferOperationsResponse_Error{
+			Failure: &hostsvc.OperationsFailure{
+				Message: err.Error(),
+			},
+		}
+	}
+	return nil
 }
 
 // ClusterCapacity fetches the allocated resources to the framework
@@ -1335,6 +1902,12 @@ func (h *ServiceHandler) ClusterCapacity(
 		if nonRevocableClusterCapacity.GetGPU() <= 0 {
 			nonRevocableClusterCapacity.GPU = agentMap.Capacity.GetGPU()
 		}
+		if nonRevocableClusterCapacity.GetPorts() <= 0 {
+			nonRevocableClusterCapacity.Ports = agentMap.Capacity.GetPorts()
+		}
+		if nonRevocableClusterCapacity.GetNetwork() <= 0 {
+			nonRevocableClusterCapacity.Network = agentMap.Capacity.GetNetwork()
+		}
 	}
 
 	revocableAllocated, nonRevocableAllocated := scalar.FilterMesosResources(
@@ -1347,8 +1920,10 @@ func (h *ServiceHandler) ClusterCapacity(
 	response = &hostsvc.ClusterCapacityResponse{
 		Resources:               toHostSvcResources(&physicalAllocated),
 		AllocatedSlackResources: toHostSvcResources(&slackAllocated),
-		PhysicalResources:       toHostSvcResources(&nonRevocableClusterCapacity),
-		PhysicalSlackResources:  toHostSvcResources(&agentMap.SlackCapacity),
+		PhysicalResources: append(
+			toHostSvcResources(&nonRevocableClusterCapacity),
+			toGPUModelResources(agentMap.GPUModelCapacity)...),
+		PhysicalSlackResources: toHostSvcResources(&agentMap.SlackCapacity),
 	}
 
 	return response, nil
@@ -1453,6 +2028,21 @@ func (h *ServiceHandler) GetCompletedReservations(
 	}, nil
 }
 
+// GetHostReservations returns the outstanding host reservations from
+// reserver for the tasks with at least the requested priority, along with
+// the resources which are still missing on each reserved host.
+func (h *ServiceHandler) GetHostReservations(
+	ctx context.Context,
+	req *hostsvc.GetHostReservationsRequest,
+) (*hostsvc.GetHostReservationsResponse, error) {
+	log.WithField("request", req).Debug("GetHostReservations called.")
+	reservations := h.reserver.GetReservations(req.GetMinPriority())
+	log.Debug("GetHostReservations returned.")
+	return &hostsvc.GetHostReservationsResponse{
+		Reservations: reservations,
+	}, nil
+}
+
 // GetDrainingHosts implements InternalHostService.GetDrainingHosts
 func (h *ServiceHandler) GetDrainingHosts(
 	ctx context.Context,
@@ -1490,7 +2080,9 @@ func (h *ServiceHandler) GetDrainingHosts(
 
 // MarkHostsDrained implements InternalHostService.MarkHostsDrained
 // Mark the host as drained. This method is called by Resource Manager Drainer
-// when there are no tasks on the DRAINING hosts
+// when there are no tasks on the DRAINING hosts. Hosts draining for a Mesos
+// inverse offer are not put down before the unavailability of their inverse
+// offer starts, the other hosts are put down right away.
 func (h *ServiceHandler) MarkHostsDrained(
 	ctx context.Context,
 	request *hostsvc.MarkHostsDrainedRequest,
@@ -1499,9 +2091,28 @@ func (h *ServiceHandler) MarkHostsDrained(
 	for _, host := range request.GetHostnames() {
 		hostSet.Add(host)
 	}
-	var machineIDs []*mesos.MachineID
+	var drainingHosts []string
+	var drainingHostInfos []*hpb.HostInfo
 	for _, hostInfo := range h.maintenanceHostInfoMap.GetDrainingHostInfos([]string{}) {
 		if hostSet.Contains(hostInfo.GetHostname()) {
+			drainingHosts = append(drainingHosts, hostInfo.GetHostname())
+			drainingHostInfos = append(drainingHostInfos, hostInfo)
+		}
+	}
+
+	// Hosts draining for Mesos inverse offers out of their unavailability
+	// are not put down, their inverse offers are accepted by the offer
+	// event handler instead. They are marked as drained again by the next
+	// drain until their unavailability starts.
+	drainedHosts := h.offerEventHandler.MarkHostsDrained(drainingHosts)
+	drainedHostSet := stringset.New()
+	for _, host := range drainedHosts {
+		drainedHostSet.Add(host)
+	}
+
+	var machineIDs []*mesos.MachineID
+	for _, hostInfo := range drainingHostInfos {
+		if !drainedHostSet.Contains(hostInfo.GetHostname()) {
 			machineIDs = append(machineIDs, &mesos.MachineID{
 				Hostname: &hostInfo.Hostname,
 				Ip:       &hostInfo.Ip,
@@ -1509,17 +2120,21 @@ func (h *ServiceHandler) MarkHostsDrained(
 		}
 	}
 
-	if len(machineIDs) != len(request.Hostnames) {
+	if len(drainingHosts) != len(request.Hostnames) {
 		log.WithFields(log.Fields{
-			"machine_ids_in_map": machineIDs,
-			"request":            request,
+			"hosts_in_map": drainingHosts,
+			"request":      request,
 		}).Errorf("failed to find some hostnames in maintenanceHostInfoMap")
 	}
 	// No-op if none of the hosts in the request are 'DRAINING'
+	// or all of them are drained for Mesos inverse offers
 	if len(machineIDs) == 0 {
-		return &hostsvc.MarkHostsDrainedResponse{}, nil
+		h.metrics.MarkHostsDrained.Inc(int64(len(drainedHosts)))
+		return &hostsvc.MarkHostsDrainedResponse{
+			MarkedHosts: drainedHosts,
+		}, nil
 	}
-	var downedHosts []string
+	downedHosts := drainedHosts
 	var errs error
 	for _, machineID := range machineIDs {
 		// Start maintenance on the host by posting to
@@ -1634,10 +2249,32 @@ func toHostSvcResources(rs *scalar.Resources) []*hostsvc.Resource {
 		}, {
 			Kind:     common.MEMORY,
 			Capacity: rs.Mem,
+		}, {
+			Kind:     common.PORTS,
+			Capacity: rs.Ports,
+		}, {
+			Kind:     common.NETWORK,
+			Capacity: rs.Network,
 		},
 	}
 }
 
+// Helper function to convert the GPU capacity per model into hostsvc format,
+// sorted by kind so that the response is stable.
+func toGPUModelResources(capacity map[string]float64) []*hostsvc.Resource {
+	var resources []*hostsvc.Resource
+	for model, gpus := range capacity {
+		resources = append(resources, &hostsvc.Resource{
+			Kind:     gpu.ModelKind(model),
+			Capacity: gpus,
+		})
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].GetKind() < resources[j].GetKind()
+	})
+	return resources
+}
+
 // Helper function to convert summary.HostStatus to string
 func toHostStatus(hostStatus summary.HostStatus) string {
 	var status string
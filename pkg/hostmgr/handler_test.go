@@ -34,6 +34,7 @@ import (
 	pb_eventstream "github.com/uber/peloton/.gen/peloton/private/eventstream"
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 
+	"github.com/uber/peloton/pkg/common"
 	"github.com/uber/peloton/pkg/common/queue"
 	"github.com/uber/peloton/pkg/common/reservation"
 	"github.com/uber/peloton/pkg/common/util"
@@ -44,12 +45,14 @@ import (
 	hostmgr_mesos_mocks "github.com/uber/peloton/pkg/hostmgr/mesos/mocks"
 	mpb_mocks "github.com/uber/peloton/pkg/hostmgr/mesos/yarpc/encoding/mpb/mocks"
 	"github.com/uber/peloton/pkg/hostmgr/metrics"
+	offer_mocks "github.com/uber/peloton/pkg/hostmgr/offer/mocks"
 	"github.com/uber/peloton/pkg/hostmgr/offer/offerpool"
 	qm "github.com/uber/peloton/pkg/hostmgr/queue/mocks"
 	"github.com/uber/peloton/pkg/hostmgr/reserver"
 	reserver_mocks "github.com/uber/peloton/pkg/hostmgr/reserver/mocks"
 	"github.com/uber/peloton/pkg/hostmgr/summary"
 	task_state_mocks "github.com/uber/peloton/pkg/hostmgr/task/mocks"
+	"github.com/uber/peloton/pkg/storage"
 	storage_mocks "github.com/uber/peloton/pkg/storage/mocks"
 
 	"github.com/gogo/protobuf/proto"
@@ -205,6 +208,7 @@ type HostMgrHandlerTestSuite struct {
 	provider               *hostmgr_mesos_mocks.MockFrameworkInfoProvider
 	volumeStore            *storage_mocks.MockPersistentVolumeStore
 	pool                   offerpool.Pool
+	offerEventHandler      *offer_mocks.MockEventHandler
 	handler                *ServiceHandler
 	frameworkID            *mesos.FrameworkID
 	mesosDetector          *hostmgr_mesos_mocks.MockMasterDetector
@@ -266,12 +270,14 @@ func (suite *HostMgrHandlerTestSuite) SetupTest() {
 
 	suite.maintenanceQueue = qm.NewMockMaintenanceQueue(suite.ctrl)
 	suite.maintenanceHostInfoMap = hm.NewMockMaintenanceHostInfoMap(suite.ctrl)
+	suite.offerEventHandler = offer_mocks.NewMockEventHandler(suite.ctrl)
 
 	suite.handler = &ServiceHandler{
 		schedulerClient:        suite.schedulerClient,
 		operatorMasterClient:   suite.masterOperatorClient,
 		metrics:                metrics.NewMetrics(suite.testScope),
 		offerPool:              suite.pool,
+		offerEventHandler:      suite.offerEventHandler,
 		frameworkInfoProvider:  suite.provider,
 		volumeStore:            suite.volumeStore,
 		mesosDetector:          suite.mesosDetector,
@@ -708,6 +714,55 @@ func (suite *HostMgrHandlerTestSuite) TestAcquireReleaseHostOffers() {
 	suite.Equal(numHosts, len(acquiredResp.GetHostOffers()))
 }
 
+// TestAcquireHostOffersDryRun checks that a dry run acquire returns
+// matching host offers without moving the hosts to placing.
+func (suite *HostMgrHandlerTestSuite) TestAcquireHostOffersDryRun() {
+	defer suite.ctrl.Finish()
+
+	numHosts := 5
+	suite.pool.AddOffers(context.Background(), generateOffers(numHosts))
+
+	acquireReq := &hostsvc.AcquireHostOffersRequest{
+		Filter: &hostsvc.HostFilter{
+			Quantity: &hostsvc.QuantityControl{
+				MaxHosts: uint32(numHosts * 2),
+			},
+			ResourceConstraint: &hostsvc.ResourceConstraint{
+				Minimum: &task.ResourceConfig{
+					CpuLimit:    _perHostCPU,
+					MemLimitMb:  _perHostMem,
+					DiskLimitMb: _perHostDisk,
+				},
+			},
+		},
+		DryRun: true,
+	}
+
+	acquiredResp, err := suite.handler.AcquireHostOffers(
+		rootCtx,
+		acquireReq,
+	)
+	suite.NoError(err)
+	suite.Nil(acquiredResp.GetError())
+	suite.Equal(numHosts, len(acquiredResp.GetHostOffers()))
+
+	suite.checkResourcesGauges(numHosts, "ready")
+	suite.checkResourcesGauges(0, "placing")
+
+	// The same hosts can still be acquired for placement.
+	acquireReq.DryRun = false
+	acquiredResp, err = suite.handler.AcquireHostOffers(
+		rootCtx,
+		acquireReq,
+	)
+	suite.NoError(err)
+	suite.Nil(acquiredResp.GetError())
+	suite.Equal(numHosts, len(acquiredResp.GetHostOffers()))
+
+	suite.checkResourcesGauges(0, "ready")
+	suite.checkResourcesGauges(numHosts, "placing")
+}
+
 // This checks the happy case of acquire -> launch
 // sequence.
 func (suite *HostMgrHandlerTestSuite) TestAcquireAndLaunch() {
@@ -1606,7 +1661,7 @@ func (suite *HostMgrHandlerTestSuite) TestServiceHandlerClusterCapacity() {
 		} else {
 			suite.Nil(resp.Error)
 			suite.NotNil(resp.Resources)
-			suite.Equal(4, len(resp.PhysicalResources))
+			suite.Equal(6, len(resp.PhysicalResources))
 			for _, v := range resp.PhysicalResources {
 				suite.Equal(v.Capacity, float64(numAgents))
 			}
@@ -1726,7 +1781,7 @@ func (suite *HostMgrHandlerTestSuite) TestServiceHandlerClusterCapacityWithQuota
 
 	suite.Nil(resp.Error)
 	suite.NotNil(resp.Resources)
-	suite.Equal(4, len(resp.PhysicalResources))
+	suite.Equal(6, len(resp.PhysicalResources))
 	for _, v := range resp.PhysicalResources {
 		if v.GetKind() == "cpu" {
 			suite.Equal(v.Capacity, float64(quotaVal))
@@ -1734,6 +1789,19 @@ func (suite *HostMgrHandlerTestSuite) TestServiceHandlerClusterCapacityWithQuota
 	}
 }
 
+// TestToGPUModelResources tests converting the GPU capacity per model into
+// cluster capacity resources.
+func (suite *HostMgrHandlerTestSuite) TestToGPUModelResources() {
+	suite.Nil(toGPUModelResources(nil))
+	suite.Equal([]*hostsvc.Resource{
+		{Kind: "gpu.p100", Capacity: 4},
+		{Kind: "gpu.tesla-v100", Capacity: 16},
+	}, toGPUModelResources(map[string]float64{
+		"tesla-v100": 16,
+		"p100":       4,
+	}))
+}
+
 func (suite *HostMgrHandlerTestSuite) TestLaunchOperationWithReservedOffers() {
 	defer suite.ctrl.Finish()
 
@@ -2072,6 +2140,386 @@ func (suite *HostMgrHandlerTestSuite) TestReserveCreateLaunchOperationWithCreate
 		suite.testScope.Snapshot().Counters()["offer_operations+"].Value())
 }
 
+// addExplicitlyReservedOffer adds an offer for hostname-0 with resources
+// explicitly reserved with the test reservation labels, and a volume on them
+// if withVolume is set.
+func (suite *HostMgrHandlerTestSuite) addExplicitlyReservedOffer(
+	withVolume bool) *mesos.Labels {
+	labels := reservation.CreateExplicitReservationLabels(
+		createReservationLabels())
+	resInfo := &mesos.Resource_ReservationInfo{
+		Labels: labels,
+	}
+
+	offers := generateOffers(1)
+	offers[0].Resources = append(offers[0].Resources,
+		util.NewMesosResourceBuilder().
+			WithName("cpus").
+			WithValue(_perHostCPU).
+			WithRole(_pelotonRole).
+			WithReservation(resInfo).
+			Build(),
+		util.NewMesosResourceBuilder().
+			WithName("disk").
+			WithValue(_perHostDisk).
+			WithRole(_pelotonRole).
+			WithReservation(resInfo).
+			Build(),
+	)
+	if withVolume {
+		offers[0].Resources = append(offers[0].Resources,
+			util.NewMesosResourceBuilder().
+				WithName("disk").
+				WithValue(1.0).
+				WithRole(_pelotonRole).
+				WithReservation(resInfo).
+				WithDisk(&mesos.Resource_DiskInfo{
+					Persistence: &mesos.Resource_DiskInfo_Persistence{
+						Id: &_testKey,
+					},
+				}).
+				Build(),
+		)
+	}
+	suite.pool.AddOffers(context.Background(), offers)
+	return labels
+}
+
+// expectAcceptOperation sets the expectation of an accept call with a single
+// offer operation of the given type.
+func (suite *HostMgrHandlerTestSuite) expectAcceptOperation(
+	opType mesos.Offer_Operation_Type) {
+	gomock.InOrder(
+		suite.provider.EXPECT().GetFrameworkID(context.Background()).Return(
+			suite.frameworkID),
+		suite.provider.EXPECT().GetMesosStreamID(context.Background()).Return(_streamID),
+		suite.schedulerClient.EXPECT().
+			Call(
+				gomock.Eq(_streamID),
+				gomock.Any(),
+			).
+			Do(func(_ string, msg proto.Message) {
+				call := msg.(*sched.Call)
+				suite.Equal(sched.Call_ACCEPT, call.GetType())
+				accept := call.GetAccept()
+				suite.Equal(1, len(accept.GetOfferIds()))
+				suite.Equal(1, len(accept.GetOperations()))
+				suite.Equal(opType, accept.GetOperations()[0].GetType())
+			}).
+			Return(nil),
+	)
+}
+
+// TestReserveResources tests reserving resources from an acquired host offer.
+func (suite *HostMgrHandlerTestSuite) TestReserveResources() {
+	defer suite.ctrl.Finish()
+
+	acquiredResp, err := suite.acquireHostOffers(1)
+	suite.NoError(err)
+	suite.Equal(1, len(acquiredResp.GetHostOffers()))
+	hostOffer := acquiredResp.GetHostOffers()[0]
+
+	suite.expectAcceptOperation(mesos.Offer_Operation_RESERVE)
+
+	resp, err := suite.handler.ReserveResources(
+		rootCtx,
+		&hostsvc.ReserveResourcesRequest{
+			Hostname:          hostOffer.GetHostname(),
+			Id:                hostOffer.GetId(),
+			Resources:         createHostReserveOperation().GetReserve().GetResources(),
+			ReservationLabels: createReservationLabels(),
+		},
+	)
+	suite.NoError(err)
+	suite.Nil(resp.GetError())
+	suite.Equal(
+		int64(1),
+		suite.testScope.Snapshot().Counters()["reserve_resources+"].Value())
+}
+
+// TestReserveResourcesAlreadyReserved tests reserving resources which are
+// already reserved with the same labels is a no-op.
+func (suite *HostMgrHandlerTestSuite) TestReserveResourcesAlreadyReserved() {
+	defer suite.ctrl.Finish()
+
+	suite.addExplicitlyReservedOffer(false)
+
+	resp, err := suite.handler.ReserveResources(
+		rootCtx,
+		&hostsvc.ReserveResourcesRequest{
+			Hostname: "hostname-0",
+			Id:       &peloton.HostOfferID{Value: uuid.New()},
+			Resources: []*mesos.Resource{
+				util.NewMesosResourceBuilder().
+					WithName("cpus").
+					WithValue(_perHostCPU).
+					Build(),
+			},
+			ReservationLabels: createReservationLabels(),
+		},
+	)
+	suite.NoError(err)
+	suite.Nil(resp.GetError())
+	suite.Equal(
+		int64(1),
+		suite.testScope.Snapshot().Counters()["reservation_operations_noop+"].Value())
+}
+
+// TestReserveResourcesInvalidRequest tests reserving resources with an
+// invalid request.
+func (suite *HostMgrHandlerTestSuite) TestReserveResourcesInvalidRequest() {
+	defer suite.ctrl.Finish()
+
+	resp, err := suite.handler.ReserveResources(
+		rootCtx,
+		&hostsvc.ReserveResourcesRequest{
+			Hostname:          "hostname-0",
+			ReservationLabels: createReservationLabels(),
+		},
+	)
+	suite.NoError(err)
+	suite.NotNil(resp.GetError().GetInvalidArgument())
+
+	// the host offer is not acquired.
+	resp, err = suite.handler.ReserveResources(
+		rootCtx,
+		&hostsvc.ReserveResourcesRequest{
+			Hostname:          "hostname-0",
+			Id:                &peloton.HostOfferID{Value: uuid.New()},
+			Resources:         createHostReserveOperation().GetReserve().GetResources(),
+			ReservationLabels: createReservationLabels(),
+		},
+	)
+	suite.NoError(err)
+	suite.NotNil(resp.GetError().GetInvalidOffers())
+}
+
+// TestUnreserveResources tests unreserving explicitly reserved resources.
+func (suite *HostMgrHandlerTestSuite) TestUnreserveResources() {
+	defer suite.ctrl.Finish()
+
+	suite.addExplicitlyReservedOffer(false)
+	suite.expectAcceptOperation(mesos.Offer_Operation_UNRESERVE)
+
+	resp, err := suite.handler.UnreserveResources(
+		rootCtx,
+		&hostsvc.UnreserveResourcesRequest{
+			Hostname:          "hostname-0",
+			ReservationLabels: createReservationLabels(),
+		},
+	)
+	suite.NoError(err)
+	suite.Nil(resp.GetError())
+	suite.Equal(
+		int64(1),
+		suite.testScope.Snapshot().Counters()["unreserve_resources+"].Value())
+}
+
+// TestUnreserveResourcesNotReserved tests unreserving resources which are
+// not reserved on the host is a no-op.
+func (suite *HostMgrHandlerTestSuite) TestUnreserveResourcesNotReserved() {
+	defer suite.ctrl.Finish()
+
+	suite.pool.AddOffers(context.Background(), generateOffers(1))
+
+	resp, err := suite.handler.UnreserveResources(
+		rootCtx,
+		&hostsvc.UnreserveResourcesRequest{
+			Hostname:          "hostname-0",
+			ReservationLabels: createReservationLabels(),
+		},
+	)
+	suite.NoError(err)
+	suite.Nil(resp.GetError())
+	suite.Equal(
+		int64(1),
+		suite.testScope.Snapshot().Counters()["reservation_operations_noop+"].Value())
+}
+
+// TestUnreserveResourcesWithVolumes tests unreserving resources which
+// still have volumes fails.
+func (suite *HostMgrHandlerTestSuite) TestUnreserveResourcesWithVolumes() {
+	defer suite.ctrl.Finish()
+
+	suite.addExplicitlyReservedOffer(true)
+
+	resp, err := suite.handler.UnreserveResources(
+		rootCtx,
+		&hostsvc.UnreserveResourcesRequest{
+			Hostname:          "hostname-0",
+			ReservationLabels: createReservationLabels(),
+		},
+	)
+	suite.NoError(err)
+	suite.Equal(
+		errReservationHasVolumes.Error(),
+		resp.GetError().GetInvalidArgument().GetMessage())
+}
+
+// TestCreateVolumes tests creating a volume on explicitly reserved resources.
+func (suite *HostMgrHandlerTestSuite) TestCreateVolumes() {
+	defer suite.ctrl.Finish()
+
+	suite.addExplicitlyReservedOffer(false)
+
+	suite.volumeStore.EXPECT().
+		GetPersistentVolume(gomock.Any(), gomock.Any()).
+		Return(nil, &storage.VolumeNotFoundError{})
+	suite.volumeStore.EXPECT().
+		CreatePersistentVolume(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, volumeInfo *volume.PersistentVolumeInfo) {
+			suite.Equal("volumeid", volumeInfo.GetId().GetValue())
+			suite.Equal(_testJobID, volumeInfo.GetJobId().GetValue())
+			suite.Equal("test", volumeInfo.GetContainerPath())
+		}).
+		Return(nil)
+	suite.expectAcceptOperation(mesos.Offer_Operation_CREATE)
+
+	volumeID := "volumeid"
+	containerPath := "test"
+	resp, err := suite.handler.CreateVolumes(
+		rootCtx,
+		&hostsvc.CreateVolumesRequest{
+			Hostname: "hostname-0",
+			Volumes: []*mesos.Resource{
+				util.NewMesosResourceBuilder().
+					WithName("disk").
+					WithValue(1.0).
+					WithDisk(&mesos.Resource_DiskInfo{
+						Persistence: &mesos.Resource_DiskInfo_Persistence{
+							Id: &volumeID,
+						},
+						Volume: &mesos.Volume{
+							ContainerPath: &containerPath,
+						},
+					}).
+					Build(),
+			},
+			ReservationLabels: createReservationLabels(),
+		},
+	)
+	suite.NoError(err)
+	suite.Nil(resp.GetError())
+	suite.Equal(
+		int64(1),
+		suite.testScope.Snapshot().Counters()["create_volumes+"].Value())
+}
+
+// TestCreateVolumesInvalidVolume tests creating a volume without container
+// path fails.
+func (suite *HostMgrHandlerTestSuite) TestCreateVolumesInvalidVolume() {
+	defer suite.ctrl.Finish()
+
+	resp, err := suite.handler.CreateVolumes(
+		rootCtx,
+		&hostsvc.CreateVolumesRequest{
+			Hostname: "hostname-0",
+			Volumes: []*mesos.Resource{
+				util.NewMesosResourceBuilder().
+					WithName("disk").
+					WithValue(1.0).
+					WithDisk(&mesos.Resource_DiskInfo{
+						Persistence: &mesos.Resource_DiskInfo_Persistence{
+							Id: &_testKey,
+						},
+					}).
+					Build(),
+			},
+			ReservationLabels: createReservationLabels(),
+		},
+	)
+	suite.NoError(err)
+	suite.Equal(
+		errInvalidVolume.Error(),
+		resp.GetError().GetInvalidArgument().GetMessage())
+}
+
+// TestDestroyVolumes tests destroying an offered volume.
+func (suite *HostMgrHandlerTestSuite) TestDestroyVolumes() {
+	defer suite.ctrl.Finish()
+
+	suite.addExplicitlyReservedOffer(true)
+
+	volumeInfo := &volume.PersistentVolumeInfo{
+		Id:        &peloton.VolumeID{Value: _testKey},
+		State:     volume.VolumeState_CREATED,
+		GoalState: volume.VolumeState_CREATED,
+	}
+	suite.volumeStore.EXPECT().
+		GetPersistentVolume(gomock.Any(), gomock.Any()).
+		Return(volumeInfo, nil)
+	suite.volumeStore.EXPECT().
+		UpdatePersistentVolume(gomock.Any(), volumeInfo).
+		Return(nil).
+		Times(2)
+	suite.expectAcceptOperation(mesos.Offer_Operation_DESTROY)
+
+	resp, err := suite.handler.DestroyVolumes(
+		rootCtx,
+		&hostsvc.DestroyVolumesRequest{
+			Hostname: "hostname-0",
+			Volumes: []*mesos.Resource{
+				util.NewMesosResourceBuilder().
+					WithName("disk").
+					WithValue(1.0).
+					WithDisk(&mesos.Resource_DiskInfo{
+						Persistence: &mesos.Resource_DiskInfo_Persistence{
+							Id: &_testKey,
+						},
+					}).
+					Build(),
+			},
+		},
+	)
+	suite.NoError(err)
+	suite.Nil(resp.GetError())
+	suite.Equal(volume.VolumeState_DELETED, volumeInfo.GetState())
+	suite.Equal(volume.VolumeState_DELETED, volumeInfo.GetGoalState())
+	suite.Equal(
+		int64(1),
+		suite.testScope.Snapshot().Counters()["destroy_volumes+"].Value())
+}
+
+// TestDestroyVolumesNotOffered tests destroying a volume which is not
+// offered only updates its goal state.
+func (suite *HostMgrHandlerTestSuite) TestDestroyVolumesNotOffered() {
+	defer suite.ctrl.Finish()
+
+	volumeInfo := &volume.PersistentVolumeInfo{
+		Id:        &peloton.VolumeID{Value: _testKey},
+		State:     volume.VolumeState_CREATED,
+		GoalState: volume.VolumeState_CREATED,
+	}
+	suite.volumeStore.EXPECT().
+		GetPersistentVolume(gomock.Any(), gomock.Any()).
+		Return(volumeInfo, nil)
+	suite.volumeStore.EXPECT().
+		UpdatePersistentVolume(gomock.Any(), volumeInfo).
+		Return(nil)
+
+	resp, err := suite.handler.DestroyVolumes(
+		rootCtx,
+		&hostsvc.DestroyVolumesRequest{
+			Hostname: "hostname-0",
+			Volumes: []*mesos.Resource{
+				util.NewMesosResourceBuilder().
+					WithName("disk").
+					WithValue(1.0).
+					WithDisk(&mesos.Resource_DiskInfo{
+						Persistence: &mesos.Resource_DiskInfo_Persistence{
+							Id: &_testKey,
+						},
+					}).
+					Build(),
+			},
+		},
+	)
+	suite.NoError(err)
+	suite.Nil(resp.GetError())
+	suite.Equal(volume.VolumeState_CREATED, volumeInfo.GetState())
+	suite.Equal(volume.VolumeState_DELETED, volumeInfo.GetGoalState())
+}
+
 func (suite *HostMgrHandlerTestSuite) TestGetMesosMasterHostPort() {
 	defer suite.ctrl.Finish()
 
@@ -2155,6 +2603,10 @@ func (suite *HostMgrHandlerTestSuite) TestServiceHandlerMarkHostsDrained() {
 			GetDrainingHostInfos([]string{}).
 			Return(hostInfos),
 
+		suite.offerEventHandler.EXPECT().
+			MarkHostsDrained([]string{"testhost"}).
+			Return(nil),
+
 		suite.masterOperatorClient.EXPECT().
 			StartMaintenance(gomock.Any()).
 			Return(nil).
@@ -2188,6 +2640,9 @@ func (suite *HostMgrHandlerTestSuite) TestServiceHandlerMarkHostsDrained() {
 	suite.maintenanceHostInfoMap.EXPECT().
 		GetDrainingHostInfos([]string{}).
 		Return([]*hpb.HostInfo{})
+	suite.offerEventHandler.EXPECT().
+		MarkHostsDrained(gomock.Nil()).
+		Return(nil)
 
 	resp, err = suite.handler.MarkHostsDrained(
 		context.Background(),
@@ -2207,6 +2662,9 @@ func (suite *HostMgrHandlerTestSuite) TestServiceHandlerMarkHostsDrained() {
 			},
 		})
 
+	suite.offerEventHandler.EXPECT().
+		MarkHostsDrained([]string{"host1"}).
+		Return(nil)
 	suite.masterOperatorClient.EXPECT().
 		StartMaintenance(gomock.Any()).
 		Return(fmt.Errorf("fake StartMaintenance error"))
@@ -2219,6 +2677,70 @@ func (suite *HostMgrHandlerTestSuite) TestServiceHandlerMarkHostsDrained() {
 	suite.Nil(resp.GetMarkedHosts())
 }
 
+// TestServiceHandlerMarkHostsDrainedInverseOffer tests that a host draining
+// for a Mesos inverse offer is marked as drained without being put down.
+func (suite *HostMgrHandlerTestSuite) TestServiceHandlerMarkHostsDrainedInverseOffer() {
+	defer suite.ctrl.Finish()
+
+	hostInfos := []*hpb.HostInfo{
+		{
+			Hostname: "host1",
+			Ip:       "0.0.0.1",
+			State:    hpb.HostState_HOST_STATE_DRAINING,
+		},
+		{
+			Hostname: "host2",
+			Ip:       "0.0.0.2",
+			State:    hpb.HostState_HOST_STATE_DRAINING,
+		},
+	}
+
+	gomock.InOrder(
+		suite.maintenanceHostInfoMap.EXPECT().
+			GetDrainingHostInfos([]string{}).
+			Return(hostInfos),
+		suite.offerEventHandler.EXPECT().
+			MarkHostsDrained([]string{"host1", "host2"}).
+			Return([]string{"host1"}),
+		suite.masterOperatorClient.EXPECT().
+			StartMaintenance(gomock.Any()).
+			Do(func(machineIds []*mesos.MachineID) {
+				suite.Len(machineIds, 1)
+				suite.Equal("host2", machineIds[0].GetHostname())
+			}).
+			Return(nil),
+		suite.maintenanceHostInfoMap.EXPECT().UpdateHostState(
+			"host2",
+			hpb.HostState_HOST_STATE_DRAINING,
+			hpb.HostState_HOST_STATE_DOWN).
+			Return(nil),
+	)
+
+	resp, err := suite.handler.MarkHostsDrained(
+		context.Background(),
+		&hostsvc.MarkHostsDrainedRequest{
+			Hostnames: []string{"host1", "host2"},
+		})
+	suite.NoError(err)
+	suite.Equal([]string{"host1", "host2"}, resp.GetMarkedHosts())
+
+	// all hosts are draining for inverse offers
+	suite.maintenanceHostInfoMap.EXPECT().
+		GetDrainingHostInfos([]string{}).
+		Return(hostInfos[:1])
+	suite.offerEventHandler.EXPECT().
+		MarkHostsDrained([]string{"host1"}).
+		Return([]string{"host1"})
+
+	resp, err = suite.handler.MarkHostsDrained(
+		context.Background(),
+		&hostsvc.MarkHostsDrainedRequest{
+			Hostnames: []string{"host1"},
+		})
+	suite.NoError(err)
+	suite.Equal([]string{"host1"}, resp.GetMarkedHosts())
+}
+
 func getAcquireHostOffersRequest() *hostsvc.AcquireHostOffersRequest {
 	return &hostsvc.AcquireHostOffersRequest{
 		Filter: &hostsvc.HostFilter{
@@ -2319,6 +2841,16 @@ func makeAgentsResponse(numAgents int) *mesos_master.Response_GetAgents {
 				WithName(_gpuName).
 				WithValue(resVal).
 				Build(),
+			util.NewMesosResourceBuilder().
+				WithName(common.MesosNetworkBandwidth).
+				WithValue(resVal).
+				Build(),
+			util.NewMesosResourceBuilder().
+				WithName(common.MesosPorts).
+				WithType(mesos.Value_RANGES).
+				WithRanges(util.CreatePortRanges(
+					map[uint32]bool{31000: true})).
+				Build(),
 			util.NewMesosResourceBuilder().
 				WithName(_cpuName).
 				WithValue(resVal).
@@ -2570,6 +3102,25 @@ func (suite *HostMgrHandlerTestSuite) TestGetCompletedReservationsError() {
 	suite.Equal(reservations.GetError().GetNotFound().Message, "error")
 }
 
+// TestGetHostReservations tests the outstanding host reservations
+func (suite *HostMgrHandlerTestSuite) TestGetHostReservations() {
+	ctrl := gomock.NewController(suite.T())
+	handler := &ServiceHandler{}
+	mockReserver := reserver_mocks.NewMockReserver(ctrl)
+	handler.reserver = mockReserver
+	mockReserver.EXPECT().GetReservations(uint32(10)).Return(
+		[]*hostsvc.HostReservation{
+			{Hostname: "host1"},
+		})
+	resp, err := handler.GetHostReservations(
+		context.Background(),
+		&hostsvc.GetHostReservationsRequest{MinPriority: 10})
+
+	suite.NoError(err)
+	suite.Equal(1, len(resp.GetReservations()))
+	suite.Equal("host1", resp.GetReservations()[0].GetHostname())
+}
+
 // Test OfferOperations errors
 func (suite *HostMgrHandlerTestSuite) TestOfferOperationsError() {
 	launchOperation := &hostsvc.OfferOperation{
@@ -23,6 +23,7 @@ import (
 	host "github.com/uber/peloton/.gen/peloton/api/v0/host"
 
 	"github.com/uber/peloton/pkg/common"
+	"github.com/uber/peloton/pkg/common/gpu"
 	"github.com/uber/peloton/pkg/hostmgr/mesos/yarpc/encoding/mpb"
 	"github.com/uber/peloton/pkg/hostmgr/scalar"
 	"github.com/uber/peloton/pkg/hostmgr/util"
@@ -41,6 +42,10 @@ type AgentMap struct {
 
 	Capacity      scalar.Resources
 	SlackCapacity scalar.Resources
+
+	// GPUModelCapacity is the number of GPUs per GPU model, as parsed from
+	// the gpu.model attribute of the agents.
+	GPUModelCapacity map[string]float64
 }
 
 // ReportCapacityMetrics into given metric scope.
@@ -49,6 +54,8 @@ func (a *AgentMap) ReportCapacityMetrics(scope tally.Scope) {
 	scope.Gauge(common.MesosMem).Update(a.Capacity.GetMem())
 	scope.Gauge(common.MesosDisk).Update(a.Capacity.GetDisk())
 	scope.Gauge(common.MesosGPU).Update(a.Capacity.GetGPU())
+	scope.Gauge(common.MesosPorts).Update(a.Capacity.GetPorts())
+	scope.Gauge(common.MesosNetworkBandwidth).Update(a.Capacity.GetNetwork())
 	scope.Gauge("cpus_revocable").Update(a.SlackCapacity.GetCPU())
 	scope.Gauge("registered_hosts").Update(float64(len(a.RegisteredAgents)))
 }
@@ -102,6 +109,7 @@ func (loader *Loader) Load(_ *uatomic.Bool) {
 		RegisteredAgents: make(map[string]*mesos_master.Response_GetAgents_Agent),
 		Capacity:         scalar.Resources{},
 		SlackCapacity:    scalar.Resources{},
+		GPUModelCapacity: make(map[string]float64),
 	}
 
 	outchan := make(chan func() (scalar.Resources, scalar.Resources))
@@ -114,6 +122,12 @@ func (loader *Loader) Load(_ *uatomic.Bool) {
 		}
 		m.RegisteredAgents[hostname] = agent
 		count++
+		topology := gpu.FromAttributes(
+			agent.GetAgentInfo().GetAttributes(),
+			scalar.FromMesosResources(agent.GetTotalResources()).GetGPU())
+		if topology.Model != "" && topology.Count > 0 {
+			m.GPUModelCapacity[topology.Model] += topology.Count
+		}
 		go getResourcesByType(
 			agent.GetTotalResources(),
 			outchan,
@@ -24,6 +24,7 @@ import (
 	host "github.com/uber/peloton/.gen/peloton/api/v0/host"
 
 	"github.com/uber/peloton/pkg/common"
+	"github.com/uber/peloton/pkg/common/gpu"
 	"github.com/uber/peloton/pkg/common/util"
 	hm "github.com/uber/peloton/pkg/hostmgr/host/mocks"
 	mock_mpb "github.com/uber/peloton/pkg/hostmgr/mesos/yarpc/encoding/mpb/mocks"
@@ -35,6 +36,7 @@ import (
 
 var (
 	_defaultResourceValue = 1
+	_gpuModel             = "tesla-v100"
 )
 
 type HostMapTestSuite struct {
@@ -86,10 +88,18 @@ func makeAgentsResponse(numAgents int) *mesos_master.Response_GetAgents {
 				WithRevocable(&mesos.Resource_RevocableInfo{}).
 				Build(),
 		}
+		gpuModelAttr := gpu.ModelAttribute
 		getAgent := &mesos_master.Response_GetAgents_Agent{
 			AgentInfo: &mesos.AgentInfo{
 				Hostname:  &tmpID,
 				Resources: resources,
+				Attributes: []*mesos.Attribute{
+					{
+						Name: &gpuModelAttr,
+						Type: mesos.Value_TEXT.Enum(),
+						Text: &mesos.Value_Text{Value: &_gpuModel},
+					},
+				},
 			},
 			TotalResources: resources,
 		}
@@ -140,6 +150,11 @@ func (suite *HostMapTestSuite) TestRefresh() {
 	numRegisteredAgents := numAgents - 1
 	m := GetAgentMap()
 	suite.Len(m.RegisteredAgents, numRegisteredAgents)
+	suite.Equal(
+		map[string]float64{
+			_gpuModel: float64(numRegisteredAgents * _defaultResourceValue),
+		},
+		m.GPUModelCapacity)
 
 	id1 := "id-1"
 	a1 := GetAgentInfo(id1)
@@ -63,6 +63,20 @@ type Metrics struct {
 	OfferOperationsInvalid       tally.Counter
 	OfferOperationsInvalidOffers tally.Counter
 
+	ReserveResources          tally.Counter
+	ReserveResourcesFail      tally.Counter
+	ReserveResourcesInvalid   tally.Counter
+	UnreserveResources        tally.Counter
+	UnreserveResourcesFail    tally.Counter
+	UnreserveResourcesInvalid tally.Counter
+	CreateVolumes             tally.Counter
+	CreateVolumesFail         tally.Counter
+	CreateVolumesInvalid      tally.Counter
+	DestroyVolumes            tally.Counter
+	DestroyVolumesFail        tally.Counter
+	DestroyVolumesInvalid     tally.Counter
+	ReservationOperationsNoop tally.Counter
+
 	RecoverySuccess tally.Counter
 	RecoveryFail    tally.Counter
 
@@ -89,6 +103,20 @@ func NewMetrics(scope tally.Scope) *Metrics {
 		OfferOperationsInvalid:       scope.Counter("offer_operations_invalid"),
 		OfferOperationsInvalidOffers: scope.Counter("offer_operations_invalid_offers"),
 
+		ReserveResources:          scope.Counter("reserve_resources"),
+		ReserveResourcesFail:      scope.Counter("reserve_resources_fail"),
+		ReserveResourcesInvalid:   scope.Counter("reserve_resources_invalid"),
+		UnreserveResources:        scope.Counter("unreserve_resources"),
+		UnreserveResourcesFail:    scope.Counter("unreserve_resources_fail"),
+		UnreserveResourcesInvalid: scope.Counter("unreserve_resources_invalid"),
+		CreateVolumes:             scope.Counter("create_volumes"),
+		CreateVolumesFail:         scope.Counter("create_volumes_fail"),
+		CreateVolumesInvalid:      scope.Counter("create_volumes_invalid"),
+		DestroyVolumes:            scope.Counter("destroy_volumes"),
+		DestroyVolumesFail:        scope.Counter("destroy_volumes_fail"),
+		DestroyVolumesInvalid:     scope.Counter("destroy_volumes_invalid"),
+		ReservationOperationsNoop: scope.Counter("reservation_operations_noop"),
+
 		AcquireHostOffers:        scope.Counter("acquire_host_offers"),
 		AcquireHostOffersFail:    scope.Counter("acquire_host_offers_fail"),
 		AcquireHostOffersInvalid: scope.Counter("acquire_host_offers_invalid"),
@@ -26,6 +26,7 @@ import (
 	sched "github.com/uber/peloton/.gen/mesos/v1/scheduler"
 	"github.com/uber/peloton/pkg/common/background"
 	"github.com/uber/peloton/pkg/hostmgr/binpacking"
+	"github.com/uber/peloton/pkg/hostmgr/host"
 	hostmgr_mesos "github.com/uber/peloton/pkg/hostmgr/mesos"
 	"github.com/uber/peloton/pkg/hostmgr/mesos/yarpc/encoding/mpb"
 	"github.com/uber/peloton/pkg/hostmgr/offer/offerpool"
@@ -60,13 +61,20 @@ type EventHandler interface {
 
 	// GetOfferPool returns the underlying Pool holding the offers.
 	GetOfferPool() offerpool.Pool
+
+	// MarkHostsDrained marks the hosts draining for Mesos inverse offers
+	// as drained, so that their inverse offers are accepted, and returns
+	// the ones which must not be put down as they are out of the
+	// unavailability of their inverse offers.
+	MarkHostsDrained(hostnames []string) []string
 }
 
 // eventHandler is the handler for Mesos Offer events
 type eventHandler struct {
-	offerPool   offerpool.Pool
-	offerPruner Pruner
-	metrics     *offerpool.Metrics
+	offerPool             offerpool.Pool
+	offerPruner           Pruner
+	inverseOfferProcessor *inverseOfferProcessor
+	metrics               *offerpool.Metrics
 }
 
 // Singleton event handler for offers
@@ -87,7 +95,8 @@ func InitEventHandler(
 	slackResourceTypes []string,
 	ranker binpacking.Ranker,
 	binPackingRefreshIntervalSec time.Duration,
-	hostPlacingOfferStatusTimeout time.Duration) {
+	hostPlacingOfferStatusTimeout time.Duration,
+	maintenanceHostInfoMap host.MaintenanceHostInfoMap) {
 
 	if handler != nil {
 		log.Warning("Offer event handler has already been initialized")
@@ -168,7 +177,13 @@ func InitEventHandler(
 	handler = &eventHandler{
 		offerPool:   pool,
 		offerPruner: NewOfferPruner(pool, offerPruningPeriod, metrics),
-		metrics:     metrics,
+		inverseOfferProcessor: newInverseOfferProcessor(
+			maintenanceHostInfoMap,
+			schedulerClient,
+			hostmgr_mesos.GetSchedulerDriver(),
+			parent,
+		),
+		metrics: metrics,
 	}
 	procedures := map[sched.Event_Type]interface{}{
 		sched.Event_OFFERS:                handler.Offers,
@@ -210,9 +225,8 @@ func (h *eventHandler) InverseOffers(ctx context.Context, body *sched.Event) err
 	event := body.GetInverseOffers()
 	log.WithField("event", event).
 		Debug("OfferManager: processing InverseOffers event")
-
-	// TODO: Handle inverse offers from Mesos
-	return nil
+	return h.inverseOfferProcessor.AddInverseOffers(
+		ctx, event.GetInverseOffers())
 }
 
 // Rescind offers
@@ -231,8 +245,8 @@ func (h *eventHandler) RescindInverseOffer(ctx context.Context, body *sched.Even
 	event := body.GetRescindInverseOffer()
 	log.WithField("event", event).
 		Debug("OfferManager: processing RescindInverseOffer event")
+	h.inverseOfferProcessor.RescindInverseOffer(event.GetInverseOfferId())
 
-	// TODO: handle rescind inverse offer events
 	return nil
 }
 
@@ -241,6 +255,12 @@ func (h *eventHandler) GetOfferPool() offerpool.Pool {
 	return h.offerPool
 }
 
+// MarkHostsDrained marks the hosts draining for Mesos inverse offers
+// as drained.
+func (h *eventHandler) MarkHostsDrained(hostnames []string) []string {
+	return h.inverseOfferProcessor.MarkHostsDrained(hostnames)
+}
+
 // Start runs startup related procedures
 func (h *eventHandler) Start() error {
 	// Start offer pruner
@@ -254,6 +274,9 @@ func (h *eventHandler) Start() error {
 func (h *eventHandler) Stop() error {
 	// Clean up all existing offers
 	h.offerPool.Clear()
+	// Clean up outstanding inverse offers, they are resent
+	// by Mesos master on subscription of the new leader
+	h.inverseOfferProcessor.Clear()
 	// Stop offer pruner
 	h.offerPruner.Stop()
 
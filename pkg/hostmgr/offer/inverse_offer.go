@@ -0,0 +1,304 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	mesos "github.com/uber/peloton/.gen/mesos/v1"
+	sched "github.com/uber/peloton/.gen/mesos/v1/scheduler"
+
+	"github.com/uber/peloton/pkg/hostmgr/host"
+	hostmgr_mesos "github.com/uber/peloton/pkg/hostmgr/mesos"
+	"github.com/uber/peloton/pkg/hostmgr/mesos/yarpc/encoding/mpb"
+
+	"github.com/golang/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+	"github.com/uber-go/tally"
+)
+
+// _inverseOfferRefuseSeconds is the duration for which Mesos master
+// should not resend an inverse offer after it is declined, so that the
+// drain progress of the host is checked again when it is resent.
+const _inverseOfferRefuseSeconds = float64(60)
+
+// inverseOfferMetrics tracks the inverse offers processed by hostmgr.
+type inverseOfferMetrics struct {
+	InverseOffers        tally.Counter
+	RescindInverseOffers tally.Counter
+	Accept               tally.Counter
+	AcceptFail           tally.Counter
+	Decline              tally.Counter
+	DeclineFail          tally.Counter
+}
+
+func newInverseOfferMetrics(scope tally.Scope) *inverseOfferMetrics {
+	inverseOfferScope := scope.SubScope("inverse_offers")
+	return &inverseOfferMetrics{
+		InverseOffers:        inverseOfferScope.Counter("received"),
+		RescindInverseOffers: inverseOfferScope.Counter("rescind"),
+		Accept:               inverseOfferScope.Counter("accept"),
+		AcceptFail:           inverseOfferScope.Counter("accept_fail"),
+		Decline:              inverseOfferScope.Counter("decline"),
+		DeclineFail:          inverseOfferScope.Counter("decline_fail"),
+	}
+}
+
+// inverseOfferProcessor responds to the inverse offers sent by Mesos
+// master for hosts scheduled for maintenance. The hosts themselves are
+// moved to DRAINING and enqueued into the maintenance queue by the host
+// drainer, which reconciles the draining machines with Mesos master, so
+// that their tasks get migrated. The inverse offer of a host is declined
+// while tasks remain on it, and accepted once the resmgr drainer has
+// marked the host as drained. A drained host is only put down once the
+// unavailability of its inverse offer has started.
+type inverseOfferProcessor struct {
+	sync.Mutex
+
+	// inverse offer id -> hostname of outstanding inverse offers
+	inverseOffers map[string]string
+	// hostname -> unavailability of the hosts with an inverse offer
+	unavailabilities map[string]*mesos.Unavailability
+	// hosts drained for an inverse offer, with no tasks left on them
+	drainedHosts map[string]struct{}
+
+	maintenanceHostInfoMap     host.MaintenanceHostInfoMap
+	schedulerClient            mpb.SchedulerClient
+	mesosFrameworkInfoProvider hostmgr_mesos.FrameworkInfoProvider
+	metrics                    *inverseOfferMetrics
+}
+
+// newInverseOfferProcessor returns a new inverseOfferProcessor
+func newInverseOfferProcessor(
+	maintenanceHostInfoMap host.MaintenanceHostInfoMap,
+	schedulerClient mpb.SchedulerClient,
+	frameworkInfoProvider hostmgr_mesos.FrameworkInfoProvider,
+	scope tally.Scope,
+) *inverseOfferProcessor {
+	return &inverseOfferProcessor{
+		inverseOffers:              make(map[string]string),
+		unavailabilities:           make(map[string]*mesos.Unavailability),
+		drainedHosts:               make(map[string]struct{}),
+		maintenanceHostInfoMap:     maintenanceHostInfoMap,
+		schedulerClient:            schedulerClient,
+		mesosFrameworkInfoProvider: frameworkInfoProvider,
+		metrics:                    newInverseOfferMetrics(scope),
+	}
+}
+
+// AddInverseOffers processes the inverse offers sent by Mesos master.
+func (p *inverseOfferProcessor) AddInverseOffers(
+	ctx context.Context,
+	inverseOffers []*mesos.InverseOffer) error {
+	p.Lock()
+	defer p.Unlock()
+
+	p.metrics.InverseOffers.Inc(int64(len(inverseOffers)))
+
+	var accepted, declined []*mesos.OfferID
+	for _, inverseOffer := range inverseOffers {
+		hostname := getInverseOfferHostname(inverseOffer)
+		if hostname == "" {
+			log.WithField("inverse_offer", inverseOffer).
+				Warn("unable to find host of inverse offer")
+			declined = append(declined, inverseOffer.GetId())
+			continue
+		}
+		p.inverseOffers[inverseOffer.GetId().GetValue()] = hostname
+		p.unavailabilities[hostname] = inverseOffer.GetUnavailability()
+
+		// the host has no tasks left or is already in maintenance,
+		// it can be safely made unavailable
+		if _, ok := p.drainedHosts[hostname]; ok ||
+			len(p.maintenanceHostInfoMap.GetDownHostInfos(
+				[]string{hostname})) > 0 {
+			accepted = append(accepted, inverseOffer.GetId())
+			continue
+		}
+
+		// tasks remain on the host, which is drained by the host drainer
+		declined = append(declined, inverseOffer.GetId())
+	}
+
+	if len(accepted) > 0 {
+		if err := p.acceptInverseOffers(ctx, accepted); err != nil {
+			return err
+		}
+	}
+	if len(declined) > 0 {
+		if err := p.declineInverseOffers(ctx, declined); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RescindInverseOffer processes an inverse offer rescinded by Mesos master,
+// which happens when the host goes down or when its maintenance is
+// removed from the maintenance schedule. In the latter case, the host
+// is brought back UP by the host drainer.
+func (p *inverseOfferProcessor) RescindInverseOffer(
+	inverseOfferID *mesos.OfferID) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.metrics.RescindInverseOffers.Inc(1)
+
+	hostname, ok := p.inverseOffers[inverseOfferID.GetValue()]
+	if !ok {
+		return
+	}
+	delete(p.inverseOffers, inverseOfferID.GetValue())
+
+	// another inverse offer is outstanding for the host
+	for _, h := range p.inverseOffers {
+		if h == hostname {
+			return
+		}
+	}
+
+	delete(p.unavailabilities, hostname)
+	delete(p.drainedHosts, hostname)
+}
+
+// Clear removes all outstanding inverse offers.
+func (p *inverseOfferProcessor) Clear() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.inverseOffers = make(map[string]string)
+	p.unavailabilities = make(map[string]*mesos.Unavailability)
+	p.drainedHosts = make(map[string]struct{})
+}
+
+// MarkHostsDrained marks the hosts with an inverse offer as drained, so
+// that their inverse offers are accepted when they are resent by Mesos
+// master. It returns the drained hosts whose unavailability has not
+// started yet, or is already over, which must not be put down, the other
+// hosts can be put down right away.
+func (p *inverseOfferProcessor) MarkHostsDrained(hostnames []string) []string {
+	p.Lock()
+	defer p.Unlock()
+
+	now := time.Now()
+	var drained []string
+	for _, hostname := range hostnames {
+		unavailability, ok := p.unavailabilities[hostname]
+		if !ok {
+			continue
+		}
+		p.drainedHosts[hostname] = struct{}{}
+		if !isUnavailable(unavailability, now) {
+			drained = append(drained, hostname)
+		}
+	}
+	return drained
+}
+
+// acceptInverseOffers calls mesos master to accept the inverse offers
+func (p *inverseOfferProcessor) acceptInverseOffers(
+	ctx context.Context,
+	inverseOfferIDs []*mesos.OfferID) error {
+	callType := sched.Call_ACCEPT_INVERSE_OFFERS
+	msg := &sched.Call{
+		FrameworkId: p.mesosFrameworkInfoProvider.GetFrameworkID(ctx),
+		Type:        &callType,
+		AcceptInverseOffers: &sched.Call_AcceptInverseOffers{
+			InverseOfferIds: inverseOfferIDs,
+		},
+	}
+	msid := p.mesosFrameworkInfoProvider.GetMesosStreamID(ctx)
+	if err := p.schedulerClient.Call(msid, msg); err != nil {
+		log.WithError(err).
+			WithField("call", msg).
+			Warn("failed to accept inverse offers")
+		p.metrics.AcceptFail.Inc(1)
+		return err
+	}
+
+	p.metrics.Accept.Inc(int64(len(inverseOfferIDs)))
+	for _, inverseOfferID := range inverseOfferIDs {
+		delete(p.inverseOffers, inverseOfferID.GetValue())
+	}
+	return nil
+}
+
+// declineInverseOffers calls mesos master to decline the inverse offers
+func (p *inverseOfferProcessor) declineInverseOffers(
+	ctx context.Context,
+	inverseOfferIDs []*mesos.OfferID) error {
+	callType := sched.Call_DECLINE_INVERSE_OFFERS
+	msg := &sched.Call{
+		FrameworkId: p.mesosFrameworkInfoProvider.GetFrameworkID(ctx),
+		Type:        &callType,
+		DeclineInverseOffers: &sched.Call_DeclineInverseOffers{
+			InverseOfferIds: inverseOfferIDs,
+			Filters: &mesos.Filters{
+				RefuseSeconds: proto.Float64(_inverseOfferRefuseSeconds),
+			},
+		},
+	}
+	msid := p.mesosFrameworkInfoProvider.GetMesosStreamID(ctx)
+	if err := p.schedulerClient.Call(msid, msg); err != nil {
+		log.WithError(err).
+			WithField("call", msg).
+			Warn("failed to decline inverse offers")
+		p.metrics.DeclineFail.Inc(1)
+		return err
+	}
+
+	p.metrics.Decline.Inc(int64(len(inverseOfferIDs)))
+	return nil
+}
+
+// getInverseOfferHostname returns the hostname of the inverse offer,
+// looking up the agent map if the inverse offer has no URL.
+func getInverseOfferHostname(inverseOffer *mesos.InverseOffer) string {
+	if address := inverseOffer.GetUrl().GetAddress(); address != nil {
+		return address.GetHostname()
+	}
+
+	agentMap := host.GetAgentMap()
+	if agentMap == nil {
+		return ""
+	}
+	for hostname, agent := range agentMap.RegisteredAgents {
+		if agent.GetAgentInfo().GetId().GetValue() ==
+			inverseOffer.GetAgentId().GetValue() {
+			return hostname
+		}
+	}
+	return ""
+}
+
+// isUnavailable returns whether the given time is within the
+// unavailability of an inverse offer. An unavailability without
+// duration lasts forever.
+func isUnavailable(unavailability *mesos.Unavailability, now time.Time) bool {
+	if unavailability == nil {
+		return true
+	}
+	start := time.Unix(0, unavailability.GetStart().GetNanoseconds())
+	if now.Before(start) {
+		return false
+	}
+	if unavailability.GetDuration() == nil {
+		return true
+	}
+	return now.Before(start.Add(
+		time.Duration(unavailability.GetDuration().GetNanoseconds())))
+}
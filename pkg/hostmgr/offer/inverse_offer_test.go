@@ -0,0 +1,289 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mesos "github.com/uber/peloton/.gen/mesos/v1"
+	sched "github.com/uber/peloton/.gen/mesos/v1/scheduler"
+	hpb "github.com/uber/peloton/.gen/peloton/api/v0/host"
+
+	host_mocks "github.com/uber/peloton/pkg/hostmgr/host/mocks"
+	hostmgr_mesos_mocks "github.com/uber/peloton/pkg/hostmgr/mesos/mocks"
+	mpb_mocks "github.com/uber/peloton/pkg/hostmgr/mesos/yarpc/encoding/mpb/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+)
+
+const (
+	_testHostname     = "hostname"
+	_testIP           = "1.2.3.4"
+	_testInverseOffer = "inverse-offer"
+	_testStreamID     = "stream-id"
+)
+
+type InverseOfferTestSuite struct {
+	suite.Suite
+
+	ctrl                   *gomock.Controller
+	maintenanceHostInfoMap *host_mocks.MockMaintenanceHostInfoMap
+	schedulerClient        *mpb_mocks.MockSchedulerClient
+	frameworkInfoProvider  *hostmgr_mesos_mocks.MockFrameworkInfoProvider
+
+	frameworkID *mesos.FrameworkID
+	processor   *inverseOfferProcessor
+}
+
+func (suite *InverseOfferTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.maintenanceHostInfoMap = host_mocks.NewMockMaintenanceHostInfoMap(suite.ctrl)
+	suite.schedulerClient = mpb_mocks.NewMockSchedulerClient(suite.ctrl)
+	suite.frameworkInfoProvider = hostmgr_mesos_mocks.NewMockFrameworkInfoProvider(suite.ctrl)
+	suite.frameworkID = &mesos.FrameworkID{Value: proto.String("framework")}
+
+	suite.frameworkInfoProvider.EXPECT().
+		GetFrameworkID(gomock.Any()).
+		Return(suite.frameworkID).
+		AnyTimes()
+	suite.frameworkInfoProvider.EXPECT().
+		GetMesosStreamID(gomock.Any()).
+		Return(_testStreamID).
+		AnyTimes()
+
+	suite.processor = newInverseOfferProcessor(
+		suite.maintenanceHostInfoMap,
+		suite.schedulerClient,
+		suite.frameworkInfoProvider,
+		tally.NoopScope,
+	)
+}
+
+func (suite *InverseOfferTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func TestInverseOfferTestSuite(t *testing.T) {
+	suite.Run(t, new(InverseOfferTestSuite))
+}
+
+// newUnavailability returns an unavailability starting at the given
+// time, lasting for the given duration if it is not zero
+func newUnavailability(
+	start time.Time,
+	duration time.Duration) *mesos.Unavailability {
+	unavailability := &mesos.Unavailability{
+		Start: &mesos.TimeInfo{Nanoseconds: proto.Int64(start.UnixNano())},
+	}
+	if duration != 0 {
+		unavailability.Duration = &mesos.DurationInfo{
+			Nanoseconds: proto.Int64(duration.Nanoseconds()),
+		}
+	}
+	return unavailability
+}
+
+// newInverseOffer returns an inverse offer for the test host
+// whose unavailability starts in an hour
+func newInverseOffer() *mesos.InverseOffer {
+	return &mesos.InverseOffer{
+		Id: &mesos.OfferID{Value: proto.String(_testInverseOffer)},
+		Url: &mesos.URL{
+			Address: &mesos.Address{
+				Hostname: proto.String(_testHostname),
+				Ip:       proto.String(_testIP),
+			},
+		},
+		Unavailability: newUnavailability(time.Now().Add(time.Hour), 0),
+	}
+}
+
+// expectMasterCall sets the expectation of a call of the given type
+// to the inverse offer to the simulated Mesos master.
+func (suite *InverseOfferTestSuite) expectMasterCall(
+	callType sched.Call_Type,
+	err error) {
+	suite.schedulerClient.EXPECT().
+		Call(_testStreamID, gomock.Any()).
+		Do(func(_ string, call *sched.Call) {
+			suite.Equal(callType, call.GetType())
+			suite.Equal(suite.frameworkID, call.GetFrameworkId())
+			var ids []*mesos.OfferID
+			if callType == sched.Call_ACCEPT_INVERSE_OFFERS {
+				ids = call.GetAcceptInverseOffers().GetInverseOfferIds()
+			} else {
+				ids = call.GetDeclineInverseOffers().GetInverseOfferIds()
+			}
+			suite.Len(ids, 1)
+			suite.Equal(_testInverseOffer, ids[0].GetValue())
+		}).
+		Return(err)
+}
+
+// TestInverseOfferHostDraining tests that an inverse offer for a host
+// with tasks left is declined, leaving the host to the host drainer
+func (suite *InverseOfferTestSuite) TestInverseOfferHostDraining() {
+	inverseOffer := newInverseOffer()
+
+	suite.maintenanceHostInfoMap.EXPECT().
+		GetDownHostInfos([]string{_testHostname}).
+		Return(nil)
+	suite.expectMasterCall(sched.Call_DECLINE_INVERSE_OFFERS, nil)
+
+	suite.NoError(suite.processor.AddInverseOffers(
+		context.Background(),
+		[]*mesos.InverseOffer{inverseOffer},
+	))
+	suite.Equal(_testHostname, suite.processor.inverseOffers[_testInverseOffer])
+	suite.Equal(
+		inverseOffer.GetUnavailability(),
+		suite.processor.unavailabilities[_testHostname])
+}
+
+// TestInverseOfferHostDrained tests that an inverse offer for a
+// host which has no tasks left is accepted
+func (suite *InverseOfferTestSuite) TestInverseOfferHostDrained() {
+	suite.processor.drainedHosts[_testHostname] = struct{}{}
+	suite.expectMasterCall(sched.Call_ACCEPT_INVERSE_OFFERS, nil)
+
+	suite.NoError(suite.processor.AddInverseOffers(
+		context.Background(),
+		[]*mesos.InverseOffer{newInverseOffer()},
+	))
+	suite.Empty(suite.processor.inverseOffers)
+}
+
+// TestInverseOfferDrainProgress tests that the inverse offer of a host
+// is declined while tasks remain on it, and accepted once the host
+// has been marked as drained
+func (suite *InverseOfferTestSuite) TestInverseOfferDrainProgress() {
+	suite.maintenanceHostInfoMap.EXPECT().
+		GetDownHostInfos([]string{_testHostname}).
+		Return(nil)
+	suite.expectMasterCall(sched.Call_DECLINE_INVERSE_OFFERS, nil)
+	suite.NoError(suite.processor.AddInverseOffers(
+		context.Background(),
+		[]*mesos.InverseOffer{newInverseOffer()},
+	))
+
+	// the unavailability has not started, the host is not put down
+	suite.Equal(
+		[]string{_testHostname},
+		suite.processor.MarkHostsDrained([]string{_testHostname}))
+
+	suite.expectMasterCall(sched.Call_ACCEPT_INVERSE_OFFERS, nil)
+	suite.NoError(suite.processor.AddInverseOffers(
+		context.Background(),
+		[]*mesos.InverseOffer{newInverseOffer()},
+	))
+	suite.Empty(suite.processor.inverseOffers)
+}
+
+// TestMarkHostsDrained tests that the hosts with an inverse offer are
+// marked as drained, and that only the ones out of the unavailability
+// of their inverse offer are not put down
+func (suite *InverseOfferTestSuite) TestMarkHostsDrained() {
+	now := time.Now()
+	suite.processor.unavailabilities = map[string]*mesos.Unavailability{
+		"pending":  newUnavailability(now.Add(time.Hour), 0),
+		"started":  newUnavailability(now.Add(-time.Hour), 0),
+		"ongoing":  newUnavailability(now.Add(-time.Hour), 2*time.Hour),
+		"over":     newUnavailability(now.Add(-2*time.Hour), time.Hour),
+		"no-start": nil,
+	}
+
+	suite.Equal(
+		[]string{"pending", "over"},
+		suite.processor.MarkHostsDrained([]string{
+			"pending", "started", "ongoing", "over", "no-start", "other",
+		}))
+	suite.Len(suite.processor.drainedHosts, 5)
+	suite.NotContains(suite.processor.drainedHosts, "other")
+	suite.Empty(suite.processor.MarkHostsDrained([]string{"other"}))
+}
+
+// TestInverseOfferHostDown tests that an inverse offer for a
+// host which is already DOWN is accepted
+func (suite *InverseOfferTestSuite) TestInverseOfferHostDown() {
+	suite.maintenanceHostInfoMap.EXPECT().
+		GetDownHostInfos([]string{_testHostname}).
+		Return([]*hpb.HostInfo{{Hostname: _testHostname}})
+	suite.expectMasterCall(sched.Call_ACCEPT_INVERSE_OFFERS, nil)
+
+	suite.NoError(suite.processor.AddInverseOffers(
+		context.Background(),
+		[]*mesos.InverseOffer{newInverseOffer()},
+	))
+	suite.Empty(suite.processor.inverseOffers)
+}
+
+// TestInverseOfferMasterCallFail tests failing to respond
+// to an inverse offer
+func (suite *InverseOfferTestSuite) TestInverseOfferMasterCallFail() {
+	suite.maintenanceHostInfoMap.EXPECT().
+		GetDownHostInfos([]string{_testHostname}).
+		Return([]*hpb.HostInfo{{Hostname: _testHostname}})
+	suite.expectMasterCall(
+		sched.Call_ACCEPT_INVERSE_OFFERS, errors.New("master unavailable"))
+
+	suite.Error(suite.processor.AddInverseOffers(
+		context.Background(),
+		[]*mesos.InverseOffer{newInverseOffer()},
+	))
+	suite.Contains(suite.processor.inverseOffers, _testInverseOffer)
+}
+
+// TestRescindInverseOffer tests that rescinding the inverse offer
+// of a host forgets the host
+func (suite *InverseOfferTestSuite) TestRescindInverseOffer() {
+	suite.processor.inverseOffers[_testInverseOffer] = _testHostname
+	suite.processor.unavailabilities[_testHostname] =
+		newUnavailability(time.Now(), 0)
+	suite.processor.drainedHosts[_testHostname] = struct{}{}
+
+	suite.processor.RescindInverseOffer(
+		&mesos.OfferID{Value: proto.String(_testInverseOffer)})
+	suite.Empty(suite.processor.inverseOffers)
+	suite.Empty(suite.processor.unavailabilities)
+	suite.Empty(suite.processor.drainedHosts)
+}
+
+// TestRescindInverseOfferOtherOutstanding tests that rescinding an
+// inverse offer of a host with another outstanding inverse offer
+// keeps the host
+func (suite *InverseOfferTestSuite) TestRescindInverseOfferOtherOutstanding() {
+	suite.processor.inverseOffers[_testInverseOffer] = _testHostname
+	suite.processor.inverseOffers["other"] = _testHostname
+	suite.processor.unavailabilities[_testHostname] =
+		newUnavailability(time.Now(), 0)
+
+	suite.processor.RescindInverseOffer(
+		&mesos.OfferID{Value: proto.String(_testInverseOffer)})
+	suite.Len(suite.processor.inverseOffers, 1)
+	suite.Contains(suite.processor.unavailabilities, _testHostname)
+}
+
+// TestRescindUnknownInverseOffer tests rescinding an unknown inverse offer
+func (suite *InverseOfferTestSuite) TestRescindUnknownInverseOffer() {
+	suite.processor.RescindInverseOffer(
+		&mesos.OfferID{Value: proto.String(_testInverseOffer)})
+	suite.Empty(suite.processor.inverseOffers)
+}
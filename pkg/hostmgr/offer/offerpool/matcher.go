@@ -44,6 +44,10 @@ type Matcher struct {
 	hostOffers map[string]*summary.Offer
 
 	filterResultCounts map[string]uint32
+
+	// whether matched hosts are claimed for placement, or only peeked
+	// at for a dry run.
+	claim bool
 }
 
 // tryMatch tries to match ready unreserved offers in summary with particular
@@ -73,7 +77,12 @@ func (m *Matcher) tryMatchImpl(
 		return hostsvc.HostFilterResult_MATCH
 	}
 
-	match := s.TryMatch(m.hostFilter, m.evaluator)
+	var match summary.Match
+	if m.claim {
+		match = s.TryMatch(m.hostFilter, m.evaluator)
+	} else {
+		match = s.PeekMatch(m.hostFilter, m.evaluator)
+	}
 	log.WithFields(log.Fields{
 		"host_filter": m.hostFilter,
 		"host":        hostname,
@@ -107,11 +116,29 @@ func (m *Matcher) getHostOffers() (map[string]*summary.Offer, map[string]uint32)
 func NewMatcher(
 	hostFilter *hostsvc.HostFilter,
 	evaluator constraints.Evaluator,
+) *Matcher {
+	return newMatcher(hostFilter, evaluator, true)
+}
+
+// newPeekMatcher returns a new instance of Matcher which does not claim
+// the matched hosts.
+func newPeekMatcher(
+	hostFilter *hostsvc.HostFilter,
+	evaluator constraints.Evaluator,
+) *Matcher {
+	return newMatcher(hostFilter, evaluator, false)
+}
+
+func newMatcher(
+	hostFilter *hostsvc.HostFilter,
+	evaluator constraints.Evaluator,
+	claim bool,
 ) *Matcher {
 	return &Matcher{
 		hostFilter:         hostFilter,
 		evaluator:          evaluator,
 		hostOffers:         make(map[string]*summary.Offer),
 		filterResultCounts: make(map[string]uint32),
+		claim:              claim,
 	}
 }
@@ -71,6 +71,14 @@ type Pool interface {
 		map[string]*summary.Offer,
 		map[string]uint32, error)
 
+	// PeekForPlace returns offers from pool conforming to given HostFilter
+	// like ClaimForPlace, but without claiming them. Returned offers are
+	// copies and the matched hosts remain available for placement.
+	PeekForPlace(
+		constraint *hostsvc.HostFilter) (
+		map[string]*summary.Offer,
+		map[string]uint32, error)
+
 	// ClaimForLaunch finds offers previously for placement on given host.
 	// The difference from ClaimForPlace is that offers claimed from this
 	// function are considered used and sent back to Mesos master in a Launch
@@ -256,10 +264,41 @@ func (p *offerPool) ClaimForPlace(hostFilter *hostsvc.HostFilter) (
 	p.RLock()
 	defer p.RUnlock()
 
-	matcher := NewMatcher(
+	return p.matchForPlace(
 		hostFilter,
-		constraints.NewEvaluator(task.LabelConstraint_HOST))
+		NewMatcher(
+			hostFilter,
+			constraints.NewTopologyEvaluator(
+				task.LabelConstraint_HOST,
+				hostFilter.GetTopologyDomainCounts())))
+}
 
+// PeekForPlace returns offers from pool conforming to given constraints,
+// without moving the matched hosts to placing status.
+func (p *offerPool) PeekForPlace(hostFilter *hostsvc.HostFilter) (
+	map[string]*summary.Offer,
+	map[string]uint32,
+	error) {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.matchForPlace(
+		hostFilter,
+		newPeekMatcher(
+			hostFilter,
+			constraints.NewTopologyEvaluator(
+				task.LabelConstraint_HOST,
+				hostFilter.GetTopologyDomainCounts())))
+}
+
+// matchForPlace runs the matcher over the hosts in the pool, host hints
+// first. The caller must hold the read lock.
+func (p *offerPool) matchForPlace(
+	hostFilter *hostsvc.HostFilter,
+	matcher *Matcher) (
+	map[string]*summary.Offer,
+	map[string]uint32,
+	error) {
 	// if host hint is provided, try to return the hosts in hints first
 	for _, filterHints := range hostFilter.GetHint().GetHostHint() {
 		if hs, ok := p.hostOfferIndex[filterHints.GetHostname()]; ok {
@@ -30,6 +30,7 @@ import (
 	mesos "github.com/uber/peloton/.gen/mesos/v1"
 	sched "github.com/uber/peloton/.gen/mesos/v1/scheduler"
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 
 	"github.com/uber/peloton/pkg/common"
@@ -1127,6 +1128,94 @@ func (suite *OfferPoolTestSuite) TestClaimForPlaceWithFilterHint() {
 	suite.NotNil(result[hostname2])
 }
 
+// TestClaimForPlaceWithTopologyCounts tests that ClaimForPlace only returns
+// the hosts on which the limits of the topology constraints are kept
+func (suite *OfferPoolTestSuite) TestClaimForPlaceWithTopologyCounts() {
+	rack := func(offer *mesos.Offer, value string) {
+		name := "rack"
+		textType := mesos.Value_TEXT
+		offer.Attributes = []*mesos.Attribute{
+			{
+				Name: &name,
+				Type: &textType,
+				Text: &mesos.Value_Text{Value: &value},
+			},
+		}
+	}
+	hostname0 := "hostname0"
+	offer0 := suite.createOffer(hostname0,
+		scalar.Resources{CPU: 1, Mem: 1, Disk: 1})
+	rack(offer0, "r1")
+	hostname1 := "hostname1"
+	offer1 := suite.createOffer(hostname1,
+		scalar.Resources{CPU: 1, Mem: 1, Disk: 1})
+	rack(offer1, "r2")
+
+	suite.pool.AddOffers(context.Background(),
+		[]*mesos.Offer{offer0, offer1})
+
+	label := &peloton.Label{Key: "job", Value: "cassandra"}
+	filter := &hostsvc.HostFilter{
+		SchedulingConstraint: &task.Constraint{
+			Type: task.Constraint_TOPOLOGY_CONSTRAINT,
+			TopologyConstraint: &task.TopologyConstraint{
+				TopologyKey:  "rack",
+				Label:        label,
+				MaxPerDomain: 1,
+			},
+		},
+		Quantity: &hostsvc.QuantityControl{MaxHosts: 2},
+		TopologyDomainCounts: []*hostsvc.TopologyDomainCounts{
+			{
+				TopologyKey:      "rack",
+				Label:            label,
+				Counts:           map[string]uint32{"r1": 1},
+				CountsPlacedTask: true,
+			},
+		},
+	}
+	result, _, err := suite.pool.ClaimForPlace(filter)
+	suite.NoError(err)
+	suite.Len(result, 1)
+	suite.NotNil(result[hostname1])
+}
+
+// TestPeekForPlace tests that offers returned by PeekForPlace can still be
+// claimed for placement.
+func (suite *OfferPoolTestSuite) TestPeekForPlace() {
+	hostname0 := "hostname0"
+	offer0 := suite.createOffer(hostname0,
+		scalar.Resources{CPU: 1, Mem: 1, Disk: 1})
+	hostname1 := "hostname1"
+	offer1 := suite.createOffer(hostname1,
+		scalar.Resources{CPU: 1, Mem: 1, Disk: 1})
+
+	suite.pool.AddOffers(context.Background(),
+		[]*mesos.Offer{offer0, offer1})
+
+	filter := &hostsvc.HostFilter{
+		Quantity: &hostsvc.QuantityControl{MaxHosts: 2},
+	}
+	result, resultCount, err := suite.pool.PeekForPlace(filter)
+	suite.NoError(err)
+	suite.Len(result, 2)
+	suite.Equal(uint32(2), resultCount["match"])
+
+	for _, hostname := range []string{hostname0, hostname1} {
+		s, err := suite.pool.GetHostSummary(hostname)
+		suite.NoError(err)
+		suite.Equal(summary.ReadyHost, s.GetHostStatus())
+	}
+
+	result, _, err = suite.pool.ClaimForPlace(filter)
+	suite.NoError(err)
+	suite.Len(result, 2)
+
+	result, _, err = suite.pool.PeekForPlace(filter)
+	suite.NoError(err)
+	suite.Empty(result)
+}
+
 func TestOfferPoolTestSuite(t *testing.T) {
 	suite.Run(t, new(OfferPoolTestSuite))
 }
@@ -28,6 +28,7 @@ import (
 
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
 	"github.com/uber/peloton/.gen/peloton/api/v0/volume"
+	commonreservation "github.com/uber/peloton/pkg/common/reservation"
 	"github.com/uber/peloton/pkg/hostmgr/factory/operation"
 	hostmgrmesos "github.com/uber/peloton/pkg/hostmgr/mesos"
 	"github.com/uber/peloton/pkg/hostmgr/mesos/yarpc/encoding/mpb"
@@ -114,11 +115,18 @@ func (c *cleaner) cleanReservedResources(offer *mesos.Offer, reservationLabel st
 	return c.callMesosForOfferOperations(offer, operations)
 }
 
-func (c *cleaner) cleanVolume(offer *mesos.Offer, volumeID string, reservationLabel string) error {
+// cleanVolume destroys the volume, and unreserves the reserved resources
+// unless the reservation is explicitly managed.
+func (c *cleaner) cleanVolume(
+	offer *mesos.Offer,
+	volumeID string,
+	reservationLabel string,
+	unreserve bool) error {
 	log.WithFields(log.Fields{
 		"offer":     offer,
 		"volume_id": volumeID,
 		"label":     reservationLabel,
+		"unreserve": unreserve,
 	}).Info("Cleaning volume resources")
 
 	// Remove given offer from memory before destroy/unreserve.
@@ -131,12 +139,14 @@ func (c *cleaner) cleanVolume(offer *mesos.Offer, volumeID string, reservationLa
 				VolumeID: volumeID,
 			},
 		},
-		{
+	}
+	if unreserve {
+		operations = append(operations, &hostsvc.OfferOperation{
 			Type: hostsvc.OfferOperation_UNRESERVE,
 			Unreserve: &hostsvc.OfferOperation_Unreserve{
 				Label: reservationLabel,
 			},
-		},
+		})
 	}
 	return c.callMesosForOfferOperations(offer, operations)
 }
@@ -146,10 +156,16 @@ func (c *cleaner) cleanOffer(offer *mesos.Offer) error {
 	reservedResources := reservation.GetLabeledReservedResources([]*mesos.Offer{offer})
 
 	for labels, res := range reservedResources {
+		// explicitly managed reservations are only unreserved on request,
+		// but their deleted volumes are still destroyed.
+		explicit := commonreservation.IsExplicitReservation(res.Labels)
 		if len(res.Volumes) == 0 {
+			if explicit {
+				continue
+			}
 			return c.cleanReservedResources(offer, labels)
 		} else if c.needCleanVolume(res.Volumes[0], offer) {
-			return c.cleanVolume(offer, res.Volumes[0], labels)
+			return c.cleanVolume(offer, res.Volumes[0], labels, !explicit)
 		}
 	}
 	return nil
@@ -28,6 +28,7 @@ import (
 	"github.com/uber/peloton/.gen/peloton/api/v0/volume"
 
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	commonreservation "github.com/uber/peloton/pkg/common/reservation"
 	"github.com/uber/peloton/pkg/common/util"
 	mpb_mocks "github.com/uber/peloton/pkg/hostmgr/mesos/yarpc/encoding/mpb/mocks"
 	offerpool_mocks "github.com/uber/peloton/pkg/hostmgr/offer/offerpool/mocks"
@@ -307,6 +308,133 @@ func TestNotCleanVolumeIfVolumeGoalstateNotDeleted(t *testing.T) {
 	cleaner.Run(nil)
 }
 
+// TestNotCleanExplicitReservation tests that the cleaner does not unreserve
+// the resources of an explicitly managed reservation.
+func TestNotCleanExplicitReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSchedulerClient := mpb_mocks.NewMockSchedulerClient(ctrl)
+	mockVolumeStore := store_mocks.NewMockPersistentVolumeStore(ctrl)
+	mockOfferPool := offerpool_mocks.NewMockPool(ctrl)
+	defer ctrl.Finish()
+
+	testScope := tally.NewTestScope("", map[string]string{})
+	cleaner := NewCleaner(
+		mockOfferPool,
+		testScope,
+		mockVolumeStore,
+		mockSchedulerClient,
+		&mockMesosStreamIDProvider{})
+
+	reservation := &mesos.Resource_ReservationInfo{
+		Labels: commonreservation.CreateExplicitReservationLabels(
+			&mesos.Labels{
+				Labels: []*mesos.Label{
+					{
+						Key:   &_testKey,
+						Value: &_testValue,
+					},
+				},
+			}),
+	}
+	resources := []*mesos.Resource{
+		util.NewMesosResourceBuilder().
+			WithName("cpus").
+			WithValue(_perHostCPU).
+			WithRole(pelotonRole).
+			WithReservation(reservation).
+			Build(),
+	}
+	offer := createMesosOffer(resources)
+	reservedOffers := make(map[string]*mesos.Offer)
+	reservedOffers[offer.GetId().GetValue()] = offer
+	hostOffers := make(map[string]map[string]*mesos.Offer)
+	hostOffers[offer.GetHostname()] = reservedOffers
+
+	mockOfferPool.EXPECT().GetOffers(summary.Reserved).Return(hostOffers, 1)
+
+	cleaner.Run(nil)
+}
+
+// TestCleanVolumeOfExplicitReservation tests that the cleaner destroys a
+// deleted volume of an explicitly managed reservation without unreserving
+// its resources.
+func TestCleanVolumeOfExplicitReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockSchedulerClient := mpb_mocks.NewMockSchedulerClient(ctrl)
+	mockVolumeStore := store_mocks.NewMockPersistentVolumeStore(ctrl)
+	mockOfferPool := offerpool_mocks.NewMockPool(ctrl)
+	defer ctrl.Finish()
+
+	testScope := tally.NewTestScope("", map[string]string{})
+	cleaner := NewCleaner(
+		mockOfferPool,
+		testScope,
+		mockVolumeStore,
+		mockSchedulerClient,
+		&mockMesosStreamIDProvider{})
+
+	reservation := &mesos.Resource_ReservationInfo{
+		Labels: commonreservation.CreateExplicitReservationLabels(
+			&mesos.Labels{
+				Labels: []*mesos.Label{
+					{
+						Key:   &_testKey,
+						Value: &_testValue,
+					},
+				},
+			}),
+	}
+	diskInfo := &mesos.Resource_DiskInfo{
+		Persistence: &mesos.Resource_DiskInfo_Persistence{
+			Id: &_testVolumeID,
+		},
+	}
+	resources := []*mesos.Resource{
+		util.NewMesosResourceBuilder().
+			WithName("disk").
+			WithValue(_perHostDisk).
+			WithRole(pelotonRole).
+			WithReservation(reservation).
+			WithDisk(diskInfo).
+			Build(),
+	}
+	offer := createMesosOffer(resources)
+	reservedOffers := make(map[string]*mesos.Offer)
+	reservedOffers[offer.GetId().GetValue()] = offer
+	hostOffers := make(map[string]map[string]*mesos.Offer)
+	hostOffers[offer.GetHostname()] = reservedOffers
+	volumeID := &peloton.VolumeID{
+		Value: _testVolumeID,
+	}
+	volumeInfo := &volume.PersistentVolumeInfo{
+		State:     volume.VolumeState_CREATED,
+		GoalState: volume.VolumeState_DELETED,
+	}
+
+	gomock.InOrder(
+		mockOfferPool.EXPECT().GetOffers(summary.Reserved).Return(hostOffers, 1),
+		mockVolumeStore.EXPECT().GetPersistentVolume(gomock.Any(), volumeID).Return(volumeInfo, nil),
+		mockVolumeStore.EXPECT().UpdatePersistentVolume(gomock.Any(), volumeInfo).Return(nil),
+		mockOfferPool.EXPECT().RemoveReservedOffer(offer.GetHostname(), offer.GetId().GetValue()),
+		mockSchedulerClient.EXPECT().
+			Call(
+				gomock.Eq("stream"),
+				gomock.Any()).
+			Do(func(_ string, msg proto.Message) {
+				call := msg.(*sched.Call)
+				assert.Equal(t, sched.Call_ACCEPT, call.GetType())
+				assert.Equal(t, 1, len(call.GetAccept().GetOperations()))
+				assert.Equal(
+					t,
+					mesos.Offer_Operation_DESTROY,
+					call.GetAccept().GetOperations()[0].GetType())
+			}).
+			Return(nil),
+	)
+
+	cleaner.Run(nil)
+}
+
 func createMesosOffer(res []*mesos.Resource) *mesos.Offer {
 	return &mesos.Offer{
 		Id: &mesos.OfferID{
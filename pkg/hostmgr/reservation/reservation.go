@@ -29,6 +29,8 @@ type ReservedResources struct {
 	Resources scalar.Resources
 	// volumes has list of volume IDs.
 	Volumes []string
+	// Labels are the labels of the reservation.
+	Labels *mesos.Labels
 }
 
 // GetLabeledReservedResources extracts reserved resources from given list of
@@ -61,6 +63,7 @@ func GetLabeledReservedResourcesFromResources(
 		if _, ok := reservedResources[resLabels]; !ok {
 			reservedResources[resLabels] = &ReservedResources{
 				Resources: scalar.Resources{},
+				Labels:    res.GetReservation().GetLabels(),
 			}
 		}
 
@@ -18,11 +18,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"sync"
 	"time"
 
 	"github.com/uber/peloton/.gen/mesos/v1"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 
 	"github.com/uber/peloton/pkg/common/async"
@@ -82,6 +84,11 @@ type Reserver interface {
 	// DequeueCompletedReservation dequeues the completed reservations equal to
 	// limit from the completed Reservation queue
 	DequeueCompletedReservation(ctx context.Context, limit int) ([]*hostsvc.CompletedReservation, error)
+
+	// GetReservations returns the outstanding host reservations for tasks
+	// with at least the given priority, along with the resources which are
+	// still missing on each reserved host
+	GetReservations(minPriority uint32) []*hostsvc.HostReservation
 }
 
 // reserver is the struct which impelements Reserver interface
@@ -295,6 +302,37 @@ func (r *reserver) removeFailedReservedHosts(hosts []*hostsvc.HostInfo,
 	return remainingHosts
 }
 
+// GetReservations returns the outstanding host reservations for tasks
+// with at least the given priority. The shortfall of each reservation is
+// the part of the task resources which is not yet covered by the
+// outstanding offers on the reserved host.
+func (r *reserver) GetReservations(
+	minPriority uint32) []*hostsvc.HostReservation {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var reservations []*hostsvc.HostReservation
+	for host, res := range r.reservations {
+		if res.GetTask().GetPriority() < minPriority {
+			continue
+		}
+		shortfall := scalar.FromResourceConfig(res.GetTask().GetResource()).
+			Subtract(r.getResourcesFromHostOffers(host))
+		reservations = append(reservations, &hostsvc.HostReservation{
+			Hostname: host,
+			Task:     res.GetTask(),
+			Shortfall: &task.ResourceConfig{
+				CpuLimit:    math.Max(shortfall.GetCPU(), 0),
+				MemLimitMb:  math.Max(shortfall.GetMem(), 0),
+				DiskLimitMb: math.Max(shortfall.GetDisk(), 0),
+				GpuLimit:    math.Max(shortfall.GetGPU(), 0),
+				NetworkMbps: math.Max(shortfall.GetNetwork(), 0),
+			},
+		})
+	}
+	return reservations
+}
+
 // FindCompletedReservations looks into current reservations and
 // try to find out if the hosts are having outstanding offers
 // for reservations to fulfil the reservation. If yes it will put
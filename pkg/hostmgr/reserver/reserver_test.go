@@ -260,6 +260,38 @@ func (suite *ReserverTestSuite) TestFindCompletedReservations() {
 	suite.NotNil(item)
 }
 
+// Testing the outstanding reservations are returned with their shortfall
+func (suite *ReserverTestSuite) TestGetReservations() {
+	reservation := createReservation()
+	reservation.Task.Priority = 5
+	reservation.Task.Resource.CpuLimit = 3
+	suite.reserver.EnqueueReservation(context.Background(), reservation)
+	summary := summary_mocks.NewMockHostSummary(suite.mockCtrl)
+	gomock.InOrder(
+		suite.mockPool.EXPECT().GetHostSummary(gomock.Any()).
+			Return(summary, nil).Times(1),
+		summary.EXPECT().GetHostStatus().
+			Return(sum.ReadyHost).Times(2),
+		summary.EXPECT().CasStatus(gomock.Any(), gomock.Any()).
+			Return(nil),
+		suite.mockPool.EXPECT().GetHostSummary("host1").
+			Return(summary, nil).Times(1),
+		summary.EXPECT().GetOffers(gomock.Any()).
+			Return(suite.createUnreservedMesosOffers(1)),
+	)
+	_, err := suite.reserver.Reserve(context.Background())
+	suite.NoError(err)
+
+	suite.Empty(suite.reserver.GetReservations(10))
+
+	reservations := suite.reserver.GetReservations(5)
+	suite.Len(reservations, 1)
+	suite.Equal("host1", reservations[0].GetHostname())
+	suite.Equal(reservation.Task, reservations[0].GetTask())
+	suite.Equal(&task.ResourceConfig{CpuLimit: 2},
+		reservations[0].GetShortfall())
+}
+
 func (suite *ReserverTestSuite) TestDequeueCompletedReservations() {
 	_, err := suite.reserver.DequeueCompletedReservation(
 		context.Background(),
@@ -22,6 +22,7 @@ import (
 	mesos "github.com/uber/peloton/.gen/mesos/v1"
 	"github.com/uber/peloton/.gen/peloton/api/v0/task"
 
+	"github.com/uber/peloton/pkg/common"
 	"github.com/uber/peloton/pkg/common/util"
 )
 
@@ -31,6 +32,11 @@ type Resources struct {
 	Mem  float64
 	Disk float64
 	GPU  float64
+	// Ports is the number of ports. Ports are range resources in Mesos, so
+	// they are only counted here and matched separately when launching.
+	Ports float64
+	// Network is the network bandwidth in Mbps.
+	Network float64
 }
 
 // a safe less than or equal to comparator which takes epsilon into consideration.
@@ -72,6 +78,16 @@ func (r Resources) GetGPU() float64 {
 	return r.GPU
 }
 
+// GetPorts returns the number of ports
+func (r Resources) GetPorts() float64 {
+	return r.Ports
+}
+
+// GetNetwork returns the network bandwidth resource
+func (r Resources) GetNetwork() float64 {
+	return r.Network
+}
+
 // HasGPU is a special condition to ensure exclusive protection for GPU.
 func (r Resources) HasGPU() bool {
 	return math.Abs(r.GPU) > util.ResourceEpsilon
@@ -83,7 +99,9 @@ func (r Resources) Contains(other Resources) bool {
 	return lessThanOrEqual(other.CPU, r.CPU) &&
 		lessThanOrEqual(other.Mem, r.Mem) &&
 		lessThanOrEqual(other.Disk, r.Disk) &&
-		lessThanOrEqual(other.GPU, r.GPU)
+		lessThanOrEqual(other.GPU, r.GPU) &&
+		lessThanOrEqual(other.Ports, r.Ports) &&
+		lessThanOrEqual(other.Network, r.Network)
 }
 
 // Compare method compares current Resources with the other one, return
@@ -115,16 +133,24 @@ func (r Resources) Compare(other Resources, cmpLess bool) bool {
 	if other.Disk > 0 && lessThan(r.Disk, other.Disk) != cmpLess {
 		return false
 	}
+	if other.Ports > 0 && lessThan(r.Ports, other.Ports) != cmpLess {
+		return false
+	}
+	if other.Network > 0 && lessThan(r.Network, other.Network) != cmpLess {
+		return false
+	}
 	return true
 }
 
 // Add atomically add another scalar resources onto current one.
 func (r Resources) Add(other Resources) Resources {
 	return Resources{
-		CPU:  r.CPU + other.CPU,
-		Mem:  r.Mem + other.Mem,
-		Disk: r.Disk + other.Disk,
-		GPU:  r.GPU + other.GPU,
+		CPU:     r.CPU + other.CPU,
+		Mem:     r.Mem + other.Mem,
+		Disk:    r.Disk + other.Disk,
+		GPU:     r.GPU + other.GPU,
+		Ports:   r.Ports + other.Ports,
+		Network: r.Network + other.Network,
 	}
 }
 
@@ -140,14 +166,17 @@ func (r Resources) TrySubtract(other Resources) (Resources, bool) {
 // Subtract another scalar resources from current one and return a new copy of result.
 func (r Resources) Subtract(other Resources) Resources {
 	return Resources{
-		CPU:  r.CPU - other.CPU,
-		Mem:  r.Mem - other.Mem,
-		Disk: r.Disk - other.Disk,
-		GPU:  r.GPU - other.GPU,
+		CPU:     r.CPU - other.CPU,
+		Mem:     r.Mem - other.Mem,
+		Disk:    r.Disk - other.Disk,
+		GPU:     r.GPU - other.GPU,
+		Ports:   r.Ports - other.Ports,
+		Network: r.Network - other.Network,
 	}
 }
 
-// NonEmptyFields returns corresponding Mesos resource names for fields which are not empty.
+// NonEmptyFields returns corresponding Mesos resource names for scalar fields
+// which are not empty. Ports are not scalar resources and are not included.
 func (r Resources) NonEmptyFields() []string {
 	var nonEmptyFields []string
 	if math.Abs(r.CPU) > util.ResourceEpsilon {
@@ -162,6 +191,9 @@ func (r Resources) NonEmptyFields() []string {
 	if math.Abs(r.GPU) > util.ResourceEpsilon {
 		nonEmptyFields = append(nonEmptyFields, "gpus")
 	}
+	if math.Abs(r.Network) > util.ResourceEpsilon {
+		nonEmptyFields = append(nonEmptyFields, common.MesosNetworkBandwidth)
+	}
 
 	return nonEmptyFields
 }
@@ -173,8 +205,9 @@ func (r Resources) Empty() bool {
 
 // String returns a formatted string for scalar resources
 func (r Resources) String() string {
-	return fmt.Sprintf("CPU:%.2f MEM:%.2f DISK:%.2f GPU:%.2f",
-		r.GetCPU(), r.GetMem(), r.GetDisk(), r.GetGPU())
+	return fmt.Sprintf("CPU:%.2f MEM:%.2f DISK:%.2f GPU:%.2f PORTS:%.0f NETWORK:%.2f",
+		r.GetCPU(), r.GetMem(), r.GetDisk(), r.GetGPU(), r.GetPorts(),
+		r.GetNetwork())
 }
 
 // HasResourceType validates requested resource type is present agent resource type.
@@ -217,6 +250,7 @@ func FromResourceConfig(rc *task.ResourceConfig) (r Resources) {
 	r.Mem = rc.GetMemLimitMb()
 	r.Disk = rc.GetDiskLimitMb()
 	r.GPU = rc.GetGpuLimit()
+	r.Network = rc.GetNetworkMbps()
 	return r
 }
 
@@ -232,6 +266,12 @@ func FromMesosResource(resource *mesos.Resource) (r Resources) {
 		r.Disk += value
 	case "gpus":
 		r.GPU += value
+	case common.MesosNetworkBandwidth:
+		r.Network += value
+	case common.MesosPorts:
+		for _, portRange := range resource.GetRanges().GetRange() {
+			r.Ports += float64(portRange.GetEnd() - portRange.GetBegin() + 1)
+		}
 	}
 	return r
 }
@@ -302,6 +342,8 @@ func Minimum(r1, r2 Resources) (m Resources) {
 	m.Mem = math.Min(r1.Mem, r2.Mem)
 	m.Disk = math.Min(r1.Disk, r2.Disk)
 	m.GPU = math.Min(r1.GPU, r2.GPU)
+	m.Ports = math.Min(r1.Ports, r2.Ports)
+	m.Network = math.Min(r1.Network, r2.Network)
 	return m
 }
 
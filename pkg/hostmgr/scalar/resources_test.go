@@ -416,11 +416,40 @@ func TestFromResourceConfig(t *testing.T) {
 		MemLimitMb:  2.0,
 		DiskLimitMb: 3.0,
 		GpuLimit:    4.0,
+		NetworkMbps: 5.0,
 	})
 	assert.InDelta(t, 1.0, result.CPU, _zeroDelta)
 	assert.InDelta(t, 2.0, result.Mem, _zeroDelta)
 	assert.InDelta(t, 3.0, result.Disk, _zeroDelta)
 	assert.InDelta(t, 4.0, result.GPU, _zeroDelta)
+	assert.InDelta(t, 5.0, result.Network, _zeroDelta)
+}
+
+// TestFromMesosResourcesPortsAndNetwork tests ports are counted from
+// range resources and network bandwidth is read as a scalar.
+func TestFromMesosResourcesPortsAndNetwork(t *testing.T) {
+	networkRes := util.NewMesosResourceBuilder().
+		WithName(common.MesosNetworkBandwidth).
+		WithValue(100.0).
+		Build()
+	portsRes := util.NewMesosResourceBuilder().
+		WithName(common.MesosPorts).
+		WithType(mesos.Value_RANGES).
+		WithRanges(util.CreatePortRanges(
+			map[uint32]bool{31000: true, 31001: true, 31005: true})).
+		Build()
+
+	result := FromMesosResources([]*mesos.Resource{_cpuRes, networkRes, portsRes})
+	assert.InDelta(t, 1.0, result.CPU, _zeroDelta)
+	assert.InDelta(t, 100.0, result.Network, _zeroDelta)
+	assert.InDelta(t, 3.0, result.Ports, _zeroDelta)
+
+	// Ports are not scalar resources so they are not reported as non-empty.
+	assert.Equal(
+		t,
+		[]string{"cpus", common.MesosNetworkBandwidth},
+		result.NonEmptyFields())
+	assert.True(t, Resources{Ports: 3.0}.Empty())
 }
 
 func TestMinimum(t *testing.T) {
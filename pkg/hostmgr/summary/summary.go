@@ -25,6 +25,7 @@ import (
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 
 	"github.com/uber/peloton/pkg/common/constraints"
+	"github.com/uber/peloton/pkg/common/gpu"
 	"github.com/uber/peloton/pkg/common/util"
 	"github.com/uber/peloton/pkg/hostmgr/host"
 	"github.com/uber/peloton/pkg/hostmgr/reservation"
@@ -118,6 +119,13 @@ type HostSummary interface {
 		hostFilter *hostsvc.HostFilter,
 		evaluator constraints.Evaluator) Match
 
+	// PeekMatch matches offers from the current host with given constraint
+	// like TryMatch, but neither changes the host status nor mutates the
+	// offers held by the host. It is used for dry-run placement.
+	PeekMatch(
+		hostFilter *hostsvc.HostFilter,
+		evaluator constraints.Evaluator) Match
+
 	// AddMesosOffer adds a Mesos offers to the current HostSummary.
 	AddMesosOffers(ctx context.Context, offer []*mesos.Offer) HostStatus
 
@@ -323,6 +331,20 @@ func matchHostFilter(
 				return hostsvc.HostFilterResult_SCARCE_RESOURCES
 			}
 		}
+
+		// Validates the GPU device requirements against the GPU topology
+		// parsed from the host attributes.
+		if min.GetGpu() != nil && scalarMin.GetGPU() > 0 {
+			var attributes []*mesos.Attribute
+			for _, offer := range offerMap {
+				attributes = offer.GetAttributes()
+				break
+			}
+			topology := gpu.FromAttributes(attributes, scalarAgentRes.GetGPU())
+			if !topology.Match(min.GetGpu(), scalarMin.GetGPU(), scalarRes.GetGPU()) {
+				return hostsvc.HostFilterResult_MISMATCH_CONSTRAINTS
+			}
+		}
 	}
 
 	// Match ports resources.
@@ -400,6 +422,28 @@ func (a *hostSummary) TryMatch(
 	a.Lock()
 	defer a.Unlock()
 
+	return a.matchLockFree(filter, evaluator, true)
+}
+
+// PeekMatch matches offers from the current host with given HostFilter
+// without claiming them. The returned offers are copies, and the status of
+// the host is left unchanged.
+func (a *hostSummary) PeekMatch(
+	filter *hostsvc.HostFilter,
+	evaluator constraints.Evaluator) Match {
+	a.Lock()
+	defer a.Unlock()
+
+	return a.matchLockFree(filter, evaluator, false)
+}
+
+// matchLockFree matches the unreserved offers of the host with given
+// HostFilter. If claim is set, matched offers are filtered in place and the
+// host is moved to `PlacingHost`. The caller must hold the lock.
+func (a *hostSummary) matchLockFree(
+	filter *hostsvc.HostFilter,
+	evaluator constraints.Evaluator,
+	claim bool) Match {
 	if a.status != ReadyHost && a.status != HeldHost {
 		return Match{
 			Result: hostsvc.HostFilterResult_MISMATCH_STATUS,
@@ -444,6 +488,9 @@ func (a *hostSummary) TryMatch(
 	// Its a match!
 	var offers []*mesos.Offer
 	for _, offer := range a.unreservedOffers {
+		if !claim {
+			offer = proto.Clone(offer).(*mesos.Offer)
+		}
 		if filter.GetResourceConstraint().GetRevocable() {
 			offer.Resources, _ = scalar.FilterMesosResources(
 				offer.GetResources(),
@@ -458,6 +505,17 @@ func (a *hostSummary) TryMatch(
 		}
 		offers = append(offers, offer)
 	}
+
+	if !claim {
+		return Match{
+			Result: hostsvc.HostFilterResult_MATCH,
+			Offer: &Offer{
+				ID:     a.hostOfferID,
+				Offers: offers,
+			},
+		}
+	}
+
 	// Setting status to `PlacingHost`: this ensures proper state
 	// tracking of resources on the host and also ensures offers on
 	// this host will not be sent to another `AcquireHostOffers`
@@ -32,6 +32,7 @@ import (
 	"github.com/uber/peloton/pkg/common"
 	"github.com/uber/peloton/pkg/common/constraints"
 	constraint_mocks "github.com/uber/peloton/pkg/common/constraints/mocks"
+	"github.com/uber/peloton/pkg/common/gpu"
 	"github.com/uber/peloton/pkg/common/util"
 	"github.com/uber/peloton/pkg/hostmgr/scalar"
 	store_mocks "github.com/uber/peloton/pkg/storage/mocks"
@@ -277,6 +278,16 @@ func (suite *HostOfferSummaryTestSuite) TestScarceResourcesConstraint() {
 	agent1 := suite.createAgentInfo(_testAgent1, 5.0, 5.0, 5.0, 5.0)
 	agent2 := suite.createAgentInfo(_testAgent2, 5.0, 0, 5.0, 5.0)
 
+	gpuModelAttr := gpu.ModelAttribute
+	gpuModel := "tesla-v100"
+	gpuAttributes := []*mesos.Attribute{
+		{
+			Name: &gpuModelAttr,
+			Type: mesos.Value_TEXT.Enum(),
+			Text: &mesos.Value_Text{Value: &gpuModel},
+		},
+	}
+
 	testTable := []struct {
 		msg                string
 		expected           hostsvc.HostFilterResult
@@ -506,6 +517,60 @@ func (suite *HostOfferSummaryTestSuite) TestScarceResourcesConstraint() {
 			},
 			scarceResourceType: scarceResourceType3,
 		},
+		{
+			msg:      "GPU model matches",
+			expected: hostsvc.HostFilterResult_MATCH,
+			filter: &hostsvc.HostFilter{
+				Quantity: &hostsvc.QuantityControl{
+					MaxHosts: 1,
+				},
+				ResourceConstraint: &hostsvc.ResourceConstraint{
+					Minimum: &task.ResourceConfig{
+						CpuLimit:    1.0,
+						GpuLimit:    1.0,
+						MemLimitMb:  1.0,
+						DiskLimitMb: 1.0,
+						Gpu: &task.GPUConfig{
+							Models: []string{gpuModel},
+						},
+					},
+				},
+			},
+			agent: agent1,
+			offer: &mesos.Offer{
+				AgentId:    agent1.Id,
+				Resources:  []*mesos.Resource{_cpuRes, _memRes, _diskRes, _gpuRes},
+				Attributes: gpuAttributes,
+			},
+			scarceResourceType: scarceResourceType1,
+		},
+		{
+			msg:      "GPU model does not match",
+			expected: hostsvc.HostFilterResult_MISMATCH_CONSTRAINTS,
+			filter: &hostsvc.HostFilter{
+				Quantity: &hostsvc.QuantityControl{
+					MaxHosts: 1,
+				},
+				ResourceConstraint: &hostsvc.ResourceConstraint{
+					Minimum: &task.ResourceConfig{
+						CpuLimit:    1.0,
+						GpuLimit:    1.0,
+						MemLimitMb:  1.0,
+						DiskLimitMb: 1.0,
+						Gpu: &task.GPUConfig{
+							Models: []string{"p100"},
+						},
+					},
+				},
+			},
+			agent: agent1,
+			offer: &mesos.Offer{
+				AgentId:    agent1.Id,
+				Resources:  []*mesos.Resource{_cpuRes, _memRes, _diskRes, _gpuRes},
+				Attributes: gpuAttributes,
+			},
+			scarceResourceType: scarceResourceType1,
+		},
 		{
 			msg:      "Empty offer map",
 			expected: hostsvc.HostFilterResult_NO_OFFER,
@@ -979,6 +1044,52 @@ func (suite *HostOfferSummaryTestSuite) TestTryMatchHostOnHeld() {
 	}
 }
 
+// TestPeekMatch tests that PeekMatch matches offers without changing
+// the host status or the offers held by the host.
+func (suite *HostOfferSummaryTestSuite) TestPeekMatch() {
+	defer suite.ctrl.Finish()
+	offers := suite.createUnreservedMesosOffers(2)
+
+	s := New(
+		suite.mockVolumeStore,
+		nil,
+		_testAgent,
+		supportedSlackResourceTypes,
+		time.Duration(30*time.Second)).(*hostSummary)
+	suite.Equal(ReadyHost, s.AddMesosOffers(context.Background(), offers))
+
+	filter := &hostsvc.HostFilter{
+		ResourceConstraint: &hostsvc.ResourceConstraint{
+			Minimum: &task.ResourceConfig{CpuLimit: 1.0},
+		},
+	}
+
+	match := s.PeekMatch(filter, nil)
+	suite.Equal(hostsvc.HostFilterResult_MATCH, match.Result)
+	suite.Len(match.Offer.Offers, 2)
+	for _, o := range match.Offer.Offers {
+		// revocable resources are filtered out of the returned copies
+		suite.Len(o.GetResources(), 4)
+	}
+	for _, o := range s.unreservedOffers {
+		suite.Len(o.GetResources(), 6)
+	}
+
+	_, _, status := s.UnreservedAmount()
+	suite.Equal(ReadyHost, status)
+	suite.Equal(emptyOfferID, s.hostOfferID)
+
+	// host can still be claimed after a peek
+	match = s.TryMatch(filter, nil)
+	suite.Equal(hostsvc.HostFilterResult_MATCH, match.Result)
+	_, _, status = s.UnreservedAmount()
+	suite.Equal(PlacingHost, status)
+
+	// a host in placing is not matched by a peek either
+	match = s.PeekMatch(filter, nil)
+	suite.Equal(hostsvc.HostFilterResult_MISMATCH_STATUS, match.Result)
+}
+
 func (suite *HostOfferSummaryTestSuite) TestAddRemoveHybridOffers() {
 	defer suite.ctrl.Finish()
 	// Add offer concurrently.
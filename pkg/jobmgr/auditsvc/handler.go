@@ -0,0 +1,121 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditsvc
+
+import (
+	"context"
+	"time"
+
+	pbaudit "github.com/uber/peloton/.gen/peloton/private/auditsvc"
+
+	yarpcutil "github.com/uber/peloton/pkg/common/util/yarpc"
+	ormobjects "github.com/uber/peloton/pkg/storage/objects"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/uber-go/tally"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// _defaultListWindow is the time range the audit records are listed for
+// when the start time is not set in the request
+const _defaultListWindow = 7 * 24 * time.Hour
+
+// serviceHandler implements peloton.private.audit.AuditService
+type serviceHandler struct {
+	metrics     *Metrics
+	auditLogOps ormobjects.AuditLogOps
+}
+
+// InitServiceHandler initializes the Audit Service Handler. The audit
+// records are read from the audit_log table, to which the jobmgr, resmgr
+// and hostmgr write when they use the STORAGE audit sink.
+func InitServiceHandler(
+	d *yarpc.Dispatcher,
+	parent tally.Scope,
+	ormStore *ormobjects.Store,
+) {
+	handler := &serviceHandler{
+		metrics:     NewMetrics(parent),
+		auditLogOps: ormobjects.NewAuditLogOps(ormStore),
+	}
+	d.Register(pbaudit.BuildAuditServiceYARPCProcedures(handler))
+}
+
+// ListAuditRecords implements AuditService.ListAuditRecords.
+func (h *serviceHandler) ListAuditRecords(
+	ctx context.Context,
+	req *pbaudit.ListAuditRecordsRequest,
+) (resp *pbaudit.ListAuditRecordsResponse, err error) {
+	h.metrics.ListAuditRecordsAPI.Inc(1)
+	defer func() {
+		if err != nil {
+			log.WithField("target", req.GetTarget()).
+				WithError(err).
+				Warn("AuditSVC.ListAuditRecords failed")
+			h.metrics.ListAuditRecordsFail.Inc(1)
+			err = yarpcutil.ConvertToYARPCError(err)
+			return
+		}
+		log.WithField("target", req.GetTarget()).
+			Debug("AuditSVC.ListAuditRecords succeeded")
+		h.metrics.ListAuditRecords.Inc(1)
+	}()
+
+	startTime, endTime, err := getTimeRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := h.auditLogOps.GetAll(
+		ctx, req.GetTarget(), startTime, endTime, req.GetLimit())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get audit records")
+	}
+	return &pbaudit.ListAuditRecordsResponse{Records: records}, nil
+}
+
+// getTimeRange returns the time range to list the audit records for
+func getTimeRange(
+	req *pbaudit.ListAuditRecordsRequest,
+) (time.Time, time.Time, error) {
+	endTime := time.Now()
+	if len(req.GetEndTime()) != 0 {
+		t, err := time.Parse(time.RFC3339, req.GetEndTime())
+		if err != nil {
+			return time.Time{}, time.Time{}, yarpcerrors.InvalidArgumentErrorf(
+				"invalid end time %s", req.GetEndTime())
+		}
+		endTime = t
+	}
+
+	startTime := endTime.Add(-_defaultListWindow)
+	if len(req.GetStartTime()) != 0 {
+		t, err := time.Parse(time.RFC3339, req.GetStartTime())
+		if err != nil {
+			return time.Time{}, time.Time{}, yarpcerrors.InvalidArgumentErrorf(
+				"invalid start time %s", req.GetStartTime())
+		}
+		startTime = t
+	}
+
+	if startTime.After(endTime) {
+		return time.Time{}, time.Time{}, yarpcerrors.InvalidArgumentErrorf(
+			"start time %s is after end time %s",
+			startTime.Format(time.RFC3339), endTime.Format(time.RFC3339))
+	}
+	return startTime, endTime, nil
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditsvc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pbaudit "github.com/uber/peloton/.gen/peloton/private/auditsvc"
+
+	objectmocks "github.com/uber/peloton/pkg/storage/objects/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+const testJobID = "481d565e-28da-457d-8434-f6bb7faa0e95"
+
+type auditHandlerTestSuite struct {
+	suite.Suite
+
+	ctx         context.Context
+	ctrl        *gomock.Controller
+	auditLogOps *objectmocks.MockAuditLogOps
+	handler     *serviceHandler
+}
+
+func TestAuditServiceHandler(t *testing.T) {
+	suite.Run(t, new(auditHandlerTestSuite))
+}
+
+func (suite *auditHandlerTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.auditLogOps = objectmocks.NewMockAuditLogOps(suite.ctrl)
+	suite.handler = &serviceHandler{
+		metrics:     NewMetrics(tally.NoopScope),
+		auditLogOps: suite.auditLogOps,
+	}
+}
+
+func (suite *auditHandlerTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+// TestListAuditRecords tests listing the audit records of a job
+func (suite *auditHandlerTestSuite) TestListAuditRecords() {
+	records := []*pbaudit.AuditRecord{
+		{Principal: "bob", Target: testJobID, Procedure: "StopJob"},
+		{Principal: "alice", Target: testJobID, Procedure: "CreateJob"},
+	}
+	suite.auditLogOps.EXPECT().GetAll(gomock.Any(), testJobID, gomock.Any(), gomock.Any(), uint32(0)).
+		Return(records, nil)
+
+	resp, err := suite.handler.ListAuditRecords(
+		suite.ctx,
+		&pbaudit.ListAuditRecordsRequest{Target: testJobID},
+	)
+	suite.NoError(err)
+	suite.Equal(records, resp.GetRecords())
+
+	// only the most recent records are returned with a limit
+	suite.auditLogOps.EXPECT().GetAll(gomock.Any(), testJobID, gomock.Any(), gomock.Any(), uint32(1)).
+		Return(records[:1], nil)
+	resp, err = suite.handler.ListAuditRecords(
+		suite.ctx,
+		&pbaudit.ListAuditRecordsRequest{Target: testJobID, Limit: 1},
+	)
+	suite.NoError(err)
+	suite.Equal(records[:1], resp.GetRecords())
+}
+
+// TestListAuditRecordsTimeRange tests listing the audit records of a
+// job within a time range
+func (suite *auditHandlerTestSuite) TestListAuditRecordsTimeRange() {
+	endTime := time.Date(2019, 5, 10, 12, 0, 0, 0, time.UTC)
+	startTime := endTime.Add(-time.Hour)
+	suite.auditLogOps.EXPECT().
+		GetAll(gomock.Any(), testJobID, startTime, endTime, uint32(0)).
+		Return(nil, nil)
+	_, err := suite.handler.ListAuditRecords(
+		suite.ctx,
+		&pbaudit.ListAuditRecordsRequest{
+			Target:    testJobID,
+			StartTime: startTime.Format(time.RFC3339),
+			EndTime:   endTime.Format(time.RFC3339),
+		},
+	)
+	suite.NoError(err)
+
+	// the start time defaults to the default window before the end time
+	suite.auditLogOps.EXPECT().
+		GetAll(gomock.Any(), testJobID, endTime.Add(-_defaultListWindow), endTime, uint32(0)).
+		Return(nil, nil)
+	_, err = suite.handler.ListAuditRecords(
+		suite.ctx,
+		&pbaudit.ListAuditRecordsRequest{
+			Target:  testJobID,
+			EndTime: endTime.Format(time.RFC3339),
+		},
+	)
+	suite.NoError(err)
+}
+
+// TestListAuditRecordsInvalidTimeRange tests the invalid time ranges
+// are rejected
+func (suite *auditHandlerTestSuite) TestListAuditRecordsInvalidTimeRange() {
+	for _, req := range []*pbaudit.ListAuditRecordsRequest{
+		{Target: testJobID, StartTime: "invalid"},
+		{Target: testJobID, EndTime: "invalid"},
+		{
+			Target:    testJobID,
+			StartTime: "2019-05-10T12:00:00Z",
+			EndTime:   "2019-05-10T11:00:00Z",
+		},
+	} {
+		_, err := suite.handler.ListAuditRecords(suite.ctx, req)
+		suite.True(yarpcerrors.IsInvalidArgument(err))
+	}
+}
+
+// TestListAuditRecordsFailure tests the failure to read the audit records
+func (suite *auditHandlerTestSuite) TestListAuditRecordsFailure() {
+	suite.auditLogOps.EXPECT().GetAll(gomock.Any(), testJobID, gomock.Any(), gomock.Any(), uint32(0)).
+		Return(nil, errors.New("test error"))
+
+	_, err := suite.handler.ListAuditRecords(
+		suite.ctx,
+		&pbaudit.ListAuditRecordsRequest{Target: testJobID},
+	)
+	suite.Error(err)
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditsvc
+
+import (
+	"github.com/uber-go/tally"
+)
+
+// Metrics is a placeholder for all metrics in audit service.
+type Metrics struct {
+	ListAuditRecordsAPI  tally.Counter
+	ListAuditRecords     tally.Counter
+	ListAuditRecordsFail tally.Counter
+}
+
+// NewMetrics returns a new instance of auditsvc.Metrics.
+func NewMetrics(scope tally.Scope) *Metrics {
+	subScope := scope.SubScope("audit")
+	return &Metrics{
+		ListAuditRecordsAPI:  subScope.Counter("list_api"),
+		ListAuditRecords:     subScope.Counter("list"),
+		ListAuditRecordsFail: subScope.Counter("list_fail"),
+	}
+}
@@ -45,6 +45,18 @@ type JobFactory interface {
 	// GetAllJobs returns the list of all jobs in cache.
 	GetAllJobs() map[string]Job
 
+	// GetResourcePoolPath returns the cached path of a resource pool,
+	// and false if it is not in cache.
+	GetResourcePoolPath(respoolID *peloton.ResourcePoolID) (string, bool)
+
+	// SetResourcePoolPath caches the path of a resource pool.
+	SetResourcePoolPath(respoolID *peloton.ResourcePoolID, path string)
+
+	// ClearResourcePoolPaths clears the cached paths of all the resource
+	// pools, which are stale once a resource pool has been moved to a
+	// new parent.
+	ClearResourcePoolPaths()
+
 	// Start emitting metrics.
 	Start()
 
@@ -56,7 +68,9 @@ type jobFactory struct {
 	sync.RWMutex //  Mutex to acquire before accessing any variables in the job factory object
 
 	// map of active jobs (job identifier -> cache job object) in the system
-	jobs           map[string]*job
+	jobs map[string]*job
+	// map of resource pool identifier -> path of the resource pool
+	respoolPaths   map[string]string
 	running        bool                          // whether job factory is running
 	jobStore       storage.JobStore              // storage job store object
 	taskStore      storage.TaskStore             // storage task store object
@@ -85,6 +99,7 @@ func InitJobFactory(
 	listeners []JobTaskListener) JobFactory {
 	return &jobFactory{
 		jobs:           map[string]*job{},
+		respoolPaths:   map[string]string{},
 		jobStore:       jobStore,
 		taskStore:      taskStore,
 		updateStore:    updateStore,
@@ -150,6 +165,34 @@ func (f *jobFactory) GetAllJobs() map[string]Job {
 	return jobMap
 }
 
+// GetResourcePoolPath returns the cached path of a resource pool
+func (f *jobFactory) GetResourcePoolPath(
+	respoolID *peloton.ResourcePoolID) (string, bool) {
+	f.RLock()
+	defer f.RUnlock()
+
+	path, ok := f.respoolPaths[respoolID.GetValue()]
+	return path, ok
+}
+
+// SetResourcePoolPath caches the path of a resource pool
+func (f *jobFactory) SetResourcePoolPath(
+	respoolID *peloton.ResourcePoolID,
+	path string) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.respoolPaths[respoolID.GetValue()] = path
+}
+
+// ClearResourcePoolPaths clears the cached paths of all the resource pools
+func (f *jobFactory) ClearResourcePoolPaths() {
+	f.Lock()
+	defer f.Unlock()
+
+	f.respoolPaths = map[string]string{}
+}
+
 // Start the job factory, starts emitting metrics.
 func (f *jobFactory) Start() {
 	f.Lock()
@@ -178,6 +221,7 @@ func (f *jobFactory) Stop() {
 
 	f.running = false
 	f.jobs = map[string]*job{}
+	f.respoolPaths = map[string]string{}
 	close(f.stopChan)
 	log.Info("job factory stopped")
 }
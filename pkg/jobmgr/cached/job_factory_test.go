@@ -59,6 +59,30 @@ func TestAddAndGetAndClearJob(t *testing.T) {
 	assert.Nil(t, f.GetJob(jobID))
 }
 
+// TestResourcePoolPaths tests caching and clearing the paths of the
+// resource pools in the factory.
+func TestResourcePoolPaths(t *testing.T) {
+	respoolID := &peloton.ResourcePoolID{Value: uuid.NewRandom().String()}
+
+	f := &jobFactory{
+		jobs:         map[string]*job{},
+		respoolPaths: map[string]string{},
+		running:      true,
+	}
+
+	_, ok := f.GetResourcePoolPath(respoolID)
+	assert.False(t, ok)
+
+	f.SetResourcePoolPath(respoolID, "/parent/child")
+	path, ok := f.GetResourcePoolPath(respoolID)
+	assert.True(t, ok)
+	assert.Equal(t, "/parent/child", path)
+
+	f.ClearResourcePoolPaths()
+	_, ok = f.GetResourcePoolPath(respoolID)
+	assert.False(t, ok)
+}
+
 // TestStartStop tests starting and then stopping the factory.
 func TestStartStop(t *testing.T) {
 	ctrl := gomock.NewController(t)
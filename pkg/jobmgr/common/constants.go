@@ -29,6 +29,7 @@ const (
 	HostField                 = "Host"
 	MesosTaskIDField          = "MesosTaskId"
 	MessageField              = "Message"
+	PendingTimeField          = "PendingTime"
 	PortsField                = "Ports"
 	PrevMesosTaskIDField      = "PrevMesosTaskId"
 	ReasonField               = "Reason"
@@ -51,6 +52,13 @@ const (
 	MaxSystemFailureAttempts = 4
 )
 
+const (
+	// ReasonRelaxedConstraint is the reason of the runtime of a task which
+	// is requeued after exceeding a state timeout. The constraint of the
+	// task is relaxed until the task is initialized again.
+	ReasonRelaxedConstraint = "REASON_REQUEUED_WITH_RELAXED_CONSTRAINT"
+)
+
 const (
 	// DummyConfigVersion is the config version of the dummy config which is used
 	// for job creation. Config with this version means that the config has nothing,
@@ -31,6 +31,7 @@ func TestTaskRuntimeInfoFieldNames(t *testing.T) {
 		GoalStateField,
 		MesosTaskIDField,
 		MessageField,
+		PendingTimeField,
 		PortsField,
 		PrevMesosTaskIDField,
 		ReasonField,
@@ -174,6 +174,7 @@ func NewDriver(
 			d.ClientConfig(common.PelotonHostManager)),
 		resmgrClient: resmgrsvc.NewResourceManagerServiceYARPCClient(
 			d.ClientConfig(common.PelotonResourceManager)),
+		resMgrTaskStates:              newResMgrTaskStates(),
 		jobStore:                      jobStore,
 		taskStore:                     taskStore,
 		volumeStore:                   volumeStore,
@@ -226,6 +227,10 @@ type driver struct {
 	hostmgrClient hostsvc.InternalHostServiceYARPCClient
 	resmgrClient  resmgrsvc.ResourceManagerServiceYARPCClient
 
+	// resMgrTaskStates caches the states of the tasks of jobs in resource
+	// manager, to check their PENDING and PLACING timeouts.
+	resMgrTaskStates *resMgrTaskStates
+
 	// jobStore, taskStore and volumeStore are the objects to the storage interface.
 	jobStore     storage.JobStore
 	taskStore    storage.TaskStore
@@ -16,6 +16,7 @@ package goalstate
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"time"
 
@@ -148,12 +149,14 @@ func sendTasksToResMgr(
 	}
 
 	// Move all task states to pending
+	pendingTime := time.Now().UTC().Format(time.RFC3339Nano)
 	runtimeDiffs := make(map[uint32]jobmgrcommon.RuntimeDiff)
 	for _, tt := range tasks {
 		instID := tt.GetInstanceId()
 		runtimeDiff := jobmgrcommon.RuntimeDiff{
-			jobmgrcommon.StateField:   task.TaskState_PENDING,
-			jobmgrcommon.MessageField: "Task sent for placement",
+			jobmgrcommon.StateField:       task.TaskState_PENDING,
+			jobmgrcommon.MessageField:     "Task sent for placement",
+			jobmgrcommon.PendingTimeField: pendingTime,
 		}
 		runtimeDiffs[instID] = runtimeDiff
 	}
@@ -186,7 +189,7 @@ func recoverTasks(
 	cachedJob := goalStateDriver.jobFactory.AddJob(jobID)
 	maxRunningInstances := jobConfig.GetSLA().GetMaximumRunningInstances()
 	taskRuntimeInfoMap := make(map[uint32]*task.RuntimeInfo)
-	for i := uint32(0); i < jobConfig.InstanceCount; i++ {
+	for _, i := range getRecoverInstanceIDs(taskInfos, jobConfig.InstanceCount) {
 		if _, ok := taskInfos[i]; ok {
 			taskInfo := &task.TaskInfo{
 				JobId:      jobID,
@@ -255,6 +258,32 @@ func recoverTasks(
 	return sendTasksToResMgr(ctx, jobID, tasks, jobConfig, goalStateDriver)
 }
 
+// getRecoverInstanceIDs returns the sorted instance ids of the existing
+// tasks of a partially created job, and the instance ids of the tasks
+// missing to reach the instance count. A batch job scaled down can have
+// gaps in its instance ids, so the existing tasks are recovered whatever
+// their instance ids are, and the missing tasks take the lowest free
+// instance ids.
+func getRecoverInstanceIDs(
+	taskInfos map[uint32]*task.TaskInfo,
+	instanceCount uint32) []uint32 {
+	var instanceIDs []uint32
+	for i := range taskInfos {
+		instanceIDs = append(instanceIDs, i)
+	}
+
+	for i := uint32(0); uint32(len(instanceIDs)) < instanceCount; i++ {
+		if _, ok := taskInfos[i]; !ok {
+			instanceIDs = append(instanceIDs, i)
+		}
+	}
+
+	sort.Slice(instanceIDs, func(i, j int) bool {
+		return instanceIDs[i] < instanceIDs[j]
+	})
+	return instanceIDs
+}
+
 // createAndEnqueueTasks creates all tasks in the job and enqueues them to resource manager.
 func createAndEnqueueTasks(
 	ctx context.Context,
@@ -290,6 +290,99 @@ func (suite *JobCreateTestSuite) TestJobCreateUpdateFailure() {
 	suite.Error(err)
 }
 
+// TestJobRecoverScaledDown tests that the tasks missing in a job scaled
+// down are created in the lowest free instance ids, and that the existing
+// tasks are recovered whatever their instance ids
+func (suite *JobCreateTestSuite) TestJobRecoverScaledDown() {
+	taskInfos := make(map[uint32]*pbtask.TaskInfo)
+	for _, i := range []uint32{0, 2, 5} {
+		taskInfos[i] = &pbtask.TaskInfo{
+			Runtime: &pbtask.RuntimeInfo{
+				State:     pbtask.TaskState_RUNNING,
+				GoalState: pbtask.TaskState_SUCCEEDED,
+			},
+			InstanceId: i,
+			JobId:      suite.jobID,
+		}
+	}
+	// instance 5 is beyond the instance count, but was not sent to
+	// resource manager yet
+	taskInfos[5].Runtime.State = pbtask.TaskState_INITIALIZED
+
+	suite.cachedJob.EXPECT().
+		GetTask(uint32(5)).Return(nil)
+
+	suite.cachedJob.EXPECT().
+		ReplaceTasks(gomock.Any(), false).
+		Return(nil)
+
+	suite.taskStore.EXPECT().
+		GetTasksForJob(gomock.Any(), suite.jobID).
+		Return(taskInfos, nil)
+
+	suite.jobStore.EXPECT().
+		GetJobConfig(gomock.Any(), suite.jobID.GetValue()).
+		Return(suite.jobConfig, &models.ConfigAddOn{}, nil)
+
+	suite.cachedJob.EXPECT().
+		CreateTaskConfigs(gomock.Any(), suite.jobID, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	suite.jobFactory.EXPECT().
+		AddJob(suite.jobID).
+		Return(suite.cachedJob)
+
+	suite.cachedJob.EXPECT().
+		Update(gomock.Any(), gomock.Any(), gomock.Any(), cached.UpdateCacheAndDB).
+		Return(nil)
+
+	suite.cachedJob.EXPECT().
+		CreateTaskRuntimes(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context,
+			runtimes map[uint32]*pbtask.RuntimeInfo,
+			_ string) {
+			suite.Len(runtimes, 1)
+			suite.NotNil(runtimes[1])
+		}).
+		Return(nil)
+
+	suite.resmgrClient.EXPECT().
+		EnqueueGangs(gomock.Any(), gomock.Any()).
+		Return(&resmgrsvc.EnqueueGangsResponse{}, nil)
+
+	suite.jobFactory.EXPECT().
+		GetJob(suite.jobID).
+		Return(suite.cachedJob).
+		Times(2)
+
+	suite.cachedJob.EXPECT().
+		PatchTasks(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context,
+			runtimeDiffs map[uint32]jobmgrcommon.RuntimeDiff) {
+			suite.Len(runtimeDiffs, 2)
+			suite.NotNil(runtimeDiffs[1])
+			suite.NotNil(runtimeDiffs[5])
+		}).
+		Return(nil)
+
+	err := JobCreateTasks(context.Background(), suite.jobEnt)
+	suite.NoError(err)
+}
+
+// TestGetRecoverInstanceIDs tests that the existing tasks are recovered
+// whatever their instance ids, and the missing tasks take the lowest
+// free instance ids
+func (suite *JobCreateTestSuite) TestGetRecoverInstanceIDs() {
+	taskInfos := map[uint32]*pbtask.TaskInfo{
+		0: {InstanceId: 0},
+		2: {InstanceId: 2},
+		5: {InstanceId: 5},
+	}
+	suite.Equal([]uint32{0, 1, 2, 3, 5}, getRecoverInstanceIDs(taskInfos, 5))
+	suite.Equal([]uint32{0, 2, 5}, getRecoverInstanceIDs(taskInfos, 3))
+	suite.Equal([]uint32{0, 1, 2}, getRecoverInstanceIDs(nil, 3))
+}
+
 // These are integration tests within job manager.
 //TODO find a place to put them.
 /*func TestJobCreateTasksWithStore(t *testing.T) {
@@ -545,6 +638,7 @@ func (suite *JobCreateTestSuite) TestJobMaxRunningInstances() {
 			suite.Equal(uint32(len(runtimeDiffs)), suite.jobConfig.SLA.MaximumRunningInstances)
 			for _, runtimeDiff := range runtimeDiffs {
 				suite.Equal(runtimeDiff[jobmgrcommon.StateField], pbtask.TaskState_PENDING)
+				suite.NotEmpty(runtimeDiff[jobmgrcommon.PendingTimeField])
 			}
 		}).
 		Return(nil)
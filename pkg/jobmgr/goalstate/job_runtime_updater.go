@@ -215,6 +215,11 @@ func JobEvaluateMaxRunningInstancesSLA(ctx context.Context, entity goalstate.Ent
 			continue
 		}
 
+		if taskRuntime.GetGoalState() == task.TaskState_DELETED {
+			// Task removed by scaling down the job, ignore.
+			continue
+		}
+
 		taskinfo := &task.TaskInfo{
 			JobId:      jobID,
 			InstanceId: instID,
@@ -288,15 +293,23 @@ func (d *jobStateDeterminer) getState(
 ) (job.JobState, error) {
 	totalInstanceCount := d.config.GetInstanceCount()
 
-	// There are two reasons where state counts can be greater than
+	// There are three reasons where state counts can be greater than
 	// configured instance count
 	// 1. storage materialized view is diverged and till it converges
 	// 2. Workflow to reduce instance count and change spec failed/aborted
+	// 3. Batch job has been scaled down and the removed instances
+	// are not deleted yet, nor in the cache
 	// If Job's goal state is non-terminal then return service job's default
 	// state PENDING
 	// If terminal then continue to evaluate state counts for job runtime state
 	if getTotalInstanceCount(d.stateCounts) > totalInstanceCount {
+		// a batch job cannot complete till the instances removed
+		// are deleted, since the instances left are not known from
+		// the state counts alone
 		if d.config.GetType() == job.JobType_BATCH {
+			if d.stateCounts[task.TaskState_RUNNING.String()] > 0 {
+				return job.JobState_RUNNING, nil
+			}
 			return job.JobState_PENDING, nil
 		}
 
@@ -567,7 +580,6 @@ func JobRuntimeUpdater(ctx context.Context, entity goalstate.Entity) error {
 	}
 
 	stateCounts, err := goalStateDriver.taskStore.GetTaskStateSummaryForJob(ctx, jobID)
-	currStateCounts := stateCounts
 	if err != nil {
 		log.WithError(err).
 			WithField("job_id", id).
@@ -575,6 +587,12 @@ func JobRuntimeUpdater(ctx context.Context, entity goalstate.Entity) error {
 		return err
 	}
 
+	if getTotalInstanceCount(stateCounts) > config.GetInstanceCount() &&
+		config.GetType() == job.JobType_BATCH {
+		stateCounts = getStateCountsWithoutRemovedTasks(cachedJob, stateCounts)
+	}
+	currStateCounts := stateCounts
+
 	var jobState job.JobState
 	jobRuntimeUpdate := &job.RuntimeInfo{}
 	totalInstanceCount := getTotalInstanceCount(currStateCounts)
@@ -690,6 +708,31 @@ func JobRuntimeUpdater(ctx context.Context, entity goalstate.Entity) error {
 	return nil
 }
 
+// getStateCountsWithoutRemovedTasks returns the state counts of a batch
+// job without the tasks removed by scaling down the job, which are not
+// deleted yet. The instance ids of a scaled down batch job are not
+// contiguous, so the removed tasks are found by their goal state in the
+// cache rather than by their instance ids.
+func getStateCountsWithoutRemovedTasks(
+	cachedJob cached.Job,
+	stateCounts map[string]uint32) map[string]uint32 {
+	result := make(map[string]uint32)
+	for state, count := range stateCounts {
+		result[state] = count
+	}
+
+	for _, t := range cachedJob.GetAllTasks() {
+		if t.GoalState().State != task.TaskState_DELETED {
+			continue
+		}
+		state := t.CurrentState().State.String()
+		if result[state] > 0 {
+			result[state]--
+		}
+	}
+	return result
+}
+
 func getTotalInstanceCount(stateCounts map[string]uint32) uint32 {
 	totalInstanceCount := uint32(0)
 	for _, state := range task.TaskState_name {
@@ -1060,6 +1060,10 @@ func (suite *JobRuntimeUpdaterTestSuite) TestJobRuntimeUpdater_InitializedJobWit
 		GetTaskStateSummaryForJob(gomock.Any(), suite.jobID).
 		Return(stateCounts, nil)
 
+	suite.cachedJob.EXPECT().
+		GetAllTasks().
+		Return(nil)
+
 	suite.cachedJob.EXPECT().
 		IsPartiallyCreated(gomock.Any()).
 		Return(true).
@@ -1139,6 +1143,10 @@ func (suite *JobRuntimeUpdaterTestSuite) TestJobRuntimeUpdater_PendingJobWithMor
 		GetTaskStateSummaryForJob(gomock.Any(), suite.jobID).
 		Return(stateCounts, nil)
 
+	suite.cachedJob.EXPECT().
+		GetAllTasks().
+		Return(nil)
+
 	suite.cachedJob.EXPECT().
 		IsPartiallyCreated(gomock.Any()).
 		Return(true).
@@ -1169,6 +1177,101 @@ func (suite *JobRuntimeUpdaterTestSuite) TestJobRuntimeUpdater_PendingJobWithMor
 	suite.NoError(err)
 }
 
+// TestJobRuntimeUpdater_ScaledDownBatchJob tests updating a batch job
+// scaled down, whose removed tasks are not deleted yet
+func (suite *JobRuntimeUpdaterTestSuite) TestJobRuntimeUpdater_ScaledDownBatchJob() {
+	instanceCount := uint32(100)
+	removedCount := uint32(10)
+	suite.cachedConfig.EXPECT().
+		GetInstanceCount().
+		Return(instanceCount - removedCount).
+		AnyTimes()
+
+	startTime, _ := time.Parse(time.RFC3339Nano, jobStartTime)
+	startTimeUnix := float64(startTime.UnixNano()) / float64(time.Second/time.Nanosecond)
+
+	stateCounts := make(map[string]uint32)
+	stateCounts[pbtask.TaskState_SUCCEEDED.String()] = instanceCount
+
+	jobRuntime := pbjob.RuntimeInfo{
+		State:     pbjob.JobState_RUNNING,
+		GoalState: pbjob.JobState_SUCCEEDED,
+		TaskStats: stateCounts,
+	}
+
+	// the removed tasks have non-contiguous instance ids
+	cachedTasks := make(map[uint32]cached.Task)
+	for i := uint32(0); i < instanceCount; i++ {
+		cachedTask := cachedmocks.NewMockTask(suite.ctrl)
+		goalState := pbtask.TaskState_SUCCEEDED
+		if i%removedCount == 1 {
+			goalState = pbtask.TaskState_DELETED
+			cachedTask.EXPECT().CurrentState().Return(cached.TaskStateVector{
+				State: pbtask.TaskState_SUCCEEDED,
+			})
+		}
+		cachedTask.EXPECT().GoalState().Return(cached.TaskStateVector{
+			State: goalState,
+		})
+		cachedTasks[i] = cachedTask
+	}
+
+	suite.jobFactory.EXPECT().
+		AddJob(suite.jobID).
+		Return(suite.cachedJob)
+
+	suite.cachedJob.EXPECT().
+		GetRuntime(gomock.Any()).
+		Return(&jobRuntime, nil)
+
+	suite.cachedJob.EXPECT().
+		GetConfig(gomock.Any()).
+		Return(suite.cachedConfig, nil)
+
+	suite.cachedConfig.EXPECT().
+		HasControllerTask().
+		Return(false)
+
+	suite.cachedConfig.EXPECT().
+		GetType().
+		Return(pbjob.JobType_BATCH).
+		AnyTimes()
+
+	suite.taskStore.EXPECT().
+		GetTaskStateSummaryForJob(gomock.Any(), suite.jobID).
+		Return(stateCounts, nil)
+
+	suite.cachedJob.EXPECT().
+		GetAllTasks().
+		Return(cachedTasks)
+
+	suite.cachedJob.EXPECT().
+		GetFirstTaskUpdateTime().
+		Return(startTimeUnix)
+
+	suite.cachedJob.EXPECT().
+		GetLastTaskUpdateTime().
+		Return(suite.lastUpdateTs)
+
+	suite.cachedJob.EXPECT().
+		Update(gomock.Any(), gomock.Any(), gomock.Any(), cached.UpdateCacheAndDB).
+		Do(func(_ context.Context,
+			jobInfo *pbjob.JobInfo,
+			_ *models.ConfigAddOn,
+			_ cached.UpdateRequest) {
+			suite.Equal(pbjob.JobState_SUCCEEDED, jobInfo.Runtime.State)
+			suite.Equal(instanceCount-removedCount,
+				jobInfo.Runtime.TaskStats[pbtask.TaskState_SUCCEEDED.String()])
+		}).Return(nil)
+
+	suite.jobGoalStateEngine.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any()).
+		Return()
+
+	err := JobRuntimeUpdater(context.Background(), suite.jobEnt)
+	suite.NoError(err)
+}
+
 // TestJobRuntimeUpdater_ControllerTaskSucceeded tests
 // updating a job with controller task succeeded
 func (suite *JobRuntimeUpdaterTestSuite) TestJobRuntimeUpdater_ControllerTaskSucceeded() {
@@ -2079,6 +2182,26 @@ func (suite *JobRuntimeUpdaterTestSuite) TestDetermineBatchJobRuntimeState() {
 			pbjob.JobState_INITIALIZED,
 			"Batch job partially created should be INITIALIZED",
 		},
+		{
+			map[string]uint32{
+				pbtask.TaskState_SUCCEEDED.String(): instanceCount / 2,
+				pbtask.TaskState_KILLED.String():    instanceCount / 2,
+			},
+			instanceCount / 2,
+			pbjob.JobState_RUNNING,
+			pbjob.JobState_PENDING,
+			"Batch job scaled down should not complete before the removed tasks are deleted",
+		},
+		{
+			map[string]uint32{
+				pbtask.TaskState_RUNNING.String(): instanceCount / 2,
+				pbtask.TaskState_KILLING.String(): instanceCount / 2,
+			},
+			instanceCount / 2,
+			pbjob.JobState_RUNNING,
+			pbjob.JobState_RUNNING,
+			"Batch job scaled down with tasks running should be RUNNING",
+		},
 	}
 
 	for index, test := range tests {
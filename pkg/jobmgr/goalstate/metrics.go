@@ -49,6 +49,10 @@ type TaskMetrics struct {
 	RetryFailedLaunchTotal tally.Counter
 	RetryFailedTasksTotal  tally.Counter
 	RetryLostTasksTotal    tally.Counter
+
+	TaskStateTimeoutFail    tally.Counter
+	TaskStateTimeoutAlert   tally.Counter
+	TaskStateTimeoutRequeue tally.Counter
 }
 
 // UpdateMetrics contains all counters to track
@@ -108,6 +112,10 @@ func NewMetrics(scope tally.Scope) *Metrics {
 		RetryFailedLaunchTotal: taskScope.Counter("retry_system_failure_total"),
 		RetryFailedTasksTotal:  taskScope.Counter("retry_failed_total"),
 		RetryLostTasksTotal:    taskScope.Counter("retry_lost_total"),
+
+		TaskStateTimeoutFail:    taskScope.Counter("state_timeout_fail"),
+		TaskStateTimeoutAlert:   taskScope.Counter("state_timeout_alert"),
+		TaskStateTimeoutRequeue: taskScope.Counter("state_timeout_requeue"),
 	}
 
 	updateMetrics := &UpdateMetrics{
@@ -63,6 +63,9 @@ const (
 	// TaskStateInvalidAction is executed when a task enters
 	// invalid current state and goal state combination, and it logs a sentry error
 	TaskStateInvalidAction TaskAction = "state_invalid"
+	// StateTimeoutAction enforces the PENDING and PLACING timeouts
+	// configured in the job SLA for tasks sent for placement
+	StateTimeoutAction TaskAction = "state_timeout"
 )
 
 // _taskActionsMaps maps the task action string to task action function
@@ -79,6 +82,7 @@ var (
 		ExecutorShutdownAction: TaskExecutorShutdown,
 		DeleteAction:           TaskDelete,
 		TaskStateInvalidAction: TaskStateInvalid,
+		StateTimeoutAction:     TaskStateTimeout,
 	}
 )
 
@@ -93,6 +97,7 @@ var (
 		},
 		task.TaskState_RUNNING: {
 			task.TaskState_INITIALIZED: StartAction,
+			task.TaskState_PENDING:     StateTimeoutAction,
 			task.TaskState_LAUNCHED:    LaunchRetryAction,
 			task.TaskState_STARTING:    LaunchRetryAction,
 			task.TaskState_SUCCEEDED:   TerminatedRetryAction,
@@ -103,6 +108,7 @@ var (
 		},
 		task.TaskState_SUCCEEDED: {
 			task.TaskState_INITIALIZED: StartAction,
+			task.TaskState_PENDING:     StateTimeoutAction,
 			task.TaskState_LAUNCHED:    LaunchRetryAction,
 			task.TaskState_STARTING:    LaunchRetryAction,
 			task.TaskState_FAILED:      FailRetryAction,
@@ -30,7 +30,16 @@ import (
 // 2. Sets the goal state depending on the JobType
 // 3. Regenerates a new mesos task ID
 func TaskInitialize(ctx context.Context, entity goalstate.Entity) error {
-	taskEnt := entity.(*taskEntity)
+	return initializeTask(ctx, entity.(*taskEntity), nil)
+}
+
+// initializeTask re-initializes the task, additionally applying the
+// given runtime diff on top of the one regenerating the mesos task ID.
+func initializeTask(
+	ctx context.Context,
+	taskEnt *taskEntity,
+	extraDiff jobmgrcommon.RuntimeDiff,
+) error {
 	goalStateDriver := taskEnt.driver
 	cachedJob := goalStateDriver.jobFactory.GetJob(taskEnt.jobID)
 
@@ -75,6 +84,10 @@ func TaskInitialize(ctx context.Context, entity goalstate.Entity) error {
 			runtime.GetDesiredConfigVersion()
 	}
 
+	for field, value := range extraDiff {
+		runtimeDiff[field] = value
+	}
+
 	err = cachedJob.PatchTasks(ctx,
 		map[uint32]jobmgrcommon.RuntimeDiff{taskEnt.instanceID: runtimeDiff})
 	if err == nil {
@@ -35,6 +35,7 @@ func sendLaunchInfoToResMgr(
 	ctx context.Context,
 	taskEnt *taskEntity,
 	mesosTaskID *mesosv1.TaskID,
+	launchTimeout time.Duration,
 ) error {
 	// Updating resource manager with state as LAUNCHED for the task
 	_, err := taskEnt.driver.resmgrClient.UpdateTasksState(
@@ -59,7 +60,7 @@ func sendLaunchInfoToResMgr(
 	// Starting timeout as we need to track if the task is
 	// launched within timeout period
 	taskEnt.driver.EnqueueTask(taskEnt.jobID, taskEnt.instanceID,
-		time.Now().Add(taskEnt.driver.cfg.LaunchTimeout))
+		time.Now().Add(launchTimeout))
 
 	return nil
 }
@@ -89,16 +90,33 @@ func TaskLaunchRetry(ctx context.Context, entity goalstate.Entity) error {
 
 	switch cachedRuntime.State {
 	case task.TaskState_LAUNCHED:
+		cachedConfig, err := cachedJob.GetConfig(ctx)
+		if err != nil {
+			return err
+		}
+
+		launchTimeout := goalStateDriver.cfg.LaunchTimeout
+		stateTimeout := getActiveStateTimeout(
+			cachedConfig.GetSLA(), cachedRuntime, task.TaskState_LAUNCHED)
+		if stateTimeout != nil {
+			launchTimeout = stateTimeoutDuration(stateTimeout)
+		}
+
 		if time.Now().Sub(
 			time.Unix(0, int64(cachedRuntime.GetRevision().GetUpdatedAt())),
-		) < goalStateDriver.cfg.LaunchTimeout {
+		) < launchTimeout {
 			// LAUNCHED not times out, just send it to resource manager
 			return sendLaunchInfoToResMgr(
 				ctx,
 				taskEnt,
 				cachedRuntime.GetMesosTaskId(),
+				launchTimeout,
 			)
 		}
+		if stateTimeout != nil {
+			return runStateTimeoutAction(
+				ctx, taskEnt, cachedJob, cachedRuntime, stateTimeout)
+		}
 		goalStateDriver.mtx.taskMetrics.TaskLaunchTimeout.Inc(1)
 	case task.TaskState_STARTING:
 		cachedConfig, err := cachedJob.GetConfig(ctx)
@@ -106,18 +124,27 @@ func TaskLaunchRetry(ctx context.Context, entity goalstate.Entity) error {
 			return err
 		}
 
-		if cachedConfig.GetType() == job.JobType_SERVICE {
+		startTimeout := goalStateDriver.cfg.StartTimeout
+		stateTimeout := getActiveStateTimeout(
+			cachedConfig.GetSLA(), cachedRuntime, task.TaskState_STARTING)
+		if stateTimeout != nil {
+			startTimeout = stateTimeoutDuration(stateTimeout)
+		} else if cachedConfig.GetType() == job.JobType_SERVICE {
 			return nil
 		}
 
 		if time.Now().Sub(
 			time.Unix(0, int64(cachedRuntime.GetRevision().GetUpdatedAt())),
-		) < goalStateDriver.cfg.StartTimeout {
+		) < startTimeout {
 			// the job is STARTING on mesos, enqueue the task in case the start timeout
 			goalStateDriver.EnqueueTask(taskEnt.jobID, taskEnt.instanceID,
-				time.Now().Add(goalStateDriver.cfg.StartTimeout))
+				time.Now().Add(startTimeout))
 			return nil
 		}
+		if stateTimeout != nil {
+			return runStateTimeoutAction(
+				ctx, taskEnt, cachedJob, cachedRuntime, stateTimeout)
+		}
 		goalStateDriver.mtx.taskMetrics.TaskStartTimeout.Inc(1)
 	default:
 		log.WithFields(log.Fields{
@@ -138,23 +165,32 @@ func TaskLaunchRetry(ctx context.Context, entity goalstate.Entity) error {
 	}).Info("task timed out, reinitializing the task")
 
 	// kill the old task before intializing a new one as the best effort
-	taskConfig, _, err := goalStateDriver.taskStore.GetTaskConfig(
+	killOrphanTask(ctx, taskEnt, cachedRuntime)
+
+	// going to regenerate the mesos id and enqueue to place it again
+	return TaskInitialize(ctx, entity)
+}
+
+// killOrphanTask kills the launched task on its host as the best effort.
+func killOrphanTask(
+	ctx context.Context,
+	taskEnt *taskEntity,
+	runtime *task.RuntimeInfo,
+) {
+	taskConfig, _, err := taskEnt.driver.taskStore.GetTaskConfig(
 		ctx,
 		taskEnt.jobID,
 		taskEnt.instanceID,
-		cachedRuntime.GetConfigVersion())
+		runtime.GetConfigVersion())
+	if err != nil {
+		return
+	}
 
 	taskInfo := &task.TaskInfo{
 		InstanceId: taskEnt.instanceID,
 		JobId:      taskEnt.jobID,
-		Runtime:    cachedRuntime,
+		Runtime:    runtime,
 		Config:     taskConfig,
 	}
-
-	if err == nil {
-		jobmgrtask.KillOrphanTask(ctx, goalStateDriver.hostmgrClient, taskInfo)
-	}
-
-	// going to regenerate the mesos id and enqueue to place it again
-	return TaskInitialize(ctx, entity)
+	jobmgrtask.KillOrphanTask(ctx, taskEnt.driver.hostmgrClient, taskInfo)
 }
@@ -120,6 +120,12 @@ func (suite *TestTaskLaunchRetrySuite) TestTaskLaunchTimeout() {
 		suite.cachedTask.EXPECT().
 			GetRuntime(gomock.Any()).Return(runtime, nil)
 
+		suite.cachedJob.EXPECT().
+			GetConfig(gomock.Any()).
+			Return(&pb_job.JobConfig{
+				Type: pb_job.JobType_BATCH,
+			}, nil)
+
 		suite.jobFactory.EXPECT().
 			GetJob(suite.jobID).Return(suite.cachedJob)
 
@@ -187,6 +193,12 @@ func (suite *TestTaskLaunchRetrySuite) TestLaunchedTaskSendLaunchInfoResMgr() {
 	suite.cachedTask.EXPECT().
 		GetRuntime(gomock.Any()).Return(runtime, nil)
 
+	suite.cachedJob.EXPECT().
+		GetConfig(gomock.Any()).
+		Return(&pb_job.JobConfig{
+			Type: pb_job.JobType_BATCH,
+		}, nil)
+
 	suite.resmgrClient.EXPECT().
 		UpdateTasksState(gomock.Any(), gomock.Any()).
 		Return(&resmgrsvc.UpdateTasksStateResponse{}, nil)
@@ -216,6 +228,12 @@ func (suite *TestTaskLaunchRetrySuite) TestLaunchRetryError() {
 	suite.cachedTask.EXPECT().
 		GetRuntime(gomock.Any()).Return(runtime, nil)
 
+	suite.cachedJob.EXPECT().
+		GetConfig(gomock.Any()).
+		Return(&pb_job.JobConfig{
+			Type: pb_job.JobType_BATCH,
+		}, nil)
+
 	suite.resmgrClient.EXPECT().
 		UpdateTasksState(gomock.Any(), gomock.Any()).
 		Return(&resmgrsvc.UpdateTasksStateResponse{}, errors.New("error"))
@@ -397,6 +415,114 @@ func (suite *TestTaskLaunchRetrySuite) TestTaskWithUnexpectedStateReenqueue() {
 		suite.getTaskEntity(suite.jobID, suite.instanceID)))
 }
 
+// TestTaskLaunchTimeoutFromJobSLA tests that the LAUNCHED timeout
+// configured in the job SLA overrides the default one
+func (suite *TestTaskLaunchRetrySuite) TestTaskLaunchTimeoutFromJobSLA() {
+	oldMesosTaskID := &mesos_v1.TaskID{
+		Value: &[]string{uuid.New()}[0],
+	}
+	runtime := suite.getRunTime(
+		pb_task.TaskState_LAUNCHED,
+		pb_task.TaskState_SUCCEEDED,
+		oldMesosTaskID)
+	runtime.Revision = &peloton.ChangeLog{
+		UpdatedAt: uint64(time.Now().Add(-2 * time.Minute).UnixNano()),
+	}
+
+	suite.jobFactory.EXPECT().
+		GetJob(suite.jobID).Return(suite.cachedJob)
+
+	suite.cachedJob.EXPECT().
+		GetTask(suite.instanceID).Return(suite.cachedTask)
+
+	suite.cachedTask.EXPECT().
+		GetRuntime(gomock.Any()).Return(runtime, nil)
+
+	suite.cachedJob.EXPECT().
+		GetConfig(gomock.Any()).
+		Return(&pb_job.JobConfig{
+			Type: pb_job.JobType_BATCH,
+			SLA: &pb_job.SlaConfig{
+				StateTimeouts: []*pb_job.StateTimeout{
+					{
+						State:          pb_task.TaskState_LAUNCHED,
+						TimeoutSeconds: 60,
+						Action:         pb_job.StateTimeout_FAIL,
+					},
+				},
+			},
+		}, nil)
+
+	suite.taskStore.EXPECT().GetTaskConfig(
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&pb_task.TaskConfig{}, &models.ConfigAddOn{}, nil)
+
+	suite.mockHostMgr.EXPECT().KillTasks(gomock.Any(), &hostsvc.KillTasksRequest{
+		TaskIds: []*mesos_v1.TaskID{oldMesosTaskID},
+	})
+
+	suite.cachedJob.EXPECT().PatchTasks(gomock.Any(), gomock.Any()).Do(
+		func(_ context.Context, runtimeDiffs map[uint32]jobmgrcommon.RuntimeDiff) {
+			runtimeDiff := runtimeDiffs[suite.instanceID]
+			suite.Equal(pb_task.TaskState_FAILED, runtimeDiff[jobmgrcommon.StateField])
+			suite.Equal("REASON_LAUNCHED_TIMEOUT", runtimeDiff[jobmgrcommon.ReasonField])
+		}).Return(nil)
+
+	suite.cachedJob.EXPECT().
+		GetJobType().Return(pb_job.JobType_BATCH)
+
+	suite.taskGoalStateEngine.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any()).
+		Return()
+
+	suite.jobGoalStateEngine.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any()).
+		Return()
+
+	suite.NoError(TaskLaunchRetry(context.Background(),
+		suite.getTaskEntity(suite.jobID, suite.instanceID)))
+}
+
+// TestStartingServiceTaskWithJobSLATimeout tests that a STARTING task
+// of a service job is tracked if the job SLA has a STARTING timeout
+func (suite *TestTaskLaunchRetrySuite) TestStartingServiceTaskWithJobSLATimeout() {
+	runtime := suite.getRunTime(
+		pb_task.TaskState_STARTING,
+		pb_task.TaskState_RUNNING,
+		nil)
+
+	suite.jobFactory.EXPECT().
+		GetJob(suite.jobID).Return(suite.cachedJob)
+
+	suite.cachedJob.EXPECT().
+		GetTask(suite.instanceID).Return(suite.cachedTask)
+
+	suite.cachedTask.EXPECT().
+		GetRuntime(gomock.Any()).Return(runtime, nil)
+
+	suite.cachedJob.EXPECT().
+		GetConfig(gomock.Any()).
+		Return(&pb_job.JobConfig{
+			Type: pb_job.JobType_SERVICE,
+			SLA: &pb_job.SlaConfig{
+				StateTimeouts: []*pb_job.StateTimeout{
+					{
+						State:          pb_task.TaskState_STARTING,
+						TimeoutSeconds: 600,
+						Action:         pb_job.StateTimeout_ALERT,
+					},
+				},
+			},
+		}, nil)
+
+	suite.taskGoalStateEngine.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any()).
+		Return()
+
+	suite.NoError(TaskLaunchRetry(context.Background(),
+		suite.getTaskEntity(suite.jobID, suite.instanceID)))
+}
+
 // getTaskEntity returns the TaskEntity
 func (suite *TestTaskLaunchRetrySuite) getTaskEntity(
 	jobID *peloton.JobID,
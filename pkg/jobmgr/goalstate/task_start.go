@@ -222,11 +222,21 @@ func TaskStart(ctx context.Context, entity goalstate.Entity) error {
 	runtime := taskInfo.GetRuntime()
 	if runtime.GetState() != task.TaskState_PENDING {
 		runtimeDiff := jobmgrcommon.RuntimeDiff{
-			jobmgrcommon.StateField:   task.TaskState_PENDING,
-			jobmgrcommon.MessageField: "Task sent for placement",
+			jobmgrcommon.StateField:       task.TaskState_PENDING,
+			jobmgrcommon.MessageField:     "Task sent for placement",
+			jobmgrcommon.PendingTimeField: time.Now().UTC().Format(time.RFC3339Nano),
 		}
 		err = cachedJob.PatchTasks(ctx,
 			map[uint32]jobmgrcommon.RuntimeDiff{taskEnt.instanceID: runtimeDiff})
 	}
-	return err
+	if err != nil {
+		return err
+	}
+
+	// check the PENDING and PLACING timeouts of the job, if any
+	if delay, ok := getResMgrStateTimeoutCheckDelay(cachedConfig.GetSLA()); ok {
+		goalStateDriver.EnqueueTask(taskEnt.jobID, taskEnt.instanceID,
+			time.Now().Add(delay))
+	}
+	return nil
 }
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/uber/peloton/.gen/mesos/v1"
 	job2 "github.com/uber/peloton/.gen/peloton/api/v0/job"
@@ -28,6 +29,7 @@ import (
 	"github.com/uber/peloton/.gen/peloton/private/resmgrsvc"
 	resmocks "github.com/uber/peloton/.gen/peloton/private/resmgrsvc/mocks"
 
+	"github.com/uber/peloton/pkg/common/goalstate"
 	goalstatemocks "github.com/uber/peloton/pkg/common/goalstate/mocks"
 	taskutil "github.com/uber/peloton/pkg/common/util/task"
 	cachedmocks "github.com/uber/peloton/pkg/jobmgr/cached/mocks"
@@ -162,16 +164,84 @@ func (suite *TaskStartTestSuite) TestTaskStartStateless() {
 	suite.cachedJob.EXPECT().
 		PatchTasks(gomock.Any(), gomock.Any()).
 		Do(func(ctx context.Context, runtimeDiffs map[uint32]jobmgrcommon.RuntimeDiff) {
-			suite.Equal(runtimeDiffs[suite.instanceID], jobmgrcommon.RuntimeDiff{
-				jobmgrcommon.StateField:   pbtask.TaskState_PENDING,
-				jobmgrcommon.MessageField: "Task sent for placement",
-			})
+			runtimeDiff := runtimeDiffs[suite.instanceID]
+			suite.Equal(pbtask.TaskState_PENDING,
+				runtimeDiff[jobmgrcommon.StateField])
+			suite.Equal("Task sent for placement",
+				runtimeDiff[jobmgrcommon.MessageField])
+			suite.NotEmpty(runtimeDiff[jobmgrcommon.PendingTimeField])
 		}).Return(nil)
 
 	err := TaskStart(context.Background(), suite.taskEnt)
 	suite.NoError(err)
 }
 
+// TestTaskStartWithStateTimeouts tests that a task of a job with
+// PENDING or PLACING timeouts is enqueued to check the timeouts
+func (suite *TaskStartTestSuite) TestTaskStartWithStateTimeouts() {
+	jobConfig := &job2.JobConfig{
+		RespoolID: &peloton.ResourcePoolID{
+			Value: "my-respool-id",
+		},
+	}
+	taskInfo := &pbtask.TaskInfo{
+		InstanceId: suite.instanceID,
+		Config:     &pbtask.TaskConfig{},
+		Runtime:    &pbtask.RuntimeInfo{},
+	}
+
+	suite.jobFactory.EXPECT().
+		GetJob(suite.jobID).
+		Return(suite.cachedJob)
+
+	suite.cachedJob.EXPECT().
+		GetConfig(gomock.Any()).
+		Return(suite.cachedConfig, nil)
+
+	suite.cachedConfig.EXPECT().
+		GetSLA().
+		Return(&job2.SlaConfig{
+			StateTimeouts: []*job2.StateTimeout{
+				{
+					State:          pbtask.TaskState_PLACING,
+					TimeoutSeconds: 600,
+					Action:         job2.StateTimeout_REQUEUE,
+				},
+			},
+		}).
+		AnyTimes()
+
+	suite.cachedConfig.EXPECT().
+		GetRespoolID().
+		Return(jobConfig.RespoolID)
+
+	suite.cachedConfig.EXPECT().
+		GetType().
+		Return(job2.JobType_BATCH).
+		AnyTimes()
+
+	suite.taskStore.EXPECT().
+		GetTaskByID(gomock.Any(), fmt.Sprintf("%s-%d", suite.jobID.GetValue(), suite.instanceID)).
+		Return(taskInfo, nil)
+
+	suite.resmgrClient.EXPECT().
+		EnqueueGangs(gomock.Any(), gomock.Any()).
+		Return(nil, nil)
+
+	suite.cachedJob.EXPECT().
+		PatchTasks(gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	suite.taskGoalStateEngine.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any()).
+		Do(func(_ goalstate.Entity, deadline time.Time) {
+			suite.True(deadline.After(time.Now().Add(590 * time.Second)))
+		})
+
+	err := TaskStart(context.Background(), suite.taskEnt)
+	suite.NoError(err)
+}
+
 func (suite *TaskStartTestSuite) TestTaskStartWithSlaMaxRunningInstances() {
 	jobConfig := &job2.JobConfig{
 		InstanceCount: 2,
@@ -584,10 +654,12 @@ func (suite *TaskStartTestSuite) TestTaskStartStatefulWithoutVolume() {
 	suite.cachedJob.EXPECT().
 		PatchTasks(gomock.Any(), gomock.Any()).
 		Do(func(ctx context.Context, runtimeDiffs map[uint32]jobmgrcommon.RuntimeDiff) {
-			suite.Equal(runtimeDiffs[suite.instanceID], jobmgrcommon.RuntimeDiff{
-				jobmgrcommon.StateField:   pbtask.TaskState_PENDING,
-				jobmgrcommon.MessageField: "Task sent for placement",
-			})
+			runtimeDiff := runtimeDiffs[suite.instanceID]
+			suite.Equal(pbtask.TaskState_PENDING,
+				runtimeDiff[jobmgrcommon.StateField])
+			suite.Equal("Task sent for placement",
+				runtimeDiff[jobmgrcommon.MessageField])
+			suite.NotEmpty(runtimeDiff[jobmgrcommon.PendingTimeField])
 		}).
 		Return(nil)
 
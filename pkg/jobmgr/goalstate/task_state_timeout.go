@@ -0,0 +1,378 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goalstate
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/private/resmgrsvc"
+
+	"github.com/uber/peloton/pkg/common/goalstate"
+	"github.com/uber/peloton/pkg/jobmgr/cached"
+	jobmgrcommon "github.com/uber/peloton/pkg/jobmgr/common"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// _resMgrTimeoutStates are the task states tracked by resource manager
+// for which a state timeout can be configured.
+var _resMgrTimeoutStates = []task.TaskState{
+	task.TaskState_PENDING,
+	task.TaskState_PLACING,
+}
+
+// _resMgrPlacingStates are the states in resource manager of the tasks
+// admitted for placement, to which the PLACING timeout applies.
+var _resMgrPlacingStates = []string{
+	task.TaskState_READY.String(),
+	task.TaskState_PLACING.String(),
+	task.TaskState_PLACED.String(),
+}
+
+// _resMgrTaskStatesTTL is the time for which the states of the tasks of
+// a job fetched from resource manager are reused.
+const _resMgrTaskStatesTTL = 5 * time.Second
+
+// getStateTimeout returns the timeout configured in the job SLA for the
+// given task state, or nil if there is none.
+func getStateTimeout(
+	sla *job.SlaConfig,
+	state task.TaskState,
+) *job.StateTimeout {
+	for _, timeout := range sla.GetStateTimeouts() {
+		if timeout.GetState() == state {
+			return timeout
+		}
+	}
+	return nil
+}
+
+// stateTimeoutDuration returns the duration of a state timeout.
+func stateTimeoutDuration(timeout *job.StateTimeout) time.Duration {
+	return time.Duration(timeout.GetTimeoutSeconds()) * time.Second
+}
+
+// stateTimeoutReason returns the task runtime reason recorded when
+// a task exceeds the timeout of the given state.
+func stateTimeoutReason(state task.TaskState) string {
+	return fmt.Sprintf("REASON_%s_TIMEOUT", state.String())
+}
+
+// getActiveStateTimeout returns the timeout of the given state which still
+// needs to be enforced for the task. A timeout with the ALERT action is
+// enforced only once, so it is skipped if the task has already been alerted.
+func getActiveStateTimeout(
+	sla *job.SlaConfig,
+	runtime *task.RuntimeInfo,
+	state task.TaskState,
+) *job.StateTimeout {
+	timeout := getStateTimeout(sla, state)
+	if timeout == nil {
+		return nil
+	}
+	if timeout.GetAction() == job.StateTimeout_ALERT &&
+		runtime.GetReason() == stateTimeoutReason(state) {
+		return nil
+	}
+	return timeout
+}
+
+// getPendingTime returns the time when the task was last sent for placement.
+// The last update of the runtime is used for the tasks sent for placement
+// before their pending time was recorded.
+func getPendingTime(runtime *task.RuntimeInfo) time.Time {
+	if pendingTime, err := time.Parse(
+		time.RFC3339Nano, runtime.GetPendingTime()); err == nil {
+		return pendingTime
+	}
+	return time.Unix(0, int64(runtime.GetRevision().GetUpdatedAt()))
+}
+
+// getResMgrStateTimeoutCheckDelay returns the delay after which a task sent
+// to resource manager should be checked for its PENDING and PLACING
+// timeouts, and false if the job has no such timeouts.
+func getResMgrStateTimeoutCheckDelay(sla *job.SlaConfig) (time.Duration, bool) {
+	var delay time.Duration
+	for _, state := range _resMgrTimeoutStates {
+		timeout := getStateTimeout(sla, state)
+		if timeout == nil {
+			continue
+		}
+		if d := stateTimeoutDuration(timeout); delay == 0 || d < delay {
+			delay = d
+		}
+	}
+	return delay, delay > 0
+}
+
+// resMgrTaskStates caches the states in resource manager of the tasks of
+// jobs which are admitted for placement, so that the tasks of a job whose
+// timeouts expire together are looked up with a single request.
+type resMgrTaskStates struct {
+	sync.Mutex
+	jobs map[string]*resMgrJobTaskStates
+}
+
+// resMgrJobTaskStates are the states in resource manager of the tasks of
+// a job which are admitted for placement, by mesos task ID.
+type resMgrJobTaskStates struct {
+	sync.Mutex
+	fetchTime time.Time
+	states    map[string]string
+}
+
+// newResMgrTaskStates returns an empty cache of the task states in
+// resource manager.
+func newResMgrTaskStates() *resMgrTaskStates {
+	return &resMgrTaskStates{jobs: make(map[string]*resMgrJobTaskStates)}
+}
+
+// getJob returns the cached task states of a job, and evicts the ones
+// which are stale.
+func (c *resMgrTaskStates) getJob(jobID string, now time.Time) *resMgrJobTaskStates {
+	c.Lock()
+	defer c.Unlock()
+
+	for id, jobStates := range c.jobs {
+		if id != jobID && now.Sub(jobStates.fetchTime) > _resMgrTaskStatesTTL {
+			delete(c.jobs, id)
+		}
+	}
+	jobStates, ok := c.jobs[jobID]
+	if !ok {
+		// not evicted while its tasks are being fetched
+		jobStates = &resMgrJobTaskStates{fetchTime: now}
+		c.jobs[jobID] = jobStates
+	}
+	return jobStates
+}
+
+// getResMgrPlacingState returns the state of the task in resource manager
+// if it is admitted for placement, or an empty string otherwise. All the
+// tasks of the job admitted for placement are fetched at once, and reused
+// for the other tasks of the job for _resMgrTaskStatesTTL.
+func getResMgrPlacingState(
+	ctx context.Context,
+	taskEnt *taskEntity,
+	mesosTaskID string,
+) (string, error) {
+	now := time.Now()
+	jobStates := taskEnt.driver.resMgrTaskStates.getJob(
+		taskEnt.jobID.GetValue(), now)
+
+	jobStates.Lock()
+	defer jobStates.Unlock()
+	if jobStates.states == nil ||
+		now.Sub(jobStates.fetchTime) > _resMgrTaskStatesTTL {
+		resp, err := taskEnt.driver.resmgrClient.GetActiveTasks(
+			ctx,
+			&resmgrsvc.GetActiveTasksRequest{
+				JobID:  taskEnt.jobID.GetValue(),
+				States: _resMgrPlacingStates,
+			})
+		if err != nil {
+			return "", err
+		}
+
+		states := make(map[string]string)
+		for state, entries := range resp.GetTasksByState() {
+			for _, entry := range entries.GetTaskEntry() {
+				states[entry.GetTaskID()] = state
+			}
+		}
+		jobStates.states = states
+		jobStates.fetchTime = now
+	}
+	return jobStates.states[mesosTaskID], nil
+}
+
+// TaskStateTimeout checks whether a task sent for placement has exceeded
+// the PENDING or PLACING timeout configured in its job SLA, and runs the
+// configured action if so. Both timeouts are measured from the pending
+// time recorded in the runtime when the task was sent for placement, so
+// that neither the requeues of the task within resource manager nor the
+// other updates of its runtime restart them. The PENDING timeout applies
+// to the whole time the task waits for placement, while the PLACING
+// timeout applies only once the task is admitted for placement by
+// resource manager. If no timeout has expired yet, the task is enqueued
+// again to be checked at the next deadline.
+func TaskStateTimeout(ctx context.Context, entity goalstate.Entity) error {
+	taskEnt := entity.(*taskEntity)
+	goalStateDriver := taskEnt.driver
+	cachedJob := goalStateDriver.jobFactory.GetJob(taskEnt.jobID)
+	if cachedJob == nil {
+		return nil
+	}
+	cachedTask := cachedJob.GetTask(taskEnt.instanceID)
+	if cachedTask == nil {
+		log.WithFields(log.Fields{
+			"job_id":      taskEnt.jobID.GetValue(),
+			"instance_id": taskEnt.instanceID,
+		}).Error("task is nil in cache with valid job")
+		return nil
+	}
+
+	runtime, err := cachedTask.GetRuntime(ctx)
+	if err != nil {
+		return err
+	}
+
+	cachedConfig, err := cachedJob.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	pendingSince := getPendingTime(runtime)
+	now := time.Now()
+	var nextCheck time.Time
+	for _, state := range _resMgrTimeoutStates {
+		timeout := getActiveStateTimeout(cachedConfig.GetSLA(), runtime, state)
+		if timeout == nil {
+			continue
+		}
+
+		deadline := pendingSince.Add(stateTimeoutDuration(timeout))
+		if now.Before(deadline) {
+			if nextCheck.IsZero() || deadline.Before(nextCheck) {
+				nextCheck = deadline
+			}
+			continue
+		}
+
+		if state == task.TaskState_PLACING {
+			placingState, err := getResMgrPlacingState(
+				ctx, taskEnt, runtime.GetMesosTaskId().GetValue())
+			if err != nil {
+				return err
+			}
+			if placingState == "" {
+				// not admitted for placement yet, check again later
+				delay, _ := getResMgrStateTimeoutCheckDelay(cachedConfig.GetSLA())
+				if nextCheck.IsZero() || now.Add(delay).Before(nextCheck) {
+					nextCheck = now.Add(delay)
+				}
+				continue
+			}
+		}
+		return runStateTimeoutAction(ctx, taskEnt, cachedJob, runtime, timeout)
+	}
+
+	if !nextCheck.IsZero() {
+		goalStateDriver.EnqueueTask(taskEnt.jobID, taskEnt.instanceID, nextCheck)
+	}
+	return nil
+}
+
+// runStateTimeoutAction runs the action configured for a state timeout
+// which the task has exceeded.
+func runStateTimeoutAction(
+	ctx context.Context,
+	taskEnt *taskEntity,
+	cachedJob cached.Job,
+	runtime *task.RuntimeInfo,
+	timeout *job.StateTimeout,
+) error {
+	goalStateDriver := taskEnt.driver
+	log.WithFields(log.Fields{
+		"job_id":          taskEnt.jobID.GetValue(),
+		"instance_id":     taskEnt.instanceID,
+		"mesos_id":        runtime.GetMesosTaskId().GetValue(),
+		"state":           timeout.GetState().String(),
+		"timeout_seconds": timeout.GetTimeoutSeconds(),
+		"action":          timeout.GetAction().String(),
+	}).Info("task exceeded state timeout")
+
+	message := fmt.Sprintf("Task exceeded %s timeout of %d seconds",
+		timeout.GetState().String(), timeout.GetTimeoutSeconds())
+
+	switch timeout.GetAction() {
+	case job.StateTimeout_ALERT:
+		// record the timeout in the task runtime, which creates a pod event
+		runtimeDiff := jobmgrcommon.RuntimeDiff{
+			jobmgrcommon.ReasonField:  stateTimeoutReason(timeout.GetState()),
+			jobmgrcommon.MessageField: message,
+		}
+		if err := cachedJob.PatchTasks(ctx,
+			map[uint32]jobmgrcommon.RuntimeDiff{taskEnt.instanceID: runtimeDiff},
+		); err != nil {
+			return err
+		}
+		goalStateDriver.mtx.taskMetrics.TaskStateTimeoutAlert.Inc(1)
+		// check the timeouts of the other states
+		goalStateDriver.EnqueueTask(taskEnt.jobID, taskEnt.instanceID, time.Now())
+		return nil
+
+	case job.StateTimeout_FAIL:
+		if err := killTimedOutTask(ctx, taskEnt, runtime); err != nil {
+			return err
+		}
+		runtimeDiff := jobmgrcommon.RuntimeDiff{
+			jobmgrcommon.StateField:          task.TaskState_FAILED,
+			jobmgrcommon.ReasonField:         stateTimeoutReason(timeout.GetState()),
+			jobmgrcommon.MessageField:        message,
+			jobmgrcommon.FailureCountField:   runtime.GetFailureCount() + 1,
+			jobmgrcommon.CompletionTimeField: time.Now().UTC().Format(time.RFC3339Nano),
+			jobmgrcommon.TerminationStatusField: &task.TerminationStatus{
+				Reason: task.TerminationStatus_TERMINATION_STATUS_REASON_DEADLINE_TIMEOUT_EXCEEDED,
+			},
+		}
+		if err := cachedJob.PatchTasks(ctx,
+			map[uint32]jobmgrcommon.RuntimeDiff{taskEnt.instanceID: runtimeDiff},
+		); err != nil {
+			return err
+		}
+		goalStateDriver.mtx.taskMetrics.TaskStateTimeoutFail.Inc(1)
+		goalStateDriver.EnqueueTask(taskEnt.jobID, taskEnt.instanceID, time.Now())
+		EnqueueJobWithDefaultDelay(taskEnt.jobID, goalStateDriver, cachedJob)
+		return nil
+
+	case job.StateTimeout_REQUEUE:
+		if err := killTimedOutTask(ctx, taskEnt, runtime); err != nil {
+			return err
+		}
+		goalStateDriver.mtx.taskMetrics.TaskStateTimeoutRequeue.Inc(1)
+		// regenerate the mesos task id and send the task for placement
+		// again without its desired host, and with its constraint relaxed
+		// until the task is initialized again
+		return initializeTask(ctx, taskEnt, jobmgrcommon.RuntimeDiff{
+			jobmgrcommon.DesiredHostField: "",
+			jobmgrcommon.ReasonField:      jobmgrcommon.ReasonRelaxedConstraint,
+			jobmgrcommon.MessageField: message +
+				", requeued with a relaxed constraint",
+		})
+	}
+
+	return nil
+}
+
+// killTimedOutTask kills a task which exceeded its state timeout. Tasks
+// owned by resource manager are killed there, while launched tasks are
+// killed on their host as the best effort.
+func killTimedOutTask(
+	ctx context.Context,
+	taskEnt *taskEntity,
+	runtime *task.RuntimeInfo,
+) error {
+	if cached.IsResMgrOwnedState(runtime.GetState()) {
+		return killTaskInResMgr(ctx, taskEnt)
+	}
+	killOrphanTask(ctx, taskEnt, runtime)
+	return nil
+}
@@ -0,0 +1,441 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goalstate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mesos_v1 "github.com/uber/peloton/.gen/mesos/v1"
+	pbjob "github.com/uber/peloton/.gen/peloton/api/v0/job"
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	pbtask "github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/private/models"
+	"github.com/uber/peloton/.gen/peloton/private/resmgrsvc"
+	resmocks "github.com/uber/peloton/.gen/peloton/private/resmgrsvc/mocks"
+
+	"github.com/uber/peloton/pkg/common/goalstate"
+	goalstatemocks "github.com/uber/peloton/pkg/common/goalstate/mocks"
+	cachedmocks "github.com/uber/peloton/pkg/jobmgr/cached/mocks"
+	jobmgrcommon "github.com/uber/peloton/pkg/jobmgr/common"
+	storemocks "github.com/uber/peloton/pkg/storage/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+)
+
+type TaskStateTimeoutTestSuite struct {
+	suite.Suite
+
+	ctrl                *gomock.Controller
+	taskStore           *storemocks.MockTaskStore
+	jobGoalStateEngine  *goalstatemocks.MockEngine
+	taskGoalStateEngine *goalstatemocks.MockEngine
+	jobFactory          *cachedmocks.MockJobFactory
+	cachedJob           *cachedmocks.MockJob
+	cachedTask          *cachedmocks.MockTask
+	resmgrClient        *resmocks.MockResourceManagerServiceYARPCClient
+	goalStateDriver     *driver
+	jobID               *peloton.JobID
+	instanceID          uint32
+	mesosTaskID         *mesos_v1.TaskID
+	taskEnt             *taskEntity
+}
+
+func TestTaskStateTimeout(t *testing.T) {
+	suite.Run(t, new(TaskStateTimeoutTestSuite))
+}
+
+func (suite *TaskStateTimeoutTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.taskStore = storemocks.NewMockTaskStore(suite.ctrl)
+	suite.jobGoalStateEngine = goalstatemocks.NewMockEngine(suite.ctrl)
+	suite.taskGoalStateEngine = goalstatemocks.NewMockEngine(suite.ctrl)
+	suite.jobFactory = cachedmocks.NewMockJobFactory(suite.ctrl)
+	suite.cachedJob = cachedmocks.NewMockJob(suite.ctrl)
+	suite.cachedTask = cachedmocks.NewMockTask(suite.ctrl)
+	suite.resmgrClient = resmocks.NewMockResourceManagerServiceYARPCClient(suite.ctrl)
+
+	suite.goalStateDriver = &driver{
+		jobEngine:        suite.jobGoalStateEngine,
+		taskEngine:       suite.taskGoalStateEngine,
+		taskStore:        suite.taskStore,
+		jobFactory:       suite.jobFactory,
+		resmgrClient:     suite.resmgrClient,
+		resMgrTaskStates: newResMgrTaskStates(),
+		mtx:              NewMetrics(tally.NoopScope),
+		cfg:              &Config{},
+	}
+	suite.goalStateDriver.cfg.normalize()
+
+	suite.jobID = &peloton.JobID{Value: uuid.NewRandom().String()}
+	suite.instanceID = 0
+	suite.mesosTaskID = &mesos_v1.TaskID{Value: &[]string{uuid.New()}[0]}
+	suite.taskEnt = &taskEntity{
+		jobID:      suite.jobID,
+		instanceID: suite.instanceID,
+		driver:     suite.goalStateDriver,
+	}
+}
+
+func (suite *TaskStateTimeoutTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+// expectTask sets up the expectations to fetch the pending task runtime
+// and the job config with the given state timeouts
+func (suite *TaskStateTimeoutTestSuite) expectTask(
+	runtime *pbtask.RuntimeInfo,
+	timeouts ...*pbjob.StateTimeout,
+) {
+	suite.jobFactory.EXPECT().
+		GetJob(suite.jobID).Return(suite.cachedJob)
+	suite.cachedJob.EXPECT().
+		GetTask(suite.instanceID).Return(suite.cachedTask)
+	suite.cachedTask.EXPECT().
+		GetRuntime(gomock.Any()).Return(runtime, nil)
+	suite.cachedJob.EXPECT().
+		GetConfig(gomock.Any()).
+		Return(&pbjob.JobConfig{
+			Type: pbjob.JobType_BATCH,
+			SLA:  &pbjob.SlaConfig{StateTimeouts: timeouts},
+		}, nil)
+}
+
+// expectResMgrTask sets up the expectation to fetch the tasks of the job
+// admitted for placement from resource manager, with the task in the
+// given state, or not admitted if the state is empty
+func (suite *TaskStateTimeoutTestSuite) expectResMgrTask(state string) {
+	tasksByState := map[string]*resmgrsvc.GetActiveTasksResponse_TaskEntries{
+		pbtask.TaskState_PLACING.String(): {
+			TaskEntry: []*resmgrsvc.GetActiveTasksResponse_TaskEntry{
+				{TaskID: "other-task"},
+			},
+		},
+	}
+	if state != "" {
+		tasksByState[state] = &resmgrsvc.GetActiveTasksResponse_TaskEntries{
+			TaskEntry: []*resmgrsvc.GetActiveTasksResponse_TaskEntry{
+				{TaskID: suite.mesosTaskID.GetValue()},
+			},
+		}
+	}
+
+	suite.resmgrClient.EXPECT().
+		GetActiveTasks(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *resmgrsvc.GetActiveTasksRequest) {
+			// all the tasks of the job admitted for placement are fetched
+			suite.Equal(suite.jobID.GetValue(), req.GetJobID())
+			suite.Equal(_resMgrPlacingStates, req.GetStates())
+			suite.Empty(req.GetTaskIDs())
+		}).
+		Return(&resmgrsvc.GetActiveTasksResponse{
+			TasksByState: tasksByState,
+		}, nil)
+}
+
+// pendingRuntime returns the runtime of a task sent for placement
+// at the given time, and updated since
+func (suite *TaskStateTimeoutTestSuite) pendingRuntime(
+	since time.Time,
+) *pbtask.RuntimeInfo {
+	return &pbtask.RuntimeInfo{
+		State:       pbtask.TaskState_PENDING,
+		GoalState:   pbtask.TaskState_SUCCEEDED,
+		MesosTaskId: suite.mesosTaskID,
+		DesiredHost: "host1",
+		PendingTime: since.UTC().Format(time.RFC3339Nano),
+		Revision: &peloton.ChangeLog{
+			UpdatedAt: uint64(time.Now().UnixNano()),
+		},
+	}
+}
+
+// TestNoStateTimeouts tests that nothing is done for
+// a job without PENDING or PLACING timeouts
+func (suite *TaskStateTimeoutTestSuite) TestNoStateTimeouts() {
+	suite.expectTask(suite.pendingRuntime(time.Now()), &pbjob.StateTimeout{
+		State:          pbtask.TaskState_STARTING,
+		TimeoutSeconds: 10,
+		Action:         pbjob.StateTimeout_FAIL,
+	})
+
+	suite.NoError(TaskStateTimeout(context.Background(), suite.taskEnt))
+}
+
+// TestStateTimeoutNotExpired tests that the task is enqueued again at the
+// first deadline, measured from the time it was sent for placement, if no
+// timeout has expired yet, without looking it up in resource manager
+func (suite *TaskStateTimeoutTestSuite) TestStateTimeoutNotExpired() {
+	suite.expectTask(suite.pendingRuntime(time.Now().Add(-100*time.Second)),
+		&pbjob.StateTimeout{
+			State:          pbtask.TaskState_PENDING,
+			TimeoutSeconds: 600,
+			Action:         pbjob.StateTimeout_FAIL,
+		},
+		&pbjob.StateTimeout{
+			State:          pbtask.TaskState_PLACING,
+			TimeoutSeconds: 300,
+			Action:         pbjob.StateTimeout_FAIL,
+		})
+
+	suite.taskGoalStateEngine.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any()).
+		Do(func(_ goalstate.Entity, deadline time.Time) {
+			// the PLACING deadline is due first
+			suite.WithinDuration(
+				time.Now().Add(200*time.Second), deadline, 5*time.Second)
+		})
+
+	suite.NoError(TaskStateTimeout(context.Background(), suite.taskEnt))
+}
+
+// TestStateTimeoutPlacingNotAdmitted tests that the PLACING timeout does
+// not apply to a task not admitted for placement yet, which is checked
+// again later
+func (suite *TaskStateTimeoutTestSuite) TestStateTimeoutPlacingNotAdmitted() {
+	suite.expectTask(suite.pendingRuntime(time.Now().Add(-2*time.Minute)),
+		&pbjob.StateTimeout{
+			State:          pbtask.TaskState_PLACING,
+			TimeoutSeconds: 60,
+			Action:         pbjob.StateTimeout_FAIL,
+		})
+	suite.expectResMgrTask("")
+
+	suite.taskGoalStateEngine.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any()).
+		Do(func(_ goalstate.Entity, deadline time.Time) {
+			suite.WithinDuration(
+				time.Now().Add(60*time.Second), deadline, 5*time.Second)
+		})
+
+	suite.NoError(TaskStateTimeout(context.Background(), suite.taskEnt))
+}
+
+// TestStateTimeoutResMgrError tests the failure to fetch
+// the task from resource manager
+func (suite *TaskStateTimeoutTestSuite) TestStateTimeoutResMgrError() {
+	suite.expectTask(suite.pendingRuntime(time.Now().Add(-2*time.Minute)),
+		&pbjob.StateTimeout{
+			State:          pbtask.TaskState_PLACING,
+			TimeoutSeconds: 60,
+			Action:         pbjob.StateTimeout_FAIL,
+		})
+	suite.resmgrClient.EXPECT().
+		GetActiveTasks(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("resmgr error"))
+
+	suite.Error(TaskStateTimeout(context.Background(), suite.taskEnt))
+}
+
+// TestStateTimeoutFail tests failing a task which exceeded its PENDING
+// timeout, whatever its state in resource manager
+func (suite *TaskStateTimeoutTestSuite) TestStateTimeoutFail() {
+	suite.expectTask(suite.pendingRuntime(time.Now().Add(-2*time.Minute)),
+		&pbjob.StateTimeout{
+			State:          pbtask.TaskState_PENDING,
+			TimeoutSeconds: 60,
+			Action:         pbjob.StateTimeout_FAIL,
+		})
+
+	suite.resmgrClient.EXPECT().
+		KillTasks(gomock.Any(), &resmgrsvc.KillTasksRequest{
+			Tasks: []*peloton.TaskID{{Value: suite.taskEnt.GetID()}},
+		}).
+		Return(&resmgrsvc.KillTasksResponse{}, nil)
+
+	suite.cachedJob.EXPECT().
+		PatchTasks(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, runtimeDiffs map[uint32]jobmgrcommon.RuntimeDiff) {
+			runtimeDiff := runtimeDiffs[suite.instanceID]
+			suite.Equal(pbtask.TaskState_FAILED, runtimeDiff[jobmgrcommon.StateField])
+			suite.Equal("REASON_PENDING_TIMEOUT", runtimeDiff[jobmgrcommon.ReasonField])
+			suite.Equal(uint32(1), runtimeDiff[jobmgrcommon.FailureCountField])
+		}).
+		Return(nil)
+
+	suite.cachedJob.EXPECT().
+		GetJobType().Return(pbjob.JobType_BATCH)
+	suite.taskGoalStateEngine.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any())
+	suite.jobGoalStateEngine.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any())
+
+	suite.NoError(TaskStateTimeout(context.Background(), suite.taskEnt))
+}
+
+// TestStateTimeoutRequeue tests requeuing a task admitted for placement
+// which exceeded its PLACING timeout, with a relaxed constraint
+func (suite *TaskStateTimeoutTestSuite) TestStateTimeoutRequeue() {
+	runtime := suite.pendingRuntime(time.Now().Add(-2 * time.Minute))
+	suite.expectTask(runtime, &pbjob.StateTimeout{
+		State:          pbtask.TaskState_PLACING,
+		TimeoutSeconds: 60,
+		Action:         pbjob.StateTimeout_REQUEUE,
+	})
+	// the task is requeued by resource manager between placement attempts
+	suite.expectResMgrTask(pbtask.TaskState_READY.String())
+
+	suite.resmgrClient.EXPECT().
+		KillTasks(gomock.Any(), gomock.Any()).
+		Return(&resmgrsvc.KillTasksResponse{}, nil)
+
+	suite.jobFactory.EXPECT().
+		GetJob(suite.jobID).Return(suite.cachedJob)
+	suite.cachedJob.EXPECT().
+		GetTask(suite.instanceID).Return(suite.cachedTask)
+	suite.cachedTask.EXPECT().
+		GetRuntime(gomock.Any()).Return(runtime, nil)
+	suite.taskStore.EXPECT().
+		GetTaskConfig(gomock.Any(), suite.jobID, suite.instanceID, gomock.Any()).
+		Return(&pbtask.TaskConfig{}, &models.ConfigAddOn{}, nil)
+
+	suite.cachedJob.EXPECT().
+		PatchTasks(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, runtimeDiffs map[uint32]jobmgrcommon.RuntimeDiff) {
+			runtimeDiff := runtimeDiffs[suite.instanceID]
+			suite.Equal(pbtask.TaskState_INITIALIZED, runtimeDiff[jobmgrcommon.StateField])
+			suite.Equal("", runtimeDiff[jobmgrcommon.DesiredHostField])
+			suite.Equal(jobmgrcommon.ReasonRelaxedConstraint,
+				runtimeDiff[jobmgrcommon.ReasonField])
+			suite.NotEqual(suite.mesosTaskID, runtimeDiff[jobmgrcommon.MesosTaskIDField])
+		}).
+		Return(nil)
+
+	suite.cachedJob.EXPECT().
+		GetJobType().Return(pbjob.JobType_BATCH)
+	suite.taskGoalStateEngine.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any())
+	suite.jobGoalStateEngine.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any())
+
+	suite.NoError(TaskStateTimeout(context.Background(), suite.taskEnt))
+}
+
+// TestStateTimeoutAlert tests alerting for a task which exceeded
+// its PENDING timeout, and that it is alerted only once
+func (suite *TaskStateTimeoutTestSuite) TestStateTimeoutAlert() {
+	timeout := &pbjob.StateTimeout{
+		State:          pbtask.TaskState_PENDING,
+		TimeoutSeconds: 60,
+		Action:         pbjob.StateTimeout_ALERT,
+	}
+	suite.expectTask(suite.pendingRuntime(time.Now().Add(-2*time.Minute)),
+		timeout)
+
+	suite.cachedJob.EXPECT().
+		PatchTasks(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, runtimeDiffs map[uint32]jobmgrcommon.RuntimeDiff) {
+			runtimeDiff := runtimeDiffs[suite.instanceID]
+			suite.Equal("REASON_PENDING_TIMEOUT", runtimeDiff[jobmgrcommon.ReasonField])
+			_, ok := runtimeDiff[jobmgrcommon.StateField]
+			suite.False(ok)
+		}).
+		Return(nil)
+	suite.taskGoalStateEngine.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any())
+
+	suite.NoError(TaskStateTimeout(context.Background(), suite.taskEnt))
+
+	// the task has already been alerted
+	runtime := suite.pendingRuntime(time.Now().Add(-2 * time.Minute))
+	runtime.Reason = "REASON_PENDING_TIMEOUT"
+	suite.expectTask(runtime, timeout)
+
+	suite.NoError(TaskStateTimeout(context.Background(), suite.taskEnt))
+}
+
+// TestStateTimeoutAfterAlert tests that the PLACING timeout is still
+// measured from the pending time once the task has been alerted for
+// its PENDING timeout, which updated its runtime
+func (suite *TaskStateTimeoutTestSuite) TestStateTimeoutAfterAlert() {
+	runtime := suite.pendingRuntime(time.Now().Add(-2 * time.Minute))
+	runtime.Reason = "REASON_PENDING_TIMEOUT"
+	suite.expectTask(runtime,
+		&pbjob.StateTimeout{
+			State:          pbtask.TaskState_PENDING,
+			TimeoutSeconds: 30,
+			Action:         pbjob.StateTimeout_ALERT,
+		},
+		&pbjob.StateTimeout{
+			State:          pbtask.TaskState_PLACING,
+			TimeoutSeconds: 90,
+			Action:         pbjob.StateTimeout_FAIL,
+		})
+	suite.expectResMgrTask(pbtask.TaskState_PLACING.String())
+
+	suite.resmgrClient.EXPECT().
+		KillTasks(gomock.Any(), gomock.Any()).
+		Return(&resmgrsvc.KillTasksResponse{}, nil)
+	suite.cachedJob.EXPECT().
+		PatchTasks(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, runtimeDiffs map[uint32]jobmgrcommon.RuntimeDiff) {
+			runtimeDiff := runtimeDiffs[suite.instanceID]
+			suite.Equal(pbtask.TaskState_FAILED, runtimeDiff[jobmgrcommon.StateField])
+			suite.Equal("REASON_PLACING_TIMEOUT", runtimeDiff[jobmgrcommon.ReasonField])
+		}).
+		Return(nil)
+
+	suite.cachedJob.EXPECT().
+		GetJobType().Return(pbjob.JobType_BATCH)
+	suite.taskGoalStateEngine.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any())
+	suite.jobGoalStateEngine.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any())
+
+	suite.NoError(TaskStateTimeout(context.Background(), suite.taskEnt))
+}
+
+// TestGetPendingTime tests that the last update of the runtime is used
+// for a task sent for placement before its pending time was recorded
+func (suite *TaskStateTimeoutTestSuite) TestGetPendingTime() {
+	since := time.Now().Add(-time.Minute)
+	runtime := suite.pendingRuntime(since)
+	suite.True(since.Equal(getPendingTime(runtime)))
+
+	runtime.PendingTime = ""
+	runtime.Revision.UpdatedAt = uint64(since.UnixNano())
+	suite.True(since.Equal(getPendingTime(runtime)))
+}
+
+// TestResMgrPlacingStateBatched tests that the tasks of a job are looked
+// up in resource manager with a single request
+func (suite *TaskStateTimeoutTestSuite) TestResMgrPlacingStateBatched() {
+	suite.expectResMgrTask(pbtask.TaskState_PLACED.String())
+
+	state, err := getResMgrPlacingState(
+		context.Background(), suite.taskEnt, suite.mesosTaskID.GetValue())
+	suite.NoError(err)
+	suite.Equal(pbtask.TaskState_PLACED.String(), state)
+
+	otherTaskEnt := &taskEntity{
+		jobID:      suite.jobID,
+		instanceID: 1,
+		driver:     suite.goalStateDriver,
+	}
+	state, err = getResMgrPlacingState(
+		context.Background(), otherTaskEnt, "other-task")
+	suite.NoError(err)
+	suite.Equal(pbtask.TaskState_PLACING.String(), state)
+
+	state, err = getResMgrPlacingState(
+		context.Background(), otherTaskEnt, "unknown-task")
+	suite.NoError(err)
+	suite.Empty(state)
+}
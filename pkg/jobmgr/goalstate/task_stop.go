@@ -77,41 +77,16 @@ func stopInitializedTask(ctx context.Context, taskEnt *taskEntity) error {
 	// TODO: Due to missing atomic updates in DB, there is a race
 	// where we accidentially may start off the task, even though we
 	// have marked it as KILLED.
-	taskID := taskEnt.GetID()
 	goalStateDriver := taskEnt.driver
 	cachedJob := goalStateDriver.jobFactory.GetJob(taskEnt.jobID)
 	if cachedJob == nil {
 		return nil
 	}
 
-	req := &resmgrsvc.KillTasksRequest{
-		Tasks: []*peloton.TaskID{
-			{
-				Value: taskID,
-			},
-		},
-	}
-	// Calling resmgr Kill API
-	res, err := goalStateDriver.resmgrClient.KillTasks(ctx, req)
-	if err != nil {
+	if err := killTaskInResMgr(ctx, taskEnt); err != nil {
 		return err
 	}
 
-	if e := res.GetError(); e != nil {
-		// TODO: As of now this function supports one task
-		// We need to do it for batch
-		if e[0].GetNotFound() != nil {
-			log.WithFields(log.Fields{
-				"Task":  e[0].GetNotFound().Task.Value,
-				"Error": e[0].GetNotFound().Message,
-			}).Info("Task not found in resmgr")
-		} else {
-			return fmt.Errorf("Task %s can not be killed due to %s",
-				e[0].GetKillError().Task.Value,
-				e[0].GetKillError().Message)
-		}
-	}
-
 	cachedTask := cachedJob.GetTask(taskEnt.instanceID)
 	if cachedTask == nil {
 		log.WithFields(log.Fields{
@@ -153,6 +128,39 @@ func stopInitializedTask(ctx context.Context, taskEnt *taskEntity) error {
 	return err
 }
 
+// killTaskInResMgr removes the task from resource manager. A task not
+// found in resource manager is not treated as an error.
+func killTaskInResMgr(ctx context.Context, taskEnt *taskEntity) error {
+	req := &resmgrsvc.KillTasksRequest{
+		Tasks: []*peloton.TaskID{
+			{
+				Value: taskEnt.GetID(),
+			},
+		},
+	}
+	// Calling resmgr Kill API
+	res, err := taskEnt.driver.resmgrClient.KillTasks(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if e := res.GetError(); e != nil {
+		// TODO: As of now this function supports one task
+		// We need to do it for batch
+		if e[0].GetNotFound() != nil {
+			log.WithFields(log.Fields{
+				"Task":  e[0].GetNotFound().Task.Value,
+				"Error": e[0].GetNotFound().Message,
+			}).Info("Task not found in resmgr")
+		} else {
+			return fmt.Errorf("Task %s can not be killed due to %s",
+				e[0].GetKillError().Task.Value,
+				e[0].GetKillError().Message)
+		}
+	}
+	return nil
+}
+
 func stopMesosTask(ctx context.Context, taskEnt *taskEntity, runtime *task.RuntimeInfo) error {
 	goalStateDriver := taskEnt.driver
 	cachedJob := goalStateDriver.jobFactory.GetJob(taskEnt.jobID)
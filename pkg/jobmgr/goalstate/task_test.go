@@ -245,6 +245,26 @@ func TestEngineSuggestActionLaunchedState(t *testing.T) {
 	assert.Equal(t, LaunchRetryAction, a)
 }
 
+func TestEngineSuggestActionPendingState(t *testing.T) {
+	jobID := &peloton.JobID{Value: uuid.NewRandom().String()}
+	instanceID := uint32(0)
+
+	taskEnt := &taskEntity{
+		jobID:      jobID,
+		instanceID: instanceID,
+	}
+
+	for _, goalState := range []pbtask.TaskState{
+		pbtask.TaskState_RUNNING,
+		pbtask.TaskState_SUCCEEDED,
+	} {
+		a := taskEnt.suggestTaskAction(
+			cached.TaskStateVector{State: pbtask.TaskState_PENDING, ConfigVersion: 0},
+			cached.TaskStateVector{State: goalState, ConfigVersion: 0})
+		assert.Equal(t, StateTimeoutAction, a)
+	}
+}
+
 func TestEngineSuggestActionGoalRunning(t *testing.T) {
 	jobID := &peloton.JobID{Value: uuid.NewRandom().String()}
 	instanceID := uint32(0)
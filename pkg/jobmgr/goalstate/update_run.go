@@ -16,6 +16,7 @@ package goalstate
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	pbjob "github.com/uber/peloton/.gen/peloton/api/v0/job"
@@ -206,43 +207,9 @@ func processFailedUpdate(
 			instancesCurrent,
 		)
 
-		if err := cachedJob.RollbackWorkflow(ctx); err != nil {
-			log.WithFields(log.Fields{
-				"update_id": cachedUpdate.ID().GetValue(),
-				"job_id":    cachedJob.ID().GetValue(),
-			}).WithError(err).
-				Info("fail to rollback update")
+		if err := RollbackUpdate(ctx, cachedJob, cachedUpdate); err != nil {
 			return err
 		}
-
-		cachedConfig, err := cachedJob.GetConfig(ctx)
-		if err != nil {
-			log.WithFields(log.Fields{
-				"update_id": cachedUpdate.ID().GetValue(),
-				"job_id":    cachedJob.ID().GetValue(),
-			}).WithError(err).
-				Info("fail to get job config to rollback update")
-			return err
-		}
-
-		if err := handleUnchangedInstancesInUpdate(
-			ctx,
-			cachedUpdate,
-			cachedJob,
-			cachedConfig,
-		); err != nil {
-			log.WithFields(log.Fields{
-				"update_id": cachedUpdate.ID().GetValue(),
-				"job_id":    cachedJob.ID().GetValue(),
-			}).WithError(err).
-				Info("fail to update unchanged instances to rollback update")
-			return err
-		}
-
-		log.WithFields(log.Fields{
-			"update_id": cachedUpdate.ID().GetValue(),
-			"job_id":    cachedJob.ID().GetValue(),
-		}).Info("update rolling back")
 	} else {
 		if err := cachedUpdate.WriteProgress(
 			ctx,
@@ -259,6 +226,54 @@ func processFailedUpdate(
 	return nil
 }
 
+// RollbackUpdate rolls back an update in progress to the job configuration
+// before the update. The configuration version of the instances not touched
+// by the update is moved to the version of the rollback as well.
+func RollbackUpdate(
+	ctx context.Context,
+	cachedJob cached.Job,
+	cachedUpdate cached.Update,
+) error {
+	if err := cachedJob.RollbackWorkflow(ctx); err != nil {
+		log.WithFields(log.Fields{
+			"update_id": cachedUpdate.ID().GetValue(),
+			"job_id":    cachedJob.ID().GetValue(),
+		}).WithError(err).
+			Info("fail to rollback update")
+		return err
+	}
+
+	cachedConfig, err := cachedJob.GetConfig(ctx)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"update_id": cachedUpdate.ID().GetValue(),
+			"job_id":    cachedJob.ID().GetValue(),
+		}).WithError(err).
+			Info("fail to get job config to rollback update")
+		return err
+	}
+
+	if err := handleUnchangedInstancesInUpdate(
+		ctx,
+		cachedUpdate,
+		cachedJob,
+		cachedConfig,
+	); err != nil {
+		log.WithFields(log.Fields{
+			"update_id": cachedUpdate.ID().GetValue(),
+			"job_id":    cachedJob.ID().GetValue(),
+		}).WithError(err).
+			Info("fail to update unchanged instances to rollback update")
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"update_id": cachedUpdate.ID().GetValue(),
+		"job_id":    cachedJob.ID().GetValue(),
+	}).Info("update rolling back")
+	return nil
+}
+
 // isUpdateRollback returns if an update is a rolling back to a
 // previous version
 func isUpdateRollback(cachedUpdate cached.Update) bool {
@@ -829,9 +844,23 @@ func getUnprocessedInstances(
 	instancesRemainToAdd = util.SubtractSlice(update.GetInstancesAdded(), instancesProcessed)
 	instancesRemainToUpdate = util.SubtractSlice(update.GetInstancesUpdated(), instancesProcessed)
 	instancesRemainToRemove = util.SubtractSlice(update.GetInstancesRemoved(), instancesProcessed)
+
+	// process the instances in the order of their instance ids, so that
+	// the batches of a rolling update are deterministic, which is relied
+	// upon by stateful jobs.
+	sortInstances(instancesRemainToAdd)
+	sortInstances(instancesRemainToUpdate)
+	sortInstances(instancesRemainToRemove)
 	return
 }
 
+// sortInstances sorts the instance ids in increasing order.
+func sortInstances(instances []uint32) {
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i] < instances[j]
+	})
+}
+
 // updateWithRecentRunID has primary use case to sync runID from persistent storage
 // for previously removed instance that is added back again.
 //
@@ -3342,3 +3342,30 @@ func newSlice(start uint32, end uint32) []uint32 {
 	}
 	return result
 }
+
+// TestGetInstancesForUpdateRunOrdered tests that the instances of a rolling
+// update are processed in the order of their instance ids
+func (suite *UpdateRunTestSuite) TestGetInstancesForUpdateRunOrdered() {
+	suite.cachedUpdate.EXPECT().
+		GetInstancesAdded().
+		Return([]uint32{5, 4}).
+		AnyTimes()
+	suite.cachedUpdate.EXPECT().
+		GetInstancesUpdated().
+		Return([]uint32{3, 0, 2, 1}).
+		AnyTimes()
+	suite.cachedUpdate.EXPECT().
+		GetInstancesRemoved().
+		Return(nil).
+		AnyTimes()
+	suite.cachedUpdate.EXPECT().
+		GetUpdateConfig().
+		Return(&pbupdate.UpdateConfig{BatchSize: 3}).
+		AnyTimes()
+
+	instancesToAdd, instancesToUpdate, instancesToRemove :=
+		getInstancesForUpdateRun(suite.cachedUpdate, nil, []uint32{0}, nil)
+	suite.Equal([]uint32{4, 5}, instancesToAdd)
+	suite.Equal([]uint32{1}, instancesToUpdate)
+	suite.Empty(instancesToRemove)
+}
@@ -66,6 +66,18 @@ var (
 		"can't override the preemption policy of a task" +
 			" which is going to be a part of a gang having tasks with" +
 			" a different preemption policy")
+	errStateTimeoutZero = yarpcerrors.InvalidArgumentErrorf(
+		"state timeout should be greater than 0")
+	errStateTimeoutActionInvalid = yarpcerrors.InvalidArgumentErrorf(
+		"state timeout action should be set")
+
+	// _stateTimeoutStates are the task states which support a state timeout
+	_stateTimeoutStates = map[task.TaskState]bool{
+		task.TaskState_PENDING:  true,
+		task.TaskState_PLACING:  true,
+		task.TaskState_LAUNCHED: true,
+		task.TaskState_STARTING: true,
+	}
 
 	_jobTypeTaskValidate = map[job.JobType]func(*task.TaskConfig) error{
 		job.JobType_BATCH:   validateBatchTaskConfig,
@@ -175,6 +187,10 @@ func validateTaskConfigWithRange(jobConfig *job.JobConfig, maxTasksPerJob uint32
 		return err
 	}
 
+	if err := validateStateTimeouts(jobConfig.GetSLA()); err != nil {
+		return err
+	}
+
 	// validate ports
 	defaultConfig := jobConfig.GetDefaultConfig()
 	if err := validatePortConfig(defaultConfig); err != nil {
@@ -259,6 +275,32 @@ func validateTaskConfigWithRange(jobConfig *job.JobConfig, maxTasksPerJob uint32
 	return nil
 }
 
+// validateStateTimeouts validates the per-state task timeouts of the job SLA
+func validateStateTimeouts(sla *job.SlaConfig) error {
+	states := make(map[task.TaskState]bool)
+	for _, timeout := range sla.GetStateTimeouts() {
+		if !_stateTimeoutStates[timeout.GetState()] {
+			return yarpcerrors.InvalidArgumentErrorf(
+				"state timeout not supported for state %s",
+				timeout.GetState().String())
+		}
+		if states[timeout.GetState()] {
+			return yarpcerrors.InvalidArgumentErrorf(
+				"more than one state timeout for state %s",
+				timeout.GetState().String())
+		}
+		states[timeout.GetState()] = true
+
+		if timeout.GetTimeoutSeconds() == 0 {
+			return errStateTimeoutZero
+		}
+		if timeout.GetAction() == job.StateTimeout_INVALID {
+			return errStateTimeoutActionInvalid
+		}
+	}
+	return nil
+}
+
 func errInvalidTaskConfig(instanceID uint32, err error) error {
 	return yarpcerrors.InvalidArgumentErrorf(
 		"Invalid config for instance %v, %v", instanceID, err)
@@ -188,6 +188,98 @@ func TestValidateTaskConfigFailureMaxInstances(t *testing.T) {
 	assert.EqualError(t, err, errMaxInstancesTooBig.Error())
 }
 
+// TestValidateStateTimeouts tests validation of the per-state
+// task timeouts in the job SLA
+func TestValidateStateTimeouts(t *testing.T) {
+	jobConfig := job.JobConfig{
+		Name:          "TestJob_1",
+		InstanceCount: 1,
+		DefaultConfig: &task.TaskConfig{
+			Command: &mesos.CommandInfo{
+				Value: util.PtrPrintf("echo Hello"),
+			},
+		},
+	}
+
+	tests := []struct {
+		timeouts []*job.StateTimeout
+		err      bool
+	}{
+		{
+			timeouts: []*job.StateTimeout{
+				{
+					State:          task.TaskState_PENDING,
+					TimeoutSeconds: 60,
+					Action:         job.StateTimeout_FAIL,
+				},
+				{
+					State:          task.TaskState_PLACING,
+					TimeoutSeconds: 60,
+					Action:         job.StateTimeout_REQUEUE,
+				},
+				{
+					State:          task.TaskState_STARTING,
+					TimeoutSeconds: 60,
+					Action:         job.StateTimeout_ALERT,
+				},
+			},
+		},
+		{
+			timeouts: []*job.StateTimeout{
+				{
+					State:          task.TaskState_RUNNING,
+					TimeoutSeconds: 60,
+					Action:         job.StateTimeout_FAIL,
+				},
+			},
+			err: true,
+		},
+		{
+			timeouts: []*job.StateTimeout{
+				{
+					State:          task.TaskState_PENDING,
+					TimeoutSeconds: 60,
+					Action:         job.StateTimeout_FAIL,
+				},
+				{
+					State:          task.TaskState_PENDING,
+					TimeoutSeconds: 30,
+					Action:         job.StateTimeout_ALERT,
+				},
+			},
+			err: true,
+		},
+		{
+			timeouts: []*job.StateTimeout{
+				{
+					State:  task.TaskState_LAUNCHED,
+					Action: job.StateTimeout_FAIL,
+				},
+			},
+			err: true,
+		},
+		{
+			timeouts: []*job.StateTimeout{
+				{
+					State:          task.TaskState_LAUNCHED,
+					TimeoutSeconds: 60,
+				},
+			},
+			err: true,
+		},
+	}
+
+	for i, test := range tests {
+		jobConfig.SLA = &job.SlaConfig{StateTimeouts: test.timeouts}
+		err := ValidateConfig(&jobConfig, maxTasksPerJob)
+		if test.err {
+			assert.Error(t, err, "test %d", i)
+		} else {
+			assert.NoError(t, err, "test %d", i)
+		}
+	}
+}
+
 func TestValidateTaskConfigMaxFailureRetries(t *testing.T) {
 	// No error if there is a default task config
 	taskConfig := task.TaskConfig{
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"sort"
 	"time"
 
 	mesos "github.com/uber/peloton/.gen/mesos/v1"
@@ -37,11 +38,13 @@ import (
 	versionutil "github.com/uber/peloton/pkg/common/util/entityversion"
 	yarpcutil "github.com/uber/peloton/pkg/common/util/yarpc"
 	"github.com/uber/peloton/pkg/jobmgr/cached"
+	jobmgrcommon "github.com/uber/peloton/pkg/jobmgr/common"
 	"github.com/uber/peloton/pkg/jobmgr/goalstate"
 	"github.com/uber/peloton/pkg/jobmgr/job/config"
 	jobmgrtask "github.com/uber/peloton/pkg/jobmgr/task"
 	"github.com/uber/peloton/pkg/jobmgr/util/handler"
 	jobutil "github.com/uber/peloton/pkg/jobmgr/util/job"
+	updateutil "github.com/uber/peloton/pkg/jobmgr/util/update"
 	"github.com/uber/peloton/pkg/storage"
 	ormobjects "github.com/uber/peloton/pkg/storage/objects"
 
@@ -53,6 +56,14 @@ import (
 	"go.uber.org/yarpc/yarpcerrors"
 )
 
+// _defaultDisruptionDays is the number of days to look back for the
+// disruptions of a job if not specified in the request
+const _defaultDisruptionDays = 7
+
+// _maxDisruptionDays is the maximum number of days to look back for the
+// disruptions of a job, the disruptions expire after that
+const _maxDisruptionDays = uint32(ormobjects.JobDisruptionTTL / (24 * time.Hour))
+
 var (
 	errNullResourcePoolID   = errors.New("resource pool ID is null")
 	errResourcePoolNotFound = errors.New("resource pool not found")
@@ -72,7 +83,7 @@ func InitServiceHandler(
 	goalStateDriver goalstate.Driver,
 	candidate leader.Candidate,
 	clientName string,
-	jobSvcCfg Config) {
+	jobSvcCfg Config) job.JobManagerYARPCServer {
 
 	jobSvcCfg.normalize()
 	handler := &serviceHandler{
@@ -81,6 +92,7 @@ func InitServiceHandler(
 		jobIndexOps:     ormobjects.NewJobIndexOps(ormStore),
 		jobConfigOps:    ormobjects.NewJobConfigOps(ormStore),
 		secretInfoOps:   ormobjects.NewSecretInfoOps(ormStore),
+		disruptionOps:   ormobjects.NewJobDisruptionOps(ormStore),
 		respoolClient:   respool.NewResourceManagerYARPCClient(d.ClientConfig(clientName)),
 		resmgrClient:    resmgrsvc.NewResourceManagerServiceYARPCClient(d.ClientConfig(clientName)),
 		rootCtx:         context.Background(),
@@ -92,6 +104,7 @@ func InitServiceHandler(
 	}
 
 	d.Register(job.BuildJobManagerYARPCProcedures(handler))
+	return handler
 }
 
 // serviceHandler implements peloton.api.job.JobManager
@@ -101,6 +114,7 @@ type serviceHandler struct {
 	jobIndexOps     ormobjects.JobIndexOps
 	jobConfigOps    ormobjects.JobConfigOps
 	secretInfoOps   ormobjects.SecretInfoOps
+	disruptionOps   ormobjects.JobDisruptionOps
 	respoolClient   respool.ResourceManagerYARPCClient
 	resmgrClient    resmgrsvc.ResourceManagerServiceYARPCClient
 	rootCtx         context.Context
@@ -1002,6 +1016,480 @@ func (h *serviceHandler) GetActiveJobs(
 	}, nil
 }
 
+// GetDisruptions returns the disruptions of the instances of a job by
+// Peloton over the last days, aggregated by their cause
+func (h *serviceHandler) GetDisruptions(
+	ctx context.Context,
+	req *job.GetDisruptionsRequest) (resp *job.GetDisruptionsResponse, err error) {
+	defer func() {
+		headers := yarpcutil.GetHeaders(ctx)
+
+		if err != nil {
+			log.WithField("job_id", req.GetId().GetValue()).
+				WithField("headers", headers).
+				WithError(err).
+				Warn("JobManager.GetDisruptions failed")
+			return
+		}
+
+		log.WithField("job_id", req.GetId().GetValue()).
+			WithField("headers", headers).
+			Debug("JobManager.GetDisruptions succeeded")
+	}()
+
+	h.metrics.JobAPIGetDisruptions.Inc(1)
+
+	days := req.GetDays()
+	if days == 0 {
+		days = _defaultDisruptionDays
+	}
+	if days > _maxDisruptionDays {
+		h.metrics.JobGetDisruptionsFail.Inc(1)
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"days can not be more than %d", _maxDisruptionDays)
+	}
+
+	jobIndex, err := h.jobIndexOps.Get(ctx, req.GetId())
+	if err != nil {
+		h.metrics.JobGetDisruptionsFail.Inc(1)
+		if yarpcerrors.IsNotFound(err) {
+			return nil, yarpcerrors.NotFoundErrorf("job not found")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	since := now.Add(-time.Duration(days) * 24 * time.Hour)
+	disruptions, err := h.disruptionOps.GetAll(
+		ctx, req.GetId().GetValue(), since)
+	if err != nil {
+		h.metrics.JobGetDisruptionsFail.Inc(1)
+		return nil, err
+	}
+
+	var jobRuntime *job.RuntimeInfo
+	taskRuntimes := make(map[uint32]*task.RuntimeInfo)
+	summaries := make(map[task.TerminationStatus_Reason]*job.DisruptionSummary)
+	var causes []task.TerminationStatus_Reason
+	for _, d := range disruptions {
+		cause := task.TerminationStatus_Reason(
+			task.TerminationStatus_Reason_value[d.Cause])
+		summary, ok := summaries[cause]
+		if !ok {
+			summary = &job.DisruptionSummary{Cause: cause}
+			summaries[cause] = summary
+			causes = append(causes, cause)
+		}
+		summary.Count++
+		if d.Resolved {
+			summary.DurationSeconds += d.Duration.Seconds()
+			continue
+		}
+
+		if jobRuntime == nil {
+			jobRuntime, err = h.jobStore.GetJobRuntime(
+				ctx, req.GetId().GetValue())
+			if err != nil {
+				h.metrics.JobGetDisruptionsFail.Inc(1)
+				return nil, err
+			}
+		}
+		end, ended, err := h.getDisruptionEnd(
+			ctx, req.GetId(), d, jobRuntime, taskRuntimes)
+		if err != nil {
+			h.metrics.JobGetDisruptionsFail.Inc(1)
+			return nil, err
+		}
+		if !ended {
+			// the instance is not running again yet
+			summary.PendingCount++
+			end = now
+		}
+		summary.DurationSeconds += end.Sub(d.Time).Seconds()
+	}
+
+	resp = &job.GetDisruptionsResponse{
+		InstanceCount: jobIndex.InstanceCount,
+		Days:          days,
+	}
+	for _, cause := range causes {
+		resp.Disruptions = append(resp.Disruptions, summaries[cause])
+	}
+
+	h.metrics.JobGetDisruptions.Inc(1)
+	return resp, nil
+}
+
+// getDisruptionEnd returns the end of a disruption whose duration is not
+// recorded, if its instance is not going to run again: the completion of
+// its job if the job is terminal or being deleted, or the termination of
+// its instance if it was killed or removed. It returns false if the
+// instance may still run again. The task runtimes read are cached in
+// taskRuntimes.
+func (h *serviceHandler) getDisruptionEnd(
+	ctx context.Context,
+	jobID *peloton.JobID,
+	d *ormobjects.JobDisruption,
+	jobRuntime *job.RuntimeInfo,
+	taskRuntimes map[uint32]*task.RuntimeInfo,
+) (time.Time, bool, error) {
+	if util.IsPelotonJobStateTerminal(jobRuntime.GetState()) ||
+		jobRuntime.GetGoalState() == job.JobState_DELETED {
+		return getDisruptionEndTime(
+			d,
+			jobRuntime.GetCompletionTime(),
+			jobRuntime.GetRevision().GetUpdatedAt(),
+		), true, nil
+	}
+
+	runtime, ok := taskRuntimes[d.InstanceID]
+	if !ok {
+		var err error
+		runtime, err = h.taskStore.GetTaskRuntime(ctx, jobID, d.InstanceID)
+		if err != nil && !yarpcerrors.IsNotFound(err) {
+			return time.Time{}, false, err
+		}
+		taskRuntimes[d.InstanceID] = runtime
+	}
+
+	if runtime == nil {
+		// the instance was removed from the job, at an unknown time
+		return d.Time, true, nil
+	}
+	if util.IsPelotonStateTerminal(runtime.GetState()) &&
+		(runtime.GetGoalState() == task.TaskState_KILLED ||
+			runtime.GetGoalState() == task.TaskState_DELETED) {
+		return getDisruptionEndTime(
+			d,
+			runtime.GetCompletionTime(),
+			runtime.GetRevision().GetUpdatedAt(),
+		), true, nil
+	}
+	return time.Time{}, false, nil
+}
+
+// getDisruptionEndTime returns the completion time of the job or instance
+// which ended a disruption, or the time of its last runtime update if the
+// completion time is not set, and no earlier than the disruption.
+func getDisruptionEndTime(
+	d *ormobjects.JobDisruption,
+	completionTime string,
+	updatedAt uint64,
+) time.Time {
+	end, err := time.Parse(time.RFC3339Nano, completionTime)
+	if err != nil {
+		end = time.Unix(0, int64(updatedAt))
+	}
+	if end.Before(d.Time) {
+		return d.Time
+	}
+	return end
+}
+
+// Scale changes the number of instances of a running batch job without
+// going through a job update. New instances take the lowest free instance
+// ids, and instances which are not launched yet are removed first.
+func (h *serviceHandler) Scale(
+	ctx context.Context,
+	req *job.ScaleRequest) (resp *job.ScaleResponse, err error) {
+	defer func() {
+		headers := yarpcutil.GetHeaders(ctx)
+
+		if err != nil {
+			log.WithField("job_id", req.GetId().GetValue()).
+				WithField("instance_count", req.GetInstanceCount()).
+				WithField("headers", headers).
+				WithError(err).
+				Warn("JobManager.Scale failed")
+			return
+		}
+
+		log.WithField("job_id", req.GetId().GetValue()).
+			WithField("response", resp).
+			WithField("headers", headers).
+			Info("JobManager.Scale succeeded")
+	}()
+
+	h.metrics.JobAPIScale.Inc(1)
+
+	if !h.candidate.IsLeader() {
+		h.metrics.JobScaleFail.Inc(1)
+		return nil, yarpcerrors.UnavailableErrorf(
+			"Job Scale API not suppported on non-leader")
+	}
+
+	jobID := req.GetId()
+	cachedJob := h.jobFactory.AddJob(jobID)
+	jobRuntime, err := cachedJob.GetRuntime(ctx)
+	if err != nil {
+		h.metrics.JobScaleFail.Inc(1)
+		return nil, err
+	}
+
+	if util.IsPelotonJobStateTerminal(jobRuntime.GetState()) ||
+		jobRuntime.GetGoalState() == job.JobState_KILLED ||
+		jobRuntime.GetGoalState() == job.JobState_DELETED {
+		h.metrics.JobScaleFail.Inc(1)
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"cannot scale job in state %s with goal state %s",
+			jobRuntime.GetState(), jobRuntime.GetGoalState())
+	}
+
+	if updateutil.HasUpdate(jobRuntime) {
+		h.metrics.JobScaleFail.Inc(1)
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"cannot scale job with an update in progress")
+	}
+
+	oldConfig, configAddOn, err := h.jobConfigOps.Get(
+		ctx,
+		jobID,
+		jobRuntime.GetConfigurationVersion())
+	if err != nil {
+		h.metrics.JobScaleFail.Inc(1)
+		return nil, err
+	}
+
+	if oldConfig.GetType() != job.JobType_BATCH {
+		h.metrics.JobScaleFail.Inc(1)
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"job scale is only supported for batch jobs")
+	}
+
+	if err := validateScaleInstanceCount(
+		oldConfig,
+		req.GetInstanceCount(),
+		h.jobSvcCfg.MaxTasksPerJob); err != nil {
+		h.metrics.JobScaleFail.Inc(1)
+		return nil, err
+	}
+
+	tasks := cachedJob.GetAllTasks()
+	for _, t := range tasks {
+		if t.GoalState().State == task.TaskState_DELETED {
+			h.metrics.JobScaleFail.Inc(1)
+			return nil, yarpcerrors.InvalidArgumentErrorf(
+				"cannot scale job while instances of a previous scale down " +
+					"are being removed")
+		}
+	}
+
+	resp = &job.ScaleResponse{Id: jobID}
+	instanceCount := req.GetInstanceCount()
+	if instanceCount == oldConfig.GetInstanceCount() {
+		h.metrics.JobScale.Inc(1)
+		return resp, nil
+	}
+
+	// the instances of a partially created job which are not created yet
+	// are created by the goal state engine in the lowest free instance ids
+	var numNotCreated uint32
+	if oldConfig.GetInstanceCount() > uint32(len(tasks)) {
+		numNotCreated = oldConfig.GetInstanceCount() - uint32(len(tasks))
+	}
+
+	var tasksToRemove []uint32
+	if instanceCount > oldConfig.GetInstanceCount() {
+		resp.InstancesAdded = getFreeInstanceIDs(
+			tasks,
+			numNotCreated+instanceCount-oldConfig.GetInstanceCount(),
+		)[numNotCreated:]
+	} else {
+		// the instances not created yet are removed first, they
+		// are dropped by just decreasing the instance count
+		count := oldConfig.GetInstanceCount() - instanceCount
+		notCreated := getFreeInstanceIDs(tasks, numNotCreated)
+		if count < numNotCreated {
+			notCreated = notCreated[numNotCreated-count:]
+		}
+
+		tasksToRemove, err = getInstancesToRemove(
+			ctx,
+			oldConfig,
+			tasks,
+			count-uint32(len(notCreated)))
+		if err != nil {
+			h.metrics.JobScaleFail.Inc(1)
+			return nil, err
+		}
+
+		resp.InstancesRemoved = append(
+			append([]uint32{}, tasksToRemove...),
+			notCreated...)
+		sort.Slice(resp.InstancesRemoved, func(i, j int) bool {
+			return resp.InstancesRemoved[i] < resp.InstancesRemoved[j]
+		})
+	}
+
+	newConfig := *oldConfig
+	newConfig.InstanceCount = instanceCount
+	updatedConfig, err := cachedJob.CompareAndSetConfig(
+		ctx,
+		&newConfig,
+		configAddOn)
+	if err != nil {
+		h.metrics.JobScaleFail.Inc(1)
+		return nil, err
+	}
+
+	runtime := &job.RuntimeInfo{
+		ConfigurationVersion: updatedConfig.GetChangeLog().GetVersion(),
+	}
+	// the new instances are created by the goal state engine
+	// like for a partially created job
+	if len(resp.GetInstancesAdded()) > 0 {
+		runtime.State = job.JobState_INITIALIZED
+	}
+	err = cachedJob.Update(ctx, &job.JobInfo{
+		Runtime: runtime,
+	}, nil,
+		cached.UpdateCacheAndDB)
+	if err != nil {
+		h.metrics.JobScaleFail.Inc(1)
+		return nil, err
+	}
+
+	if len(tasksToRemove) > 0 {
+		runtimeDiffs := make(map[uint32]jobmgrcommon.RuntimeDiff)
+		for _, instID := range tasksToRemove {
+			runtimeDiffs[instID] = jobmgrcommon.RuntimeDiff{
+				jobmgrcommon.GoalStateField: task.TaskState_DELETED,
+				jobmgrcommon.MessageField:   "Task removed by scaling down the job",
+			}
+		}
+		if err := cachedJob.PatchTasks(ctx, runtimeDiffs); err != nil {
+			h.metrics.JobScaleFail.Inc(1)
+			return nil, err
+		}
+
+		for _, instID := range tasksToRemove {
+			h.goalStateDriver.EnqueueTask(jobID, instID, time.Now())
+		}
+	}
+
+	h.goalStateDriver.EnqueueJob(jobID, time.Now())
+
+	h.metrics.JobScale.Inc(1)
+	return resp, nil
+}
+
+// getInstancesToRemove returns the instances of a batch job to remove
+// to scale it down. Instances which have not been launched yet are
+// removed first, then the launched ones and then the running ones.
+// Among the instances in the same state, the ones with the highest
+// instance id are removed first. Completed instances and the controller
+// task are never removed.
+func getInstancesToRemove(
+	ctx context.Context,
+	jobConfig *job.JobConfig,
+	tasks map[uint32]cached.Task,
+	count uint32,
+) ([]uint32, error) {
+	type candidate struct {
+		instanceID uint32
+		rank       int
+	}
+
+	var candidates []candidate
+	for instID, t := range tasks {
+		if instID == 0 && cached.HasControllerTask(jobConfig) {
+			continue
+		}
+
+		runtime, err := t.GetRuntime(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		state := runtime.GetState()
+		if util.IsPelotonStateTerminal(state) {
+			continue
+		}
+
+		rank := 2
+		switch {
+		case state == task.TaskState_INITIALIZED ||
+			cached.IsResMgrOwnedState(state):
+			rank = 0
+		case state == task.TaskState_LAUNCHED ||
+			state == task.TaskState_STARTING:
+			rank = 1
+		}
+		candidates = append(candidates, candidate{
+			instanceID: instID,
+			rank:       rank,
+		})
+	}
+
+	if uint32(len(candidates)) < count {
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"cannot remove %d instances, only %d instances are not completed",
+			count, len(candidates))
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].rank != candidates[j].rank {
+			return candidates[i].rank < candidates[j].rank
+		}
+		return candidates[i].instanceID > candidates[j].instanceID
+	})
+
+	var instances []uint32
+	for _, c := range candidates[:count] {
+		instances = append(instances, c.instanceID)
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i] < instances[j]
+	})
+	return instances, nil
+}
+
+// validateScaleInstanceCount validates the new instance count
+// of a batch job being scaled
+func validateScaleInstanceCount(
+	jobConfig *job.JobConfig,
+	instanceCount uint32,
+	maxTasksPerJob uint32,
+) error {
+	if instanceCount == 0 {
+		return yarpcerrors.InvalidArgumentErrorf(
+			"instance count of a job cannot be scaled to 0")
+	}
+
+	if instanceCount > maxTasksPerJob {
+		return yarpcerrors.InvalidArgumentErrorf(
+			"Requested tasks: %v for job is greater than supported: %v tasks/job",
+			instanceCount, maxTasksPerJob)
+	}
+
+	if instanceCount < jobConfig.GetSLA().GetMaximumRunningInstances() {
+		return yarpcerrors.InvalidArgumentErrorf(
+			"instance count cannot be less than the maximum running "+
+				"instances of the job: %v",
+			jobConfig.GetSLA().GetMaximumRunningInstances())
+	}
+
+	if instanceCount < jobConfig.GetSLA().GetMinimumRunningInstances() {
+		return yarpcerrors.InvalidArgumentErrorf(
+			"instance count cannot be less than the minimum running "+
+				"instances of the job: %v",
+			jobConfig.GetSLA().GetMinimumRunningInstances())
+	}
+	return nil
+}
+
+// getFreeInstanceIDs returns the lowest count instance ids
+// not used by the tasks of a job
+func getFreeInstanceIDs(tasks map[uint32]cached.Task, count uint32) []uint32 {
+	var instances []uint32
+	for i := uint32(0); uint32(len(instances)) < count; i++ {
+		if _, ok := tasks[i]; !ok {
+			instances = append(instances, i)
+		}
+	}
+	return instances
+}
+
 // validateResourcePool validates the resource pool before submitting job
 func (h *serviceHandler) validateResourcePool(
 	respoolID *peloton.ResourcePoolID,
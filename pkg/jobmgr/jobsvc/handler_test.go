@@ -40,9 +40,11 @@ import (
 	"github.com/uber/peloton/pkg/jobmgr/cached"
 	cachedmocks "github.com/uber/peloton/pkg/jobmgr/cached/mocks"
 	cachedtest "github.com/uber/peloton/pkg/jobmgr/cached/test"
+	jobmgrcommon "github.com/uber/peloton/pkg/jobmgr/common"
 	goalstatemocks "github.com/uber/peloton/pkg/jobmgr/goalstate/mocks"
 	jobmgrtask "github.com/uber/peloton/pkg/jobmgr/task"
 	storemocks "github.com/uber/peloton/pkg/storage/mocks"
+	ormobjects "github.com/uber/peloton/pkg/storage/objects"
 	objectmocks "github.com/uber/peloton/pkg/storage/objects/mocks"
 
 	"github.com/golang/mock/gomock"
@@ -94,6 +96,7 @@ type JobHandlerTestSuite struct {
 	mockedJobIndexOps     *objectmocks.MockJobIndexOps
 	mockedSecretInfoOps   *objectmocks.MockSecretInfoOps
 	mockedJobConfigOps    *objectmocks.MockJobConfigOps
+	mockedDisruptionOps   *objectmocks.MockJobDisruptionOps
 }
 
 // helper to initialize mocks in JobHandlerTestSuite
@@ -112,12 +115,14 @@ func (suite *JobHandlerTestSuite) initializeMocks() {
 	suite.mockedJobIndexOps = objectmocks.NewMockJobIndexOps(suite.ctrl)
 	suite.mockedSecretInfoOps = objectmocks.NewMockSecretInfoOps(suite.ctrl)
 	suite.mockedJobConfigOps = objectmocks.NewMockJobConfigOps(suite.ctrl)
+	suite.mockedDisruptionOps = objectmocks.NewMockJobDisruptionOps(suite.ctrl)
 
 	suite.handler.jobStore = suite.mockedJobStore
 	suite.handler.taskStore = suite.mockedTaskStore
 	suite.handler.jobIndexOps = suite.mockedJobIndexOps
 	suite.handler.jobConfigOps = suite.mockedJobConfigOps
 	suite.handler.secretInfoOps = suite.mockedSecretInfoOps
+	suite.handler.disruptionOps = suite.mockedDisruptionOps
 	suite.handler.jobFactory = suite.mockedJobFactory
 	suite.handler.goalStateDriver = suite.mockedGoalStateDriver
 	suite.handler.respoolClient = suite.mockedRespoolClient
@@ -1937,6 +1942,532 @@ func (suite *JobHandlerTestSuite) TestJobGetActiveJobs() {
 	suite.Equal(resp.GetIds(), expectedJobIDs)
 }
 
+// TestGetDisruptions tests getting the disruptions of a job by cause
+func (suite *JobHandlerTestSuite) TestGetDisruptions() {
+	now := time.Now()
+	suite.mockedJobIndexOps.EXPECT().
+		Get(context.Background(), suite.testJobID).
+		Return(&ormobjects.JobIndexObject{InstanceCount: testInstanceCount}, nil)
+	suite.mockedDisruptionOps.EXPECT().
+		GetAll(context.Background(), suite.testJobID.GetValue(), gomock.Any()).
+		Return([]*ormobjects.JobDisruption{
+			{
+				// removed from the job
+				InstanceID: 5,
+				Cause:      "TERMINATION_STATUS_REASON_PREEMPTED_RESOURCES",
+				Time:       now.Add(-10 * time.Minute),
+			},
+			{
+				// killed before running again
+				InstanceID: 4,
+				Cause:      "TERMINATION_STATUS_REASON_KILLED_HOST_MAINTENANCE",
+				Time:       now.Add(-25 * time.Minute),
+			},
+			{
+				// not running again yet
+				InstanceID: 3,
+				Cause:      "TERMINATION_STATUS_REASON_KILLED_HOST_MAINTENANCE",
+				Time:       now.Add(-30 * time.Minute),
+			},
+			{
+				InstanceID: 0,
+				Cause:      "TERMINATION_STATUS_REASON_KILLED_HOST_MAINTENANCE",
+				Time:       now.Add(-time.Hour),
+				Duration:   time.Minute,
+				Resolved:   true,
+			},
+			{
+				InstanceID: 1,
+				Cause:      "TERMINATION_STATUS_REASON_PREEMPTED_RESOURCES",
+				Time:       now.Add(-2 * time.Hour),
+				Duration:   30 * time.Second,
+				Resolved:   true,
+			},
+			{
+				InstanceID: 2,
+				Cause:      "TERMINATION_STATUS_REASON_KILLED_HOST_MAINTENANCE",
+				Time:       now.Add(-3 * time.Hour),
+				Duration:   2 * time.Minute,
+				Resolved:   true,
+			},
+		}, nil)
+	suite.mockedJobStore.EXPECT().
+		GetJobRuntime(context.Background(), suite.testJobID.GetValue()).
+		Return(&job.RuntimeInfo{
+			State:     job.JobState_RUNNING,
+			GoalState: job.JobState_SUCCEEDED,
+		}, nil)
+	suite.mockedTaskStore.EXPECT().
+		GetTaskRuntime(context.Background(), suite.testJobID, uint32(5)).
+		Return(nil, yarpcerrors.NotFoundErrorf("task not found"))
+	suite.mockedTaskStore.EXPECT().
+		GetTaskRuntime(context.Background(), suite.testJobID, uint32(4)).
+		Return(&task.RuntimeInfo{
+			State:          task.TaskState_KILLED,
+			GoalState:      task.TaskState_KILLED,
+			CompletionTime: now.Add(-20 * time.Minute).Format(time.RFC3339Nano),
+		}, nil)
+	suite.mockedTaskStore.EXPECT().
+		GetTaskRuntime(context.Background(), suite.testJobID, uint32(3)).
+		Return(&task.RuntimeInfo{
+			State:     task.TaskState_PENDING,
+			GoalState: task.TaskState_SUCCEEDED,
+		}, nil)
+
+	resp, err := suite.handler.GetDisruptions(
+		context.Background(),
+		&job.GetDisruptionsRequest{Id: suite.testJobID, Days: 1})
+	suite.NoError(err)
+	suite.Equal(uint32(testInstanceCount), resp.GetInstanceCount())
+	suite.Equal(uint32(1), resp.GetDays())
+	suite.Len(resp.GetDisruptions(), 2)
+	suite.Equal(
+		task.TerminationStatus_TERMINATION_STATUS_REASON_PREEMPTED_RESOURCES,
+		resp.GetDisruptions()[0].GetCause())
+	suite.Equal(uint32(2), resp.GetDisruptions()[0].GetCount())
+	suite.Equal(uint32(0), resp.GetDisruptions()[0].GetPendingCount())
+	suite.Equal(float64(30), resp.GetDisruptions()[0].GetDurationSeconds())
+	suite.Equal(
+		task.TerminationStatus_TERMINATION_STATUS_REASON_KILLED_HOST_MAINTENANCE,
+		resp.GetDisruptions()[1].GetCause())
+	suite.Equal(uint32(4), resp.GetDisruptions()[1].GetCount())
+	suite.Equal(uint32(1), resp.GetDisruptions()[1].GetPendingCount())
+	suite.InDelta(float64(2280), resp.GetDisruptions()[1].GetDurationSeconds(), 1)
+}
+
+// TestGetDisruptionsTerminalJob tests that the disruptions of a terminal
+// job whose instances did not run again last until the job completed
+func (suite *JobHandlerTestSuite) TestGetDisruptionsTerminalJob() {
+	now := time.Now()
+	suite.mockedJobIndexOps.EXPECT().
+		Get(context.Background(), suite.testJobID).
+		Return(&ormobjects.JobIndexObject{InstanceCount: testInstanceCount}, nil)
+	suite.mockedDisruptionOps.EXPECT().
+		GetAll(context.Background(), suite.testJobID.GetValue(), gomock.Any()).
+		Return([]*ormobjects.JobDisruption{
+			{
+				InstanceID: 1,
+				Cause:      "TERMINATION_STATUS_REASON_PREEMPTED_RESOURCES",
+				Time:       now.Add(-time.Hour),
+			},
+			{
+				InstanceID: 0,
+				Cause:      "TERMINATION_STATUS_REASON_PREEMPTED_RESOURCES",
+				Time:       now.Add(-2 * time.Hour),
+			},
+		}, nil)
+	suite.mockedJobStore.EXPECT().
+		GetJobRuntime(context.Background(), suite.testJobID.GetValue()).
+		Return(&job.RuntimeInfo{
+			State:          job.JobState_KILLED,
+			GoalState:      job.JobState_KILLED,
+			CompletionTime: now.Add(-30 * time.Minute).Format(time.RFC3339Nano),
+		}, nil)
+
+	resp, err := suite.handler.GetDisruptions(
+		context.Background(),
+		&job.GetDisruptionsRequest{Id: suite.testJobID, Days: 1})
+	suite.NoError(err)
+	suite.Len(resp.GetDisruptions(), 1)
+	suite.Equal(uint32(2), resp.GetDisruptions()[0].GetCount())
+	suite.Equal(uint32(0), resp.GetDisruptions()[0].GetPendingCount())
+	suite.InDelta(float64(7200), resp.GetDisruptions()[0].GetDurationSeconds(), 1)
+}
+
+// TestGetDisruptionsTooManyDays tests getting the disruptions of a job
+// for more days than the disruptions are kept
+func (suite *JobHandlerTestSuite) TestGetDisruptionsTooManyDays() {
+	_, err := suite.handler.GetDisruptions(
+		context.Background(),
+		&job.GetDisruptionsRequest{Id: suite.testJobID, Days: 91})
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestGetDisruptionsDefaultDays tests getting the disruptions of a job
+// without specifying the number of days
+func (suite *JobHandlerTestSuite) TestGetDisruptionsDefaultDays() {
+	suite.mockedJobIndexOps.EXPECT().
+		Get(context.Background(), suite.testJobID).
+		Return(&ormobjects.JobIndexObject{InstanceCount: testInstanceCount}, nil)
+	suite.mockedDisruptionOps.EXPECT().
+		GetAll(context.Background(), suite.testJobID.GetValue(), gomock.Any()).
+		Return(nil, nil)
+
+	resp, err := suite.handler.GetDisruptions(
+		context.Background(),
+		&job.GetDisruptionsRequest{Id: suite.testJobID})
+	suite.NoError(err)
+	suite.Equal(uint32(_defaultDisruptionDays), resp.GetDays())
+	suite.Empty(resp.GetDisruptions())
+}
+
+// TestGetDisruptionsFailure tests failures to get the disruptions of a job
+func (suite *JobHandlerTestSuite) TestGetDisruptionsFailure() {
+	req := &job.GetDisruptionsRequest{Id: suite.testJobID}
+
+	// job not found
+	suite.mockedJobIndexOps.EXPECT().
+		Get(context.Background(), suite.testJobID).
+		Return(nil, yarpcerrors.NotFoundErrorf("not found"))
+	_, err := suite.handler.GetDisruptions(context.Background(), req)
+	suite.True(yarpcerrors.IsNotFound(err))
+
+	// failure to read the disruptions
+	suite.mockedJobIndexOps.EXPECT().
+		Get(context.Background(), suite.testJobID).
+		Return(&ormobjects.JobIndexObject{InstanceCount: testInstanceCount}, nil)
+	suite.mockedDisruptionOps.EXPECT().
+		GetAll(context.Background(), suite.testJobID.GetValue(), gomock.Any()).
+		Return(nil, fmt.Errorf("get disruptions err"))
+	_, err = suite.handler.GetDisruptions(context.Background(), req)
+	suite.Error(err)
+
+	// failure to read the runtime of a disrupted instance
+	suite.mockedJobIndexOps.EXPECT().
+		Get(context.Background(), suite.testJobID).
+		Return(&ormobjects.JobIndexObject{InstanceCount: testInstanceCount}, nil)
+	suite.mockedDisruptionOps.EXPECT().
+		GetAll(context.Background(), suite.testJobID.GetValue(), gomock.Any()).
+		Return([]*ormobjects.JobDisruption{{
+			InstanceID: 0,
+			Cause:      "TERMINATION_STATUS_REASON_PREEMPTED_RESOURCES",
+			Time:       time.Now(),
+		}}, nil)
+	suite.mockedJobStore.EXPECT().
+		GetJobRuntime(context.Background(), suite.testJobID.GetValue()).
+		Return(&job.RuntimeInfo{State: job.JobState_RUNNING}, nil)
+	suite.mockedTaskStore.EXPECT().
+		GetTaskRuntime(context.Background(), suite.testJobID, uint32(0)).
+		Return(nil, fmt.Errorf("get task runtime err"))
+	_, err = suite.handler.GetDisruptions(context.Background(), req)
+	suite.Error(err)
+}
+
+// setupScaleMocks sets up the mocks to scale a batch job
+// with tasks in the given states
+func (suite *JobHandlerTestSuite) setupScaleMocks(
+	states []task.TaskState,
+) map[uint32]cached.Task {
+	suite.testJobConfig.Type = job.JobType_BATCH
+	suite.testJobConfig.InstanceCount = uint32(len(states))
+	suite.testJobConfig.ChangeLog = &peloton.ChangeLog{Version: 1}
+
+	suite.mockedCandidate.EXPECT().IsLeader().Return(true)
+	suite.mockedJobFactory.EXPECT().
+		AddJob(suite.testJobID).
+		Return(suite.mockedCachedJob)
+	suite.mockedCachedJob.EXPECT().GetRuntime(gomock.Any()).
+		Return(&job.RuntimeInfo{
+			State:                job.JobState_RUNNING,
+			GoalState:            job.JobState_SUCCEEDED,
+			ConfigurationVersion: 1,
+		}, nil)
+	suite.mockedJobConfigOps.EXPECT().
+		Get(gomock.Any(), suite.testJobID, uint64(1)).
+		Return(suite.testJobConfig, &models.ConfigAddOn{}, nil)
+
+	tasks := make(map[uint32]cached.Task)
+	for i, state := range states {
+		cachedTask := cachedmocks.NewMockTask(suite.ctrl)
+		cachedTask.EXPECT().GoalState().
+			Return(cached.TaskStateVector{State: task.TaskState_SUCCEEDED}).
+			AnyTimes()
+		cachedTask.EXPECT().GetRuntime(gomock.Any()).
+			Return(&task.RuntimeInfo{
+				State:     state,
+				GoalState: task.TaskState_SUCCEEDED,
+			}, nil).
+			AnyTimes()
+		tasks[uint32(i)] = cachedTask
+	}
+	suite.mockedCachedJob.EXPECT().GetAllTasks().Return(tasks)
+	return tasks
+}
+
+// TestScaleUp tests adding instances to a running batch job
+func (suite *JobHandlerTestSuite) TestScaleUp() {
+	suite.setupScaleMocks([]task.TaskState{
+		task.TaskState_RUNNING,
+		task.TaskState_SUCCEEDED,
+	})
+
+	gomock.InOrder(
+		suite.mockedCachedJob.EXPECT().
+			CompareAndSetConfig(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context,
+				config *job.JobConfig,
+				_ *models.ConfigAddOn) {
+				suite.Equal(uint32(4), config.GetInstanceCount())
+			}).
+			Return(&job.JobConfig{
+				ChangeLog: &peloton.ChangeLog{Version: 2},
+			}, nil),
+		suite.mockedCachedJob.EXPECT().
+			Update(gomock.Any(), gomock.Any(), nil, cached.UpdateCacheAndDB).
+			Do(func(_ context.Context,
+				jobInfo *job.JobInfo,
+				_ *models.ConfigAddOn,
+				_ cached.UpdateRequest) {
+				suite.Equal(uint64(2),
+					jobInfo.GetRuntime().GetConfigurationVersion())
+				suite.Equal(job.JobState_INITIALIZED,
+					jobInfo.GetRuntime().GetState())
+			}).
+			Return(nil),
+		suite.mockedGoalStateDriver.EXPECT().
+			EnqueueJob(suite.testJobID, gomock.Any()),
+	)
+
+	resp, err := suite.handler.Scale(
+		context.Background(),
+		&job.ScaleRequest{Id: suite.testJobID, InstanceCount: 4})
+	suite.NoError(err)
+	suite.Equal([]uint32{2, 3}, resp.GetInstancesAdded())
+	suite.Empty(resp.GetInstancesRemoved())
+}
+
+// TestScaleDown tests that scaling down a running batch job removes
+// the instances not launched yet first and never the completed ones
+func (suite *JobHandlerTestSuite) TestScaleDown() {
+	suite.setupScaleMocks([]task.TaskState{
+		task.TaskState_RUNNING,
+		task.TaskState_PENDING,
+		task.TaskState_SUCCEEDED,
+		task.TaskState_LAUNCHED,
+		task.TaskState_RUNNING,
+		task.TaskState_INITIALIZED,
+	})
+
+	gomock.InOrder(
+		suite.mockedCachedJob.EXPECT().
+			CompareAndSetConfig(gomock.Any(), gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context,
+				config *job.JobConfig,
+				_ *models.ConfigAddOn) {
+				suite.Equal(uint32(2), config.GetInstanceCount())
+			}).
+			Return(&job.JobConfig{
+				ChangeLog: &peloton.ChangeLog{Version: 2},
+			}, nil),
+		suite.mockedCachedJob.EXPECT().
+			Update(gomock.Any(), gomock.Any(), nil, cached.UpdateCacheAndDB).
+			Do(func(_ context.Context,
+				jobInfo *job.JobInfo,
+				_ *models.ConfigAddOn,
+				_ cached.UpdateRequest) {
+				suite.Equal(uint64(2),
+					jobInfo.GetRuntime().GetConfigurationVersion())
+				suite.Equal(job.JobState_UNKNOWN,
+					jobInfo.GetRuntime().GetState())
+			}).
+			Return(nil),
+		suite.mockedCachedJob.EXPECT().
+			PatchTasks(gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context,
+				runtimeDiffs map[uint32]jobmgrcommon.RuntimeDiff) {
+				suite.Len(runtimeDiffs, 4)
+				for _, diff := range runtimeDiffs {
+					suite.Equal(task.TaskState_DELETED,
+						diff[jobmgrcommon.GoalStateField])
+				}
+			}).
+			Return(nil),
+	)
+	suite.mockedGoalStateDriver.EXPECT().
+		EnqueueTask(suite.testJobID, gomock.Any(), gomock.Any()).
+		Times(4)
+	suite.mockedGoalStateDriver.EXPECT().
+		EnqueueJob(suite.testJobID, gomock.Any())
+
+	resp, err := suite.handler.Scale(
+		context.Background(),
+		&job.ScaleRequest{Id: suite.testJobID, InstanceCount: 2})
+	suite.NoError(err)
+	suite.Empty(resp.GetInstancesAdded())
+	suite.Equal([]uint32{1, 3, 4, 5}, resp.GetInstancesRemoved())
+}
+
+// TestScalePartiallyCreatedJob tests scaling a batch job whose
+// instances are not all created yet
+func (suite *JobHandlerTestSuite) TestScalePartiallyCreatedJob() {
+	suite.testJobConfig.SLA.MaximumRunningInstances = 0
+	states := []task.TaskState{
+		task.TaskState_RUNNING,
+		task.TaskState_PENDING,
+	}
+
+	// scale up adds instances after the ones not created yet
+	suite.setupScaleMocks(states)
+	suite.testJobConfig.InstanceCount = 5
+	suite.mockedCachedJob.EXPECT().
+		CompareAndSetConfig(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&job.JobConfig{
+			ChangeLog: &peloton.ChangeLog{Version: 2},
+		}, nil)
+	suite.mockedCachedJob.EXPECT().
+		Update(gomock.Any(), gomock.Any(), nil, cached.UpdateCacheAndDB).
+		Return(nil)
+	suite.mockedGoalStateDriver.EXPECT().
+		EnqueueJob(suite.testJobID, gomock.Any())
+
+	resp, err := suite.handler.Scale(
+		context.Background(),
+		&job.ScaleRequest{Id: suite.testJobID, InstanceCount: 7})
+	suite.NoError(err)
+	suite.Equal([]uint32{5, 6}, resp.GetInstancesAdded())
+	suite.Empty(resp.GetInstancesRemoved())
+
+	// scale down removes only instances not created yet
+	suite.setupScaleMocks(states)
+	suite.testJobConfig.InstanceCount = 5
+	suite.mockedCachedJob.EXPECT().
+		CompareAndSetConfig(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context,
+			config *job.JobConfig,
+			_ *models.ConfigAddOn) {
+			suite.Equal(uint32(3), config.GetInstanceCount())
+		}).
+		Return(&job.JobConfig{
+			ChangeLog: &peloton.ChangeLog{Version: 2},
+		}, nil)
+	suite.mockedCachedJob.EXPECT().
+		Update(gomock.Any(), gomock.Any(), nil, cached.UpdateCacheAndDB).
+		Return(nil)
+	suite.mockedGoalStateDriver.EXPECT().
+		EnqueueJob(suite.testJobID, gomock.Any())
+
+	resp, err = suite.handler.Scale(
+		context.Background(),
+		&job.ScaleRequest{Id: suite.testJobID, InstanceCount: 3})
+	suite.NoError(err)
+	suite.Empty(resp.GetInstancesAdded())
+	suite.Equal([]uint32{3, 4}, resp.GetInstancesRemoved())
+
+	// scale down removes all the instances not created yet
+	// and then the created ones
+	suite.setupScaleMocks(states)
+	suite.testJobConfig.InstanceCount = 5
+	suite.mockedCachedJob.EXPECT().
+		CompareAndSetConfig(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&job.JobConfig{
+			ChangeLog: &peloton.ChangeLog{Version: 2},
+		}, nil)
+	suite.mockedCachedJob.EXPECT().
+		Update(gomock.Any(), gomock.Any(), nil, cached.UpdateCacheAndDB).
+		Return(nil)
+	suite.mockedCachedJob.EXPECT().
+		PatchTasks(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context,
+			runtimeDiffs map[uint32]jobmgrcommon.RuntimeDiff) {
+			suite.Len(runtimeDiffs, 1)
+			suite.Equal(task.TaskState_DELETED,
+				runtimeDiffs[1][jobmgrcommon.GoalStateField])
+		}).
+		Return(nil)
+	suite.mockedGoalStateDriver.EXPECT().
+		EnqueueTask(suite.testJobID, uint32(1), gomock.Any())
+	suite.mockedGoalStateDriver.EXPECT().
+		EnqueueJob(suite.testJobID, gomock.Any())
+
+	resp, err = suite.handler.Scale(
+		context.Background(),
+		&job.ScaleRequest{Id: suite.testJobID, InstanceCount: 1})
+	suite.NoError(err)
+	suite.Empty(resp.GetInstancesAdded())
+	suite.Equal([]uint32{1, 2, 3, 4}, resp.GetInstancesRemoved())
+}
+
+// TestScaleDownTooManyInstances tests that a batch job cannot be
+// scaled down below its completed instances
+func (suite *JobHandlerTestSuite) TestScaleDownTooManyInstances() {
+	suite.testJobConfig.SLA.MaximumRunningInstances = 0
+	suite.setupScaleMocks([]task.TaskState{
+		task.TaskState_SUCCEEDED,
+		task.TaskState_FAILED,
+		task.TaskState_RUNNING,
+	})
+
+	_, err := suite.handler.Scale(
+		context.Background(),
+		&job.ScaleRequest{Id: suite.testJobID, InstanceCount: 1})
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestScaleFailures tests the failures to scale a job
+func (suite *JobHandlerTestSuite) TestScaleFailures() {
+	req := &job.ScaleRequest{Id: suite.testJobID, InstanceCount: 4}
+
+	// not leader
+	suite.mockedCandidate.EXPECT().IsLeader().Return(false)
+	_, err := suite.handler.Scale(context.Background(), req)
+	suite.True(yarpcerrors.IsUnavailable(err))
+
+	suite.mockedCandidate.EXPECT().IsLeader().Return(true).AnyTimes()
+	suite.mockedJobFactory.EXPECT().
+		AddJob(suite.testJobID).
+		Return(suite.mockedCachedJob).
+		AnyTimes()
+
+	// failure to get the job runtime
+	suite.mockedCachedJob.EXPECT().GetRuntime(gomock.Any()).
+		Return(nil, fmt.Errorf("get runtime err"))
+	_, err = suite.handler.Scale(context.Background(), req)
+	suite.Error(err)
+
+	// job being killed
+	suite.mockedCachedJob.EXPECT().GetRuntime(gomock.Any()).
+		Return(&job.RuntimeInfo{
+			State:     job.JobState_RUNNING,
+			GoalState: job.JobState_KILLED,
+		}, nil)
+	_, err = suite.handler.Scale(context.Background(), req)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+
+	// service job
+	suite.mockedCachedJob.EXPECT().GetRuntime(gomock.Any()).
+		Return(&job.RuntimeInfo{
+			State:     job.JobState_RUNNING,
+			GoalState: job.JobState_RUNNING,
+		}, nil)
+	suite.mockedJobConfigOps.EXPECT().
+		Get(gomock.Any(), suite.testJobID, gomock.Any()).
+		Return(suite.testJobConfig, &models.ConfigAddOn{}, nil)
+	_, err = suite.handler.Scale(context.Background(), req)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+
+	// instance count less than the maximum running instances
+	suite.testJobConfig.Type = job.JobType_BATCH
+	suite.mockedCachedJob.EXPECT().GetRuntime(gomock.Any()).
+		Return(&job.RuntimeInfo{
+			State:     job.JobState_RUNNING,
+			GoalState: job.JobState_SUCCEEDED,
+		}, nil)
+	suite.mockedJobConfigOps.EXPECT().
+		Get(gomock.Any(), suite.testJobID, gomock.Any()).
+		Return(suite.testJobConfig, &models.ConfigAddOn{}, nil)
+	_, err = suite.handler.Scale(
+		context.Background(),
+		&job.ScaleRequest{Id: suite.testJobID, InstanceCount: 1})
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+
+	// previous scale down in progress
+	suite.mockedCachedJob.EXPECT().GetRuntime(gomock.Any()).
+		Return(&job.RuntimeInfo{
+			State:     job.JobState_RUNNING,
+			GoalState: job.JobState_SUCCEEDED,
+		}, nil)
+	suite.mockedJobConfigOps.EXPECT().
+		Get(gomock.Any(), suite.testJobID, gomock.Any()).
+		Return(suite.testJobConfig, &models.ConfigAddOn{}, nil)
+	cachedTask := cachedmocks.NewMockTask(suite.ctrl)
+	cachedTask.EXPECT().GoalState().
+		Return(cached.TaskStateVector{State: task.TaskState_DELETED})
+	suite.mockedCachedJob.EXPECT().GetAllTasks().
+		Return(map[uint32]cached.Task{0: cachedTask})
+	_, err = suite.handler.Scale(context.Background(), req)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
 // TestRestartJobSuccess tests the success path of restarting job
 func (suite *JobHandlerTestSuite) TestRestartJobSuccess() {
 	var configurationVersion uint64 = 1
@@ -53,6 +53,14 @@ type Metrics struct {
 	JobGetByRespoolID     tally.Counter
 	JobGetByRespoolIDFail tally.Counter
 
+	JobAPIGetDisruptions  tally.Counter
+	JobGetDisruptions     tally.Counter
+	JobGetDisruptionsFail tally.Counter
+
+	JobAPIScale  tally.Counter
+	JobScale     tally.Counter
+	JobScaleFail tally.Counter
+
 	// Timers
 	JobQueryHandlerDuration tally.Timer
 
@@ -110,5 +118,13 @@ func NewMetrics(scope tally.Scope) *Metrics {
 		JobAPIGetByRespoolID:  jobAPIScope.Counter("get_by_respool_id"),
 		JobGetByRespoolID:     jobSuccessScope.Counter("get_by_respool_id"),
 		JobGetByRespoolIDFail: jobFailScope.Counter("get_by_respool_id"),
+
+		JobAPIGetDisruptions:  jobAPIScope.Counter("get_disruptions"),
+		JobGetDisruptions:     jobSuccessScope.Counter("get_disruptions"),
+		JobGetDisruptionsFail: jobFailScope.Counter("get_disruptions"),
+
+		JobAPIScale:  jobAPIScope.Counter("scale"),
+		JobScale:     jobSuccessScope.Counter("scale"),
+		JobScaleFail: jobFailScope.Counter("scale"),
 	}
 }
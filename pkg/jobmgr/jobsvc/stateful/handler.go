@@ -0,0 +1,414 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stateful
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/volume"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateful"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateful/svc"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless"
+	statelesssvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless/svc"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+
+	"github.com/uber/peloton/pkg/common"
+	yarpcutil "github.com/uber/peloton/pkg/common/util/yarpc"
+	"github.com/uber/peloton/pkg/storage"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+var (
+	errNilJobSpec = yarpcerrors.InvalidArgumentErrorf(
+		"job spec is not set")
+	errNoPersistentVolume = yarpcerrors.InvalidArgumentErrorf(
+		"default pod spec of a stateful job must have a persistent volume")
+	errInvalidPersistentVolume = yarpcerrors.InvalidArgumentErrorf(
+		"persistent volume must have a container path and a size")
+	errPersistentVolumeOverride = yarpcerrors.InvalidArgumentErrorf(
+		"persistent volume cannot be overridden by an instance spec")
+	errPersistentVolumeChanged = yarpcerrors.InvalidArgumentErrorf(
+		"persistent volume of a stateful job cannot be changed")
+	errNotStatefulJob = yarpcerrors.InvalidArgumentErrorf(
+		"job is not a stateful job")
+)
+
+var volumeRetentionPolicyLabelKey = fmt.Sprintf(
+	common.SystemLabelKeyTemplate,
+	common.SystemLabelPrefix,
+	common.SystemLabelVolumeRetentionPolicy)
+
+// serviceHandler implements peloton.api.v1alpha.job.stateful.svc.JobService.
+// A stateful job is a stateless job whose pods have a persistent volume, so
+// the job lifecycle and workflows are handled by the stateless job service.
+type serviceHandler struct {
+	jobSvc      statelesssvc.JobServiceYARPCServer
+	taskStore   storage.TaskStore
+	volumeStore storage.PersistentVolumeStore
+}
+
+// InitV1AlphaJobServiceHandler initializes the Stateful Job Service Handler.
+// Jobs are created, replaced and deleted through the stateless job service
+// handler jobSvc.
+func InitV1AlphaJobServiceHandler(
+	d *yarpc.Dispatcher,
+	taskStore storage.TaskStore,
+	volumeStore storage.PersistentVolumeStore,
+	jobSvc statelesssvc.JobServiceYARPCServer,
+) {
+	handler := &serviceHandler{
+		jobSvc:      jobSvc,
+		taskStore:   taskStore,
+		volumeStore: volumeStore,
+	}
+	d.Register(svc.BuildJobServiceYARPCProcedures(handler))
+}
+
+// CreateJob implements JobService.CreateJob.
+func (h *serviceHandler) CreateJob(
+	ctx context.Context,
+	req *svc.CreateJobRequest,
+) (resp *svc.CreateJobResponse, err error) {
+	defer func() {
+		if err != nil {
+			log.WithField("job_id", req.GetJobId().GetValue()).
+				WithError(err).
+				Warn("StatefulJobSVC.CreateJob failed")
+			err = yarpcutil.ConvertToYARPCError(err)
+			return
+		}
+		log.WithField("job_id", resp.GetJobId().GetValue()).
+			Info("StatefulJobSVC.CreateJob succeeded")
+	}()
+
+	if err := validateJobSpec(req.GetSpec()); err != nil {
+		return nil, err
+	}
+
+	createResp, err := h.jobSvc.CreateJob(ctx, &statelesssvc.CreateJobRequest{
+		JobId:      req.GetJobId(),
+		Spec:       toStatelessJobSpec(req.GetSpec()),
+		CreateSpec: req.GetCreateSpec(),
+		OpaqueData: req.GetOpaqueData(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &svc.CreateJobResponse{
+		JobId:   createResp.GetJobId(),
+		Version: createResp.GetVersion(),
+	}, nil
+}
+
+// ReplaceJob implements JobService.ReplaceJob.
+func (h *serviceHandler) ReplaceJob(
+	ctx context.Context,
+	req *svc.ReplaceJobRequest,
+) (resp *svc.ReplaceJobResponse, err error) {
+	defer func() {
+		if err != nil {
+			log.WithField("job_id", req.GetJobId().GetValue()).
+				WithField("entity_version", req.GetVersion().GetValue()).
+				WithError(err).
+				Warn("StatefulJobSVC.ReplaceJob failed")
+			err = yarpcutil.ConvertToYARPCError(err)
+			return
+		}
+		log.WithField("job_id", req.GetJobId().GetValue()).
+			WithField("entity_version", req.GetVersion().GetValue()).
+			Info("StatefulJobSVC.ReplaceJob succeeded")
+	}()
+
+	if err := validateJobSpec(req.GetSpec()); err != nil {
+		return nil, err
+	}
+
+	prevSpec, err := h.getJobSpec(ctx, req.GetJobId())
+	if err != nil {
+		return nil, err
+	}
+
+	// the persistent volume follows the pod across updates
+	if !proto.Equal(
+		prevSpec.GetSpec().GetDefaultSpec().GetVolume(),
+		req.GetSpec().GetSpec().GetDefaultSpec().GetVolume()) {
+		return nil, errPersistentVolumeChanged
+	}
+
+	replaceResp, err := h.jobSvc.ReplaceJob(ctx, &statelesssvc.ReplaceJobRequest{
+		JobId:      req.GetJobId(),
+		Version:    req.GetVersion(),
+		Spec:       toStatelessJobSpec(req.GetSpec()),
+		UpdateSpec: toOrderedUpdateSpec(req.GetUpdateSpec()),
+		OpaqueData: req.GetOpaqueData(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &svc.ReplaceJobResponse{Version: replaceResp.GetVersion()}, nil
+}
+
+// DeleteJob implements JobService.DeleteJob.
+func (h *serviceHandler) DeleteJob(
+	ctx context.Context,
+	req *svc.DeleteJobRequest,
+) (resp *svc.DeleteJobResponse, err error) {
+	defer func() {
+		if err != nil {
+			log.WithField("request", req).
+				WithError(err).
+				Warn("StatefulJobSVC.DeleteJob failed")
+			err = yarpcutil.ConvertToYARPCError(err)
+			return
+		}
+		log.WithField("request", req).
+			Info("StatefulJobSVC.DeleteJob succeeded")
+	}()
+
+	spec, err := h.getJobSpec(ctx, req.GetJobId())
+	if err != nil {
+		return nil, err
+	}
+
+	// the volumes are looked up before deleting the job,
+	// since its pods are removed along with it.
+	var volumeIDs []*peloton.VolumeID
+	if spec.GetVolumeRetentionPolicy() ==
+		stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_DELETE {
+		if volumeIDs, err = h.getVolumeIDs(ctx, req.GetJobId()); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := h.jobSvc.DeleteJob(ctx, &statelesssvc.DeleteJobRequest{
+		JobId:   req.GetJobId(),
+		Version: req.GetVersion(),
+		Force:   req.GetForce(),
+	}); err != nil {
+		return nil, err
+	}
+
+	// the volumes are destroyed by the host manager once their pods
+	// have been killed and their resources are offered again.
+	for _, volumeID := range volumeIDs {
+		if err := h.deleteVolume(ctx, volumeID); err != nil {
+			return nil, errors.Wrapf(err,
+				"job is deleted, but failed to delete volume %s",
+				volumeID.GetValue())
+		}
+	}
+
+	return &svc.DeleteJobResponse{}, nil
+}
+
+// GetJob implements JobService.GetJob.
+func (h *serviceHandler) GetJob(
+	ctx context.Context,
+	req *svc.GetJobRequest,
+) (resp *svc.GetJobResponse, err error) {
+	defer func() {
+		if err != nil {
+			log.WithField("request", req).
+				WithError(err).
+				Warn("StatefulJobSVC.GetJob failed")
+			err = yarpcutil.ConvertToYARPCError(err)
+			return
+		}
+		log.WithField("request", req).
+			Debug("StatefulJobSVC.GetJob succeeded")
+	}()
+
+	getResp, err := h.jobSvc.GetJob(ctx, &statelesssvc.GetJobRequest{
+		JobId:   req.GetJobId(),
+		Version: req.GetVersion(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := toStatefulJobSpec(getResp.GetJobInfo().GetSpec())
+	if err != nil {
+		return nil, err
+	}
+
+	return &svc.GetJobResponse{
+		JobInfo: &stateful.JobInfo{
+			JobId:  getResp.GetJobInfo().GetJobId(),
+			Spec:   spec,
+			Status: getResp.GetJobInfo().GetStatus(),
+		},
+		WorkflowInfo: getResp.GetWorkflowInfo(),
+	}, nil
+}
+
+// getJobSpec returns the current spec of a stateful job.
+func (h *serviceHandler) getJobSpec(
+	ctx context.Context,
+	jobID *v1alphapeloton.JobID,
+) (*stateful.JobSpec, error) {
+	getResp, err := h.jobSvc.GetJob(ctx, &statelesssvc.GetJobRequest{
+		JobId: jobID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toStatefulJobSpec(getResp.GetJobInfo().GetSpec())
+}
+
+// getVolumeIDs returns the ids of the persistent volumes of the pods of a job.
+func (h *serviceHandler) getVolumeIDs(
+	ctx context.Context,
+	jobID *v1alphapeloton.JobID,
+) ([]*peloton.VolumeID, error) {
+	taskInfos, err := h.taskStore.GetTasksForJob(
+		ctx,
+		&peloton.JobID{Value: jobID.GetValue()},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pods of job")
+	}
+
+	var volumeIDs []*peloton.VolumeID
+	for _, taskInfo := range taskInfos {
+		if len(taskInfo.GetRuntime().GetVolumeID().GetValue()) == 0 {
+			continue
+		}
+		volumeIDs = append(volumeIDs, taskInfo.GetRuntime().GetVolumeID())
+	}
+	return volumeIDs, nil
+}
+
+// deleteVolume sets the goal state of a persistent volume to deleted.
+func (h *serviceHandler) deleteVolume(
+	ctx context.Context,
+	volumeID *peloton.VolumeID,
+) error {
+	volumeInfo, err := h.volumeStore.GetPersistentVolume(ctx, volumeID)
+	if err != nil {
+		if _, ok := err.(*storage.VolumeNotFoundError); ok {
+			return nil
+		}
+		return err
+	}
+
+	if volumeInfo.GetGoalState() == volume.VolumeState_DELETED {
+		return nil
+	}
+	volumeInfo.GoalState = volume.VolumeState_DELETED
+	return h.volumeStore.UpdatePersistentVolume(ctx, volumeInfo)
+}
+
+// validateJobSpec validates that a stateful job spec has a valid persistent
+// volume, which is shared by all of the pods.
+func validateJobSpec(spec *stateful.JobSpec) error {
+	if spec.GetSpec() == nil {
+		return errNilJobSpec
+	}
+
+	vol := spec.GetSpec().GetDefaultSpec().GetVolume()
+	if vol == nil {
+		return errNoPersistentVolume
+	}
+	if len(vol.GetContainerPath()) == 0 || vol.GetSizeMb() == 0 {
+		return errInvalidPersistentVolume
+	}
+
+	for _, podSpec := range spec.GetSpec().GetInstanceSpec() {
+		if podSpec.GetVolume() != nil && !proto.Equal(podSpec.GetVolume(), vol) {
+			return errPersistentVolumeOverride
+		}
+	}
+	return nil
+}
+
+// toStatelessJobSpec converts a stateful job spec to the stateless job spec
+// it is run as, recording the volume retention policy in the job labels.
+func toStatelessJobSpec(spec *stateful.JobSpec) *stateless.JobSpec {
+	policy := spec.GetVolumeRetentionPolicy()
+	if policy == stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_INVALID {
+		policy = stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_RETAIN
+	}
+
+	result := proto.Clone(spec.GetSpec()).(*stateless.JobSpec)
+	result.Labels = append(
+		removeVolumeRetentionPolicyLabel(result.GetLabels()),
+		&v1alphapeloton.Label{
+			Key:   volumeRetentionPolicyLabelKey,
+			Value: policy.String(),
+		},
+	)
+	return result
+}
+
+// toStatefulJobSpec converts the stateless job spec a stateful job is run as
+// back to the stateful job spec.
+func toStatefulJobSpec(spec *stateless.JobSpec) (*stateful.JobSpec, error) {
+	if spec.GetDefaultSpec().GetVolume() == nil {
+		return nil, errNotStatefulJob
+	}
+
+	policy := stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_RETAIN
+	for _, label := range spec.GetLabels() {
+		if label.GetKey() == volumeRetentionPolicyLabelKey {
+			policy = stateful.VolumeRetentionPolicy(
+				stateful.VolumeRetentionPolicy_value[label.GetValue()])
+		}
+	}
+
+	result := proto.Clone(spec).(*stateless.JobSpec)
+	result.Labels = removeVolumeRetentionPolicyLabel(result.GetLabels())
+	return &stateful.JobSpec{
+		Spec:                  result,
+		VolumeRetentionPolicy: policy,
+	}, nil
+}
+
+// removeVolumeRetentionPolicyLabel returns the labels without the
+// volume retention policy label.
+func removeVolumeRetentionPolicyLabel(
+	labels []*v1alphapeloton.Label,
+) []*v1alphapeloton.Label {
+	var result []*v1alphapeloton.Label
+	for _, label := range labels {
+		if label.GetKey() != volumeRetentionPolicyLabelKey {
+			result = append(result, label)
+		}
+	}
+	return result
+}
+
+// toOrderedUpdateSpec returns the update spec used to roll out an update to
+// a stateful job. The pods are updated in place, one pod at a time unless a
+// batch size is provided.
+func toOrderedUpdateSpec(spec *stateless.UpdateSpec) *stateless.UpdateSpec {
+	result := &stateless.UpdateSpec{}
+	if spec != nil {
+		result = proto.Clone(spec).(*stateless.UpdateSpec)
+	}
+	if result.GetBatchSize() == 0 {
+		result.BatchSize = 1
+	}
+	result.InPlace = true
+	return result
+}
@@ -0,0 +1,331 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stateful
+
+import (
+	"context"
+	"testing"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/api/v0/volume"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateful"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateful/svc"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless"
+	statelesssvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless/svc"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/pod"
+
+	statelesssvcmocks "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless/svc/mocks"
+	storagemocks "github.com/uber/peloton/pkg/storage/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+const (
+	testJobID    = "481d565e-28da-457d-8434-f6bb7faa0e95"
+	testVolumeID = "4f1f1d31-54ad-4c1c-a4c2-8c7b7a1ab0f5"
+)
+
+type statefulHandlerTestSuite struct {
+	suite.Suite
+
+	ctx         context.Context
+	ctrl        *gomock.Controller
+	jobSvc      *statelesssvcmocks.MockJobServiceYARPCServer
+	taskStore   *storagemocks.MockTaskStore
+	volumeStore *storagemocks.MockPersistentVolumeStore
+	handler     *serviceHandler
+}
+
+func TestStatefulServiceHandler(t *testing.T) {
+	suite.Run(t, new(statefulHandlerTestSuite))
+}
+
+func (suite *statefulHandlerTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.jobSvc = statelesssvcmocks.NewMockJobServiceYARPCServer(suite.ctrl)
+	suite.taskStore = storagemocks.NewMockTaskStore(suite.ctrl)
+	suite.volumeStore = storagemocks.NewMockPersistentVolumeStore(suite.ctrl)
+	suite.handler = &serviceHandler{
+		jobSvc:      suite.jobSvc,
+		taskStore:   suite.taskStore,
+		volumeStore: suite.volumeStore,
+	}
+}
+
+func (suite *statefulHandlerTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+// newTestJobSpec returns a stateful job spec used in the tests
+func newTestJobSpec(
+	policy stateful.VolumeRetentionPolicy,
+) *stateful.JobSpec {
+	return &stateful.JobSpec{
+		Spec: &stateless.JobSpec{
+			Name:          "test",
+			InstanceCount: 3,
+			Labels: []*v1alphapeloton.Label{
+				{Key: "key", Value: "value"},
+			},
+			DefaultSpec: &pod.PodSpec{
+				Volume: &pod.PersistentVolumeSpec{
+					ContainerPath: "/data",
+					SizeMb:        1024,
+				},
+			},
+		},
+		VolumeRetentionPolicy: policy,
+	}
+}
+
+// expectGetJob sets the expectation of getting the job from the
+// stateless job service
+func (suite *statefulHandlerTestSuite) expectGetJob(spec *stateful.JobSpec) {
+	suite.jobSvc.EXPECT().
+		GetJob(gomock.Any(), &statelesssvc.GetJobRequest{
+			JobId: &v1alphapeloton.JobID{Value: testJobID},
+		}).
+		Return(&statelesssvc.GetJobResponse{
+			JobInfo: &stateless.JobInfo{
+				JobId: &v1alphapeloton.JobID{Value: testJobID},
+				Spec:  toStatelessJobSpec(spec),
+			},
+		}, nil)
+}
+
+// TestCreateJob tests creating a stateful job
+func (suite *statefulHandlerTestSuite) TestCreateJob() {
+	spec := newTestJobSpec(
+		stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_DELETE)
+
+	suite.jobSvc.EXPECT().
+		CreateJob(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *statelesssvc.CreateJobRequest) {
+			suite.Equal(testJobID, req.GetJobId().GetValue())
+			suite.Equal(
+				spec.GetSpec().GetDefaultSpec(),
+				req.GetSpec().GetDefaultSpec())
+			suite.Len(req.GetSpec().GetLabels(), 2)
+			suite.Equal(
+				volumeRetentionPolicyLabelKey,
+				req.GetSpec().GetLabels()[1].GetKey())
+			suite.Equal(
+				stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_DELETE.String(),
+				req.GetSpec().GetLabels()[1].GetValue())
+		}).
+		Return(&statelesssvc.CreateJobResponse{
+			JobId:   &v1alphapeloton.JobID{Value: testJobID},
+			Version: &v1alphapeloton.EntityVersion{Value: "1-1-1"},
+		}, nil)
+
+	resp, err := suite.handler.CreateJob(suite.ctx, &svc.CreateJobRequest{
+		JobId: &v1alphapeloton.JobID{Value: testJobID},
+		Spec:  spec,
+	})
+	suite.NoError(err)
+	suite.Equal(testJobID, resp.GetJobId().GetValue())
+	suite.Equal("1-1-1", resp.GetVersion().GetValue())
+
+	// the request spec is not modified
+	suite.Len(spec.GetSpec().GetLabels(), 1)
+}
+
+// TestCreateJobInvalidSpec tests creating a stateful job with
+// an invalid spec
+func (suite *statefulHandlerTestSuite) TestCreateJobInvalidSpec() {
+	noVolume := newTestJobSpec(
+		stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_RETAIN)
+	noVolume.Spec.DefaultSpec.Volume = nil
+
+	noSize := newTestJobSpec(
+		stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_RETAIN)
+	noSize.Spec.DefaultSpec.Volume.SizeMb = 0
+
+	override := newTestJobSpec(
+		stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_RETAIN)
+	override.Spec.InstanceSpec = map[uint32]*pod.PodSpec{
+		1: {
+			Volume: &pod.PersistentVolumeSpec{
+				ContainerPath: "/other",
+				SizeMb:        1024,
+			},
+		},
+	}
+
+	for _, spec := range []*stateful.JobSpec{nil, noVolume, noSize, override} {
+		_, err := suite.handler.CreateJob(suite.ctx, &svc.CreateJobRequest{
+			Spec: spec,
+		})
+		suite.Error(err)
+		suite.True(yarpcerrors.IsInvalidArgument(err))
+	}
+}
+
+// TestReplaceJob tests replacing the spec of a stateful job
+func (suite *statefulHandlerTestSuite) TestReplaceJob() {
+	spec := newTestJobSpec(
+		stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_RETAIN)
+	version := &v1alphapeloton.EntityVersion{Value: "1-1-1"}
+
+	suite.expectGetJob(spec)
+	suite.jobSvc.EXPECT().
+		ReplaceJob(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *statelesssvc.ReplaceJobRequest) {
+			suite.Equal(version, req.GetVersion())
+			suite.Equal(uint32(1), req.GetUpdateSpec().GetBatchSize())
+			suite.True(req.GetUpdateSpec().GetInPlace())
+		}).
+		Return(&statelesssvc.ReplaceJobResponse{
+			Version: &v1alphapeloton.EntityVersion{Value: "2-1-2"},
+		}, nil)
+
+	resp, err := suite.handler.ReplaceJob(suite.ctx, &svc.ReplaceJobRequest{
+		JobId:   &v1alphapeloton.JobID{Value: testJobID},
+		Version: version,
+		Spec:    spec,
+	})
+	suite.NoError(err)
+	suite.Equal("2-1-2", resp.GetVersion().GetValue())
+}
+
+// TestReplaceJobVolumeChanged tests that the persistent volume of
+// a stateful job cannot be changed
+func (suite *statefulHandlerTestSuite) TestReplaceJobVolumeChanged() {
+	spec := newTestJobSpec(
+		stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_RETAIN)
+	suite.expectGetJob(spec)
+
+	newSpec := newTestJobSpec(
+		stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_RETAIN)
+	newSpec.Spec.DefaultSpec.Volume.SizeMb = 2048
+
+	_, err := suite.handler.ReplaceJob(suite.ctx, &svc.ReplaceJobRequest{
+		JobId: &v1alphapeloton.JobID{Value: testJobID},
+		Spec:  newSpec,
+	})
+	suite.Error(err)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestDeleteJobRetainVolumes tests deleting a stateful job
+// which retains its volumes
+func (suite *statefulHandlerTestSuite) TestDeleteJobRetainVolumes() {
+	suite.expectGetJob(newTestJobSpec(
+		stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_INVALID))
+	suite.jobSvc.EXPECT().
+		DeleteJob(gomock.Any(), gomock.Any()).
+		Return(&statelesssvc.DeleteJobResponse{}, nil)
+
+	_, err := suite.handler.DeleteJob(suite.ctx, &svc.DeleteJobRequest{
+		JobId: &v1alphapeloton.JobID{Value: testJobID},
+	})
+	suite.NoError(err)
+}
+
+// TestDeleteJobDeleteVolumes tests deleting a stateful job
+// which deletes its volumes
+func (suite *statefulHandlerTestSuite) TestDeleteJobDeleteVolumes() {
+	volumeID := &peloton.VolumeID{Value: testVolumeID}
+	volumeInfo := &volume.PersistentVolumeInfo{
+		Id:        volumeID,
+		State:     volume.VolumeState_CREATED,
+		GoalState: volume.VolumeState_CREATED,
+	}
+
+	suite.expectGetJob(newTestJobSpec(
+		stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_DELETE))
+	gomock.InOrder(
+		suite.taskStore.EXPECT().
+			GetTasksForJob(gomock.Any(), &peloton.JobID{Value: testJobID}).
+			Return(map[uint32]*task.TaskInfo{
+				0: {Runtime: &task.RuntimeInfo{VolumeID: volumeID}},
+				1: {Runtime: &task.RuntimeInfo{}},
+			}, nil),
+		suite.jobSvc.EXPECT().
+			DeleteJob(gomock.Any(), gomock.Any()).
+			Return(&statelesssvc.DeleteJobResponse{}, nil),
+		suite.volumeStore.EXPECT().
+			GetPersistentVolume(gomock.Any(), volumeID).
+			Return(volumeInfo, nil),
+		suite.volumeStore.EXPECT().
+			UpdatePersistentVolume(gomock.Any(), volumeInfo).
+			Return(nil),
+	)
+
+	_, err := suite.handler.DeleteJob(suite.ctx, &svc.DeleteJobRequest{
+		JobId: &v1alphapeloton.JobID{Value: testJobID},
+	})
+	suite.NoError(err)
+	suite.Equal(volume.VolumeState_DELETED, volumeInfo.GetGoalState())
+}
+
+// TestDeleteJobFailure tests that the volumes are not deleted
+// if the job fails to be deleted
+func (suite *statefulHandlerTestSuite) TestDeleteJobFailure() {
+	suite.expectGetJob(newTestJobSpec(
+		stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_DELETE))
+	suite.taskStore.EXPECT().
+		GetTasksForJob(gomock.Any(), gomock.Any()).
+		Return(map[uint32]*task.TaskInfo{
+			0: {Runtime: &task.RuntimeInfo{
+				VolumeID: &peloton.VolumeID{Value: testVolumeID},
+			}},
+		}, nil)
+	suite.jobSvc.EXPECT().
+		DeleteJob(gomock.Any(), gomock.Any()).
+		Return(nil, yarpcerrors.AbortedErrorf("job is not stopped"))
+
+	_, err := suite.handler.DeleteJob(suite.ctx, &svc.DeleteJobRequest{
+		JobId: &v1alphapeloton.JobID{Value: testJobID},
+	})
+	suite.Error(err)
+	suite.True(yarpcerrors.IsAborted(err))
+}
+
+// TestGetJob tests getting a stateful job
+func (suite *statefulHandlerTestSuite) TestGetJob() {
+	spec := newTestJobSpec(
+		stateful.VolumeRetentionPolicy_VOLUME_RETENTION_POLICY_DELETE)
+	suite.expectGetJob(spec)
+
+	resp, err := suite.handler.GetJob(suite.ctx, &svc.GetJobRequest{
+		JobId: &v1alphapeloton.JobID{Value: testJobID},
+	})
+	suite.NoError(err)
+	suite.Equal(testJobID, resp.GetJobInfo().GetJobId().GetValue())
+	suite.Equal(spec, resp.GetJobInfo().GetSpec())
+}
+
+// TestGetJobNotStateful tests getting a job which is not stateful
+func (suite *statefulHandlerTestSuite) TestGetJobNotStateful() {
+	suite.jobSvc.EXPECT().
+		GetJob(gomock.Any(), gomock.Any()).
+		Return(&statelesssvc.GetJobResponse{
+			JobInfo: &stateless.JobInfo{
+				Spec: &stateless.JobSpec{DefaultSpec: &pod.PodSpec{}},
+			},
+		}, nil)
+
+	_, err := suite.handler.GetJob(suite.ctx, &svc.GetJobRequest{
+		JobId: &v1alphapeloton.JobID{Value: testJobID},
+	})
+	suite.Error(err)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
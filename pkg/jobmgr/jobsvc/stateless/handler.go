@@ -52,6 +52,7 @@ import (
 	ormobjects "github.com/uber/peloton/pkg/storage/objects"
 
 	"github.com/gocql/gocql"
+	"github.com/golang/protobuf/proto"
 	"github.com/pborman/uuid"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -88,7 +89,8 @@ const (
 	_defaultInstanceWorkflowEventsWorker = 25
 )
 
-// InitV1AlphaJobServiceHandler initializes the Job Manager V1Alpha Service Handler
+// InitV1AlphaJobServiceHandler initializes the Job Manager V1Alpha Service
+// Handler, and returns it for use by other handlers in Job Manager.
 func InitV1AlphaJobServiceHandler(
 	d *yarpc.Dispatcher,
 	jobStore storage.JobStore,
@@ -100,7 +102,7 @@ func InitV1AlphaJobServiceHandler(
 	candidate leader.Candidate,
 	jobSvcCfg jobsvc.Config,
 	activeRMTasks activermtask.ActiveRMTasks,
-) {
+) svc.JobServiceYARPCServer {
 	handler := &serviceHandler{
 		jobStore:       jobStore,
 		updateStore:    updateStore,
@@ -119,6 +121,7 @@ func InitV1AlphaJobServiceHandler(
 		activeRMTasks:   activeRMTasks,
 	}
 	d.Register(svc.BuildJobServiceYARPCProcedures(handler))
+	return handler
 }
 
 func (h *serviceHandler) CreateJob(
@@ -316,8 +319,161 @@ func (h *serviceHandler) ReplaceJob(
 		return nil, errors.Wrap(err, "failed to get previous job spec")
 	}
 
+	updateID, newEntityVersion, err := h.createUpdateWorkflow(
+		ctx,
+		jobID,
+		cachedJob,
+		jobConfig,
+		prevJobConfig,
+		prevConfigAddOn,
+		req.GetVersion(),
+		req.GetUpdateSpec(),
+		req.GetOpaqueData(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &svc.ReplaceJobResponse{Version: newEntityVersion}, nil
+}
+
+func (h *serviceHandler) PatchJob(
+	ctx context.Context,
+	req *svc.PatchJobRequest) (resp *svc.PatchJobResponse, err error) {
+	var updateID *peloton.UpdateID
+
+	defer func() {
+		jobID := req.GetJobId().GetValue()
+		entityVersion := req.GetVersion().GetValue()
+		headers := yarpcutil.GetHeaders(ctx)
+
+		if err != nil {
+			log.WithField("job_id", jobID).
+				WithField("entity_version", entityVersion).
+				WithField("headers", headers).
+				WithError(err).
+				Warn("JobSVC.PatchJob failed")
+			err = yarpcutil.ConvertToYARPCError(err)
+			return
+		}
+
+		log.WithField("job_id", jobID).
+			WithField("entity_version", entityVersion).
+			WithField("response", resp).
+			WithField("update_id", updateID.GetValue()).
+			WithField("headers", headers).
+			Info("JobSVC.PatchJob succeeded")
+	}()
+
+	if !h.candidate.IsLeader() {
+		return nil,
+			yarpcerrors.UnavailableErrorf("JobSVC.PatchJob is not supported on non-leader")
+	}
+
+	jobUUID := uuid.Parse(req.GetJobId().GetValue())
+	if jobUUID == nil {
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"JobID must be of UUID format")
+	}
+
+	// the patch is applied onto the config of the entity version
+	// provided, CreateWorkflow then makes sure the entity version is
+	// still the current one so that a concurrent change is not overwritten
+	configVersion, err := versionutil.GetConfigVersion(req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	jobID := &peloton.JobID{Value: req.GetJobId().GetValue()}
+
+	cachedJob := h.jobFactory.AddJob(jobID)
+	prevJobConfig, prevConfigAddOn, err := h.jobConfigOps.Get(
+		ctx,
+		jobID,
+		configVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get previous job spec")
+	}
+
+	// the secret volumes added by peloton when the secrets of the job were
+	// created are taken out of the spec being patched, so that the patched
+	// spec can be validated like the spec of a new job
+	currentConfig := proto.Clone(prevJobConfig).(*pbjob.JobConfig)
+	secretVolumes := util.RemoveSecretVolumesFromJobConfig(currentConfig)
+
+	spec := mergeJobSpec(
+		handlerutil.ConvertJobConfigToJobSpec(currentConfig),
+		req.GetSpec(),
+	)
+
+	// check secrets and config for input sanity
+	if err = h.validateSecretsAndConfig(spec, req.GetSecrets()); err != nil {
+		return nil, errors.Wrap(err, "input cannot contain secret volume")
+	}
+	if err = validatePatchSecrets(secretVolumes, req.GetSecrets()); err != nil {
+		return nil, err
+	}
+
+	// keep the existing secrets of the job, and create the new secrets in
+	// the DB and add them as secret volumes to the default spec
+	if len(secretVolumes) > 0 {
+		if err = validateMesosContainerizerForSecrets(spec); err != nil {
+			return nil, err
+		}
+		addSecretVolumes(spec, secretVolumes)
+	}
+	err = h.handleCreateSecrets(ctx, jobID.GetValue(), spec, req.GetSecrets())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to handle create-secrets")
+	}
+
+	jobConfig, err := handlerutil.ConvertJobSpecToJobConfig(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert job spec")
+	}
+
+	err = jobconfig.ValidateConfig(
+		jobConfig,
+		h.jobSvcCfg.MaxTasksPerJob,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid job spec")
+	}
+
+	updateID, newEntityVersion, err := h.createUpdateWorkflow(
+		ctx,
+		jobID,
+		cachedJob,
+		jobConfig,
+		prevJobConfig,
+		prevConfigAddOn,
+		req.GetVersion(),
+		req.GetUpdateSpec(),
+		req.GetOpaqueData(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &svc.PatchJobResponse{Version: newEntityVersion}, nil
+}
+
+// createUpdateWorkflow validates the new job config against the previous
+// job config, and creates an update workflow to move the job to the new
+// job config.
+func (h *serviceHandler) createUpdateWorkflow(
+	ctx context.Context,
+	jobID *peloton.JobID,
+	cachedJob cached.Job,
+	jobConfig *pbjob.JobConfig,
+	prevJobConfig *pbjob.JobConfig,
+	prevConfigAddOn *models.ConfigAddOn,
+	version *v1alphapeloton.EntityVersion,
+	updateSpec *stateless.UpdateSpec,
+	opaqueData *v1alphapeloton.OpaqueData,
+) (*peloton.UpdateID, *v1alphapeloton.EntityVersion, error) {
 	if err := validateJobConfigUpdate(prevJobConfig, jobConfig); err != nil {
-		return nil, errors.Wrap(err, "failed to validate spec update")
+		return nil, nil, errors.Wrap(err, "failed to validate spec update")
 	}
 
 	// get the new configAddOn
@@ -332,9 +488,9 @@ func (h *serviceHandler) ReplaceJob(
 	}
 
 	opaque := cached.WithOpaqueData(nil)
-	if req.GetOpaqueData() != nil {
+	if opaqueData != nil {
 		opaque = cached.WithOpaqueData(&peloton.OpaqueData{
-			Data: req.GetOpaqueData().GetData(),
+			Data: opaqueData.GetData(),
 		})
 	}
 
@@ -347,8 +503,8 @@ func (h *serviceHandler) ReplaceJob(
 	updateID, newEntityVersion, err := cachedJob.CreateWorkflow(
 		ctx,
 		models.WorkflowType_UPDATE,
-		handlerutil.ConvertUpdateSpecToUpdateConfig(req.GetUpdateSpec()),
-		req.GetVersion(),
+		handlerutil.ConvertUpdateSpecToUpdateConfig(updateSpec),
+		version,
 		cached.WithConfig(jobConfig, prevJobConfig, configAddOn),
 		opaque,
 	)
@@ -365,16 +521,10 @@ func (h *serviceHandler) ReplaceJob(
 	}
 
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create update workload")
+		return updateID, nil, errors.Wrap(err, "failed to create update workload")
 	}
 
-	return &svc.ReplaceJobResponse{Version: newEntityVersion}, nil
-}
-
-func (h *serviceHandler) PatchJob(
-	ctx context.Context,
-	req *svc.PatchJobRequest) (*svc.PatchJobResponse, error) {
-	return &svc.PatchJobResponse{}, nil
+	return updateID, newEntityVersion, nil
 }
 
 func (h *serviceHandler) RestartJob(
@@ -514,7 +664,7 @@ func (h *serviceHandler) PauseJobWorkflow(
 	)
 
 	if len(updateID.GetValue()) > 0 {
-		h.goalStateDriver.EnqueueUpdate(cachedJob.ID(), updateID, time.Now())
+		h.goalStateDriver.EnqueueUpdate(jobID, updateID, time.Now())
 	}
 
 	if err != nil {
@@ -563,7 +713,7 @@ func (h *serviceHandler) ResumeJobWorkflow(
 	)
 
 	if len(updateID.GetValue()) > 0 {
-		h.goalStateDriver.EnqueueUpdate(cachedJob.ID(), updateID, time.Now())
+		h.goalStateDriver.EnqueueUpdate(jobID, updateID, time.Now())
 	}
 
 	if err != nil {
@@ -612,7 +762,7 @@ func (h *serviceHandler) AbortJobWorkflow(
 	)
 
 	if len(updateID.GetValue()) > 0 {
-		h.goalStateDriver.EnqueueUpdate(cachedJob.ID(), updateID, time.Now())
+		h.goalStateDriver.EnqueueUpdate(jobID, updateID, time.Now())
 	}
 
 	if err != nil {
@@ -1824,6 +1974,42 @@ func (h *serviceHandler) validateSecretsAndConfig(
 	return nil
 }
 
+// validatePatchSecrets makes sure that the secrets of a patch are new
+// secrets. The existing secrets of a job are kept as is by a patch.
+func validatePatchSecrets(
+	secretVolumes []*mesos.Volume,
+	secrets []*v1alphapeloton.Secret) error {
+	for _, volume := range secretVolumes {
+		existingSecretID := string(
+			volume.GetSource().GetSecret().GetValue().GetData())
+		for _, secret := range secrets {
+			if (secret.GetSecretId().GetValue() != "" &&
+				secret.GetSecretId().GetValue() == existingSecretID) ||
+				secret.GetPath() == volume.GetContainerPath() {
+				return yarpcerrors.InvalidArgumentErrorf(
+					"secret with id %v path %v already exists, "+
+						"only new secrets can be added by a patch",
+					existingSecretID, volume.GetContainerPath())
+			}
+		}
+	}
+	return nil
+}
+
+// addSecretVolumes adds secret volumes to the containers of the default
+// spec of a job
+func addSecretVolumes(spec *stateless.JobSpec, volumes []*mesos.Volume) {
+	for _, container := range spec.GetDefaultSpec().GetContainers() {
+		if container.GetContainer() == nil {
+			container.Container = &mesos.ContainerInfo{
+				Type: mesos.ContainerInfo_MESOS.Enum(),
+			}
+		}
+		container.Container.Volumes = append(
+			container.Container.Volumes, volumes...)
+	}
+}
+
 // validateMesosContainerizerForSecrets returns error if default config doesn't
 // use mesos containerizer. Secrets will be common for all instances in a job.
 // They will be a part of default container config. This means that if a job is
@@ -1296,6 +1296,356 @@ func (suite *statelessHandlerTestSuite) TestReplaceJobGetJobConfigFailure() {
 	suite.Nil(resp)
 }
 
+// TestPatchJobSuccess tests the success case of patching a job
+func (suite *statelessHandlerTestSuite) TestPatchJobSuccess() {
+	command := "echo hello"
+	batchSize := uint32(1)
+	entityVersion := &v1alphapeloton.EntityVersion{Value: testEntityVersion}
+	newEntityVersion := versionutil.GetJobEntityVersion(
+		testConfigurationVersion+1,
+		testDesiredStateVersion,
+		testWorkflowVersion+1,
+	)
+
+	suite.candidate.EXPECT().
+		IsLeader().
+		Return(true)
+
+	suite.jobFactory.EXPECT().
+		AddJob(testPelotonJobID).
+		Return(suite.cachedJob)
+
+	suite.jobConfigOps.EXPECT().
+		Get(
+			gomock.Any(),
+			testPelotonJobID,
+			testConfigurationVersion,
+		).Return(
+		&pbjob.JobConfig{
+			Type:          pbjob.JobType_SERVICE,
+			InstanceCount: 2,
+			DefaultConfig: &pbtask.TaskConfig{
+				Command: &mesos.CommandInfo{Value: &command},
+			},
+		},
+		&models.ConfigAddOn{},
+		nil)
+
+	suite.cachedJob.EXPECT().
+		CreateWorkflow(
+			gomock.Any(),
+			models.WorkflowType_UPDATE,
+			&pbupdate.UpdateConfig{
+				BatchSize: batchSize,
+			},
+			entityVersion,
+			gomock.Any(),
+		).
+		Return(
+			&peloton.UpdateID{Value: testUpdateID},
+			newEntityVersion,
+			nil)
+
+	suite.goalStateDriver.EXPECT().
+		EnqueueUpdate(testPelotonJobID, &peloton.UpdateID{Value: testUpdateID}, gomock.Any()).
+		Return()
+
+	resp, err := suite.handler.PatchJob(
+		context.Background(),
+		&statelesssvc.PatchJobRequest{
+			JobId:   &v1alphapeloton.JobID{Value: testJobID},
+			Version: entityVersion,
+			Spec:    &stateless.JobSpec{InstanceCount: 3},
+			UpdateSpec: &stateless.UpdateSpec{
+				BatchSize: batchSize,
+			},
+		},
+	)
+	suite.NoError(err)
+	suite.Equal(newEntityVersion, resp.GetVersion())
+}
+
+// TestPatchJobFailNonLeader tests the failure case of patching a job
+// due to JobMgr is not leader
+func (suite *statelessHandlerTestSuite) TestPatchJobFailNonLeader() {
+	suite.candidate.EXPECT().
+		IsLeader().
+		Return(false)
+
+	resp, err := suite.handler.PatchJob(
+		context.Background(),
+		&statelesssvc.PatchJobRequest{
+			JobId: &v1alphapeloton.JobID{Value: testJobID},
+		})
+	suite.Nil(resp)
+	suite.True(yarpcerrors.IsUnavailable(err))
+}
+
+// TestPatchJobInvalidVersion tests the failure case of patching a job
+// without a valid entity version
+func (suite *statelessHandlerTestSuite) TestPatchJobInvalidVersion() {
+	suite.candidate.EXPECT().
+		IsLeader().
+		Return(true)
+
+	resp, err := suite.handler.PatchJob(
+		context.Background(),
+		&statelesssvc.PatchJobRequest{
+			JobId: &v1alphapeloton.JobID{Value: testJobID},
+			Spec:  &stateless.JobSpec{InstanceCount: 3},
+		})
+	suite.Nil(resp)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestPatchJobGetJobConfigFailure tests the failure case of patching
+// a job due to not able to get job config
+func (suite *statelessHandlerTestSuite) TestPatchJobGetJobConfigFailure() {
+	suite.candidate.EXPECT().
+		IsLeader().
+		Return(true)
+
+	suite.jobFactory.EXPECT().
+		AddJob(testPelotonJobID).
+		Return(suite.cachedJob)
+
+	suite.jobConfigOps.EXPECT().
+		Get(
+			gomock.Any(),
+			testPelotonJobID,
+			testConfigurationVersion,
+		).Return(nil, nil, yarpcerrors.InternalErrorf("test error"))
+
+	resp, err := suite.handler.PatchJob(
+		context.Background(),
+		&statelesssvc.PatchJobRequest{
+			JobId:   &v1alphapeloton.JobID{Value: testJobID},
+			Version: &v1alphapeloton.EntityVersion{Value: testEntityVersion},
+			Spec:    &stateless.JobSpec{InstanceCount: 3},
+		})
+	suite.Nil(resp)
+	suite.Error(err)
+}
+
+// TestPatchJobImmutableField tests the failure case of patching
+// a field which cannot be updated
+func (suite *statelessHandlerTestSuite) TestPatchJobImmutableField() {
+	command := "echo hello"
+
+	suite.candidate.EXPECT().
+		IsLeader().
+		Return(true)
+
+	suite.jobFactory.EXPECT().
+		AddJob(testPelotonJobID).
+		Return(suite.cachedJob)
+
+	suite.jobConfigOps.EXPECT().
+		Get(
+			gomock.Any(),
+			testPelotonJobID,
+			testConfigurationVersion,
+		).Return(
+		&pbjob.JobConfig{
+			Type:          pbjob.JobType_SERVICE,
+			InstanceCount: 2,
+			DefaultConfig: &pbtask.TaskConfig{
+				Command: &mesos.CommandInfo{Value: &command},
+			},
+			RespoolID: &peloton.ResourcePoolID{Value: "respool1"},
+		},
+		&models.ConfigAddOn{},
+		nil)
+
+	resp, err := suite.handler.PatchJob(
+		context.Background(),
+		&statelesssvc.PatchJobRequest{
+			JobId:   &v1alphapeloton.JobID{Value: testJobID},
+			Version: &v1alphapeloton.EntityVersion{Value: testEntityVersion},
+			Spec: &stateless.JobSpec{
+				RespoolId: &v1alphapeloton.ResourcePoolID{Value: "respool2"},
+			},
+		})
+	suite.Nil(resp)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// patchJobConfigWithSecret returns the config of a job which was
+// created with a secret
+func patchJobConfigWithSecret() *pbjob.JobConfig {
+	command := "echo hello"
+	mesosContainerizer := mesos.ContainerInfo_MESOS
+	return &pbjob.JobConfig{
+		Type:          pbjob.JobType_SERVICE,
+		InstanceCount: 2,
+		DefaultConfig: &pbtask.TaskConfig{
+			Command: &mesos.CommandInfo{Value: &command},
+			Container: &mesos.ContainerInfo{
+				Type: &mesosContainerizer,
+				Volumes: []*mesos.Volume{
+					util.CreateSecretVolume("/tmp/existing", "existing-id"),
+				},
+			},
+		},
+	}
+}
+
+// TestPatchJobWithSecretsSuccess tests the success case of patching a
+// job which has a secret with a new secret
+func (suite *statelessHandlerTestSuite) TestPatchJobWithSecretsSuccess() {
+	entityVersion := &v1alphapeloton.EntityVersion{Value: testEntityVersion}
+	secret := &v1alphapeloton.Secret{
+		Path: testSecretPath,
+		Value: &v1alphapeloton.Secret_Value{
+			Data: []byte(base64.StdEncoding.EncodeToString(
+				[]byte(testSecretStr))),
+		},
+	}
+
+	suite.candidate.EXPECT().
+		IsLeader().
+		Return(true)
+
+	suite.jobFactory.EXPECT().
+		AddJob(testPelotonJobID).
+		Return(suite.cachedJob)
+
+	suite.jobConfigOps.EXPECT().
+		Get(
+			gomock.Any(),
+			testPelotonJobID,
+			testConfigurationVersion,
+		).Return(patchJobConfigWithSecret(), &models.ConfigAddOn{}, nil)
+
+	suite.secretInfoOps.EXPECT().CreateSecret(
+		gomock.Any(),
+		// jobID, now, secretID, secretString, secretPath
+		testJobID, gomock.Any(), gomock.Any(), string(secret.Value.Data), testSecretPath).
+		Return(nil)
+
+	suite.cachedJob.EXPECT().
+		CreateWorkflow(
+			gomock.Any(),
+			models.WorkflowType_UPDATE,
+			gomock.Any(),
+			entityVersion,
+			gomock.Any(),
+		).
+		Return(
+			&peloton.UpdateID{Value: testUpdateID},
+			entityVersion,
+			nil)
+
+	suite.goalStateDriver.EXPECT().
+		EnqueueUpdate(testPelotonJobID, &peloton.UpdateID{Value: testUpdateID}, gomock.Any()).
+		Return()
+
+	resp, err := suite.handler.PatchJob(
+		context.Background(),
+		&statelesssvc.PatchJobRequest{
+			JobId:   &v1alphapeloton.JobID{Value: testJobID},
+			Version: entityVersion,
+			Spec:    &stateless.JobSpec{InstanceCount: 3},
+			Secrets: []*v1alphapeloton.Secret{secret},
+		},
+	)
+	suite.NoError(err)
+	suite.NotNil(resp)
+}
+
+// TestPatchJobWithSecretsFailure tests the failure cases of patching a
+// job with secrets
+func (suite *statelessHandlerTestSuite) TestPatchJobWithSecretsFailure() {
+	entityVersion := &v1alphapeloton.EntityVersion{Value: testEntityVersion}
+	data := []byte(base64.StdEncoding.EncodeToString([]byte(testSecretStr)))
+
+	tt := []struct {
+		msg     string
+		secret  *v1alphapeloton.Secret
+		spec    *stateless.JobSpec
+		enabled bool
+	}{
+		{
+			msg: "existing secret path",
+			secret: &v1alphapeloton.Secret{
+				Path:  "/tmp/existing",
+				Value: &v1alphapeloton.Secret_Value{Data: data},
+			},
+			enabled: true,
+		},
+		{
+			msg: "existing secret id",
+			secret: &v1alphapeloton.Secret{
+				SecretId: &v1alphapeloton.SecretID{Value: "existing-id"},
+				Path:     testSecretPath,
+				Value:    &v1alphapeloton.Secret_Value{Data: data},
+			},
+			enabled: true,
+		},
+		{
+			msg: "secret without path",
+			secret: &v1alphapeloton.Secret{
+				Value: &v1alphapeloton.Secret_Value{Data: data},
+			},
+			enabled: true,
+		},
+		{
+			msg: "secrets not enabled",
+			secret: &v1alphapeloton.Secret{
+				Path:  testSecretPath,
+				Value: &v1alphapeloton.Secret_Value{Data: data},
+			},
+		},
+	}
+
+	for _, t := range tt {
+		suite.handler.jobSvcCfg.EnableSecrets = t.enabled
+
+		suite.candidate.EXPECT().
+			IsLeader().
+			Return(true)
+
+		suite.jobFactory.EXPECT().
+			AddJob(testPelotonJobID).
+			Return(suite.cachedJob)
+
+		suite.jobConfigOps.EXPECT().
+			Get(
+				gomock.Any(),
+				testPelotonJobID,
+				testConfigurationVersion,
+			).Return(patchJobConfigWithSecret(), &models.ConfigAddOn{}, nil)
+
+		resp, err := suite.handler.PatchJob(
+			context.Background(),
+			&statelesssvc.PatchJobRequest{
+				JobId:   &v1alphapeloton.JobID{Value: testJobID},
+				Version: entityVersion,
+				Spec:    &stateless.JobSpec{InstanceCount: 3},
+				Secrets: []*v1alphapeloton.Secret{t.secret},
+			},
+		)
+		suite.Nil(resp, t.msg)
+		suite.True(yarpcerrors.IsInvalidArgument(err), t.msg)
+	}
+}
+
+// TestAddSecretVolumes tests adding the existing secret volumes of a job
+// back to its patched spec
+func (suite *statelessHandlerTestSuite) TestAddSecretVolumes() {
+	config := patchJobConfigWithSecret()
+	volumes := util.RemoveSecretVolumesFromJobConfig(config)
+	spec := handlerutil.ConvertJobConfigToJobSpec(config)
+	suite.False(util.ConfigHasSecretVolumes(config.GetDefaultConfig()))
+
+	addSecretVolumes(spec, volumes)
+	patchedConfig, err := handlerutil.ConvertJobSpecToJobConfig(spec)
+	suite.NoError(err)
+	suite.Equal(
+		patchJobConfigWithSecret().GetDefaultConfig().GetContainer(),
+		patchedConfig.GetDefaultConfig().GetContainer())
+}
+
 // TestGetReplaceJobDiffSuccess tests the success case of getting the
 // difference in configuration for ReplaceJob API
 func (suite *statelessHandlerTestSuite) TestGetReplaceJobDiffSuccess() {
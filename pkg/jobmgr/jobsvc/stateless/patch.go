@@ -0,0 +1,161 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stateless
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/pod"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// _patchKeyFields are the fields identifying the elements of repeated
+// fields which are merged element by element when patching a job spec.
+var _patchKeyFields = map[reflect.Type]string{
+	reflect.TypeOf(&v1alphapeloton.Label{}): "Key",
+	reflect.TypeOf(&pod.ContainerSpec{}):    "Name",
+	reflect.TypeOf(&pod.Environment{}):      "Name",
+	reflect.TypeOf(&pod.PortSpec{}):         "Name",
+	reflect.TypeOf(&pod.VolumeMount{}):      "Name",
+}
+
+// mergeJobSpec merges a partial job spec onto the current job spec and
+// returns the merged spec. Neither the current spec nor the patch is
+// modified, and the merged spec shares no field with either of them.
+//
+// The merge rules are:
+//   - a scalar field is overwritten if it is set to a non-zero value in
+//     the patch, so a field cannot be reset to its zero value by a patch
+//   - a message field is merged recursively
+//   - labels, containers, ports, environment variables and volume mounts
+//     are merged by key (or name): elements with the same key are merged
+//     recursively and new elements are appended, no element is removed
+//   - any other repeated field is replaced if it is not empty in the patch
+//   - a map field is merged by key, values with the same key are
+//     merged recursively
+func mergeJobSpec(current, patch *stateless.JobSpec) *stateless.JobSpec {
+	merged := proto.Clone(current).(*stateless.JobSpec)
+	if merged == nil {
+		merged = &stateless.JobSpec{}
+	}
+	if patch == nil {
+		return merged
+	}
+
+	// the fields of the patch are set on the merged spec by reference,
+	// so they are copied from a clone owned by the merged spec
+	patch = proto.Clone(patch).(*stateless.JobSpec)
+	mergeStruct(reflect.ValueOf(merged).Elem(), reflect.ValueOf(patch).Elem())
+	return merged
+}
+
+// mergeStruct merges all the fields of src onto dst
+func mergeStruct(dst, src reflect.Value) {
+	for i := 0; i < src.NumField(); i++ {
+		if strings.HasPrefix(src.Type().Field(i).Name, "XXX_") {
+			continue
+		}
+		mergeField(dst.Field(i), src.Field(i))
+	}
+}
+
+func mergeField(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return
+		}
+		if dst.IsNil() || src.Elem().Kind() != reflect.Struct {
+			dst.Set(src)
+			return
+		}
+		mergeStruct(dst.Elem(), src.Elem())
+
+	case reflect.Slice:
+		if src.Len() == 0 {
+			return
+		}
+		keyField, ok := _patchKeyFields[src.Type().Elem()]
+		if !ok {
+			dst.Set(src)
+			return
+		}
+		mergeKeyedSlice(dst, src, keyField)
+
+	case reflect.Map:
+		if src.Len() == 0 {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMap(src.Type()))
+		}
+		for _, key := range src.MapKeys() {
+			srcValue := src.MapIndex(key)
+			dstValue := dst.MapIndex(key)
+			if dstValue.IsValid() &&
+				srcValue.Kind() == reflect.Ptr &&
+				!srcValue.IsNil() &&
+				!dstValue.IsNil() &&
+				srcValue.Elem().Kind() == reflect.Struct {
+				mergeStruct(dstValue.Elem(), srcValue.Elem())
+				continue
+			}
+			dst.SetMapIndex(key, srcValue)
+		}
+
+	case reflect.Interface:
+		// oneof fields
+		if !src.IsNil() {
+			dst.Set(src)
+		}
+
+	default:
+		if src.Interface() != reflect.Zero(src.Type()).Interface() {
+			dst.Set(src)
+		}
+	}
+}
+
+// mergeKeyedSlice merges the elements of src onto the elements of dst
+// with the same key, and appends the elements with a new key.
+func mergeKeyedSlice(dst, src reflect.Value, keyField string) {
+	merged := dst
+	for i := 0; i < src.Len(); i++ {
+		srcElem := src.Index(i)
+		if srcElem.IsNil() {
+			continue
+		}
+		key := srcElem.Elem().FieldByName(keyField).Interface()
+
+		found := false
+		for j := 0; j < merged.Len(); j++ {
+			dstElem := merged.Index(j)
+			if !dstElem.IsNil() &&
+				dstElem.Elem().FieldByName(keyField).Interface() == key {
+				mergeStruct(dstElem.Elem(), srcElem.Elem())
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = reflect.Append(merged, srcElem)
+		}
+	}
+	dst.Set(merged)
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stateless
+
+import (
+	"testing"
+
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/pod"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCurrentJobSpec() *stateless.JobSpec {
+	return &stateless.JobSpec{
+		Name:          "test-job",
+		InstanceCount: 2,
+		LdapGroups:    []string{"group1", "group2"},
+		Labels: []*v1alphapeloton.Label{
+			{Key: "key1", Value: "value1"},
+			{Key: "key2", Value: "value2"},
+		},
+		DefaultSpec: &pod.PodSpec{
+			Containers: []*pod.ContainerSpec{
+				{
+					Name:  "container1",
+					Image: "image:1",
+					Resource: &pod.ResourceSpec{
+						CpuLimit:   1,
+						MemLimitMb: 100,
+					},
+					Entrypoint: &pod.CommandSpec{
+						Value:     "run",
+						Arguments: []string{"--port", "8080"},
+					},
+					Environment: []*pod.Environment{
+						{Name: "ENV1", Value: "1"},
+						{Name: "ENV2", Value: "2"},
+					},
+				},
+			},
+		},
+		InstanceSpec: map[uint32]*pod.PodSpec{
+			0: {
+				Containers: []*pod.ContainerSpec{
+					{Name: "container1", Image: "image:0"},
+				},
+			},
+		},
+	}
+}
+
+// TestMergeJobSpecScalars tests patching scalar fields
+func TestMergeJobSpecScalars(t *testing.T) {
+	current := testCurrentJobSpec()
+	merged := mergeJobSpec(current, &stateless.JobSpec{
+		InstanceCount: 5,
+	})
+
+	expected := testCurrentJobSpec()
+	expected.InstanceCount = 5
+	assert.True(t, proto.Equal(expected, merged))
+
+	// the current spec is not modified
+	assert.True(t, proto.Equal(testCurrentJobSpec(), current))
+}
+
+// TestMergeJobSpecNilPatch tests patching with an empty patch
+func TestMergeJobSpecNilPatch(t *testing.T) {
+	assert.True(t, proto.Equal(
+		testCurrentJobSpec(),
+		mergeJobSpec(testCurrentJobSpec(), nil),
+	))
+	assert.True(t, proto.Equal(
+		testCurrentJobSpec(),
+		mergeJobSpec(testCurrentJobSpec(), &stateless.JobSpec{}),
+	))
+}
+
+// TestMergeJobSpecLabels tests labels are merged by key
+func TestMergeJobSpecLabels(t *testing.T) {
+	merged := mergeJobSpec(testCurrentJobSpec(), &stateless.JobSpec{
+		Labels: []*v1alphapeloton.Label{
+			{Key: "key2", Value: "new-value2"},
+			{Key: "key3", Value: "value3"},
+		},
+	})
+
+	assert.Equal(t, []*v1alphapeloton.Label{
+		{Key: "key1", Value: "value1"},
+		{Key: "key2", Value: "new-value2"},
+		{Key: "key3", Value: "value3"},
+	}, merged.GetLabels())
+}
+
+// TestMergeJobSpecRepeatedReplaced tests repeated fields without a key
+// are replaced
+func TestMergeJobSpecRepeatedReplaced(t *testing.T) {
+	merged := mergeJobSpec(testCurrentJobSpec(), &stateless.JobSpec{
+		LdapGroups: []string{"group3"},
+		DefaultSpec: &pod.PodSpec{
+			Containers: []*pod.ContainerSpec{
+				{
+					Name: "container1",
+					Entrypoint: &pod.CommandSpec{
+						Arguments: []string{"--port", "9090"},
+					},
+				},
+			},
+		},
+	})
+
+	assert.Equal(t, []string{"group3"}, merged.GetLdapGroups())
+	entrypoint := merged.GetDefaultSpec().GetContainers()[0].GetEntrypoint()
+	assert.Equal(t, "run", entrypoint.GetValue())
+	assert.Equal(t, []string{"--port", "9090"}, entrypoint.GetArguments())
+}
+
+// TestMergeJobSpecContainers tests containers and their environment
+// variables are merged by name
+func TestMergeJobSpecContainers(t *testing.T) {
+	merged := mergeJobSpec(testCurrentJobSpec(), &stateless.JobSpec{
+		DefaultSpec: &pod.PodSpec{
+			Containers: []*pod.ContainerSpec{
+				{
+					Name: "container1",
+					Resource: &pod.ResourceSpec{
+						MemLimitMb: 200,
+					},
+					Environment: []*pod.Environment{
+						{Name: "ENV2", Value: "new-2"},
+						{Name: "ENV3", Value: "3"},
+					},
+				},
+				{
+					Name:  "sidecar",
+					Image: "sidecar:1",
+				},
+			},
+		},
+	})
+
+	containers := merged.GetDefaultSpec().GetContainers()
+	assert.Len(t, containers, 2)
+	assert.Equal(t, "image:1", containers[0].GetImage())
+	assert.Equal(t, float64(1), containers[0].GetResource().GetCpuLimit())
+	assert.Equal(t, float64(200), containers[0].GetResource().GetMemLimitMb())
+	assert.Equal(t, []*pod.Environment{
+		{Name: "ENV1", Value: "1"},
+		{Name: "ENV2", Value: "new-2"},
+		{Name: "ENV3", Value: "3"},
+	}, containers[0].GetEnvironment())
+	assert.Equal(t, "sidecar", containers[1].GetName())
+	assert.Equal(t, "sidecar:1", containers[1].GetImage())
+}
+
+// TestMergeJobSpecInstanceSpec tests instance specs are merged by
+// instance id
+func TestMergeJobSpecInstanceSpec(t *testing.T) {
+	merged := mergeJobSpec(testCurrentJobSpec(), &stateless.JobSpec{
+		InstanceSpec: map[uint32]*pod.PodSpec{
+			0: {
+				Containers: []*pod.ContainerSpec{
+					{Name: "container1", Image: "image:2"},
+				},
+			},
+			1: {
+				Containers: []*pod.ContainerSpec{
+					{Name: "container1", Image: "image:3"},
+				},
+			},
+		},
+	})
+
+	assert.Len(t, merged.GetInstanceSpec(), 2)
+	assert.Equal(t, "image:2",
+		merged.GetInstanceSpec()[0].GetContainers()[0].GetImage())
+	assert.Equal(t, "image:3",
+		merged.GetInstanceSpec()[1].GetContainers()[0].GetImage())
+}
+
+// TestMergeJobSpecNoAliasing tests that the merged spec does not share
+// any field with the patch
+func TestMergeJobSpecNoAliasing(t *testing.T) {
+	patch := &stateless.JobSpec{
+		LdapGroups: []string{"group3"},
+		Labels: []*v1alphapeloton.Label{
+			{Key: "key3", Value: "value3"},
+		},
+		InstanceSpec: map[uint32]*pod.PodSpec{
+			1: {
+				Containers: []*pod.ContainerSpec{
+					{Name: "container1", Image: "image:3"},
+				},
+			},
+		},
+	}
+	expected := proto.Clone(patch).(*stateless.JobSpec)
+
+	merged := mergeJobSpec(testCurrentJobSpec(), patch)
+	merged.LdapGroups[0] = "group4"
+	merged.Labels[2].Value = "value4"
+	merged.InstanceSpec[1].Containers[0].Image = "image:4"
+	merged.InstanceSpec[2] = &pod.PodSpec{}
+
+	// mutating the merged spec does not modify the patch
+	assert.True(t, proto.Equal(expected, patch))
+}
@@ -16,8 +16,10 @@ package logmanager
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/uber/peloton/pkg/common"
 )
@@ -25,8 +27,13 @@ import (
 const (
 	_slaveSandboxDir    = "%s/slaves/%s/frameworks/%s/executors/%s/runs/latest"
 	_slaveFileBrowseURL = "http://%s:%s/files/browse?path=%s"
+	_slaveFileReadURL   = "http://%s:%s/files/read?%s"
 )
 
+// ErrFileNotFound is returned when the file to read does not
+// exist in the sandbox.
+var ErrFileNotFound = errors.New("file not found in sandbox")
+
 // TODO: (varung) Move this component to HostManger
 
 // LogManager log manager, is used to access sandbox files under mesos agent executor run directory.
@@ -40,6 +47,27 @@ type LogManager interface {
 		port,
 		agentID,
 		taskID string) ([]string, error)
+
+	// ReadSandboxFile reads at most length bytes of a file in the mesos agent
+	// executor run directory, starting at offset. An offset of -1 returns an
+	// empty chunk whose offset is the current size of the file.
+	ReadSandboxFile(mesosAgentWorDir,
+		frameworkID,
+		hostname,
+		port,
+		agentID,
+		taskID,
+		filename string,
+		offset,
+		length int64) (*SandboxFileChunk, error)
+}
+
+// SandboxFileChunk is a chunk of a sandbox file read from a mesos agent.
+type SandboxFileChunk struct {
+	// Data is the content of the chunk.
+	Data []byte
+	// Offset is the offset of the chunk in the file.
+	Offset int64
 }
 
 // logManager is a wrapper to collect logs location by talking to mesos agents.
@@ -58,6 +86,11 @@ type filePath struct {
 	Path string `json:"path"`
 }
 
+type fileData struct {
+	Data   string `json:"data"`
+	Offset int64  `json:"offset"`
+}
+
 // ListSandboxFilesPaths returns the list of logs url under sandbox directory for given task.
 func (l *logManager) ListSandboxFilesPaths(
 	mesosAgentWorDir, frameworkID, hostname, port,
@@ -92,6 +125,43 @@ func (l *logManager) ListSandboxFilesPaths(
 	return result, nil
 }
 
+// ReadSandboxFile reads a chunk of a file under the sandbox directory
+// of the given task.
+func (l *logManager) ReadSandboxFile(
+	mesosAgentWorDir, frameworkID, hostname, port,
+	agentID, taskID, filename string,
+	offset, length int64) (*SandboxFileChunk, error) {
+	slaveReadURL := getSlaveFileReadEndpointURL(
+		mesosAgentWorDir,
+		frameworkID,
+		hostname,
+		port,
+		agentID,
+		taskID,
+		filename,
+		offset,
+		length)
+
+	result, err := readTaskLogFile(l.client, slaveReadURL)
+	if err == ErrFileNotFound {
+		// Same as ListSandboxFilesPaths, the task may have been
+		// launched by thermos executor
+		slaveReadURL = getSlaveFileReadEndpointURL(
+			mesosAgentWorDir,
+			frameworkID,
+			hostname,
+			port,
+			agentID,
+			common.PelotonAuroraBridgeExecutorIDPrefix+taskID,
+			filename,
+			offset,
+			length)
+
+		result, err = readTaskLogFile(l.client, slaveReadURL)
+	}
+	return result, err
+}
+
 func getSlaveFileBrowseEndpointURL(mesosAgentWorDir, frameworkID,
 	hostname, port, agentID, taskID string) string {
 	sandboxDir := fmt.Sprintf(
@@ -103,6 +173,22 @@ func getSlaveFileBrowseEndpointURL(mesosAgentWorDir, frameworkID,
 	return fmt.Sprintf(_slaveFileBrowseURL, hostname, port, sandboxDir)
 }
 
+func getSlaveFileReadEndpointURL(mesosAgentWorDir, frameworkID,
+	hostname, port, agentID, taskID, filename string,
+	offset, length int64) string {
+	sandboxDir := fmt.Sprintf(
+		_slaveSandboxDir,
+		mesosAgentWorDir,
+		agentID,
+		frameworkID,
+		taskID)
+	query := url.Values{}
+	query.Set("path", sandboxDir+"/"+filename)
+	query.Set("offset", fmt.Sprint(offset))
+	query.Set("length", fmt.Sprint(length))
+	return fmt.Sprintf(_slaveFileReadURL, hostname, port, query.Encode())
+}
+
 // listTaskLogFiles list logs files paths under given sandbox directory.
 func listTaskLogFiles(client *http.Client, fileURL string) ([]string, error) {
 
@@ -128,3 +214,30 @@ func listTaskLogFiles(client *http.Client, fileURL string) ([]string, error) {
 	}
 	return result, nil
 }
+
+// readTaskLogFile reads a chunk of a file using the mesos agent files API.
+func readTaskLogFile(client *http.Client, fileURL string) (*SandboxFileChunk, error) {
+	resp, err := client.Get(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrFileNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP GET failed for %s: %v", fileURL, resp)
+	}
+
+	var slaveResp fileData
+	if err = json.NewDecoder(resp.Body).Decode(&slaveResp); err != nil {
+		return nil,
+			fmt.Errorf("Failed to decode response for %s: %v", fileURL, resp)
+	}
+
+	return &SandboxFileChunk{
+		Data:   []byte(slaveResp.Data),
+		Offset: slaveResp.Offset,
+	}, nil
+}
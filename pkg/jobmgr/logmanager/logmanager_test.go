@@ -15,12 +15,18 @@
 package logmanager
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/uber/peloton/pkg/common"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
 )
@@ -110,6 +116,133 @@ func (suite *LogManagerTestSuite) TestGetSlaveFileBrowseEndpointURL() {
 		sandboxDir)
 }
 
+func (suite *LogManagerTestSuite) TestGetSlaveFileReadEndpointURL() {
+	readURL := getSlaveFileReadEndpointURL(
+		_testMesosWorkDir, _testFrameworkID, _testHostname, _testPort,
+		_testAgentID, _testTaskID, "stdout", 10, 20)
+	suite.Equal(
+		"http://test-hostname:31002/files/read?length=20&offset=10&path="+
+			url.QueryEscape("/var/lib/mesos/agent/slaves/test-agent-id/frameworks"+
+				"/test-framework-id/executors/test-task-id/runs/latest/stdout"),
+		readURL)
+}
+
+func (suite *LogManagerTestSuite) TestReadSandboxFile() {
+	ts := httptest.NewServer(slaveFilesMux(map[string]string{
+		sandboxFilePath(_testTaskID, "stdout"): "hello world",
+	}))
+	defer ts.Close()
+	host, port := suite.hostPort(ts)
+
+	lm := NewLogManager(&http.Client{Timeout: 10 * time.Second})
+
+	chunk, err := lm.ReadSandboxFile(_testMesosWorkDir, _testFrameworkID,
+		host, port, _testAgentID, _testTaskID, "stdout", 6, 100)
+	suite.NoError(err)
+	suite.Equal("world", string(chunk.Data))
+	suite.Equal(int64(6), chunk.Offset)
+
+	chunk, err = lm.ReadSandboxFile(_testMesosWorkDir, _testFrameworkID,
+		host, port, _testAgentID, _testTaskID, "stdout", 0, 5)
+	suite.NoError(err)
+	suite.Equal("hello", string(chunk.Data))
+
+	// offset -1 returns the size of the file
+	chunk, err = lm.ReadSandboxFile(_testMesosWorkDir, _testFrameworkID,
+		host, port, _testAgentID, _testTaskID, "stdout", -1, 0)
+	suite.NoError(err)
+	suite.Empty(chunk.Data)
+	suite.Equal(int64(11), chunk.Offset)
+}
+
+func (suite *LogManagerTestSuite) TestReadSandboxFileThermos() {
+	thermosTaskID := common.PelotonAuroraBridgeExecutorIDPrefix + _testTaskID
+	ts := httptest.NewServer(slaveFilesMux(map[string]string{
+		sandboxFilePath(thermosTaskID, "stderr"): "error",
+	}))
+	defer ts.Close()
+	host, port := suite.hostPort(ts)
+
+	lm := NewLogManager(&http.Client{Timeout: 10 * time.Second})
+	chunk, err := lm.ReadSandboxFile(_testMesosWorkDir, _testFrameworkID,
+		host, port, _testAgentID, _testTaskID, "stderr", 0, 100)
+	suite.NoError(err)
+	suite.Equal("error", string(chunk.Data))
+}
+
+func (suite *LogManagerTestSuite) TestReadSandboxFileNotFound() {
+	ts := httptest.NewServer(slaveFilesMux(map[string]string{}))
+	defer ts.Close()
+	host, port := suite.hostPort(ts)
+
+	lm := NewLogManager(&http.Client{Timeout: 10 * time.Second})
+	_, err := lm.ReadSandboxFile(_testMesosWorkDir, _testFrameworkID,
+		host, port, _testAgentID, _testTaskID, "stdout", 0, 100)
+	suite.Equal(ErrFileNotFound, err)
+}
+
+func (suite *LogManagerTestSuite) TestReadTaskLogFileFailure() {
+	ts := httptest.NewServer(slaveMux())
+	defer ts.Close()
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	_, err := readTaskLogFile(client, "UnexistFile")
+	suite.Error(err)
+
+	_, err = readTaskLogFile(client, ts.URL+"/failed")
+	suite.Error(err)
+
+	_, err = readTaskLogFile(client, ts.URL+"/nonjson")
+	suite.Error(err)
+}
+
+func (suite *LogManagerTestSuite) hostPort(ts *httptest.Server) (string, string) {
+	u, err := url.Parse(ts.URL)
+	suite.NoError(err)
+	host, port, err := net.SplitHostPort(u.Host)
+	suite.NoError(err)
+	return host, port
+}
+
+func sandboxFilePath(taskID, filename string) string {
+	return fmt.Sprintf(_slaveSandboxDir, _testMesosWorkDir, _testAgentID,
+		_testFrameworkID, taskID) + "/" + filename
+}
+
+// slaveFilesMux is a stand-in for the mesos agent /files/read endpoint
+// serving the given files.
+func slaveFilesMux(files map[string]string) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/files/read", func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Query().Get("path")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		offset, _ := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+		length, _ := strconv.ParseInt(r.URL.Query().Get("length"), 10, 64)
+
+		resp := fileData{}
+		if offset < 0 {
+			resp.Offset = int64(len(content))
+		} else {
+			resp.Offset = offset
+			if offset < int64(len(content)) {
+				end := offset + length
+				if end > int64(len(content)) {
+					end = int64(len(content))
+				}
+				resp.Data = content[offset:end]
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	return mux
+}
+
 var (
 	_slaveFileBrowseStr = `[{"path": "/var/lib/path1"}, {"path": "/var/lib/path2"}]`
 	_NonJSONResponse    = `error`
@@ -16,6 +16,8 @@ package podsvc
 
 import (
 	"context"
+	"path"
+	"strings"
 	"time"
 
 	mesos "github.com/uber/peloton/.gen/mesos/v1"
@@ -49,6 +51,18 @@ import (
 
 const (
 	_frameworkName = "Peloton"
+
+	_defaultMesosAgentPort = "5051"
+
+	// _defaultPodLogsFilename is the sandbox file read by GetPodLogs
+	// if no filename is given
+	_defaultPodLogsFilename = "stdout"
+	// _podLogsChunkSize is the maximum number of bytes read from the
+	// agent and sent to the client at a time
+	_podLogsChunkSize = int64(64 * 1024)
+	// _podLogsPollInterval is the interval at which the file is polled
+	// for new data when following pod logs
+	_podLogsPollInterval = time.Second
 )
 
 type serviceHandler struct {
@@ -545,25 +559,7 @@ func (h *serviceHandler) BrowsePodSandbox(
 		return nil, err
 	}
 
-	// Extract the IP address + port of the agent, if possible,
-	// because the hostname may not be resolvable on the network
-	agentIP := hostname
-	agentPort := "5051"
-	agentResponse, err := h.hostMgrClient.GetMesosAgentInfo(ctx,
-		&hostsvc.GetMesosAgentInfoRequest{Hostname: hostname})
-	if err == nil && len(agentResponse.Agents) > 0 {
-		ip, port, err := util.ExtractIPAndPortFromMesosAgentPID(
-			agentResponse.Agents[0].GetPid())
-		if err == nil {
-			agentIP = ip
-			if port != "" {
-				agentPort = port
-			}
-		}
-	} else {
-		log.WithField("hostname", hostname).
-			Info("Could not get Mesos agent info")
-	}
+	agentIP, agentPort := h.getAgentAddress(ctx, hostname)
 
 	var logPaths []string
 	logPaths, err = h.logManager.ListSandboxFilesPaths(
@@ -597,6 +593,140 @@ func (h *serviceHandler) BrowsePodSandbox(
 	return resp, nil
 }
 
+func (h *serviceHandler) GetPodLogs(
+	req *svc.GetPodLogsRequest,
+	stream svc.PodServiceServiceGetPodLogsYARPCServer,
+) (err error) {
+	ctx := stream.Context()
+	defer func() {
+		headers := yarpcutil.GetHeaders(ctx)
+		if err != nil {
+			log.WithField("request", req).
+				WithField("headers", headers).
+				WithError(err).
+				Warn("PodSVC.GetPodLogs failed")
+			err = yarpcutil.ConvertToYARPCError(err)
+			return
+		}
+
+		log.WithField("request", req).
+			WithField("headers", headers).
+			Debug("PodSVC.GetPodLogs succeeded")
+	}()
+
+	filename, err := getPodLogsFilename(req.GetFilename())
+	if err != nil {
+		return err
+	}
+
+	if req.GetLimitBytes() < 0 {
+		return yarpcerrors.InvalidArgumentErrorf("limit_bytes cannot be negative")
+	}
+
+	jobID, instanceID, err := util.ParseTaskID(req.GetPodName().GetValue())
+	if err != nil {
+		return err
+	}
+
+	hostname, agentID, podID, frameworkID, err :=
+		h.getSandboxPathInfo(
+			ctx,
+			jobID,
+			instanceID,
+			req.GetPodId().GetValue(),
+		)
+	if err != nil {
+		return err
+	}
+
+	agentIP, agentPort := h.getAgentAddress(ctx, hostname)
+
+	read := func(offset, length int64) (*logmanager.SandboxFileChunk, error) {
+		chunk, err := h.logManager.ReadSandboxFile(
+			h.mesosAgentWorkDir,
+			frameworkID,
+			agentIP,
+			agentPort,
+			agentID,
+			podID,
+			filename,
+			offset,
+			length,
+		)
+		if err == logmanager.ErrFileNotFound {
+			return nil, yarpcerrors.NotFoundErrorf(
+				"file %s not found in pod sandbox", filename)
+		}
+		return chunk, err
+	}
+
+	offset := req.GetOffset()
+	if offset < 0 {
+		// offset is relative to the end of the file
+		chunk, err := read(-1, 0)
+		if err != nil {
+			return err
+		}
+		offset += chunk.Offset
+		if offset < 0 {
+			offset = 0
+		}
+	}
+
+	remaining := req.GetLimitBytes()
+	podDone := false
+	for {
+		length := _podLogsChunkSize
+		if req.GetLimitBytes() > 0 && remaining < length {
+			length = remaining
+		}
+
+		chunk, err := read(offset, length)
+		if err != nil {
+			return err
+		}
+
+		if len(chunk.Data) > 0 {
+			if err := stream.Send(&svc.GetPodLogsResponse{
+				Data:   chunk.Data,
+				Offset: chunk.Offset,
+			}); err != nil {
+				return err
+			}
+
+			offset = chunk.Offset + int64(len(chunk.Data))
+			if req.GetLimitBytes() > 0 {
+				remaining -= int64(len(chunk.Data))
+				if remaining <= 0 {
+					return nil
+				}
+			}
+			continue
+		}
+
+		// Reached the end of the file. When following, stop only after
+		// the file has been read to the end once more after the pod run
+		// terminated, so that no output written on exit is lost.
+		if !req.GetFollow() || podDone {
+			return nil
+		}
+
+		podDone, err = h.isPodRunTerminal(ctx, jobID, instanceID, podID)
+		if err != nil {
+			return err
+		}
+		if podDone {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(_podLogsPollInterval):
+		}
+	}
+}
+
 func (h *serviceHandler) RefreshPod(
 	ctx context.Context,
 	req *svc.RefreshPodRequest,
@@ -772,6 +902,72 @@ func (h *serviceHandler) getHostInfo(
 	return hostname, podid, agentID, nil
 }
 
+// getAgentAddress returns the IP address and port of the agent, if possible,
+// because the hostname may not be resolvable on the network.
+func (h *serviceHandler) getAgentAddress(
+	ctx context.Context,
+	hostname string,
+) (agentIP, agentPort string) {
+	agentIP = hostname
+	agentPort = _defaultMesosAgentPort
+	agentResponse, err := h.hostMgrClient.GetMesosAgentInfo(ctx,
+		&hostsvc.GetMesosAgentInfoRequest{Hostname: hostname})
+	if err == nil && len(agentResponse.Agents) > 0 {
+		ip, port, err := util.ExtractIPAndPortFromMesosAgentPID(
+			agentResponse.Agents[0].GetPid())
+		if err == nil {
+			agentIP = ip
+			if port != "" {
+				agentPort = port
+			}
+		}
+	} else {
+		log.WithField("hostname", hostname).
+			Info("Could not get Mesos agent info")
+	}
+	return agentIP, agentPort
+}
+
+// isPodRunTerminal returns whether the given run of a pod has terminated.
+func (h *serviceHandler) isPodRunTerminal(
+	ctx context.Context,
+	jobID string,
+	instanceID uint32,
+	podID string,
+) (bool, error) {
+	runtime, err := h.podStore.GetTaskRuntime(
+		ctx,
+		&v0peloton.JobID{Value: jobID},
+		instanceID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	// a newer run of the pod has been created
+	if runtime.GetMesosTaskId().GetValue() != podID {
+		return true, nil
+	}
+	return util.IsPelotonStateTerminal(runtime.GetState()), nil
+}
+
+// getPodLogsFilename validates the sandbox file to read, which must
+// not escape the sandbox directory.
+func getPodLogsFilename(filename string) (string, error) {
+	if len(filename) == 0 {
+		return _defaultPodLogsFilename, nil
+	}
+
+	cleaned := path.Clean(filename)
+	if path.IsAbs(cleaned) ||
+		cleaned == ".." ||
+		strings.HasPrefix(cleaned, "../") {
+		return "", yarpcerrors.InvalidArgumentErrorf(
+			"filename %s is outside of the pod sandbox", filename)
+	}
+	return cleaned, nil
+}
+
 // getSandboxPathInfo - return details such as hostname, agentID,
 // frameworkID and podName to create sandbox path.
 func (h *serviceHandler) getSandboxPathInfo(ctx context.Context,
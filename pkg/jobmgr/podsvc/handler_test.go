@@ -28,6 +28,7 @@ import (
 	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
 	"github.com/uber/peloton/.gen/peloton/api/v1alpha/pod"
 	"github.com/uber/peloton/.gen/peloton/api/v1alpha/pod/svc"
+	svcmocks "github.com/uber/peloton/.gen/peloton/api/v1alpha/pod/svc/mocks"
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 	hostmocks "github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc/mocks"
 	"github.com/uber/peloton/.gen/peloton/private/models"
@@ -37,6 +38,7 @@ import (
 	cachedmocks "github.com/uber/peloton/pkg/jobmgr/cached/mocks"
 	jobmgrcommon "github.com/uber/peloton/pkg/jobmgr/common"
 	goalstatemocks "github.com/uber/peloton/pkg/jobmgr/goalstate/mocks"
+	"github.com/uber/peloton/pkg/jobmgr/logmanager"
 	logmanagermocks "github.com/uber/peloton/pkg/jobmgr/logmanager/mocks"
 	handlerutil "github.com/uber/peloton/pkg/jobmgr/util/handler"
 	storemocks "github.com/uber/peloton/pkg/storage/mocks"
@@ -1908,6 +1910,173 @@ func (suite *podHandlerTestSuite) TestBrowsePodSandboxGetMesosMasterHostPortFail
 	suite.Error(err)
 }
 
+// setupGetPodLogs sets up the expectations to locate the sandbox of
+// the pod for GetPodLogs, and returns the stream to send the logs to
+func (suite *podHandlerTestSuite) setupGetPodLogs() *svcmocks.MockPodServiceServiceGetPodLogsYARPCServer {
+	mesosTaskID := testPodID
+	agentPID := "slave(1)@1.2.3.4:9090"
+	events := []*pbtask.PodEvent{
+		{
+			TaskId: &mesos.TaskID{
+				Value: &mesosTaskID,
+			},
+			ActualState: pbtask.TaskState_RUNNING.String(),
+			Hostname:    "hostname",
+			AgentID:     "agentID",
+		},
+	}
+
+	suite.podStore.EXPECT().
+		GetPodEvents(gomock.Any(), testJobID, uint32(testInstanceID), "").
+		Return(events, nil)
+	suite.frameworkInfoStore.EXPECT().
+		GetFrameworkID(gomock.Any(), _frameworkName).
+		Return("testFramework", nil)
+	suite.hostmgrClient.EXPECT().
+		GetMesosAgentInfo(
+			gomock.Any(),
+			&hostsvc.GetMesosAgentInfoRequest{Hostname: "hostname"},
+		).Return(&hostsvc.GetMesosAgentInfoResponse{
+		Agents: []*mesosmaster.Response_GetAgents_Agent{{Pid: &agentPID}},
+	}, nil)
+
+	stream := svcmocks.NewMockPodServiceServiceGetPodLogsYARPCServer(suite.ctrl)
+	stream.EXPECT().Context().Return(context.Background()).AnyTimes()
+	return stream
+}
+
+// expectReadSandboxFile sets up the expectation to read the given
+// chunk of the stdout file of the pod
+func (suite *podHandlerTestSuite) expectReadSandboxFile(
+	offset, length int64,
+	chunk *logmanager.SandboxFileChunk,
+	err error,
+) *gomock.Call {
+	return suite.logmanager.EXPECT().
+		ReadSandboxFile(
+			suite.mesosAgentWorkDir,
+			"testFramework",
+			"1.2.3.4",
+			"9090",
+			"agentID",
+			testPodID,
+			"stdout",
+			offset,
+			length,
+		).Return(chunk, err)
+}
+
+// TestGetPodLogsSuccess tests reading pod logs till the end of the file
+func (suite *podHandlerTestSuite) TestGetPodLogsSuccess() {
+	stream := suite.setupGetPodLogs()
+
+	gomock.InOrder(
+		suite.expectReadSandboxFile(0, _podLogsChunkSize,
+			&logmanager.SandboxFileChunk{Data: []byte("hello"), Offset: 0}, nil),
+		stream.EXPECT().Send(&svc.GetPodLogsResponse{
+			Data:   []byte("hello"),
+			Offset: 0,
+		}).Return(nil),
+		suite.expectReadSandboxFile(5, _podLogsChunkSize,
+			&logmanager.SandboxFileChunk{Offset: 5}, nil),
+	)
+
+	suite.NoError(suite.handler.GetPodLogs(&svc.GetPodLogsRequest{
+		PodName: &v1alphapeloton.PodName{Value: testPodName},
+	}, stream))
+}
+
+// TestGetPodLogsTailWithLimit tests reading the end of the pod logs
+// with a byte limit
+func (suite *podHandlerTestSuite) TestGetPodLogsTailWithLimit() {
+	stream := suite.setupGetPodLogs()
+
+	gomock.InOrder(
+		suite.expectReadSandboxFile(-1, 0,
+			&logmanager.SandboxFileChunk{Offset: 10}, nil),
+		suite.expectReadSandboxFile(7, 2,
+			&logmanager.SandboxFileChunk{Data: []byte("lo"), Offset: 7}, nil),
+		stream.EXPECT().Send(&svc.GetPodLogsResponse{
+			Data:   []byte("lo"),
+			Offset: 7,
+		}).Return(nil),
+	)
+
+	suite.NoError(suite.handler.GetPodLogs(&svc.GetPodLogsRequest{
+		PodName:    &v1alphapeloton.PodName{Value: testPodName},
+		Offset:     -3,
+		LimitBytes: 2,
+	}, stream))
+}
+
+// TestGetPodLogsFollow tests following pod logs until the pod terminates
+func (suite *podHandlerTestSuite) TestGetPodLogsFollow() {
+	stream := suite.setupGetPodLogs()
+	mesosTaskID := testPodID
+
+	gomock.InOrder(
+		suite.expectReadSandboxFile(0, _podLogsChunkSize,
+			&logmanager.SandboxFileChunk{Offset: 0}, nil),
+		suite.podStore.EXPECT().
+			GetTaskRuntime(gomock.Any(), &peloton.JobID{Value: testJobID}, uint32(testInstanceID)).
+			Return(&pbtask.RuntimeInfo{
+				MesosTaskId: &mesos.TaskID{Value: &mesosTaskID},
+				State:       pbtask.TaskState_SUCCEEDED,
+			}, nil),
+		suite.expectReadSandboxFile(0, _podLogsChunkSize,
+			&logmanager.SandboxFileChunk{Data: []byte("bye"), Offset: 0}, nil),
+		stream.EXPECT().Send(&svc.GetPodLogsResponse{
+			Data:   []byte("bye"),
+			Offset: 0,
+		}).Return(nil),
+		suite.expectReadSandboxFile(3, _podLogsChunkSize,
+			&logmanager.SandboxFileChunk{Offset: 3}, nil),
+	)
+
+	suite.NoError(suite.handler.GetPodLogs(&svc.GetPodLogsRequest{
+		PodName: &v1alphapeloton.PodName{Value: testPodName},
+		Follow:  true,
+	}, stream))
+}
+
+// TestGetPodLogsFileNotFound tests GetPodLogs failure when
+// the file is not in the sandbox
+func (suite *podHandlerTestSuite) TestGetPodLogsFileNotFound() {
+	stream := suite.setupGetPodLogs()
+	suite.expectReadSandboxFile(0, _podLogsChunkSize, nil, logmanager.ErrFileNotFound)
+
+	err := suite.handler.GetPodLogs(&svc.GetPodLogsRequest{
+		PodName: &v1alphapeloton.PodName{Value: testPodName},
+	}, stream)
+	suite.True(yarpcerrors.IsNotFound(err))
+}
+
+// TestGetPodLogsInvalidRequest tests GetPodLogs failure due to
+// invalid request
+func (suite *podHandlerTestSuite) TestGetPodLogsInvalidRequest() {
+	stream := svcmocks.NewMockPodServiceServiceGetPodLogsYARPCServer(suite.ctrl)
+	stream.EXPECT().Context().Return(context.Background()).AnyTimes()
+
+	for _, filename := range []string{"/etc/passwd", "../../../etc/passwd", "a/../../b"} {
+		err := suite.handler.GetPodLogs(&svc.GetPodLogsRequest{
+			PodName:  &v1alphapeloton.PodName{Value: testPodName},
+			Filename: filename,
+		}, stream)
+		suite.True(yarpcerrors.IsInvalidArgument(err), filename)
+	}
+
+	err := suite.handler.GetPodLogs(&svc.GetPodLogsRequest{
+		PodName:    &v1alphapeloton.PodName{Value: testPodName},
+		LimitBytes: -1,
+	}, stream)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+
+	err = suite.handler.GetPodLogs(&svc.GetPodLogsRequest{
+		PodName: &v1alphapeloton.PodName{Value: "InvalidPodName"},
+	}, stream)
+	suite.Error(err)
+}
+
 // TestDeletePodEventsSuccess tests the success case of deleting pod events
 func (suite *podHandlerTestSuite) TestDeletePodEventsSuccess() {
 	request := &svc.DeletePodEventsRequest{
@@ -0,0 +1,46 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disruption
+
+import (
+	"github.com/uber-go/tally"
+)
+
+// Metrics is the struct containing all the counters and timers that track
+// the disruptions of the instances of jobs.
+type Metrics struct {
+	DisruptionRecord     tally.Counter
+	DisruptionRecordFail tally.Counter
+	DurationRecord       tally.Counter
+	DurationRecordFail   tally.Counter
+	DisruptionDuration   tally.Timer
+	PendingDisruptions   tally.Gauge
+}
+
+// NewMetrics returns a new Metrics struct, with all metrics
+// initialized and rooted at the given tally.Scope
+func NewMetrics(scope tally.Scope) *Metrics {
+	successScope := scope.Tagged(map[string]string{"result": "success"})
+	failScope := scope.Tagged(map[string]string{"result": "fail"})
+
+	return &Metrics{
+		DisruptionRecord:     successScope.Counter("record_disruption"),
+		DisruptionRecordFail: failScope.Counter("record_disruption"),
+		DurationRecord:       successScope.Counter("record_duration"),
+		DurationRecordFail:   failScope.Counter("record_duration"),
+		DisruptionDuration:   scope.Timer("disruption_duration"),
+		PendingDisruptions:   scope.Gauge("pending_disruptions"),
+	}
+}
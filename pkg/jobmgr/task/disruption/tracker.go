@@ -0,0 +1,405 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disruption
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+
+	"github.com/uber/peloton/pkg/common/util"
+	"github.com/uber/peloton/pkg/jobmgr/cached"
+	ormobjects "github.com/uber/peloton/pkg/storage/objects"
+
+	"github.com/gocql/gocql"
+	log "github.com/sirupsen/logrus"
+	"github.com/uber-go/tally"
+	"go.uber.org/multierr"
+)
+
+// _maxPendingDuration is the time after which a disruption whose instance
+// has not run again is no longer tracked, e.g. if the instance was removed
+// from the job by an update. The disruption has expired in db by then.
+const _maxPendingDuration = ormobjects.JobDisruptionTTL
+
+// _listenerName is the name of the tracker as a listener of the cache
+const _listenerName = "DisruptionTracker"
+
+// _resolveTimeout is the timeout to record the durations of the pending
+// disruptions of instances which are not going to run again
+const _resolveTimeout = 10 * time.Second
+
+// Declare a Now function so that we can mock it in unit tests.
+var now = time.Now
+
+// disruptionReasons are the termination reasons of the instances which
+// are counted as disruptions by Peloton. Kills requested by the user and
+// failures of the instances are not disruptions.
+var disruptionReasons = map[task.TerminationStatus_Reason]bool{
+	task.TerminationStatus_TERMINATION_STATUS_REASON_KILLED_HOST_MAINTENANCE:   true,
+	task.TerminationStatus_TERMINATION_STATUS_REASON_PREEMPTED_RESOURCES:       true,
+	task.TerminationStatus_TERMINATION_STATUS_REASON_DEADLINE_TIMEOUT_EXCEEDED: true,
+	task.TerminationStatus_TERMINATION_STATUS_REASON_KILLED_FOR_UPDATE:         true,
+	task.TerminationStatus_TERMINATION_STATUS_REASON_KILLED_FOR_RESTART:        true,
+}
+
+// IsDisruption returns true if an instance terminated for the reason
+// is disrupted by Peloton.
+func IsDisruption(reason task.TerminationStatus_Reason) bool {
+	return disruptionReasons[reason]
+}
+
+// Tracker records the disruptions of the instances of jobs, and the time
+// the disrupted instances take to run again. As a listener of the cache,
+// it resolves the pending disruptions of the instances which are killed or
+// removed, and of the jobs which terminate or are deleted, since those
+// instances are not going to run again.
+type Tracker interface {
+	cached.JobTaskListener
+
+	// Record records the transition of the runtime of an instance from
+	// prev to next. A disruption is recorded when the instance terminates
+	// for a disruption reason, and its duration is recorded when the
+	// instance runs again.
+	Record(
+		ctx context.Context,
+		jobID *peloton.JobID,
+		instanceID uint32,
+		prev *task.RuntimeInfo,
+		next *task.RuntimeInfo,
+	) error
+}
+
+// pendingDisruption is a disruption whose instance is not running again yet
+type pendingDisruption struct {
+	// key of the disruption in db
+	key gocql.UUID
+	// time at which the instance was disrupted
+	start time.Time
+}
+
+// tracker implements Tracker. The disruptions whose instances are not
+// running again yet are persisted, and are loaded from db the first time
+// an instance of their job runs after the tracker is created, so that their
+// durations are recorded across job manager failovers.
+type tracker struct {
+	sync.Mutex
+
+	// pending disruptions by task ID
+	pending map[string][]*pendingDisruption
+	// jobs whose pending disruptions are loaded from db
+	loadedJobs       map[string]bool
+	jobDisruptionOps ormobjects.JobDisruptionOps
+	metrics          *Metrics
+}
+
+// NewTracker creates a new disruption Tracker
+func NewTracker(
+	ormStore *ormobjects.Store,
+	parentScope tally.Scope,
+) Tracker {
+	return &tracker{
+		pending:          make(map[string][]*pendingDisruption),
+		loadedJobs:       make(map[string]bool),
+		jobDisruptionOps: ormobjects.NewJobDisruptionOps(ormStore),
+		metrics:          NewMetrics(parentScope.SubScope("disruption")),
+	}
+}
+
+// Record records the disruption of an instance and its duration
+func (t *tracker) Record(
+	ctx context.Context,
+	jobID *peloton.JobID,
+	instanceID uint32,
+	prev *task.RuntimeInfo,
+	next *task.RuntimeInfo,
+) error {
+	taskID := util.CreatePelotonTaskID(jobID.GetValue(), instanceID)
+
+	if next.GetState() == task.TaskState_RUNNING &&
+		prev.GetState() != task.TaskState_RUNNING {
+		return t.recordDuration(ctx, jobID, taskID)
+	}
+
+	if !util.IsPelotonStateTerminal(next.GetState()) ||
+		util.IsPelotonStateTerminal(prev.GetState()) {
+		return nil
+	}
+
+	reason := next.GetTerminationStatus().GetReason()
+	if !IsDisruption(reason) {
+		return nil
+	}
+
+	disruptionTime := now()
+	key, err := t.jobDisruptionOps.Create(
+		ctx,
+		jobID.GetValue(),
+		instanceID,
+		reason.String(),
+		disruptionTime,
+	)
+	if err != nil {
+		t.metrics.DisruptionRecordFail.Inc(1)
+		return err
+	}
+	t.metrics.DisruptionRecord.Inc(1)
+
+	if isRemoved(next) {
+		// The instance was killed or removed, and the cache listeners are
+		// invoked by the runtime write before the disruption is recorded,
+		// so it is resolved right away as the instance won't run again.
+		return t.updateDurations(
+			ctx,
+			jobID.GetValue(),
+			[]*pendingDisruption{{key: key, start: disruptionTime}},
+			disruptionTime,
+		)
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	t.prunePending(disruptionTime)
+	t.pending[taskID] = append(t.pending[taskID], &pendingDisruption{
+		key:   key,
+		start: disruptionTime,
+	})
+	t.updatePendingMetric()
+	return nil
+}
+
+// recordDuration records the time taken by a disrupted instance to run
+// again for each of its pending disruptions
+func (t *tracker) recordDuration(
+	ctx context.Context,
+	jobID *peloton.JobID,
+	taskID string,
+) error {
+	if err := t.loadPending(ctx, jobID); err != nil {
+		t.metrics.DurationRecordFail.Inc(1)
+		return err
+	}
+
+	t.Lock()
+	pending := t.pending[taskID]
+	delete(t.pending, taskID)
+	t.updatePendingMetric()
+	t.Unlock()
+
+	return t.updateDurations(ctx, jobID.GetValue(), pending, now())
+}
+
+// updateDurations records the durations of the pending disruptions of an
+// instance of a job until the current time
+func (t *tracker) updateDurations(
+	ctx context.Context,
+	jobID string,
+	pending []*pendingDisruption,
+	current time.Time,
+) error {
+	var errs error
+	for _, p := range pending {
+		duration := current.Sub(p.start)
+		if err := t.jobDisruptionOps.UpdateDuration(
+			ctx,
+			jobID,
+			p.key,
+			duration,
+		); err != nil {
+			t.metrics.DurationRecordFail.Inc(1)
+			errs = multierr.Append(errs, err)
+			continue
+		}
+
+		t.metrics.DurationRecord.Inc(1)
+		t.metrics.DisruptionDuration.Record(duration)
+	}
+	return errs
+}
+
+// Name returns the name of the tracker as a listener of the cache
+func (t *tracker) Name() string {
+	return _listenerName
+}
+
+// JobRuntimeChanged resolves the pending disruptions of a job once it is
+// terminal or being deleted, and stops tracking the job.
+func (t *tracker) JobRuntimeChanged(
+	jobID *peloton.JobID,
+	jobType job.JobType,
+	runtime *job.RuntimeInfo,
+) {
+	if !util.IsPelotonJobStateTerminal(runtime.GetState()) &&
+		runtime.GetGoalState() != job.JobState_DELETED {
+		return
+	}
+
+	prefix := jobID.GetValue() + "-"
+	var pending []*pendingDisruption
+	t.Lock()
+	for taskID, p := range t.pending {
+		if strings.HasPrefix(taskID, prefix) {
+			pending = append(pending, p...)
+			delete(t.pending, taskID)
+		}
+	}
+	delete(t.loadedJobs, jobID.GetValue())
+	t.updatePendingMetric()
+	t.Unlock()
+
+	t.resolve(jobID.GetValue(), pending)
+}
+
+// TaskRuntimeChanged resolves the pending disruptions of an instance once
+// it is terminated after being killed or removed.
+func (t *tracker) TaskRuntimeChanged(
+	jobID *peloton.JobID,
+	instanceID uint32,
+	jobType job.JobType,
+	runtime *task.RuntimeInfo,
+	labels []*peloton.Label,
+) {
+	if !isRemoved(runtime) {
+		return
+	}
+
+	taskID := util.CreatePelotonTaskID(jobID.GetValue(), instanceID)
+	t.Lock()
+	pending := t.pending[taskID]
+	delete(t.pending, taskID)
+	t.updatePendingMetric()
+	t.Unlock()
+
+	t.resolve(jobID.GetValue(), pending)
+}
+
+// isRemoved returns true if the instance is terminated after being killed
+// or removed, so it is not going to run again.
+func isRemoved(runtime *task.RuntimeInfo) bool {
+	return util.IsPelotonStateTerminal(runtime.GetState()) &&
+		(runtime.GetGoalState() == task.TaskState_KILLED ||
+			runtime.GetGoalState() == task.TaskState_DELETED)
+}
+
+// resolve records the durations of the pending disruptions of instances
+// which are not going to run again, until now. The listeners are invoked
+// with the cache locked, so the durations are recorded asynchronously.
+func (t *tracker) resolve(jobID string, pending []*pendingDisruption) {
+	if len(pending) == 0 {
+		return
+	}
+
+	current := now()
+	go func() {
+		ctx, cancel := context.WithTimeout(
+			context.Background(), _resolveTimeout)
+		defer cancel()
+
+		if err := t.updateDurations(ctx, jobID, pending, current); err != nil {
+			log.WithField("job_id", jobID).
+				WithError(err).
+				Warn("failed to resolve disruptions of instances not running again")
+		}
+	}()
+}
+
+// loadPending loads the pending disruptions of a job from db, unless they
+// were loaded already.
+func (t *tracker) loadPending(ctx context.Context, jobID *peloton.JobID) error {
+	t.Lock()
+	loaded := t.loadedJobs[jobID.GetValue()]
+	t.Unlock()
+	if loaded {
+		return nil
+	}
+
+	disruptions, err := t.jobDisruptionOps.GetAll(
+		ctx, jobID.GetValue(), now().Add(-_maxPendingDuration))
+	if err != nil {
+		return err
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	if t.loadedJobs[jobID.GetValue()] {
+		return nil
+	}
+	for _, d := range disruptions {
+		if d.Resolved {
+			continue
+		}
+		taskID := util.CreatePelotonTaskID(jobID.GetValue(), d.InstanceID)
+		if t.isPending(taskID, d.Key) {
+			// recorded after the tracker was created
+			continue
+		}
+		t.pending[taskID] = append(t.pending[taskID], &pendingDisruption{
+			key:   d.Key,
+			start: d.Time,
+		})
+	}
+	t.loadedJobs[jobID.GetValue()] = true
+	t.updatePendingMetric()
+	return nil
+}
+
+// isPending returns true if the disruption of the task with the key is
+// tracked. The lock must be held by the caller.
+func (t *tracker) isPending(taskID string, key gocql.UUID) bool {
+	for _, p := range t.pending[taskID] {
+		if p.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// prunePending stops tracking the disruptions whose instances did not run
+// again for too long. The lock must be held by the caller.
+func (t *tracker) prunePending(current time.Time) {
+	for taskID, pending := range t.pending {
+		var recent []*pendingDisruption
+		for _, p := range pending {
+			if current.Sub(p.start) <= _maxPendingDuration {
+				recent = append(recent, p)
+			}
+		}
+		if len(recent) == len(pending) {
+			continue
+		}
+		log.WithField("task_id", taskID).
+			Debug("stop tracking disruption of task not running again")
+		if len(recent) == 0 {
+			delete(t.pending, taskID)
+		} else {
+			t.pending[taskID] = recent
+		}
+	}
+}
+
+// updatePendingMetric updates the number of pending disruptions. The lock
+// must be held by the caller.
+func (t *tracker) updatePendingMetric() {
+	count := 0
+	for _, pending := range t.pending {
+		count += len(pending)
+	}
+	t.metrics.PendingDisruptions.Update(float64(count))
+}
@@ -0,0 +1,459 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disruption
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+
+	ormobjects "github.com/uber/peloton/pkg/storage/objects"
+	objectmocks "github.com/uber/peloton/pkg/storage/objects/mocks"
+
+	"github.com/gocql/gocql"
+	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+)
+
+type TrackerTestSuite struct {
+	suite.Suite
+
+	ctrl                 *gomock.Controller
+	mockJobDisruptionOps *objectmocks.MockJobDisruptionOps
+	tracker              *tracker
+	jobID                *peloton.JobID
+	currentTime          time.Time
+}
+
+func TestTracker(t *testing.T) {
+	suite.Run(t, new(TrackerTestSuite))
+}
+
+func (suite *TrackerTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockJobDisruptionOps = objectmocks.NewMockJobDisruptionOps(suite.ctrl)
+	suite.tracker = &tracker{
+		pending:          make(map[string][]*pendingDisruption),
+		loadedJobs:       make(map[string]bool),
+		jobDisruptionOps: suite.mockJobDisruptionOps,
+		metrics:          NewMetrics(tally.NoopScope),
+	}
+	suite.jobID = &peloton.JobID{Value: uuid.New()}
+	suite.currentTime = time.Now()
+	now = func() time.Time { return suite.currentTime }
+}
+
+func (suite *TrackerTestSuite) TearDownTest() {
+	now = time.Now
+	suite.ctrl.Finish()
+}
+
+func terminatedRuntime(
+	state task.TaskState,
+	reason task.TerminationStatus_Reason,
+) *task.RuntimeInfo {
+	return &task.RuntimeInfo{
+		State:             state,
+		TerminationStatus: &task.TerminationStatus{Reason: reason},
+	}
+}
+
+// TestRecordDisruptionAndDuration tests recording a disruption of an
+// instance and the time it takes to run again
+func (suite *TrackerTestSuite) TestRecordDisruptionAndDuration() {
+	key := gocql.TimeUUID()
+	start := suite.currentTime
+
+	suite.mockJobDisruptionOps.EXPECT().
+		Create(
+			gomock.Any(),
+			suite.jobID.GetValue(),
+			uint32(1),
+			"TERMINATION_STATUS_REASON_KILLED_HOST_MAINTENANCE",
+			start).
+		Return(key, nil)
+	suite.NoError(suite.tracker.Record(
+		context.Background(),
+		suite.jobID,
+		1,
+		&task.RuntimeInfo{State: task.TaskState_RUNNING},
+		terminatedRuntime(
+			task.TaskState_KILLED,
+			task.TerminationStatus_TERMINATION_STATUS_REASON_KILLED_HOST_MAINTENANCE),
+	))
+	suite.Len(suite.tracker.pending, 1)
+
+	// instance is launched again
+	suite.NoError(suite.tracker.Record(
+		context.Background(),
+		suite.jobID,
+		1,
+		&task.RuntimeInfo{State: task.TaskState_INITIALIZED},
+		&task.RuntimeInfo{State: task.TaskState_LAUNCHED},
+	))
+	suite.Len(suite.tracker.pending, 1)
+
+	suite.currentTime = start.Add(5 * time.Minute)
+	// the disruption recorded by the tracker is not tracked twice
+	suite.mockJobDisruptionOps.EXPECT().
+		GetAll(
+			gomock.Any(),
+			suite.jobID.GetValue(),
+			suite.currentTime.Add(-_maxPendingDuration)).
+		Return([]*ormobjects.JobDisruption{
+			{Key: key, InstanceID: 1, Time: start},
+		}, nil)
+	suite.mockJobDisruptionOps.EXPECT().
+		UpdateDuration(gomock.Any(), suite.jobID.GetValue(), key, 5*time.Minute).
+		Return(nil)
+	suite.NoError(suite.tracker.Record(
+		context.Background(),
+		suite.jobID,
+		1,
+		&task.RuntimeInfo{State: task.TaskState_STARTING},
+		&task.RuntimeInfo{State: task.TaskState_RUNNING},
+	))
+	suite.Empty(suite.tracker.pending)
+
+	// running again without a pending disruption is a noop
+	suite.NoError(suite.tracker.Record(
+		context.Background(),
+		suite.jobID,
+		1,
+		&task.RuntimeInfo{State: task.TaskState_STARTING},
+		&task.RuntimeInfo{State: task.TaskState_RUNNING},
+	))
+}
+
+// TestRecordDisruptionOfRemovedInstance tests that the disruption of an
+// instance which is killed or removed is resolved when it is recorded
+func (suite *TrackerTestSuite) TestRecordDisruptionOfRemovedInstance() {
+	key := gocql.TimeUUID()
+	next := terminatedRuntime(
+		task.TaskState_KILLED,
+		task.TerminationStatus_TERMINATION_STATUS_REASON_KILLED_FOR_UPDATE)
+	next.GoalState = task.TaskState_DELETED
+
+	gomock.InOrder(
+		suite.mockJobDisruptionOps.EXPECT().
+			Create(
+				gomock.Any(),
+				suite.jobID.GetValue(),
+				uint32(1),
+				"TERMINATION_STATUS_REASON_KILLED_FOR_UPDATE",
+				suite.currentTime).
+			Return(key, nil),
+		suite.mockJobDisruptionOps.EXPECT().
+			UpdateDuration(
+				gomock.Any(), suite.jobID.GetValue(), key, time.Duration(0)).
+			Return(nil),
+	)
+	suite.NoError(suite.tracker.Record(
+		context.Background(),
+		suite.jobID,
+		1,
+		&task.RuntimeInfo{State: task.TaskState_RUNNING},
+		next,
+	))
+	suite.Empty(suite.tracker.pending)
+}
+
+// TestRecordNotDisruption tests that terminations which are not
+// disruptions by Peloton are not recorded
+func (suite *TrackerTestSuite) TestRecordNotDisruption() {
+	tests := []struct {
+		prev *task.RuntimeInfo
+		next *task.RuntimeInfo
+	}{
+		{
+			// killed on request
+			prev: &task.RuntimeInfo{State: task.TaskState_RUNNING},
+			next: terminatedRuntime(
+				task.TaskState_KILLED,
+				task.TerminationStatus_TERMINATION_STATUS_REASON_KILLED_ON_REQUEST),
+		},
+		{
+			// failed
+			prev: &task.RuntimeInfo{State: task.TaskState_RUNNING},
+			next: terminatedRuntime(
+				task.TaskState_FAILED,
+				task.TerminationStatus_TERMINATION_STATUS_REASON_FAILED),
+		},
+		{
+			// already terminal
+			prev: &task.RuntimeInfo{State: task.TaskState_KILLED},
+			next: terminatedRuntime(
+				task.TaskState_KILLED,
+				task.TerminationStatus_TERMINATION_STATUS_REASON_PREEMPTED_RESOURCES),
+		},
+		{
+			// kill issued, but not terminated yet
+			prev: &task.RuntimeInfo{State: task.TaskState_RUNNING},
+			next: terminatedRuntime(
+				task.TaskState_KILLING,
+				task.TerminationStatus_TERMINATION_STATUS_REASON_KILLED_FOR_UPDATE),
+		},
+	}
+
+	for _, tt := range tests {
+		suite.NoError(suite.tracker.Record(
+			context.Background(), suite.jobID, 0, tt.prev, tt.next))
+	}
+	suite.Empty(suite.tracker.pending)
+}
+
+// TestRecordErrors tests failures to persist disruptions
+func (suite *TrackerTestSuite) TestRecordErrors() {
+	suite.mockJobDisruptionOps.EXPECT().
+		Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(gocql.UUID{}, errors.New("test error"))
+	suite.Error(suite.tracker.Record(
+		context.Background(),
+		suite.jobID,
+		0,
+		&task.RuntimeInfo{State: task.TaskState_RUNNING},
+		terminatedRuntime(
+			task.TaskState_KILLED,
+			task.TerminationStatus_TERMINATION_STATUS_REASON_PREEMPTED_RESOURCES),
+	))
+	suite.Empty(suite.tracker.pending)
+
+	suite.mockJobDisruptionOps.EXPECT().
+		GetAll(
+			gomock.Any(),
+			suite.jobID.GetValue(),
+			suite.currentTime.Add(-_maxPendingDuration)).
+		Return(nil, errors.New("test error"))
+	suite.Error(suite.tracker.Record(
+		context.Background(),
+		suite.jobID,
+		0,
+		&task.RuntimeInfo{State: task.TaskState_STARTING},
+		&task.RuntimeInfo{State: task.TaskState_RUNNING},
+	))
+	suite.False(suite.tracker.loadedJobs[suite.jobID.GetValue()])
+
+	taskID := suite.jobID.GetValue() + "-0"
+	suite.tracker.loadedJobs[suite.jobID.GetValue()] = true
+	suite.tracker.pending[taskID] = []*pendingDisruption{{
+		key:   gocql.TimeUUID(),
+		start: suite.currentTime,
+	}}
+	suite.mockJobDisruptionOps.EXPECT().
+		UpdateDuration(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(errors.New("test error"))
+	suite.Error(suite.tracker.Record(
+		context.Background(),
+		suite.jobID,
+		0,
+		&task.RuntimeInfo{State: task.TaskState_STARTING},
+		&task.RuntimeInfo{State: task.TaskState_RUNNING},
+	))
+	suite.Empty(suite.tracker.pending)
+}
+
+// TestRecordDurationAfterFailover tests that the durations of the
+// disruptions recorded before the tracker was created are recorded from
+// their start time in db
+func (suite *TrackerTestSuite) TestRecordDurationAfterFailover() {
+	key1, key2 := gocql.TimeUUID(), gocql.TimeUUID()
+	suite.mockJobDisruptionOps.EXPECT().
+		GetAll(
+			gomock.Any(),
+			suite.jobID.GetValue(),
+			suite.currentTime.Add(-_maxPendingDuration)).
+		Return([]*ormobjects.JobDisruption{
+			{
+				Key:        key1,
+				InstanceID: 2,
+				Time:       suite.currentTime.Add(-10 * time.Minute),
+			},
+			{
+				Key:        key2,
+				InstanceID: 3,
+				Time:       suite.currentTime.Add(-2 * time.Hour),
+				Duration:   time.Minute,
+				Resolved:   true,
+			},
+		}, nil)
+	suite.mockJobDisruptionOps.EXPECT().
+		UpdateDuration(
+			gomock.Any(), suite.jobID.GetValue(), key1, 10*time.Minute).
+		Return(nil)
+	suite.NoError(suite.tracker.Record(
+		context.Background(),
+		suite.jobID,
+		2,
+		&task.RuntimeInfo{State: task.TaskState_STARTING},
+		&task.RuntimeInfo{State: task.TaskState_RUNNING},
+	))
+	suite.Empty(suite.tracker.pending)
+
+	// the pending disruptions of the job are loaded only once
+	suite.NoError(suite.tracker.Record(
+		context.Background(),
+		suite.jobID,
+		3,
+		&task.RuntimeInfo{State: task.TaskState_STARTING},
+		&task.RuntimeInfo{State: task.TaskState_RUNNING},
+	))
+}
+
+// TestPrunePending tests that stale pending disruptions are pruned
+func (suite *TrackerTestSuite) TestPrunePending() {
+	suite.tracker.pending["stale"] = []*pendingDisruption{{
+		start: suite.currentTime.Add(-2 * _maxPendingDuration),
+	}}
+	suite.tracker.pending["recent"] = []*pendingDisruption{
+		{start: suite.currentTime.Add(-2 * _maxPendingDuration)},
+		{start: suite.currentTime.Add(-time.Minute)},
+	}
+	suite.tracker.prunePending(suite.currentTime)
+	suite.Len(suite.tracker.pending, 1)
+	suite.Len(suite.tracker.pending["recent"], 1)
+}
+
+// TestTaskRuntimeChangedResolvesKilledInstance tests that the pending
+// disruptions of an instance are resolved once it is killed or removed
+func (suite *TrackerTestSuite) TestTaskRuntimeChangedResolvesKilledInstance() {
+	key1, key2 := gocql.TimeUUID(), gocql.TimeUUID()
+	suite.tracker.pending[suite.jobID.GetValue()+"-1"] = []*pendingDisruption{{
+		key:   key1,
+		start: suite.currentTime.Add(-time.Minute),
+	}}
+	suite.tracker.pending[suite.jobID.GetValue()+"-2"] = []*pendingDisruption{{
+		key:   key2,
+		start: suite.currentTime.Add(-2 * time.Minute),
+	}}
+
+	// instance is restarted after the disruption
+	suite.tracker.TaskRuntimeChanged(
+		suite.jobID,
+		1,
+		job.JobType_BATCH,
+		&task.RuntimeInfo{
+			State:     task.TaskState_KILLED,
+			GoalState: task.TaskState_SUCCEEDED,
+		},
+		nil,
+	)
+	// instance is not terminated yet
+	suite.tracker.TaskRuntimeChanged(
+		suite.jobID,
+		1,
+		job.JobType_BATCH,
+		&task.RuntimeInfo{
+			State:     task.TaskState_KILLING,
+			GoalState: task.TaskState_KILLED,
+		},
+		nil,
+	)
+	suite.Len(suite.tracker.pending, 2)
+
+	done := make(chan struct{})
+	suite.mockJobDisruptionOps.EXPECT().
+		UpdateDuration(gomock.Any(), suite.jobID.GetValue(), key1, time.Minute).
+		Do(func(context.Context, string, gocql.UUID, time.Duration) {
+			close(done)
+		}).
+		Return(nil)
+	suite.tracker.TaskRuntimeChanged(
+		suite.jobID,
+		1,
+		job.JobType_SERVICE,
+		&task.RuntimeInfo{
+			State:     task.TaskState_KILLED,
+			GoalState: task.TaskState_DELETED,
+		},
+		nil,
+	)
+	suite.waitResolved(done)
+	suite.Len(suite.tracker.pending, 1)
+}
+
+// TestJobRuntimeChangedResolvesTerminalJob tests that the pending
+// disruptions of a job are resolved and the job is no longer tracked once
+// it is terminal
+func (suite *TrackerTestSuite) TestJobRuntimeChangedResolvesTerminalJob() {
+	key := gocql.TimeUUID()
+	otherJobID := &peloton.JobID{Value: uuid.New()}
+	suite.tracker.loadedJobs[suite.jobID.GetValue()] = true
+	suite.tracker.loadedJobs[otherJobID.GetValue()] = true
+	suite.tracker.pending[suite.jobID.GetValue()+"-1"] = []*pendingDisruption{{
+		key:   key,
+		start: suite.currentTime.Add(-time.Hour),
+	}}
+
+	// job is still running
+	suite.tracker.JobRuntimeChanged(
+		suite.jobID,
+		job.JobType_BATCH,
+		&job.RuntimeInfo{
+			State:     job.JobState_RUNNING,
+			GoalState: job.JobState_SUCCEEDED,
+		},
+	)
+	suite.Len(suite.tracker.pending, 1)
+	suite.True(suite.tracker.loadedJobs[suite.jobID.GetValue()])
+
+	done := make(chan struct{})
+	suite.mockJobDisruptionOps.EXPECT().
+		UpdateDuration(gomock.Any(), suite.jobID.GetValue(), key, time.Hour).
+		Do(func(context.Context, string, gocql.UUID, time.Duration) {
+			close(done)
+		}).
+		Return(nil)
+	suite.tracker.JobRuntimeChanged(
+		suite.jobID,
+		job.JobType_BATCH,
+		&job.RuntimeInfo{
+			State:     job.JobState_KILLED,
+			GoalState: job.JobState_KILLED,
+		},
+	)
+	suite.waitResolved(done)
+	suite.Empty(suite.tracker.pending)
+	suite.False(suite.tracker.loadedJobs[suite.jobID.GetValue()])
+	suite.True(suite.tracker.loadedJobs[otherJobID.GetValue()])
+
+	// the job is untracked when it is deleted, even without any pending
+	// disruption
+	suite.tracker.JobRuntimeChanged(
+		otherJobID,
+		job.JobType_SERVICE,
+		&job.RuntimeInfo{
+			State:     job.JobState_RUNNING,
+			GoalState: job.JobState_DELETED,
+		},
+	)
+	suite.Empty(suite.tracker.loadedJobs)
+}
+
+// waitResolved waits for the pending disruptions to be resolved
+// asynchronously
+func (suite *TrackerTestSuite) waitResolved(done chan struct{}) {
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		suite.Fail("pending disruptions not resolved")
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/uber/peloton/pkg/jobmgr/cached"
 	"github.com/uber/peloton/pkg/jobmgr/goalstate"
 	jobmgr_task "github.com/uber/peloton/pkg/jobmgr/task"
+	"github.com/uber/peloton/pkg/jobmgr/task/disruption"
 	taskutil "github.com/uber/peloton/pkg/jobmgr/util/task"
 	"github.com/uber/peloton/pkg/storage"
 
@@ -82,6 +83,7 @@ type statusUpdate struct {
 	applier         *asyncEventProcessor
 	jobFactory      cached.JobFactory
 	goalStateDriver goalstate.Driver
+	tracker         disruption.Tracker
 	listeners       []Listener
 	rootCtx         context.Context
 	metrics         *Metrics
@@ -95,6 +97,7 @@ func NewTaskStatusUpdate(
 	volumeStore storage.PersistentVolumeStore,
 	jobFactory cached.JobFactory,
 	goalStateDriver goalstate.Driver,
+	tracker disruption.Tracker,
 	listeners []Listener,
 	parentScope tally.Scope) StatusUpdate {
 
@@ -107,6 +110,7 @@ func NewTaskStatusUpdate(
 		eventClients:    make(map[string]*eventstream.Client),
 		jobFactory:      jobFactory,
 		goalStateDriver: goalStateDriver,
+		tracker:         tracker,
 		listeners:       listeners,
 		hostmgrClient:   hostsvc.NewInternalHostServiceYARPCClient(d.ClientConfig(common.PelotonHostManager)),
 	}
@@ -134,6 +138,20 @@ func NewTaskStatusUpdate(
 // OnEvent is the callback function notifying an event
 func (p *statusUpdate) OnEvent(event *pb_eventstream.Event) {
 	log.WithField("event_offset", event.Offset).Debug("JobMgr receiving event")
+	if event.GetType() == pb_eventstream.Event_RESOURCE_POOL_MOVED {
+		// the paths of the moved resource pool and of its descendants
+		// have changed, they are looked up again on the next launch
+		log.WithField("respool_id", event.GetResPoolID().GetValue()).
+			Info("Resource pool moved, clearing cached resource pool paths")
+		p.jobFactory.ClearResourcePoolPaths()
+		return
+	}
+	if event.GetType() == pb_eventstream.Event_CAPACITY_ALERT {
+		// capacity alerts are not acted upon by job manager
+		log.WithField("alert", event.GetCapacityAlert()).
+			Debug("Ignoring capacity alert")
+		return
+	}
 	p.applier.addEvent(event)
 }
 
@@ -354,6 +372,21 @@ func (p *statusUpdate) ProcessStatusUpdate(ctx context.Context, event *pb_events
 		return err
 	}
 
+	// Record the disruption of the task, if any, once the runtime is
+	// written so that no disruption is recorded for a failed write. The
+	// runtime update is not retried on failure since the disruption
+	// history is best effort.
+	if err := p.tracker.Record(
+		ctx,
+		taskInfo.GetJobId(),
+		taskInfo.GetInstanceId(),
+		taskInfo.GetRuntime(),
+		newRuntime); err != nil {
+		log.WithError(err).
+			WithField("task_id", updateEvent.taskID).
+			Warn("Failed to record task disruption")
+	}
+
 	// Enqueue task to goal state
 	p.goalStateDriver.EnqueueTask(
 		taskInfo.GetJobId(),
@@ -33,6 +33,7 @@ import (
 	mesos "github.com/uber/peloton/.gen/mesos/v1"
 	"github.com/uber/peloton/.gen/peloton/api/v0/job"
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	pb_respool "github.com/uber/peloton/.gen/peloton/api/v0/respool"
 	"github.com/uber/peloton/.gen/peloton/api/v0/task"
 	"github.com/uber/peloton/.gen/peloton/api/v0/volume"
 	pb_eventstream "github.com/uber/peloton/.gen/peloton/private/eventstream"
@@ -43,6 +44,7 @@ import (
 	cachedmocks "github.com/uber/peloton/pkg/jobmgr/cached/mocks"
 	goalstatemocks "github.com/uber/peloton/pkg/jobmgr/goalstate/mocks"
 	jobmgrtask "github.com/uber/peloton/pkg/jobmgr/task"
+	disruptionmocks "github.com/uber/peloton/pkg/jobmgr/task/disruption/mocks"
 	event_mocks "github.com/uber/peloton/pkg/jobmgr/task/event/mocks"
 	store_mocks "github.com/uber/peloton/pkg/storage/mocks"
 )
@@ -85,6 +87,7 @@ type TaskUpdaterTestSuite struct {
 	mockVolumeStore   *store_mocks.MockPersistentVolumeStore
 	jobFactory        *cachedmocks.MockJobFactory
 	goalStateDriver   *goalstatemocks.MockDriver
+	mockTracker       *disruptionmocks.MockTracker
 	mockListener1     *event_mocks.MockListener
 	mockListener2     *event_mocks.MockListener
 	mockHostMgrClient *host_mocks.MockInternalHostServiceYARPCClient
@@ -98,6 +101,11 @@ func (suite *TaskUpdaterTestSuite) SetupTest() {
 	suite.mockVolumeStore = store_mocks.NewMockPersistentVolumeStore(suite.ctrl)
 	suite.jobFactory = cachedmocks.NewMockJobFactory(suite.ctrl)
 	suite.goalStateDriver = goalstatemocks.NewMockDriver(suite.ctrl)
+	suite.mockTracker = disruptionmocks.NewMockTracker(suite.ctrl)
+	suite.mockTracker.EXPECT().
+		Record(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
 	suite.mockListener1 = event_mocks.NewMockListener(suite.ctrl)
 	suite.mockListener2 = event_mocks.NewMockListener(suite.ctrl)
 	suite.mockHostMgrClient = host_mocks.NewMockInternalHostServiceYARPCClient(suite.ctrl)
@@ -109,6 +117,7 @@ func (suite *TaskUpdaterTestSuite) SetupTest() {
 		listeners:       []Listener{suite.mockListener1, suite.mockListener2},
 		jobFactory:      suite.jobFactory,
 		goalStateDriver: suite.goalStateDriver,
+		tracker:         suite.mockTracker,
 		rootCtx:         context.Background(),
 		metrics:         NewMetrics(suite.testScope.SubScope("status_updater")),
 		hostmgrClient:   suite.mockHostMgrClient,
@@ -124,6 +133,35 @@ func TestPelotonTaskUpdater(t *testing.T) {
 	suite.Run(t, new(TaskUpdaterTestSuite))
 }
 
+// TestResourcePoolMovedEvent tests that the cached resource pool paths are
+// cleared when a resource pool is moved
+func (suite *TaskUpdaterTestSuite) TestResourcePoolMovedEvent() {
+	defer suite.ctrl.Finish()
+
+	suite.jobFactory.EXPECT().ClearResourcePoolPaths()
+	suite.updater.OnEvent(&pb_eventstream.Event{
+		Offset:    1,
+		Type:      pb_eventstream.Event_RESOURCE_POOL_MOVED,
+		ResPoolID: &peloton.ResourcePoolID{Value: "respool"},
+	})
+	suite.Equal(uint64(0), suite.updater.GetEventProgress())
+}
+
+// TestCapacityAlertEvent tests that the capacity alerts are ignored
+func (suite *TaskUpdaterTestSuite) TestCapacityAlertEvent() {
+	defer suite.ctrl.Finish()
+
+	suite.updater.OnEvent(&pb_eventstream.Event{
+		Offset: 1,
+		Type:   pb_eventstream.Event_CAPACITY_ALERT,
+		CapacityAlert: &pb_respool.CapacityAlert{
+			Kind: "cpu",
+			Type: pb_respool.CapacityAlert_HEADROOM,
+		},
+	})
+	suite.Equal(uint64(0), suite.updater.GetEventProgress())
+}
+
 func (suite *TaskUpdaterTestSuite) TestNewTaskStatusUpdate() {
 	dispatcher := yarpc.NewDispatcher(yarpc.Config{
 		Name: common.PelotonJobManager,
@@ -143,6 +181,7 @@ func (suite *TaskUpdaterTestSuite) TestNewTaskStatusUpdate() {
 		suite.mockVolumeStore,
 		suite.jobFactory,
 		suite.goalStateDriver,
+		suite.mockTracker,
 		[]Listener{},
 		tally.NoopScope,
 	)
@@ -279,6 +318,59 @@ func (suite *TaskUpdaterTestSuite) TestProcessStatusUpdate() {
 		suite.testScope.Snapshot().Counters()["status_updater.tasks_running_total+"].Value())
 }
 
+// Test case of processing status update for a task whose disruption fails
+// to be recorded
+func (suite *TaskUpdaterTestSuite) TestProcessStatusUpdateRecordDisruptionError() {
+	defer suite.ctrl.Finish()
+
+	cachedJob := cachedmocks.NewMockJob(suite.ctrl)
+	cachedTask := cachedmocks.NewMockTask(suite.ctrl)
+	mockTracker := disruptionmocks.NewMockTracker(suite.ctrl)
+	suite.updater.tracker = mockTracker
+	event := createTestTaskUpdateEvent(mesos.TaskState_TASK_KILLED)
+	timeNow := float64(time.Now().UnixNano())
+	event.MesosTaskStatus.Timestamp = &timeNow
+	taskInfo := createTestTaskInfo(task.TaskState_RUNNING)
+	taskInfo.Runtime.TerminationStatus = &task.TerminationStatus{
+		Reason: task.TerminationStatus_TERMINATION_STATUS_REASON_KILLED_HOST_MAINTENANCE,
+	}
+
+	gomock.InOrder(
+		suite.mockTaskStore.EXPECT().
+			GetTaskByID(context.Background(), _pelotonTaskID).
+			Return(taskInfo, nil),
+		suite.jobFactory.EXPECT().AddJob(_pelotonJobID).Return(cachedJob),
+		cachedJob.EXPECT().SetTaskUpdateTime(event.MesosTaskStatus.Timestamp).Return(),
+		cachedJob.EXPECT().AddTask(gomock.Any(), _instanceID).Return(cachedTask, nil),
+		cachedJob.EXPECT().GetJobType().Return(job.JobType_BATCH),
+		cachedTask.EXPECT().CompareAndSetTask(context.Background(), gomock.Any(), job.JobType_BATCH).Return(nil, nil),
+		mockTracker.EXPECT().
+			Record(context.Background(), _pelotonJobID, _instanceID, taskInfo.GetRuntime(), gomock.Any()).
+			Do(func(
+				_ context.Context,
+				_ *peloton.JobID,
+				_ uint32,
+				_ *task.RuntimeInfo,
+				runtime *task.RuntimeInfo) {
+				suite.Equal(task.TaskState_KILLED, runtime.GetState())
+				suite.Equal(
+					task.TerminationStatus_TERMINATION_STATUS_REASON_KILLED_HOST_MAINTENANCE,
+					runtime.GetTerminationStatus().GetReason())
+			}).
+			Return(fmt.Errorf("test error")),
+		suite.goalStateDriver.EXPECT().EnqueueTask(_pelotonJobID, _instanceID, gomock.Any()).Return(),
+		cachedJob.EXPECT().GetJobType().Return(job.JobType_BATCH),
+		suite.goalStateDriver.EXPECT().
+			JobRuntimeDuration(job.JobType_BATCH).
+			Return(1*time.Second),
+		suite.goalStateDriver.EXPECT().EnqueueJob(_pelotonJobID, gomock.Any()).Return(),
+		cachedJob.EXPECT().UpdateResourceUsage(gomock.Any()).Return(),
+	)
+
+	now = nowMock
+	suite.NoError(suite.updater.ProcessStatusUpdate(context.Background(), event))
+}
+
 // Test case of processing status update for a task going through in-place update
 func (suite *TaskUpdaterTestSuite) TestProcessStatusUpdateInPlaceUpdateTask() {
 	defer suite.ctrl.Finish()
@@ -25,6 +25,7 @@ import (
 
 	mesos "github.com/uber/peloton/.gen/mesos/v1"
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/respool"
 	"github.com/uber/peloton/.gen/peloton/api/v0/task"
 	"github.com/uber/peloton/.gen/peloton/api/v0/volume"
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
@@ -37,6 +38,7 @@ import (
 	"github.com/uber/peloton/pkg/common/util"
 	"github.com/uber/peloton/pkg/jobmgr/cached"
 	jobmgrcommon "github.com/uber/peloton/pkg/jobmgr/common"
+	handlerutil "github.com/uber/peloton/pkg/jobmgr/util/handler"
 	"github.com/uber/peloton/pkg/storage"
 	ormobjects "github.com/uber/peloton/pkg/storage/objects"
 
@@ -129,6 +131,7 @@ type Launcher interface {
 type launcher struct {
 	sync.Mutex
 	hostMgrClient hostsvc.InternalHostServiceYARPCClient
+	respoolClient respool.ResourceManagerYARPCClient
 	jobFactory    cached.JobFactory
 	taskStore     storage.TaskStore
 	volumeStore   storage.PersistentVolumeStore
@@ -148,6 +151,12 @@ const (
 var (
 	errEmptyTasks         = errors.New("empty tasks infos")
 	errLaunchInvalidOffer = errors.New("invalid offer to launch tasks")
+
+	// key of the system label holding the resource pool path of the job
+	_respoolLabelKey = fmt.Sprintf(
+		common.SystemLabelKeyTemplate,
+		common.SystemLabelPrefix,
+		common.SystemLabelResourcePool)
 )
 
 var taskLauncher *launcher
@@ -157,6 +166,7 @@ var onceInitTaskLauncher sync.Once
 func InitTaskLauncher(
 	d *yarpc.Dispatcher,
 	hostMgrClientName string,
+	resMgrClientName string,
 	jobFactory cached.JobFactory,
 	taskStore storage.TaskStore,
 	volumeStore storage.PersistentVolumeStore,
@@ -171,6 +181,7 @@ func InitTaskLauncher(
 
 		taskLauncher = &launcher{
 			hostMgrClient: hostsvc.NewInternalHostServiceYARPCClient(d.ClientConfig(hostMgrClientName)),
+			respoolClient: respool.NewResourceManagerYARPCClient(d.ClientConfig(resMgrClientName)),
 			jobFactory:    jobFactory,
 			taskStore:     taskStore,
 			volumeStore:   volumeStore,
@@ -290,6 +301,13 @@ func (l *launcher) GetLaunchableTasks(
 			continue
 		}
 
+		if err := l.updateResourcePoolLabel(
+			ctx, cachedJob, configAddOn); err != nil {
+			// launch the task with the stored resource pool path
+			log.WithError(err).WithField("task_id", taskID.GetValue()).
+				Warn("failed to get the resource pool path of the job")
+		}
+
 		runtimeDiff := make(jobmgrcommon.RuntimeDiff)
 
 		// Generate volume ID if not set for stateful task.
@@ -364,6 +382,40 @@ func (l *launcher) GetLaunchableTasks(
 	return launchableTasks, skippedTasks, nil
 }
 
+// updateResourcePoolLabel sets the resource pool system label of the config
+// add-on to the current path of the resource pool of the job. The path stored
+// in the config add-on is stale once the resource pool, or one of its
+// ancestors, has been moved to a new parent. The paths are cached by the job
+// factory, which clears them when resource manager reports a move, so that
+// resource manager is only called for the resource pools not in cache.
+func (l *launcher) updateResourcePoolLabel(
+	ctx context.Context,
+	cachedJob cached.Job,
+	configAddOn *models.ConfigAddOn) error {
+	var respoolLabel *peloton.Label
+	for _, label := range configAddOn.GetSystemLabels() {
+		if label.GetKey() == _respoolLabelKey {
+			respoolLabel = label
+		}
+	}
+	if respoolLabel == nil {
+		return nil
+	}
+
+	config, err := cachedJob.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	path, err := handlerutil.GetResourcePoolPath(
+		ctx, config.GetRespoolID(), l.jobFactory, l.respoolClient)
+	if err != nil {
+		return err
+	}
+
+	respoolLabel.Value = path
+	return nil
+}
+
 // updateTaskRuntime updates task runtime with goalstate, reason and message
 // for the given task id.
 func (l *launcher) updateTaskRuntime(
@@ -33,6 +33,8 @@ import (
 
 	mesos "github.com/uber/peloton/.gen/mesos/v1"
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/respool"
+	respoolmocks "github.com/uber/peloton/.gen/peloton/api/v0/respool/mocks"
 	"github.com/uber/peloton/.gen/peloton/api/v0/task"
 	"github.com/uber/peloton/.gen/peloton/api/v0/volume"
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
@@ -74,6 +76,7 @@ type LauncherTestSuite struct {
 
 	ctrl            *gomock.Controller
 	mockHostMgr     *host_mocks.MockInternalHostServiceYARPCClient
+	mockRespool     *respoolmocks.MockResourceManagerYARPCClient
 	mockTaskStore   *store_mocks.MockTaskStore
 	jobFactory      *cachedmocks.MockJobFactory
 	cachedJob       *cachedmocks.MockJob
@@ -93,6 +96,7 @@ func (suite *LauncherTestSuite) SetupTest() {
 	suite.ctrl = gomock.NewController(suite.T())
 
 	suite.mockHostMgr = host_mocks.NewMockInternalHostServiceYARPCClient(suite.ctrl)
+	suite.mockRespool = respoolmocks.NewMockResourceManagerYARPCClient(suite.ctrl)
 	suite.mockTaskStore = store_mocks.NewMockTaskStore(suite.ctrl)
 	suite.jobFactory = cachedmocks.NewMockJobFactory(suite.ctrl)
 	suite.cachedJob = cachedmocks.NewMockJob(suite.ctrl)
@@ -104,6 +108,7 @@ func (suite *LauncherTestSuite) SetupTest() {
 	suite.metrics = NewMetrics(suite.testScope)
 	suite.taskLauncher = launcher{
 		hostMgrClient: suite.mockHostMgr,
+		respoolClient: suite.mockRespool,
 		jobFactory:    suite.jobFactory,
 		volumeStore:   suite.mockVolumeStore,
 		taskStore:     suite.mockTaskStore,
@@ -240,6 +245,107 @@ func (suite *LauncherTestSuite) TestGetLaunchableTasks() {
 	}
 	suite.EqualValues(unknownTasks, skippedTasks)
 }
+
+// TestGetLaunchableTasksResourcePoolLabel tests that the resource pool
+// system label of the launched tasks is set to the current path of the
+// resource pool of the job, which changes when the pool is moved.
+func (suite *LauncherTestSuite) TestGetLaunchableTasksResourcePoolLabel() {
+	jobID := &peloton.JobID{Value: _testJobID}
+	respoolID := &peloton.ResourcePoolID{Value: "respool"}
+	jobConfig := cachedmocks.NewMockJobConfigCache(suite.ctrl)
+	rs := createResources(1)
+	hostOffer := createHostOffer(0, rs)
+
+	var tasks []*peloton.TaskID
+	for i := 0; i < 2; i++ {
+		tmp := createTestTask(i)
+		tasks = append(tasks, &peloton.TaskID{
+			Value: fmt.Sprintf("%s-%d", _testJobID, i),
+		})
+		suite.jobFactory.EXPECT().
+			GetJob(jobID).Return(suite.cachedJob)
+		suite.cachedJob.EXPECT().
+			AddTask(gomock.Any(), uint32(i)).
+			Return(suite.cachedTask, nil)
+		suite.mockTaskStore.EXPECT().
+			GetTaskConfig(gomock.Any(), jobID, uint32(i), gomock.Any()).
+			Return(tmp.GetConfig(), &models.ConfigAddOn{
+				SystemLabels: []*peloton.Label{
+					{Key: _respoolLabelKey, Value: "/old/path"},
+				},
+			}, nil)
+		suite.cachedTask.EXPECT().
+			GetRuntime(gomock.Any()).Return(tmp.GetRuntime(), nil)
+	}
+
+	// the path is looked up from resource manager once, and then read
+	// from the cache of the job factory
+	suite.cachedJob.EXPECT().GetConfig(gomock.Any()).Return(jobConfig, nil).Times(2)
+	jobConfig.EXPECT().GetRespoolID().Return(respoolID).AnyTimes()
+	gomock.InOrder(
+		suite.jobFactory.EXPECT().
+			GetResourcePoolPath(respoolID).Return("", false),
+		suite.mockRespool.EXPECT().
+			GetResourcePool(gomock.Any(), &respool.GetRequest{Id: respoolID}).
+			Return(&respool.GetResponse{
+				Poolinfo: &respool.ResourcePoolInfo{
+					Id:   respoolID,
+					Path: &respool.ResourcePoolPath{Value: "/new/path"},
+				},
+			}, nil),
+		suite.jobFactory.EXPECT().
+			SetResourcePoolPath(respoolID, "/new/path"),
+		suite.jobFactory.EXPECT().
+			GetResourcePoolPath(respoolID).Return("/new/path", true),
+	)
+
+	launchableTasks, _, err := suite.taskLauncher.GetLaunchableTasks(
+		context.Background(), tasks, hostOffer.Hostname,
+		hostOffer.AgentId, nil)
+	suite.NoError(err)
+	suite.Len(launchableTasks, 2)
+	for _, launchableTask := range launchableTasks {
+		suite.Equal(
+			"/new/path",
+			launchableTask.ConfigAddOn.GetSystemLabels()[0].GetValue())
+	}
+
+	// the stored path is kept if the path is not cached, and the resource
+	// pool cannot be looked up
+	tmp := createTestTask(0)
+	suite.jobFactory.EXPECT().
+		GetJob(jobID).Return(suite.cachedJob)
+	suite.cachedJob.EXPECT().
+		AddTask(gomock.Any(), uint32(0)).
+		Return(suite.cachedTask, nil)
+	suite.mockTaskStore.EXPECT().
+		GetTaskConfig(gomock.Any(), jobID, uint32(0), gomock.Any()).
+		Return(tmp.GetConfig(), &models.ConfigAddOn{
+			SystemLabels: []*peloton.Label{
+				{Key: _respoolLabelKey, Value: "/old/path"},
+			},
+		}, nil)
+	suite.cachedTask.EXPECT().
+		GetRuntime(gomock.Any()).Return(tmp.GetRuntime(), nil)
+	suite.cachedJob.EXPECT().GetConfig(gomock.Any()).Return(jobConfig, nil)
+	suite.jobFactory.EXPECT().
+		GetResourcePoolPath(respoolID).Return("", false)
+	suite.mockRespool.EXPECT().
+		GetResourcePool(gomock.Any(), &respool.GetRequest{Id: respoolID}).
+		Return(nil, errors.New("resmgr unavailable"))
+
+	launchableTasks, _, err = suite.taskLauncher.GetLaunchableTasks(
+		context.Background(), tasks[:1], hostOffer.Hostname,
+		hostOffer.AgentId, nil)
+	suite.NoError(err)
+	suite.Len(launchableTasks, 1)
+	for _, launchableTask := range launchableTasks {
+		suite.Equal(
+			"/old/path",
+			launchableTask.ConfigAddOn.GetSystemLabels()[0].GetValue())
+	}
+}
+
 func (suite *LauncherTestSuite) TestGetLaunchableTasksStateful() {
 	unknownTasks := []*peloton.TaskID{
 		{Value: "bcabcabc-bcab-bcab-bcab-bcabcabcabca-0"},
@@ -0,0 +1,114 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package launchscheduler
+
+import (
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+)
+
+const (
+	_defaultMaxLaunches               = 300
+	_defaultBatchMaxLaunches          = 100
+	_defaultBatchMaxLaunchesPerHost   = 2
+	_defaultServiceMaxLaunches        = 200
+	_defaultServiceMaxLaunchesPerHost = 4
+	_defaultDaemonMaxLaunches         = 200
+	_defaultDaemonMaxLaunchesPerHost  = 2
+
+	// the placing offers of a host are reset by host manager after
+	// 5 minutes, so return the placements well before
+	_defaultMaxQueueAge  = time.Minute
+	_defaultMaxQueueSize = 10000
+)
+
+// Config is the launch scheduler specific config
+type Config struct {
+	// Enabled enables the launch scheduler. If disabled, the placements
+	// are launched as soon as they are received from resource manager.
+	Enabled bool `yaml:"enabled"`
+
+	// MaxLaunches is the maximum number of placements of all job types
+	// launched concurrently in the cluster
+	MaxLaunches int `yaml:"max_launches"`
+
+	// Batch are the launch limits of batch jobs
+	Batch LimitConfig `yaml:"batch"`
+
+	// Service are the launch limits of service jobs
+	Service LimitConfig `yaml:"service"`
+
+	// Daemon are the launch limits of daemon jobs
+	Daemon LimitConfig `yaml:"daemon"`
+
+	// MaxQueueAge is the maximum time a placement waits to be launched,
+	// after which it is returned to resource manager to be placed again
+	MaxQueueAge time.Duration `yaml:"max_queue_age"`
+
+	// MaxQueueSize is the maximum number of placements waiting to be
+	// launched, the placements received when the queue is full are
+	// returned to resource manager to be placed again
+	MaxQueueSize int `yaml:"max_queue_size"`
+}
+
+// LimitConfig are the limits on the number of placements of a job type
+// which can be launched concurrently
+type LimitConfig struct {
+	// MaxLaunches is the maximum number of placements launched
+	// concurrently in the cluster
+	MaxLaunches int `yaml:"max_launches"`
+
+	// MaxLaunchesPerHost is the maximum number of placements launched
+	// concurrently on a host
+	MaxLaunchesPerHost int `yaml:"max_launches_per_host"`
+}
+
+// normalize configuration by setting unassigned fields to default values.
+func (c *Config) normalize() {
+	if c.MaxLaunches == 0 {
+		c.MaxLaunches = _defaultMaxLaunches
+	}
+	c.Batch.normalize(_defaultBatchMaxLaunches, _defaultBatchMaxLaunchesPerHost)
+	c.Service.normalize(_defaultServiceMaxLaunches, _defaultServiceMaxLaunchesPerHost)
+	c.Daemon.normalize(_defaultDaemonMaxLaunches, _defaultDaemonMaxLaunchesPerHost)
+	if c.MaxQueueAge == 0 {
+		c.MaxQueueAge = _defaultMaxQueueAge
+	}
+	if c.MaxQueueSize == 0 {
+		c.MaxQueueSize = _defaultMaxQueueSize
+	}
+}
+
+func (c *LimitConfig) normalize(maxLaunches, maxLaunchesPerHost int) {
+	if c.MaxLaunches == 0 {
+		c.MaxLaunches = maxLaunches
+	}
+	if c.MaxLaunchesPerHost == 0 {
+		c.MaxLaunchesPerHost = maxLaunchesPerHost
+	}
+}
+
+// limits returns the launch limits of a job type
+func (c *Config) limits(jobType job.JobType) LimitConfig {
+	switch jobType {
+	case job.JobType_SERVICE:
+		return c.Service
+	case job.JobType_DAEMON:
+		return c.Daemon
+	default:
+		return c.Batch
+	}
+}
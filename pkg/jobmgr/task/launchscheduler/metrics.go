@@ -0,0 +1,49 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package launchscheduler
+
+import (
+	"github.com/uber-go/tally"
+)
+
+// Metrics is the struct containing all the counters, gauges and timers
+// that track the launch queue of the launch scheduler.
+type Metrics struct {
+	LaunchEnqueued      tally.Counter
+	LaunchAdmitted      tally.Counter
+	LaunchDropped       tally.Counter
+	LaunchExpired       tally.Counter
+	LaunchRejected      tally.Counter
+	LaunchQueueDuration tally.Timer
+	LaunchDuration      tally.Timer
+	PendingLaunches     tally.Gauge
+	InflightLaunches    tally.Gauge
+}
+
+// NewMetrics returns a new Metrics struct, with all metrics
+// initialized and rooted at the given tally.Scope
+func NewMetrics(scope tally.Scope) *Metrics {
+	return &Metrics{
+		LaunchEnqueued:      scope.Counter("launch_enqueued"),
+		LaunchAdmitted:      scope.Counter("launch_admitted"),
+		LaunchDropped:       scope.Counter("launch_dropped"),
+		LaunchExpired:       scope.Counter("launch_expired"),
+		LaunchRejected:      scope.Counter("launch_rejected"),
+		LaunchQueueDuration: scope.Timer("launch_queue_duration"),
+		LaunchDuration:      scope.Timer("launch_duration"),
+		PendingLaunches:     scope.Gauge("pending_launches"),
+		InflightLaunches:    scope.Gauge("inflight_launches"),
+	}
+}
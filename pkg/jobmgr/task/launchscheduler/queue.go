@@ -0,0 +1,99 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package launchscheduler
+
+// launchQueue is the heap of the pending launches of a job type on a host,
+// implementing the `container/heap.Interface` interface. The launch with
+// the highest priority, and then the oldest, is at the head. The
+// launchQueue must only be called indirectly through the `container/heap`
+// functions.
+type launchQueue []*Launch
+
+func (q launchQueue) Len() int { return len(q) }
+
+func (q launchQueue) Less(i, j int) bool {
+	if q[i].Priority != q[j].Priority {
+		return q[i].Priority > q[j].Priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q launchQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *launchQueue) Push(x interface{}) {
+	*q = append(*q, x.(*Launch))
+}
+
+func (q *launchQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	launch := old[n-1]
+	old[n-1] = nil
+	*q = old[0 : n-1]
+	return launch
+}
+
+// candidate is the queue of a job type on a host whose head can be admitted
+type candidate struct {
+	key   hostKey
+	queue *launchQueue
+	// inflightByJob is the number of launches in flight of the job of the
+	// head of the queue when the candidate was pushed
+	inflightByJob int
+}
+
+// head returns the launch at the head of the queue of the candidate
+func (c *candidate) head() *Launch {
+	return (*c.queue)[0]
+}
+
+// candidateQueue is the heap of the candidates of a dispatch, implementing
+// the `container/heap.Interface` interface. The candidate whose head has
+// the highest priority is at the head, then the one whose job has the
+// fewest launches in flight, and then the oldest. The candidateQueue must
+// only be called indirectly through the `container/heap` functions.
+type candidateQueue []*candidate
+
+func (q candidateQueue) Len() int { return len(q) }
+
+func (q candidateQueue) Less(i, j int) bool {
+	a, b := q[i].head(), q[j].head()
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	if q[i].inflightByJob != q[j].inflightByJob {
+		return q[i].inflightByJob < q[j].inflightByJob
+	}
+	return a.seq < b.seq
+}
+
+func (q candidateQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *candidateQueue) Push(x interface{}) {
+	*q = append(*q, x.(*candidate))
+}
+
+func (q *candidateQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	c := old[n-1]
+	old[n-1] = nil
+	*q = old[0 : n-1]
+	return c
+}
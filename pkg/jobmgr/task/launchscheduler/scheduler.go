@@ -0,0 +1,373 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package launchscheduler
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+
+	"github.com/uber/peloton/pkg/common/lifecycle"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/uber-go/tally"
+)
+
+// Launch is a placement waiting to be launched by the scheduler
+type Launch struct {
+	// JobID is the job of the tasks in the placement, which is used
+	// to share the launches fairly across jobs
+	JobID string
+	// JobType is the type of the job, which selects the launch limits
+	JobType job.JobType
+	// Priority is the SLA priority of the job, higher priority
+	// placements are launched first
+	Priority uint32
+	// Hostname is the host on which the tasks are placed
+	Hostname string
+	// Run launches the placement, in its own goroutine once admitted
+	Run func()
+	// Return returns the placement to resource manager, in its own
+	// goroutine, if the placement is not admitted within the max queue
+	// age, if the queue is full when it is enqueued or if the scheduler
+	// is stopped before it is admitted
+	Return func()
+
+	// seq is the order in which the launch was enqueued
+	seq uint64
+	// enqueueTime is the time at which the launch was enqueued
+	enqueueTime time.Time
+}
+
+// _maxExpiryCheckPeriod is the maximum period to check for the launches
+// which waited in the queue for longer than the max queue age
+const _maxExpiryCheckPeriod = 5 * time.Second
+
+// Scheduler orders the launches of the placements by priority, and
+// limits the number of placements launched concurrently per host and
+// in the cluster, per job type and in total, so that a mass launch of
+// a job cannot starve the launches of other jobs.
+type Scheduler interface {
+	// Start starts the scheduler goroutine
+	Start() error
+	// Stop stops the scheduler goroutine, the pending launches are returned
+	Stop() error
+	// Enqueue adds a launch to the queue of the scheduler, the launch is
+	// returned if the queue is full
+	Enqueue(launch *Launch)
+}
+
+// hostKey is the key of the launches of a job type on a host
+type hostKey struct {
+	jobType  job.JobType
+	hostname string
+}
+
+// scheduler implements Scheduler
+type scheduler struct {
+	sync.Mutex
+
+	config *Config
+	// pending launches by job type and host
+	pending map[hostKey]*launchQueue
+	// number of pending launches
+	numPending int
+	// number of launches in flight, in total, by job type, by host and
+	// by job
+	inflight       int
+	inflightByType map[job.JobType]int
+	inflightByHost map[hostKey]int
+	inflightByJob  map[string]int
+	// seq is the sequence number of the last enqueued launch
+	seq uint64
+	// notifyCh wakes up the scheduler goroutine to dispatch launches
+	notifyCh  chan struct{}
+	lifeCycle lifecycle.LifeCycle
+	metrics   *Metrics
+}
+
+// New creates a new launch Scheduler
+func New(config Config, parent tally.Scope) Scheduler {
+	config.normalize()
+	return &scheduler{
+		config:         &config,
+		pending:        make(map[hostKey]*launchQueue),
+		inflightByType: make(map[job.JobType]int),
+		inflightByHost: make(map[hostKey]int),
+		inflightByJob:  make(map[string]int),
+		notifyCh:       make(chan struct{}, 1),
+		lifeCycle:      lifecycle.NewLifeCycle(),
+		metrics:        NewMetrics(parent.SubScope("launch_scheduler")),
+	}
+}
+
+// Start starts the scheduler
+func (s *scheduler) Start() error {
+	if !s.lifeCycle.Start() {
+		log.Warn("launch scheduler is already running, no action will be performed")
+		return nil
+	}
+
+	go s.run()
+
+	log.Info("launch scheduler started")
+	return nil
+}
+
+// Stop stops the scheduler
+func (s *scheduler) Stop() error {
+	if !s.lifeCycle.Stop() {
+		log.Warn("launch scheduler is already stopped, no action will be performed")
+		return nil
+	}
+
+	s.lifeCycle.Wait()
+	log.Info("launch scheduler stopped")
+	return nil
+}
+
+// Enqueue adds a launch to the queue of the scheduler
+func (s *scheduler) Enqueue(launch *Launch) {
+	s.Lock()
+	if s.numPending >= s.config.MaxQueueSize {
+		s.Unlock()
+		log.WithFields(log.Fields{
+			"job_id":   launch.JobID,
+			"hostname": launch.Hostname,
+		}).Warn("launch queue is full, returning the placement")
+		s.metrics.LaunchRejected.Inc(1)
+		go launch.Return()
+		return
+	}
+	s.seq++
+	launch.seq = s.seq
+	launch.enqueueTime = time.Now()
+	key := hostKey{jobType: launch.JobType, hostname: launch.Hostname}
+	queue, ok := s.pending[key]
+	if !ok {
+		queue = &launchQueue{}
+		s.pending[key] = queue
+	}
+	heap.Push(queue, launch)
+	s.numPending++
+	s.metrics.PendingLaunches.Update(float64(s.numPending))
+	s.Unlock()
+
+	s.metrics.LaunchEnqueued.Inc(1)
+	s.notify()
+}
+
+// notify wakes up the scheduler goroutine without blocking
+func (s *scheduler) notify() {
+	select {
+	case s.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+func (s *scheduler) run() {
+	period := s.config.MaxQueueAge
+	if period > _maxExpiryCheckPeriod {
+		period = _maxExpiryCheckPeriod
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.lifeCycle.StopCh():
+			s.dropPending()
+			s.lifeCycle.StopComplete()
+			return
+		case <-ticker.C:
+			s.dispatch()
+		case <-s.notifyCh:
+			s.dispatch()
+		}
+	}
+}
+
+// dispatch returns the expired launches and runs the pending launches
+// admitted within the limits
+func (s *scheduler) dispatch() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.expire()
+	for _, launch := range s.admit() {
+		s.metrics.LaunchAdmitted.Inc(1)
+		s.metrics.LaunchQueueDuration.Record(time.Since(launch.enqueueTime))
+		go s.launch(launch)
+	}
+
+	s.metrics.PendingLaunches.Update(float64(s.numPending))
+	s.metrics.InflightLaunches.Update(float64(s.inflight))
+}
+
+// expire returns the pending launches which waited in the queue for longer
+// than the max queue age. The lock must be held by the caller.
+func (s *scheduler) expire() {
+	for key, queue := range s.pending {
+		var pending launchQueue
+		for _, launch := range *queue {
+			if time.Since(launch.enqueueTime) <= s.config.MaxQueueAge {
+				pending = append(pending, launch)
+				continue
+			}
+			log.WithFields(log.Fields{
+				"job_id":   launch.JobID,
+				"hostname": launch.Hostname,
+			}).Info("launch expired in the queue, returning the placement")
+			s.metrics.LaunchExpired.Inc(1)
+			s.numPending--
+			go launch.Return()
+		}
+		if len(pending) == len(*queue) {
+			continue
+		}
+		if len(pending) == 0 {
+			delete(s.pending, key)
+			continue
+		}
+		heap.Init(&pending)
+		*queue = pending
+	}
+}
+
+// admit removes the pending launches which can be admitted within the
+// limits from the queue, accounts for them in flight and returns them in
+// the order they are admitted. Among the launches within their limits, the
+// one with the highest priority is admitted first, then the one whose job
+// has the fewest launches in flight, and then the oldest. Only the heads
+// of the queues of the hosts are compared, so the launches on a host are
+// admitted by priority and then in the order they were enqueued.
+// The lock must be held by the caller.
+func (s *scheduler) admit() []*Launch {
+	var candidates candidateQueue
+	for key, queue := range s.pending {
+		if s.admissible(key) {
+			candidates = append(candidates, &candidate{
+				key:           key,
+				queue:         queue,
+				inflightByJob: s.inflightByJob[(*queue)[0].JobID],
+			})
+		}
+	}
+	heap.Init(&candidates)
+
+	var admitted []*Launch
+	for candidates.Len() > 0 && s.inflight < s.config.MaxLaunches {
+		c := heap.Pop(&candidates).(*candidate)
+		if !s.admissible(c.key) {
+			// the cluster limit of the job type is reached
+			continue
+		}
+		// the launches in flight only increase while admitting, so the
+		// candidate is pushed back if its job got more launches in flight
+		if inflight := s.inflightByJob[c.head().JobID]; inflight != c.inflightByJob {
+			c.inflightByJob = inflight
+			heap.Push(&candidates, c)
+			continue
+		}
+
+		launch := heap.Pop(c.queue).(*Launch)
+		s.numPending--
+		s.acquire(launch)
+		admitted = append(admitted, launch)
+
+		if c.queue.Len() == 0 {
+			delete(s.pending, c.key)
+			continue
+		}
+		if s.admissible(c.key) {
+			c.inflightByJob = s.inflightByJob[c.head().JobID]
+			heap.Push(&candidates, c)
+		}
+	}
+	return admitted
+}
+
+// admissible returns true if a launch of the job type on the host is
+// within the limits of the job type. The lock must be held by the caller.
+func (s *scheduler) admissible(key hostKey) bool {
+	limits := s.config.limits(key.jobType)
+	if s.inflightByType[key.jobType] >= limits.MaxLaunches {
+		return false
+	}
+	return s.inflightByHost[key] < limits.MaxLaunchesPerHost
+}
+
+// acquire accounts for a launch in flight. The lock must be held by the caller.
+func (s *scheduler) acquire(launch *Launch) {
+	s.inflight++
+	s.inflightByType[launch.JobType]++
+	s.inflightByHost[hostKey{jobType: launch.JobType, hostname: launch.Hostname}]++
+	s.inflightByJob[launch.JobID]++
+}
+
+// release accounts for a launch which is no longer in flight
+func (s *scheduler) release(launch *Launch) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.inflight--
+	s.inflightByType[launch.JobType]--
+	key := hostKey{jobType: launch.JobType, hostname: launch.Hostname}
+	s.inflightByHost[key]--
+	if s.inflightByHost[key] == 0 {
+		delete(s.inflightByHost, key)
+	}
+	s.inflightByJob[launch.JobID]--
+	if s.inflightByJob[launch.JobID] == 0 {
+		delete(s.inflightByJob, launch.JobID)
+	}
+	s.metrics.InflightLaunches.Update(float64(s.inflight))
+}
+
+// launch runs an admitted launch and dispatches the next launches
+func (s *scheduler) launch(launch *Launch) {
+	start := time.Now()
+	launch.Run()
+	s.metrics.LaunchDuration.Record(time.Since(start))
+
+	s.release(launch)
+	s.notify()
+}
+
+// dropPending returns the launches which were not admitted yet, so that
+// the offers of their placements are released right away rather than
+// when the host manager times out the placing hosts
+func (s *scheduler) dropPending() {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.numPending == 0 {
+		return
+	}
+
+	log.WithField("launches_total", s.numPending).
+		Warn("returning pending launches due to lost leadership")
+	s.metrics.LaunchDropped.Inc(int64(s.numPending))
+	for _, queue := range s.pending {
+		for _, launch := range *queue {
+			go launch.Return()
+		}
+	}
+	s.pending = make(map[hostKey]*launchQueue)
+	s.numPending = 0
+	s.metrics.PendingLaunches.Update(0)
+}
@@ -0,0 +1,294 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package launchscheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+)
+
+type SchedulerTestSuite struct {
+	suite.Suite
+
+	scope     tally.TestScope
+	scheduler *scheduler
+}
+
+func TestScheduler(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
+
+func (suite *SchedulerTestSuite) SetupTest() {
+	suite.scope = tally.NewTestScope("", map[string]string{})
+	suite.scheduler = New(Config{
+		Enabled: true,
+		Batch: LimitConfig{
+			MaxLaunches:        2,
+			MaxLaunchesPerHost: 1,
+		},
+	}, suite.scope).(*scheduler)
+}
+
+func newLaunch(
+	jobID string,
+	jobType job.JobType,
+	priority uint32,
+	hostname string,
+) *Launch {
+	return &Launch{
+		JobID:    jobID,
+		JobType:  jobType,
+		Priority: priority,
+		Hostname: hostname,
+		Run:      func() {},
+		Return:   func() {},
+	}
+}
+
+// TestNormalizeConfig tests the default launch limits
+func (suite *SchedulerTestSuite) TestNormalizeConfig() {
+	config := suite.scheduler.config
+	suite.Equal(2, config.limits(job.JobType_BATCH).MaxLaunches)
+	suite.Equal(1, config.limits(job.JobType_BATCH).MaxLaunchesPerHost)
+	suite.Equal(
+		_defaultServiceMaxLaunches,
+		config.limits(job.JobType_SERVICE).MaxLaunches)
+	suite.Equal(
+		_defaultServiceMaxLaunchesPerHost,
+		config.limits(job.JobType_SERVICE).MaxLaunchesPerHost)
+	suite.Equal(
+		_defaultDaemonMaxLaunches,
+		config.limits(job.JobType_DAEMON).MaxLaunches)
+	suite.Equal(
+		_defaultDaemonMaxLaunchesPerHost,
+		config.limits(job.JobType_DAEMON).MaxLaunchesPerHost)
+	suite.Equal(_defaultMaxLaunches, config.MaxLaunches)
+	suite.Equal(_defaultMaxQueueAge, config.MaxQueueAge)
+	suite.Equal(_defaultMaxQueueSize, config.MaxQueueSize)
+}
+
+// TestAdmitByPriority tests that the launch with the highest priority
+// is admitted first
+func (suite *SchedulerTestSuite) TestAdmitByPriority() {
+	launch1 := newLaunch("job1", job.JobType_BATCH, 1, "host1")
+	launch2 := newLaunch("job2", job.JobType_BATCH, 5, "host2")
+	launch3 := newLaunch("job3", job.JobType_BATCH, 3, "host3")
+	suite.scheduler.Enqueue(launch1)
+	suite.scheduler.Enqueue(launch2)
+	suite.scheduler.Enqueue(launch3)
+
+	suite.Equal([]*Launch{launch2, launch3}, suite.scheduler.admit())
+	suite.Equal(1, suite.scheduler.numPending)
+}
+
+// TestAdmitFairShare tests that among launches with the same priority,
+// the job with the fewest launches in flight is admitted first
+func (suite *SchedulerTestSuite) TestAdmitFairShare() {
+	suite.scheduler.acquire(newLaunch("job1", job.JobType_SERVICE, 1, "host3"))
+	launch1 := newLaunch("job1", job.JobType_BATCH, 1, "host1")
+	launch2 := newLaunch("job1", job.JobType_BATCH, 1, "host2")
+	launch3 := newLaunch("job2", job.JobType_BATCH, 1, "host4")
+	suite.scheduler.Enqueue(launch1)
+	suite.scheduler.Enqueue(launch2)
+	suite.scheduler.Enqueue(launch3)
+
+	suite.Equal([]*Launch{launch3, launch1}, suite.scheduler.admit())
+}
+
+// TestAdmitHostLimit tests that launches on a host are limited
+func (suite *SchedulerTestSuite) TestAdmitHostLimit() {
+	suite.scheduler.acquire(newLaunch("job1", job.JobType_BATCH, 1, "host1"))
+	suite.scheduler.Enqueue(newLaunch("job2", job.JobType_BATCH, 5, "host1"))
+	suite.Empty(suite.scheduler.admit())
+
+	// the host limit is per job type
+	launch := newLaunch("job3", job.JobType_SERVICE, 1, "host1")
+	suite.scheduler.Enqueue(launch)
+	suite.Equal([]*Launch{launch}, suite.scheduler.admit())
+
+	// the launches on a host are admitted by priority
+	suite.scheduler.release(newLaunch("job1", job.JobType_BATCH, 1, "host1"))
+	launch = newLaunch("job4", job.JobType_BATCH, 7, "host1")
+	suite.scheduler.Enqueue(launch)
+	suite.Equal([]*Launch{launch}, suite.scheduler.admit())
+	suite.Equal(1, suite.scheduler.numPending)
+}
+
+// TestAdmitClusterLimit tests that launches in the cluster are limited
+// per job type
+func (suite *SchedulerTestSuite) TestAdmitClusterLimit() {
+	suite.scheduler.acquire(newLaunch("job1", job.JobType_BATCH, 1, "host1"))
+	suite.scheduler.acquire(newLaunch("job1", job.JobType_BATCH, 1, "host2"))
+	launch1 := newLaunch("job2", job.JobType_BATCH, 5, "host3")
+	suite.scheduler.Enqueue(launch1)
+	suite.Empty(suite.scheduler.admit())
+
+	launch2 := newLaunch("job3", job.JobType_SERVICE, 1, "host3")
+	suite.scheduler.Enqueue(launch2)
+	suite.Equal([]*Launch{launch2}, suite.scheduler.admit())
+
+	suite.scheduler.release(newLaunch("job1", job.JobType_BATCH, 1, "host1"))
+	suite.Equal([]*Launch{launch1}, suite.scheduler.admit())
+}
+
+// TestAdmitTotalLimit tests that launches in the cluster are limited
+// in total across job types
+func (suite *SchedulerTestSuite) TestAdmitTotalLimit() {
+	suite.scheduler.config.MaxLaunches = 2
+	suite.scheduler.acquire(newLaunch("job1", job.JobType_BATCH, 1, "host1"))
+	launch1 := newLaunch("job2", job.JobType_SERVICE, 1, "host2")
+	launch2 := newLaunch("job3", job.JobType_DAEMON, 1, "host3")
+	suite.scheduler.Enqueue(launch1)
+	suite.scheduler.Enqueue(launch2)
+
+	suite.Equal([]*Launch{launch1}, suite.scheduler.admit())
+	suite.Empty(suite.scheduler.admit())
+
+	suite.scheduler.release(launch1)
+	suite.Equal([]*Launch{launch2}, suite.scheduler.admit())
+}
+
+// TestLaunches tests that all the enqueued launches are run within
+// the limits
+func (suite *SchedulerTestSuite) TestLaunches() {
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	var inflight, maxInflight int
+
+	suite.NoError(suite.scheduler.Start())
+	defer suite.scheduler.Stop()
+
+	numLaunches := 10
+	wg.Add(numLaunches)
+	for i := 0; i < numLaunches; i++ {
+		launch := newLaunch("job1", job.JobType_BATCH, 1, "host1")
+		launch.Run = func() {
+			defer wg.Done()
+			lock.Lock()
+			inflight++
+			if inflight > maxInflight {
+				maxInflight = inflight
+			}
+			lock.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			lock.Lock()
+			inflight--
+			lock.Unlock()
+		}
+		suite.scheduler.Enqueue(launch)
+	}
+	wg.Wait()
+
+	// the host limit is 1
+	suite.Equal(1, maxInflight)
+	suite.Equal(
+		int64(numLaunches),
+		suite.scope.Snapshot().Counters()["launch_scheduler.launch_enqueued+"].Value())
+
+	// the launches in flight are released after they are run
+	released := func() bool {
+		suite.scheduler.Lock()
+		defer suite.scheduler.Unlock()
+		return len(suite.scheduler.inflightByJob) == 0 &&
+			len(suite.scheduler.inflightByHost) == 0
+	}
+	for i := 0; i < 100 && !released(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	suite.True(released())
+}
+
+// TestStopDropsPending tests that the pending launches are returned on stop
+func (suite *SchedulerTestSuite) TestStopDropsPending() {
+	suite.scheduler.acquire(newLaunch("job1", job.JobType_BATCH, 1, "host1"))
+	suite.scheduler.acquire(newLaunch("job1", job.JobType_BATCH, 1, "host2"))
+
+	returned := make(chan struct{})
+	pending := newLaunch("job2", job.JobType_BATCH, 1, "host3")
+	pending.Return = func() { close(returned) }
+	suite.scheduler.Enqueue(pending)
+
+	suite.NoError(suite.scheduler.Start())
+	suite.NoError(suite.scheduler.Start())
+	suite.NoError(suite.scheduler.Stop())
+	suite.NoError(suite.scheduler.Stop())
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		suite.Fail("pending launch is not returned")
+	}
+	suite.Empty(suite.scheduler.pending)
+	suite.Zero(suite.scheduler.numPending)
+	suite.Equal(
+		int64(1),
+		suite.scope.Snapshot().Counters()["launch_scheduler.launch_dropped+"].Value())
+}
+
+// TestExpire tests that the launches which are not admitted within the
+// max queue age are returned
+func (suite *SchedulerTestSuite) TestExpire() {
+	suite.scheduler.acquire(newLaunch("job1", job.JobType_BATCH, 1, "host1"))
+	suite.scheduler.acquire(newLaunch("job1", job.JobType_BATCH, 1, "host2"))
+
+	returned := make(chan struct{})
+	expired := newLaunch("job2", job.JobType_BATCH, 1, "host3")
+	expired.Return = func() { close(returned) }
+	suite.scheduler.Enqueue(expired)
+	suite.scheduler.Enqueue(newLaunch("job3", job.JobType_BATCH, 1, "host3"))
+
+	expired.enqueueTime = time.Now().Add(-2 * suite.scheduler.config.MaxQueueAge)
+	suite.scheduler.dispatch()
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		suite.Fail("expired launch is not returned")
+	}
+	suite.Equal(1, suite.scheduler.numPending)
+	suite.Equal(
+		int64(1),
+		suite.scope.Snapshot().Counters()["launch_scheduler.launch_expired+"].Value())
+}
+
+// TestEnqueueQueueFull tests that the launches enqueued when the queue
+// is full are returned
+func (suite *SchedulerTestSuite) TestEnqueueQueueFull() {
+	suite.scheduler.config.MaxQueueSize = 1
+	suite.scheduler.Enqueue(newLaunch("job1", job.JobType_BATCH, 1, "host1"))
+
+	returned := make(chan struct{})
+	rejected := newLaunch("job2", job.JobType_BATCH, 1, "host2")
+	rejected.Return = func() { close(returned) }
+	suite.scheduler.Enqueue(rejected)
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		suite.Fail("rejected launch is not returned")
+	}
+	suite.Equal(1, suite.scheduler.numPending)
+	suite.Equal(
+		int64(1),
+		suite.scope.Snapshot().Counters()["launch_scheduler.launch_rejected+"].Value())
+}
@@ -35,6 +35,7 @@ import (
 	jobmgrcommon "github.com/uber/peloton/pkg/jobmgr/common"
 	"github.com/uber/peloton/pkg/jobmgr/goalstate"
 	"github.com/uber/peloton/pkg/jobmgr/task/launcher"
+	"github.com/uber/peloton/pkg/jobmgr/task/launchscheduler"
 	taskutil "github.com/uber/peloton/pkg/jobmgr/util/task"
 )
 
@@ -47,6 +48,10 @@ type Config struct {
 	// GetPlacementsTimeout is the timeout value for placement processor to
 	// call GetPlacements
 	GetPlacementsTimeout int `yaml:"get_placements_timeout_ms"`
+
+	// LaunchScheduler is the config of the scheduler which orders and
+	// limits the launches of the placements
+	LaunchScheduler launchscheduler.Config `yaml:"launch_scheduler"`
 }
 
 // Processor defines the interface of placement processor
@@ -64,11 +69,16 @@ type processor struct {
 	jobFactory      cached.JobFactory
 	goalStateDriver goalstate.Driver
 	taskLauncher    launcher.Launcher
+	launchScheduler launchscheduler.Scheduler
 	lifeCycle       lifecycle.LifeCycle
 	config          *Config
 	metrics         *Metrics
 }
 
+// errPlacementNotLaunched is the reason to release the offers of a
+// placement which is returned by the launch scheduler
+var errPlacementNotLaunched = errors.New("placement is not launched")
+
 const (
 	// Time out for the function to time out
 	_rpcTimeout = 10 * time.Second
@@ -88,7 +98,7 @@ func InitProcessor(
 	config *Config,
 	parent tally.Scope,
 ) Processor {
-	return &processor{
+	p := &processor{
 		resMgrClient:    resmgrsvc.NewResourceManagerServiceYARPCClient(d.ClientConfig(resMgrClientName)),
 		jobFactory:      jobFactory,
 		goalStateDriver: goalStateDriver,
@@ -97,6 +107,12 @@ func InitProcessor(
 		metrics:         NewMetrics(parent.SubScope("jobmgr").SubScope("task")),
 		lifeCycle:       lifecycle.NewLifeCycle(),
 	}
+	if config.LaunchScheduler.Enabled {
+		p.launchScheduler = launchscheduler.New(
+			config.LaunchScheduler,
+			parent.SubScope("jobmgr"))
+	}
+	return p
 }
 
 // Start starts Processor
@@ -108,6 +124,14 @@ func (p *processor) Start() error {
 	}
 	log.Info("starting placement processor")
 
+	if p.launchScheduler != nil {
+		if err := p.launchScheduler.Start(); err != nil {
+			log.WithError(err).Error("failed to start launch scheduler")
+			p.lifeCycle.Stop()
+			p.lifeCycle.StopComplete()
+			return err
+		}
+	}
 	go p.run()
 
 	log.Info("placement processor started")
@@ -154,10 +178,80 @@ func (p *processor) process() {
 	// Getting and launching placements in different go routine
 	log.WithField("placements", placements).Debug("Start processing placements")
 	for _, placement := range placements {
+		if p.launchScheduler != nil {
+			go p.enqueueLaunch(ctx, placement)
+			continue
+		}
 		go p.processPlacement(ctx, placement)
 	}
 }
 
+// enqueueLaunch enqueues the launch of a placement to the launch scheduler.
+// It runs in its own goroutine, as the job config of the placement may
+// have to be read from the DB, which must not block dequeuing the next
+// placements.
+func (p *processor) enqueueLaunch(
+	ctx context.Context,
+	placement *resmgr.Placement,
+) {
+	launch := p.newLaunch(ctx, placement)
+
+	select {
+	case <-p.lifeCycle.StopCh():
+		// the launch scheduler no longer admits the launches
+		launch.Return()
+	default:
+		p.launchScheduler.Enqueue(launch)
+	}
+}
+
+// newLaunch creates the launch of a placement for the launch scheduler.
+// The job of the first task of the placement is used for the priority and
+// the fair share of the launch.
+func (p *processor) newLaunch(
+	ctx context.Context,
+	placement *resmgr.Placement,
+) *launchscheduler.Launch {
+	launch := &launchscheduler.Launch{
+		Hostname: placement.GetHostname(),
+		Run: func() {
+			p.processPlacement(ctx, placement)
+		},
+		Return: func() {
+			p.returnPlacement(ctx, placement)
+		},
+	}
+
+	if len(placement.GetTaskIDs()) == 0 {
+		return launch
+	}
+
+	taskID := placement.GetTaskIDs()[0].GetPelotonTaskID().GetValue()
+	jobID, _, err := util.ParseTaskID(taskID)
+	if err != nil {
+		log.WithError(err).
+			WithField("task_id", taskID).
+			Error("failed to parse the task id in placement processor")
+		return launch
+	}
+	launch.JobID = jobID
+
+	cachedJob := p.jobFactory.AddJob(&peloton.JobID{Value: jobID})
+	configCtx, cancel := context.WithTimeout(ctx, _rpcTimeout)
+	defer cancel()
+	config, err := cachedJob.GetConfig(configCtx)
+	if err != nil {
+		log.WithError(err).
+			WithField("job_id", jobID).
+			Warn("failed to get job config, launching with default priority")
+		launch.JobType = cachedJob.GetJobType()
+		return launch
+	}
+	launch.JobType = config.GetType()
+	launch.Priority = config.GetSLA().GetPriority()
+	return launch
+}
+
 func (p *processor) processPlacement(ctx context.Context, placement *resmgr.Placement) {
 	var tasks []*peloton.TaskID
 	for _, t := range placement.GetTaskIDs() {
@@ -215,6 +309,78 @@ func (p *processor) processPlacement(ctx context.Context, placement *resmgr.Plac
 	p.KillResManagerTasks(ctx, skippedTasks)
 }
 
+// returnPlacement returns a placement which is not launched to resource
+// manager. The offers of the placement are released, and the tasks are
+// killed in resource manager and enqueued again to be placed again.
+func (p *processor) returnPlacement(
+	ctx context.Context,
+	placement *resmgr.Placement,
+) {
+	log.WithFields(log.Fields{
+		"hostname":    placement.GetHostname(),
+		"tasks_total": len(placement.GetTaskIDs()),
+	}).Info("returning placement which is not launched")
+
+	if err := p.taskLauncher.TryReturnOffers(
+		ctx, errPlacementNotLaunched, placement); err != nil {
+		log.WithError(err).
+			WithField("placement", placement).
+			Error("Failed to return offers for placement")
+	}
+
+	var tasks []*peloton.TaskID
+	for _, t := range placement.GetTaskIDs() {
+		tasks = append(tasks, t.GetPelotonTaskID())
+	}
+	launchableTasks, skippedTasks, err := p.taskLauncher.GetLaunchableTasks(
+		ctx,
+		tasks,
+		placement.GetHostname(),
+		placement.GetAgentId(),
+		nil,
+	)
+	if err != nil {
+		log.WithError(err).
+			WithField("placement", placement).
+			Error("Failed to get tasks of returned placement")
+		return
+	}
+
+	taskInfos := make(map[string]*launcher.LaunchableTaskInfo)
+	for taskID, launchableTask := range launchableTasks {
+		id, instanceID, err := util.ParseTaskID(taskID)
+		if err != nil {
+			continue
+		}
+		jobID := &peloton.JobID{Value: id}
+		cachedTask, err := p.jobFactory.AddJob(jobID).
+			AddTask(ctx, uint32(instanceID))
+		if err != nil {
+			continue
+		}
+		runtime, err := cachedTask.GetRuntime(ctx)
+		if err != nil {
+			continue
+		}
+		if runtime.GetGoalState() == task.TaskState_KILLED {
+			skippedTasks = append(skippedTasks, &peloton.TaskID{Value: taskID})
+			continue
+		}
+		taskInfos[taskID] = &launcher.LaunchableTaskInfo{
+			TaskInfo: &task.TaskInfo{
+				Runtime:    runtime,
+				Config:     launchableTask.Config,
+				InstanceId: uint32(instanceID),
+				JobId:      jobID,
+			},
+			ConfigAddOn: launchableTask.ConfigAddOn,
+		}
+	}
+
+	p.processSkippedLaunches(ctx, taskInfos)
+	p.KillResManagerTasks(ctx, skippedTasks)
+}
+
 // processSkippedLaunches tries to kill the tasks in resmgr and
 // if the kill goes through enqueue the task into resmgr
 func (p *processor) processSkippedLaunches(
@@ -432,6 +598,9 @@ func (p *processor) Stop() error {
 	}
 
 	p.lifeCycle.Wait()
+	if p.launchScheduler != nil {
+		p.launchScheduler.Stop()
+	}
 	log.Info("placement processor stopped")
 	return nil
 }
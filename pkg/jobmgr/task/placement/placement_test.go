@@ -41,10 +41,13 @@ import (
 	"github.com/uber/peloton/pkg/common/rpc"
 	"github.com/uber/peloton/pkg/common/util"
 	cachedmocks "github.com/uber/peloton/pkg/jobmgr/cached/mocks"
+	cachedtest "github.com/uber/peloton/pkg/jobmgr/cached/test"
 	jobmgrcommon "github.com/uber/peloton/pkg/jobmgr/common"
 	goalstatemocks "github.com/uber/peloton/pkg/jobmgr/goalstate/mocks"
 	"github.com/uber/peloton/pkg/jobmgr/task/launcher"
 	launchermocks "github.com/uber/peloton/pkg/jobmgr/task/launcher/mocks"
+	"github.com/uber/peloton/pkg/jobmgr/task/launchscheduler"
+	launchschedulermocks "github.com/uber/peloton/pkg/jobmgr/task/launchscheduler/mocks"
 )
 
 const (
@@ -780,6 +783,154 @@ func (suite *PlacementTestSuite) TestTaskPlacementProcessorProcessNormal() {
 	time.Sleep(time.Second)
 }
 
+// TestTaskPlacementProcessorProcessLaunchScheduler tests that placements
+// are enqueued to the launch scheduler if it is enabled
+func (suite *PlacementTestSuite) TestTaskPlacementProcessorProcessLaunchScheduler() {
+	launchScheduler := launchschedulermocks.NewMockScheduler(suite.ctrl)
+	suite.pp.launchScheduler = launchScheduler
+
+	testTask, _ := createTestTask(0)
+	hostOffer := createHostOffer(0, createResources(float64(1)))
+	placements := []*resmgr.Placement{createPlacements(testTask, hostOffer)}
+
+	launchScheduler.EXPECT().Start().Return(nil)
+	suite.resMgrClient.EXPECT().
+		GetPlacements(gomock.Any(), gomock.Any()).
+		Return(&resmgrsvc.GetPlacementsResponse{Placements: placements}, nil)
+	suite.jobFactory.EXPECT().
+		AddJob(testTask.JobId).
+		Return(suite.cachedJob)
+	suite.cachedJob.EXPECT().
+		GetConfig(gomock.Any()).
+		Return(cachedtest.NewMockJobConfig(suite.ctrl, &job.JobConfig{
+			Type: job.JobType_SERVICE,
+			SLA:  &job.SlaConfig{Priority: 10},
+		}), nil)
+	enqueued := make(chan struct{})
+	launchScheduler.EXPECT().
+		Enqueue(gomock.Any()).
+		Do(func(launch *launchscheduler.Launch) {
+			defer close(enqueued)
+			suite.Equal(_testJobID, launch.JobID)
+			suite.Equal(job.JobType_SERVICE, launch.JobType)
+			suite.Equal(uint32(10), launch.Priority)
+			suite.Equal(hostOffer.GetHostname(), launch.Hostname)
+			suite.NotNil(launch.Run)
+			suite.NotNil(launch.Return)
+		})
+	launchScheduler.EXPECT().Stop().Return(nil)
+
+	// start the lifeCycle and the launch scheduler only, otherwise
+	// pp.run would also be called and make test hard
+	suite.pp.lifeCycle.Start()
+	suite.NoError(suite.pp.launchScheduler.Start())
+	suite.pp.process()
+	// the launch is enqueued asynchronously
+	select {
+	case <-enqueued:
+	case <-time.After(time.Second):
+		suite.Fail("placement is not enqueued to the launch scheduler")
+	}
+	go suite.pp.lifeCycle.StopComplete()
+	suite.NoError(suite.pp.Stop())
+}
+
+// TestTaskPlacementProcessorStartLaunchSchedulerError tests that the
+// processor fails to start if the launch scheduler fails to start
+func (suite *PlacementTestSuite) TestTaskPlacementProcessorStartLaunchSchedulerError() {
+	launchScheduler := launchschedulermocks.NewMockScheduler(suite.ctrl)
+	suite.pp.launchScheduler = launchScheduler
+
+	launchScheduler.EXPECT().Start().Return(fmt.Errorf("test error"))
+	suite.Error(suite.pp.Start())
+	suite.NoError(suite.pp.Stop())
+}
+
+// TestReturnPlacement tests returning a placement which is not launched
+// by the launch scheduler
+func (suite *PlacementTestSuite) TestReturnPlacement() {
+	testTask, runtimeDiff := createTestTask(0)
+	hostOffer := createHostOffer(0, createResources(float64(1)))
+	p := createPlacements(testTask, hostOffer)
+	taskID := &peloton.TaskID{
+		Value: testTask.JobId.Value + "-" + fmt.Sprint(testTask.InstanceId),
+	}
+
+	gomock.InOrder(
+		suite.taskLauncher.EXPECT().
+			TryReturnOffers(gomock.Any(), errPlacementNotLaunched, p).
+			Return(nil),
+		suite.taskLauncher.EXPECT().
+			GetLaunchableTasks(
+				gomock.Any(), []*peloton.TaskID{taskID}, p.Hostname, p.AgentId, gomock.Nil()).
+			Return(
+				map[string]*launcher.LaunchableTask{
+					taskID.Value: {
+						RuntimeDiff: runtimeDiff,
+						Config:      testTask.Config,
+					},
+				},
+				nil,
+				nil),
+		suite.jobFactory.EXPECT().
+			AddJob(testTask.JobId).Return(suite.cachedJob),
+		suite.cachedJob.EXPECT().
+			AddTask(gomock.Any(), uint32(0)).
+			Return(suite.cachedTask, nil),
+		suite.cachedTask.EXPECT().
+			GetRuntime(gomock.Any()).Return(testTask.Runtime, nil),
+		suite.resMgrClient.EXPECT().
+			KillTasks(gomock.Any(), &resmgrsvc.KillTasksRequest{
+				Tasks: []*peloton.TaskID{taskID},
+			}).
+			Return(&resmgrsvc.KillTasksResponse{}, nil),
+		suite.jobFactory.EXPECT().
+			AddJob(testTask.JobId).Return(suite.cachedJob),
+		suite.cachedJob.EXPECT().
+			PatchTasks(gomock.Any(), gomock.Any()).Return(nil),
+		suite.goalStateDriver.EXPECT().
+			EnqueueTask(testTask.JobId, testTask.InstanceId, gomock.Any()).Return(),
+		suite.cachedJob.EXPECT().GetJobType().Return(job.JobType_BATCH),
+		suite.goalStateDriver.EXPECT().
+			JobRuntimeDuration(job.JobType_BATCH).
+			Return(1*time.Second),
+		suite.goalStateDriver.EXPECT().
+			EnqueueJob(testTask.JobId, gomock.Any()).Return(),
+	)
+
+	suite.pp.returnPlacement(context.Background(), p)
+}
+
+// TestNewLaunchConfigError tests creating the launch of a placement
+// whose job config cannot be fetched
+func (suite *PlacementTestSuite) TestNewLaunchConfigError() {
+	testTask, _ := createTestTask(0)
+	hostOffer := createHostOffer(0, createResources(float64(1)))
+	placement := createPlacements(testTask, hostOffer)
+
+	suite.jobFactory.EXPECT().
+		AddJob(testTask.JobId).
+		Return(suite.cachedJob)
+	suite.cachedJob.EXPECT().
+		GetConfig(gomock.Any()).
+		Return(nil, fmt.Errorf("test error"))
+	suite.cachedJob.EXPECT().
+		GetJobType().
+		Return(job.JobType_BATCH)
+
+	launch := suite.pp.newLaunch(context.Background(), placement)
+	suite.Equal(_testJobID, launch.JobID)
+	suite.Equal(job.JobType_BATCH, launch.JobType)
+	suite.Equal(uint32(0), launch.Priority)
+
+	// placement without tasks
+	launch = suite.pp.newLaunch(context.Background(), &resmgr.Placement{
+		Hostname: hostOffer.GetHostname(),
+	})
+	suite.Empty(launch.JobID)
+	suite.Equal(hostOffer.GetHostname(), launch.Hostname)
+}
+
 func (suite *PlacementTestSuite) TestInitPlacementProcessor() {
 	t := rpc.NewTransport()
 	outbounds := yarpc.Outbounds{
@@ -809,6 +960,19 @@ func (suite *PlacementTestSuite) TestInitPlacementProcessor() {
 	suite.NotNil(pp.metrics)
 	suite.NotNil(pp.lifeCycle)
 	suite.NotNil(pp.resMgrClient)
+	suite.Nil(pp.launchScheduler)
+
+	suite.config.LaunchScheduler.Enabled = true
+	pp = InitProcessor(
+		dispatcher,
+		"testClient",
+		suite.jobFactory,
+		suite.goalStateDriver,
+		suite.taskLauncher,
+		suite.config,
+		suite.scope,
+	).(*processor)
+	suite.NotNil(pp.launchScheduler)
 }
 
 // createPlacements creates the placement
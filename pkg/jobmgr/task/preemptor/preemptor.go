@@ -316,7 +316,8 @@ func getRuntimeDiffForPreempt(
 		switch taskReason {
 		case resmgr.PreemptionReason_PREEMPTION_REASON_HOST_MAINTENANCE:
 			tsReason = pbtask.TerminationStatus_TERMINATION_STATUS_REASON_KILLED_HOST_MAINTENANCE
-		case resmgr.PreemptionReason_PREEMPTION_REASON_REVOKE_RESOURCES:
+		case resmgr.PreemptionReason_PREEMPTION_REASON_REVOKE_RESOURCES,
+			resmgr.PreemptionReason_PREEMPTION_REASON_PRIORITY:
 			tsReason = pbtask.TerminationStatus_TERMINATION_STATUS_REASON_PREEMPTED_RESOURCES
 		}
 		runtimeDiff[jobmgrcommon.TerminationStatusField] =
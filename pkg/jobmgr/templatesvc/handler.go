@@ -0,0 +1,564 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templatesvc
+
+import (
+	"context"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless"
+	statelesssvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless/svc"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template/svc"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+
+	"github.com/uber/peloton/pkg/common/leader"
+	yarpcutil "github.com/uber/peloton/pkg/common/util/yarpc"
+	ormobjects "github.com/uber/peloton/pkg/storage/objects"
+
+	"github.com/gocql/gocql"
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/uber-go/tally"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+var (
+	errTemplateNotFound       = yarpcerrors.NotFoundErrorf("template not found")
+	errTemplateAlreadyExists  = yarpcerrors.AlreadyExistsErrorf("template already exists")
+	errInvalidTemplateVersion = yarpcerrors.AbortedErrorf("invalid template version")
+	errJobNotFound            = yarpcerrors.NotFoundErrorf("job not found")
+)
+
+// serviceHandler implements peloton.api.v1alpha.job.template.svc.TemplateService
+type serviceHandler struct {
+	metrics             *Metrics
+	templateOps         ormobjects.JobTemplateOps
+	templateInstanceOps ormobjects.JobTemplateInstanceOps
+	jobSvc              statelesssvc.JobServiceYARPCServer
+	batchJobSvc         job.JobManagerYARPCServer
+	candidate           leader.Candidate
+}
+
+// InitServiceHandler initializes the Template Service Handler. Stateless
+// jobs are created and replaced through the stateless job service handler
+// jobSvc, and batch jobs are created through the job manager batchJobSvc.
+func InitServiceHandler(
+	d *yarpc.Dispatcher,
+	parent tally.Scope,
+	ormStore *ormobjects.Store,
+	jobSvc statelesssvc.JobServiceYARPCServer,
+	batchJobSvc job.JobManagerYARPCServer,
+	candidate leader.Candidate,
+) {
+	handler := &serviceHandler{
+		metrics:             NewMetrics(parent),
+		templateOps:         ormobjects.NewJobTemplateOps(ormStore),
+		templateInstanceOps: ormobjects.NewJobTemplateInstanceOps(ormStore),
+		jobSvc:              jobSvc,
+		batchJobSvc:         batchJobSvc,
+		candidate:           candidate,
+	}
+	d.Register(svc.BuildTemplateServiceYARPCProcedures(handler))
+}
+
+// getTemplate gets a template version, and converts the storage
+// not found error to a yarpc error.
+func (h *serviceHandler) getTemplate(
+	ctx context.Context,
+	name string,
+	version uint64,
+) (*template.TemplateInfo, error) {
+	info, err := h.templateOps.Get(ctx, name, version)
+	if err == gocql.ErrNotFound {
+		return nil, errTemplateNotFound
+	}
+	return info, err
+}
+
+// renderRollout renders the job spec of a job instantiated from a
+// template for a new template version. The parameters the job was
+// instantiated with are validated against the new version.
+func renderRollout(
+	spec *template.TemplateSpec,
+	version uint64,
+	ref *template.TemplateReference,
+) (*stateless.JobSpec, *template.TemplateReference, error) {
+	params, err := resolveParameters(spec, ref.GetParameters())
+	if err != nil {
+		return nil, nil, err
+	}
+	jobSpec, err := renderJobSpec(spec, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newRef := &template.TemplateReference{
+		Name:       spec.GetName(),
+		Version:    version,
+		Parameters: params,
+	}
+	addTemplateLabels(jobSpec, newRef)
+	return jobSpec, newRef, nil
+}
+
+// CreateTemplate implements TemplateService.CreateTemplate.
+func (h *serviceHandler) CreateTemplate(
+	ctx context.Context,
+	req *svc.CreateTemplateRequest,
+) (resp *svc.CreateTemplateResponse, err error) {
+	h.metrics.CreateTemplateAPI.Inc(1)
+	defer func() {
+		if err != nil {
+			log.WithField("name", req.GetSpec().GetName()).
+				WithError(err).
+				Warn("TemplateSVC.CreateTemplate failed")
+			h.metrics.CreateTemplateFail.Inc(1)
+			err = yarpcutil.ConvertToYARPCError(err)
+			return
+		}
+		log.WithField("name", req.GetSpec().GetName()).
+			Info("TemplateSVC.CreateTemplate succeeded")
+		h.metrics.CreateTemplate.Inc(1)
+	}()
+
+	if !h.candidate.IsLeader() {
+		return nil, yarpcerrors.UnavailableErrorf(
+			"TemplateSVC.CreateTemplate is not supported on non-leader")
+	}
+
+	if err := validateTemplateSpec(req.GetSpec()); err != nil {
+		return nil, err
+	}
+
+	if _, err := h.getTemplate(ctx, req.GetSpec().GetName(), 0); err == nil {
+		return nil, errTemplateAlreadyExists
+	} else if !yarpcerrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "failed to get template")
+	}
+
+	if err := h.templateOps.Create(ctx, req.GetSpec(), 1); err != nil {
+		if yarpcerrors.IsAlreadyExists(err) {
+			return nil, errTemplateAlreadyExists
+		}
+		return nil, errors.Wrap(err, "failed to create template")
+	}
+
+	return &svc.CreateTemplateResponse{Version: 1}, nil
+}
+
+// UpdateTemplate implements TemplateService.UpdateTemplate.
+func (h *serviceHandler) UpdateTemplate(
+	ctx context.Context,
+	req *svc.UpdateTemplateRequest,
+) (resp *svc.UpdateTemplateResponse, err error) {
+	h.metrics.UpdateTemplateAPI.Inc(1)
+	defer func() {
+		if err != nil {
+			log.WithField("name", req.GetSpec().GetName()).
+				WithField("version", req.GetVersion()).
+				WithError(err).
+				Warn("TemplateSVC.UpdateTemplate failed")
+			h.metrics.UpdateTemplateFail.Inc(1)
+			err = yarpcutil.ConvertToYARPCError(err)
+			return
+		}
+		log.WithField("name", req.GetSpec().GetName()).
+			WithField("response", resp).
+			Info("TemplateSVC.UpdateTemplate succeeded")
+		h.metrics.UpdateTemplate.Inc(1)
+	}()
+
+	if !h.candidate.IsLeader() {
+		return nil, yarpcerrors.UnavailableErrorf(
+			"TemplateSVC.UpdateTemplate is not supported on non-leader")
+	}
+
+	spec := req.GetSpec()
+	if err := validateTemplateSpec(spec); err != nil {
+		return nil, err
+	}
+	if req.GetRollout() &&
+		spec.GetKind() == template.TemplateKind_TEMPLATE_KIND_BATCH {
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"rollout is not supported for batch templates")
+	}
+
+	latest, err := h.getTemplate(ctx, spec.GetName(), 0)
+	if err != nil {
+		return nil, err
+	}
+	if latest.GetVersion() != req.GetVersion() {
+		return nil, errInvalidTemplateVersion
+	}
+	// the jobs instantiated from the template are of the template kind
+	if latest.GetSpec().GetKind() != spec.GetKind() {
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"cannot change the kind of template from %s to %s",
+			latest.GetSpec().GetKind(), spec.GetKind())
+	}
+
+	version := latest.GetVersion() + 1
+
+	// Validate the parameters of the jobs of the template against the new
+	// version before creating it, so that a version which cannot be rolled
+	// out to all the jobs is rejected.
+	var refs map[string]*template.TemplateReference
+	if req.GetRollout() {
+		refs, err = h.templateInstanceOps.GetAll(ctx, spec.GetName())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get jobs of template")
+		}
+		for jobID, ref := range refs {
+			if _, _, err := renderRollout(spec, version, ref); err != nil {
+				return nil, yarpcerrors.InvalidArgumentErrorf(
+					"parameters of job %s are invalid for the new version: %s",
+					jobID, yarpcerrors.FromError(err).Message())
+			}
+		}
+	}
+
+	if err := h.templateOps.Create(ctx, spec, version); err != nil {
+		// another update created the version concurrently
+		if yarpcerrors.IsAlreadyExists(err) {
+			return nil, errInvalidTemplateVersion
+		}
+		return nil, errors.Wrap(err, "failed to create template version")
+	}
+
+	resp = &svc.UpdateTemplateResponse{Version: version}
+	if !req.GetRollout() {
+		return resp, nil
+	}
+
+	for jobID, ref := range refs {
+		id := &v1alphapeloton.JobID{Value: jobID}
+		err := h.rolloutJob(ctx, id, spec, version, ref, req)
+		if err == errJobNotFound {
+			continue
+		}
+		if err != nil {
+			log.WithField("job_id", jobID).
+				WithField("name", spec.GetName()).
+				WithField("version", version).
+				WithError(err).
+				Warn("failed to roll out template version to job")
+			h.metrics.RolloutJobFail.Inc(1)
+			resp.FailedJobs = append(resp.FailedJobs, id)
+			continue
+		}
+		h.metrics.RolloutJob.Inc(1)
+		resp.RolledOutJobs = append(resp.RolledOutJobs, id)
+	}
+	return resp, nil
+}
+
+// rolloutJob replaces the spec of a job instantiated from a template with
+// the spec rendered from a new template version, using the parameters the
+// job was instantiated with. Returns errJobNotFound if the job no longer
+// exists.
+func (h *serviceHandler) rolloutJob(
+	ctx context.Context,
+	jobID *v1alphapeloton.JobID,
+	spec *template.TemplateSpec,
+	version uint64,
+	ref *template.TemplateReference,
+	req *svc.UpdateTemplateRequest,
+) error {
+	jobSpec, newRef, err := renderRollout(spec, version, ref)
+	if err != nil {
+		return err
+	}
+
+	getResp, err := h.jobSvc.GetJob(ctx, &statelesssvc.GetJobRequest{
+		JobId: jobID,
+	})
+	if yarpcerrors.IsNotFound(err) {
+		// the job has been deleted, or failed to be created
+		if err := h.templateInstanceOps.Delete(
+			ctx, spec.GetName(), jobID); err != nil {
+			return errors.Wrap(err, "failed to delete job of template")
+		}
+		return errJobNotFound
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to get job")
+	}
+	// the resource pool of a job cannot be changed by an update
+	jobSpec.RespoolId = getResp.GetJobInfo().GetSpec().GetRespoolId()
+
+	if _, err := h.jobSvc.ReplaceJob(ctx, &statelesssvc.ReplaceJobRequest{
+		JobId:      jobID,
+		Version:    getResp.GetJobInfo().GetStatus().GetVersion(),
+		Spec:       jobSpec,
+		UpdateSpec: req.GetUpdateSpec(),
+	}); err != nil {
+		return errors.Wrap(err, "failed to replace job")
+	}
+
+	return h.templateInstanceOps.Create(ctx, jobID, newRef)
+}
+
+// GetTemplate implements TemplateService.GetTemplate.
+func (h *serviceHandler) GetTemplate(
+	ctx context.Context,
+	req *svc.GetTemplateRequest,
+) (resp *svc.GetTemplateResponse, err error) {
+	h.metrics.GetTemplateAPI.Inc(1)
+	defer func() {
+		if err != nil {
+			log.WithField("name", req.GetName()).
+				WithField("version", req.GetVersion()).
+				WithError(err).
+				Warn("TemplateSVC.GetTemplate failed")
+			h.metrics.GetTemplateFail.Inc(1)
+			err = yarpcutil.ConvertToYARPCError(err)
+			return
+		}
+		log.WithField("name", req.GetName()).
+			WithField("version", req.GetVersion()).
+			Debug("TemplateSVC.GetTemplate succeeded")
+		h.metrics.GetTemplate.Inc(1)
+	}()
+
+	info, err := h.getTemplate(ctx, req.GetName(), req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	return &svc.GetTemplateResponse{Template: info}, nil
+}
+
+// ListTemplates implements TemplateService.ListTemplates.
+func (h *serviceHandler) ListTemplates(
+	ctx context.Context,
+	req *svc.ListTemplatesRequest,
+) (resp *svc.ListTemplatesResponse, err error) {
+	h.metrics.ListTemplatesAPI.Inc(1)
+	defer func() {
+		if err != nil {
+			log.WithError(err).
+				Warn("TemplateSVC.ListTemplates failed")
+			h.metrics.ListTemplatesFail.Inc(1)
+			err = yarpcutil.ConvertToYARPCError(err)
+			return
+		}
+		log.Debug("TemplateSVC.ListTemplates succeeded")
+		h.metrics.ListTemplates.Inc(1)
+	}()
+
+	infos, err := h.templateOps.GetAll(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get templates")
+	}
+	return &svc.ListTemplatesResponse{Templates: infos}, nil
+}
+
+// DeleteTemplate implements TemplateService.DeleteTemplate.
+func (h *serviceHandler) DeleteTemplate(
+	ctx context.Context,
+	req *svc.DeleteTemplateRequest,
+) (resp *svc.DeleteTemplateResponse, err error) {
+	h.metrics.DeleteTemplateAPI.Inc(1)
+	defer func() {
+		if err != nil {
+			log.WithField("name", req.GetName()).
+				WithError(err).
+				Warn("TemplateSVC.DeleteTemplate failed")
+			h.metrics.DeleteTemplateFail.Inc(1)
+			err = yarpcutil.ConvertToYARPCError(err)
+			return
+		}
+		log.WithField("name", req.GetName()).
+			Info("TemplateSVC.DeleteTemplate succeeded")
+		h.metrics.DeleteTemplate.Inc(1)
+	}()
+
+	if !h.candidate.IsLeader() {
+		return nil, yarpcerrors.UnavailableErrorf(
+			"TemplateSVC.DeleteTemplate is not supported on non-leader")
+	}
+
+	if _, err := h.getTemplate(ctx, req.GetName(), 0); err != nil {
+		return nil, err
+	}
+
+	refs, err := h.templateInstanceOps.GetAll(ctx, req.GetName())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get jobs of template")
+	}
+	for jobID := range refs {
+		if err := h.templateInstanceOps.Delete(
+			ctx,
+			req.GetName(),
+			&v1alphapeloton.JobID{Value: jobID},
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to delete job of template")
+		}
+	}
+
+	if err := h.templateOps.Delete(ctx, req.GetName()); err != nil {
+		return nil, errors.Wrap(err, "failed to delete template")
+	}
+	return &svc.DeleteTemplateResponse{}, nil
+}
+
+// InstantiateTemplate implements TemplateService.InstantiateTemplate.
+func (h *serviceHandler) InstantiateTemplate(
+	ctx context.Context,
+	req *svc.InstantiateTemplateRequest,
+) (resp *svc.InstantiateTemplateResponse, err error) {
+	h.metrics.InstantiateTemplateAPI.Inc(1)
+	defer func() {
+		if err != nil {
+			log.WithField("name", req.GetName()).
+				WithField("version", req.GetVersion()).
+				WithField("job_id", req.GetJobId().GetValue()).
+				WithError(err).
+				Warn("TemplateSVC.InstantiateTemplate failed")
+			h.metrics.InstantiateTemplateFail.Inc(1)
+			err = yarpcutil.ConvertToYARPCError(err)
+			return
+		}
+		log.WithField("name", req.GetName()).
+			WithField("response", resp).
+			Info("TemplateSVC.InstantiateTemplate succeeded")
+		h.metrics.InstantiateTemplate.Inc(1)
+	}()
+
+	if !h.candidate.IsLeader() {
+		return nil, yarpcerrors.UnavailableErrorf(
+			"TemplateSVC.InstantiateTemplate is not supported on non-leader")
+	}
+
+	info, err := h.getTemplate(ctx, req.GetName(), req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := resolveParameters(info.GetSpec(), req.GetParameters())
+	if err != nil {
+		return nil, err
+	}
+
+	if info.GetSpec().GetKind() == template.TemplateKind_TEMPLATE_KIND_BATCH {
+		return h.instantiateBatchJob(ctx, info, params, req)
+	}
+
+	jobSpec, err := renderJobSpec(info.GetSpec(), params)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := &template.TemplateReference{
+		Name:       info.GetSpec().GetName(),
+		Version:    info.GetVersion(),
+		Parameters: params,
+	}
+	addTemplateLabels(jobSpec, ref)
+	if len(req.GetRespoolId().GetValue()) != 0 {
+		jobSpec.RespoolId = req.GetRespoolId()
+	}
+
+	jobID := req.GetJobId()
+	if len(jobID.GetValue()) == 0 {
+		jobID = &v1alphapeloton.JobID{Value: uuid.New()}
+	}
+
+	createResp, err := h.jobSvc.CreateJob(ctx, &statelesssvc.CreateJobRequest{
+		JobId:      jobID,
+		Spec:       jobSpec,
+		CreateSpec: req.GetCreateSpec(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the job only once it is created, so that template rollouts
+	// never replace a job which was not created from the template.
+	if err := h.templateInstanceOps.Create(ctx, jobID, ref); err != nil {
+		return nil, errors.Wrap(err, "failed to record job of template")
+	}
+
+	return &svc.InstantiateTemplateResponse{
+		JobId:    createResp.GetJobId(),
+		Version:  createResp.GetVersion(),
+		Template: ref,
+	}, nil
+}
+
+// instantiateBatchJob creates a batch job from a batch template version
+// with the resolved parameter values.
+func (h *serviceHandler) instantiateBatchJob(
+	ctx context.Context,
+	info *template.TemplateInfo,
+	params map[string]string,
+	req *svc.InstantiateTemplateRequest,
+) (*svc.InstantiateTemplateResponse, error) {
+	jobConfig, err := renderJobConfig(info.GetSpec(), params)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := &template.TemplateReference{
+		Name:       info.GetSpec().GetName(),
+		Version:    info.GetVersion(),
+		Parameters: params,
+	}
+	addJobConfigTemplateLabels(jobConfig, ref)
+	if len(req.GetRespoolId().GetValue()) != 0 {
+		jobConfig.RespoolID = &peloton.ResourcePoolID{
+			Value: req.GetRespoolId().GetValue(),
+		}
+	}
+
+	jobID := req.GetJobId()
+	if len(jobID.GetValue()) == 0 {
+		jobID = &v1alphapeloton.JobID{Value: uuid.New()}
+	}
+
+	createResp, err := h.batchJobSvc.Create(ctx, &job.CreateRequest{
+		Id:     &peloton.JobID{Value: jobID.GetValue()},
+		Config: jobConfig,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if createErr := createResp.GetError(); createErr != nil {
+		switch {
+		case createErr.GetAlreadyExists() != nil:
+			return nil, yarpcerrors.AlreadyExistsErrorf("%s",
+				createErr.GetAlreadyExists().GetMessage())
+		case createErr.GetInvalidConfig() != nil:
+			return nil, yarpcerrors.InvalidArgumentErrorf("%s",
+				createErr.GetInvalidConfig().GetMessage())
+		case createErr.GetInvalidJobId() != nil:
+			return nil, yarpcerrors.InvalidArgumentErrorf("%s",
+				createErr.GetInvalidJobId().GetMessage())
+		}
+		return nil, yarpcerrors.InternalErrorf("failed to create job")
+	}
+
+	if err := h.templateInstanceOps.Create(ctx, jobID, ref); err != nil {
+		return nil, errors.Wrap(err, "failed to record job of template")
+	}
+
+	return &svc.InstantiateTemplateResponse{
+		JobId:    &v1alphapeloton.JobID{Value: createResp.GetJobId().GetValue()},
+		Template: ref,
+	}, nil
+}
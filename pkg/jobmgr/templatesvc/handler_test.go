@@ -0,0 +1,582 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templatesvc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless"
+	statelesssvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless/svc"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template/svc"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+
+	jobmocks "github.com/uber/peloton/.gen/peloton/api/v0/job/mocks"
+	statelesssvcmocks "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless/svc/mocks"
+	leadermocks "github.com/uber/peloton/pkg/common/leader/mocks"
+	objectmocks "github.com/uber/peloton/pkg/storage/objects/mocks"
+
+	"github.com/gocql/gocql"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+const (
+	testJobID  = "481d565e-28da-457d-8434-f6bb7faa0e95"
+	testJobID2 = "941ff353-ba82-49fe-8f80-fb5bc649b04d"
+)
+
+type templateHandlerTestSuite struct {
+	suite.Suite
+
+	ctx                 context.Context
+	ctrl                *gomock.Controller
+	templateOps         *objectmocks.MockJobTemplateOps
+	templateInstanceOps *objectmocks.MockJobTemplateInstanceOps
+	jobSvc              *statelesssvcmocks.MockJobServiceYARPCServer
+	batchJobSvc         *jobmocks.MockJobManagerYARPCServer
+	candidate           *leadermocks.MockCandidate
+	handler             *serviceHandler
+}
+
+func TestTemplateServiceHandler(t *testing.T) {
+	suite.Run(t, new(templateHandlerTestSuite))
+}
+
+func (suite *templateHandlerTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.templateOps = objectmocks.NewMockJobTemplateOps(suite.ctrl)
+	suite.templateInstanceOps = objectmocks.NewMockJobTemplateInstanceOps(suite.ctrl)
+	suite.jobSvc = statelesssvcmocks.NewMockJobServiceYARPCServer(suite.ctrl)
+	suite.batchJobSvc = jobmocks.NewMockJobManagerYARPCServer(suite.ctrl)
+	suite.candidate = leadermocks.NewMockCandidate(suite.ctrl)
+	suite.handler = &serviceHandler{
+		metrics:             NewMetrics(tally.NoopScope),
+		templateOps:         suite.templateOps,
+		templateInstanceOps: suite.templateInstanceOps,
+		jobSvc:              suite.jobSvc,
+		batchJobSvc:         suite.batchJobSvc,
+		candidate:           suite.candidate,
+	}
+	suite.candidate.EXPECT().IsLeader().Return(true).AnyTimes()
+}
+
+func (suite *templateHandlerTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+// TestCreateTemplate tests creating a template
+func (suite *templateHandlerTestSuite) TestCreateTemplate() {
+	spec := newTestTemplateSpec()
+
+	suite.templateOps.EXPECT().
+		Get(gomock.Any(), spec.GetName(), uint64(0)).
+		Return(nil, gocql.ErrNotFound)
+	suite.templateOps.EXPECT().
+		Create(gomock.Any(), spec, uint64(1)).
+		Return(nil)
+
+	resp, err := suite.handler.CreateTemplate(
+		suite.ctx,
+		&svc.CreateTemplateRequest{Spec: spec},
+	)
+	suite.NoError(err)
+	suite.Equal(uint64(1), resp.GetVersion())
+}
+
+// TestCreateTemplateAlreadyExists tests creating a template which
+// already exists
+func (suite *templateHandlerTestSuite) TestCreateTemplateAlreadyExists() {
+	spec := newTestTemplateSpec()
+
+	suite.templateOps.EXPECT().
+		Get(gomock.Any(), spec.GetName(), uint64(0)).
+		Return(&template.TemplateInfo{Spec: spec, Version: 1}, nil)
+
+	_, err := suite.handler.CreateTemplate(
+		suite.ctx,
+		&svc.CreateTemplateRequest{Spec: spec},
+	)
+	suite.True(yarpcerrors.IsAlreadyExists(err))
+}
+
+// TestCreateTemplateInvalidSpec tests creating a template with
+// invalid spec
+func (suite *templateHandlerTestSuite) TestCreateTemplateInvalidSpec() {
+	spec := newTestTemplateSpec()
+	spec.Name = ""
+
+	_, err := suite.handler.CreateTemplate(
+		suite.ctx,
+		&svc.CreateTemplateRequest{Spec: spec},
+	)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestUpdateTemplateInvalidVersion tests updating a template with
+// a stale version
+func (suite *templateHandlerTestSuite) TestUpdateTemplateInvalidVersion() {
+	spec := newTestTemplateSpec()
+
+	suite.templateOps.EXPECT().
+		Get(gomock.Any(), spec.GetName(), uint64(0)).
+		Return(&template.TemplateInfo{Spec: spec, Version: 2}, nil)
+
+	_, err := suite.handler.UpdateTemplate(
+		suite.ctx,
+		&svc.UpdateTemplateRequest{Spec: spec, Version: 1},
+	)
+	suite.True(yarpcerrors.IsAborted(err))
+}
+
+// TestUpdateTemplateKind tests that the kind of a template cannot be
+// changed, and that batch templates cannot be rolled out
+func (suite *templateHandlerTestSuite) TestUpdateTemplateKind() {
+	spec := newTestBatchTemplateSpec()
+
+	suite.templateOps.EXPECT().
+		Get(gomock.Any(), spec.GetName(), uint64(0)).
+		Return(&template.TemplateInfo{
+			Spec:    newTestTemplateSpec(),
+			Version: 1,
+		}, nil)
+	_, err := suite.handler.UpdateTemplate(
+		suite.ctx,
+		&svc.UpdateTemplateRequest{Spec: spec, Version: 1},
+	)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+
+	_, err = suite.handler.UpdateTemplate(
+		suite.ctx,
+		&svc.UpdateTemplateRequest{Spec: spec, Version: 1, Rollout: true},
+	)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestUpdateTemplateWithRollout tests updating a template and rolling out
+// the new version to the jobs of the template
+func (suite *templateHandlerTestSuite) TestUpdateTemplateWithRollout() {
+	spec := newTestTemplateSpec()
+	params := map[string]string{
+		"environment": "production",
+		"instances":   "5",
+		"cpu":         "0.5",
+		"verbose":     "false",
+	}
+	respoolID := &v1alphapeloton.ResourcePoolID{Value: "respool"}
+	entityVersion := &v1alphapeloton.EntityVersion{Value: "1-1-1"}
+	updateSpec := &stateless.UpdateSpec{BatchSize: 1}
+
+	suite.templateOps.EXPECT().
+		Get(gomock.Any(), spec.GetName(), uint64(0)).
+		Return(&template.TemplateInfo{Spec: spec, Version: 1}, nil)
+	suite.templateOps.EXPECT().
+		Create(gomock.Any(), spec, uint64(2)).
+		Return(nil)
+	suite.templateInstanceOps.EXPECT().
+		GetAll(gomock.Any(), spec.GetName()).
+		Return(map[string]*template.TemplateReference{
+			testJobID: {
+				Name:       spec.GetName(),
+				Version:    1,
+				Parameters: params,
+			},
+			testJobID2: {
+				Name:       spec.GetName(),
+				Version:    1,
+				Parameters: params,
+			},
+		}, nil)
+
+	// the first job is rolled out
+	suite.jobSvc.EXPECT().
+		GetJob(gomock.Any(), &statelesssvc.GetJobRequest{
+			JobId: &v1alphapeloton.JobID{Value: testJobID},
+		}).
+		Return(&statelesssvc.GetJobResponse{
+			JobInfo: &stateless.JobInfo{
+				Spec:   &stateless.JobSpec{RespoolId: respoolID},
+				Status: &stateless.JobStatus{Version: entityVersion},
+			},
+		}, nil)
+	suite.jobSvc.EXPECT().
+		ReplaceJob(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *statelesssvc.ReplaceJobRequest) {
+			suite.Equal(testJobID, req.GetJobId().GetValue())
+			suite.Equal(entityVersion, req.GetVersion())
+			suite.Equal(respoolID, req.GetSpec().GetRespoolId())
+			suite.Equal(uint32(5), req.GetSpec().GetInstanceCount())
+			suite.Equal(updateSpec, req.GetUpdateSpec())
+		}).
+		Return(&statelesssvc.ReplaceJobResponse{}, nil)
+	suite.templateInstanceOps.EXPECT().
+		Create(
+			gomock.Any(),
+			&v1alphapeloton.JobID{Value: testJobID},
+			&template.TemplateReference{
+				Name:       spec.GetName(),
+				Version:    2,
+				Parameters: params,
+			}).
+		Return(nil)
+
+	// the second job has been deleted
+	suite.jobSvc.EXPECT().
+		GetJob(gomock.Any(), &statelesssvc.GetJobRequest{
+			JobId: &v1alphapeloton.JobID{Value: testJobID2},
+		}).
+		Return(nil, yarpcerrors.NotFoundErrorf("job not found"))
+	suite.templateInstanceOps.EXPECT().
+		Delete(
+			gomock.Any(),
+			spec.GetName(),
+			&v1alphapeloton.JobID{Value: testJobID2}).
+		Return(nil)
+
+	resp, err := suite.handler.UpdateTemplate(
+		suite.ctx,
+		&svc.UpdateTemplateRequest{
+			Spec:       spec,
+			Version:    1,
+			Rollout:    true,
+			UpdateSpec: updateSpec,
+		},
+	)
+	suite.NoError(err)
+	suite.Equal(uint64(2), resp.GetVersion())
+	suite.Equal(
+		[]*v1alphapeloton.JobID{{Value: testJobID}},
+		resp.GetRolledOutJobs())
+	suite.Empty(resp.GetFailedJobs())
+}
+
+// TestUpdateTemplateRolloutFailure tests the failure to roll out a new
+// template version to a job
+func (suite *templateHandlerTestSuite) TestUpdateTemplateRolloutFailure() {
+	spec := newTestTemplateSpec()
+
+	suite.templateOps.EXPECT().
+		Get(gomock.Any(), spec.GetName(), uint64(0)).
+		Return(&template.TemplateInfo{Spec: spec, Version: 1}, nil)
+	suite.templateOps.EXPECT().
+		Create(gomock.Any(), spec, uint64(2)).
+		Return(nil)
+	suite.templateInstanceOps.EXPECT().
+		GetAll(gomock.Any(), spec.GetName()).
+		Return(map[string]*template.TemplateReference{
+			testJobID: {
+				Name:       spec.GetName(),
+				Version:    1,
+				Parameters: map[string]string{"environment": "production"},
+			},
+		}, nil)
+	suite.jobSvc.EXPECT().
+		GetJob(gomock.Any(), gomock.Any()).
+		Return(&statelesssvc.GetJobResponse{}, nil)
+	suite.jobSvc.EXPECT().
+		ReplaceJob(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("replace failed"))
+
+	resp, err := suite.handler.UpdateTemplate(
+		suite.ctx,
+		&svc.UpdateTemplateRequest{
+			Spec:    spec,
+			Version: 1,
+			Rollout: true,
+		},
+	)
+	suite.NoError(err)
+	suite.Empty(resp.GetRolledOutJobs())
+	suite.Equal(
+		[]*v1alphapeloton.JobID{{Value: testJobID}},
+		resp.GetFailedJobs())
+}
+
+// TestUpdateTemplateRolloutInvalidParameters tests that a template version
+// is not created when the parameters of a job of the template are invalid
+// for the new version
+func (suite *templateHandlerTestSuite) TestUpdateTemplateRolloutInvalidParameters() {
+	spec := newTestTemplateSpec()
+
+	suite.templateOps.EXPECT().
+		Get(gomock.Any(), spec.GetName(), uint64(0)).
+		Return(&template.TemplateInfo{Spec: spec, Version: 1}, nil)
+	suite.templateInstanceOps.EXPECT().
+		GetAll(gomock.Any(), spec.GetName()).
+		Return(map[string]*template.TemplateReference{
+			testJobID: {
+				Name:    spec.GetName(),
+				Version: 1,
+				Parameters: map[string]string{
+					"environment": "production",
+					"removed":     "value",
+				},
+			},
+		}, nil)
+
+	_, err := suite.handler.UpdateTemplate(
+		suite.ctx,
+		&svc.UpdateTemplateRequest{
+			Spec:    spec,
+			Version: 1,
+			Rollout: true,
+		},
+	)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestGetTemplateNotFound tests getting a template which does not exist
+func (suite *templateHandlerTestSuite) TestGetTemplateNotFound() {
+	suite.templateOps.EXPECT().
+		Get(gomock.Any(), "echo", uint64(3)).
+		Return(nil, gocql.ErrNotFound)
+
+	_, err := suite.handler.GetTemplate(
+		suite.ctx,
+		&svc.GetTemplateRequest{Name: "echo", Version: 3},
+	)
+	suite.True(yarpcerrors.IsNotFound(err))
+}
+
+// TestListTemplates tests listing the templates
+func (suite *templateHandlerTestSuite) TestListTemplates() {
+	infos := []*template.TemplateInfo{
+		{Spec: newTestTemplateSpec(), Version: 1},
+	}
+	suite.templateOps.EXPECT().GetAll(gomock.Any()).Return(infos, nil)
+
+	resp, err := suite.handler.ListTemplates(
+		suite.ctx,
+		&svc.ListTemplatesRequest{},
+	)
+	suite.NoError(err)
+	suite.Equal(infos, resp.GetTemplates())
+}
+
+// TestDeleteTemplate tests deleting a template along with the records of
+// the jobs instantiated from it
+func (suite *templateHandlerTestSuite) TestDeleteTemplate() {
+	spec := newTestTemplateSpec()
+
+	suite.templateOps.EXPECT().
+		Get(gomock.Any(), spec.GetName(), uint64(0)).
+		Return(&template.TemplateInfo{Spec: spec, Version: 1}, nil)
+	suite.templateInstanceOps.EXPECT().
+		GetAll(gomock.Any(), spec.GetName()).
+		Return(map[string]*template.TemplateReference{
+			testJobID: {Name: spec.GetName(), Version: 1},
+		}, nil)
+	suite.templateInstanceOps.EXPECT().
+		Delete(
+			gomock.Any(),
+			spec.GetName(),
+			&v1alphapeloton.JobID{Value: testJobID}).
+		Return(nil)
+	suite.templateOps.EXPECT().
+		Delete(gomock.Any(), spec.GetName()).
+		Return(nil)
+
+	_, err := suite.handler.DeleteTemplate(
+		suite.ctx,
+		&svc.DeleteTemplateRequest{Name: spec.GetName()},
+	)
+	suite.NoError(err)
+}
+
+// TestInstantiateTemplate tests creating a job from a template
+func (suite *templateHandlerTestSuite) TestInstantiateTemplate() {
+	spec := newTestTemplateSpec()
+	jobID := &v1alphapeloton.JobID{Value: testJobID}
+	respoolID := &v1alphapeloton.ResourcePoolID{Value: "respool"}
+	createSpec := &stateless.CreateSpec{BatchSize: 2}
+	ref := &template.TemplateReference{
+		Name:    spec.GetName(),
+		Version: 3,
+		Parameters: map[string]string{
+			"environment": "production",
+			"instances":   "3",
+			"cpu":         "1",
+			"verbose":     "false",
+		},
+	}
+
+	suite.templateOps.EXPECT().
+		Get(gomock.Any(), spec.GetName(), uint64(0)).
+		Return(&template.TemplateInfo{Spec: spec, Version: 3}, nil)
+	suite.templateInstanceOps.EXPECT().
+		Create(gomock.Any(), jobID, ref).
+		Return(nil)
+	suite.jobSvc.EXPECT().
+		CreateJob(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *statelesssvc.CreateJobRequest) {
+			suite.Equal(jobID, req.GetJobId())
+			suite.Equal(createSpec, req.GetCreateSpec())
+			suite.Equal("echo-production", req.GetSpec().GetName())
+			suite.Equal(respoolID, req.GetSpec().GetRespoolId())
+			suite.Equal(1.0, req.GetSpec().GetDefaultSpec().
+				GetContainers()[0].GetResource().GetCpuLimit())
+			suite.Contains(req.GetSpec().GetLabels(), &v1alphapeloton.Label{
+				Key:   templateVersionLabelKey,
+				Value: "3",
+			})
+		}).
+		Return(&statelesssvc.CreateJobResponse{JobId: jobID}, nil)
+
+	resp, err := suite.handler.InstantiateTemplate(
+		suite.ctx,
+		&svc.InstantiateTemplateRequest{
+			Name: spec.GetName(),
+			Parameters: map[string]string{
+				"environment": "production",
+				"cpu":         "1",
+			},
+			JobId:      jobID,
+			RespoolId:  respoolID,
+			CreateSpec: createSpec,
+		},
+	)
+	suite.NoError(err)
+	suite.Equal(jobID, resp.GetJobId())
+	suite.Equal(ref, resp.GetTemplate())
+}
+
+// TestInstantiateTemplateCreateFailure tests that a job which fails to be
+// created from a template is not recorded as a job of the template
+func (suite *templateHandlerTestSuite) TestInstantiateTemplateCreateFailure() {
+	spec := newTestTemplateSpec()
+
+	suite.templateOps.EXPECT().
+		Get(gomock.Any(), spec.GetName(), uint64(0)).
+		Return(&template.TemplateInfo{Spec: spec, Version: 1}, nil)
+	suite.jobSvc.EXPECT().
+		CreateJob(gomock.Any(), gomock.Any()).
+		Return(nil, yarpcerrors.AlreadyExistsErrorf("job already exists"))
+
+	_, err := suite.handler.InstantiateTemplate(
+		suite.ctx,
+		&svc.InstantiateTemplateRequest{
+			Name:       spec.GetName(),
+			Parameters: map[string]string{"environment": "production"},
+			JobId:      &v1alphapeloton.JobID{Value: testJobID},
+		},
+	)
+	suite.True(yarpcerrors.IsAlreadyExists(err))
+}
+
+// TestInstantiateTemplateMissingParameter tests creating a job from a
+// template without providing a required parameter
+func (suite *templateHandlerTestSuite) TestInstantiateTemplateMissingParameter() {
+	spec := newTestTemplateSpec()
+
+	suite.templateOps.EXPECT().
+		Get(gomock.Any(), spec.GetName(), uint64(1)).
+		Return(&template.TemplateInfo{Spec: spec, Version: 1}, nil)
+
+	_, err := suite.handler.InstantiateTemplate(
+		suite.ctx,
+		&svc.InstantiateTemplateRequest{
+			Name:    spec.GetName(),
+			Version: 1,
+		},
+	)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestInstantiateBatchTemplate tests creating a batch job from a
+// batch template
+func (suite *templateHandlerTestSuite) TestInstantiateBatchTemplate() {
+	spec := newTestBatchTemplateSpec()
+	jobID := &v1alphapeloton.JobID{Value: testJobID}
+	ref := &template.TemplateReference{
+		Name:    spec.GetName(),
+		Version: 2,
+		Parameters: map[string]string{
+			"environment": "production",
+			"instances":   "3",
+			"cpu":         "0.5",
+			"verbose":     "false",
+		},
+	}
+
+	suite.templateOps.EXPECT().
+		Get(gomock.Any(), spec.GetName(), uint64(0)).
+		Return(&template.TemplateInfo{Spec: spec, Version: 2}, nil)
+	suite.templateInstanceOps.EXPECT().
+		Create(gomock.Any(), jobID, ref).
+		Return(nil)
+	suite.batchJobSvc.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *job.CreateRequest) {
+			suite.Equal(testJobID, req.GetId().GetValue())
+			suite.Equal(job.JobType_BATCH, req.GetConfig().GetType())
+			suite.Equal("echo-production", req.GetConfig().GetName())
+			suite.Equal(uint32(3), req.GetConfig().GetInstanceCount())
+			suite.Equal("respool", req.GetConfig().GetRespoolID().GetValue())
+			suite.Contains(req.GetConfig().GetLabels(), &peloton.Label{
+				Key:   templateVersionLabelKey,
+				Value: "2",
+			})
+		}).
+		Return(&job.CreateResponse{
+			JobId: &peloton.JobID{Value: testJobID},
+		}, nil)
+
+	resp, err := suite.handler.InstantiateTemplate(
+		suite.ctx,
+		&svc.InstantiateTemplateRequest{
+			Name: spec.GetName(),
+			Parameters: map[string]string{
+				"environment": "production",
+			},
+			JobId:     jobID,
+			RespoolId: &v1alphapeloton.ResourcePoolID{Value: "respool"},
+		},
+	)
+	suite.NoError(err)
+	suite.Equal(jobID, resp.GetJobId())
+	suite.Equal(ref, resp.GetTemplate())
+	suite.Nil(resp.GetVersion())
+
+	// the batch job config is invalid, and the job is not recorded
+	suite.templateOps.EXPECT().
+		Get(gomock.Any(), spec.GetName(), uint64(0)).
+		Return(&template.TemplateInfo{Spec: spec, Version: 2}, nil)
+	suite.batchJobSvc.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(&job.CreateResponse{
+			Error: &job.CreateResponse_Error{
+				InvalidConfig: &job.InvalidJobConfig{Message: "invalid"},
+			},
+		}, nil)
+
+	_, err = suite.handler.InstantiateTemplate(
+		suite.ctx,
+		&svc.InstantiateTemplateRequest{
+			Name: spec.GetName(),
+			Parameters: map[string]string{
+				"environment": "production",
+			},
+			JobId: jobID,
+		},
+	)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templatesvc
+
+import (
+	"github.com/uber-go/tally"
+)
+
+// Metrics is a placeholder for all metrics in template service.
+type Metrics struct {
+	CreateTemplateAPI  tally.Counter
+	CreateTemplate     tally.Counter
+	CreateTemplateFail tally.Counter
+
+	UpdateTemplateAPI  tally.Counter
+	UpdateTemplate     tally.Counter
+	UpdateTemplateFail tally.Counter
+
+	GetTemplateAPI  tally.Counter
+	GetTemplate     tally.Counter
+	GetTemplateFail tally.Counter
+
+	ListTemplatesAPI  tally.Counter
+	ListTemplates     tally.Counter
+	ListTemplatesFail tally.Counter
+
+	DeleteTemplateAPI  tally.Counter
+	DeleteTemplate     tally.Counter
+	DeleteTemplateFail tally.Counter
+
+	InstantiateTemplateAPI  tally.Counter
+	InstantiateTemplate     tally.Counter
+	InstantiateTemplateFail tally.Counter
+
+	RolloutJob     tally.Counter
+	RolloutJobFail tally.Counter
+}
+
+// NewMetrics returns a new instance of templatesvc.Metrics.
+func NewMetrics(scope tally.Scope) *Metrics {
+	subScope := scope.SubScope("template")
+	return &Metrics{
+		CreateTemplateAPI:  subScope.Counter("create_api"),
+		CreateTemplate:     subScope.Counter("create"),
+		CreateTemplateFail: subScope.Counter("create_fail"),
+
+		UpdateTemplateAPI:  subScope.Counter("update_api"),
+		UpdateTemplate:     subScope.Counter("update"),
+		UpdateTemplateFail: subScope.Counter("update_fail"),
+
+		GetTemplateAPI:  subScope.Counter("get_api"),
+		GetTemplate:     subScope.Counter("get"),
+		GetTemplateFail: subScope.Counter("get_fail"),
+
+		ListTemplatesAPI:  subScope.Counter("list_api"),
+		ListTemplates:     subScope.Counter("list"),
+		ListTemplatesFail: subScope.Counter("list_fail"),
+
+		DeleteTemplateAPI:  subScope.Counter("delete_api"),
+		DeleteTemplate:     subScope.Counter("delete"),
+		DeleteTemplateFail: subScope.Counter("delete_fail"),
+
+		InstantiateTemplateAPI:  subScope.Counter("instantiate_api"),
+		InstantiateTemplate:     subScope.Counter("instantiate"),
+		InstantiateTemplateFail: subScope.Counter("instantiate_fail"),
+
+		RolloutJob:     subScope.Counter("rollout_job"),
+		RolloutJobFail: subScope.Counter("rollout_job_fail"),
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templatesvc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+
+	"github.com/uber/peloton/pkg/common"
+
+	"go.uber.org/yarpc/yarpcerrors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// validNameRegex is the regular expression template and
+// parameter names have to match.
+var validNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+var (
+	templateNameLabelKey = fmt.Sprintf(
+		common.SystemLabelKeyTemplate,
+		common.SystemLabelPrefix,
+		common.SystemLabelTemplateName)
+	templateVersionLabelKey = fmt.Sprintf(
+		common.SystemLabelKeyTemplate,
+		common.SystemLabelPrefix,
+		common.SystemLabelTemplateVersion)
+)
+
+// yamlString is the value of a string parameter while rendering the
+// template body. It renders as a double-quoted YAML scalar, so that the
+// value cannot inject YAML into the body. It still compares equal to the
+// unquoted string in the template actions.
+type yamlString string
+
+// String returns the value as a double-quoted YAML scalar. The JSON
+// strings are valid YAML double-quoted scalars.
+func (s yamlString) String() string {
+	buffer, _ := json.Marshal(string(s))
+	return string(buffer)
+}
+
+// concat concatenates the values into one string, which renders as a
+// double-quoted YAML scalar. It is used to embed the string parameters
+// in longer strings, e.g. {{concat "echo-" .environment}}.
+func concat(values ...interface{}) yamlString {
+	var b strings.Builder
+	for _, value := range values {
+		if s, ok := value.(yamlString); ok {
+			b.WriteString(string(s))
+			continue
+		}
+		fmt.Fprint(&b, value)
+	}
+	return yamlString(b.String())
+}
+
+// parseParameterValue converts the string value of a parameter to
+// the Go type used while rendering the template body.
+func parseParameterValue(
+	param *template.ParameterSpec,
+	value string,
+) (interface{}, error) {
+	switch param.GetType() {
+	case template.ParameterType_PARAMETER_TYPE_STRING:
+		return yamlString(value), nil
+	case template.ParameterType_PARAMETER_TYPE_INT:
+		return strconv.ParseInt(value, 10, 64)
+	case template.ParameterType_PARAMETER_TYPE_DOUBLE:
+		return strconv.ParseFloat(value, 64)
+	case template.ParameterType_PARAMETER_TYPE_BOOL:
+		return strconv.ParseBool(value)
+	}
+	return nil, fmt.Errorf("invalid type %s", param.GetType())
+}
+
+// zeroParameterValue returns the zero value of a parameter, which is used
+// to validate the template body for required parameters.
+func zeroParameterValue(param *template.ParameterSpec) string {
+	switch param.GetType() {
+	case template.ParameterType_PARAMETER_TYPE_INT,
+		template.ParameterType_PARAMETER_TYPE_DOUBLE:
+		return "0"
+	case template.ParameterType_PARAMETER_TYPE_BOOL:
+		return "false"
+	}
+	return ""
+}
+
+// validateTemplateSpec validates the template spec, and that its body
+// renders to a job spec, or to a batch job config for a batch template,
+// with the default values of the parameters.
+func validateTemplateSpec(spec *template.TemplateSpec) error {
+	if !validNameRegex.MatchString(spec.GetName()) {
+		return yarpcerrors.InvalidArgumentErrorf(
+			"invalid template name %q", spec.GetName())
+	}
+
+	values := make(map[string]string)
+	for _, param := range spec.GetParameters() {
+		name := param.GetName()
+		if !validNameRegex.MatchString(name) {
+			return yarpcerrors.InvalidArgumentErrorf(
+				"invalid parameter name %q", name)
+		}
+		if _, ok := values[name]; ok {
+			return yarpcerrors.InvalidArgumentErrorf(
+				"duplicate parameter %q", name)
+		}
+		if param.GetType() == template.ParameterType_PARAMETER_TYPE_INVALID {
+			return yarpcerrors.InvalidArgumentErrorf(
+				"parameter %q has invalid type", name)
+		}
+
+		if param.GetRequired() {
+			if len(param.GetDefaultValue()) != 0 {
+				return yarpcerrors.InvalidArgumentErrorf(
+					"required parameter %q cannot have a default value", name)
+			}
+			values[name] = zeroParameterValue(param)
+			continue
+		}
+
+		if _, err := parseParameterValue(
+			param, param.GetDefaultValue()); err != nil {
+			return yarpcerrors.InvalidArgumentErrorf(
+				"invalid default value of parameter %q: %v", name, err)
+		}
+		values[name] = param.GetDefaultValue()
+	}
+
+	switch spec.GetKind() {
+	case template.TemplateKind_TEMPLATE_KIND_STATELESS:
+		_, err := renderJobSpec(spec, values)
+		return err
+	case template.TemplateKind_TEMPLATE_KIND_BATCH:
+		_, err := renderJobConfig(spec, values)
+		return err
+	}
+	return yarpcerrors.InvalidArgumentErrorf(
+		"invalid template kind %s", spec.GetKind())
+}
+
+// resolveParameters validates the parameter values provided to
+// instantiate a template, and fills in the default values of the
+// parameters which are not provided.
+func resolveParameters(
+	spec *template.TemplateSpec,
+	values map[string]string,
+) (map[string]string, error) {
+	params := make(map[string]*template.ParameterSpec)
+	for _, param := range spec.GetParameters() {
+		params[param.GetName()] = param
+	}
+
+	for name := range values {
+		if _, ok := params[name]; !ok {
+			return nil, yarpcerrors.InvalidArgumentErrorf(
+				"unknown parameter %q", name)
+		}
+	}
+
+	result := make(map[string]string)
+	for name, param := range params {
+		value, ok := values[name]
+		if !ok {
+			if param.GetRequired() {
+				return nil, yarpcerrors.InvalidArgumentErrorf(
+					"missing required parameter %q", name)
+			}
+			value = param.GetDefaultValue()
+		}
+		if _, err := parseParameterValue(param, value); err != nil {
+			return nil, yarpcerrors.InvalidArgumentErrorf(
+				"invalid value of parameter %q: %v", name, err)
+		}
+		result[name] = value
+	}
+	return result, nil
+}
+
+// renderBody renders the template body with the resolved parameter values.
+// The string parameters render as double-quoted YAML scalars.
+func renderBody(
+	spec *template.TemplateSpec,
+	values map[string]string,
+) ([]byte, error) {
+	data := make(map[string]interface{})
+	for _, param := range spec.GetParameters() {
+		value, err := parseParameterValue(param, values[param.GetName()])
+		if err != nil {
+			return nil, yarpcerrors.InvalidArgumentErrorf(
+				"invalid value of parameter %q: %v", param.GetName(), err)
+		}
+		data[param.GetName()] = value
+	}
+
+	tmpl, err := texttemplate.New(spec.GetName()).
+		Option("missingkey=error").
+		Funcs(texttemplate.FuncMap{"concat": concat}).
+		Parse(spec.GetBody())
+	if err != nil {
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"failed to parse template body: %v", err)
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"failed to render template body: %v", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// renderJobSpec renders the body of a stateless template with the
+// resolved parameter values and parses the result as a job spec.
+func renderJobSpec(
+	spec *template.TemplateSpec,
+	values map[string]string,
+) (*stateless.JobSpec, error) {
+	body, err := renderBody(spec, values)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobSpec stateless.JobSpec
+	if err := yaml.Unmarshal(body, &jobSpec); err != nil {
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"rendered template body is not a valid job spec: %v", err)
+	}
+	return &jobSpec, nil
+}
+
+// renderJobConfig renders the body of a batch template with the resolved
+// parameter values and parses the result as the config of a batch job.
+func renderJobConfig(
+	spec *template.TemplateSpec,
+	values map[string]string,
+) (*job.JobConfig, error) {
+	body, err := renderBody(spec, values)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobConfig job.JobConfig
+	if err := yaml.Unmarshal(body, &jobConfig); err != nil {
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"rendered template body is not a valid job config: %v", err)
+	}
+	if jobConfig.GetType() != job.JobType_BATCH {
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"batch template cannot render a job of type %s",
+			jobConfig.GetType())
+	}
+	return &jobConfig, nil
+}
+
+// addTemplateLabels records the template reference in the labels of the
+// job spec, replacing the existing template labels if any.
+func addTemplateLabels(
+	jobSpec *stateless.JobSpec,
+	ref *template.TemplateReference,
+) {
+	var labels []*v1alphapeloton.Label
+	for _, label := range jobSpec.GetLabels() {
+		if label.GetKey() == templateNameLabelKey ||
+			label.GetKey() == templateVersionLabelKey {
+			continue
+		}
+		labels = append(labels, label)
+	}
+	jobSpec.Labels = append(labels,
+		&v1alphapeloton.Label{
+			Key:   templateNameLabelKey,
+			Value: ref.GetName(),
+		},
+		&v1alphapeloton.Label{
+			Key:   templateVersionLabelKey,
+			Value: strconv.FormatUint(ref.GetVersion(), 10),
+		},
+	)
+}
+
+// addJobConfigTemplateLabels records the template reference in the labels
+// of a batch job config, replacing the existing template labels if any.
+func addJobConfigTemplateLabels(
+	jobConfig *job.JobConfig,
+	ref *template.TemplateReference,
+) {
+	var labels []*peloton.Label
+	for _, label := range jobConfig.GetLabels() {
+		if label.GetKey() == templateNameLabelKey ||
+			label.GetKey() == templateVersionLabelKey {
+			continue
+		}
+		labels = append(labels, label)
+	}
+	jobConfig.Labels = append(labels,
+		&peloton.Label{
+			Key:   templateNameLabelKey,
+			Value: ref.GetName(),
+		},
+		&peloton.Label{
+			Key:   templateVersionLabelKey,
+			Value: strconv.FormatUint(ref.GetVersion(), 10),
+		},
+	)
+}
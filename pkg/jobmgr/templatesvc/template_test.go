@@ -0,0 +1,303 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templatesvc
+
+import (
+	"testing"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+const _testTemplateBody = `
+name: {{concat "echo-" .environment}}
+instancecount: {{.instances}}
+labels:
+- key: verbose
+  value: "{{.verbose}}"
+defaultspec:
+  containers:
+  - resource:
+      cpulimit: {{.cpu}}
+`
+
+const _testBatchTemplateBody = `
+name: {{concat "echo-" .environment}}
+type: 0
+instancecount: {{.instances}}
+labels:
+- key: verbose
+  value: "{{.verbose}}"
+defaultconfig:
+  resource:
+    cpulimit: {{.cpu}}
+`
+
+type templateTestSuite struct {
+	suite.Suite
+}
+
+func TestTemplate(t *testing.T) {
+	suite.Run(t, new(templateTestSuite))
+}
+
+func newTestTemplateSpec() *template.TemplateSpec {
+	return &template.TemplateSpec{
+		Name: "echo",
+		Parameters: []*template.ParameterSpec{
+			{
+				Name:     "environment",
+				Type:     template.ParameterType_PARAMETER_TYPE_STRING,
+				Required: true,
+			},
+			{
+				Name:         "instances",
+				Type:         template.ParameterType_PARAMETER_TYPE_INT,
+				DefaultValue: "3",
+			},
+			{
+				Name:         "cpu",
+				Type:         template.ParameterType_PARAMETER_TYPE_DOUBLE,
+				DefaultValue: "0.5",
+			},
+			{
+				Name:         "verbose",
+				Type:         template.ParameterType_PARAMETER_TYPE_BOOL,
+				DefaultValue: "false",
+			},
+		},
+		Body: _testTemplateBody,
+	}
+}
+
+func newTestBatchTemplateSpec() *template.TemplateSpec {
+	spec := newTestTemplateSpec()
+	spec.Kind = template.TemplateKind_TEMPLATE_KIND_BATCH
+	spec.Body = _testBatchTemplateBody
+	return spec
+}
+
+// TestValidateTemplateSpec tests validating template specs
+func (suite *templateTestSuite) TestValidateTemplateSpec() {
+	suite.NoError(validateTemplateSpec(newTestTemplateSpec()))
+
+	tt := []struct {
+		msg    string
+		modify func(spec *template.TemplateSpec)
+	}{
+		{
+			msg:    "invalid template name",
+			modify: func(spec *template.TemplateSpec) { spec.Name = "1echo" },
+		},
+		{
+			msg: "invalid parameter name",
+			modify: func(spec *template.TemplateSpec) {
+				spec.Parameters[0].Name = "env-name"
+			},
+		},
+		{
+			msg: "duplicate parameter",
+			modify: func(spec *template.TemplateSpec) {
+				spec.Parameters[1].Name = "environment"
+			},
+		},
+		{
+			msg: "invalid parameter type",
+			modify: func(spec *template.TemplateSpec) {
+				spec.Parameters[1].Type = template.ParameterType_PARAMETER_TYPE_INVALID
+			},
+		},
+		{
+			msg: "required parameter with default value",
+			modify: func(spec *template.TemplateSpec) {
+				spec.Parameters[0].DefaultValue = "production"
+			},
+		},
+		{
+			msg: "default value of wrong type",
+			modify: func(spec *template.TemplateSpec) {
+				spec.Parameters[1].DefaultValue = "three"
+			},
+		},
+		{
+			msg: "body refers to unknown parameter",
+			modify: func(spec *template.TemplateSpec) {
+				spec.Body += "description: {{.unknown}}\n"
+			},
+		},
+		{
+			msg: "body is not a valid template",
+			modify: func(spec *template.TemplateSpec) {
+				spec.Body += "description: {{.environment\n"
+			},
+		},
+		{
+			msg: "body does not render to a job spec",
+			modify: func(spec *template.TemplateSpec) {
+				spec.Body = "instancecount: [1, 2]"
+			},
+		},
+	}
+
+	for _, t := range tt {
+		spec := newTestTemplateSpec()
+		t.modify(spec)
+		err := validateTemplateSpec(spec)
+		suite.Error(err, t.msg)
+		suite.True(yarpcerrors.IsInvalidArgument(err), t.msg)
+	}
+}
+
+// TestResolveParameters tests resolving the parameters of a template
+func (suite *templateTestSuite) TestResolveParameters() {
+	spec := newTestTemplateSpec()
+
+	params, err := resolveParameters(spec, map[string]string{
+		"environment": "production",
+		"instances":   "5",
+	})
+	suite.NoError(err)
+	suite.Equal(map[string]string{
+		"environment": "production",
+		"instances":   "5",
+		"cpu":         "0.5",
+		"verbose":     "false",
+	}, params)
+
+	_, err = resolveParameters(spec, map[string]string{})
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+
+	_, err = resolveParameters(spec, map[string]string{
+		"environment": "production",
+		"unknown":     "value",
+	})
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+
+	_, err = resolveParameters(spec, map[string]string{
+		"environment": "production",
+		"verbose":     "maybe",
+	})
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestRenderJobSpec tests rendering a job spec from a template, and
+// recording the template in the job spec labels
+func (suite *templateTestSuite) TestRenderJobSpec() {
+	spec := newTestTemplateSpec()
+	params, err := resolveParameters(spec, map[string]string{
+		"environment": "staging",
+		"verbose":     "true",
+	})
+	suite.NoError(err)
+
+	jobSpec, err := renderJobSpec(spec, params)
+	suite.NoError(err)
+	suite.Equal("echo-staging", jobSpec.GetName())
+	suite.Equal(uint32(3), jobSpec.GetInstanceCount())
+	suite.Equal(0.5,
+		jobSpec.GetDefaultSpec().GetContainers()[0].GetResource().GetCpuLimit())
+
+	ref := &template.TemplateReference{
+		Name:       spec.GetName(),
+		Version:    2,
+		Parameters: params,
+	}
+	addTemplateLabels(jobSpec, ref)
+	addTemplateLabels(jobSpec, ref)
+	suite.Equal([]*v1alphapeloton.Label{
+		{Key: "verbose", Value: "true"},
+		{Key: "peloton.template_name", Value: "echo"},
+		{Key: "peloton.template_version", Value: "2"},
+	}, jobSpec.GetLabels())
+}
+
+// TestRenderJobConfig tests rendering a batch job config from a batch
+// template, and recording the template in the job config labels
+func (suite *templateTestSuite) TestRenderJobConfig() {
+	spec := newTestBatchTemplateSpec()
+	suite.NoError(validateTemplateSpec(spec))
+
+	params, err := resolveParameters(spec, map[string]string{
+		"environment": "staging",
+		"verbose":     "true",
+	})
+	suite.NoError(err)
+
+	jobConfig, err := renderJobConfig(spec, params)
+	suite.NoError(err)
+	suite.Equal(job.JobType_BATCH, jobConfig.GetType())
+	suite.Equal("echo-staging", jobConfig.GetName())
+	suite.Equal(uint32(3), jobConfig.GetInstanceCount())
+	suite.Equal(0.5, jobConfig.GetDefaultConfig().GetResource().GetCpuLimit())
+
+	ref := &template.TemplateReference{
+		Name:       spec.GetName(),
+		Version:    2,
+		Parameters: params,
+	}
+	addJobConfigTemplateLabels(jobConfig, ref)
+	addJobConfigTemplateLabels(jobConfig, ref)
+	suite.Equal([]*peloton.Label{
+		{Key: "verbose", Value: "true"},
+		{Key: "peloton.template_name", Value: "echo"},
+		{Key: "peloton.template_version", Value: "2"},
+	}, jobConfig.GetLabels())
+
+	// a batch template cannot render a service job
+	spec.Body = "name: echo\ntype: 1\n"
+	err = validateTemplateSpec(spec)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestRenderStringParameterInjection tests the string parameters cannot
+// inject YAML into the rendered job spec
+func (suite *templateTestSuite) TestRenderStringParameterInjection() {
+	spec := newTestTemplateSpec()
+	spec.Body += "description: {{.environment}}\n"
+
+	for _, payload := range []string{
+		"staging\ninstancecount: 100",
+		"staging\", \"instancecount\": 100, \"x\": \"",
+		"{instancecount: 100}",
+		"true",
+		"staging # comment",
+	} {
+		params, err := resolveParameters(spec, map[string]string{
+			"environment": payload,
+		})
+		suite.NoError(err)
+
+		jobSpec, err := renderJobSpec(spec, params)
+		suite.NoError(err, payload)
+		suite.Equal(payload, jobSpec.GetDescription())
+		suite.Equal("echo-"+payload, jobSpec.GetName())
+		suite.Equal(uint32(3), jobSpec.GetInstanceCount())
+	}
+
+	// the string parameters still compare equal to their values
+	spec.Body += "{{if eq .environment \"staging\"}}owner: staging-owner{{end}}\n"
+	params, err := resolveParameters(spec, map[string]string{
+		"environment": "staging",
+	})
+	suite.NoError(err)
+	jobSpec, err := renderJobSpec(spec, params)
+	suite.NoError(err)
+	suite.Equal("staging-owner", jobSpec.GetOwner())
+}
@@ -31,6 +31,7 @@ import (
 	jobutil "github.com/uber/peloton/pkg/jobmgr/util/job"
 	"github.com/uber/peloton/pkg/storage"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/pborman/uuid"
 	"github.com/uber-go/tally"
 	"go.uber.org/yarpc"
@@ -121,10 +122,6 @@ func (h *serviceHandler) CreateUpdate(
 			"JobID must be of UUID format")
 	}
 
-	if req.GetUpdateConfig().GetInPlace() {
-		return nil, yarpcerrors.UnimplementedErrorf("in-place update is not supported yet")
-	}
-
 	// Validate that the job does exist
 	jobRuntime, err := h.jobStore.GetJobRuntime(ctx, jobID.GetValue())
 	if err != nil {
@@ -456,8 +453,167 @@ func (h *serviceHandler) AbortUpdate(ctx context.Context,
 
 func (h *serviceHandler) RollbackUpdate(ctx context.Context,
 	req *svc.RollbackUpdateRequest) (*svc.RollbackUpdateResponse, error) {
-	return nil, yarpcerrors.UnimplementedErrorf(
-		"UpdateService.RollbackUpdate is not implemented")
+	h.metrics.UpdateAPIRollback.Inc(1)
+
+	updateID := req.GetUpdateId()
+	if len(updateID.GetValue()) == 0 {
+		h.metrics.UpdateRollbackFail.Inc(1)
+		return nil, yarpcerrors.InvalidArgumentErrorf("no update ID provided")
+	}
+
+	updateModel, err := h.updateStore.GetUpdate(ctx, updateID)
+	if err != nil {
+		h.metrics.UpdateRollbackFail.Inc(1)
+		return nil, err
+	}
+
+	if updateModel.GetType() != models.WorkflowType_UPDATE {
+		h.metrics.UpdateRollbackFail.Inc(1)
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"workflow of type %s cannot be rolled back",
+			updateModel.GetType().String())
+	}
+
+	cachedJob := h.jobFactory.AddJob(updateModel.GetJobID())
+	runtime, err := cachedJob.GetRuntime(ctx)
+	if err != nil {
+		h.metrics.UpdateRollbackFail.Inc(1)
+		return nil, err
+	}
+
+	// rolling back an older update would overwrite the updates after it
+	if runtime.GetUpdateID().GetValue() != updateID.GetValue() {
+		h.metrics.UpdateRollbackFail.Inc(1)
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"only the latest update of a job can be rolled back")
+	}
+
+	var rollbackUpdateID *peloton.UpdateID
+	switch updateModel.GetState() {
+	case update.State_ROLLED_BACK:
+		h.metrics.UpdateRollbackFail.Inc(1)
+		return nil, yarpcerrors.InvalidArgumentErrorf(
+			"update has already been rolled back")
+
+	case update.State_ROLLING_BACKWARD:
+		// the update is already being rolled back
+		rollbackUpdateID = updateID
+
+	case update.State_SUCCEEDED, update.State_ABORTED, update.State_FAILED:
+		// the update is done, create a new update to
+		// the job configuration before the update
+		rollbackUpdateID, err = h.createRollbackUpdate(
+			ctx,
+			cachedJob,
+			runtime,
+			updateModel,
+			req.GetOpaqueData(),
+		)
+
+	default:
+		// the update is in progress, roll it back the same way
+		// as an update failing with RollbackOnFailure set
+		rollbackUpdateID = updateID
+		err = goalstate.RollbackUpdate(
+			ctx,
+			cachedJob,
+			cachedJob.AddWorkflow(updateID),
+		)
+	}
+
+	if err != nil {
+		h.metrics.UpdateRollbackFail.Inc(1)
+	} else {
+		h.metrics.UpdateRollback.Inc(1)
+	}
+
+	// In case of error, enqueue the update anyway for the same
+	// reasons as in CreateUpdate
+	if len(rollbackUpdateID.GetValue()) > 0 {
+		h.goalStateDriver.EnqueueUpdate(
+			updateModel.GetJobID(), rollbackUpdateID, time.Now())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return &svc.RollbackUpdateResponse{
+		UpdateID: rollbackUpdateID,
+	}, nil
+}
+
+// createRollbackUpdate creates an update of the job to the job
+// configuration before the given terminated update.
+func (h *serviceHandler) createRollbackUpdate(
+	ctx context.Context,
+	cachedJob cached.Job,
+	jobRuntime *job.RuntimeInfo,
+	updateModel *models.UpdateModel,
+	opaqueData *peloton.OpaqueData,
+) (*peloton.UpdateID, error) {
+	jobID := updateModel.GetJobID()
+
+	prevJobConfig, prevConfigAddOn, err := h.jobStore.GetJobConfigWithVersion(
+		ctx,
+		jobID.GetValue(),
+		updateModel.GetPrevJobConfigVersion(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	currentJobConfig, _, err := h.jobStore.GetJobConfigWithVersion(
+		ctx,
+		jobID.GetValue(),
+		jobRuntime.GetConfigurationVersion(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// the new configuration is the one before the update, set
+	// with the change log of the current configuration
+	jobConfig := proto.Clone(prevJobConfig).(*job.JobConfig)
+	jobConfig.ChangeLog = &peloton.ChangeLog{
+		Version: currentJobConfig.GetChangeLog().GetVersion(),
+	}
+
+	if err = h.validateJobConfigUpdate(
+		ctx, jobID, currentJobConfig, jobConfig); err != nil {
+		return nil, err
+	}
+
+	var respoolPath string
+	for _, label := range prevConfigAddOn.GetSystemLabels() {
+		if label.GetKey() == common.SystemLabelResourcePool {
+			respoolPath = label.GetValue()
+		}
+	}
+	configAddOn := &models.ConfigAddOn{
+		SystemLabels: jobutil.ConstructSystemLabels(jobConfig, respoolPath),
+	}
+
+	// the rollback uses the same update configuration as the update,
+	// but is not rolled back itself if it fails, which would move the
+	// job back to the configuration being rolled back.
+	updateConfig := proto.Clone(updateModel.GetUpdateConfig()).(*update.UpdateConfig)
+	if updateConfig == nil {
+		updateConfig = &update.UpdateConfig{}
+	}
+	updateConfig.RollbackOnFailure = false
+
+	updateID, _, err := cachedJob.CreateWorkflow(
+		ctx,
+		models.WorkflowType_UPDATE,
+		updateConfig,
+		versionutil.GetJobEntityVersion(
+			jobRuntime.GetConfigurationVersion(),
+			jobRuntime.GetDesiredStateVersion(),
+			jobRuntime.GetWorkflowVersion()),
+		cached.WithConfig(jobConfig, currentJobConfig, configAddOn),
+		cached.WithOpaqueData(opaqueData),
+	)
+	return updateID, err
 }
 
 func (h *serviceHandler) getCachedJobWithUpdateID(
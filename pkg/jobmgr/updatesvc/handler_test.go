@@ -1149,3 +1149,288 @@ func (suite *UpdateSvcTestSuite) TestResumeProgressUpdateFails() {
 	)
 	suite.Error(err)
 }
+
+// TestCreateInPlaceSuccess tests successfully creating an in-place update
+func (suite *UpdateSvcTestSuite) TestCreateInPlaceSuccess() {
+	updateConfig := &update.UpdateConfig{
+		BatchSize: uint32(2),
+		InPlace:   true,
+	}
+
+	suite.jobFactory.EXPECT().
+		AddJob(suite.jobID).
+		Return(suite.cachedJob)
+
+	suite.jobStore.EXPECT().
+		GetJobRuntime(gomock.Any(), suite.jobID.GetValue()).
+		Return(suite.jobRuntime, nil)
+
+	suite.jobStore.EXPECT().
+		GetJobConfig(gomock.Any(), suite.jobID.GetValue()).
+		Return(suite.jobConfig, &models.ConfigAddOn{}, nil)
+
+	suite.cachedJob.EXPECT().
+		CreateWorkflow(
+			gomock.Any(),
+			models.WorkflowType_UPDATE,
+			updateConfig,
+			gomock.Any(),
+			gomock.Any(),
+			gomock.Any(),
+		).
+		Return(suite.updateID, nil, nil)
+
+	suite.goalStateDriver.EXPECT().
+		EnqueueUpdate(suite.jobID, suite.updateID, gomock.Any())
+
+	resp, err := suite.h.CreateUpdate(
+		context.Background(),
+		&svc.CreateUpdateRequest{
+			JobId:        suite.jobID,
+			JobConfig:    suite.newJobConfig,
+			UpdateConfig: updateConfig,
+		},
+	)
+	suite.NoError(err)
+	suite.Equal(suite.updateID, resp.GetUpdateID())
+}
+
+// TestRollbackNoID tests rolling back without providing an update ID
+func (suite *UpdateSvcTestSuite) TestRollbackNoID() {
+	_, err := suite.h.RollbackUpdate(
+		context.Background(),
+		&svc.RollbackUpdateRequest{},
+	)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestRollbackGetUpdateFail tests failing to read the update
+// from DB while rolling it back
+func (suite *UpdateSvcTestSuite) TestRollbackGetUpdateFail() {
+	suite.updateStore.EXPECT().
+		GetUpdate(gomock.Any(), suite.updateID).
+		Return(nil, fmt.Errorf("fake db error"))
+
+	_, err := suite.h.RollbackUpdate(
+		context.Background(),
+		&svc.RollbackUpdateRequest{UpdateId: suite.updateID},
+	)
+	suite.EqualError(err, "fake db error")
+}
+
+// TestRollbackNotUpdateWorkflow tests rolling back a workflow
+// which is not an update
+func (suite *UpdateSvcTestSuite) TestRollbackNotUpdateWorkflow() {
+	suite.updateStore.EXPECT().
+		GetUpdate(gomock.Any(), suite.updateID).
+		Return(&models.UpdateModel{
+			JobID: suite.jobID,
+			Type:  models.WorkflowType_RESTART,
+			State: update.State_SUCCEEDED,
+		}, nil)
+
+	_, err := suite.h.RollbackUpdate(
+		context.Background(),
+		&svc.RollbackUpdateRequest{UpdateId: suite.updateID},
+	)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestRollbackNotLatestUpdate tests rolling back an update
+// which is not the latest update of the job
+func (suite *UpdateSvcTestSuite) TestRollbackNotLatestUpdate() {
+	suite.updateStore.EXPECT().
+		GetUpdate(gomock.Any(), suite.updateID).
+		Return(&models.UpdateModel{
+			JobID: suite.jobID,
+			Type:  models.WorkflowType_UPDATE,
+			State: update.State_SUCCEEDED,
+		}, nil)
+
+	suite.jobFactory.EXPECT().
+		AddJob(suite.jobID).
+		Return(suite.cachedJob)
+
+	suite.jobRuntime.UpdateID = &peloton.UpdateID{Value: uuid.NewRandom().String()}
+	suite.cachedJob.EXPECT().
+		GetRuntime(gomock.Any()).
+		Return(suite.jobRuntime, nil)
+
+	_, err := suite.h.RollbackUpdate(
+		context.Background(),
+		&svc.RollbackUpdateRequest{UpdateId: suite.updateID},
+	)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestRollbackRolledBackUpdate tests rolling back an update
+// which has already been rolled back
+func (suite *UpdateSvcTestSuite) TestRollbackRolledBackUpdate() {
+	suite.updateStore.EXPECT().
+		GetUpdate(gomock.Any(), suite.updateID).
+		Return(&models.UpdateModel{
+			JobID: suite.jobID,
+			Type:  models.WorkflowType_UPDATE,
+			State: update.State_ROLLED_BACK,
+		}, nil)
+
+	suite.jobFactory.EXPECT().
+		AddJob(suite.jobID).
+		Return(suite.cachedJob)
+
+	suite.jobRuntime.UpdateID = suite.updateID
+	suite.cachedJob.EXPECT().
+		GetRuntime(gomock.Any()).
+		Return(suite.jobRuntime, nil)
+
+	_, err := suite.h.RollbackUpdate(
+		context.Background(),
+		&svc.RollbackUpdateRequest{UpdateId: suite.updateID},
+	)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestRollbackActiveUpdateFail tests failing to roll back
+// an update which is in progress
+func (suite *UpdateSvcTestSuite) TestRollbackActiveUpdateFail() {
+	suite.updateStore.EXPECT().
+		GetUpdate(gomock.Any(), suite.updateID).
+		Return(&models.UpdateModel{
+			JobID: suite.jobID,
+			Type:  models.WorkflowType_UPDATE,
+			State: update.State_ROLLING_FORWARD,
+		}, nil)
+
+	suite.jobFactory.EXPECT().
+		AddJob(suite.jobID).
+		Return(suite.cachedJob)
+
+	suite.jobRuntime.UpdateID = suite.updateID
+	suite.cachedJob.EXPECT().
+		GetRuntime(gomock.Any()).
+		Return(suite.jobRuntime, nil)
+
+	suite.cachedJob.EXPECT().
+		AddWorkflow(suite.updateID).
+		Return(suite.cachedUpdate)
+
+	suite.cachedJob.EXPECT().
+		RollbackWorkflow(gomock.Any()).
+		Return(fmt.Errorf("fake db error"))
+
+	suite.cachedJob.EXPECT().
+		ID().
+		Return(suite.jobID).
+		AnyTimes()
+
+	suite.cachedUpdate.EXPECT().
+		ID().
+		Return(suite.updateID).
+		AnyTimes()
+
+	suite.goalStateDriver.EXPECT().
+		EnqueueUpdate(suite.jobID, suite.updateID, gomock.Any())
+
+	_, err := suite.h.RollbackUpdate(
+		context.Background(),
+		&svc.RollbackUpdateRequest{UpdateId: suite.updateID},
+	)
+	suite.EqualError(err, "fake db error")
+}
+
+// TestRollbackRollingBackUpdate tests rolling back an update
+// which is already rolling back
+func (suite *UpdateSvcTestSuite) TestRollbackRollingBackUpdate() {
+	suite.updateStore.EXPECT().
+		GetUpdate(gomock.Any(), suite.updateID).
+		Return(&models.UpdateModel{
+			JobID: suite.jobID,
+			Type:  models.WorkflowType_UPDATE,
+			State: update.State_ROLLING_BACKWARD,
+		}, nil)
+
+	suite.jobFactory.EXPECT().
+		AddJob(suite.jobID).
+		Return(suite.cachedJob)
+
+	suite.jobRuntime.UpdateID = suite.updateID
+	suite.cachedJob.EXPECT().
+		GetRuntime(gomock.Any()).
+		Return(suite.jobRuntime, nil)
+
+	suite.goalStateDriver.EXPECT().
+		EnqueueUpdate(suite.jobID, suite.updateID, gomock.Any())
+
+	resp, err := suite.h.RollbackUpdate(
+		context.Background(),
+		&svc.RollbackUpdateRequest{UpdateId: suite.updateID},
+	)
+	suite.NoError(err)
+	suite.Equal(suite.updateID, resp.GetUpdateID())
+}
+
+// TestRollbackTerminalUpdate tests rolling back an update which
+// has completed by creating a new update to the previous configuration
+func (suite *UpdateSvcTestSuite) TestRollbackTerminalUpdate() {
+	rollbackUpdateID := &peloton.UpdateID{Value: uuid.NewRandom().String()}
+	opaque := &peloton.OpaqueData{Data: "test"}
+
+	suite.updateStore.EXPECT().
+		GetUpdate(gomock.Any(), suite.updateID).
+		Return(&models.UpdateModel{
+			JobID:                suite.jobID,
+			Type:                 models.WorkflowType_UPDATE,
+			State:                update.State_SUCCEEDED,
+			JobConfigVersion:     3,
+			PrevJobConfigVersion: 2,
+			UpdateConfig: &update.UpdateConfig{
+				BatchSize:         uint32(2),
+				RollbackOnFailure: true,
+			},
+		}, nil)
+
+	suite.jobFactory.EXPECT().
+		AddJob(suite.jobID).
+		Return(suite.cachedJob)
+
+	suite.jobRuntime.UpdateID = suite.updateID
+	suite.jobRuntime.ConfigurationVersion = 3
+	suite.cachedJob.EXPECT().
+		GetRuntime(gomock.Any()).
+		Return(suite.jobRuntime, nil)
+
+	suite.jobStore.EXPECT().
+		GetJobConfigWithVersion(gomock.Any(), suite.jobID.GetValue(), uint64(2)).
+		Return(suite.jobConfig, &models.ConfigAddOn{}, nil)
+
+	suite.jobStore.EXPECT().
+		GetJobConfigWithVersion(gomock.Any(), suite.jobID.GetValue(), uint64(3)).
+		Return(suite.newJobConfig, &models.ConfigAddOn{}, nil)
+
+	suite.cachedJob.EXPECT().
+		CreateWorkflow(
+			gomock.Any(),
+			models.WorkflowType_UPDATE,
+			&update.UpdateConfig{
+				BatchSize:         uint32(2),
+				RollbackOnFailure: false,
+			},
+			versionutil.GetJobEntityVersion(3, 0, 1),
+			gomock.Any(),
+			gomock.Any(),
+		).
+		Return(rollbackUpdateID, nil, nil)
+
+	suite.goalStateDriver.EXPECT().
+		EnqueueUpdate(suite.jobID, rollbackUpdateID, gomock.Any())
+
+	resp, err := suite.h.RollbackUpdate(
+		context.Background(),
+		&svc.RollbackUpdateRequest{
+			UpdateId:   suite.updateID,
+			OpaqueData: opaque,
+		},
+	)
+	suite.NoError(err)
+	suite.Equal(rollbackUpdateID, resp.GetUpdateID())
+}
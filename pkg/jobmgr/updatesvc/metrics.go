@@ -48,6 +48,10 @@ type Metrics struct {
 	UpdateAPIResume  tally.Counter
 	UpdateResume     tally.Counter
 	UpdateResumeFail tally.Counter
+
+	UpdateAPIRollback  tally.Counter
+	UpdateRollback     tally.Counter
+	UpdateRollbackFail tally.Counter
 }
 
 // NewMetrics returns a new Metrics struct, with all metrics
@@ -85,5 +89,9 @@ func NewMetrics(scope tally.Scope) *Metrics {
 		UpdateAPIResume:  UpdateAPIScope.Counter("resume"),
 		UpdateResume:     UpdateSuccessScope.Counter("resume"),
 		UpdateResumeFail: UpdateFailScope.Counter("resume"),
+
+		UpdateAPIRollback:  UpdateAPIScope.Counter("rollback"),
+		UpdateRollback:     UpdateSuccessScope.Counter("rollback"),
+		UpdateRollbackFail: UpdateFailScope.Counter("rollback"),
 	}
 }
@@ -23,10 +23,12 @@ import (
 	pelotonv0respool "github.com/uber/peloton/.gen/peloton/api/v0/respool"
 	"github.com/uber/peloton/.gen/peloton/api/v0/task"
 	"github.com/uber/peloton/.gen/peloton/api/v0/update"
+	"github.com/uber/peloton/.gen/peloton/api/v0/volume"
 	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless"
 	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
 	"github.com/uber/peloton/.gen/peloton/api/v1alpha/pod"
 	"github.com/uber/peloton/.gen/peloton/api/v1alpha/query"
+	v1alphavolume "github.com/uber/peloton/.gen/peloton/api/v1alpha/volume"
 	"github.com/uber/peloton/.gen/peloton/private/models"
 
 	"github.com/uber/peloton/pkg/common/util"
@@ -229,6 +231,16 @@ func ConvertTaskConfigToPodSpec(taskConfig *task.TaskConfig, jobID string, insta
 			DiskLimitMb: taskConfig.GetResource().GetDiskLimitMb(),
 			FdLimit:     taskConfig.GetResource().GetFdLimit(),
 			GpuLimit:    taskConfig.GetResource().GetGpuLimit(),
+			NetworkMbps: taskConfig.GetResource().GetNetworkMbps(),
+		}
+
+		if gpu := taskConfig.GetResource().GetGpu(); gpu != nil {
+			container.Resource.Gpu = &pod.GPUSpec{
+				Models:       gpu.GetModels(),
+				MinMemoryMb:  gpu.GetMinMemoryMb(),
+				WholeHost:    gpu.GetWholeHost(),
+				SameNumaNode: gpu.GetSameNumaNode(),
+			}
 		}
 	}
 
@@ -322,6 +334,54 @@ func ConvertTaskConstraintsToPodConstraints(constraints []*task.Constraint) []*p
 			}
 		}
 
+		if constraint.GetAttributeConstraint() != nil {
+			podConstraint.AttributeConstraint = &pod.AttributeConstraint{
+				Name: constraint.GetAttributeConstraint().GetName(),
+				Condition: pod.AttributeConstraint_Condition(
+					constraint.GetAttributeConstraint().GetCondition(),
+				),
+				Value:      constraint.GetAttributeConstraint().GetValue(),
+				RangeBegin: constraint.GetAttributeConstraint().GetRangeBegin(),
+				RangeEnd:   constraint.GetAttributeConstraint().GetRangeEnd(),
+				Values:     constraint.GetAttributeConstraint().GetValues(),
+				Version:    constraint.GetAttributeConstraint().GetVersion(),
+			}
+		}
+
+		if constraint.GetTopologyConstraint() != nil {
+			podConstraint.TopologyConstraint = &pod.TopologyConstraint{
+				TopologyKey:  constraint.GetTopologyConstraint().GetTopologyKey(),
+				MaxPerDomain: constraint.GetTopologyConstraint().GetMaxPerDomain(),
+				MaxSkew:      constraint.GetTopologyConstraint().GetMaxSkew(),
+			}
+
+			if constraint.GetTopologyConstraint().GetLabel() != nil {
+				podConstraint.TopologyConstraint.Label = &v1alphapeloton.Label{
+					Key:   constraint.GetTopologyConstraint().GetLabel().GetKey(),
+					Value: constraint.GetTopologyConstraint().GetLabel().GetValue(),
+				}
+			}
+		}
+
+		if constraint.GetPreferenceConstraint() != nil {
+			podConstraint.PreferenceConstraint = &pod.PreferenceConstraint{}
+			for _, preference := range constraint.GetPreferenceConstraint().GetPreferences() {
+				podPreference := &pod.PlacementPreference{
+					Kind:   pod.PlacementPreference_Kind(preference.GetKind()),
+					Weight: preference.GetWeight(),
+					Metric: pod.PlacementPreference_Metric(preference.GetMetric()),
+				}
+				if preference.GetLabel() != nil {
+					podPreference.Label = &v1alphapeloton.Label{
+						Key:   preference.GetLabel().GetKey(),
+						Value: preference.GetLabel().GetValue(),
+					}
+				}
+				podConstraint.PreferenceConstraint.Preferences = append(
+					podConstraint.PreferenceConstraint.Preferences, podPreference)
+			}
+		}
+
 		if constraint.GetAndConstraint() != nil {
 			podConstraint.AndConstraint = &pod.AndConstraint{
 				Constraints: ConvertTaskConstraintsToPodConstraints(constraint.GetAndConstraint().GetConstraints()),
@@ -519,6 +579,7 @@ func ConvertSLAConfigToSLASpec(slaConfig *job.SlaConfig) *stateless.SlaSpec {
 		Preemptible:                 slaConfig.GetPreemptible(),
 		Revocable:                   slaConfig.GetRevocable(),
 		MaximumUnavailableInstances: slaConfig.GetMaximumUnavailableInstances(),
+		StateTimeouts:               convertStateTimeoutsToV1Alpha(slaConfig.GetStateTimeouts()),
 	}
 }
 
@@ -529,7 +590,40 @@ func ConvertSLASpecToSLAConfig(slaSpec *stateless.SlaSpec) *job.SlaConfig {
 		Preemptible:                 slaSpec.GetPreemptible(),
 		Revocable:                   slaSpec.GetRevocable(),
 		MaximumUnavailableInstances: slaSpec.GetMaximumUnavailableInstances(),
+		StateTimeouts:               convertStateTimeoutsToV0(slaSpec.GetStateTimeouts()),
+	}
+}
+
+// convertStateTimeoutsToV1Alpha converts v0 job state timeouts
+// to v1alpha stateless state timeouts
+func convertStateTimeoutsToV1Alpha(
+	timeouts []*job.StateTimeout,
+) []*stateless.StateTimeout {
+	var result []*stateless.StateTimeout
+	for _, timeout := range timeouts {
+		result = append(result, &stateless.StateTimeout{
+			State:          ConvertTaskStateToPodState(timeout.GetState()),
+			TimeoutSeconds: timeout.GetTimeoutSeconds(),
+			Action:         stateless.StateTimeout_Action(timeout.GetAction()),
+		})
 	}
+	return result
+}
+
+// convertStateTimeoutsToV0 converts v1alpha stateless state timeouts
+// to v0 job state timeouts
+func convertStateTimeoutsToV0(
+	timeouts []*stateless.StateTimeout,
+) []*job.StateTimeout {
+	var result []*job.StateTimeout
+	for _, timeout := range timeouts {
+		result = append(result, &job.StateTimeout{
+			State:          ConvertPodStateToTaskState(timeout.GetState()),
+			TimeoutSeconds: timeout.GetTimeoutSeconds(),
+			Action:         job.StateTimeout_Action(timeout.GetAction()),
+		})
+	}
+	return result
 }
 
 // ConvertUpdateModelToWorkflowInfo converts private UpdateModel
@@ -748,6 +842,16 @@ func ConvertPodSpecToTaskConfig(spec *pod.PodSpec) (*task.TaskConfig, error) {
 			DiskLimitMb: mainContainer.GetResource().GetDiskLimitMb(),
 			FdLimit:     mainContainer.GetResource().GetFdLimit(),
 			GpuLimit:    mainContainer.GetResource().GetGpuLimit(),
+			NetworkMbps: mainContainer.GetResource().GetNetworkMbps(),
+		}
+
+		if gpu := mainContainer.GetResource().GetGpu(); gpu != nil {
+			result.Resource.Gpu = &task.GPUConfig{
+				Models:       gpu.GetModels(),
+				MinMemoryMb:  gpu.GetMinMemoryMb(),
+				WholeHost:    gpu.GetWholeHost(),
+				SameNumaNode: gpu.GetSameNumaNode(),
+			}
 		}
 	}
 
@@ -853,6 +957,54 @@ func ConvertPodConstraintsToTaskConstraints(
 			}
 		}
 
+		if podConstraint.GetAttributeConstraint() != nil {
+			taskConstraint.AttributeConstraint = &task.AttributeConstraint{
+				Name: podConstraint.GetAttributeConstraint().GetName(),
+				Condition: task.AttributeConstraint_Condition(
+					podConstraint.GetAttributeConstraint().GetCondition(),
+				),
+				Value:      podConstraint.GetAttributeConstraint().GetValue(),
+				RangeBegin: podConstraint.GetAttributeConstraint().GetRangeBegin(),
+				RangeEnd:   podConstraint.GetAttributeConstraint().GetRangeEnd(),
+				Values:     podConstraint.GetAttributeConstraint().GetValues(),
+				Version:    podConstraint.GetAttributeConstraint().GetVersion(),
+			}
+		}
+
+		if podConstraint.GetTopologyConstraint() != nil {
+			taskConstraint.TopologyConstraint = &task.TopologyConstraint{
+				TopologyKey:  podConstraint.GetTopologyConstraint().GetTopologyKey(),
+				MaxPerDomain: podConstraint.GetTopologyConstraint().GetMaxPerDomain(),
+				MaxSkew:      podConstraint.GetTopologyConstraint().GetMaxSkew(),
+			}
+
+			if podConstraint.GetTopologyConstraint().GetLabel() != nil {
+				taskConstraint.TopologyConstraint.Label = &peloton.Label{
+					Key:   podConstraint.GetTopologyConstraint().GetLabel().GetKey(),
+					Value: podConstraint.GetTopologyConstraint().GetLabel().GetValue(),
+				}
+			}
+		}
+
+		if podConstraint.GetPreferenceConstraint() != nil {
+			taskConstraint.PreferenceConstraint = &task.PreferenceConstraint{}
+			for _, podPreference := range podConstraint.GetPreferenceConstraint().GetPreferences() {
+				preference := &task.PlacementPreference{
+					Kind:   task.PlacementPreference_Kind(podPreference.GetKind()),
+					Weight: podPreference.GetWeight(),
+					Metric: task.PlacementPreference_Metric(podPreference.GetMetric()),
+				}
+				if podPreference.GetLabel() != nil {
+					preference.Label = &peloton.Label{
+						Key:   podPreference.GetLabel().GetKey(),
+						Value: podPreference.GetLabel().GetValue(),
+					}
+				}
+				taskConstraint.PreferenceConstraint.Preferences = append(
+					taskConstraint.PreferenceConstraint.Preferences, preference)
+			}
+		}
+
 		if podConstraint.GetAndConstraint() != nil {
 			taskConstraint.AndConstraint = &task.AndConstraint{
 				Constraints: ConvertPodConstraintsToTaskConstraints(
@@ -993,6 +1145,41 @@ func ConvertTaskStatsToPodStats(taskStats map[string]uint32) map[string]uint32 {
 	return result
 }
 
+// ConvertVolumeInfoToV1AlphaVolumeInfo converts v0 volume.PersistentVolumeInfo
+// to v1alpha volume.PersistentVolumeInfo
+func ConvertVolumeInfoToV1AlphaVolumeInfo(
+	volumeInfo *volume.PersistentVolumeInfo,
+) *v1alphavolume.PersistentVolumeInfo {
+	if volumeInfo == nil {
+		return nil
+	}
+
+	return &v1alphavolume.PersistentVolumeInfo{
+		VolumeId: &v1alphapeloton.VolumeID{
+			Value: volumeInfo.GetId().GetValue(),
+		},
+		PodName: &v1alphapeloton.PodName{
+			Value: util.CreatePelotonTaskID(
+				volumeInfo.GetJobId().GetValue(),
+				volumeInfo.GetInstanceId(),
+			),
+		},
+		Hostname: volumeInfo.GetHostname(),
+		State: v1alphavolume.VolumeState(
+			v1alphavolume.VolumeState_value["VOLUME_STATE_"+
+				volumeInfo.GetState().String()],
+		),
+		DesiredState: v1alphavolume.VolumeState(
+			v1alphavolume.VolumeState_value["VOLUME_STATE_"+
+				volumeInfo.GetGoalState().String()],
+		),
+		SizeMb:        volumeInfo.GetSizeMB(),
+		ContainerPath: volumeInfo.GetContainerPath(),
+		CreateTime:    volumeInfo.GetCreateTime(),
+		UpdateTime:    volumeInfo.GetUpdateTime(),
+	}
+}
+
 func convertV1AlphaPaginationSpecToV0PaginationSpec(
 	pagination *query.PaginationSpec,
 ) *pelotonv0query.PaginationSpec {
@@ -25,11 +25,13 @@ import (
 	"github.com/uber/peloton/.gen/peloton/api/v0/respool"
 	"github.com/uber/peloton/.gen/peloton/api/v0/task"
 	"github.com/uber/peloton/.gen/peloton/api/v0/update"
+	"github.com/uber/peloton/.gen/peloton/api/v0/volume"
 	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless"
 	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
 	"github.com/uber/peloton/.gen/peloton/api/v1alpha/pod"
 	v1alphaquery "github.com/uber/peloton/.gen/peloton/api/v1alpha/query"
 	v1alpharespool "github.com/uber/peloton/.gen/peloton/api/v1alpha/respool"
+	v1alphavolume "github.com/uber/peloton/.gen/peloton/api/v1alpha/volume"
 	"github.com/uber/peloton/.gen/peloton/private/models"
 
 	"github.com/uber/peloton/pkg/common/util"
@@ -247,6 +249,11 @@ func (suite *apiConverterTestSuite) TestConvertTaskConfigToPodSpecAndViceVersa()
 			DiskLimitMb: 400,
 			FdLimit:     100,
 			GpuLimit:    10,
+			Gpu: &task.GPUConfig{
+				Models:       []string{"tesla-v100"},
+				MinMemoryMb:  16000,
+				SameNumaNode: true,
+			},
 		},
 		Container: &mesos.ContainerInfo{
 			Type: &containerType,
@@ -316,6 +323,11 @@ func (suite *apiConverterTestSuite) TestConvertTaskConfigToPodSpecAndViceVersa()
 					DiskLimitMb: taskConfig.GetResource().GetDiskLimitMb(),
 					FdLimit:     taskConfig.GetResource().GetFdLimit(),
 					GpuLimit:    taskConfig.GetResource().GetGpuLimit(),
+					Gpu: &pod.GPUSpec{
+						Models:       []string{"tesla-v100"},
+						MinMemoryMb:  16000,
+						SameNumaNode: true,
+					},
 				},
 				Container: taskConfig.GetContainer(),
 				Command:   taskConfig.GetCommand(),
@@ -671,6 +683,157 @@ func (suite *apiConverterTestSuite) TestConvertTaskConstraintsToPodConstraintsAn
 	suite.Equal(taskConstraints, ConvertPodConstraintsToTaskConstraints(podConstraints))
 }
 
+// TestConvertAttributeConstraints tests conversion of attribute
+// constraints between v0 and v1alpha
+func (suite *apiConverterTestSuite) TestConvertAttributeConstraints() {
+	taskConstraints := []*task.Constraint{
+		{
+			Type: task.Constraint_ATTRIBUTE_CONSTRAINT,
+			AttributeConstraint: &task.AttributeConstraint{
+				Name:       "rack",
+				Condition:  task.AttributeConstraint_CONDITION_IN_RANGE,
+				RangeBegin: "10",
+				RangeEnd:   "20",
+				Version:    true,
+			},
+		},
+		{
+			Type: task.Constraint_ATTRIBUTE_CONSTRAINT,
+			AttributeConstraint: &task.AttributeConstraint{
+				Name:      "zone",
+				Condition: task.AttributeConstraint_CONDITION_IN_SET,
+				Values:    []string{"dca1", "sjc1"},
+			},
+		},
+	}
+
+	podConstraints := []*pod.Constraint{
+		{
+			Type: pod.Constraint_CONSTRAINT_TYPE_ATTRIBUTE,
+			AttributeConstraint: &pod.AttributeConstraint{
+				Name:       "rack",
+				Condition:  pod.AttributeConstraint_ATTRIBUTE_CONSTRAINT_CONDITION_IN_RANGE,
+				RangeBegin: "10",
+				RangeEnd:   "20",
+				Version:    true,
+			},
+		},
+		{
+			Type: pod.Constraint_CONSTRAINT_TYPE_ATTRIBUTE,
+			AttributeConstraint: &pod.AttributeConstraint{
+				Name:      "zone",
+				Condition: pod.AttributeConstraint_ATTRIBUTE_CONSTRAINT_CONDITION_IN_SET,
+				Values:    []string{"dca1", "sjc1"},
+			},
+		},
+	}
+
+	suite.Equal(podConstraints, ConvertTaskConstraintsToPodConstraints(taskConstraints))
+	suite.Equal(taskConstraints, ConvertPodConstraintsToTaskConstraints(podConstraints))
+}
+
+// TestConvertTopologyConstraints tests conversion of topology
+// constraints between v0 and v1alpha
+func (suite *apiConverterTestSuite) TestConvertTopologyConstraints() {
+	taskConstraints := []*task.Constraint{
+		{
+			Type: task.Constraint_TOPOLOGY_CONSTRAINT,
+			TopologyConstraint: &task.TopologyConstraint{
+				TopologyKey: "rack",
+				Label: &peloton.Label{
+					Key:   "job",
+					Value: "cassandra",
+				},
+				MaxPerDomain: 2,
+			},
+		},
+		{
+			Type: task.Constraint_TOPOLOGY_CONSTRAINT,
+			TopologyConstraint: &task.TopologyConstraint{
+				TopologyKey: "zone",
+				MaxSkew:     1,
+			},
+		},
+	}
+
+	podConstraints := []*pod.Constraint{
+		{
+			Type: pod.Constraint_CONSTRAINT_TYPE_TOPOLOGY,
+			TopologyConstraint: &pod.TopologyConstraint{
+				TopologyKey: "rack",
+				Label: &v1alphapeloton.Label{
+					Key:   "job",
+					Value: "cassandra",
+				},
+				MaxPerDomain: 2,
+			},
+		},
+		{
+			Type: pod.Constraint_CONSTRAINT_TYPE_TOPOLOGY,
+			TopologyConstraint: &pod.TopologyConstraint{
+				TopologyKey: "zone",
+				MaxSkew:     1,
+			},
+		},
+	}
+
+	suite.Equal(podConstraints, ConvertTaskConstraintsToPodConstraints(taskConstraints))
+	suite.Equal(taskConstraints, ConvertPodConstraintsToTaskConstraints(podConstraints))
+}
+
+// TestConvertPreferenceConstraints tests conversion of preference
+// constraints between v0 and v1alpha
+func (suite *apiConverterTestSuite) TestConvertPreferenceConstraints() {
+	taskConstraints := []*task.Constraint{
+		{
+			Type: task.Constraint_PREFERENCE_CONSTRAINT,
+			PreferenceConstraint: &task.PreferenceConstraint{
+				Preferences: []*task.PlacementPreference{
+					{
+						Kind:   task.PlacementPreference_HOST_LABEL,
+						Weight: 10,
+						Label: &peloton.Label{
+							Key:   "ssd",
+							Value: "true",
+						},
+					},
+					{
+						Kind:   task.PlacementPreference_METRIC,
+						Weight: -1,
+						Metric: task.PlacementPreference_METRIC_CPU_FREE,
+					},
+				},
+			},
+		},
+	}
+
+	podConstraints := []*pod.Constraint{
+		{
+			Type: pod.Constraint_CONSTRAINT_TYPE_PREFERENCE,
+			PreferenceConstraint: &pod.PreferenceConstraint{
+				Preferences: []*pod.PlacementPreference{
+					{
+						Kind:   pod.PlacementPreference_PLACEMENT_PREFERENCE_KIND_HOST_LABEL,
+						Weight: 10,
+						Label: &v1alphapeloton.Label{
+							Key:   "ssd",
+							Value: "true",
+						},
+					},
+					{
+						Kind:   pod.PlacementPreference_PLACEMENT_PREFERENCE_KIND_METRIC,
+						Weight: -1,
+						Metric: pod.PlacementPreference_PLACEMENT_PREFERENCE_METRIC_CPU_FREE,
+					},
+				},
+			},
+		},
+	}
+
+	suite.Equal(podConstraints, ConvertTaskConstraintsToPodConstraints(taskConstraints))
+	suite.Equal(taskConstraints, ConvertPodConstraintsToTaskConstraints(podConstraints))
+}
+
 // TestConvertContainerPorts tests conversion from v0 task.PortConfig array to
 // v1alpha pod.PortSpec array
 func (suite *apiConverterTestSuite) TestConvertContainerPorts() {
@@ -1514,6 +1677,41 @@ func (suite *apiConverterTestSuite) TestConvertTerminationStatusReason() {
 	}
 }
 
+// TestConvertSLAStateTimeouts tests converting the state timeouts
+// of a job SLA between v0 and v1alpha and back
+func (suite *apiConverterTestSuite) TestConvertSLAStateTimeouts() {
+	slaConfig := &job.SlaConfig{
+		Priority: 1,
+		StateTimeouts: []*job.StateTimeout{
+			{
+				State:          task.TaskState_PENDING,
+				TimeoutSeconds: 600,
+				Action:         job.StateTimeout_REQUEUE,
+			},
+			{
+				State:          task.TaskState_STARTING,
+				TimeoutSeconds: 60,
+				Action:         job.StateTimeout_ALERT,
+			},
+		},
+	}
+
+	slaSpec := ConvertSLAConfigToSLASpec(slaConfig)
+	suite.Len(slaSpec.GetStateTimeouts(), 2)
+	suite.Equal(pod.PodState_POD_STATE_PENDING,
+		slaSpec.GetStateTimeouts()[0].GetState())
+	suite.Equal(uint32(600), slaSpec.GetStateTimeouts()[0].GetTimeoutSeconds())
+	suite.Equal(stateless.StateTimeout_STATE_TIMEOUT_ACTION_REQUEUE,
+		slaSpec.GetStateTimeouts()[0].GetAction())
+	suite.Equal(pod.PodState_POD_STATE_STARTING,
+		slaSpec.GetStateTimeouts()[1].GetState())
+	suite.Equal(stateless.StateTimeout_STATE_TIMEOUT_ACTION_ALERT,
+		slaSpec.GetStateTimeouts()[1].GetAction())
+
+	suite.Equal(slaConfig.GetStateTimeouts(),
+		ConvertSLASpecToSLAConfig(slaSpec).GetStateTimeouts())
+}
+
 func (suite *apiConverterTestSuite) TestConvertV1InstanceRangeToV0() {
 	from := uint32(5)
 	to := uint32(10)
@@ -1617,6 +1815,37 @@ func (suite *apiConverterTestSuite) TestConvertTaskStatsToPodStats() {
 	}
 }
 
+// TestConvertVolumeInfoToV1AlphaVolumeInfo tests conversion
+// from v0 volume info to v1alpha volume info
+func (suite *apiConverterTestSuite) TestConvertVolumeInfoToV1AlphaVolumeInfo() {
+	jobID := uuid.New()
+	volumeInfo := &volume.PersistentVolumeInfo{
+		Id:            &peloton.VolumeID{Value: "volume-id"},
+		JobId:         &peloton.JobID{Value: jobID},
+		InstanceId:    1,
+		Hostname:      "host-1",
+		State:         volume.VolumeState_CREATED,
+		GoalState:     volume.VolumeState_DELETED,
+		SizeMB:        1024,
+		ContainerPath: "/data",
+	}
+
+	result := ConvertVolumeInfoToV1AlphaVolumeInfo(volumeInfo)
+	suite.Equal("volume-id", result.GetVolumeId().GetValue())
+	suite.Equal(
+		util.CreatePelotonTaskID(jobID, 1),
+		result.GetPodName().GetValue())
+	suite.Equal("host-1", result.GetHostname())
+	suite.Equal(v1alphavolume.VolumeState_VOLUME_STATE_CREATED, result.GetState())
+	suite.Equal(
+		v1alphavolume.VolumeState_VOLUME_STATE_DELETED,
+		result.GetDesiredState())
+	suite.Equal(uint32(1024), result.GetSizeMb())
+	suite.Equal("/data", result.GetContainerPath())
+
+	suite.Nil(ConvertVolumeInfoToV1AlphaVolumeInfo(nil))
+}
+
 func TestAPIConverter(t *testing.T) {
 	suite.Run(t, new(apiConverterTestSuite))
 }
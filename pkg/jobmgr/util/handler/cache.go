@@ -16,15 +16,23 @@ package handler
 
 import (
 	"context"
+	"time"
 
 	"github.com/uber/peloton/.gen/peloton/api/v0/job"
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/respool"
 
 	"github.com/uber/peloton/pkg/jobmgr/cached"
 	jobmgrcommon "github.com/uber/peloton/pkg/jobmgr/common"
 	"github.com/uber/peloton/pkg/storage"
+
+	"go.uber.org/yarpc/yarpcerrors"
 )
 
+// _respoolRPCTimeout is the timeout to get a resource pool
+// from resource manager
+const _respoolRPCTimeout = 10 * time.Second
+
 // GetJobConfigWithoutFillingCache returns models.JobConfig without filling in
 // cache. It would first try to find the object from cache. If cache misses,
 // it will load from DB.
@@ -61,3 +69,67 @@ func GetJobRuntimeWithoutFillingCache(
 
 	return store.GetJobRuntime(ctx, id.GetValue())
 }
+
+// GetResourcePoolPath returns the path of a resource pool. It would first
+// try to find the path cached by the job factory. If cache misses, it will
+// get the path from resource manager and cache it. The cached paths are
+// cleared when a resource pool is moved to a new parent.
+func GetResourcePoolPath(
+	ctx context.Context,
+	respoolID *peloton.ResourcePoolID,
+	factory cached.JobFactory,
+	respoolClient respool.ResourceManagerYARPCClient) (string, error) {
+	if path, ok := factory.GetResourcePoolPath(respoolID); ok {
+		return path, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, _respoolRPCTimeout)
+	defer cancel()
+	resp, err := respoolClient.GetResourcePool(
+		ctx,
+		&respool.GetRequest{Id: respoolID})
+	if err != nil {
+		return "", err
+	}
+	if resp.GetError() != nil {
+		return "", yarpcerrors.NotFoundErrorf(
+			"resource pool %s not found",
+			respoolID.GetValue())
+	}
+
+	path := resp.GetPoolinfo().GetPath().GetValue()
+	factory.SetResourcePoolPath(respoolID, path)
+	return path, nil
+}
+
+// RespoolPathResolver resolves the IDs of resource pools to their paths
+// using the paths cached by the job factory.
+type RespoolPathResolver struct {
+	factory       cached.JobFactory
+	respoolClient respool.ResourceManagerYARPCClient
+}
+
+// NewRespoolPathResolver returns a RespoolPathResolver which gets the
+// paths missing in the cache of factory from resource manager.
+func NewRespoolPathResolver(
+	factory cached.JobFactory,
+	respoolClient respool.ResourceManagerYARPCClient,
+) *RespoolPathResolver {
+	return &RespoolPathResolver{
+		factory:       factory,
+		respoolClient: respoolClient,
+	}
+}
+
+// GetRespoolPath returns the path of the resource pool with the ID.
+func (r *RespoolPathResolver) GetRespoolPath(
+	ctx context.Context,
+	respoolID string,
+) (string, error) {
+	return GetResourcePoolPath(
+		ctx,
+		&peloton.ResourcePoolID{Value: respoolID},
+		r.factory,
+		r.respoolClient,
+	)
+}
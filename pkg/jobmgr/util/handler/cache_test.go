@@ -21,6 +21,8 @@ import (
 
 	"github.com/uber/peloton/.gen/peloton/api/v0/job"
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/respool"
+	respoolmocks "github.com/uber/peloton/.gen/peloton/api/v0/respool/mocks"
 	"github.com/uber/peloton/.gen/peloton/private/models"
 
 	cachedmocks "github.com/uber/peloton/pkg/jobmgr/cached/mocks"
@@ -29,6 +31,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/yarpc/yarpcerrors"
 )
 
 type HandlerCacheTestSuite struct {
@@ -144,3 +147,47 @@ func (suite *HandlerCacheTestSuite) TestGetJobConfig_CacheMissWithErr() {
 	suite.Error(err)
 	suite.Nil(config)
 }
+
+func (suite *HandlerCacheTestSuite) TestGetResourcePoolPath() {
+	respoolClient := respoolmocks.NewMockResourceManagerYARPCClient(suite.ctrl)
+	respoolID := &peloton.ResourcePoolID{Value: uuid.New()}
+
+	// cache hit
+	suite.jobFactory.EXPECT().GetResourcePoolPath(respoolID).
+		Return("/team1", true)
+	path, err := GetResourcePoolPath(
+		context.Background(), respoolID, suite.jobFactory, respoolClient)
+	suite.NoError(err)
+	suite.Equal("/team1", path)
+
+	// cache miss, the path is cached
+	suite.jobFactory.EXPECT().GetResourcePoolPath(respoolID).
+		Return("", false)
+	respoolClient.EXPECT().
+		GetResourcePool(gomock.Any(), &respool.GetRequest{Id: respoolID}).
+		Return(&respool.GetResponse{
+			Poolinfo: &respool.ResourcePoolInfo{
+				Id:   respoolID,
+				Path: &respool.ResourcePoolPath{Value: "/team2"},
+			},
+		}, nil)
+	suite.jobFactory.EXPECT().SetResourcePoolPath(respoolID, "/team2")
+	path, err = GetResourcePoolPath(
+		context.Background(), respoolID, suite.jobFactory, respoolClient)
+	suite.NoError(err)
+	suite.Equal("/team2", path)
+
+	// resource pool not found
+	suite.jobFactory.EXPECT().GetResourcePoolPath(respoolID).
+		Return("", false)
+	respoolClient.EXPECT().
+		GetResourcePool(gomock.Any(), &respool.GetRequest{Id: respoolID}).
+		Return(&respool.GetResponse{
+			Error: &respool.GetResponse_Error{
+				NotFound: &respool.ResourcePoolNotFound{Id: respoolID},
+			},
+		}, nil)
+	_, err = GetResourcePoolPath(
+		context.Background(), respoolID, suite.jobFactory, respoolClient)
+	suite.True(yarpcerrors.IsNotFound(err))
+}
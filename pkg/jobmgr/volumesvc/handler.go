@@ -24,6 +24,7 @@ import (
 
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
 	volume_svc "github.com/uber/peloton/.gen/peloton/api/v0/volume/svc"
+	v1alpha_volume_svc "github.com/uber/peloton/.gen/peloton/api/v1alpha/volume/svc"
 
 	"github.com/uber/peloton/.gen/peloton/api/v0/volume"
 	"github.com/uber/peloton/pkg/common/util"
@@ -45,7 +46,8 @@ type serviceHandler struct {
 	volumeStore storage.PersistentVolumeStore
 }
 
-// InitServiceHandler initialize serviceHandler.
+// InitServiceHandler initialize serviceHandler, and registers it for both
+// the v0 and the v1alpha volume service.
 func InitServiceHandler(
 	d *yarpc.Dispatcher,
 	parent tally.Scope,
@@ -62,6 +64,8 @@ func InitServiceHandler(
 	}
 
 	d.Register(volume_svc.BuildVolumeServiceYARPCProcedures(handler))
+	d.Register(v1alpha_volume_svc.BuildVolumeServiceYARPCProcedures(
+		&v1AlphaServiceHandler{handler: handler}))
 }
 
 // DeleteVolume implements VolumeService.DeleteVolume.
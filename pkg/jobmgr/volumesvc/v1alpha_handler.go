@@ -0,0 +1,94 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package volumesvc
+
+import (
+	"context"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	volume_svc "github.com/uber/peloton/.gen/peloton/api/v0/volume/svc"
+	v1alphavolume "github.com/uber/peloton/.gen/peloton/api/v1alpha/volume"
+	v1alpha_volume_svc "github.com/uber/peloton/.gen/peloton/api/v1alpha/volume/svc"
+
+	handlerutil "github.com/uber/peloton/pkg/jobmgr/util/handler"
+)
+
+// v1AlphaServiceHandler implements peloton.api.v1alpha.volume.svc.VolumeService
+// on top of the v0 volume service handler.
+type v1AlphaServiceHandler struct {
+	handler *serviceHandler
+}
+
+// DeleteVolume implements VolumeService.DeleteVolume.
+func (h *v1AlphaServiceHandler) DeleteVolume(
+	ctx context.Context,
+	req *v1alpha_volume_svc.DeleteVolumeRequest,
+) (*v1alpha_volume_svc.DeleteVolumeResponse, error) {
+	_, err := h.handler.DeleteVolume(
+		ctx,
+		&volume_svc.DeleteVolumeRequest{
+			Id: &peloton.VolumeID{Value: req.GetVolumeId().GetValue()},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha_volume_svc.DeleteVolumeResponse{}, nil
+}
+
+// ListVolumes implements VolumeService.ListVolumes.
+func (h *v1AlphaServiceHandler) ListVolumes(
+	ctx context.Context,
+	req *v1alpha_volume_svc.ListVolumesRequest,
+) (*v1alpha_volume_svc.ListVolumesResponse, error) {
+	resp, err := h.handler.ListVolumes(
+		ctx,
+		&volume_svc.ListVolumesRequest{
+			JobId: &peloton.JobID{Value: req.GetJobId().GetValue()},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*v1alphavolume.PersistentVolumeInfo)
+	for volumeID, volumeInfo := range resp.GetVolumes() {
+		result[volumeID] =
+			handlerutil.ConvertVolumeInfoToV1AlphaVolumeInfo(volumeInfo)
+	}
+	return &v1alpha_volume_svc.ListVolumesResponse{
+		Volumes: result,
+	}, nil
+}
+
+// GetVolume implements VolumeService.GetVolume.
+func (h *v1AlphaServiceHandler) GetVolume(
+	ctx context.Context,
+	req *v1alpha_volume_svc.GetVolumeRequest,
+) (*v1alpha_volume_svc.GetVolumeResponse, error) {
+	resp, err := h.handler.GetVolume(
+		ctx,
+		&volume_svc.GetVolumeRequest{
+			Id: &peloton.VolumeID{Value: req.GetVolumeId().GetValue()},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha_volume_svc.GetVolumeResponse{
+		Result: handlerutil.ConvertVolumeInfoToV1AlphaVolumeInfo(
+			resp.GetResult()),
+	}, nil
+}
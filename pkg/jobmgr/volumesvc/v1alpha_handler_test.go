@@ -0,0 +1,148 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package volumesvc
+
+import (
+	"context"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/api/v0/volume"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+	v1alphavolume "github.com/uber/peloton/.gen/peloton/api/v1alpha/volume"
+	v1alpha_volume_svc "github.com/uber/peloton/.gen/peloton/api/v1alpha/volume/svc"
+
+	"github.com/uber/peloton/pkg/storage"
+)
+
+func (suite *VolumeHandlerTestSuite) TestV1AlphaGetVolume() {
+	testPelotonVolumeID := &peloton.VolumeID{
+		Value: _testVolumeID,
+	}
+	volumeInfo := &volume.PersistentVolumeInfo{
+		Id:         testPelotonVolumeID,
+		JobId:      &peloton.JobID{Value: _testJobID},
+		InstanceId: 1,
+		State:      volume.VolumeState_CREATED,
+		GoalState:  volume.VolumeState_CREATED,
+	}
+	suite.volumeStore.EXPECT().
+		GetPersistentVolume(context.Background(), testPelotonVolumeID).
+		Return(volumeInfo, nil)
+
+	handler := &v1AlphaServiceHandler{handler: suite.handler}
+	resp, err := handler.GetVolume(
+		context.Background(),
+		&v1alpha_volume_svc.GetVolumeRequest{
+			VolumeId: &v1alphapeloton.VolumeID{Value: _testVolumeID},
+		},
+	)
+	suite.NoError(err)
+	suite.Equal(_testVolumeID, resp.GetResult().GetVolumeId().GetValue())
+	suite.Equal(_testJobID+"-1", resp.GetResult().GetPodName().GetValue())
+	suite.Equal(
+		v1alphavolume.VolumeState_VOLUME_STATE_CREATED,
+		resp.GetResult().GetState())
+}
+
+func (suite *VolumeHandlerTestSuite) TestV1AlphaGetVolumeNotFound() {
+	suite.volumeStore.EXPECT().
+		GetPersistentVolume(context.Background(), &peloton.VolumeID{Value: _testVolumeID}).
+		Return(nil, &storage.VolumeNotFoundError{})
+
+	handler := &v1AlphaServiceHandler{handler: suite.handler}
+	_, err := handler.GetVolume(
+		context.Background(),
+		&v1alpha_volume_svc.GetVolumeRequest{
+			VolumeId: &v1alphapeloton.VolumeID{Value: _testVolumeID},
+		},
+	)
+	suite.Equal(errVolumeNotFound, err)
+}
+
+func (suite *VolumeHandlerTestSuite) TestV1AlphaListVolumes() {
+	testPelotonVolumeID := &peloton.VolumeID{
+		Value: _testVolumeID,
+	}
+	testJobID := &peloton.JobID{
+		Value: _testJobID,
+	}
+	taskInfos := map[uint32]*task.TaskInfo{
+		0: {},
+		1: {
+			Runtime: &task.RuntimeInfo{
+				VolumeID: testPelotonVolumeID,
+			},
+		},
+	}
+	suite.taskStore.EXPECT().
+		GetTasksForJob(context.Background(), testJobID).
+		Return(taskInfos, nil)
+	suite.volumeStore.EXPECT().
+		GetPersistentVolume(context.Background(), testPelotonVolumeID).
+		Return(&volume.PersistentVolumeInfo{
+			Id: testPelotonVolumeID,
+		}, nil)
+
+	handler := &v1AlphaServiceHandler{handler: suite.handler}
+	resp, err := handler.ListVolumes(
+		context.Background(),
+		&v1alpha_volume_svc.ListVolumesRequest{
+			JobId: &v1alphapeloton.JobID{Value: _testJobID},
+		},
+	)
+	suite.NoError(err)
+	suite.Equal(1, len(resp.GetVolumes()))
+	suite.Equal(
+		_testVolumeID,
+		resp.GetVolumes()[_testVolumeID].GetVolumeId().GetValue())
+}
+
+func (suite *VolumeHandlerTestSuite) TestV1AlphaDeleteVolume() {
+	testPelotonVolumeID := &peloton.VolumeID{
+		Value: _testVolumeID,
+	}
+	testJobID := &peloton.JobID{
+		Value: _testJobID,
+	}
+	volumeInfo := &volume.PersistentVolumeInfo{
+		Id:         testPelotonVolumeID,
+		JobId:      testJobID,
+		InstanceId: 0,
+	}
+	suite.volumeStore.EXPECT().
+		GetPersistentVolume(context.Background(), testPelotonVolumeID).
+		Return(volumeInfo, nil)
+	suite.taskStore.EXPECT().
+		GetTaskRuntime(context.Background(), testJobID, uint32(0)).
+		Return(&task.RuntimeInfo{
+			VolumeID:  testPelotonVolumeID,
+			State:     task.TaskState_KILLED,
+			GoalState: task.TaskState_KILLED,
+		}, nil)
+	suite.volumeStore.EXPECT().
+		UpdatePersistentVolume(context.Background(), volumeInfo).
+		Return(nil)
+
+	handler := &v1AlphaServiceHandler{handler: suite.handler}
+	_, err := handler.DeleteVolume(
+		context.Background(),
+		&v1alpha_volume_svc.DeleteVolumeRequest{
+			VolumeId: &v1alphapeloton.VolumeID{Value: _testVolumeID},
+		},
+	)
+	suite.NoError(err)
+	suite.Equal(volume.VolumeState_DELETED, volumeInfo.GetGoalState())
+}
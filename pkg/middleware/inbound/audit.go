@@ -0,0 +1,220 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inbound
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/private/auditsvc"
+
+	"github.com/uber/peloton/pkg/audit"
+	"github.com/uber/peloton/pkg/common/stringset"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/uber-go/tally"
+	"go.uber.org/yarpc/api/transport"
+)
+
+const (
+	// _principalHeaderKey is the header carrying the username of the
+	// caller when auth is enabled
+	_principalHeaderKey = "username"
+	// _auditResultOK is the result recorded for successful calls
+	_auditResultOK = "ok"
+)
+
+// AuditInboundMiddleware records the principal, procedure, target
+// resource, request digest, result and latency of the mutating calls
+// to an audit sink. Stream calls are not audited, since Peloton only
+// serves read-only APIs over streams. The records are written to the
+// sink in the background, so that a slow sink does not stall the calls.
+type AuditInboundMiddleware struct {
+	sink       audit.Sink
+	enabled    bool
+	procedures stringset.StringSet
+
+	// records is the queue of the records waiting to be written
+	records chan *auditsvc.AuditRecord
+	// writeTimeout is the timeout to write a record, which is
+	// independent of the deadline of the call
+	writeTimeout time.Duration
+
+	writeFail tally.Counter
+	dropped   tally.Counter
+}
+
+// Handle audits the call if it is mutating and invokes the underlying handler
+func (m *AuditInboundMiddleware) Handle(
+	ctx context.Context,
+	req *transport.Request,
+	resw transport.ResponseWriter,
+	h transport.UnaryHandler,
+) error {
+	if !m.isAudited(req.Procedure) {
+		return h.Handle(ctx, req, resw)
+	}
+
+	record, targets, err := m.newRecord(req)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = h.Handle(ctx, req, resw)
+	m.write(record, targets, start, err)
+	return err
+}
+
+// HandleOneway audits the call if it is mutating and invokes the underlying handler
+func (m *AuditInboundMiddleware) HandleOneway(
+	ctx context.Context,
+	req *transport.Request,
+	h transport.OnewayHandler,
+) error {
+	if !m.isAudited(req.Procedure) {
+		return h.HandleOneway(ctx, req)
+	}
+
+	record, targets, err := m.newRecord(req)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = h.HandleOneway(ctx, req)
+	m.write(record, targets, start, err)
+	return err
+}
+
+// HandleStream invokes the underlying handler
+func (m *AuditInboundMiddleware) HandleStream(
+	s *transport.ServerStream,
+	h transport.StreamHandler,
+) error {
+	return h.HandleStream(s)
+}
+
+func (m *AuditInboundMiddleware) isAudited(procedure string) bool {
+	return m.enabled && m.procedures.Contains(procedure)
+}
+
+// newRecord reads the request body to build the audit record of the
+// call along with the resources targeted by the call, and replaces the
+// body so it can be read again by the handler.
+func (m *AuditInboundMiddleware) newRecord(
+	req *transport.Request,
+) (*auditsvc.AuditRecord, []string, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, nil, err
+		}
+		req.Body = bytes.NewReader(body)
+	}
+
+	principal, _ := req.Headers.Get(_principalHeaderKey)
+	digest := sha256.Sum256(body)
+
+	record := &auditsvc.AuditRecord{
+		Principal:     principal,
+		Caller:        req.Caller,
+		Service:       req.Service,
+		Procedure:     req.Procedure,
+		RequestDigest: hex.EncodeToString(digest[:]),
+	}
+	return record, audit.RequestTargets(req.Encoding, req.Procedure, body), nil
+}
+
+// write completes the audit record with the result of the call, and
+// queues one copy of it per target to be written to the sink. The
+// records are dropped if the queue is full. Failing to write the records
+// does not fail the call, which has already been served.
+func (m *AuditInboundMiddleware) write(
+	record *auditsvc.AuditRecord,
+	targets []string,
+	start time.Time,
+	err error,
+) {
+	record.Time = start.UTC().Format(time.RFC3339Nano)
+	record.LatencyMs = int64(time.Since(start) / time.Millisecond)
+	record.Result = _auditResultOK
+	if err != nil {
+		record.Result = errorCode(err)
+	}
+
+	records := []*auditsvc.AuditRecord{record}
+	if len(targets) != 0 {
+		records = nil
+		for _, target := range targets {
+			r := *record
+			r.Target = target
+			records = append(records, &r)
+		}
+	}
+
+	for _, r := range records {
+		select {
+		case m.records <- r:
+		default:
+			m.dropped.Inc(1)
+			log.WithField("record", r).
+				Warn("audit queue is full, dropping audit record")
+		}
+	}
+}
+
+// run writes the queued audit records to the sink
+func (m *AuditInboundMiddleware) run() {
+	for r := range m.records {
+		ctx, cancel := context.WithTimeout(
+			context.Background(), m.writeTimeout)
+		if err := m.sink.Write(ctx, r); err != nil {
+			m.writeFail.Inc(1)
+			log.WithField("record", r).
+				WithError(err).
+				Warn("failed to write audit record")
+		}
+		cancel()
+	}
+}
+
+// NewAuditInboundMiddleware returns AuditInboundMiddleware which writes
+// the audit records to sink in the background. The calls are not audited
+// if audit is not enabled in config.
+func NewAuditInboundMiddleware(
+	config *audit.Config,
+	sink audit.Sink,
+	scope tally.Scope,
+) *AuditInboundMiddleware {
+	m := &AuditInboundMiddleware{
+		sink:         sink,
+		enabled:      config.Enabled(),
+		procedures:   audit.NewProcedureSet(config.GetProcedures()),
+		records:      make(chan *auditsvc.AuditRecord, config.GetQueueSize()),
+		writeTimeout: config.GetWriteTimeout(),
+		writeFail:    scope.Counter("write_fail"),
+		dropped:      scope.Counter("dropped"),
+	}
+	if m.enabled {
+		go m.run()
+	}
+	return m
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inbound
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	statelesssvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless/svc"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+	"github.com/uber/peloton/.gen/peloton/private/auditsvc"
+
+	"github.com/uber/peloton/pkg/audit"
+	auditmocks "github.com/uber/peloton/pkg/audit/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+const (
+	_testStopJobProcedure = "peloton.api.v1alpha.job.stateless.svc.JobService::StopJob"
+	_testGetJobProcedure  = "peloton.api.v1alpha.job.stateless.svc.JobService::GetJob"
+	_testJobID            = "b64fd26b-0e39-41b7-b22a-205b69f247bd"
+	_testWriteWait        = 5 * time.Second
+)
+
+type AuditInboundMiddlewareSuite struct {
+	suite.Suite
+
+	ctrl *gomock.Controller
+	sink *auditmocks.MockSink
+	s    tally.TestScope
+	m    *AuditInboundMiddleware
+	body []byte
+
+	// written is signaled each time a record is written to the sink
+	written chan struct{}
+}
+
+func (suite *AuditInboundMiddlewareSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.sink = auditmocks.NewMockSink(suite.ctrl)
+	suite.written = make(chan struct{}, 10)
+	suite.s = tally.NewTestScope("", nil)
+	suite.m = NewAuditInboundMiddleware(
+		&audit.Config{SinkType: audit.STORAGE},
+		suite.sink,
+		suite.s,
+	)
+
+	var err error
+	suite.body, err = proto.Marshal(&statelesssvc.StopJobRequest{
+		JobId: &v1alphapeloton.JobID{Value: _testJobID},
+	})
+	suite.NoError(err)
+}
+
+func (suite *AuditInboundMiddlewareSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *AuditInboundMiddlewareSuite) newRequest(procedure string) *transport.Request {
+	return &transport.Request{
+		Caller:    "peloton-cli",
+		Service:   "peloton-jobmgr",
+		Procedure: procedure,
+		Encoding:  transport.Encoding("proto"),
+		Headers:   transport.NewHeaders().With("username", "alice"),
+		Body:      bytes.NewReader(suite.body),
+	}
+}
+
+// expectWrite expects a record to be written to the sink, checks it
+// with check and returns err
+func (suite *AuditInboundMiddlewareSuite) expectWrite(
+	check func(record *auditsvc.AuditRecord),
+	err error,
+) {
+	suite.sink.EXPECT().Write(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, record *auditsvc.AuditRecord) {
+			check(record)
+			suite.written <- struct{}{}
+		}).Return(err)
+}
+
+// waitForWrite waits for a record to be written to the sink
+func (suite *AuditInboundMiddlewareSuite) waitForWrite() {
+	select {
+	case <-suite.written:
+	case <-time.After(_testWriteWait):
+		suite.Fail("audit record not written")
+	}
+}
+
+// expectBody checks that the request body can still be read by the handler
+func (suite *AuditInboundMiddlewareSuite) expectBody(req *transport.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	suite.NoError(err)
+	suite.Equal(suite.body, body)
+}
+
+// TestHandleMutating tests auditing a mutating unary call
+func (suite *AuditInboundMiddlewareSuite) TestHandleMutating() {
+	h := transporttest.NewMockUnaryHandler(suite.ctrl)
+	digest := sha256.Sum256(suite.body)
+
+	h.EXPECT().Handle(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *transport.Request, _ transport.ResponseWriter) {
+			suite.expectBody(req)
+		}).Return(nil)
+	suite.expectWrite(func(record *auditsvc.AuditRecord) {
+		suite.Equal("alice", record.GetPrincipal())
+		suite.Equal("peloton-cli", record.GetCaller())
+		suite.Equal("peloton-jobmgr", record.GetService())
+		suite.Equal(_testStopJobProcedure, record.GetProcedure())
+		suite.Equal(_testJobID, record.GetTarget())
+		suite.Equal(hex.EncodeToString(digest[:]), record.GetRequestDigest())
+		suite.Equal("ok", record.GetResult())
+		suite.NotEmpty(record.GetTime())
+	}, nil)
+	suite.NoError(suite.m.Handle(
+		context.Background(), suite.newRequest(_testStopJobProcedure), nil, h))
+	suite.waitForWrite()
+
+	// the error code of a failed call is recorded
+	h.EXPECT().Handle(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(yarpcerrors.NotFoundErrorf("job not found"))
+	suite.expectWrite(func(record *auditsvc.AuditRecord) {
+		suite.Equal(yarpcerrors.CodeNotFound.String(), record.GetResult())
+	}, nil)
+	suite.Error(suite.m.Handle(
+		context.Background(), suite.newRequest(_testStopJobProcedure), nil, h))
+	suite.waitForWrite()
+}
+
+// TestHandleNotMutating tests read-only calls are not audited
+func (suite *AuditInboundMiddlewareSuite) TestHandleNotMutating() {
+	h := transporttest.NewMockUnaryHandler(suite.ctrl)
+
+	h.EXPECT().Handle(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	suite.NoError(suite.m.Handle(
+		context.Background(), suite.newRequest(_testGetJobProcedure), nil, h))
+
+	// non peloton procedures are not audited either
+	h.EXPECT().Handle(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	suite.NoError(suite.m.Handle(
+		context.Background(), suite.newRequest("Scheduler::Update"), nil, h))
+}
+
+// TestHandleDisabled tests no call is audited if audit is disabled
+func (suite *AuditInboundMiddlewareSuite) TestHandleDisabled() {
+	m := NewAuditInboundMiddleware(&audit.Config{}, suite.sink, suite.s)
+	h := transporttest.NewMockUnaryHandler(suite.ctrl)
+
+	h.EXPECT().Handle(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	suite.NoError(m.Handle(
+		context.Background(), suite.newRequest(_testStopJobProcedure), nil, h))
+}
+
+// TestHandleSinkFailure tests the call does not fail if the audit
+// record cannot be written
+func (suite *AuditInboundMiddlewareSuite) TestHandleSinkFailure() {
+	h := transporttest.NewMockUnaryHandler(suite.ctrl)
+
+	h.EXPECT().Handle(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	suite.expectWrite(func(*auditsvc.AuditRecord) {}, errors.New("test error"))
+	suite.NoError(suite.m.Handle(
+		context.Background(), suite.newRequest(_testStopJobProcedure), nil, h))
+	suite.waitForWrite()
+
+	// the failure is counted once the write returns
+	deadline := time.Now().Add(_testWriteWait)
+	for time.Now().Before(deadline) {
+		if c, ok := suite.s.Snapshot().Counters()["write_fail+"]; ok &&
+			c.Value() == 1 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	suite.Fail("audit write failure not counted")
+}
+
+// TestHandleQueueFull tests the audit records are dropped without
+// blocking the call when the queue is full
+func (suite *AuditInboundMiddlewareSuite) TestHandleQueueFull() {
+	// no records are written since the sink is not being drained
+	m := &AuditInboundMiddleware{
+		sink:    suite.sink,
+		enabled: true,
+		procedures: audit.NewProcedureSet(
+			[]string{_testStopJobProcedure}),
+		records:   make(chan *auditsvc.AuditRecord, 1),
+		writeFail: suite.s.Counter("write_fail"),
+		dropped:   suite.s.Counter("dropped"),
+	}
+	h := transporttest.NewMockUnaryHandler(suite.ctrl)
+
+	h.EXPECT().Handle(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).Times(2)
+	suite.NoError(m.Handle(
+		context.Background(), suite.newRequest(_testStopJobProcedure), nil, h))
+	suite.NoError(m.Handle(
+		context.Background(), suite.newRequest(_testStopJobProcedure), nil, h))
+	suite.Len(m.records, 1)
+	suite.Equal(int64(1), suite.s.Snapshot().Counters()["dropped+"].Value())
+}
+
+// TestHandleOneway tests auditing a mutating oneway call
+func (suite *AuditInboundMiddlewareSuite) TestHandleOneway() {
+	h := transporttest.NewMockOnewayHandler(suite.ctrl)
+
+	h.EXPECT().HandleOneway(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *transport.Request) {
+			suite.expectBody(req)
+		}).Return(nil)
+	suite.expectWrite(func(record *auditsvc.AuditRecord) {
+		suite.Equal(_testJobID, record.GetTarget())
+	}, nil)
+	suite.NoError(suite.m.HandleOneway(
+		context.Background(), suite.newRequest(_testStopJobProcedure), h))
+	suite.waitForWrite()
+}
+
+// TestHandleStream tests stream calls are not audited
+func (suite *AuditInboundMiddlewareSuite) TestHandleStream() {
+	h := transporttest.NewMockStreamHandler(suite.ctrl)
+	s := transporttest.NewMockStream(suite.ctrl)
+	ss, err := transport.NewServerStream(s)
+	suite.NoError(err)
+
+	h.EXPECT().HandleStream(gomock.Any()).Return(nil)
+	suite.NoError(suite.m.HandleStream(ss, h))
+}
+
+func TestAuditInboundMiddlewareSuite(t *testing.T) {
+	suite.Run(t, &AuditInboundMiddlewareSuite{})
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inbound
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	yarpcutil "github.com/uber/peloton/pkg/common/util/yarpc"
+	"github.com/uber/peloton/pkg/ratelimit"
+
+	log "github.com/sirupsen/logrus"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+var rateLimitedErrorStr = "rate limit exceeded for %s in %s"
+
+// RateLimitInboundMiddleware rejects the calls exceeding the budget of
+// the user on the procedure and respool with ResourceExhausted errors
+type RateLimitInboundMiddleware struct {
+	sync.RWMutex
+
+	limiter  *ratelimit.Limiter
+	resolver ratelimit.RespoolResolver
+}
+
+// SetRespoolResolver sets the resolver of the respool IDs passed in the
+// requests to the respool paths matched by the rules.
+func (m *RateLimitInboundMiddleware) SetRespoolResolver(
+	resolver ratelimit.RespoolResolver,
+) {
+	m.Lock()
+	defer m.Unlock()
+	m.resolver = resolver
+}
+
+// Handle checks the rate limit and invokes the underlying handler
+func (m *RateLimitInboundMiddleware) Handle(
+	ctx context.Context,
+	req *transport.Request,
+	resw transport.ResponseWriter,
+	h transport.UnaryHandler,
+) error {
+	if err := m.admit(ctx, req); err != nil {
+		return err
+	}
+	return h.Handle(ctx, req, resw)
+}
+
+// HandleOneway checks the rate limit and invokes the underlying handler
+func (m *RateLimitInboundMiddleware) HandleOneway(
+	ctx context.Context,
+	req *transport.Request,
+	h transport.OnewayHandler,
+) error {
+	if err := m.admit(ctx, req); err != nil {
+		return err
+	}
+	return h.HandleOneway(ctx, req)
+}
+
+// HandleStream checks the rate limit of opening the stream and invokes
+// the underlying handler
+func (m *RateLimitInboundMiddleware) HandleStream(
+	s *transport.ServerStream,
+	h transport.StreamHandler,
+) error {
+	meta := s.Request().Meta
+	user, _ := meta.Headers.Get(_principalHeaderKey)
+	if m.rateLimited(meta.Service) {
+		if !m.limiter.Allow(user, meta.Procedure, "") {
+			return yarpcerrors.ResourceExhaustedErrorf(
+				rateLimitedErrorStr, meta.Procedure, meta.Service)
+		}
+	}
+	return h.HandleStream(s)
+}
+
+// admit returns a ResourceExhausted error if the call is rate limited
+func (m *RateLimitInboundMiddleware) admit(
+	ctx context.Context,
+	req *transport.Request,
+) error {
+	if !m.rateLimited(req.Service) {
+		return nil
+	}
+	user, _ := req.Headers.Get(_principalHeaderKey)
+
+	var respool string
+	if m.limiter.MatchRespool() && req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		req.Body = bytes.NewReader(body)
+		if respool, err = m.canonicalRespool(ctx, ratelimit.RequestRespool(
+			yarpcutil.DecodeRequest(req.Encoding, req.Procedure, body),
+		)); err != nil {
+			return err
+		}
+	}
+
+	if !m.limiter.Allow(user, req.Procedure, respool) {
+		return yarpcerrors.ResourceExhaustedErrorf(
+			rateLimitedErrorStr, req.Procedure, req.Service)
+	}
+	return nil
+}
+
+// canonicalRespool returns the path of the respool passed in a request,
+// so that the calls cannot evade the rules by passing the respool ID
+// instead of the path.
+func (m *RateLimitInboundMiddleware) canonicalRespool(
+	ctx context.Context,
+	respool string,
+) (string, error) {
+	m.RLock()
+	resolver := m.resolver
+	m.RUnlock()
+
+	path, err := ratelimit.CanonicalRespool(ctx, respool, resolver)
+	if err != nil {
+		log.WithError(err).
+			WithField("respool", respool).
+			Info("Failed to resolve respool to rate limit call")
+		return "", yarpcerrors.InvalidArgumentErrorf(
+			"failed to resolve respool %s", respool)
+	}
+	return path, nil
+}
+
+// rateLimited returns true if the calls to service are rate limited.
+// Other services such as Mesos callback are not rate limited. The calls
+// of all the users, including the calls between peloton components and
+// the calls without a principal, are rate limited unless the user is
+// exempt in the rules.
+func (m *RateLimitInboundMiddleware) rateLimited(service string) bool {
+	return m.limiter != nil &&
+		strings.HasPrefix(service, _pelotonServicePrefix)
+}
+
+// NewRateLimitInboundMiddleware returns RateLimitInboundMiddleware which
+// rate limits the calls with limiter. The calls are not rate limited if
+// limiter is nil.
+func NewRateLimitInboundMiddleware(
+	limiter *ratelimit.Limiter,
+) *RateLimitInboundMiddleware {
+	return &RateLimitInboundMiddleware{
+		limiter: limiter,
+	}
+}
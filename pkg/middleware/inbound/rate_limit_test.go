@@ -0,0 +1,275 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inbound
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless"
+	statelesssvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless/svc"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+
+	"github.com/uber/peloton/pkg/ratelimit"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	"go.uber.org/yarpc/api/transport"
+	"go.uber.org/yarpc/api/transport/transporttest"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+const (
+	_testCreateJobProcedure = "peloton.api.v1alpha.job.stateless.svc.JobService::CreateJob"
+
+	_testRateLimitRules = `
+exempt_users:
+- peloton
+read:
+  rate: 1
+rules:
+- procedure: 'peloton.api.v1alpha.job.stateless.svc.JobService:CreateJob'
+  respool: /respool1
+  rate: 1
+`
+)
+
+type testRespoolResolver map[string]string
+
+func (r testRespoolResolver) GetRespoolPath(
+	_ context.Context,
+	respoolID string,
+) (string, error) {
+	path, ok := r[respoolID]
+	if !ok {
+		return "", yarpcerrors.NotFoundErrorf("respool %s not found", respoolID)
+	}
+	return path, nil
+}
+
+type RateLimitInboundMiddlewareSuite struct {
+	suite.Suite
+
+	ctrl *gomock.Controller
+	dir  string
+	m    *RateLimitInboundMiddleware
+}
+
+func (suite *RateLimitInboundMiddlewareSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+
+	var err error
+	suite.dir, err = ioutil.TempDir("", "ratelimit")
+	suite.NoError(err)
+	path := filepath.Join(suite.dir, "rules.yaml")
+	suite.NoError(ioutil.WriteFile(path, []byte(_testRateLimitRules), 0600))
+
+	limiter, err := ratelimit.NewLimiter(
+		&ratelimit.Config{Path: path},
+		tally.NoopScope,
+	)
+	suite.NoError(err)
+	suite.m = NewRateLimitInboundMiddleware(limiter)
+	suite.m.SetRespoolResolver(testRespoolResolver{"respool1": "/respool1"})
+}
+
+func (suite *RateLimitInboundMiddlewareSuite) TearDownTest() {
+	suite.ctrl.Finish()
+	os.RemoveAll(suite.dir)
+}
+
+func (suite *RateLimitInboundMiddlewareSuite) newRequest(
+	procedure string,
+	body []byte,
+) *transport.Request {
+	return &transport.Request{
+		Service:   "peloton-jobmgr",
+		Caller:    "peloton-cli",
+		Procedure: procedure,
+		Encoding:  transport.Encoding("proto"),
+		Headers:   transport.NewHeaders().With("username", "alice"),
+		Body:      bytes.NewReader(body),
+	}
+}
+
+// TestHandle tests unary calls exceeding the budget are rejected
+func (suite *RateLimitInboundMiddlewareSuite) TestHandle() {
+	h := transporttest.NewMockUnaryHandler(suite.ctrl)
+
+	h.EXPECT().Handle(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	suite.NoError(suite.m.Handle(
+		context.Background(), suite.newRequest(_testGetJobProcedure, nil), nil, h))
+
+	err := suite.m.Handle(
+		context.Background(), suite.newRequest(_testGetJobProcedure, nil), nil, h)
+	suite.True(yarpcerrors.IsResourceExhausted(err))
+
+	// non peloton services are not rate limited
+	req := suite.newRequest("Scheduler::Update", nil)
+	req.Service = "Scheduler"
+	h.EXPECT().Handle(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	suite.NoError(suite.m.Handle(context.Background(), req, nil, h))
+}
+
+// TestHandleExempt tests only the calls of the exempt users are not rate
+// limited, and the calls claiming to come from peloton components or
+// without a principal are
+func (suite *RateLimitInboundMiddlewareSuite) TestHandleExempt() {
+	h := transporttest.NewMockUnaryHandler(suite.ctrl)
+	h.EXPECT().Handle(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(4)
+
+	for i := 0; i < 2; i++ {
+		req := suite.newRequest(_testGetJobProcedure, nil)
+		req.Headers = transport.NewHeaders().With("username", "peloton")
+		suite.NoError(suite.m.Handle(context.Background(), req, nil, h))
+	}
+
+	// the caller header is set by the clients
+	req := suite.newRequest(_testGetJobProcedure, nil)
+	req.Caller = "peloton-resmgr"
+	suite.NoError(suite.m.Handle(context.Background(), req, nil, h))
+	req = suite.newRequest(_testGetJobProcedure, nil)
+	req.Caller = "peloton-resmgr"
+	suite.True(yarpcerrors.IsResourceExhausted(
+		suite.m.Handle(context.Background(), req, nil, h)))
+
+	// the calls without a principal share one bucket
+	req = suite.newRequest(_testGetJobProcedure, nil)
+	req.Headers = transport.NewHeaders()
+	suite.NoError(suite.m.Handle(context.Background(), req, nil, h))
+	req = suite.newRequest(_testGetJobProcedure, nil)
+	req.Headers = transport.NewHeaders()
+	suite.True(yarpcerrors.IsResourceExhausted(
+		suite.m.Handle(context.Background(), req, nil, h)))
+}
+
+// TestHandleRespool tests the calls are rate limited by respool, and
+// the request body can still be read by the handler
+func (suite *RateLimitInboundMiddlewareSuite) TestHandleRespool() {
+	h := transporttest.NewMockUnaryHandler(suite.ctrl)
+	body, err := proto.Marshal(&statelesssvc.CreateJobRequest{
+		Spec: &stateless.JobSpec{
+			RespoolId: &v1alphapeloton.ResourcePoolID{Value: "respool1"},
+		},
+	})
+	suite.NoError(err)
+
+	h.EXPECT().Handle(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *transport.Request, _ transport.ResponseWriter) {
+			b, err := ioutil.ReadAll(req.Body)
+			suite.NoError(err)
+			suite.Equal(body, b)
+		}).Return(nil)
+	suite.NoError(suite.m.Handle(
+		context.Background(), suite.newRequest(_testCreateJobProcedure, body), nil, h))
+
+	err = suite.m.Handle(
+		context.Background(), suite.newRequest(_testCreateJobProcedure, body), nil, h)
+	suite.True(yarpcerrors.IsResourceExhausted(err))
+}
+
+// TestHandleRespoolPath tests the calls passing the respool ID and the
+// calls passing the respool path share the budget of the respool
+func (suite *RateLimitInboundMiddlewareSuite) TestHandleRespoolPath() {
+	h := transporttest.NewMockUnaryHandler(suite.ctrl)
+	idBody, err := proto.Marshal(&statelesssvc.CreateJobRequest{
+		Spec: &stateless.JobSpec{
+			RespoolId: &v1alphapeloton.ResourcePoolID{Value: "respool1"},
+		},
+	})
+	suite.NoError(err)
+	pathBody, err := proto.Marshal(&statelesssvc.CreateJobRequest{
+		Spec: &stateless.JobSpec{
+			RespoolId: &v1alphapeloton.ResourcePoolID{Value: "/respool1/"},
+		},
+	})
+	suite.NoError(err)
+
+	h.EXPECT().Handle(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	suite.NoError(suite.m.Handle(
+		context.Background(), suite.newRequest(_testCreateJobProcedure, pathBody), nil, h))
+
+	err = suite.m.Handle(
+		context.Background(), suite.newRequest(_testCreateJobProcedure, idBody), nil, h)
+	suite.True(yarpcerrors.IsResourceExhausted(err))
+
+	// the calls with a respool which cannot be resolved are rejected
+	unknownBody, err := proto.Marshal(&statelesssvc.CreateJobRequest{
+		Spec: &stateless.JobSpec{
+			RespoolId: &v1alphapeloton.ResourcePoolID{Value: "respool2"},
+		},
+	})
+	suite.NoError(err)
+	err = suite.m.Handle(
+		context.Background(), suite.newRequest(_testCreateJobProcedure, unknownBody), nil, h)
+	suite.True(yarpcerrors.IsInvalidArgument(err))
+}
+
+// TestHandleOneway tests oneway calls exceeding the budget are rejected
+func (suite *RateLimitInboundMiddlewareSuite) TestHandleOneway() {
+	h := transporttest.NewMockOnewayHandler(suite.ctrl)
+
+	h.EXPECT().HandleOneway(gomock.Any(), gomock.Any()).Return(nil)
+	suite.NoError(suite.m.HandleOneway(
+		context.Background(), suite.newRequest(_testGetJobProcedure, nil), h))
+
+	err := suite.m.HandleOneway(
+		context.Background(), suite.newRequest(_testGetJobProcedure, nil), h)
+	suite.True(yarpcerrors.IsResourceExhausted(err))
+}
+
+// TestHandleStream tests opening streams exceeding the budget is rejected
+func (suite *RateLimitInboundMiddlewareSuite) TestHandleStream() {
+	h := transporttest.NewMockStreamHandler(suite.ctrl)
+	s := transporttest.NewMockStream(suite.ctrl)
+	ss, err := transport.NewServerStream(s)
+	suite.NoError(err)
+
+	s.EXPECT().Request().Return(
+		&transport.StreamRequest{
+			Meta: &transport.RequestMeta{
+				Service:   "peloton-jobmgr",
+				Procedure: _testGetJobProcedure,
+				Headers:   transport.NewHeaders().With("username", "alice"),
+			},
+		},
+	).AnyTimes()
+
+	h.EXPECT().HandleStream(gomock.Any()).Return(nil)
+	suite.NoError(suite.m.HandleStream(ss, h))
+	suite.True(yarpcerrors.IsResourceExhausted(suite.m.HandleStream(ss, h)))
+}
+
+// TestDisabled tests no call is rate limited without limiter
+func (suite *RateLimitInboundMiddlewareSuite) TestDisabled() {
+	m := NewRateLimitInboundMiddleware(nil)
+	h := transporttest.NewMockUnaryHandler(suite.ctrl)
+
+	h.EXPECT().Handle(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	for i := 0; i < 2; i++ {
+		suite.NoError(m.Handle(
+			context.Background(), suite.newRequest(_testGetJobProcedure, nil), nil, h))
+	}
+}
+
+func TestRateLimitInboundMiddlewareSuite(t *testing.T) {
+	suite.Run(t, &RateLimitInboundMiddlewareSuite{})
+}
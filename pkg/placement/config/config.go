@@ -32,6 +32,15 @@ const (
 	Batch = PlacementStrategy("batch")
 	// Mimir is the Mimir strategy
 	Mimir = PlacementStrategy("mimir")
+	// Scoring is the scoring strategy
+	Scoring = PlacementStrategy("scoring")
+
+	// BestFit is the scoring policy which prefers the hosts that are left
+	// with the least free resources, to pack tasks onto fewer hosts.
+	BestFit = ScoringPolicy("best_fit")
+	// LeastAllocated is the scoring policy which prefers the hosts that are
+	// left with the most free resources, to spread tasks over the hosts.
+	LeastAllocated = ScoringPolicy("least_allocated")
 )
 
 // Config holds all configs to run a placement engine.
@@ -83,6 +92,14 @@ type PlacementConfig struct {
 	// Strategy is the placement strategy that the engine should use.
 	Strategy PlacementStrategy `yaml:"strategy"`
 
+	// Strategies overrides the placement strategy per task type. The
+	// strategy for the task type of the engine is used if set, otherwise
+	// the default strategy for the task type.
+	Strategies StrategiesConfig `yaml:"strategies"`
+
+	// Scoring is the config of the scoring strategy.
+	Scoring ScoringConfig `yaml:"scoring"`
+
 	// Concurrency is the maximal worker concurrency in the engine.
 	Concurrency int `yaml:"concurrency"`
 
@@ -100,6 +117,75 @@ type PlacementConfig struct {
 	// MaxDesiredHostPlacementDuration is the max time duration to try to
 	// place a task on the desired host.
 	MaxDesiredHostPlacementDuration time.Duration `yaml:"max_desired_host_placement_duration"`
+
+	// DefaultHostOrdering is the order in which the Mimir strategy
+	// considers the hosts satisfying the constraints of a task, after its
+	// desired host and placement preferences. If empty, hosts with the most
+	// free disk, memory, CPU and GPU, in that order, are preferred.
+	DefaultHostOrdering []HostOrderingConfig `yaml:"default_host_ordering"`
+}
+
+// HostOrderingConfig is one level of a lexicographic host ordering, ties
+// are broken by the next level.
+type HostOrderingConfig struct {
+	// Metric is the free resource to order hosts by, one of cpu_free,
+	// memory_free, disk_free and gpu_free.
+	Metric string `yaml:"metric"`
+
+	// Ascending prefers hosts with less of the free resource, which packs
+	// tasks onto fewer hosts. By default hosts with more of the free
+	// resource are preferred, which spreads tasks.
+	Ascending bool `yaml:"ascending"`
+}
+
+// StrategiesConfig is the config of the placement strategy per task type.
+type StrategiesConfig struct {
+	Unknown   PlacementStrategy `yaml:"unknown"`
+	Batch     PlacementStrategy `yaml:"batch"`
+	Stateless PlacementStrategy `yaml:"stateless"`
+	Daemon    PlacementStrategy `yaml:"daemon"`
+	Stateful  PlacementStrategy `yaml:"stateful"`
+}
+
+// Value returns the value of the config for the given task type.
+func (c StrategiesConfig) Value(t resmgr.TaskType) PlacementStrategy {
+	switch t {
+	case resmgr.TaskType_UNKNOWN:
+		return c.Unknown
+	case resmgr.TaskType_BATCH:
+		return c.Batch
+	case resmgr.TaskType_STATELESS:
+		return c.Stateless
+	case resmgr.TaskType_DAEMON:
+		return c.Daemon
+	case resmgr.TaskType_STATEFUL:
+		return c.Stateful
+	}
+	return ""
+}
+
+// ScoringPolicy determines how the scoring strategy scores hosts.
+type ScoringPolicy string
+
+// ScoringConfig is the config of the scoring strategy, which places each
+// task on the host with the best weighted score of the resources which would
+// be left free on the host.
+type ScoringConfig struct {
+	// Policy is the scoring policy, best_fit or least_allocated.
+	// Defaults to best_fit.
+	Policy ScoringPolicy `yaml:"policy"`
+
+	// Weights are the weights of the resources in the score.
+	Weights ScoringWeightsConfig `yaml:"weights"`
+}
+
+// ScoringWeightsConfig is the weight per resource in the score of a host.
+// If all weights are zero, all resources have the same weight.
+type ScoringWeightsConfig struct {
+	CPU    float64 `yaml:"cpu"`
+	Memory float64 `yaml:"memory"`
+	Disk   float64 `yaml:"disk"`
+	GPU    float64 `yaml:"gpu"`
 }
 
 // MaxRoundsConfig is the config of the maximal number of successful rounds
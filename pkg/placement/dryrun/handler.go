@@ -0,0 +1,415 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dryrun
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	mesos "github.com/uber/peloton/.gen/mesos/v1"
+	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
+	"github.com/uber/peloton/.gen/peloton/private/placementsvc"
+	"github.com/uber/peloton/.gen/peloton/private/resmgr"
+	"github.com/uber/peloton/.gen/peloton/private/resmgrsvc"
+
+	"github.com/uber/peloton/pkg/common"
+	"github.com/uber/peloton/pkg/common/taskconfig"
+	"github.com/uber/peloton/pkg/common/util"
+	taskutil "github.com/uber/peloton/pkg/common/util/task"
+	yarpcutil "github.com/uber/peloton/pkg/common/util/yarpc"
+	"github.com/uber/peloton/pkg/hostmgr/scalar"
+	"github.com/uber/peloton/pkg/placement/config"
+	"github.com/uber/peloton/pkg/placement/models"
+	"github.com/uber/peloton/pkg/placement/offers"
+	"github.com/uber/peloton/pkg/placement/plugins"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+// _maxInstances is the maximum number of instances of a job which can be
+// placed in a dry run.
+const _maxInstances = 10000
+
+var (
+	errNilJobConfig       = yarpcerrors.InvalidArgumentErrorf("job config is not set")
+	errNoInstances        = yarpcerrors.InvalidArgumentErrorf("job config has no instances")
+	errNilResourcePool    = yarpcerrors.InvalidArgumentErrorf("resource pool of the job is not set")
+	errResourcePoolAbsent = yarpcerrors.NotFoundErrorf("resource pool of the job not found")
+	errTooManyInstances   = yarpcerrors.InvalidArgumentErrorf("job config has more than %d instances", _maxInstances)
+)
+
+type serviceHandler struct {
+	// lock serializes the placement rounds of the dry runs when the
+	// strategy is not concurrency safe
+	lock sync.Mutex
+
+	config       *config.PlacementConfig
+	offerService offers.Service
+	resmgrClient resmgrsvc.ResourceManagerServiceYARPCClient
+	strategy     plugins.Strategy
+}
+
+// InitServiceHandler initializes the dry-run placement service handler of
+// the placement engine. The strategy must not be shared with the placement
+// engine unless it is concurrency safe.
+func InitServiceHandler(
+	d *yarpc.Dispatcher,
+	cfg *config.PlacementConfig,
+	offerService offers.Service,
+	resmgrClient resmgrsvc.ResourceManagerServiceYARPCClient,
+	strategy plugins.Strategy,
+) {
+	handler := &serviceHandler{
+		config:       cfg,
+		offerService: offerService,
+		resmgrClient: resmgrClient,
+		strategy:     strategy,
+	}
+	d.Register(placementsvc.BuildPlacementServiceYARPCProcedures(handler))
+}
+
+// DryRunPlacement places the instances of a job using the placement
+// strategy, against offers which are not claimed from host manager.
+func (h *serviceHandler) DryRunPlacement(
+	ctx context.Context,
+	req *placementsvc.DryRunPlacementRequest,
+) (resp *placementsvc.DryRunPlacementResponse, err error) {
+	defer func() {
+		if err != nil {
+			log.WithField("request", req).
+				WithError(err).
+				Info("PlacementSVC.DryRunPlacement failed")
+			err = yarpcutil.ConvertToYARPCError(err)
+			return
+		}
+
+		log.WithField("request", req).
+			WithField("response", resp).
+			Debug("PlacementSVC.DryRunPlacement succeeded")
+	}()
+
+	jobConfig := req.GetConfig()
+	if err := validateJobConfig(jobConfig); err != nil {
+		return nil, err
+	}
+
+	gangs := taskutil.ConvertToResMgrGangs(
+		createTaskInfos(jobConfig),
+		jobConfig)
+
+	admission, err := h.checkAdmission(ctx, jobConfig.GetRespoolID(), gangs)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := h.createAssignments(gangs, time.Now())
+	h.place(ctx, assignments)
+
+	return &placementsvc.DryRunPlacementResponse{
+		Placements: toInstancePlacements(assignments),
+		Admission:  admission,
+		Strategy:   string(h.config.Strategy),
+	}, nil
+}
+
+// checkAdmission asks resource manager whether the gangs would be admitted
+// to the resource pool right now.
+func (h *serviceHandler) checkAdmission(
+	ctx context.Context,
+	respoolID *peloton.ResourcePoolID,
+	gangs []*resmgrsvc.Gang,
+) (*placementsvc.AdmissionResult, error) {
+	resp, err := h.resmgrClient.CheckAdmission(
+		ctx,
+		&resmgrsvc.CheckAdmissionRequest{
+			ResPool: respoolID,
+			Gangs:   gangs,
+		})
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetError().GetNotFound() != nil {
+		return nil, errResourcePoolAbsent
+	}
+
+	return &placementsvc.AdmissionResult{
+		Admitted: resp.GetAdmitted(),
+		Reason:   resp.GetReason(),
+	}, nil
+}
+
+// createAssignments creates the placement assignments for the tasks in
+// the gangs, the same way the task service does for dequeued gangs.
+func (h *serviceHandler) createAssignments(
+	gangs []*resmgrsvc.Gang,
+	now time.Time,
+) []*models.Assignment {
+	var assignments []*models.Assignment
+	for _, gang := range gangs {
+		for _, rmTask := range gang.GetTasks() {
+			deadline := now.Add(h.config.MaxDurations.Value(rmTask.GetType()))
+			assignments = append(assignments, models.NewAssignment(
+				models.NewTask(
+					gang,
+					rmTask,
+					deadline,
+					now.Add(h.config.MaxDesiredHostPlacementDuration),
+					h.config.MaxRounds.Value(rmTask.GetType()),
+				)))
+		}
+	}
+	return assignments
+}
+
+// place runs one placement round for each group of assignments sharing
+// a host filter. Offers are only peeked at, so they need not be released.
+// Peeked offers are not claimed either, so the resources of the tasks
+// placed by the earlier rounds are taken out of the offers of the hosts.
+func (h *serviceHandler) place(
+	ctx context.Context,
+	assignments []*models.Assignment) {
+	if !h.strategy.ConcurrencySafe() {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+	}
+
+	used := make(map[string]*usage)
+	for filter, batch := range h.strategy.Filters(assignments) {
+		// The job of a dry run has no running tasks, so the tasks per
+		// topology domain are the ones placed by the earlier rounds.
+		scope := usedHosts(used)
+		hosts, reason := h.offerService.Peek(
+			ctx,
+			h.config.FetchOfferTasks,
+			batch[0].GetTask().GetTask().GetType(),
+			plugins.WithTopologyDomainCounts(filter, batch, scope))
+
+		if len(hosts) > 0 {
+			h.placeOnce(batch, withoutUsage(hosts, used), scope)
+			recordUsage(used, batch)
+		}
+
+		// The reason from the offer service holds the host filter result
+		// counts, use it for tasks without a transcript from the strategy.
+		for _, assignment := range batch {
+			if assignment.GetHost() == nil && assignment.GetReason() == "" {
+				assignment.SetReason(reason)
+			}
+		}
+	}
+}
+
+// placeOnce places the assignments on the hosts with the placement
+// strategy. Strategies which honor constraints spanning several hosts get
+// the hosts of the tasks placed by the earlier rounds as scope.
+func (h *serviceHandler) placeOnce(
+	assignments []*models.Assignment,
+	hosts []*models.HostOffers,
+	scope []*models.Host) {
+	strategy, ok := h.strategy.(plugins.ScopedStrategy)
+	if !ok {
+		h.strategy.PlaceOnce(assignments, hosts)
+		return
+	}
+	strategy.PlaceOnceInScope(assignments, hosts, scope)
+}
+
+// usedHosts returns the hosts with the tasks placed on them.
+func usedHosts(used map[string]*usage) []*models.Host {
+	var result []*models.Host
+	for _, u := range used {
+		result = append(result, models.NewHosts(u.host, u.tasks))
+	}
+	return result
+}
+
+// usage is the resources of the tasks placed on a host.
+type usage struct {
+	resources scalar.Resources
+	ports     uint64
+
+	// host and tasks are the host and the tasks placed on it
+	host  *hostsvc.HostInfo
+	tasks []*resmgr.Task
+}
+
+// recordUsage adds the resources of the placed tasks to the usage of
+// their hosts.
+func recordUsage(used map[string]*usage, assignments []*models.Assignment) {
+	for _, assignment := range assignments {
+		host := assignment.GetHost()
+		if host == nil {
+			continue
+		}
+		hostname := host.GetOffer().GetHostname()
+		u, ok := used[hostname]
+		if !ok {
+			u = &usage{
+				host: &hostsvc.HostInfo{
+					Hostname:   hostname,
+					AgentId:    host.GetOffer().GetAgentId(),
+					Attributes: host.GetOffer().GetAttributes(),
+				},
+			}
+			used[hostname] = u
+		}
+		rmTask := assignment.GetTask().GetTask()
+		u.resources = u.resources.Add(
+			scalar.FromResourceConfig(rmTask.GetResource()))
+		u.ports += uint64(rmTask.GetNumPorts())
+		u.tasks = append(u.tasks, rmTask)
+	}
+}
+
+// withoutUsage returns the hosts with the resources used on them taken
+// out of their offers. The hosts without usage are returned unchanged.
+func withoutUsage(
+	hosts []*models.HostOffers,
+	used map[string]*usage,
+) []*models.HostOffers {
+	result := make([]*models.HostOffers, 0, len(hosts))
+	for _, host := range hosts {
+		u, ok := used[host.GetOffer().GetHostname()]
+		if !ok {
+			result = append(result, host)
+			continue
+		}
+		offer := proto.Clone(host.GetOffer()).(*hostsvc.HostOffer)
+		offer.Resources = subtractResources(offer.GetResources(), u)
+		result = append(result,
+			models.NewHostOffers(offer, host.GetTasks(), host.Claimed))
+	}
+	return result
+}
+
+// subtractResources takes the usage out of the Mesos resources, which
+// are modified in place.
+func subtractResources(
+	resources []*mesos.Resource,
+	u *usage,
+) []*mesos.Resource {
+	remaining := map[string]float64{
+		common.MesosCPU:              u.resources.GetCPU(),
+		common.MesosMem:              u.resources.GetMem(),
+		common.MesosDisk:             u.resources.GetDisk(),
+		common.MesosGPU:              u.resources.GetGPU(),
+		common.MesosNetworkBandwidth: u.resources.GetNetwork(),
+	}
+	ports := u.ports
+
+	for _, resource := range resources {
+		name := resource.GetName()
+		if name == common.MesosPorts {
+			var ranges []*mesos.Value_Range
+			for _, r := range resource.GetRanges().GetRange() {
+				begin, end := r.GetBegin(), r.GetEnd()
+				if n := end - begin + 1; ports >= n {
+					ports -= n
+					continue
+				}
+				begin += ports
+				ports = 0
+				ranges = append(ranges, &mesos.Value_Range{
+					Begin: &begin,
+					End:   &end,
+				})
+			}
+			resource.Ranges = &mesos.Value_Ranges{Range: ranges}
+			continue
+		}
+
+		if remaining[name] <= 0 || resource.GetScalar() == nil {
+			continue
+		}
+		value := resource.GetScalar().GetValue()
+		taken := remaining[name]
+		if taken > value {
+			taken = value
+		}
+		value -= taken
+		remaining[name] -= taken
+		resource.Scalar = &mesos.Value_Scalar{Value: &value}
+	}
+	return resources
+}
+
+// toInstancePlacements converts the assignments to the instance
+// placements of the response, sorted by instance id.
+func toInstancePlacements(
+	assignments []*models.Assignment,
+) []*placementsvc.InstancePlacement {
+	placements := make([]*placementsvc.InstancePlacement, 0, len(assignments))
+	for _, assignment := range assignments {
+		// task ids are created by createTaskInfos, so they always parse
+		_, instanceID, _ := util.ParseTaskID(
+			assignment.GetTask().GetTask().GetId().GetValue())
+		placement := &placementsvc.InstancePlacement{
+			InstanceId: instanceID,
+		}
+		if host := assignment.GetHost(); host != nil {
+			placement.Hostname = host.GetOffer().GetHostname()
+		} else {
+			placement.Reason = assignment.GetReason()
+		}
+		placements = append(placements, placement)
+	}
+	sort.Slice(placements, func(i, j int) bool {
+		return placements[i].GetInstanceId() < placements[j].GetInstanceId()
+	})
+	return placements
+}
+
+// createTaskInfos creates the task infos of all instances of the job,
+// with the instance config merged into the default config.
+func createTaskInfos(jobConfig *job.JobConfig) []*task.TaskInfo {
+	jobID := &peloton.JobID{Value: uuid.New()}
+	taskInfos := make([]*task.TaskInfo, 0, jobConfig.GetInstanceCount())
+	for i := uint32(0); i < jobConfig.GetInstanceCount(); i++ {
+		taskInfos = append(taskInfos, &task.TaskInfo{
+			JobId:      jobID,
+			InstanceId: i,
+			Config: taskconfig.Merge(
+				jobConfig.GetDefaultConfig(),
+				jobConfig.GetInstanceConfig()[i]),
+			Runtime: &task.RuntimeInfo{},
+		})
+	}
+	return taskInfos
+}
+
+func validateJobConfig(jobConfig *job.JobConfig) error {
+	if jobConfig == nil {
+		return errNilJobConfig
+	}
+	if jobConfig.GetInstanceCount() == 0 {
+		return errNoInstances
+	}
+	if jobConfig.GetInstanceCount() > _maxInstances {
+		return errTooManyInstances
+	}
+	if jobConfig.GetRespoolID() == nil {
+		return errNilResourcePool
+	}
+	return nil
+}
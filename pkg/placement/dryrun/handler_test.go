@@ -0,0 +1,349 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dryrun
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
+	"github.com/uber/peloton/.gen/peloton/private/placementsvc"
+	"github.com/uber/peloton/.gen/peloton/private/resmgrsvc"
+	resmgr_mocks "github.com/uber/peloton/.gen/peloton/private/resmgrsvc/mocks"
+
+	"github.com/uber/peloton/pkg/hostmgr/scalar"
+	"github.com/uber/peloton/pkg/placement/config"
+	"github.com/uber/peloton/pkg/placement/models"
+	offers_mocks "github.com/uber/peloton/pkg/placement/offers/mocks"
+	strategy_mocks "github.com/uber/peloton/pkg/placement/plugins/mocks"
+	"github.com/uber/peloton/pkg/placement/testutil"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+type DryRunHandlerTestSuite struct {
+	suite.Suite
+
+	ctrl             *gomock.Controller
+	mockOfferService *offers_mocks.MockService
+	mockResmgr       *resmgr_mocks.MockResourceManagerServiceYARPCClient
+	mockStrategy     *strategy_mocks.MockStrategy
+	handler          *serviceHandler
+}
+
+func (suite *DryRunHandlerTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockOfferService = offers_mocks.NewMockService(suite.ctrl)
+	suite.mockResmgr = resmgr_mocks.NewMockResourceManagerServiceYARPCClient(suite.ctrl)
+	suite.mockStrategy = strategy_mocks.NewMockStrategy(suite.ctrl)
+	suite.handler = &serviceHandler{
+		config: &config.PlacementConfig{
+			Strategy: config.Batch,
+			MaxDurations: config.MaxDurationsConfig{
+				Batch: 5 * time.Second,
+			},
+		},
+		offerService: suite.mockOfferService,
+		resmgrClient: suite.mockResmgr,
+		strategy:     suite.mockStrategy,
+	}
+}
+
+func (suite *DryRunHandlerTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func TestDryRunHandler(t *testing.T) {
+	suite.Run(t, new(DryRunHandlerTestSuite))
+}
+
+func (suite *DryRunHandlerTestSuite) jobConfig(instanceCount uint32) *job.JobConfig {
+	return &job.JobConfig{
+		Type:          job.JobType_BATCH,
+		InstanceCount: instanceCount,
+		RespoolID:     &peloton.ResourcePoolID{Value: "respool"},
+		DefaultConfig: &task.TaskConfig{
+			Resource: &task.ResourceConfig{
+				CpuLimit:   1,
+				MemLimitMb: 10,
+			},
+		},
+	}
+}
+
+// TestDryRunPlacement tests placing a job with one placed and one
+// unplaced instance.
+func (suite *DryRunHandlerTestSuite) TestDryRunPlacement() {
+	filter := &hostsvc.HostFilter{}
+	host := testutil.SetupHostOffers()
+
+	suite.mockResmgr.EXPECT().
+		CheckAdmission(gomock.Any(), gomock.Any()).
+		Return(&resmgrsvc.CheckAdmissionResponse{
+			Admitted: false,
+			Reason:   "resource pool full",
+		}, nil)
+	suite.mockStrategy.EXPECT().ConcurrencySafe().Return(true)
+	suite.mockStrategy.EXPECT().
+		Filters(gomock.Any()).
+		DoAndReturn(func(
+			assignments []*models.Assignment,
+		) map[*hostsvc.HostFilter][]*models.Assignment {
+			return map[*hostsvc.HostFilter][]*models.Assignment{
+				filter: assignments,
+			}
+		})
+	suite.mockOfferService.EXPECT().
+		Peek(gomock.Any(), false, gomock.Any(), filter).
+		Return([]*models.HostOffers{host}, "filter results")
+	suite.mockStrategy.EXPECT().
+		PlaceOnce(gomock.Any(), []*models.HostOffers{host}).
+		Do(func(
+			assignments []*models.Assignment,
+			hosts []*models.HostOffers,
+		) {
+			// only place the first assignment, leave the reason
+			// of the second one to the offer service
+			assignments[0].SetHost(hosts[0])
+		})
+
+	resp, err := suite.handler.DryRunPlacement(
+		context.Background(),
+		&placementsvc.DryRunPlacementRequest{Config: suite.jobConfig(2)})
+	suite.NoError(err)
+
+	suite.False(resp.GetAdmission().GetAdmitted())
+	suite.Equal("resource pool full", resp.GetAdmission().GetReason())
+	suite.Equal(string(config.Batch), resp.GetStrategy())
+
+	suite.Len(resp.GetPlacements(), 2)
+	var placed, unplaced *placementsvc.InstancePlacement
+	for i, placement := range resp.GetPlacements() {
+		suite.Equal(uint32(i), placement.GetInstanceId())
+		if placement.GetHostname() != "" {
+			placed = placement
+		} else {
+			unplaced = placement
+		}
+	}
+	suite.Equal(host.GetOffer().GetHostname(), placed.GetHostname())
+	suite.Empty(placed.GetReason())
+	suite.Equal("filter results", unplaced.GetReason())
+}
+
+// TestDryRunPlacementNoOffers tests that no placement is attempted when
+// there are no offers.
+func (suite *DryRunHandlerTestSuite) TestDryRunPlacementNoOffers() {
+	filter := &hostsvc.HostFilter{}
+
+	suite.mockResmgr.EXPECT().
+		CheckAdmission(gomock.Any(), gomock.Any()).
+		Return(&resmgrsvc.CheckAdmissionResponse{Admitted: true}, nil)
+	suite.mockStrategy.EXPECT().ConcurrencySafe().Return(true)
+	suite.mockStrategy.EXPECT().
+		Filters(gomock.Any()).
+		DoAndReturn(func(
+			assignments []*models.Assignment,
+		) map[*hostsvc.HostFilter][]*models.Assignment {
+			return map[*hostsvc.HostFilter][]*models.Assignment{
+				filter: assignments,
+			}
+		})
+	suite.mockOfferService.EXPECT().
+		Peek(gomock.Any(), false, gomock.Any(), filter).
+		Return(nil, "no offers from the cluster")
+
+	resp, err := suite.handler.DryRunPlacement(
+		context.Background(),
+		&placementsvc.DryRunPlacementRequest{Config: suite.jobConfig(1)})
+	suite.NoError(err)
+	suite.True(resp.GetAdmission().GetAdmitted())
+	suite.Len(resp.GetPlacements(), 1)
+	suite.Empty(resp.GetPlacements()[0].GetHostname())
+	suite.Equal("no offers from the cluster", resp.GetPlacements()[0].GetReason())
+}
+
+// TestDryRunPlacementUsedResources tests that the resources of the tasks
+// placed for one host filter are taken out of the offers of the hosts
+// for the next host filter.
+func (suite *DryRunHandlerTestSuite) TestDryRunPlacementUsedResources() {
+	filter1 := &hostsvc.HostFilter{}
+	filter2 := &hostsvc.HostFilter{}
+	host := testutil.SetupHostOffers()
+	cpus := scalar.FromMesosResources(host.GetOffer().GetResources()).GetCPU()
+
+	suite.mockResmgr.EXPECT().
+		CheckAdmission(gomock.Any(), gomock.Any()).
+		Return(&resmgrsvc.CheckAdmissionResponse{Admitted: true}, nil)
+	suite.mockStrategy.EXPECT().ConcurrencySafe().Return(false)
+	suite.mockStrategy.EXPECT().
+		Filters(gomock.Any()).
+		DoAndReturn(func(
+			assignments []*models.Assignment,
+		) map[*hostsvc.HostFilter][]*models.Assignment {
+			return map[*hostsvc.HostFilter][]*models.Assignment{
+				filter1: assignments[:1],
+				filter2: assignments[1:],
+			}
+		})
+	suite.mockOfferService.EXPECT().
+		Peek(gomock.Any(), false, gomock.Any(), gomock.Any()).
+		Return([]*models.HostOffers{host}, "").
+		Times(2)
+
+	var offeredCPUs []float64
+	suite.mockStrategy.EXPECT().
+		PlaceOnce(gomock.Any(), gomock.Any()).
+		Do(func(
+			assignments []*models.Assignment,
+			hosts []*models.HostOffers,
+		) {
+			offeredCPUs = append(offeredCPUs, scalar.FromMesosResources(
+				hosts[0].GetOffer().GetResources()).GetCPU())
+			assignments[0].SetHost(hosts[0])
+		}).
+		Times(2)
+
+	resp, err := suite.handler.DryRunPlacement(
+		context.Background(),
+		&placementsvc.DryRunPlacementRequest{Config: suite.jobConfig(2)})
+	suite.NoError(err)
+	suite.Len(resp.GetPlacements(), 2)
+	suite.Equal([]float64{cpus, cpus - 1}, offeredCPUs)
+	// the peeked offer is left unchanged
+	suite.Equal(cpus,
+		scalar.FromMesosResources(host.GetOffer().GetResources()).GetCPU())
+}
+
+// TestDryRunPlacementInScope tests that scoped strategies get the hosts of
+// the tasks placed by the earlier rounds as scope.
+func (suite *DryRunHandlerTestSuite) TestDryRunPlacementInScope() {
+	strategy := strategy_mocks.NewMockScopedStrategy(suite.ctrl)
+	suite.handler.strategy = strategy
+	filter1 := &hostsvc.HostFilter{}
+	filter2 := &hostsvc.HostFilter{}
+	host := testutil.SetupHostOffers()
+
+	suite.mockResmgr.EXPECT().
+		CheckAdmission(gomock.Any(), gomock.Any()).
+		Return(&resmgrsvc.CheckAdmissionResponse{Admitted: true}, nil)
+	strategy.EXPECT().ConcurrencySafe().Return(false)
+	strategy.EXPECT().
+		Filters(gomock.Any()).
+		DoAndReturn(func(
+			assignments []*models.Assignment,
+		) map[*hostsvc.HostFilter][]*models.Assignment {
+			return map[*hostsvc.HostFilter][]*models.Assignment{
+				filter1: assignments[:1],
+				filter2: assignments[1:],
+			}
+		})
+	suite.mockOfferService.EXPECT().
+		Peek(gomock.Any(), false, gomock.Any(), gomock.Any()).
+		Return([]*models.HostOffers{host}, "").
+		Times(2)
+
+	var scopes [][]*models.Host
+	strategy.EXPECT().
+		PlaceOnceInScope(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(
+			assignments []*models.Assignment,
+			hosts []*models.HostOffers,
+			scope []*models.Host,
+		) {
+			scopes = append(scopes, scope)
+			assignments[0].SetHost(hosts[0])
+		}).
+		Times(2)
+
+	_, err := suite.handler.DryRunPlacement(
+		context.Background(),
+		&placementsvc.DryRunPlacementRequest{Config: suite.jobConfig(2)})
+	suite.NoError(err)
+	suite.Len(scopes, 2)
+	suite.Empty(scopes[0])
+	suite.Len(scopes[1], 1)
+	suite.Equal(host.GetOffer().GetHostname(),
+		scopes[1][0].GetHost().GetHostname())
+	suite.Len(scopes[1][0].GetTasks(), 1)
+}
+
+// TestSubtractResources tests taking the resources used on a host out of
+// the resources of its offer.
+func (suite *DryRunHandlerTestSuite) TestSubtractResources() {
+	host := testutil.SetupHostOffers()
+	before := scalar.FromMesosResources(host.GetOffer().GetResources())
+
+	after := scalar.FromMesosResources(subtractResources(
+		host.GetOffer().GetResources(),
+		&usage{
+			resources: scalar.Resources{CPU: 2, Mem: 100, GPU: 200},
+			ports:     3,
+		}))
+	suite.Equal(before.GetCPU()-2, after.GetCPU())
+	suite.Equal(before.GetMem()-100, after.GetMem())
+	suite.Equal(before.GetDisk(), after.GetDisk())
+	// the resources cannot go below zero
+	suite.Equal(0.0, after.GetGPU())
+	suite.Equal(before.GetPorts()-3, after.GetPorts())
+}
+
+// TestDryRunPlacementInvalidConfig tests dry run with invalid job configs.
+func (suite *DryRunHandlerTestSuite) TestDryRunPlacementInvalidConfig() {
+	noRespool := suite.jobConfig(1)
+	noRespool.RespoolID = nil
+
+	for _, jobConfig := range []*job.JobConfig{
+		nil,
+		suite.jobConfig(0),
+		suite.jobConfig(_maxInstances + 1),
+		noRespool,
+	} {
+		_, err := suite.handler.DryRunPlacement(
+			context.Background(),
+			&placementsvc.DryRunPlacementRequest{Config: jobConfig})
+		suite.True(yarpcerrors.IsInvalidArgument(err))
+	}
+}
+
+// TestDryRunPlacementAdmissionFailure tests errors from resource manager.
+func (suite *DryRunHandlerTestSuite) TestDryRunPlacementAdmissionFailure() {
+	suite.mockResmgr.EXPECT().
+		CheckAdmission(gomock.Any(), gomock.Any()).
+		Return(&resmgrsvc.CheckAdmissionResponse{
+			Error: &resmgrsvc.CheckAdmissionResponse_Error{
+				NotFound: &resmgrsvc.ResourcePoolNotFound{},
+			},
+		}, nil)
+	_, err := suite.handler.DryRunPlacement(
+		context.Background(),
+		&placementsvc.DryRunPlacementRequest{Config: suite.jobConfig(1)})
+	suite.True(yarpcerrors.IsNotFound(err))
+
+	suite.mockResmgr.EXPECT().
+		CheckAdmission(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("resmgr unavailable"))
+	_, err = suite.handler.DryRunPlacement(
+		context.Background(),
+		&placementsvc.DryRunPlacementRequest{Config: suite.jobConfig(1)})
+	suite.Error(err)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 	"github.com/uber/peloton/.gen/peloton/private/resmgr"
 	"github.com/uber/peloton/pkg/common/async"
+	"github.com/uber/peloton/pkg/common/constraints"
 	"github.com/uber/peloton/pkg/placement/config"
 	"github.com/uber/peloton/pkg/placement/hosts"
 	tally_metrics "github.com/uber/peloton/pkg/placement/metrics"
@@ -44,6 +45,9 @@ const (
 	_noTasksTimeoutPenalty = 1 * time.Second
 	// error message for failed placed task
 	_failedToPlaceTaskAfterTimeout = "failed to place task after timeout"
+	// error message for tasks with topology constraints whose job hosts
+	// could not be fetched
+	_failedToGetJobHosts = "failed to get the hosts of the job"
 )
 
 // Engine represents a placement engine that can be started and stopped.
@@ -61,8 +65,11 @@ func New(
 	hostsService hosts.Service,
 	strategy plugins.Strategy,
 	pool *async.Pool) Engine {
+	// Metrics are tagged with the strategy, so that engines running
+	// different strategies for the same task type can be compared.
 	scope := tally_metrics.NewMetrics(
-		parent.SubScope(strings.ToLower(cfg.TaskType.String())))
+		parent.SubScope(strings.ToLower(cfg.TaskType.String())).
+			Tagged(map[string]string{"strategy": string(cfg.Strategy)}))
 
 	engine := NewEngine(
 		cfg,
@@ -228,12 +235,19 @@ func (e *engine) placeAssignmentGroup(
 			"assignments":     assignments,
 		}).Info("placing assignment group")
 
+		// Get the hosts of the jobs whose tasks have topology constraints
+		// with limits, so that host manager only returns the hosts on
+		// which the limits are kept.
+		placeable, scope := e.getJobHosts(ctx, assignments)
+		acquireFilter := plugins.WithTopologyDomainCounts(
+			filter, placeable, scope)
+
 		// Get hosts with available resources and tasks currently running.
 		hosts, reason := e.offerService.Acquire(
 			ctx,
 			e.config.FetchOfferTasks,
 			e.config.TaskType,
-			filter)
+			acquireFilter)
 
 		existing := e.findUsedHosts(assignments)
 		now := time.Now()
@@ -243,7 +257,7 @@ func (e *engine) placeAssignmentGroup(
 				ctx,
 				e.config.FetchOfferTasks,
 				e.config.TaskType,
-				filter)
+				acquireFilter)
 			now = time.Now()
 		}
 
@@ -263,7 +277,7 @@ func (e *engine) placeAssignmentGroup(
 		e.metrics.OfferGet.Inc(1)
 
 		// PlaceOnce the tasks on the hosts by delegating to the placement strategy.
-		e.strategy.PlaceOnce(assignments, hosts)
+		e.placeOnce(placeable, hosts, scope)
 
 		// Filter the assignments according to if they got assigned,
 		// should be retried or were unassigned.
@@ -284,6 +298,71 @@ func (e *engine) placeAssignmentGroup(
 	}
 }
 
+// getJobHosts returns the assignments which can be placed and the hosts of
+// the jobs whose tasks have topology constraints with limits, for strategies
+// which honor constraints spanning several hosts. The tasks of the jobs
+// whose hosts can't be fetched are not placed.
+func (e *engine) getJobHosts(
+	ctx context.Context,
+	assignments []*models.Assignment,
+) (placeable []*models.Assignment, scope []*models.Host) {
+	if _, ok := e.strategy.(plugins.ScopedStrategy); !ok {
+		return assignments, nil
+	}
+
+	// fetched is whether the hosts of each job could be fetched
+	fetched := make(map[string]bool)
+	scopeHosts := make(map[string]struct{})
+	for _, assignment := range assignments {
+		task := assignment.GetTask().GetTask()
+		if !constraints.HasTopologyLimits(task.GetConstraint()) {
+			placeable = append(placeable, assignment)
+			continue
+		}
+
+		jobID := task.GetJobId().GetValue()
+		succeeded, exists := fetched[jobID]
+		if !exists {
+			result, err := e.hostsService.GetJobHosts(ctx, task)
+			if err != nil {
+				log.WithField("job_id", jobID).
+					WithError(err).
+					Warn(_failedToGetJobHosts)
+			}
+			succeeded = err == nil
+			fetched[jobID] = succeeded
+			for _, host := range result {
+				hostname := host.GetHost().GetHostname()
+				if _, ok := scopeHosts[hostname]; !ok {
+					scopeHosts[hostname] = struct{}{}
+					scope = append(scope, host)
+				}
+			}
+		}
+		if !succeeded {
+			assignment.SetReason(_failedToGetJobHosts)
+			continue
+		}
+		placeable = append(placeable, assignment)
+	}
+	return placeable, scope
+}
+
+// placeOnce places the assignments on the hosts with the placement strategy,
+// strategies which honor constraints spanning several hosts also get the
+// hosts of the jobs.
+func (e *engine) placeOnce(
+	assignments []*models.Assignment,
+	hosts []*models.HostOffers,
+	scope []*models.Host) {
+	strategy, ok := e.strategy.(plugins.ScopedStrategy)
+	if !ok {
+		e.strategy.PlaceOnce(assignments, hosts)
+		return
+	}
+	strategy.PlaceOnceInScope(assignments, hosts, scope)
+}
+
 // returns the starved assignments back to the task service
 func (e *engine) returnStarvedAssignments(
 	ctx context.Context,
@@ -16,14 +16,18 @@ package placement
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 	"github.com/uber/peloton/.gen/peloton/private/resmgr"
 
 	"github.com/uber/peloton/pkg/common/async"
 	"github.com/uber/peloton/pkg/placement/config"
+	hosts_mock "github.com/uber/peloton/pkg/placement/hosts/mocks"
 	"github.com/uber/peloton/pkg/placement/models"
 	offers_mock "github.com/uber/peloton/pkg/placement/offers/mocks"
 	"github.com/uber/peloton/pkg/placement/plugins/batch"
@@ -90,6 +94,55 @@ func setupEngine(t *testing.T) (
 	return ctrl, e.(*engine), mockOfferService, mockTaskService, mockStrategy
 }
 
+// TestEngineGetJobHosts tests that a scoped strategy gets the hosts of the
+// jobs whose tasks have topology constraints with limits, and that those
+// tasks are not placed if the hosts of their job can't be fetched.
+func TestEngineGetJobHosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHostsService := hosts_mock.NewMockService(ctrl)
+	mockStrategy := mocks.NewMockScopedStrategy(ctrl)
+	e := &engine{
+		hostsService: mockHostsService,
+		strategy:     mockStrategy,
+	}
+
+	topology := testutil.SetupAssignment(time.Now().Add(10*time.Second), 1)
+	topology.GetTask().GetTask().JobId = &peloton.JobID{Value: "job"}
+	topology.GetTask().GetTask().Constraint = &task.Constraint{
+		Type: task.Constraint_TOPOLOGY_CONSTRAINT,
+		TopologyConstraint: &task.TopologyConstraint{
+			TopologyKey:  "rack",
+			MaxPerDomain: 1,
+		},
+	}
+	other := testutil.SetupAssignment(time.Now().Add(10*time.Second), 1)
+	assignments := []*models.Assignment{topology, other}
+	offers := []*models.HostOffers{testutil.SetupHostOffers()}
+	jobHosts := []*models.Host{
+		models.NewHosts(&hostsvc.HostInfo{Hostname: "job-host"}, nil),
+	}
+
+	mockHostsService.EXPECT().
+		GetJobHosts(gomock.Any(), topology.GetTask().GetTask()).
+		Return(jobHosts, nil)
+	placeable, scope := e.getJobHosts(context.Background(), assignments)
+	assert.Equal(t, assignments, placeable)
+	assert.Equal(t, jobHosts, scope)
+
+	mockStrategy.EXPECT().PlaceOnceInScope(placeable, offers, scope)
+	e.placeOnce(placeable, offers, scope)
+
+	mockHostsService.EXPECT().
+		GetJobHosts(gomock.Any(), topology.GetTask().GetTask()).
+		Return(nil, errors.New("error"))
+	placeable, scope = e.getJobHosts(context.Background(), assignments)
+	assert.Equal(t, []*models.Assignment{other}, placeable)
+	assert.Nil(t, scope)
+	assert.Equal(t, _failedToGetJobHosts, topology.GetReason())
+}
+
 func TestEnginePlaceNoTasksToPlace(t *testing.T) {
 	ctrl, engine, _, mockTaskService, _ := setupEngine(t)
 	defer ctrl.Finish()
@@ -19,9 +19,12 @@ import (
 	"errors"
 	"time"
 
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	pb_task "github.com/uber/peloton/.gen/peloton/api/v0/task"
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 	"github.com/uber/peloton/.gen/peloton/private/resmgr"
 	"github.com/uber/peloton/.gen/peloton/private/resmgrsvc"
+	"github.com/uber/peloton/pkg/common/constraints"
 	"github.com/uber/peloton/pkg/placement/metrics"
 	"github.com/uber/peloton/pkg/placement/models"
 
@@ -45,6 +48,10 @@ type Service interface {
 	// GetHosts fetches a batch of hosts from the host manager matching filter.
 	GetHosts(ctx context.Context, task *resmgr.Task, filter *hostsvc.HostFilter) (hosts []*models.Host, err error)
 
+	// GetJobHosts fetches the hosts where the active tasks of the job of the
+	// given task are placed or running, with the tasks on them.
+	GetJobHosts(ctx context.Context, task *resmgr.Task) (hosts []*models.Host, err error)
+
 	// ReserveHost Makes reservation for the host in hostmanager.
 	ReserveHost(ctx context.Context, host []*models.Host, task *resmgr.Task) (err error)
 
@@ -116,6 +123,103 @@ func (s *service) GetHosts(
 	return s.fillTasksInHost(res.GetHosts(), hostTasksMap), nil
 }
 
+// _jobHostStates are the states of the tasks which have a host.
+var _jobHostStates = []string{
+	pb_task.TaskState_PLACED.String(),
+	pb_task.TaskState_LAUNCHING.String(),
+	pb_task.TaskState_LAUNCHED.String(),
+	pb_task.TaskState_RUNNING.String(),
+}
+
+// GetJobHosts fetches the hosts where the active tasks of the job of the
+// given task are placed or running. The hosts are filtered by the host
+// constraints of the task, without the limits of its topology constraints.
+func (s *service) GetJobHosts(
+	ctx context.Context,
+	task *resmgr.Task) ([]*models.Host, error) {
+	hostnames, err := s.getJobHostnames(ctx, task)
+	if err != nil {
+		s.metrics.HostGetFail.Inc(1)
+		return nil, err
+	}
+	if len(hostnames) == 0 {
+		return nil, nil
+	}
+
+	hostConstraints := make([]*pb_task.Constraint, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		hostConstraints = append(hostConstraints, &pb_task.Constraint{
+			Type: pb_task.Constraint_LABEL_CONSTRAINT,
+			LabelConstraint: &pb_task.LabelConstraint{
+				Kind:      pb_task.LabelConstraint_HOST,
+				Condition: pb_task.LabelConstraint_CONDITION_EQUAL,
+				Label: &peloton.Label{
+					Key:   constraints.HostNameKey,
+					Value: hostname,
+				},
+				Requirement: 1,
+			},
+		})
+	}
+	constraint := &pb_task.Constraint{
+		Type: pb_task.Constraint_OR_CONSTRAINT,
+		OrConstraint: &pb_task.OrConstraint{
+			Constraints: hostConstraints,
+		},
+	}
+	if task.GetConstraint() != nil {
+		constraint = &pb_task.Constraint{
+			Type: pb_task.Constraint_AND_CONSTRAINT,
+			AndConstraint: &pb_task.AndConstraint{
+				Constraints: []*pb_task.Constraint{
+					constraint,
+					constraints.WithoutTopologyLimits(task.GetConstraint()),
+				},
+			},
+		}
+	}
+
+	return s.GetHosts(ctx, task, &hostsvc.HostFilter{
+		SchedulingConstraint: constraint,
+	})
+}
+
+// getJobHostnames returns the hosts of the active tasks of the job of the
+// given task from resource manager.
+func (s *service) getJobHostnames(
+	ctx context.Context,
+	task *resmgr.Task) ([]string, error) {
+	ctx, cancelFunc := context.WithTimeout(ctx, _timeout)
+	defer cancelFunc()
+
+	resp, err := s.resourceManager.GetActiveTasks(
+		ctx,
+		&resmgrsvc.GetActiveTasksRequest{
+			JobID:  task.GetJobId().GetValue(),
+			States: _jobHostStates,
+		})
+	if err != nil {
+		return nil, err
+	}
+	if respErr := resp.GetError(); respErr != nil {
+		return nil, errors.New(respErr.GetMessage())
+	}
+
+	var hostnames []string
+	seen := make(map[string]struct{})
+	for _, entries := range resp.GetTasksByState() {
+		for _, entry := range entries.GetTaskEntry() {
+			hostname := entry.GetHostname()
+			if _, ok := seen[hostname]; ok || hostname == "" {
+				continue
+			}
+			seen[hostname] = struct{}{}
+			hostnames = append(hostnames, hostname)
+		}
+	}
+	return hostnames, nil
+}
+
 // fillTasksInHost creates host Info into placement hosts.
 // One key notion is to add already running tasks on this host
 // such that placement can take care of task-task affinity.
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 	host_mocks "github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc/mocks"
 	"github.com/uber/peloton/.gen/peloton/private/resmgr"
@@ -122,6 +123,97 @@ func (suite *ServiceTestSuite) TestHostsService_AcquireHosts() {
 	suite.Equal(1, len(hostsRet[0].Tasks))
 }
 
+// TestHostsService_GetJobHosts tests that the hosts of the active tasks of
+// the job are fetched with the tasks running on them
+func (suite *ServiceTestSuite) TestHostsService_GetJobHosts() {
+	defer suite.mockCtrl.Finish()
+
+	ctx := context.Background()
+	task := createResMgrTask()
+	task.JobId = &peloton.JobID{Value: "job"}
+
+	gomock.InOrder(
+		suite.resmgrClient.EXPECT().
+			GetActiveTasks(
+				gomock.Any(),
+				&resmgrsvc.GetActiveTasksRequest{
+					JobID:  "job",
+					States: _jobHostStates,
+				},
+			).Return(&resmgrsvc.GetActiveTasksResponse{
+			TasksByState: map[string]*resmgrsvc.GetActiveTasksResponse_TaskEntries{
+				"RUNNING": {
+					TaskEntry: []*resmgrsvc.GetActiveTasksResponse_TaskEntry{
+						{Hostname: _hostname},
+						{Hostname: _hostname},
+					},
+				},
+			},
+		}, nil),
+		suite.hostMgrClient.EXPECT().
+			GetHosts(gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, req *hostsvc.GetHostsRequest) {
+				hostConstraints := req.GetFilter().GetSchedulingConstraint().
+					GetOrConstraint().GetConstraints()
+				suite.Equal(1, len(hostConstraints))
+				suite.Equal(_hostname,
+					hostConstraints[0].GetLabelConstraint().GetLabel().GetValue())
+			}).
+			Return(&hostsvc.GetHostsResponse{
+				Hosts: []*hostsvc.HostInfo{
+					{
+						Hostname: _hostname,
+					},
+				},
+			}, nil),
+		suite.resmgrClient.EXPECT().
+			GetTasksByHosts(gomock.Any(),
+				&resmgrsvc.GetTasksByHostsRequest{
+					Type:      resmgr.TaskType_UNKNOWN,
+					Hostnames: []string{_hostname},
+				},
+			).Return(
+			&resmgrsvc.GetTasksByHostsResponse{
+				HostTasksMap: map[string]*resmgrsvc.TaskList{
+					_hostname: {
+						Tasks: []*resmgr.Task{
+							task,
+						},
+					},
+				},
+			}, nil),
+	)
+
+	hosts, err := suite.hostService.GetJobHosts(ctx, task)
+	suite.NoError(err)
+	suite.Equal(1, len(hosts))
+	suite.Equal(_hostname, hosts[0].GetHost().Hostname)
+	suite.Equal(1, len(hosts[0].GetTasks()))
+}
+
+// TestHostsService_GetJobHostsNoActiveTasks tests that no hosts are
+// fetched for a job without placed tasks
+func (suite *ServiceTestSuite) TestHostsService_GetJobHostsNoActiveTasks() {
+	defer suite.mockCtrl.Finish()
+
+	suite.resmgrClient.EXPECT().
+		GetActiveTasks(gomock.Any(), gomock.Any()).
+		Return(&resmgrsvc.GetActiveTasksResponse{}, nil)
+
+	hosts, err := suite.hostService.GetJobHosts(
+		context.Background(), createResMgrTask())
+	suite.NoError(err)
+	suite.Empty(hosts)
+
+	suite.resmgrClient.EXPECT().
+		GetActiveTasks(gomock.Any(), gomock.Any()).
+		Return(nil, errReturn)
+
+	_, err = suite.hostService.GetJobHosts(
+		context.Background(), createResMgrTask())
+	suite.Error(err)
+}
+
 // TestHostsService_ReserveHosts tests the ReserveHosts call
 func (suite *ServiceTestSuite) TestHostsService_ReserveHosts() {
 	defer suite.mockCtrl.Finish()
@@ -41,6 +41,11 @@ type Service interface {
 	// Acquire fetches a batch of offers from the host manager.
 	Acquire(ctx context.Context, fetchTasks bool, taskType resmgr.TaskType, filter *hostsvc.HostFilter) (offers []*models.HostOffers, reason string)
 
+	// Peek fetches a batch of offers from the host manager like Acquire,
+	// but without claiming them. The offers must not be used for launching
+	// tasks and need not be released.
+	Peek(ctx context.Context, fetchTasks bool, taskType resmgr.TaskType, filter *hostsvc.HostFilter) (offers []*models.HostOffers, reason string)
+
 	// Release returns the acquired offers back to host manager.
 	Release(ctx context.Context, offers []*models.HostOffers)
 }
@@ -69,8 +74,26 @@ func (s *service) Acquire(
 	fetchTasks bool,
 	taskType resmgr.TaskType,
 	filter *hostsvc.HostFilter) (offers []*models.HostOffers, reason string) {
+	return s.acquire(ctx, fetchTasks, taskType, filter, false)
+}
+
+// Peek fetches a batch of offers from the host manager without claiming them.
+func (s *service) Peek(
+	ctx context.Context,
+	fetchTasks bool,
+	taskType resmgr.TaskType,
+	filter *hostsvc.HostFilter) (offers []*models.HostOffers, reason string) {
+	return s.acquire(ctx, fetchTasks, taskType, filter, true)
+}
+
+func (s *service) acquire(
+	ctx context.Context,
+	fetchTasks bool,
+	taskType resmgr.TaskType,
+	filter *hostsvc.HostFilter,
+	dryRun bool) (offers []*models.HostOffers, reason string) {
 	// Get list of host -> resources (aggregate of outstanding offers)
-	hostOffers, filterResults, err := s.fetchOffers(ctx, filter)
+	hostOffers, filterResults, err := s.fetchOffers(ctx, filter, dryRun)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"host_offers":    hostOffers,
@@ -187,12 +210,14 @@ func (s *service) Release(
 // fetchOffers returns the offers by each host and count of all offers from host manager.
 func (s *service) fetchOffers(
 	ctx context.Context,
-	filter *hostsvc.HostFilter) ([]*hostsvc.HostOffer, map[string]uint32, error) {
+	filter *hostsvc.HostFilter,
+	dryRun bool) ([]*hostsvc.HostOffer, map[string]uint32, error) {
 	ctx, cancelFunc := context.WithTimeout(ctx, _timeout)
 	defer cancelFunc()
 
 	offersRequest := &hostsvc.AcquireHostOffersRequest{
 		Filter: filter,
+		DryRun: dryRun,
 	}
 	offersResponse, err := s.hostManager.AcquireHostOffers(ctx, offersRequest)
 	if err != nil {
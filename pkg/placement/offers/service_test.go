@@ -149,6 +149,36 @@ func TestOfferService_Dequeue(t *testing.T) {
 	assert.Equal(t, 1, len(hosts[0].GetTasks()))
 }
 
+func TestOfferService_Peek(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockResourceManager := resource_mocks.NewMockResourceManagerServiceYARPCClient(ctrl)
+	mockHostManager := host_mocks.NewMockInternalHostServiceYARPCClient(ctrl)
+	metrics := metrics.NewMetrics(tally.NoopScope)
+	service := NewService(mockHostManager, mockResourceManager, metrics)
+
+	ctx := context.Background()
+	filter := &hostsvc.HostFilter{}
+
+	mockHostManager.EXPECT().
+		AcquireHostOffers(
+			gomock.Any(),
+			&hostsvc.AcquireHostOffersRequest{
+				Filter: filter,
+				DryRun: true,
+			}).
+		Return(&hostsvc.AcquireHostOffersResponse{
+			HostOffers: []*hostsvc.HostOffer{
+				{
+					Hostname: "hostname",
+				},
+			},
+		}, nil)
+	hosts, _ := service.Peek(ctx, false, resmgr.TaskType_UNKNOWN, filter)
+	assert.Equal(t, 1, len(hosts))
+	assert.Equal(t, "hostname", hosts[0].GetOffer().Hostname)
+}
+
 func TestOfferService_Return(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
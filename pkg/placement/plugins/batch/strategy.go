@@ -35,6 +35,9 @@ type batch struct{}
 
 // PlaceOnce is an implementation of the placement.Strategy interface.
 func (batch *batch) PlaceOnce(unassigned []*models.Assignment, hosts []*models.HostOffers) {
+	// the batch strategy only sees the offered hosts and can't count the
+	// tasks per topology domain
+	unassigned = plugins.RejectTopologyLimits(unassigned)
 	for _, host := range hosts {
 		log.WithFields(log.Fields{
 			"unassigned": unassigned,
@@ -19,7 +19,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
 	"github.com/uber/peloton/pkg/placement/models"
+	"github.com/uber/peloton/pkg/placement/plugins"
 	"github.com/uber/peloton/pkg/placement/testutil"
 )
 
@@ -59,6 +61,37 @@ func TestBatchPlaceOneFreeHost(t *testing.T) {
 	assert.Equal(t, offers[0], assignments[1].GetHost())
 }
 
+func TestBatchPlaceRejectsTopologyLimits(t *testing.T) {
+	assignments := []*models.Assignment{
+		testutil.SetupAssignment(time.Now().Add(10*time.Second), 1),
+		testutil.SetupAssignment(time.Now().Add(10*time.Second), 1),
+	}
+	assignments[0].GetTask().GetTask().Constraint = &task.Constraint{
+		Type: task.Constraint_TOPOLOGY_CONSTRAINT,
+		TopologyConstraint: &task.TopologyConstraint{
+			TopologyKey:  "rack",
+			MaxPerDomain: 1,
+		},
+	}
+	offers := []*models.HostOffers{
+		testutil.SetupHostOffers(),
+	}
+	strategy := New()
+
+	filters := strategy.Filters(assignments[:1])
+	assert.Equal(t, 1, len(filters))
+	for filter := range filters {
+		assert.Equal(t, uint32(0), filter.GetSchedulingConstraint().
+			GetTopologyConstraint().GetMaxPerDomain())
+	}
+
+	strategy.PlaceOnce(assignments, offers)
+
+	assert.Nil(t, assignments[0].GetHost())
+	assert.Equal(t, plugins.TopologyLimitsReason, assignments[0].GetReason())
+	assert.Equal(t, offers[0], assignments[1].GetHost())
+}
+
 func TestBatchFiltersWithResources(t *testing.T) {
 	assignments := []*models.Assignment{
 		testutil.SetupAssignment(time.Now().Add(10*time.Second), 1),
@@ -0,0 +1,72 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mimir
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/pkg/common/constraints"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/labels"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/placement"
+)
+
+// AttributeRequirement represents a requirement on the value of a host
+// attribute, i.e. we want to be placed on a host with a kernel version
+// greater than or equal to 4.14, or on a rack numbered within [10, 20].
+type AttributeRequirement struct {
+	Constraint *task.AttributeConstraint
+}
+
+// NewAttributeRequirement creates a new attribute requirement.
+func NewAttributeRequirement(
+	constraint *task.AttributeConstraint) *AttributeRequirement {
+	return &AttributeRequirement{
+		Constraint: constraint,
+	}
+}
+
+// Passed checks if the value of the attribute on the given group
+// fulfills the requirement.
+func (requirement *AttributeRequirement) Passed(group *placement.Group, scopeSet *placement.ScopeSet,
+	entity *placement.Entity, transcript *placement.Transcript) bool {
+	names := append([]string{AttributeValue}, strings.Split(requirement.Constraint.GetName(), ".")...)
+	names = append(names, "*")
+	var values []string
+	for _, label := range group.Labels.Find(labels.NewLabel(names...)) {
+		labelNames := label.Names()
+		values = append(values, labelNames[len(labelNames)-1])
+	}
+
+	fulfilled, err := constraints.MatchAttributeValues(
+		requirement.Constraint, values)
+	if err != nil || !fulfilled {
+		transcript.IncFailed()
+		return false
+	}
+	transcript.IncPassed()
+	return true
+}
+
+func (requirement *AttributeRequirement) String() string {
+	return fmt.Sprintf("requires that the value of the attribute %v is %v",
+		requirement.Constraint.GetName(), requirement.Constraint.GetCondition())
+}
+
+// Composite returns false as the requirement is not composite and the name of the requirement type.
+func (requirement *AttributeRequirement) Composite() (bool, string) {
+	return false, "attribute"
+}
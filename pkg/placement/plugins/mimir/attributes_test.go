@@ -0,0 +1,134 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mimir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/labels"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/placement"
+)
+
+func TestAttributeRequirement_Passed(t *testing.T) {
+	group := placement.NewGroup("hostname")
+	group.Labels.Add(labels.NewLabel(AttributeValue, "kernel", "version", "4.9"))
+	group.Labels.Add(labels.NewLabel(AttributeValue, "rack", "[10-12]"))
+	group.Labels.Add(labels.NewLabel(AttributeValue, "rack", "[20-22]"))
+	group.Labels.Add(labels.NewLabel(AttributeValue, "zone", "dca1"))
+	group.Labels.Add(labels.NewLabel(AttributeValue, "zone", "sjc1"))
+	// labels which are not attribute values are ignored
+	group.Labels.Add(labels.NewLabel("kernel", "version", "5.0"))
+	scopeSet := placement.NewScopeSet(nil)
+
+	testCases := []struct {
+		constraint *task.AttributeConstraint
+		passed     bool
+	}{
+		{
+			constraint: &task.AttributeConstraint{
+				Name:      "kernel.version",
+				Condition: task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL,
+				Value:     "4.9",
+				Version:   true,
+			},
+			passed: true,
+		},
+		{
+			constraint: &task.AttributeConstraint{
+				Name:      "kernel.version",
+				Condition: task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL,
+				Value:     "4.14",
+				Version:   true,
+			},
+			passed: false,
+		},
+		{
+			constraint: &task.AttributeConstraint{
+				Name:      "kernel.version",
+				Condition: task.AttributeConstraint_CONDITION_LESS_THAN_OR_EQUAL,
+				Value:     "4.0",
+				Version:   true,
+			},
+			passed: false,
+		},
+		{
+			constraint: &task.AttributeConstraint{
+				Name:       "rack",
+				Condition:  task.AttributeConstraint_CONDITION_IN_RANGE,
+				RangeBegin: "13",
+				RangeEnd:   "20",
+			},
+			passed: true,
+		},
+		{
+			constraint: &task.AttributeConstraint{
+				Name:       "rack",
+				Condition:  task.AttributeConstraint_CONDITION_IN_RANGE,
+				RangeBegin: "13",
+				RangeEnd:   "19",
+			},
+			passed: false,
+		},
+		{
+			constraint: &task.AttributeConstraint{
+				Name:      "zone",
+				Condition: task.AttributeConstraint_CONDITION_IN_SET,
+				Values:    []string{"dca1", "sjc1"},
+			},
+			passed: true,
+		},
+		{
+			constraint: &task.AttributeConstraint{
+				Name:      "zone",
+				Condition: task.AttributeConstraint_CONDITION_IN_SET,
+				Values:    []string{"sjc1"},
+			},
+			passed: true,
+		},
+		{
+			constraint: &task.AttributeConstraint{
+				Name:      "missing",
+				Condition: task.AttributeConstraint_CONDITION_IN_SET,
+				Values:    []string{"dca1"},
+			},
+			passed: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		transcript := placement.NewTranscript("transcript")
+		requirement := NewAttributeRequirement(tc.constraint)
+		assert.Equal(t, tc.passed,
+			requirement.Passed(group, scopeSet, nil, transcript),
+			requirement.String())
+	}
+}
+
+func TestAttributeRequirement_Entity(t *testing.T) {
+	constraint := &task.Constraint{
+		Type: task.Constraint_ATTRIBUTE_CONSTRAINT,
+		AttributeConstraint: &task.AttributeConstraint{
+			Name:      "kernel",
+			Condition: task.AttributeConstraint_CONDITION_GREATER_THAN_OR_EQUAL,
+			Value:     "4.14",
+		},
+	}
+	requirement, ok := makeAffinityRequirements(constraint).(*AttributeRequirement)
+	assert.True(t, ok)
+	assert.Equal(t, constraint.GetAttributeConstraint(), requirement.Constraint)
+}
@@ -29,8 +29,10 @@ import (
 	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/requirements"
 )
 
-// TaskToEntity will convert a task to an entity.
-func TaskToEntity(task *resmgr.Task, isLaunched bool) *placement.Entity {
+// TaskToEntity will convert a task to an entity. The groups are ordered by
+// the desired host of the task, then by its placement preferences and last
+// by the given host ordering.
+func TaskToEntity(task *resmgr.Task, isLaunched bool, hostOrdering []placement.Ordering) *placement.Entity {
 	entity := placement.NewEntity(task.GetId().GetValue())
 	if !isLaunched {
 		addMetrics(task, entity.Metrics)
@@ -43,12 +45,11 @@ func TaskToEntity(task *resmgr.Task, isLaunched bool) *placement.Entity {
 		order = append(order, orderings.Negate(orderings.Label(nil, labels.NewLabel(HostName, task.DesiredHost))))
 	}
 
-	order = append(order,
-		orderings.Negate(orderings.Metric(orderings.GroupSource, DiskFree)),
-		orderings.Negate(orderings.Metric(orderings.GroupSource, MemoryFree)),
-		orderings.Negate(orderings.Metric(orderings.GroupSource, CPUFree)),
-		orderings.Negate(orderings.Metric(orderings.GroupSource, GPUFree)),
-	)
+	if preference := makePreferenceOrdering(task.GetConstraint()); preference != nil {
+		order = append(order, preference)
+	}
+
+	order = append(order, hostOrdering...)
 
 	entity.Ordering = orderings.Concatenate(order...)
 
@@ -98,6 +99,13 @@ func makeAffinityRequirements(constraint *task.Constraint) placement.Requirement
 				Warn("unknown relation constraint kind")
 			return requirements.NewAndRequirement()
 		}
+	case task.Constraint_ATTRIBUTE_CONSTRAINT:
+		return NewAttributeRequirement(constraint.GetAttributeConstraint())
+	case task.Constraint_TOPOLOGY_CONSTRAINT:
+		return makeTopologyRequirement(constraint.GetTopologyConstraint())
+	case task.Constraint_PREFERENCE_CONSTRAINT:
+		// preferences only order the groups, see makePreferenceOrdering
+		return requirements.NewAndRequirement()
 	case task.Constraint_AND_CONSTRAINT:
 		var subRequirements []placement.Requirement
 		for _, subConstraint := range constraint.GetAndConstraint().GetConstraints() {
@@ -133,9 +141,17 @@ func makeMetricRequirements(task *resmgr.Task) []placement.Requirement {
 		GPUFree, requirements.GreaterThanEqual, resource.GetGpuLimit()*100.0)
 	portRequirement := requirements.NewMetricRequirement(
 		PortsFree, requirements.GreaterThanEqual, float64(task.GetNumPorts()))
-	return []placement.Requirement{
+	networkRequirement := requirements.NewMetricRequirement(
+		NetworkFree, requirements.GreaterThanEqual, resource.GetNetworkMbps())
+	result := []placement.Requirement{
 		cpuRequirement, memoryRequirement, diskRequirement, gpuRequirement, portRequirement,
+		networkRequirement,
+	}
+	if resource.GetGpu() != nil && resource.GetGpuLimit() > 0 {
+		result = append(result,
+			NewGPURequirement(resource.GetGpu(), resource.GetGpuLimit()))
 	}
+	return result
 }
 
 func addRelations(labels *mesos_v1.Labels, relations *labels.Bag) {
@@ -152,4 +168,5 @@ func addMetrics(task *resmgr.Task, metricSet *metrics.Set) {
 	metricSet.Set(MemoryReserved, resource.GetMemLimitMb()*metrics.MiB)
 	metricSet.Set(DiskReserved, resource.GetDiskLimitMb()*metrics.MiB)
 	metricSet.Set(PortsReserved, float64(task.GetNumPorts()))
+	metricSet.Set(NetworkReserved, resource.GetNetworkMbps())
 }
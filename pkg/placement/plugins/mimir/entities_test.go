@@ -28,7 +28,7 @@ import (
 
 func TestEntityMapper_Convert(t *testing.T) {
 	task := testutil.SetupAssignment(time.Now(), 1).GetTask().GetTask()
-	entity := TaskToEntity(task, false)
+	entity := TaskToEntity(task, false, makeHostOrdering(nil))
 	assert.Equal(t, task.GetId().GetValue(), entity.Name)
 	assert.Equal(t, 1, entity.Relations.Count(labels.NewLabel("relationKey", "relationValue")))
 	assert.NotNil(t, entity.Ordering)
@@ -38,11 +38,12 @@ func TestEntityMapper_Convert(t *testing.T) {
 	assert.Equal(t, 4096.0*metrics.MiB, entity.Metrics.Get(MemoryReserved))
 	assert.Equal(t, 1024.0*metrics.MiB, entity.Metrics.Get(DiskReserved))
 	assert.Equal(t, 3.0, entity.Metrics.Get(PortsReserved))
+	assert.Equal(t, 100.0, entity.Metrics.Get(NetworkReserved))
 
 	and1, ok := entity.Requirement.(*requirements.AndRequirement)
 	assert.True(t, ok)
 	assert.NotNil(t, and1)
-	assert.Equal(t, 6, len(and1.Requirements))
+	assert.Equal(t, 7, len(and1.Requirements))
 
 	or, ok := and1.Requirements[0].(*requirements.OrRequirement)
 	assert.True(t, ok)
@@ -85,6 +86,8 @@ func TestEntityMapper_Convert(t *testing.T) {
 			assert.Equal(t, 1024.0*metrics.MiB, requirement.Value)
 		case PortsFree:
 			assert.Equal(t, 3.0, requirement.Value)
+		case NetworkFree:
+			assert.Equal(t, 100.0, requirement.Value)
 		}
 	}
 }
@@ -0,0 +1,75 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mimir
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/pkg/common/gpu"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/placement"
+)
+
+// GPURequirement represents a requirement on the GPU device topology of a
+// group, e.g. that the GPUs of the entity must be of a specific model or be
+// attached to the same NUMA node.
+type GPURequirement struct {
+	Config *task.GPUConfig
+	GPUs   float64
+}
+
+// NewGPURequirement creates a new GPU requirement for the given number of
+// GPUs.
+func NewGPURequirement(config *task.GPUConfig, gpus float64) *GPURequirement {
+	return &GPURequirement{
+		Config: config,
+		GPUs:   gpus,
+	}
+}
+
+// Passed checks if the GPU topology of the group, as derived from the group
+// labels, matches the GPU config given the GPUs that are still free on the
+// group.
+func (requirement *GPURequirement) Passed(group *placement.Group, scopeSet *placement.ScopeSet,
+	entity *placement.Entity, transcript *placement.Transcript) bool {
+	topology := gpu.NewTopology(group.Metrics.Get(GPUAvailable) / 100.0)
+	for _, label := range group.Labels.Labels() {
+		names := label.Names()
+		if len(names) < 2 {
+			continue
+		}
+		topology.SetAttribute(
+			strings.Join(names[:len(names)-1], "."), names[len(names)-1])
+	}
+	if !topology.Match(
+		requirement.Config,
+		requirement.GPUs,
+		group.Metrics.Get(GPUFree)/100.0) {
+		transcript.IncFailed()
+		return false
+	}
+	transcript.IncPassed()
+	return true
+}
+
+func (requirement *GPURequirement) String() string {
+	return fmt.Sprintf("requires %v gpus matching %v", requirement.GPUs, requirement.Config)
+}
+
+// Composite returns false as the requirement is not composite and the name of the requirement type.
+func (requirement *GPURequirement) Composite() (bool, string) {
+	return false, "gpu"
+}
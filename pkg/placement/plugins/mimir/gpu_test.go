@@ -0,0 +1,103 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mimir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/labels"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/placement"
+)
+
+func setupGPUGroup(model string, gpus, free float64) *placement.Group {
+	group := placement.NewGroup("host")
+	group.Labels.Add(labels.NewLabel("gpu", "model", model))
+	group.Labels.Add(labels.NewLabel("gpu", "memory_mb", "16000"))
+	group.Labels.Add(labels.NewLabel("gpu", "numa", "0", "4"))
+	group.Labels.Add(labels.NewLabel("gpu", "numa", "1", "4"))
+	group.Metrics.Set(GPUAvailable, gpus*100.0)
+	group.Metrics.Set(GPUFree, free*100.0)
+	return group
+}
+
+func TestGPURequirement(t *testing.T) {
+	testCases := []struct {
+		name   string
+		group  *placement.Group
+		config *task.GPUConfig
+		gpus   float64
+		passed bool
+	}{
+		{
+			name:   "model matches",
+			group:  setupGPUGroup("tesla-v100", 8, 8),
+			config: &task.GPUConfig{Models: []string{"tesla-v100"}},
+			gpus:   1,
+			passed: true,
+		},
+		{
+			name:   "model does not match",
+			group:  setupGPUGroup("p100", 8, 8),
+			config: &task.GPUConfig{Models: []string{"tesla-v100"}},
+			gpus:   1,
+			passed: false,
+		},
+		{
+			name:   "memory too small",
+			group:  setupGPUGroup("tesla-v100", 8, 8),
+			config: &task.GPUConfig{MinMemoryMb: 32000},
+			gpus:   1,
+			passed: false,
+		},
+		{
+			name:   "whole host",
+			group:  setupGPUGroup("tesla-v100", 8, 8),
+			config: &task.GPUConfig{WholeHost: true},
+			gpus:   8,
+			passed: true,
+		},
+		{
+			name:   "whole host already used",
+			group:  setupGPUGroup("tesla-v100", 8, 4),
+			config: &task.GPUConfig{WholeHost: true},
+			gpus:   8,
+			passed: false,
+		},
+		{
+			name:   "same numa node",
+			group:  setupGPUGroup("tesla-v100", 8, 8),
+			config: &task.GPUConfig{SameNumaNode: true},
+			gpus:   4,
+			passed: true,
+		},
+		{
+			name:   "same numa node partially used",
+			group:  setupGPUGroup("tesla-v100", 8, 5),
+			config: &task.GPUConfig{SameNumaNode: true},
+			gpus:   4,
+			passed: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		requirement := NewGPURequirement(tc.config, tc.gpus)
+		transcript := placement.NewTranscript("transcript")
+		assert.Equal(t, tc.passed,
+			requirement.Passed(tc.group, nil, nil, transcript), tc.name)
+	}
+}
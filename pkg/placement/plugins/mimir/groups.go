@@ -20,6 +20,7 @@ import (
 
 	"github.com/uber/peloton/.gen/mesos/v1"
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
+	"github.com/uber/peloton/pkg/common/constraints"
 	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/labels"
 	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/metrics"
 	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/placement"
@@ -33,6 +34,18 @@ func OfferToGroup(hostOffer *hostsvc.HostOffer) *placement.Group {
 	return group
 }
 
+// HostToGroup will convert a host to a group with the labels of the host,
+// for hosts which are only used to evaluate requirements spanning several
+// groups.
+func HostToGroup(hostInfo *hostsvc.HostInfo) *placement.Group {
+	group := placement.NewGroup(hostInfo.GetHostname())
+	group.Labels = makeLabels(&hostsvc.HostOffer{
+		Hostname:   hostInfo.GetHostname(),
+		Attributes: hostInfo.GetAttributes(),
+	})
+	return group
+}
+
 func makeMetrics(resources []*mesos_v1.Resource) *metrics.Set {
 	result := metrics.NewSet()
 	for _, resource := range resources {
@@ -57,6 +70,9 @@ func makeMetrics(resources []*mesos_v1.Resource) *metrics.Set {
 			}
 			result.Add(PortsAvailable, float64(ports))
 			result.Set(PortsFree, 0.0)
+		case "network_bandwidth":
+			result.Add(NetworkAvailable, value)
+			result.Set(NetworkFree, 0.0)
 		}
 	}
 	// Compute the derived metrics, e.g. the free metrics from the available and reserved metrics.
@@ -69,10 +85,19 @@ func makeMetrics(resources []*mesos_v1.Resource) *metrics.Set {
 // A text attribute with name n and value t will be turned into the label ["n", "t"].
 // A ranges attribute with name n and ranges [r_1a:r_1b], ..., [r_na:r_nb] will be turned into
 // the label ["n", "[r_1a-r1b];...[r_na-r_nb]"].
+// Each value of an attribute with name n is also turned into the label
+// [AttributeValue, "n", "v"] to evaluate attribute requirements, where the value of
+// a ranges attribute is each of its ranges and the value of a set attribute is each of its items.
 func makeLabels(hostOffer *hostsvc.HostOffer) *labels.Bag {
 	attributes := hostOffer.GetAttributes()
 	result := labels.NewBag()
 	for _, attribute := range attributes {
+		for _, value := range makeAttributeValues(attribute) {
+			names := append([]string{AttributeValue}, strings.Split(attribute.GetName(), ".")...)
+			names = append(names, value)
+			result.Add(labels.NewLabel(names...))
+		}
+
 		var value string
 		switch attribute.GetType() {
 		case mesos_v1.Value_SCALAR:
@@ -96,3 +121,22 @@ func makeLabels(hostOffer *hostsvc.HostOffer) *labels.Bag {
 	result.Add(labels.NewLabel(HostName, hostOffer.GetHostname()))
 	return result
 }
+
+// makeAttributeValues returns the individual values of a Mesos attribute.
+func makeAttributeValues(attribute *mesos_v1.Attribute) []string {
+	switch attribute.GetType() {
+	case mesos_v1.Value_SCALAR:
+		return []string{fmt.Sprintf("%v", attribute.GetScalar().GetValue())}
+	case mesos_v1.Value_TEXT:
+		return []string{attribute.GetText().GetValue()}
+	case mesos_v1.Value_RANGES:
+		var values []string
+		for _, valueRange := range attribute.GetRanges().GetRange() {
+			values = append(values, constraints.FormatRange(valueRange.GetBegin(), valueRange.GetEnd()))
+		}
+		return values
+	case mesos_v1.Value_SET:
+		return attribute.GetSet().GetItem()
+	}
+	return nil
+}
@@ -17,8 +17,11 @@ package mimir
 import (
 	"testing"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 
+	mesos_v1 "github.com/uber/peloton/.gen/mesos/v1"
+	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/labels"
 	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/metrics"
 	"github.com/uber/peloton/pkg/placement/testutil"
@@ -33,7 +36,59 @@ func TestGroupMapper_Convert(t *testing.T) {
 	assert.Equal(t, 6.0*metrics.TiB, group.Metrics.Get(DiskAvailable))
 	assert.Equal(t, 12800.0, group.Metrics.Get(GPUAvailable))
 	assert.Equal(t, 10.0, group.Metrics.Get(PortsAvailable))
+	assert.Equal(t, 10000.0, group.Metrics.Get(NetworkAvailable))
+	assert.Equal(t, 10000.0, group.Metrics.Get(NetworkFree))
 	assert.Equal(t, 1, group.Labels.Count(labels.NewLabel("attribute", "text")))
 	assert.Equal(t, 1, group.Labels.Count(labels.NewLabel("attribute", "1")))
 	assert.Equal(t, 1, group.Labels.Count(labels.NewLabel("attribute", "[31000-31009]")))
 }
+
+func TestHostToGroup(t *testing.T) {
+	offer := testutil.SetupHostOffers().GetOffer()
+	group := HostToGroup(&hostsvc.HostInfo{
+		Hostname:   offer.GetHostname(),
+		Attributes: offer.GetAttributes(),
+	})
+	assert.Equal(t, "hostname", group.Name)
+	assert.Equal(t, 0.0, group.Metrics.Get(CPUAvailable))
+	assert.Equal(t, 1, group.Labels.Count(labels.NewLabel("attribute", "text")))
+	assert.Equal(t, 1, group.Labels.Count(labels.NewLabel("attribute", "1")))
+}
+
+func TestMakeLabels_AttributeValues(t *testing.T) {
+	setType := mesos_v1.Value_SET
+	rangesType := mesos_v1.Value_RANGES
+	offer := &hostsvc.HostOffer{
+		Hostname: "hostname",
+		Attributes: []*mesos_v1.Attribute{
+			{
+				Name: proto.String("zones"),
+				Type: &setType,
+				Set: &mesos_v1.Value_Set{
+					Item: []string{"dca1", "sjc1"},
+				},
+			},
+			{
+				Name: proto.String("numa"),
+				Type: &rangesType,
+				Ranges: &mesos_v1.Value_Ranges{
+					Range: []*mesos_v1.Value_Range{
+						{Begin: proto.Uint64(0), End: proto.Uint64(1)},
+						{Begin: proto.Uint64(4), End: proto.Uint64(5)},
+					},
+				},
+			},
+		},
+	}
+	bag := makeLabels(offer)
+
+	// the labels used by label requirements are unchanged
+	assert.Equal(t, 1, bag.Count(labels.NewLabel("zones", "")))
+	assert.Equal(t, 0, bag.Count(labels.NewLabel("zones", "dca1")))
+	assert.Equal(t, 1, bag.Count(labels.NewLabel("numa", "[0-1];[4-5]")))
+
+	assert.Equal(t, 1, bag.Count(labels.NewLabel(AttributeValue, "zones", "dca1")))
+	assert.Equal(t, 1, bag.Count(labels.NewLabel(AttributeValue, "zones", "sjc1")))
+	assert.Equal(t, 1, bag.Count(labels.NewLabel(AttributeValue, "numa", "[0-1]")))
+	assert.Equal(t, 1, bag.Count(labels.NewLabel(AttributeValue, "numa", "[4-5]")))
+}
@@ -4,4 +4,9 @@ const (
 	// HostName represents the hostname label used
 	// internally by placement engine
 	HostName = "peloton.placementengine.hostname"
+
+	// AttributeValue is the prefix of the labels holding the individual
+	// values of the host attributes, which are only used to evaluate
+	// attribute requirements
+	AttributeValue = "peloton.placementengine.attribute"
 )
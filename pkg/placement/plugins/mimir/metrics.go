@@ -113,6 +113,25 @@ var (
 		Unit:      "#",
 		Inherited: false,
 	}
+
+	// NetworkAvailable represents the available network bandwidth on a host offer.
+	NetworkAvailable = metrics.Type{
+		Name:      "network_available",
+		Unit:      "Mbps",
+		Inherited: false,
+	}
+	// NetworkReserved represents the reserved network bandwidth on a host offer or of a task.
+	NetworkReserved = metrics.Type{
+		Name:      "network_reserved",
+		Unit:      "Mbps",
+		Inherited: true,
+	}
+	// NetworkFree represents the free network bandwidth for a host offer.
+	NetworkFree = metrics.Type{
+		Name:      "network_free",
+		Unit:      "Mbps",
+		Inherited: false,
+	}
 )
 
 var _ = initializeDerivations()
@@ -123,6 +142,7 @@ func initializeDerivations() bool {
 	MemoryFree.SetDerivation(free(MemoryAvailable, MemoryReserved))
 	DiskFree.SetDerivation(free(DiskAvailable, DiskReserved))
 	PortsFree.SetDerivation(free(PortsAvailable, PortsReserved))
+	NetworkFree.SetDerivation(free(NetworkAvailable, NetworkReserved))
 	return true
 }
 
@@ -0,0 +1,134 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mimir
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/pkg/placement/config"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/metrics"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/orderings"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/placement"
+)
+
+// _defaultHostOrdering is the host ordering used if none is configured.
+var _defaultHostOrdering = []config.HostOrderingConfig{
+	{Metric: DiskFree.Name},
+	{Metric: MemoryFree.Name},
+	{Metric: CPUFree.Name},
+	{Metric: GPUFree.Name},
+}
+
+// _freeMetrics maps the free resources, which hosts can be ordered by, to
+// the Mimir metric and the factor converting the metric to CPUs, GPUs or MB.
+var _freeMetrics = map[string]struct {
+	metricType metrics.Type
+	scale      float64
+}{
+	CPUFree.Name:    {CPUFree, 0.01},
+	MemoryFree.Name: {MemoryFree, 1.0 / metrics.MiB},
+	DiskFree.Name:   {DiskFree, 1.0 / metrics.MiB},
+	GPUFree.Name:    {GPUFree, 0.01},
+}
+
+// _preferenceMetrics maps the metrics of placement preferences to the names
+// of the free resources.
+var _preferenceMetrics = map[task.PlacementPreference_Metric]string{
+	task.PlacementPreference_METRIC_CPU_FREE:    CPUFree.Name,
+	task.PlacementPreference_METRIC_MEMORY_FREE: MemoryFree.Name,
+	task.PlacementPreference_METRIC_DISK_FREE:   DiskFree.Name,
+	task.PlacementPreference_METRIC_GPU_FREE:    GPUFree.Name,
+}
+
+// makeHostOrdering creates the orderings of the levels of a host ordering,
+// where the default ordering is used if none is configured. Levels with an
+// unknown metric are skipped.
+func makeHostOrdering(hostOrdering []config.HostOrderingConfig) []placement.Ordering {
+	if len(hostOrdering) == 0 {
+		hostOrdering = _defaultHostOrdering
+	}
+	var result []placement.Ordering
+	for _, level := range hostOrdering {
+		metric, ok := _freeMetrics[level.Metric]
+		if !ok {
+			log.WithField("metric", level.Metric).
+				Error("unknown metric in host ordering")
+			continue
+		}
+		ordering := orderings.Metric(orderings.GroupSource, metric.metricType)
+		if !level.Ascending {
+			ordering = orderings.Negate(ordering)
+		}
+		result = append(result, ordering)
+	}
+	return result
+}
+
+// makePreferenceOrdering creates an ordering which prefers groups with the
+// highest weighted sum of the placement preferences of the constraint, or
+// returns nil if the constraint has no preferences.
+func makePreferenceOrdering(constraint *task.Constraint) placement.Ordering {
+	var terms []placement.Ordering
+	for _, preference := range findPreferences(constraint) {
+		var score placement.Ordering
+		weight := preference.GetWeight()
+		switch preference.GetKind() {
+		case task.PlacementPreference_HOST_LABEL:
+			score = orderings.Label(nil, makeLabel(
+				preference.GetLabel().GetKey(),
+				preference.GetLabel().GetValue()))
+		case task.PlacementPreference_TASK_LABEL:
+			score = orderings.Relation(nil, makeLabel(
+				preference.GetLabel().GetKey(),
+				preference.GetLabel().GetValue()))
+		case task.PlacementPreference_METRIC:
+			metric, ok := _freeMetrics[_preferenceMetrics[preference.GetMetric()]]
+			if !ok {
+				log.WithField("metric", preference.GetMetric()).
+					Warn("unknown placement preference metric")
+				continue
+			}
+			score = orderings.Metric(orderings.GroupSource, metric.metricType)
+			weight *= metric.scale
+		default:
+			log.WithField("kind", preference.GetKind()).
+				Warn("unknown placement preference kind")
+			continue
+		}
+		terms = append(terms, orderings.Multiply(orderings.Constant(weight), score))
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	// Groups with the lowest tuple are preferred, so negate the score.
+	return orderings.Negate(orderings.Sum(terms...))
+}
+
+// findPreferences returns the placement preferences of the constraint and of
+// the constraints nested within and constraints.
+func findPreferences(constraint *task.Constraint) []*task.PlacementPreference {
+	switch constraint.GetType() {
+	case task.Constraint_PREFERENCE_CONSTRAINT:
+		return constraint.GetPreferenceConstraint().GetPreferences()
+	case task.Constraint_AND_CONSTRAINT:
+		var result []*task.PlacementPreference
+		for _, subConstraint := range constraint.GetAndConstraint().GetConstraints() {
+			result = append(result, findPreferences(subConstraint)...)
+		}
+		return result
+	}
+	return nil
+}
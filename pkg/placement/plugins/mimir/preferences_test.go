@@ -0,0 +1,137 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mimir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/private/resmgr"
+	"github.com/uber/peloton/pkg/placement/config"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/labels"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/placement"
+)
+
+func setupPreferenceGroups() []*placement.Group {
+	group1 := placement.NewGroup("host1")
+	group1.Labels.Add(labels.NewLabel("ssd", "true"))
+	group1.Relations.Add(labels.NewLabel("job", "cassandra"))
+	group1.Metrics.Set(CPUFree, 400.0)
+	group1.Metrics.Set(DiskFree, 2000.0)
+
+	group2 := placement.NewGroup("host2")
+	group2.Metrics.Set(CPUFree, 1600.0)
+	group2.Metrics.Set(DiskFree, 1000.0)
+	return []*placement.Group{group1, group2}
+}
+
+func preferenceConstraint(preferences ...*task.PlacementPreference) *task.Constraint {
+	return &task.Constraint{
+		Type: task.Constraint_PREFERENCE_CONSTRAINT,
+		PreferenceConstraint: &task.PreferenceConstraint{
+			Preferences: preferences,
+		},
+	}
+}
+
+func TestMakePreferenceOrdering(t *testing.T) {
+	groups := setupPreferenceGroups()
+	scopeSet := placement.NewScopeSet(groups)
+	ssd := &task.PlacementPreference{
+		Kind:   task.PlacementPreference_HOST_LABEL,
+		Weight: 10,
+		Label:  &peloton.Label{Key: "ssd", Value: "true"},
+	}
+	cpu := &task.PlacementPreference{
+		Kind:   task.PlacementPreference_METRIC,
+		Weight: 1,
+		Metric: task.PlacementPreference_METRIC_CPU_FREE,
+	}
+	antiAffinity := &task.PlacementPreference{
+		Kind:   task.PlacementPreference_TASK_LABEL,
+		Weight: -100,
+		Label:  &peloton.Label{Key: "job", Value: "cassandra"},
+	}
+
+	// the free CPU is scored in CPUs
+	ordering := makePreferenceOrdering(preferenceConstraint(ssd, cpu))
+	assert.Equal(t, []float64{-14.0}, ordering.Tuple(groups[0], scopeSet, nil))
+	assert.Equal(t, []float64{-16.0}, ordering.Tuple(groups[1], scopeSet, nil))
+
+	// preferences within and constraints are honored
+	ordering = makePreferenceOrdering(&task.Constraint{
+		Type: task.Constraint_AND_CONSTRAINT,
+		AndConstraint: &task.AndConstraint{
+			Constraints: []*task.Constraint{
+				preferenceConstraint(antiAffinity),
+				preferenceConstraint(ssd),
+			},
+		},
+	})
+	assert.Equal(t, []float64{90.0}, ordering.Tuple(groups[0], scopeSet, nil))
+	assert.Equal(t, []float64{0.0}, ordering.Tuple(groups[1], scopeSet, nil))
+
+	// preferences within or constraints are ignored
+	assert.Nil(t, makePreferenceOrdering(&task.Constraint{
+		Type: task.Constraint_OR_CONSTRAINT,
+		OrConstraint: &task.OrConstraint{
+			Constraints: []*task.Constraint{preferenceConstraint(ssd)},
+		},
+	}))
+	assert.Nil(t, makePreferenceOrdering(nil))
+	assert.Nil(t, makePreferenceOrdering(preferenceConstraint(
+		&task.PlacementPreference{Kind: task.PlacementPreference_METRIC})))
+}
+
+func TestMakeHostOrdering(t *testing.T) {
+	groups := setupPreferenceGroups()
+	scopeSet := placement.NewScopeSet(groups)
+
+	// by default the hosts with the most free disk are preferred
+	ordering := makeHostOrdering(nil)
+	assert.Len(t, ordering, 4)
+	assert.Equal(t, []float64{-2000.0}, ordering[0].Tuple(groups[0], scopeSet, nil))
+
+	ordering = makeHostOrdering([]config.HostOrderingConfig{
+		{Metric: "cpu_free", Ascending: true},
+		{Metric: "unknown"},
+		{Metric: "disk_free"},
+	})
+	assert.Len(t, ordering, 2)
+	assert.Equal(t, []float64{400.0}, ordering[0].Tuple(groups[0], scopeSet, nil))
+	assert.Equal(t, []float64{-1000.0}, ordering[1].Tuple(groups[1], scopeSet, nil))
+}
+
+func TestTaskToEntity_Preferences(t *testing.T) {
+	groups := setupPreferenceGroups()
+	scopeSet := placement.NewScopeSet(groups)
+	entity := TaskToEntity(&resmgr.Task{
+		Id: &peloton.TaskID{Value: "job-0-1"},
+		Constraint: preferenceConstraint(&task.PlacementPreference{
+			Kind:   task.PlacementPreference_HOST_LABEL,
+			Weight: 1,
+			Label:  &peloton.Label{Key: "ssd", Value: "true"},
+		}),
+	}, false, makeHostOrdering(nil))
+
+	// the preference is ordered before the host ordering
+	assert.Equal(t, []float64{-1.0, -2000.0, 0, -400.0, 0},
+		entity.Ordering.Tuple(groups[0], scopeSet, entity))
+	assert.True(t, entity.Requirement.Passed(
+		groups[1], scopeSet, entity, placement.NewTranscript("transcript")))
+}
@@ -41,15 +41,17 @@ var _offersFactor = map[resmgr.TaskType]float64{
 func New(placer algorithms.Placer, config *config.PlacementConfig) plugins.Strategy {
 	log.Info("Using Mimir placement strategy.")
 	return &mimir{
-		placer: placer,
-		config: config,
+		placer:       placer,
+		config:       config,
+		hostOrdering: makeHostOrdering(config.DefaultHostOrdering),
 	}
 }
 
 // mimir is a placement strategy that uses the mimir library to decide on how to assign tasks to offers.
 type mimir struct {
-	placer algorithms.Placer
-	config *config.PlacementConfig
+	placer       algorithms.Placer
+	config       *config.PlacementConfig
+	hostOrdering []placement.Ordering
 }
 
 func (mimir *mimir) convertAssignments(
@@ -63,7 +65,7 @@ func (mimir *mimir) convertAssignments(
 	for _, p := range pelotonAssignments {
 		data := p.GetTask().Data()
 		if data == nil {
-			entity := TaskToEntity(p.GetTask().GetTask(), false)
+			entity := TaskToEntity(p.GetTask().GetTask(), false, mimir.hostOrdering)
 			p.GetTask().SetData(entity)
 			data = entity
 		}
@@ -85,7 +87,7 @@ func (mimir *mimir) convertHosts(hosts []*models.HostOffers) ([]*placement.Group
 			group := OfferToGroup(host.GetOffer())
 			entities := placement.Entities{}
 			for _, task := range host.GetTasks() {
-				entity := TaskToEntity(task, true)
+				entity := TaskToEntity(task, true, nil)
 				entities.Add(entity)
 			}
 			group.Entities = entities
@@ -100,6 +102,34 @@ func (mimir *mimir) convertHosts(hosts []*models.HostOffers) ([]*placement.Group
 	return groups, groupsToHosts
 }
 
+// convertScopeHosts converts the scope hosts which are not offered to
+// groups, which are only used to evaluate the requirements spanning several
+// groups. The tasks on the offered hosts are already in their groups.
+func (mimir *mimir) convertScopeHosts(
+	hosts []*models.HostOffers,
+	scope []*models.Host) []*placement.Group {
+	offered := make(map[string]struct{}, len(hosts))
+	for _, host := range hosts {
+		offered[host.GetOffer().GetHostname()] = struct{}{}
+	}
+
+	var groups []*placement.Group
+	for _, host := range scope {
+		if _, ok := offered[host.GetHost().GetHostname()]; ok {
+			continue
+		}
+		group := HostToGroup(host.GetHost())
+		entities := placement.Entities{}
+		for _, task := range host.GetTasks() {
+			entities.Add(TaskToEntity(task, true, nil))
+		}
+		group.Entities = entities
+		group.Update()
+		groups = append(groups, group)
+	}
+	return groups
+}
+
 func (mimir *mimir) updateAssignments(
 	assignments []*placement.Assignment,
 	entitiesToAssignments map[*placement.Entity]*models.Assignment,
@@ -120,13 +150,25 @@ func (mimir *mimir) updateAssignments(
 func (mimir *mimir) PlaceOnce(
 	pelotonAssignments []*models.Assignment,
 	hosts []*models.HostOffers) {
+	mimir.PlaceOnceInScope(pelotonAssignments, hosts, nil)
+}
+
+// PlaceOnceInScope is an implementation of the plugins.ScopedStrategy
+// interface.
+func (mimir *mimir) PlaceOnceInScope(
+	pelotonAssignments []*models.Assignment,
+	hosts []*models.HostOffers,
+	scope []*models.Host) {
 	assignments, entitiesToAssignments := mimir.convertAssignments(pelotonAssignments)
 	groups, groupsToHosts := mimir.convertHosts(hosts)
-	scopeSet := placement.NewScopeSet(groups)
+	scopeGroups := append([]*placement.Group{}, groups...)
+	scopeGroups = append(scopeGroups, mimir.convertScopeHosts(hosts, scope)...)
+	scopeSet := placement.NewScopeSet(scopeGroups)
 
 	log.WithFields(log.Fields{
 		"peloton_assignments": pelotonAssignments,
 		"peloton_hosts":       hosts,
+		"scope_hosts":         scope,
 	}).Debug("PlaceOnce Mimir strategy called")
 
 	// Place the assignments onto the groups
@@ -157,12 +199,14 @@ func (mimir *mimir) PlaceOnce(
 func (mimir *mimir) Filters(
 	assignments []*models.Assignment,
 ) map[*hostsvc.HostFilter][]*models.Assignment {
-	// Batch assignments by their scheduling constraints. For each batch,
-	// create a host filter that uses those scheduling constraints.
+	// Batch assignments by their scheduling constraints and GPU configs. For
+	// each batch, create a host filter that uses those scheduling constraints
+	// and GPU config.
 	assignmentsByConstraint := make(map[string][]*models.Assignment)
 	for _, assignment := range assignments {
 		// String() function on protobuf message is nil-safe.
-		s := assignment.GetTask().GetTask().GetConstraint().String()
+		s := assignment.GetTask().GetTask().GetConstraint().String() +
+			assignment.GetTask().GetTask().GetResource().GetGpu().String()
 		batch := assignmentsByConstraint[s]
 		batch = append(batch, assignment)
 		assignmentsByConstraint[s] = batch
@@ -185,7 +229,7 @@ func (mimir *mimir) getFilterForEquivalentAssignments(
 	if len(assignments) == 0 {
 		return nil
 	}
-	var maxCPU, maxGPU, maxMemory, maxDisk, maxPorts float64
+	var maxCPU, maxGPU, maxMemory, maxDisk, maxPorts, maxNetwork float64
 	var revocable bool
 	var hostHints []*hostsvc.FilterHint_Host
 	for _, assignment := range assignments {
@@ -195,6 +239,7 @@ func (mimir *mimir) getFilterForEquivalentAssignments(
 		maxMemory = math.Max(maxMemory, resmgrTask.Resource.MemLimitMb)
 		maxDisk = math.Max(maxDisk, resmgrTask.Resource.DiskLimitMb)
 		maxPorts = math.Max(maxPorts, float64(resmgrTask.NumPorts))
+		maxNetwork = math.Max(maxNetwork, resmgrTask.Resource.NetworkMbps)
 		// All assignments have the same value for revocability
 		revocable = resmgrTask.Revocable
 		if len(resmgrTask.GetDesiredHost()) != 0 {
@@ -218,6 +263,9 @@ func (mimir *mimir) getFilterForEquivalentAssignments(
 				GpuLimit:    maxGPU,
 				MemLimitMb:  maxMemory,
 				DiskLimitMb: maxDisk,
+				NetworkMbps: maxNetwork,
+				// All assignments have the same GPU config
+				Gpu: assignments[0].GetTask().GetTask().GetResource().GetGpu(),
 			},
 			Revocable: revocable,
 		},
@@ -262,3 +262,34 @@ func TestMimirFilters(t *testing.T) {
 		}
 	}
 }
+
+func TestMimirFiltersBatchByGPUConfig(t *testing.T) {
+	strategy := setupStrategy()
+
+	deadline := time.Now().Add(30 * time.Second)
+	gpuConfig := &task.GPUConfig{
+		Models:       []string{"tesla-v100"},
+		SameNumaNode: true,
+	}
+	assignments := []*models.Assignment{
+		testutil.SetupAssignment(deadline, 1),
+		testutil.SetupAssignment(deadline, 1),
+		testutil.SetupAssignment(deadline, 1),
+	}
+	// assignment[0] has no GPU config, assignment[1] and assignment[2]
+	// have the same GPU config
+	assignments[1].GetTask().GetTask().Resource.Gpu = gpuConfig
+	assignments[2].GetTask().GetTask().Resource.Gpu = gpuConfig
+
+	results := strategy.Filters(assignments)
+	assert.Equal(t, 2, len(results))
+	for filter, batch := range results {
+		gpu := filter.GetResourceConstraint().GetMinimum().GetGpu()
+		if gpu == nil {
+			assert.EqualValues(t, assignments[0:1], batch)
+		} else {
+			assert.Equal(t, gpuConfig, gpu)
+			assert.EqualValues(t, assignments[1:3], batch)
+		}
+	}
+}
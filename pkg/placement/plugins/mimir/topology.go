@@ -0,0 +1,126 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mimir
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/labels"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/placement"
+)
+
+// makeTopologyRequirement creates the requirement of a topology constraint.
+func makeTopologyRequirement(
+	constraint *task.TopologyConstraint) placement.Requirement {
+	return NewTopologyRequirement(
+		labels.NewLabel(
+			append(strings.Split(constraint.GetTopologyKey(), "."), "*")...),
+		makeLabel(
+			constraint.GetLabel().GetKey(),
+			constraint.GetLabel().GetValue()),
+		int(constraint.GetMaxPerDomain()),
+		int(constraint.GetMaxSkew()))
+}
+
+// TopologyRequirement represents a requirement on how a relation is spread
+// over the topology domains of the groups, i.e. we want at most two
+// instances of a job in any rack, or the number of instances of a job in any
+// rack to be at most one larger than in the rack with the fewest instances.
+//
+// The occurrences of the relation are counted over all groups of the scope
+// set when the requirement is evaluated, so they include the entities
+// assigned earlier in the same placement round. The scope set must contain
+// the groups of the hosts running the tasks with the relation in addition to
+// the groups being placed on, and a domain only counts as the domain with
+// the fewest occurrences if one of its groups is in the scope set.
+type TopologyRequirement struct {
+	// Scope is the label pattern of the topology domains, e.g. rack.*.
+	Scope        *labels.Label
+	Relation     *labels.Label
+	MaxPerDomain int
+	MaxSkew      int
+}
+
+// NewTopologyRequirement creates a new topology requirement, a limit of
+// zero means no limit.
+func NewTopologyRequirement(
+	scope, relation *labels.Label,
+	maxPerDomain, maxSkew int) *TopologyRequirement {
+	return &TopologyRequirement{
+		Scope:        scope,
+		Relation:     relation,
+		MaxPerDomain: maxPerDomain,
+		MaxSkew:      maxSkew,
+	}
+}
+
+// Passed checks if the group is in a topology domain and if placing the
+// entity on the group keeps the occurrences of the relation in the domain
+// of the group within the maximum per domain and within the maximum skew of
+// the domain with the fewest occurrences.
+func (requirement *TopologyRequirement) Passed(group *placement.Group, scopeSet *placement.ScopeSet,
+	entity *placement.Entity, transcript *placement.Transcript) bool {
+	domains := group.Labels.Find(requirement.Scope)
+	if len(domains) == 0 {
+		transcript.IncFailed()
+		return false
+	}
+	if entity == nil || entity.Relations.Count(requirement.Relation) == 0 {
+		// the entity doesn't change the occurrences of the relation
+		transcript.IncPassed()
+		return true
+	}
+
+	occurrences := map[string]int{}
+	for _, scopeGroup := range scopeSet.ScopeGroups() {
+		for _, domain := range scopeGroup.Labels.Find(requirement.Scope) {
+			occurrences[domain.String()] +=
+				scopeGroup.Relations.Count(requirement.Relation)
+		}
+	}
+	min := -1
+	for _, count := range occurrences {
+		if min < 0 || count < min {
+			min = count
+		}
+	}
+
+	for _, domain := range domains {
+		count := occurrences[domain.String()] + 1
+		if requirement.MaxPerDomain > 0 && count > requirement.MaxPerDomain {
+			transcript.IncFailed()
+			return false
+		}
+		if requirement.MaxSkew > 0 && count-min > requirement.MaxSkew {
+			transcript.IncFailed()
+			return false
+		}
+	}
+	transcript.IncPassed()
+	return true
+}
+
+func (requirement *TopologyRequirement) String() string {
+	return fmt.Sprintf("requires that the occurrences of the relation %v are at most %v per domain and "+
+		"spread with a skew of at most %v over %v",
+		requirement.Relation, requirement.MaxPerDomain, requirement.MaxSkew, requirement.Scope)
+}
+
+// Composite returns false as the requirement is not composite and the name of the requirement type.
+func (requirement *TopologyRequirement) Composite() (bool, string) {
+	return false, "topology"
+}
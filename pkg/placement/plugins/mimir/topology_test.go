@@ -0,0 +1,139 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mimir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/labels"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/model/placement"
+)
+
+func setupTopologyGroups() []*placement.Group {
+	makeGroup := func(name, rack string, instances int) *placement.Group {
+		group := placement.NewGroup(name)
+		if rack != "" {
+			group.Labels.Add(labels.NewLabel("rack", rack))
+		}
+		for i := 0; i < instances; i++ {
+			group.Relations.Add(labels.NewLabel("job", "cassandra"))
+		}
+		return group
+	}
+	return []*placement.Group{
+		makeGroup("host1", "r1", 1),
+		makeGroup("host2", "r1", 1),
+		makeGroup("host3", "r2", 1),
+		makeGroup("host4", "r3", 0),
+		makeGroup("host5", "", 0),
+	}
+}
+
+func topologyConstraint(maxPerDomain, maxSkew uint32) *task.Constraint {
+	return &task.Constraint{
+		Type: task.Constraint_TOPOLOGY_CONSTRAINT,
+		TopologyConstraint: &task.TopologyConstraint{
+			TopologyKey: "rack",
+			Label: &peloton.Label{
+				Key:   "job",
+				Value: "cassandra",
+			},
+			MaxPerDomain: maxPerDomain,
+			MaxSkew:      maxSkew,
+		},
+	}
+}
+
+func TestTopologyRequirement_MaxPerDomain(t *testing.T) {
+	groups := setupTopologyGroups()
+	scopeSet := placement.NewScopeSet(groups)
+	entity := placement.NewEntity("task")
+	entity.Relations.Add(labels.NewLabel("job", "cassandra"))
+	requirement := makeAffinityRequirements(topologyConstraint(2, 0))
+
+	passed := []bool{false, false, true, true, false}
+	for i, group := range groups {
+		transcript := placement.NewTranscript("transcript")
+		assert.Equal(t, passed[i],
+			requirement.Passed(group, scopeSet, entity, transcript),
+			group.Name)
+	}
+}
+
+func TestTopologyRequirement_MaxSkew(t *testing.T) {
+	groups := setupTopologyGroups()
+	scopeSet := placement.NewScopeSet(groups)
+	entity := placement.NewEntity("task")
+	entity.Relations.Add(labels.NewLabel("job", "cassandra"))
+
+	// rack r3 has no instances, so only it can take one more
+	requirement := makeAffinityRequirements(topologyConstraint(0, 1))
+	passed := []bool{false, false, false, true, false}
+	for i, group := range groups {
+		transcript := placement.NewTranscript("transcript")
+		assert.Equal(t, passed[i],
+			requirement.Passed(group, scopeSet, entity, transcript),
+			group.Name)
+	}
+
+	// a larger skew also allows rack r2
+	requirement = makeAffinityRequirements(topologyConstraint(0, 2))
+	passed = []bool{false, false, true, true, false}
+	for i, group := range groups {
+		transcript := placement.NewTranscript("transcript")
+		assert.Equal(t, passed[i],
+			requirement.Passed(group, scopeSet, entity, transcript),
+			group.Name)
+	}
+
+	// an entity without the relation does not change the skew
+	transcript := placement.NewTranscript("transcript")
+	assert.True(t, requirement.Passed(
+		groups[0], scopeSet, placement.NewEntity("other"), transcript))
+}
+
+func TestTopologyRequirement_ScopeGroups(t *testing.T) {
+	groups := setupTopologyGroups()
+	// a host of the job which is not offered still counts for rack r3
+	scopeGroup := placement.NewGroup("host6")
+	scopeGroup.Labels.Add(labels.NewLabel("rack", "r3"))
+	scopeGroup.Relations.Add(labels.NewLabel("job", "cassandra"))
+	scopeSet := placement.NewScopeSet(append(groups, scopeGroup))
+	entity := placement.NewEntity("task")
+	entity.Relations.Add(labels.NewLabel("job", "cassandra"))
+	requirement := makeAffinityRequirements(topologyConstraint(0, 1))
+
+	passed := []bool{false, false, true, true, false}
+	for i, group := range groups {
+		transcript := placement.NewTranscript("transcript")
+		assert.Equal(t, passed[i],
+			requirement.Passed(group, scopeSet, entity, transcript),
+			group.Name)
+	}
+}
+
+func TestTopologyRequirement_Entity(t *testing.T) {
+	requirement, ok := makeAffinityRequirements(
+		topologyConstraint(2, 1)).(*TopologyRequirement)
+	assert.True(t, ok)
+	assert.Equal(t, labels.NewLabel("rack", "*"), requirement.Scope)
+	assert.Equal(t, labels.NewLabel("job", "cassandra"), requirement.Relation)
+	assert.Equal(t, 2, requirement.MaxPerDomain)
+	assert.Equal(t, 1, requirement.MaxSkew)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registry keeps the placement strategies which the placement
+// engine can be configured with.
+package registry
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/uber/peloton/pkg/placement/config"
+	"github.com/uber/peloton/pkg/placement/plugins"
+	"github.com/uber/peloton/pkg/placement/plugins/batch"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir"
+	"github.com/uber/peloton/pkg/placement/plugins/mimir/lib/algorithms"
+	"github.com/uber/peloton/pkg/placement/plugins/scoring"
+)
+
+// StrategyFunc type of func which returns a placement strategy for the
+// given placement config.
+type StrategyFunc func(cfg *config.PlacementConfig) plugins.Strategy
+
+// map of strategy name to Init Strategy Func
+var strategies = make(map[config.PlacementStrategy]StrategyFunc)
+
+// Register registers the strategy and keep it in the
+// strategy map.
+func Register(name config.PlacementStrategy, strategy StrategyFunc) {
+	log.Infof("Registering %s placement strategy", name)
+	if strategy == nil {
+		log.Errorf("strategy does not exist")
+		return
+	}
+	if _, registered := strategies[name]; registered {
+		log.Errorf("strategy already registered")
+		return
+	}
+	strategies[name] = strategy
+}
+
+// Init registers all the strategies
+func Init() {
+	Register(config.Batch, newBatch)
+	Register(config.Mimir, newMimir)
+	Register(config.Scoring, scoring.New)
+}
+
+// CreateStrategy creates and returns the strategy configured in the given
+// placement config, or nil if the strategy is not registered.
+func CreateStrategy(cfg *config.PlacementConfig) plugins.Strategy {
+	strategy, ok := strategies[cfg.Strategy]
+	if !ok {
+		log.WithField("strategy", cfg.Strategy).
+			Errorf("Strategy is not registered")
+		return nil
+	}
+	return strategy(cfg)
+}
+
+func newBatch(_ *config.PlacementConfig) plugins.Strategy {
+	return batch.New()
+}
+
+func newMimir(cfg *config.PlacementConfig) plugins.Strategy {
+	placer := algorithms.NewPlacer(4, 300)
+	return mimir.New(placer, cfg)
+}
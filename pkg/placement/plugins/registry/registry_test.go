@@ -0,0 +1,65 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uber/peloton/.gen/peloton/private/resmgr"
+	"github.com/uber/peloton/pkg/placement/config"
+	"github.com/uber/peloton/pkg/placement/plugins"
+)
+
+func TestCreateStrategy(t *testing.T) {
+	Init()
+
+	for _, name := range []config.PlacementStrategy{
+		config.Batch,
+		config.Mimir,
+		config.Scoring,
+	} {
+		strategy := CreateStrategy(&config.PlacementConfig{
+			Strategy: name,
+			TaskType: resmgr.TaskType_BATCH,
+		})
+		assert.NotNil(t, strategy, string(name))
+	}
+
+	assert.Nil(t, CreateStrategy(&config.PlacementConfig{
+		Strategy: config.PlacementStrategy("unknown"),
+	}))
+}
+
+func TestRegister(t *testing.T) {
+	Init()
+
+	custom := config.PlacementStrategy("custom")
+	created := false
+	Register(custom, func(cfg *config.PlacementConfig) plugins.Strategy {
+		created = true
+		return CreateStrategy(&config.PlacementConfig{Strategy: config.Batch})
+	})
+	// registering a strategy twice or a nil strategy is ignored
+	Register(custom, nil)
+	Register(config.Batch, func(cfg *config.PlacementConfig) plugins.Strategy {
+		return nil
+	})
+
+	assert.NotNil(t, CreateStrategy(&config.PlacementConfig{Strategy: custom}))
+	assert.True(t, created)
+	assert.NotNil(t, CreateStrategy(&config.PlacementConfig{Strategy: config.Batch}))
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scoring
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/uber/peloton/.gen/mesos/v1"
+	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
+	"github.com/uber/peloton/pkg/hostmgr/scalar"
+	"github.com/uber/peloton/pkg/placement/config"
+	"github.com/uber/peloton/pkg/placement/models"
+	"github.com/uber/peloton/pkg/placement/plugins"
+)
+
+// New creates a new scoring placement strategy.
+func New(cfg *config.PlacementConfig) plugins.Strategy {
+	policy := cfg.Scoring.Policy
+	if policy == "" {
+		policy = config.BestFit
+	}
+	weights := cfg.Scoring.Weights
+	if weights == (config.ScoringWeightsConfig{}) {
+		weights = config.ScoringWeightsConfig{CPU: 1, Memory: 1, Disk: 1, GPU: 1}
+	}
+	log.WithFields(log.Fields{
+		"policy":  policy,
+		"weights": weights,
+	}).Info("Using scoring placement strategy.")
+	return &scoring{
+		policy:  policy,
+		weights: weights,
+	}
+}
+
+// scoring is the placement strategy which places each task on the host with
+// the best score, where the score of a host is the weighted fraction of its
+// offered resources which would be left free after placing the task.
+type scoring struct {
+	policy  config.ScoringPolicy
+	weights config.ScoringWeightsConfig
+}
+
+// hostState is the resources of a host which are left free in a round.
+type hostState struct {
+	host    *models.HostOffers
+	offered scalar.Resources
+	free    scalar.Resources
+	ports   uint64
+}
+
+// PlaceOnce is an implementation of the placement.Strategy interface.
+func (s *scoring) PlaceOnce(unassigned []*models.Assignment, hosts []*models.HostOffers) {
+	// the scoring strategy only sees the offered hosts and can't count the
+	// tasks per topology domain
+	unassigned = plugins.RejectTopologyLimits(unassigned)
+	states := make([]*hostState, 0, len(hosts))
+	for _, host := range hosts {
+		offered := scalar.FromMesosResources(host.GetOffer().GetResources())
+		states = append(states, &hostState{
+			host:    host,
+			offered: offered,
+			free:    offered,
+			ports:   availablePorts(host.GetOffer().GetResources()),
+		})
+	}
+
+	for _, assignment := range unassigned {
+		resmgrTask := assignment.GetTask().GetTask()
+		usage := scalar.FromResourceConfig(resmgrTask.GetResource())
+		ports := uint64(resmgrTask.GetNumPorts())
+
+		var best *hostState
+		var bestFree scalar.Resources
+		var bestScore float64
+		for _, state := range states {
+			if ports > state.ports {
+				continue
+			}
+			free, ok := state.free.TrySubtract(usage)
+			if !ok {
+				continue
+			}
+			score := s.score(state.offered, free)
+			if best == nil || score > bestScore {
+				best, bestFree, bestScore = state, free, score
+			}
+		}
+		if best == nil {
+			log.WithField("resmgr_task", resmgrTask).
+				Debug("No host with sufficient resources")
+			continue
+		}
+
+		best.free = bestFree
+		best.ports -= ports
+		assignment.SetHost(best.host)
+	}
+
+	log.WithFields(log.Fields{
+		"unassigned": unassigned,
+		"hosts":      hosts,
+		"strategy":   "scoring",
+	}).Info("PlaceOnce scoring strategy returned")
+}
+
+// score returns the score of a host which would have the given resources
+// free out of the offered resources, higher is better.
+func (s *scoring) score(offered, free scalar.Resources) float64 {
+	var total, weights float64
+	add := func(weight, offered, free float64) {
+		if weight <= 0 || offered <= 0 {
+			return
+		}
+		total += weight * free / offered
+		weights += weight
+	}
+	add(s.weights.CPU, offered.GetCPU(), free.GetCPU())
+	add(s.weights.Memory, offered.GetMem(), free.GetMem())
+	add(s.weights.Disk, offered.GetDisk(), free.GetDisk())
+	add(s.weights.GPU, offered.GetGPU(), free.GetGPU())
+	if weights == 0 {
+		return 0
+	}
+
+	freeFraction := total / weights
+	if s.policy == config.LeastAllocated {
+		return freeFraction
+	}
+	return 1 - freeFraction
+}
+
+func availablePorts(resources []*mesos_v1.Resource) uint64 {
+	var ports uint64
+	for _, resource := range resources {
+		if resource.GetName() != "ports" {
+			continue
+		}
+		for _, portRange := range resource.GetRanges().GetRange() {
+			ports += portRange.GetEnd() - portRange.GetBegin() + 1
+		}
+	}
+	return ports
+}
+
+func getHostFilter(assignment *models.Assignment) *hostsvc.HostFilter {
+	result := &hostsvc.HostFilter{
+		ResourceConstraint: &hostsvc.ResourceConstraint{
+			Minimum:   assignment.GetTask().GetTask().Resource,
+			NumPorts:  assignment.GetTask().GetTask().NumPorts,
+			Revocable: assignment.GetTask().GetTask().Revocable,
+		},
+	}
+	if constraint := assignment.GetTask().GetTask().Constraint; constraint != nil {
+		result.SchedulingConstraint = constraint
+	}
+	return result
+}
+
+// Filters is an implementation of the placement.Strategy interface.
+// Assignments with the same resources and constraints share a host filter,
+// which asks for as many hosts as there are assignments so that the
+// assignments can be scored against several hosts.
+func (s *scoring) Filters(assignments []*models.Assignment) map[*hostsvc.HostFilter][]*models.Assignment {
+	groups := map[string]*hostsvc.HostFilter{}
+	filters := map[*hostsvc.HostFilter][]*models.Assignment{}
+	for _, assignment := range assignments {
+		filter := getHostFilter(assignment)
+		// String() function on protobuf message should be nil-safe.
+		key := filter.String()
+		if _, exists := groups[key]; !exists {
+			groups[key] = filter
+		}
+		filters[groups[key]] = append(filters[groups[key]], assignment)
+	}
+
+	for filter, batch := range filters {
+		filter.Quantity = &hostsvc.QuantityControl{
+			MaxHosts: uint32(len(batch)),
+		}
+	}
+	return filters
+}
+
+// ConcurrencySafe is an implementation of the placement.Strategy interface.
+func (s *scoring) ConcurrencySafe() bool {
+	return true
+}
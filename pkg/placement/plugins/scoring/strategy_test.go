@@ -0,0 +1,118 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/peloton/pkg/placement/config"
+	"github.com/uber/peloton/pkg/placement/models"
+	"github.com/uber/peloton/pkg/placement/testutil"
+)
+
+// setupHostOffers creates host offers with the given number of cpus each.
+func setupHostOffers(cpus ...float64) []*models.HostOffers {
+	var hosts []*models.HostOffers
+	for _, value := range cpus {
+		host := testutil.SetupHostOffers()
+		for _, resource := range host.GetOffer().GetResources() {
+			if resource.GetName() == "cpus" {
+				v := value
+				resource.Scalar.Value = &v
+			}
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+func setupAssignments(n int, cpus float64) []*models.Assignment {
+	var assignments []*models.Assignment
+	for i := 0; i < n; i++ {
+		assignment := testutil.SetupAssignment(time.Now().Add(10*time.Second), 1)
+		assignment.GetTask().GetTask().Resource.CpuLimit = cpus
+		assignment.GetTask().GetTask().NumPorts = 1
+		assignments = append(assignments, assignment)
+	}
+	return assignments
+}
+
+func TestScoringPlaceBestFit(t *testing.T) {
+	hosts := setupHostOffers(96, 48)
+	assignments := setupAssignments(3, 16)
+	strategy := New(&config.PlacementConfig{
+		Scoring: config.ScoringConfig{
+			Policy:  config.BestFit,
+			Weights: config.ScoringWeightsConfig{CPU: 1},
+		},
+	})
+	strategy.PlaceOnce(assignments, hosts)
+
+	// the tasks are packed onto the smaller host until it is full
+	assert.Equal(t, hosts[1], assignments[0].GetHost())
+	assert.Equal(t, hosts[1], assignments[1].GetHost())
+	assert.Equal(t, hosts[1], assignments[2].GetHost())
+}
+
+func TestScoringPlaceLeastAllocated(t *testing.T) {
+	hosts := setupHostOffers(96, 48)
+	assignments := setupAssignments(3, 16)
+	strategy := New(&config.PlacementConfig{
+		Scoring: config.ScoringConfig{
+			Policy:  config.LeastAllocated,
+			Weights: config.ScoringWeightsConfig{CPU: 1},
+		},
+	})
+	strategy.PlaceOnce(assignments, hosts)
+
+	// the tasks are spread to the hosts with the largest free fraction
+	assert.Equal(t, hosts[0], assignments[0].GetHost())
+	assert.Equal(t, hosts[0], assignments[1].GetHost())
+	assert.Equal(t, hosts[1], assignments[2].GetHost())
+}
+
+func TestScoringPlaceInsufficientResources(t *testing.T) {
+	hosts := setupHostOffers(48)
+	assignments := setupAssignments(2, 32)
+	strategy := New(&config.PlacementConfig{})
+	strategy.PlaceOnce(assignments, hosts)
+
+	assert.Equal(t, hosts[0], assignments[0].GetHost())
+	assert.Nil(t, assignments[1].GetHost())
+}
+
+func TestScoringFilters(t *testing.T) {
+	assignments := setupAssignments(3, 16)
+	assignments[2].GetTask().GetTask().Resource.CpuLimit = 32
+	strategy := New(&config.PlacementConfig{})
+
+	filters := strategy.Filters(assignments)
+
+	assert.Equal(t, 2, len(filters))
+	for filter, batch := range filters {
+		assert.Equal(t, uint32(len(batch)), filter.GetQuantity().GetMaxHosts())
+		switch filter.GetResourceConstraint().GetMinimum().GetCpuLimit() {
+		case 16.0:
+			assert.Equal(t, 2, len(batch))
+		case 32.0:
+			assert.Equal(t, 1, len(batch))
+		default:
+			assert.Fail(t, "unexpected cpu limit")
+		}
+	}
+	assert.True(t, strategy.ConcurrencySafe())
+}
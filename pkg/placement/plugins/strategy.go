@@ -36,3 +36,19 @@ type Strategy interface {
 	// go-routine is allowed to run the PlaceOnce method at a time.
 	ConcurrencySafe() bool
 }
+
+// ScopedStrategy is a placement strategy which honors constraints spanning
+// several hosts, like the limits of topology constraints, and so needs to
+// see the hosts running the tasks of the jobs being placed in addition to
+// the offered hosts.
+type ScopedStrategy interface {
+	Strategy
+
+	// PlaceOnceInScope is like PlaceOnce, but also takes the tasks running on
+	// the scope hosts into account for the constraints spanning several
+	// hosts. Tasks are never assigned to the scope hosts.
+	PlaceOnceInScope(
+		assignments []*models.Assignment,
+		hosts []*models.HostOffers,
+		scope []*models.Host)
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugins
+
+import (
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
+	"github.com/uber/peloton/.gen/peloton/private/resmgr"
+
+	"github.com/uber/peloton/pkg/common/constraints"
+	"github.com/uber/peloton/pkg/placement/models"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// TopologyLimitsReason is the reason of the assignments which are not placed
+// because the strategy can't enforce the limits of their topology constraints.
+const TopologyLimitsReason = "topology constraints with limits per domain " +
+	"are not supported by the placement strategy"
+
+// RejectTopologyLimits returns the assignments whose tasks have no topology
+// constraint limiting the number of tasks per topology domain. The other
+// assignments are left unplaced with TopologyLimitsReason, for strategies
+// which can't enforce those limits.
+func RejectTopologyLimits(
+	assignments []*models.Assignment) []*models.Assignment {
+	result := make([]*models.Assignment, 0, len(assignments))
+	for _, assignment := range assignments {
+		if constraints.HasTopologyLimits(
+			assignment.GetTask().GetTask().GetConstraint()) {
+			assignment.SetReason(TopologyLimitsReason)
+			continue
+		}
+		result = append(result, assignment)
+	}
+	return result
+}
+
+// TopologyDomainCounts returns the number of tasks carrying the label of
+// each topology constraint with limits of the task per topology domain,
+// counted over the tasks of the scope hosts, so that host manager can
+// enforce the limits when filtering hosts for the task. The domains of the
+// scope hosts without such tasks are included with a zero count.
+func TopologyDomainCounts(
+	rmTask *resmgr.Task,
+	scope []*models.Host) []*hostsvc.TopologyDomainCounts {
+	var result []*hostsvc.TopologyDomainCounts
+	for _, constraint := range constraints.GetTopologyLimits(
+		rmTask.GetConstraint()) {
+		counts := make(map[string]uint32)
+		for _, host := range scope {
+			hostLabels := constraints.GetHostLabelValues(
+				host.GetHost().GetHostname(),
+				host.GetHost().GetAttributes())
+			var count uint32
+			for _, hostTask := range host.GetTasks() {
+				if hasLabel(hostTask, constraint) {
+					count++
+				}
+			}
+			for domain := range hostLabels[constraint.GetTopologyKey()] {
+				counts[domain] += count
+			}
+		}
+		result = append(result, &hostsvc.TopologyDomainCounts{
+			TopologyKey:      constraint.GetTopologyKey(),
+			Label:            constraint.GetLabel(),
+			Counts:           counts,
+			CountsPlacedTask: hasLabel(rmTask, constraint),
+		})
+	}
+	return result
+}
+
+// hasLabel returns true if the task carries the label of the topology
+// constraint.
+func hasLabel(
+	rmTask *resmgr.Task,
+	constraint *task.TopologyConstraint) bool {
+	for _, label := range rmTask.GetLabels().GetLabels() {
+		if label.GetKey() == constraint.GetLabel().GetKey() &&
+			label.GetValue() == constraint.GetLabel().GetValue() {
+			return true
+		}
+	}
+	return false
+}
+
+// WithTopologyDomainCounts returns a copy of the host filter of the
+// assignments with the number of tasks per topology domain of their topology
+// constraints with limits, counted over the scope hosts. The assignments of
+// a host filter share their constraint, so the counts are computed for the
+// first one with limits. The filter is returned unchanged if none has limits.
+func WithTopologyDomainCounts(
+	filter *hostsvc.HostFilter,
+	assignments []*models.Assignment,
+	scope []*models.Host) *hostsvc.HostFilter {
+	for _, assignment := range assignments {
+		rmTask := assignment.GetTask().GetTask()
+		if !constraints.HasTopologyLimits(rmTask.GetConstraint()) {
+			continue
+		}
+		result := proto.Clone(filter).(*hostsvc.HostFilter)
+		result.TopologyDomainCounts = TopologyDomainCounts(rmTask, scope)
+		return result
+	}
+	return filter
+}
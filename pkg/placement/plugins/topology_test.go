@@ -0,0 +1,92 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugins
+
+import (
+	"testing"
+	"time"
+
+	mesos_v1 "github.com/uber/peloton/.gen/mesos/v1"
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
+	"github.com/uber/peloton/.gen/peloton/private/resmgr"
+
+	"github.com/uber/peloton/pkg/placement/models"
+	"github.com/uber/peloton/pkg/placement/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithTopologyDomainCounts tests that the host filter gets the number
+// of tasks per topology domain on the scope hosts.
+func TestWithTopologyDomainCounts(t *testing.T) {
+	label := &peloton.Label{Key: "job", Value: "cassandra"}
+	mesosLabels := &mesos_v1.Labels{
+		Labels: []*mesos_v1.Label{
+			{Key: &label.Key, Value: &label.Value},
+		},
+	}
+	topology := testutil.SetupAssignment(time.Now().Add(10*time.Second), 1)
+	topology.GetTask().GetTask().Labels = mesosLabels
+	topology.GetTask().GetTask().Constraint = &task.Constraint{
+		Type: task.Constraint_TOPOLOGY_CONSTRAINT,
+		TopologyConstraint: &task.TopologyConstraint{
+			TopologyKey:  "rack",
+			Label:        label,
+			MaxPerDomain: 1,
+		},
+	}
+	other := testutil.SetupAssignment(time.Now().Add(10*time.Second), 1)
+
+	rack := func(value string) []*mesos_v1.Attribute {
+		name := "rack"
+		textType := mesos_v1.Value_TEXT
+		return []*mesos_v1.Attribute{
+			{
+				Name: &name,
+				Type: &textType,
+				Text: &mesos_v1.Value_Text{Value: &value},
+			},
+		}
+	}
+	scope := []*models.Host{
+		models.NewHosts(
+			&hostsvc.HostInfo{Hostname: "host1", Attributes: rack("r1")},
+			[]*resmgr.Task{{Labels: mesosLabels}, {}}),
+		models.NewHosts(
+			&hostsvc.HostInfo{Hostname: "host2", Attributes: rack("r2")},
+			nil),
+	}
+
+	filter := &hostsvc.HostFilter{
+		SchedulingConstraint: topology.GetTask().GetTask().GetConstraint(),
+	}
+	result := WithTopologyDomainCounts(
+		filter, []*models.Assignment{other, topology}, scope)
+	assert.Nil(t, filter.GetTopologyDomainCounts())
+	assert.Equal(t, []*hostsvc.TopologyDomainCounts{
+		{
+			TopologyKey:      "rack",
+			Label:            label,
+			Counts:           map[string]uint32{"r1": 1, "r2": 0},
+			CountsPlacedTask: true,
+		},
+	}, result.GetTopologyDomainCounts())
+
+	// the filter is unchanged without topology constraints with limits
+	assert.True(t, filter ==
+		WithTopologyDomainCounts(filter, []*models.Assignment{other}, scope))
+}
@@ -29,11 +29,13 @@ import (
 	"github.com/uber/peloton/.gen/peloton/private/resmgr"
 
 	"github.com/uber/peloton/pkg/common/async"
+	"github.com/uber/peloton/pkg/common/constraints"
 	"github.com/uber/peloton/pkg/common/queue"
 	"github.com/uber/peloton/pkg/placement/config"
 	"github.com/uber/peloton/pkg/placement/hosts"
 	tally_metrics "github.com/uber/peloton/pkg/placement/metrics"
 	"github.com/uber/peloton/pkg/placement/models"
+	"github.com/uber/peloton/pkg/placement/plugins"
 	"github.com/uber/peloton/pkg/placement/tasks"
 	"github.com/uber/peloton/pkg/placement/util"
 
@@ -211,7 +213,13 @@ func (r *reserver) Reserve(ctx context.Context) (time.Duration, error) {
 		"task": task.Id.Value,
 	}).Debug("Reserving host for task")
 
-	hostFilter := r.getHostFilter(task)
+	hostFilter, err := r.getHostFilter(ctx, task)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"task": task.Id,
+		}).Info("Couldn't get the hosts of the job of the task")
+		return _noHostsTimeoutPenalty, err
+	}
 	// Find the hosts list from hostmanager matching filter
 	hosts, err := r.hostService.GetHosts(ctx, task, hostFilter)
 	if err != nil {
@@ -285,7 +293,12 @@ func random(min, max int) int {
 	return rand.Intn(max-min) + min
 }
 
-func (r *reserver) getHostFilter(task *resmgr.Task) *hostsvc.HostFilter {
+// getHostFilter returns the host filter of the task, with the number of
+// tasks per topology domain on the hosts of its job if the task has
+// topology constraints with limits.
+func (r *reserver) getHostFilter(
+	ctx context.Context,
+	task *resmgr.Task) (*hostsvc.HostFilter, error) {
 	result := &hostsvc.HostFilter{
 		ResourceConstraint: &hostsvc.ResourceConstraint{
 			Minimum:  task.Resource,
@@ -295,7 +308,15 @@ func (r *reserver) getHostFilter(task *resmgr.Task) *hostsvc.HostFilter {
 	if constraint := task.Constraint; constraint != nil {
 		result.SchedulingConstraint = constraint
 	}
-	return result
+	if constraints.HasTopologyLimits(task.GetConstraint()) {
+		jobHosts, err := r.hostService.GetJobHosts(ctx, task)
+		if err != nil {
+			return nil, err
+		}
+		result.TopologyDomainCounts = plugins.TopologyDomainCounts(
+			task, jobHosts)
+	}
+	return result, nil
 }
 
 // ProcessHostReservation places the assignments which are ready for host reservation
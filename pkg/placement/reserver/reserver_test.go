@@ -393,6 +393,37 @@ func (suite *ReserverTestSuite) TestTaskLen() {
 	suite.Equal(taskLen(host), 1)
 }
 
+// TestGetHostFilterWithTopologyLimits tests that the host filter of a task
+// with topology constraints with limits gets the number of tasks per
+// topology domain on the hosts of its job
+func (suite *ReserverTestSuite) TestGetHostFilterWithTopologyLimits() {
+	rmTask := createResMgrTask()
+	rmTask.Constraint = &task.Constraint{
+		Type: task.Constraint_TOPOLOGY_CONSTRAINT,
+		TopologyConstraint: &task.TopologyConstraint{
+			TopologyKey:  "rack",
+			MaxPerDomain: 2,
+		},
+	}
+
+	suite.hostService.EXPECT().
+		GetJobHosts(gomock.Any(), rmTask).
+		Return(nil, nil)
+	filter, err := suite.reserver.(*reserver).getHostFilter(
+		context.Background(), rmTask)
+	suite.NoError(err)
+	suite.Equal(rmTask.GetConstraint(), filter.GetSchedulingConstraint())
+	suite.Len(filter.GetTopologyDomainCounts(), 1)
+	suite.Equal("rack", filter.GetTopologyDomainCounts()[0].GetTopologyKey())
+
+	suite.hostService.EXPECT().
+		GetJobHosts(gomock.Any(), rmTask).
+		Return(nil, errors.New("error"))
+	_, err = suite.reserver.(*reserver).getHostFilter(
+		context.Background(), rmTask)
+	suite.Error(err)
+}
+
 func createResMgrTask() *resmgr.Task {
 	return &resmgr.Task{
 		Name:     "task",
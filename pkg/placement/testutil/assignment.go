@@ -54,6 +54,7 @@ func SetupAssignment(deadline time.Time, maxRounds int) *models.Assignment {
 			MemLimitMb:  4096.0,
 			DiskLimitMb: 1024.0,
 			FdLimit:     32,
+			NetworkMbps: 100.0,
 		},
 		NumPorts: 3,
 		Constraint: &task.Constraint{
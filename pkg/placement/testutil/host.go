@@ -34,10 +34,12 @@ func SetupHostOffers() *models.HostOffers {
 	diskName := "disk"
 	gpuName := "gpus"
 	ports := "ports"
+	networkName := "network_bandwidth"
 	cpuValue := 48.0
 	gpuValue := 128.0
 	memoryValue := 128.0 * 1024.0
 	diskValue := 6.0 * 1024.0 * 1024.0
+	networkValue := 10000.0
 	scalar := 1.0
 	begin := uint64(31000)
 	end := uint64(31009)
@@ -100,6 +102,12 @@ func SetupHostOffers() *models.HostOffers {
 					Value: &gpuValue,
 				},
 			},
+			{
+				Name: &networkName,
+				Scalar: &mesos_v1.Value_Scalar{
+					Value: &networkValue,
+				},
+			},
 			{
 				Name: &ports,
 				Ranges: &mesos_v1.Value_Ranges{
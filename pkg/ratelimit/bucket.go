@@ -0,0 +1,77 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket, which is thread safe.
+type bucket struct {
+	sync.Mutex
+
+	budget Budget
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(budget Budget, now time.Time) *bucket {
+	if budget.Burst <= 0 {
+		budget.Burst = math.Max(budget.Rate, 1)
+	}
+	return &bucket{
+		budget: budget,
+		tokens: budget.Burst,
+		last:   now,
+	}
+}
+
+// refill adds the tokens accumulated since the last refill.
+// The lock must be held by the caller.
+func (b *bucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens = math.Min(
+			b.budget.Burst,
+			b.tokens+now.Sub(b.last).Seconds()*b.budget.Rate,
+		)
+		b.last = now
+	}
+}
+
+// take takes a token from the bucket, and returns false if the bucket
+// is empty
+func (b *bucket) take(now time.Time) bool {
+	b.Lock()
+	defer b.Unlock()
+
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// full returns whether the bucket is full, in which case it is the same
+// as a new bucket and can be dropped
+func (b *bucket) full(now time.Time) bool {
+	b.Lock()
+	defer b.Unlock()
+
+	b.refill(now)
+	return b.tokens >= b.budget.Burst
+}
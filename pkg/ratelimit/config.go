@@ -0,0 +1,88 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"time"
+)
+
+const (
+	_defaultReloadInterval = 30 * time.Second
+)
+
+// Config is rate limit specific configuration
+type Config struct {
+	// Path is the path to the rate limit rules file. Rate limiting is
+	// disabled if it is empty.
+	Path string `yaml:"path"`
+	// ReloadInterval is the interval at which the rules file is checked
+	// for changes. The rules are reloaded without a restart.
+	ReloadInterval time.Duration `yaml:"reload_interval"`
+}
+
+// Enabled returns whether the calls should be rate limited.
+func (c *Config) Enabled() bool {
+	return len(c.Path) != 0
+}
+
+func (c *Config) getReloadInterval() time.Duration {
+	if c.ReloadInterval <= 0 {
+		return _defaultReloadInterval
+	}
+	return c.ReloadInterval
+}
+
+// Budget is the token bucket budget of the calls of a user on a
+// procedure and a respool.
+type Budget struct {
+	// Rate is the number of calls allowed per second. Calls are not
+	// limited if it is 0.
+	Rate float64 `yaml:"rate"`
+	// Burst is the number of calls allowed at once. It is the same as
+	// the rate if it is not set.
+	Burst float64 `yaml:"burst"`
+}
+
+// Rule overrides the default budget of the calls it matches.
+type Rule struct {
+	// Procedure matched by the rule, using the same Service:Method
+	// syntax as the auth rules, e.g.
+	// peloton.api.v1alpha.job.stateless.svc.JobService:Query*
+	Procedure string `yaml:"procedure"`
+	// Users matched by the rule. All the users are matched if it is empty.
+	Users []string `yaml:"users"`
+	// Respool matched by the rule, which is the absolute path of the
+	// respool. The respool IDs passed in the requests are resolved to
+	// their paths before matching. All the calls are matched if it is
+	// empty.
+	Respool string `yaml:"respool"`
+	// Budget of the calls matched by the rule
+	Budget `yaml:",inline"`
+}
+
+// Rules is the content of the rate limit rules file.
+type Rules struct {
+	// ExemptUsers are never rate limited, such as the internal user used
+	// for inter-component communication. The calls between peloton
+	// components are only exempt if their user is listed here. The calls
+	// without a user share the budget of the empty user.
+	ExemptUsers []string `yaml:"exempt_users"`
+	// Read is the default budget of the read-only calls
+	Read Budget `yaml:"read"`
+	// Write is the default budget of the mutating calls
+	Write Budget `yaml:"write"`
+	// Rules override the default budgets, the first matching rule wins
+	Rules []*Rule `yaml:"rules"`
+}
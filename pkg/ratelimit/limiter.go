@@ -0,0 +1,336 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uber/peloton/pkg/audit"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/uber-go/tally"
+	"go.uber.org/net/metrics"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	_matchAllRule       = "*"
+	_ruleSeparator      = ":"
+	_procedureSeparator = "::"
+	// need to replace the _procedureSeparator in metric tags, because it
+	// is reserved in m3 and tag can get dropped
+	_newProcedureSeparator = "__"
+)
+
+// bucketKey identifies the token bucket of the calls of a user on a
+// procedure and a respool.
+type bucketKey struct {
+	user      string
+	procedure string
+	respool   string
+}
+
+// Limiter rate limits the calls using token buckets keyed by user,
+// procedure and respool. The rules are loaded from a file, and reloaded
+// when the file changes.
+type Limiter struct {
+	// RWMutex protects the rules and the map of buckets, which are only
+	// replaced on reload. The calls only take the read lock, and the
+	// lock of their own bucket.
+	sync.RWMutex
+
+	path           string
+	reloadInterval time.Duration
+	modTime        time.Time
+
+	rules       *Rules
+	exemptUsers map[string]bool
+	// map of bucketKey to *bucket
+	buckets *sync.Map
+
+	scope      tally.Scope
+	reload     tally.Counter
+	reloadFail tally.Counter
+	numBuckets tally.Gauge
+
+	now      func() time.Time
+	stopChan chan struct{}
+}
+
+// NewLimiter returns a Limiter with the rules loaded from the file
+// configured in config.
+func NewLimiter(config *Config, scope tally.Scope) (*Limiter, error) {
+	l := &Limiter{
+		path:           config.Path,
+		reloadInterval: config.getReloadInterval(),
+		buckets:        &sync.Map{},
+		scope:          scope,
+		reload:         scope.Counter("reload"),
+		reloadFail:     scope.Counter("reload_fail"),
+		numBuckets:     scope.Gauge("buckets"),
+		now:            time.Now,
+	}
+	if err := l.Reload(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Allow takes a token from the bucket of the call, and returns false if
+// the call should be rejected.
+func (l *Limiter) Allow(user, procedure, respool string) bool {
+	l.RLock()
+	if l.exemptUsers[user] {
+		l.RUnlock()
+		return true
+	}
+	budget := l.budget(user, procedure, respool)
+	buckets := l.buckets
+	l.RUnlock()
+
+	if budget.Rate <= 0 {
+		return true
+	}
+
+	now := l.now()
+	key := bucketKey{user: user, procedure: procedure, respool: respool}
+	b, ok := buckets.Load(key)
+	if !ok {
+		b, _ = buckets.LoadOrStore(key, newBucket(budget, now))
+	}
+
+	if !b.(*bucket).take(now) {
+		l.scope.Tagged(metrics.Tags{
+			"procedure": strings.Replace(
+				procedure, _procedureSeparator, _newProcedureSeparator, 1),
+		}).Counter("throttled").Inc(1)
+		return false
+	}
+	return true
+}
+
+// MatchRespool returns whether any of the rules matches on respool, in
+// which case the respool of the calls need to be passed to Allow.
+func (l *Limiter) MatchRespool() bool {
+	l.RLock()
+	defer l.RUnlock()
+
+	for _, rule := range l.rules.Rules {
+		if len(rule.Respool) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// budget returns the budget of the first matching rule, or the default
+// read or write budget. The lock must be held by the caller.
+func (l *Limiter) budget(user, procedure, respool string) Budget {
+	for _, rule := range l.rules.Rules {
+		if matchRule(rule, user, procedure, respool) {
+			return rule.Budget
+		}
+	}
+	if audit.IsMutating(procedure) {
+		return l.rules.Write
+	}
+	return l.rules.Read
+}
+
+// Reload reloads the rules file if it has changed since it was last
+// loaded. The buckets are reset when the rules change. The current rules
+// are kept if the file cannot be loaded.
+func (l *Limiter) Reload() error {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		l.reloadFail.Inc(1)
+		return errors.Wrap(err, "failed to stat rate limit rules file")
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	if l.rules != nil && !info.ModTime().After(l.modTime) {
+		l.evictFullBuckets()
+		return nil
+	}
+
+	rules, err := loadRules(l.path)
+	if err != nil {
+		l.reloadFail.Inc(1)
+		return err
+	}
+
+	exemptUsers := make(map[string]bool)
+	for _, user := range rules.ExemptUsers {
+		exemptUsers[user] = true
+	}
+
+	l.rules = rules
+	l.exemptUsers = exemptUsers
+	l.modTime = info.ModTime()
+	l.buckets = &sync.Map{}
+	l.numBuckets.Update(0)
+	l.reload.Inc(1)
+	log.WithField("rules", rules).Info("Rate limit rules loaded")
+	return nil
+}
+
+// evictFullBuckets drops the full buckets, which are the same as new
+// buckets, so that the buckets of inactive users are not kept forever.
+// The lock must be held by the caller.
+func (l *Limiter) evictFullBuckets() {
+	now := l.now()
+	var count int
+	l.buckets.Range(func(key, b interface{}) bool {
+		if b.(*bucket).full(now) {
+			l.buckets.Delete(key)
+		} else {
+			count++
+		}
+		return true
+	})
+	l.numBuckets.Update(float64(count))
+}
+
+// Start starts reloading the rules file periodically
+func (l *Limiter) Start() {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.stopChan != nil {
+		return
+	}
+	l.stopChan = make(chan struct{})
+
+	go func(stopChan chan struct{}) {
+		ticker := time.NewTicker(l.reloadInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := l.Reload(); err != nil {
+					log.WithError(err).
+						WithField("path", l.path).
+						Warn("Failed to reload rate limit rules")
+				}
+			case <-stopChan:
+				return
+			}
+		}
+	}(l.stopChan)
+}
+
+// Stop stops reloading the rules file
+func (l *Limiter) Stop() {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.stopChan == nil {
+		return
+	}
+	close(l.stopChan)
+	l.stopChan = nil
+}
+
+func loadRules(path string) (*Rules, error) {
+	buffer, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read rate limit rules file")
+	}
+
+	rules := &Rules{}
+	if err := yaml.Unmarshal(buffer, rules); err != nil {
+		return nil, errors.Wrap(err, "failed to parse rate limit rules file")
+	}
+
+	for _, rule := range rules.Rules {
+		if err := validateProcedureRule(rule.Procedure); err != nil {
+			return nil, err
+		}
+		if err := validateRespoolRule(rule.Respool); err != nil {
+			return nil, err
+		}
+		rule.Respool = normalizeRespoolPath(rule.Respool)
+	}
+	return rules, nil
+}
+
+func validateProcedureRule(rule string) error {
+	if rule == _matchAllRule {
+		return nil
+	}
+	if len(strings.Split(rule, _ruleSeparator)) != 2 {
+		return errors.Errorf("invalid procedure rule %q, expect Service:Method", rule)
+	}
+	return nil
+}
+
+func validateRespoolRule(rule string) error {
+	if len(rule) != 0 && !isRespoolPath(rule) {
+		return errors.Errorf("invalid respool rule %q, expect respool path", rule)
+	}
+	return nil
+}
+
+func matchRule(rule *Rule, user, procedure, respool string) bool {
+	if len(rule.Respool) != 0 && rule.Respool != respool {
+		return false
+	}
+
+	if len(rule.Users) != 0 {
+		found := false
+		for _, u := range rule.Users {
+			if u == user {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return matchProcedure(rule.Procedure, procedure)
+}
+
+// matchProcedure matches a procedure Service::Method against a rule
+// Service:Method, where a trailing * matches any suffix.
+func matchProcedure(rule, procedure string) bool {
+	if rule == _matchAllRule {
+		return true
+	}
+
+	ruleParts := strings.SplitN(rule, _ruleSeparator, 2)
+	procedureParts := strings.SplitN(procedure, _procedureSeparator, 2)
+	if len(ruleParts) != 2 || len(procedureParts) != 2 {
+		return false
+	}
+
+	return matchName(ruleParts[0], procedureParts[0]) &&
+		matchName(ruleParts[1], procedureParts[1])
+}
+
+func matchName(rule, name string) bool {
+	if strings.HasSuffix(rule, _matchAllRule) {
+		return strings.HasPrefix(name, strings.TrimSuffix(rule, _matchAllRule))
+	}
+	return rule == name
+}
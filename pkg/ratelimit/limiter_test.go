@@ -0,0 +1,260 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+)
+
+const (
+	_queryJobsProcedure = "peloton.api.v1alpha.job.stateless.svc.JobService::QueryJobs"
+	_getJobProcedure    = "peloton.api.v1alpha.job.stateless.svc.JobService::GetJob"
+	_createJobProcedure = "peloton.api.v1alpha.job.stateless.svc.JobService::CreateJob"
+
+	_testRules = `
+exempt_users:
+- peloton
+read:
+  rate: 2
+  burst: 2
+write:
+  rate: 1
+rules:
+- procedure: 'peloton.api.v1alpha.job.stateless.svc.JobService:Query*'
+  rate: 1
+  burst: 1
+- procedure: 'peloton.api.v1alpha.job.stateless.svc.JobService:CreateJob'
+  respool: /batch
+  rate: 3
+  burst: 3
+- procedure: '*'
+  users:
+  - unlimited
+  rate: 0
+`
+)
+
+type LimiterTestSuite struct {
+	suite.Suite
+
+	dir   string
+	path  string
+	now   time.Time
+	scope tally.TestScope
+	l     *Limiter
+}
+
+func (suite *LimiterTestSuite) SetupTest() {
+	var err error
+	suite.dir, err = ioutil.TempDir("", "ratelimit")
+	suite.NoError(err)
+	suite.path = filepath.Join(suite.dir, "rules.yaml")
+	suite.writeRules(_testRules)
+
+	suite.scope = tally.NewTestScope("", nil)
+	suite.l, err = NewLimiter(&Config{Path: suite.path}, suite.scope)
+	suite.NoError(err)
+
+	suite.now = time.Now()
+	suite.l.now = func() time.Time { return suite.now }
+}
+
+func (suite *LimiterTestSuite) TearDownTest() {
+	suite.l.Stop()
+	os.RemoveAll(suite.dir)
+}
+
+func (suite *LimiterTestSuite) writeRules(rules string) {
+	suite.NoError(ioutil.WriteFile(suite.path, []byte(rules), 0600))
+}
+
+func (suite *LimiterTestSuite) numBuckets() int {
+	var count int
+	suite.l.buckets.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// TestDefaultBudgets tests the default read and write budgets
+func (suite *LimiterTestSuite) TestDefaultBudgets() {
+	suite.True(suite.l.Allow("alice", _getJobProcedure, ""))
+	suite.True(suite.l.Allow("alice", _getJobProcedure, ""))
+	suite.False(suite.l.Allow("alice", _getJobProcedure, ""))
+
+	// buckets are per user
+	suite.True(suite.l.Allow("bob", _getJobProcedure, ""))
+
+	// write calls have a separate budget
+	suite.True(suite.l.Allow("alice", _createJobProcedure, ""))
+	suite.False(suite.l.Allow("alice", _createJobProcedure, ""))
+
+	// tokens are refilled over time
+	suite.now = suite.now.Add(time.Second)
+	suite.True(suite.l.Allow("alice", _getJobProcedure, ""))
+	suite.True(suite.l.Allow("alice", _createJobProcedure, ""))
+
+	suite.Equal(
+		int64(2),
+		suite.scope.Snapshot().Counters()["throttled+procedure=peloton.api.v1alpha.job.stateless.svc.JobService__GetJob"].Value()+
+			suite.scope.Snapshot().Counters()["throttled+procedure=peloton.api.v1alpha.job.stateless.svc.JobService__CreateJob"].Value(),
+	)
+}
+
+// TestRules tests the rules overriding the default budgets
+func (suite *LimiterTestSuite) TestRules() {
+	suite.True(suite.l.Allow("alice", _queryJobsProcedure, ""))
+	suite.False(suite.l.Allow("alice", _queryJobsProcedure, ""))
+
+	// respool rule
+	suite.True(suite.l.MatchRespool())
+	for i := 0; i < 3; i++ {
+		suite.True(suite.l.Allow("alice", _createJobProcedure, "/batch"))
+	}
+	suite.False(suite.l.Allow("alice", _createJobProcedure, "/batch"))
+	suite.True(suite.l.Allow("alice", _createJobProcedure, "/batch/child"))
+
+	// rate 0 is unlimited
+	for i := 0; i < 10; i++ {
+		suite.True(suite.l.Allow("unlimited", _getJobProcedure, ""))
+	}
+
+	// exempt users are never limited
+	for i := 0; i < 10; i++ {
+		suite.True(suite.l.Allow("peloton", _queryJobsProcedure, ""))
+	}
+}
+
+// TestReload tests reloading the rules when the file changes
+func (suite *LimiterTestSuite) TestReload() {
+	suite.True(suite.l.Allow("alice", _queryJobsProcedure, ""))
+	suite.False(suite.l.Allow("alice", _queryJobsProcedure, ""))
+
+	// file not changed, buckets are kept
+	suite.NoError(suite.l.Reload())
+	suite.False(suite.l.Allow("alice", _queryJobsProcedure, ""))
+
+	suite.writeRules(`
+read:
+  rate: 5
+`)
+	future := time.Now().Add(time.Minute)
+	suite.NoError(os.Chtimes(suite.path, future, future))
+	suite.NoError(suite.l.Reload())
+	suite.False(suite.l.MatchRespool())
+	for i := 0; i < 5; i++ {
+		suite.True(suite.l.Allow("alice", _queryJobsProcedure, ""))
+	}
+	suite.False(suite.l.Allow("alice", _queryJobsProcedure, ""))
+
+	// invalid rules are not loaded
+	suite.writeRules(`
+rules:
+- procedure: invalid
+  rate: 1
+`)
+	future = future.Add(time.Minute)
+	suite.NoError(os.Chtimes(suite.path, future, future))
+	suite.Error(suite.l.Reload())
+	suite.False(suite.l.Allow("alice", _queryJobsProcedure, ""))
+	suite.Equal(int64(1), suite.scope.Snapshot().Counters()["reload_fail+"].Value())
+}
+
+// TestEvictFullBuckets tests the full buckets are dropped on reload
+func (suite *LimiterTestSuite) TestEvictFullBuckets() {
+	suite.True(suite.l.Allow("alice", _getJobProcedure, ""))
+	suite.True(suite.l.Allow("bob", _queryJobsProcedure, ""))
+	suite.Equal(2, suite.numBuckets())
+
+	suite.now = suite.now.Add(time.Second)
+	suite.NoError(suite.l.Reload())
+	suite.Equal(0, suite.numBuckets())
+}
+
+// TestAllowConcurrent tests that the concurrent calls of a user share
+// their bucket
+func (suite *LimiterTestSuite) TestAllowConcurrent() {
+	var wg sync.WaitGroup
+	var allowed int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if suite.l.Allow("alice", _getJobProcedure, "") {
+				atomic.AddInt32(&allowed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	suite.Equal(int32(2), allowed)
+}
+
+// TestStartStop tests starting and stopping the reload loop
+func (suite *LimiterTestSuite) TestStartStop() {
+	suite.l.Start()
+	suite.l.Start()
+	suite.l.Stop()
+	suite.l.Stop()
+}
+
+// TestNewLimiterFailure tests creating a limiter with an invalid file
+func (suite *LimiterTestSuite) TestNewLimiterFailure() {
+	_, err := NewLimiter(&Config{Path: filepath.Join(suite.dir, "none")}, suite.scope)
+	suite.Error(err)
+
+	suite.writeRules("invalid")
+	_, err = NewLimiter(&Config{Path: suite.path}, suite.scope)
+	suite.Error(err)
+
+	// respool rules must be respool paths
+	suite.writeRules(`
+rules:
+- procedure: '*'
+  respool: respool1
+  rate: 1
+`)
+	_, err = NewLimiter(&Config{Path: suite.path}, suite.scope)
+	suite.Error(err)
+}
+
+func TestLimiterSuite(t *testing.T) {
+	suite.Run(t, new(LimiterTestSuite))
+}
+
+func TestMatchProcedure(t *testing.T) {
+	assert.True(t, matchProcedure("*", _getJobProcedure))
+	assert.True(t, matchProcedure(
+		"peloton.api.v1alpha.job.stateless.svc.JobService:*", _getJobProcedure))
+	assert.True(t, matchProcedure(
+		"peloton.api.v1alpha.job.stateless.svc.JobService:Get*", _getJobProcedure))
+	assert.True(t, matchProcedure(
+		"peloton.api.v1alpha.*:GetJob", _getJobProcedure))
+	assert.False(t, matchProcedure(
+		"peloton.api.v1alpha.job.stateless.svc.JobService:Query*", _getJobProcedure))
+	assert.False(t, matchProcedure(
+		"peloton.api.v1alpha.pod.svc.PodService:*", _getJobProcedure))
+	assert.False(t, matchProcedure("invalid", _getJobProcedure))
+}
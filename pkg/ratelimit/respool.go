@@ -0,0 +1,139 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// _respoolPathSeparator is the separator of the respool paths, which
+// are absolute
+const _respoolPathSeparator = "/"
+
+// RespoolResolver resolves the ID of a respool to its path, so that the
+// calls passing the ID or the path of a respool match the same rules.
+type RespoolResolver interface {
+	// GetRespoolPath returns the path of the respool with the ID
+	GetRespoolPath(ctx context.Context, respoolID string) (string, error)
+}
+
+// _respoolGetters are the getters of the respool ID or path in the
+// requests, or in the job spec or job config of the requests.
+var _respoolGetters = []string{
+	"GetRespoolId",
+	"GetRespoolID",
+	"GetRespool",
+}
+
+// _specGetters are the getters of the job spec or job config in the
+// requests, which carry the respool of the job.
+var _specGetters = []string{
+	"GetSpec",
+	"GetConfig",
+}
+
+// CanonicalRespool returns the path of the respool passed in a request,
+// which is either the ID or the path of the respool. The ID is resolved
+// to the path with resolver.
+func CanonicalRespool(
+	ctx context.Context,
+	respool string,
+	resolver RespoolResolver,
+) (string, error) {
+	if len(respool) == 0 || isRespoolPath(respool) {
+		return normalizeRespoolPath(respool), nil
+	}
+	if resolver == nil {
+		return "", errors.Errorf("no resolver for respool %s", respool)
+	}
+
+	path, err := resolver.GetRespoolPath(ctx, respool)
+	if err != nil {
+		return "", err
+	}
+	return normalizeRespoolPath(path), nil
+}
+
+// isRespoolPath returns whether respool is a path rather than an ID
+func isRespoolPath(respool string) bool {
+	return strings.HasPrefix(respool, _respoolPathSeparator)
+}
+
+// normalizeRespoolPath drops the trailing separator of a respool path
+func normalizeRespoolPath(path string) string {
+	if path == _respoolPathSeparator {
+		return path
+	}
+	return strings.TrimSuffix(path, _respoolPathSeparator)
+}
+
+// valueGetter is implemented by the respool ID and respool path.
+type valueGetter interface {
+	GetValue() string
+}
+
+// RequestRespool returns the respool ID or path passed in a request,
+// or an empty string if the request does not carry a respool.
+func RequestRespool(msg proto.Message) string {
+	if msg == nil {
+		return ""
+	}
+
+	v := reflect.ValueOf(msg)
+	if respool := respoolValue(v); len(respool) != 0 {
+		return respool
+	}
+	for _, getter := range _specGetters {
+		if spec, ok := call(v, getter); ok {
+			if respool := respoolValue(spec); len(respool) != 0 {
+				return respool
+			}
+		}
+	}
+	return ""
+}
+
+func respoolValue(v reflect.Value) string {
+	for _, getter := range _respoolGetters {
+		result, ok := call(v, getter)
+		if !ok || !result.CanInterface() {
+			continue
+		}
+		// the generated getters are safe to call on nil messages
+		if g, ok := result.Interface().(valueGetter); ok {
+			if value := g.GetValue(); len(value) != 0 {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// call calls a getter of a message
+func call(v reflect.Value, getter string) (reflect.Value, bool) {
+	if !v.IsValid() {
+		return reflect.Value{}, false
+	}
+	m := v.MethodByName(getter)
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return reflect.Value{}, false
+	}
+	return m.Call(nil)[0], true
+}
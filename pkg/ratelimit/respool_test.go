@@ -0,0 +1,100 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless"
+	statelesssvc "github.com/uber/peloton/.gen/peloton/api/v1alpha/job/stateless/svc"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+	v1alpharespool "github.com/uber/peloton/.gen/peloton/api/v1alpha/respool"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRespoolResolver map[string]string
+
+func (r testRespoolResolver) GetRespoolPath(
+	_ context.Context,
+	respoolID string,
+) (string, error) {
+	path, ok := r[respoolID]
+	if !ok {
+		return "", errors.New("respool not found")
+	}
+	return path, nil
+}
+
+func TestCanonicalRespool(t *testing.T) {
+	ctx := context.Background()
+	resolver := testRespoolResolver{"respool1": "/batch/"}
+
+	// respool IDs are resolved to their paths
+	path, err := CanonicalRespool(ctx, "respool1", resolver)
+	assert.NoError(t, err)
+	assert.Equal(t, "/batch", path)
+
+	// respool paths are only normalized
+	path, err = CanonicalRespool(ctx, "/batch/", resolver)
+	assert.NoError(t, err)
+	assert.Equal(t, "/batch", path)
+	path, err = CanonicalRespool(ctx, "/", resolver)
+	assert.NoError(t, err)
+	assert.Equal(t, "/", path)
+
+	// no respool
+	path, err = CanonicalRespool(ctx, "", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+
+	// respool IDs which cannot be resolved
+	_, err = CanonicalRespool(ctx, "respool2", resolver)
+	assert.Error(t, err)
+	_, err = CanonicalRespool(ctx, "respool1", nil)
+	assert.Error(t, err)
+}
+
+func TestRequestRespool(t *testing.T) {
+	// respool ID in the job spec
+	assert.Equal(t, "respool1", RequestRespool(&statelesssvc.CreateJobRequest{
+		Spec: &stateless.JobSpec{
+			RespoolId: &v1alphapeloton.ResourcePoolID{Value: "respool1"},
+		},
+	}))
+
+	// respool ID in the v0 job config
+	assert.Equal(t, "respool2", RequestRespool(&job.CreateRequest{
+		Config: &job.JobConfig{
+			RespoolID: &peloton.ResourcePoolID{Value: "respool2"},
+		},
+	}))
+
+	// respool path in the query spec
+	assert.Equal(t, "/batch", RequestRespool(&statelesssvc.QueryJobsRequest{
+		Spec: &stateless.QuerySpec{
+			Respool: &v1alpharespool.ResourcePoolPath{Value: "/batch"},
+		},
+	}))
+
+	// no respool
+	assert.Empty(t, RequestRespool(&statelesssvc.GetJobRequest{}))
+	assert.Empty(t, RequestRespool(&statelesssvc.CreateJobRequest{}))
+	assert.Empty(t, RequestRespool(nil))
+}
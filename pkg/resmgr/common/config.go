@@ -30,6 +30,26 @@ type PreemptionConfig struct {
 	// If the value exceeds this number then the preemption logic will kick
 	// in to reduce the allocation.
 	SustainedOverAllocationCount int `yaml:"sustained_over_allocation_count"`
+
+	// Config for preempting lower priority tasks on the hosts reserved
+	// for higher priority tasks, irrespective of their resource pools.
+	PriorityPreemption PriorityPreemptionConfig `yaml:"priority_preemption"`
+}
+
+// PriorityPreemptionConfig is the container for priority based preemption
+// related config
+type PriorityPreemptionConfig struct {
+	// Boolean value to represent if priority preemption is enabled
+	Enabled bool
+
+	// Minimum priority of a task for which lower priority tasks are
+	// preempted on the host reserved for it.
+	MinPriority uint32 `yaml:"min_priority"`
+
+	// Maximum number of instances of a job which can be in the preemption
+	// queue at the same time due to priority preemption. If not set, only
+	// one instance per job is preempted at a time.
+	MaxPreemptedInstancesPerJob uint32 `yaml:"max_preempted_instances_per_job"`
 }
 
 // RecoveryConfig is the container for recovery related config
@@ -38,3 +58,26 @@ type RecoveryConfig struct {
 	// table for recovery instead of materialized view
 	RecoverFromActiveJobs bool `yaml:"recover_from_active_jobs"`
 }
+
+// ForecastConfig is the container for capacity forecasting related config
+type ForecastConfig struct {
+	// Period to sample the cluster capacity and the reservation,
+	// allocation and demand of the resource pools.
+	SamplingPeriod time.Duration `yaml:"sampling_period"`
+
+	// Duration of the history kept to forecast the capacity usage.
+	Window time.Duration `yaml:"window"`
+
+	// Period to persist the samples of the resource pools, so that the
+	// history is downsampled in the storage and survives a failover.
+	PersistPeriod time.Duration `yaml:"persist_period"`
+
+	// Fraction of the cluster capacity below which the headroom of a
+	// resource raises an alert. No alert is raised if it is 0.
+	HeadroomThreshold float64 `yaml:"headroom_threshold"`
+
+	// Duration below which the estimated time until the reservations
+	// exceed the cluster capacity raises an alert. No alert is raised
+	// if it is 0.
+	ExhaustionThreshold time.Duration `yaml:"exhaustion_threshold"`
+}
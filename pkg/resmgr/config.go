@@ -15,6 +15,7 @@
 package resmgr
 
 import (
+	"errors"
 	"time"
 
 	"github.com/uber/peloton/pkg/resmgr/common"
@@ -49,4 +50,20 @@ type Config struct {
 
 	// RecoveryConfig to recover jobs on resmgr restart
 	RecoveryConfig *common.RecoveryConfig `yaml:"recovery"`
+
+	// Config for capacity forecasting
+	ForecastConfig *common.ForecastConfig `yaml:"forecast"`
+}
+
+// Validate validates the Resource Manager config
+func (c *Config) Validate() error {
+	// priority preemption only frees up the hosts reserved for tasks, so
+	// it can't do anything without host reservation
+	if c.PreemptionConfig != nil &&
+		c.PreemptionConfig.PriorityPreemption.Enabled &&
+		(c.RmTaskConfig == nil || !c.RmTaskConfig.EnableHostReservation) {
+		return errors.New("priority_preemption requires " +
+			"task.enable_host_reservation to be enabled")
+	}
+	return nil
 }
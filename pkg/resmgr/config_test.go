@@ -38,6 +38,16 @@ const testConfig = `
     task_preemption_period: 60s
     sustained_over_allocation_count: 5
     enabled: true
+    priority_preemption:
+      enabled: true
+      min_priority: 100
+      max_preempted_instances_per_job: 2
+  forecast:
+    sampling_period: 60s
+    window: 24h
+    persist_period: 10m
+    headroom_threshold: 0.1
+    exhaustion_threshold: 168h
 `
 
 func writeFile(t *testing.T, contents string) string {
@@ -67,4 +77,32 @@ func TestResourceManagerConfig(t *testing.T) {
 	assert.Equal(t, 1*time.Minute, testConfig.PreemptionConfig.TaskPreemptionPeriod)
 	assert.Equal(t, 5, testConfig.PreemptionConfig.SustainedOverAllocationCount)
 	assert.Equal(t, true, testConfig.PreemptionConfig.Enabled)
+	assert.Equal(t, true, testConfig.PreemptionConfig.PriorityPreemption.Enabled)
+	assert.Equal(t, uint32(100),
+		testConfig.PreemptionConfig.PriorityPreemption.MinPriority)
+	assert.Equal(t, uint32(2),
+		testConfig.PreemptionConfig.PriorityPreemption.MaxPreemptedInstancesPerJob)
+	assert.Equal(t, time.Minute, testConfig.ForecastConfig.SamplingPeriod)
+	assert.Equal(t, 24*time.Hour, testConfig.ForecastConfig.Window)
+	assert.Equal(t, 10*time.Minute, testConfig.ForecastConfig.PersistPeriod)
+	assert.Equal(t, 0.1, testConfig.ForecastConfig.HeadroomThreshold)
+	assert.Equal(t, 168*time.Hour, testConfig.ForecastConfig.ExhaustionThreshold)
+}
+
+func TestResourceManagerConfigValidate(t *testing.T) {
+	cfgFile := writeFile(t, testConfig)
+	defer os.Remove(cfgFile)
+	var testConfig Config
+	err := config.Parse(&testConfig, cfgFile)
+	assert.NoError(t, err)
+
+	// priority preemption requires host reservation
+	assert.Error(t, testConfig.Validate())
+
+	testConfig.RmTaskConfig.EnableHostReservation = true
+	assert.NoError(t, testConfig.Validate())
+
+	testConfig.RmTaskConfig.EnableHostReservation = false
+	testConfig.PreemptionConfig.PriorityPreemption.Enabled = false
+	assert.NoError(t, testConfig.Validate())
 }
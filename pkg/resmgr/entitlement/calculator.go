@@ -30,6 +30,7 @@ import (
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 
 	"github.com/uber/peloton/pkg/common"
+	"github.com/uber/peloton/pkg/common/gpu"
 	"github.com/uber/peloton/pkg/common/util"
 	res_common "github.com/uber/peloton/pkg/resmgr/common"
 	"github.com/uber/peloton/pkg/resmgr/respool"
@@ -40,6 +41,9 @@ import (
 // leaf resource pools based on the demand, free resources and share.
 type Calculator struct {
 	lock sync.Mutex
+	// calculationLock serializes the distribution of the entitlement between
+	// the periodic calculation and the recalculation after a tree change
+	calculationLock sync.Mutex
 
 	// stores the current state of the calculation
 	runningState int32
@@ -122,6 +126,13 @@ func (c *Calculator) Start() error {
 	return nil
 }
 
+// RunOnce runs a single entitlement calculation cycle synchronously. It is
+// meant for callers which drive the calculation themselves rather than
+// relying on the periodic calculation started by Start.
+func (c *Calculator) RunOnce(ctx context.Context) error {
+	return c.calculateEntitlement(ctx)
+}
+
 // calculateEntitlement runs one entitlement calculation cycle.
 func (c *Calculator) calculateEntitlement(ctx context.Context) error {
 	log.Info("calculating entitlement")
@@ -146,10 +157,48 @@ func (c *Calculator) calculateEntitlement(ctx context.Context) error {
 		return errors.Wrapf(err, "failed to get root resource pool")
 	}
 
+	// Calling the hostmgr for getting total capacity of the cluster
+	totalResources, slackTotalResources, err := c.getTotalCapacity(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get total cluster capacity")
+	}
+
+	c.calculationLock.Lock()
+	defer c.calculationLock.Unlock()
+
 	// Updating cluster capacity
-	if err = c.updateClusterCapacity(ctx, rootResPool); err != nil {
+	if err = c.updateClusterCapacity(
+		rootResPool,
+		totalResources,
+		slackTotalResources); err != nil {
 		return errors.Wrapf(err, "failed to update cluster capacity")
 	}
+	c.distributeEntitlement(rootResPool)
+	return nil
+}
+
+// Recalculate redistributes the entitlement of the resource pool tree rooted
+// at the provided resource pool, using the cluster capacity of the last
+// calculation. It is called by the resource pool tree with the tree lock held
+// after the hierarchy has changed, so it must not call back into the tree.
+func (c *Calculator) Recalculate(rootResPool respool.ResPool) {
+	c.calculationLock.Lock()
+	defer c.calculationLock.Unlock()
+
+	if len(c.clusterCapacity) == 0 {
+		// the cluster capacity is not known yet, the periodic calculation
+		// will distribute the entitlement
+		return
+	}
+
+	defer c.metrics.calculationDuration.Start().Stop()
+	c.distributeEntitlement(rootResPool)
+}
+
+// distributeEntitlement calculates the demand and allocation of the resource
+// pool tree and distributes the entitlement of the root resource pool to its
+// descendants. The caller must hold the calculation lock.
+func (c *Calculator) distributeEntitlement(rootResPool respool.ResPool) {
 	// Invoking the demand calculation
 	rootResPool.CalculateDemand()
 	// Invoking the slack demand calculation
@@ -162,8 +211,6 @@ func (c *Calculator) calculateEntitlement(ctx context.Context) error {
 	// set Slack and Non-Slack Entitlement for root respool's children
 	// based on the previous entitlement calculation
 	c.setSlackAndNonSlackEntitlementForChildren(rootResPool)
-
-	return nil
 }
 
 // getChildShare returns the combined share of all the children of the provided
@@ -178,7 +225,7 @@ func (c *Calculator) getChildShare(resp respool.ResPool, kind string) float64 {
 	totalShare := float64(0)
 	for e := children.Front(); e != nil; e = e.Next() {
 		n := e.Value.(respool.ResPool)
-		totalShare += n.Resources()[kind].Share
+		totalShare += n.Resources()[kind].GetShare()
 	}
 	return totalShare
 }
@@ -195,15 +242,13 @@ func (c *Calculator) demandExist(
 	return false
 }
 
+// updateClusterCapacity sets the cluster capacity as the resources and the
+// entitlement of the root resource pool. The caller must hold the calculation
+// lock.
 func (c *Calculator) updateClusterCapacity(
-	ctx context.Context,
-	rootResPool respool.ResPool) error {
-	// Calling the hostmgr for getting total capacity of the cluster
-	totalResources, slackTotalResources, err := c.getTotalCapacity(ctx)
-	if err != nil {
-		return errors.Wrapf(err, "failed to get total cluster capacity")
-	}
-
+	rootResPool respool.ResPool,
+	totalResources []*hostsvc.Resource,
+	slackTotalResources []*hostsvc.Resource) error {
 	rootResourcePoolConfig := rootResPool.ResourcePoolConfig()
 	if rootResourcePoolConfig == nil {
 		log.Error("root resource pool have invalid config")
@@ -248,25 +293,70 @@ func (c *Calculator) updateClusterCapacity(
 				Reservation: c.clusterCapacity[common.MEMORY],
 				Limit:       c.clusterCapacity[common.MEMORY],
 			},
+			{
+				Kind:        common.PORTS,
+				Reservation: c.clusterCapacity[common.PORTS],
+				Limit:       c.clusterCapacity[common.PORTS],
+			},
+			{
+				Kind:        common.NETWORK,
+				Reservation: c.clusterCapacity[common.NETWORK],
+				Limit:       c.clusterCapacity[common.NETWORK],
+			},
 		}
 		rootResourcePoolConfig.Resources = rootres
 	} else {
 		// update the reservation and limit to the cluster capacity
+		configured := make(map[string]bool)
 		for _, resource := range rootres {
 			resource.Reservation =
 				c.clusterCapacity[resource.Kind]
 			resource.Limit =
 				c.clusterCapacity[resource.Kind]
+			configured[resource.Kind] = true
 		}
+		// the root resource pool always configures the optional kinds, so
+		// that its children can configure them as well
+		for _, kind := range scalar.OptionalKinds {
+			if configured[kind] {
+				continue
+			}
+			rootres = append(rootres, &pb_res.ResourceConfig{
+				Kind:        kind,
+				Reservation: c.clusterCapacity[kind],
+				Limit:       c.clusterCapacity[kind],
+			})
+		}
+		rootResourcePoolConfig.Resources = rootres
+	}
+
+	// the root resource pool configures the gpu model kinds of the cluster,
+	// so that its children can configure them as well
+	rootKinds := make(map[string]bool)
+	for _, resource := range rootres {
+		rootKinds[resource.Kind] = true
+	}
+	for _, res := range totalResources {
+		if _, ok := gpu.ModelFromKind(res.Kind); !ok || rootKinds[res.Kind] {
+			continue
+		}
+		rootres = append(rootres, &pb_res.ResourceConfig{
+			Kind:        res.Kind,
+			Reservation: res.Capacity,
+			Limit:       res.Capacity,
+		})
 	}
+	rootResourcePoolConfig.Resources = rootres
 
 	rootResPool.SetResourcePoolConfig(rootResourcePoolConfig)
 	rootResPool.SetEntitlement(
 		&scalar.Resources{
-			CPU:    c.clusterCapacity[common.CPU],
-			MEMORY: c.clusterCapacity[common.MEMORY],
-			DISK:   c.clusterCapacity[common.DISK],
-			GPU:    c.clusterCapacity[common.GPU],
+			CPU:     c.clusterCapacity[common.CPU],
+			MEMORY:  c.clusterCapacity[common.MEMORY],
+			DISK:    c.clusterCapacity[common.DISK],
+			GPU:     c.clusterCapacity[common.GPU],
+			PORTS:   c.clusterCapacity[common.PORTS],
+			NETWORK: c.clusterCapacity[common.NETWORK],
 		})
 	rootResPool.SetSlackEntitlement(
 		&scalar.Resources{
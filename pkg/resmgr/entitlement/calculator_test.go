@@ -310,6 +310,53 @@ func (s *EntitlementCalculatorTestSuite) getStaticResPools() map[string]*pb_resp
 	}
 }
 
+// TestRecalculate tests that the entitlement is redistributed with the last
+// known cluster capacity, without calling the host manager
+func (s *EntitlementCalculatorTestSuite) TestRecalculate() {
+	mockHostMgr := host_mocks.NewMockInternalHostServiceYARPCClient(s.mockCtrl)
+	mockHostMgr.EXPECT().
+		ClusterCapacity(
+			gomock.Any(),
+			gomock.Any()).
+		Return(&hostsvc.ClusterCapacityResponse{
+			PhysicalResources:      s.createClusterCapacity(),
+			PhysicalSlackResources: s.createSlackClusterCapacity(),
+		}, nil).
+		Times(1)
+	s.calculator.hostMgrClient = mockHostMgr
+
+	root, err := s.resTree.Get(&peloton.ResourcePoolID{Value: common.RootResPoolID})
+	s.NoError(err)
+	resPool, err := s.resTree.Get(&peloton.ResourcePoolID{Value: "respool11"})
+	s.NoError(err)
+	demand := &scalar.Resources{
+		CPU:    20,
+		MEMORY: 200,
+		DISK:   2000,
+		GPU:    0,
+	}
+	resPool.AddToDemand(demand)
+
+	// the cluster capacity is not known yet
+	s.calculator.Recalculate(root)
+	s.True(tasktestutil.ValidateResources(resPool.GetEntitlement(),
+		map[string]int64{"CPU": 0, "GPU": 0, "MEMORY": 0, "DISK": 0}))
+
+	s.NoError(s.calculator.calculateEntitlement(context.Background()))
+	s.True(tasktestutil.ValidateResources(resPool.GetEntitlement(),
+		map[string]int64{"CPU": 33, "GPU": 0, "MEMORY": 333, "DISK": 1000}))
+
+	resPool21, err := s.resTree.Get(&peloton.ResourcePoolID{Value: "respool21"})
+	s.NoError(err)
+	resPool21.AddToDemand(demand)
+
+	s.calculator.Recalculate(root)
+	s.True(tasktestutil.ValidateResources(resPool.GetEntitlement(),
+		map[string]int64{"CPU": 30, "GPU": 0, "MEMORY": 300, "DISK": 1000}))
+	s.True(tasktestutil.ValidateResources(resPool21.GetEntitlement(),
+		map[string]int64{"CPU": 30, "GPU": 0, "MEMORY": 300, "DISK": 1000}))
+}
+
 func (s *EntitlementCalculatorTestSuite) TestEntitlement() {
 	// Mock LaunchTasks call.
 	mockHostMgr := host_mocks.NewMockInternalHostServiceYARPCClient(s.mockCtrl)
@@ -721,6 +768,10 @@ func (s *EntitlementCalculatorTestSuite) TestUpdateCapacity() {
 						Kind:     common.DISK,
 						Capacity: 6000,
 					},
+					{
+						Kind:     "gpu.tesla-v100",
+						Capacity: 8,
+					},
 				},
 			}, nil).
 			Times(1),
@@ -759,6 +810,9 @@ func (s *EntitlementCalculatorTestSuite) TestUpdateCapacity() {
 	s.Equal(RootResPool.Resources()[common.GPU].Limit, float64(10))
 	s.Equal(RootResPool.Resources()[common.MEMORY].Limit, float64(1000))
 	s.Equal(RootResPool.Resources()[common.DISK].Limit, float64(6000))
+	// the gpu model kinds of the cluster are configured for the root
+	s.Equal(RootResPool.Resources()["gpu.tesla-v100"].Reservation, float64(8))
+	s.Equal(RootResPool.Resources()["gpu.tesla-v100"].Limit, float64(8))
 }
 
 func (s *EntitlementCalculatorTestSuite) TestEntitlementWithMoreDemand() {
@@ -219,7 +219,9 @@ func (c *Calculator) distributeRemainingResources(
 		common.CPU,
 		common.GPU,
 		common.MEMORY,
-		common.DISK} {
+		common.DISK,
+		common.PORTS,
+		common.NETWORK} {
 		remaining := *entitlement
 		log.WithFields(log.Fields{
 			"kind":       kind,
@@ -251,7 +253,7 @@ func (c *Calculator) distributeRemainingResources(
 					continue
 				}
 
-				value := float64(n.Resources()[kind].Share * entitlement.Get(kind))
+				value := float64(n.Resources()[kind].GetShare() * entitlement.Get(kind))
 				value = float64(value / totalShare[kind])
 				log.WithField("value", value).Debug(" value to evaluate ")
 
@@ -311,7 +313,9 @@ func (c *Calculator) distributeUnclaimedResources(
 		common.CPU,
 		common.GPU,
 		common.MEMORY,
-		common.DISK} {
+		common.DISK,
+		common.PORTS,
+		common.NETWORK} {
 		// Third pass : Now all the demand is been satisfied
 		// we need to distribute the rest of the entitlement
 		// to all the nodes for the anticipation of some work
@@ -320,6 +324,11 @@ func (c *Calculator) distributeUnclaimedResources(
 			totalChildShare := c.getChildShare(resp, kind)
 			for e := childs.Front(); e != nil; e = e.Next() {
 				n := e.Value.(respool.ResPool)
+				// optional resource kinds are not distributed to the
+				// resource pools which don't configure them
+				if _, ok := n.Resources()[kind]; !ok {
+					continue
+				}
 				nshare := n.Resources()[kind].Share
 				value := assignments[n.ID()].Get(kind)
 				if nshare > 0 {
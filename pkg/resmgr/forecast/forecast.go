@@ -0,0 +1,123 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forecast
+
+import (
+	"sort"
+
+	pb_respool "github.com/uber/peloton/.gen/peloton/api/v0/respool"
+
+	"github.com/uber/peloton/pkg/common"
+)
+
+// forecastAll returns the forecast of all the resource kinds of a
+// resource pool from its samples, sorted by resource kind
+func forecastAll(
+	id string,
+	samples []*sample) []*pb_respool.CapacityForecast {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	latest := samples[len(samples)-1]
+	kinds := make([]string, 0, len(latest.usages))
+	for kind := range latest.usages {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	var forecasts []*pb_respool.CapacityForecast
+	for _, kind := range kinds {
+		forecasts = append(forecasts, forecastKind(id, kind, samples))
+	}
+	return forecasts
+}
+
+// forecastKind returns the forecast of a resource kind. The usage of the
+// cluster is the reservation of the resource pools, while the usage of a
+// resource pool is its allocation and demand. The growth of the usage is
+// the least squares slope of the headroom over the samples.
+func forecastKind(
+	id string,
+	kind string,
+	samples []*sample) *pb_respool.CapacityForecast {
+	var xs, ys []float64
+	start := samples[0].time
+	for _, s := range samples {
+		usage, ok := s.usages[kind]
+		if !ok {
+			continue
+		}
+		supply, used := supplyAndUsage(id, usage)
+		xs = append(xs, s.time.Sub(start).Seconds())
+		ys = append(ys, supply-used)
+	}
+
+	supply, used := supplyAndUsage(
+		id, samples[len(samples)-1].usages[kind])
+	forecast := &pb_respool.CapacityForecast{
+		Kind:                kind,
+		Supply:              supply,
+		Usage:               used,
+		Headroom:            supply - used,
+		SecondsToExhaustion: _notExhausting,
+	}
+
+	rate := slope(xs, ys)
+	forecast.GrowthPerHour = -rate * 3600
+	switch {
+	case forecast.GetHeadroom() <= 0:
+		forecast.SecondsToExhaustion = 0
+	case rate < 0:
+		forecast.SecondsToExhaustion = forecast.GetHeadroom() / -rate
+	}
+	return forecast
+}
+
+// supplyAndUsage returns the supply and the usage of a resource
+func supplyAndUsage(
+	id string,
+	usage *pb_respool.CapacityUsage) (float64, float64) {
+	if id == common.RootResPoolID {
+		return usage.GetCapacity(), usage.GetReservation()
+	}
+	return usage.GetReservation(), usage.GetAllocation() + usage.GetDemand()
+}
+
+// slope returns the least squares slope of ys over xs, or 0 if there
+// are not enough points to compute it
+func slope(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	if n < 2 {
+		return 0
+	}
+
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var num, den float64
+	for i := range xs {
+		num += (xs[i] - meanX) * (ys[i] - meanY)
+		den += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	if den == 0 {
+		return 0
+	}
+	return num / den
+}
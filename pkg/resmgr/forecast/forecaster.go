@@ -0,0 +1,577 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forecast
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	pb_respool "github.com/uber/peloton/.gen/peloton/api/v0/respool"
+
+	"github.com/uber/peloton/pkg/common"
+	"github.com/uber/peloton/pkg/common/lifecycle"
+	rc "github.com/uber/peloton/pkg/resmgr/common"
+	"github.com/uber/peloton/pkg/resmgr/respool"
+	"github.com/uber/peloton/pkg/storage/objects"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/uber-go/tally"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+const (
+	_defaultSamplingPeriod = time.Minute
+	_defaultWindow         = 24 * time.Hour
+	_defaultPersistPeriod  = 10 * time.Minute
+
+	// _loadTimeout is the timeout to load the history from the storage
+	_loadTimeout = 30 * time.Second
+	// _persistTimeout is the timeout to persist a sample or an alert
+	_persistTimeout = 10 * time.Second
+
+	// _notExhausting is the time to exhaustion of a resource
+	// whose usage is not growing relative to its supply
+	_notExhausting = float64(-1)
+)
+
+// Forecaster keeps a rolling history of the cluster capacity and of the
+// reservation, allocation and demand of the resource pools, and computes
+// from it the headroom of the resources and the time until their usage
+// exceeds their supply.
+type Forecaster interface {
+	// Start starts sampling the resource pools
+	Start() error
+
+	// Stop stops sampling the resource pools and drops the in-memory
+	// history, which is loaded again from the storage on the next start
+	Stop() error
+
+	// SetAlertListener sets the listener which is notified when a
+	// capacity alert is raised
+	SetAlertListener(listener AlertListener)
+
+	// GetForecast returns the history and the forecast of a resource
+	// pool, or of the cluster along with its alerts if the resource pool
+	// is not set
+	GetForecast(
+		id *peloton.ResourcePoolID,
+	) (*pb_respool.GetCapacityForecastResponse, error)
+}
+
+// AlertListener is notified of the capacity alerts raised by the
+// Forecaster.
+type AlertListener interface {
+	// CapacityAlertRaised is called for every alert raised for the
+	// cluster, without any lock of the Forecaster held.
+	CapacityAlertRaised(alert *pb_respool.CapacityAlert)
+}
+
+// sample is the capacity usage of a resource pool at a point in time
+type sample struct {
+	time   time.Time
+	usages map[string]*pb_respool.CapacityUsage
+}
+
+// forecaster implements the Forecaster interface
+type forecaster struct {
+	sync.RWMutex
+
+	tree      respool.Tree
+	store     objects.CapacityHistoryOps
+	cfg       rc.ForecastConfig
+	lifecycle lifecycle.LifeCycle
+	metrics   *metrics
+	listener  AlertListener
+
+	// history of the resource pools keyed by their ID, oldest first
+	history map[string][]*sample
+	// alerts raised for the cluster, oldest first
+	alerts []*pb_respool.CapacityAlert
+	// alerts currently raised keyed by the alert type and resource kind,
+	// so that an alert is only raised again once it has cleared
+	raised map[string]bool
+	// time the samples were last persisted, only accessed by the
+	// sampling goroutine while it is running
+	persisted time.Time
+}
+
+// NewForecaster creates a new Forecaster
+func NewForecaster(
+	parent tally.Scope,
+	tree respool.Tree,
+	store objects.CapacityHistoryOps,
+	cfg *rc.ForecastConfig) Forecaster {
+	f := &forecaster{
+		tree:      tree,
+		store:     store,
+		lifecycle: lifecycle.NewLifeCycle(),
+		metrics:   newMetrics(parent),
+		history:   make(map[string][]*sample),
+		raised:    make(map[string]bool),
+	}
+	if cfg != nil {
+		f.cfg = *cfg
+	}
+	if f.cfg.SamplingPeriod == 0 {
+		f.cfg.SamplingPeriod = _defaultSamplingPeriod
+	}
+	if f.cfg.Window == 0 {
+		f.cfg.Window = _defaultWindow
+	}
+	if f.cfg.PersistPeriod == 0 {
+		f.cfg.PersistPeriod = _defaultPersistPeriod
+	}
+	return f
+}
+
+// Start starts the Forecaster process
+func (f *forecaster) Start() error {
+	if !f.lifecycle.Start() {
+		log.Warn("Capacity Forecaster is already running, " +
+			"no action will be performed")
+		return nil
+	}
+	started := make(chan int, 1)
+	go func() {
+		defer f.lifecycle.StopComplete()
+		ticker := time.NewTicker(f.cfg.SamplingPeriod)
+		defer ticker.Stop()
+
+		log.Info("Starting Capacity Forecaster")
+		close(started)
+
+		// the history of the previous leader is loaded from the storage
+		if err := f.load(time.Now()); err != nil {
+			f.metrics.loadFailed.Inc(1)
+			log.WithError(err).Error("Failed to load the capacity history")
+		}
+
+		for {
+			select {
+			case <-f.lifecycle.StopCh():
+				log.Info("Exiting Capacity Forecaster")
+				return
+			case <-ticker.C:
+				now := time.Now()
+				alerts, err := f.sample(now)
+				if err != nil {
+					f.metrics.samplingFailed.Inc(1)
+					log.WithError(err).Error("Capacity sampling failed")
+					continue
+				}
+				f.publish(now, alerts)
+				f.persist(now)
+			}
+		}
+	}()
+	<-started
+	return nil
+}
+
+// Stop stops the Forecaster process
+func (f *forecaster) Stop() error {
+	if !f.lifecycle.Stop() {
+		log.Warn("Capacity Forecaster is already stopped, " +
+			"no action will be performed")
+		return nil
+	}
+	log.Info("Stopping Capacity Forecaster")
+	f.lifecycle.Wait()
+
+	// the history is only kept by the leader
+	f.Lock()
+	defer f.Unlock()
+	f.history = make(map[string][]*sample)
+	f.alerts = nil
+	f.raised = make(map[string]bool)
+	f.persisted = time.Time{}
+	log.Info("Capacity Forecaster Stopped")
+	return nil
+}
+
+// SetAlertListener sets the listener which is notified when a capacity
+// alert is raised
+func (f *forecaster) SetAlertListener(listener AlertListener) {
+	f.Lock()
+	defer f.Unlock()
+	f.listener = listener
+}
+
+// GetForecast returns the history and the forecast of a resource pool
+func (f *forecaster) GetForecast(
+	id *peloton.ResourcePoolID,
+) (*pb_respool.GetCapacityForecastResponse, error) {
+	if id.GetValue() == "" {
+		id = &peloton.ResourcePoolID{Value: common.RootResPoolID}
+	}
+
+	f.RLock()
+	defer f.RUnlock()
+
+	samples, ok := f.history[id.GetValue()]
+	if !ok {
+		return nil, yarpcerrors.NotFoundErrorf(
+			"no capacity history for resource pool %s", id.GetValue())
+	}
+
+	resp := &pb_respool.GetCapacityForecastResponse{
+		Id:        id,
+		Forecasts: forecastAll(id.GetValue(), samples),
+	}
+	// the alerts are only raised for the cluster
+	if id.GetValue() == common.RootResPoolID {
+		resp.Alerts = append([]*pb_respool.CapacityAlert{}, f.alerts...)
+	}
+	for _, s := range samples {
+		resp.Samples = append(resp.Samples, s.toCapacitySample())
+	}
+	return resp, nil
+}
+
+// load loads the history of the resource pools and the alerts within the
+// window from the storage. The loaded alerts are considered raised, so
+// that they are not raised again until they have cleared.
+func (f *forecaster) load(now time.Time) error {
+	nodes := f.tree.GetAllNodes(false)
+	if nodes == nil {
+		return errors.New("resource pool tree is not initialized")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), _loadTimeout)
+	defer cancel()
+
+	since := now.Add(-f.cfg.Window)
+	history := make(map[string][]*sample)
+	for e := nodes.Front(); e != nil; e = e.Next() {
+		pool, ok := e.Value.(respool.ResPool)
+		if !ok {
+			return errors.Errorf(
+				"failed to type assert resource pool %v", e.Value)
+		}
+
+		capacitySamples, err := f.store.GetSamples(ctx, pool.ID(), since)
+		if err != nil {
+			return errors.Wrapf(err,
+				"failed to load the samples of resource pool %s", pool.ID())
+		}
+		for _, capacitySample := range capacitySamples {
+			s, err := newSampleFromCapacitySample(capacitySample)
+			if err != nil {
+				return errors.Wrapf(err,
+					"failed to load a sample of resource pool %s", pool.ID())
+			}
+			history[pool.ID()] = append(history[pool.ID()], s)
+		}
+	}
+
+	alerts, err := f.store.GetAlerts(ctx, common.RootResPoolID, since)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the alerts")
+	}
+
+	f.Lock()
+	defer f.Unlock()
+	f.history = history
+	f.alerts = alerts
+	for _, alert := range alerts {
+		f.raised[alertKey(alert.GetType(), alert.GetKind())] = true
+	}
+	if samples := history[common.RootResPoolID]; len(samples) > 0 {
+		f.persisted = samples[len(samples)-1].time
+	}
+	log.WithField("resource_pools", len(history)).
+		WithField("alerts", len(alerts)).
+		Info("Capacity history loaded")
+	return nil
+}
+
+// publish notifies the listener of the alerts raised and persists them
+func (f *forecaster) publish(
+	now time.Time,
+	alerts []*pb_respool.CapacityAlert) {
+	f.RLock()
+	listener := f.listener
+	f.RUnlock()
+
+	for _, alert := range alerts {
+		if listener != nil {
+			listener.CapacityAlertRaised(alert)
+		}
+
+		ctx, cancel := context.WithTimeout(
+			context.Background(), _persistTimeout)
+		err := f.store.AddAlert(ctx, common.RootResPoolID, now, alert)
+		cancel()
+		if err != nil {
+			f.metrics.persistFailed.Inc(1)
+			log.WithError(err).
+				WithField("alert", alert).
+				Error("Failed to persist capacity alert")
+		}
+	}
+}
+
+// persist persists the last sample of all the resource pools once per
+// persist period, so that the history is downsampled in the storage
+func (f *forecaster) persist(now time.Time) {
+	if now.Sub(f.persisted) < f.cfg.PersistPeriod {
+		return
+	}
+	f.persisted = now
+
+	f.RLock()
+	samples := make(map[string]*sample, len(f.history))
+	for id, history := range f.history {
+		if len(history) > 0 {
+			samples[id] = history[len(history)-1]
+		}
+	}
+	f.RUnlock()
+
+	for id, s := range samples {
+		ctx, cancel := context.WithTimeout(
+			context.Background(), _persistTimeout)
+		err := f.store.AddSample(ctx, id, s.time, s.toCapacitySample())
+		cancel()
+		if err != nil {
+			f.metrics.persistFailed.Inc(1)
+			log.WithError(err).
+				WithField("respool_id", id).
+				Error("Failed to persist capacity sample")
+		}
+	}
+}
+
+// sample records the capacity usage of all the resource pools, drops
+// the samples older than the window and raises the cluster alerts. It
+// returns the alerts raised.
+func (f *forecaster) sample(now time.Time) ([]*pb_respool.CapacityAlert, error) {
+	defer f.metrics.samplingDuration.Start().Stop()
+
+	nodes := f.tree.GetAllNodes(false)
+	if nodes == nil {
+		return nil, errors.New("resource pool tree is not initialized")
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	since := now.Add(-f.cfg.Window)
+	history := make(map[string][]*sample)
+	for e := nodes.Front(); e != nil; e = e.Next() {
+		pool, ok := e.Value.(respool.ResPool)
+		if !ok {
+			return nil, errors.Errorf(
+				"failed to type assert resource pool %v", e.Value)
+		}
+
+		s, err := newSample(now, pool)
+		if err != nil {
+			return nil, errors.Wrapf(err,
+				"failed to sample resource pool %s", pool.ID())
+		}
+
+		var samples []*sample
+		for _, old := range f.history[pool.ID()] {
+			if old.time.After(since) {
+				samples = append(samples, old)
+			}
+		}
+		history[pool.ID()] = append(samples, s)
+	}
+	// resource pools deleted since the last sample are dropped
+	f.history = history
+
+	var alerts []*pb_respool.CapacityAlert
+	for _, alert := range f.alerts {
+		if t, err := time.Parse(time.RFC3339, alert.GetTime()); err == nil &&
+			t.After(since) {
+			alerts = append(alerts, alert)
+		}
+	}
+	f.alerts = alerts
+
+	return f.checkThresholds(now), nil
+}
+
+// checkThresholds updates the forecast metrics of the cluster and raises
+// an alert for the resources whose headroom or time to exhaustion fall
+// below their thresholds. It returns the alerts raised.
+func (f *forecaster) checkThresholds(
+	now time.Time) []*pb_respool.CapacityAlert {
+	var alerts []*pb_respool.CapacityAlert
+	for _, forecast := range forecastAll(
+		common.RootResPoolID,
+		f.history[common.RootResPoolID]) {
+		kind := forecast.GetKind()
+		f.metrics.updateForecast(
+			kind,
+			forecast.GetHeadroom(),
+			forecast.GetGrowthPerHour(),
+			forecast.GetSecondsToExhaustion())
+
+		if forecast.GetSupply() <= 0 {
+			continue
+		}
+
+		threshold := f.cfg.HeadroomThreshold * forecast.GetSupply()
+		alerts = f.raise(
+			alerts,
+			now,
+			kind,
+			pb_respool.CapacityAlert_HEADROOM,
+			f.cfg.HeadroomThreshold > 0 &&
+				forecast.GetHeadroom() < threshold,
+			fmt.Sprintf("headroom %.2f is below %.2f",
+				forecast.GetHeadroom(), threshold),
+		)
+
+		alerts = f.raise(
+			alerts,
+			now,
+			kind,
+			pb_respool.CapacityAlert_EXHAUSTION,
+			f.cfg.ExhaustionThreshold > 0 &&
+				forecast.GetSecondsToExhaustion() != _notExhausting &&
+				forecast.GetSecondsToExhaustion() <
+					f.cfg.ExhaustionThreshold.Seconds(),
+			fmt.Sprintf("reservations expected to exceed capacity in %s",
+				time.Duration(forecast.GetSecondsToExhaustion())*time.Second),
+		)
+	}
+	return alerts
+}
+
+// raise raises an alert if its condition holds and it is not already
+// raised, and clears it otherwise. The alert raised is appended to the
+// alerts provided.
+func (f *forecaster) raise(
+	alerts []*pb_respool.CapacityAlert,
+	now time.Time,
+	kind string,
+	alertType pb_respool.CapacityAlert_Type,
+	condition bool,
+	message string) []*pb_respool.CapacityAlert {
+	key := alertKey(alertType, kind)
+	if !condition {
+		delete(f.raised, key)
+		return alerts
+	}
+	if f.raised[key] {
+		return alerts
+	}
+	f.raised[key] = true
+
+	switch alertType {
+	case pb_respool.CapacityAlert_HEADROOM:
+		f.metrics.headroomAlert.Inc(1)
+	case pb_respool.CapacityAlert_EXHAUSTION:
+		f.metrics.exhaustionAlert.Inc(1)
+	}
+	log.WithField("kind", kind).
+		WithField("type", alertType.String()).
+		WithField("message", message).
+		Warn("Cluster capacity alert")
+
+	alert := &pb_respool.CapacityAlert{
+		Time:    now.UTC().Format(time.RFC3339),
+		Kind:    kind,
+		Type:    alertType,
+		Message: message,
+	}
+	f.alerts = append(f.alerts, alert)
+	return append(alerts, alert)
+}
+
+// alertKey returns the key of an alert in the raised alerts
+func alertKey(alertType pb_respool.CapacityAlert_Type, kind string) string {
+	return alertType.String() + "/" + kind
+}
+
+// newSample returns the capacity usage of a resource pool. The capacity
+// of the cluster is the reservation of the root resource pool, which is
+// set by the entitlement calculator.
+func newSample(now time.Time, pool respool.ResPool) (*sample, error) {
+	s := &sample{
+		time:   now,
+		usages: make(map[string]*pb_respool.CapacityUsage),
+	}
+
+	allocation := pool.GetTotalAllocatedResources()
+	demand := pool.GetDemand()
+	for kind, resource := range pool.Resources() {
+		s.usages[kind] = &pb_respool.CapacityUsage{
+			Kind:        kind,
+			Reservation: resource.GetReservation(),
+			Allocation:  allocation.Get(kind),
+			Demand:      demand.Get(kind),
+		}
+	}
+
+	if !pool.IsRoot() {
+		return s, nil
+	}
+
+	reservations, err := pool.AggregatedChildrenReservations()
+	if err != nil {
+		return nil, err
+	}
+	for kind, usage := range s.usages {
+		usage.Capacity = usage.GetReservation()
+		usage.Reservation = reservations[kind]
+	}
+	return s, nil
+}
+
+// newSampleFromCapacitySample converts a sample from its API
+// representation
+func newSampleFromCapacitySample(
+	capacitySample *pb_respool.CapacitySample) (*sample, error) {
+	t, err := time.Parse(time.RFC3339, capacitySample.GetTime())
+	if err != nil {
+		return nil, err
+	}
+
+	s := &sample{
+		time:   t,
+		usages: make(map[string]*pb_respool.CapacityUsage),
+	}
+	for _, usage := range capacitySample.GetUsages() {
+		s.usages[usage.GetKind()] = usage
+	}
+	return s, nil
+}
+
+// toCapacitySample converts the sample to its API representation
+func (s *sample) toCapacitySample() *pb_respool.CapacitySample {
+	kinds := make([]string, 0, len(s.usages))
+	for kind := range s.usages {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	capacitySample := &pb_respool.CapacitySample{
+		Time: s.time.UTC().Format(time.RFC3339),
+	}
+	for _, kind := range kinds {
+		capacitySample.Usages = append(capacitySample.Usages, s.usages[kind])
+	}
+	return capacitySample
+}
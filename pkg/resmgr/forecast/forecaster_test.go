@@ -0,0 +1,432 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forecast
+
+import (
+	"container/list"
+	"testing"
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	pb_respool "github.com/uber/peloton/.gen/peloton/api/v0/respool"
+
+	"github.com/uber/peloton/pkg/common"
+	rc "github.com/uber/peloton/pkg/resmgr/common"
+	respool_mocks "github.com/uber/peloton/pkg/resmgr/respool/mocks"
+	"github.com/uber/peloton/pkg/resmgr/scalar"
+	objectmocks "github.com/uber/peloton/pkg/storage/objects/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	"go.uber.org/yarpc/yarpcerrors"
+)
+
+const (
+	_childID = "respool1"
+	_cpu     = 100.0
+)
+
+type ForecasterTestSuite struct {
+	suite.Suite
+
+	mockCtrl *gomock.Controller
+	tree     *respool_mocks.MockTree
+	root     *respool_mocks.MockResPool
+	child    *respool_mocks.MockResPool
+	store    *objectmocks.MockCapacityHistoryOps
+
+	forecaster *forecaster
+	start      time.Time
+}
+
+func TestForecaster(t *testing.T) {
+	suite.Run(t, new(ForecasterTestSuite))
+}
+
+func (s *ForecasterTestSuite) SetupTest() {
+	s.mockCtrl = gomock.NewController(s.T())
+	s.tree = respool_mocks.NewMockTree(s.mockCtrl)
+	s.root = respool_mocks.NewMockResPool(s.mockCtrl)
+	s.child = respool_mocks.NewMockResPool(s.mockCtrl)
+	s.store = objectmocks.NewMockCapacityHistoryOps(s.mockCtrl)
+
+	s.root.EXPECT().ID().Return(common.RootResPoolID).AnyTimes()
+	s.root.EXPECT().IsRoot().Return(true).AnyTimes()
+	s.child.EXPECT().ID().Return(_childID).AnyTimes()
+	s.child.EXPECT().IsRoot().Return(false).AnyTimes()
+
+	s.forecaster = NewForecaster(
+		tally.NoopScope,
+		s.tree,
+		s.store,
+		&rc.ForecastConfig{
+			SamplingPeriod:      time.Hour,
+			Window:              24 * time.Hour,
+			PersistPeriod:       2 * time.Hour,
+			HeadroomThreshold:   0.1,
+			ExhaustionThreshold: 24 * time.Hour,
+		}).(*forecaster)
+	s.start = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *ForecasterTestSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+// sample samples the resource pools, ignoring the alerts raised
+func (s *ForecasterTestSuite) sample(now time.Time) error {
+	_, err := s.forecaster.sample(now)
+	return err
+}
+
+// expectSample sets up the tree for one sample where the resource pool
+// reserves the given cpu and has the given cpu allocation and demand
+func (s *ForecasterTestSuite) expectSample(
+	reservation, allocation, demand float64) {
+	nodes := list.New()
+	nodes.PushBack(s.root)
+	nodes.PushBack(s.child)
+	s.tree.EXPECT().GetAllNodes(false).Return(nodes)
+
+	s.root.EXPECT().Resources().Return(
+		map[string]*pb_respool.ResourceConfig{
+			common.CPU: {Kind: common.CPU, Reservation: _cpu, Limit: _cpu},
+		})
+	s.root.EXPECT().GetTotalAllocatedResources().
+		Return(&scalar.Resources{CPU: allocation})
+	s.root.EXPECT().GetDemand().Return(&scalar.Resources{CPU: demand})
+	s.root.EXPECT().AggregatedChildrenReservations().
+		Return(map[string]float64{common.CPU: reservation}, nil)
+
+	s.child.EXPECT().Resources().Return(
+		map[string]*pb_respool.ResourceConfig{
+			common.CPU: {Kind: common.CPU, Reservation: reservation, Limit: _cpu},
+		})
+	s.child.EXPECT().GetTotalAllocatedResources().
+		Return(&scalar.Resources{CPU: allocation})
+	s.child.EXPECT().GetDemand().Return(&scalar.Resources{CPU: demand})
+}
+
+// TestForecastClusterExhaustion tests that growing reservations raise a
+// single exhaustion alert with the expected time to exhaustion
+func (s *ForecasterTestSuite) TestForecastClusterExhaustion() {
+	for i, reservation := range []float64{50, 60, 70} {
+		s.expectSample(reservation, 0, 0)
+		s.NoError(s.sample(
+			s.start.Add(time.Duration(i) * time.Hour)))
+	}
+
+	resp, err := s.forecaster.GetForecast(nil)
+	s.NoError(err)
+	s.Equal(common.RootResPoolID, resp.GetId().GetValue())
+	s.Len(resp.GetSamples(), 3)
+	s.Equal("2019-01-01T00:00:00Z", resp.GetSamples()[0].GetTime())
+	s.Equal(_cpu, resp.GetSamples()[2].GetUsages()[0].GetCapacity())
+	s.Equal(70.0, resp.GetSamples()[2].GetUsages()[0].GetReservation())
+
+	s.Len(resp.GetForecasts(), 1)
+	forecast := resp.GetForecasts()[0]
+	s.Equal(common.CPU, forecast.GetKind())
+	s.Equal(_cpu, forecast.GetSupply())
+	s.Equal(70.0, forecast.GetUsage())
+	s.Equal(30.0, forecast.GetHeadroom())
+	s.InDelta(10.0, forecast.GetGrowthPerHour(), 0.001)
+	s.InDelta(3*time.Hour.Seconds(), forecast.GetSecondsToExhaustion(), 0.1)
+
+	s.Len(resp.GetAlerts(), 1)
+	s.Equal(pb_respool.CapacityAlert_EXHAUSTION, resp.GetAlerts()[0].GetType())
+	s.Equal(common.CPU, resp.GetAlerts()[0].GetKind())
+	s.Equal("2019-01-01T01:00:00Z", resp.GetAlerts()[0].GetTime())
+
+	// the cluster alerts are not returned for the other resource pools
+	resp, err = s.forecaster.GetForecast(
+		&peloton.ResourcePoolID{Value: _childID})
+	s.NoError(err)
+	s.Empty(resp.GetAlerts())
+}
+
+// TestForecastClusterHeadroom tests that a headroom alert is raised when
+// the headroom drops below the threshold, and raised again only after
+// it has cleared
+func (s *ForecasterTestSuite) TestForecastClusterHeadroom() {
+	s.forecaster.cfg.ExhaustionThreshold = 0
+	for i, reservation := range []float64{95, 95, 50, 95} {
+		s.expectSample(reservation, 0, 0)
+		s.NoError(s.sample(
+			s.start.Add(time.Duration(i) * time.Hour)))
+	}
+
+	resp, err := s.forecaster.GetForecast(
+		&peloton.ResourcePoolID{Value: common.RootResPoolID})
+	s.NoError(err)
+	s.Len(resp.GetAlerts(), 2)
+	for _, alert := range resp.GetAlerts() {
+		s.Equal(pb_respool.CapacityAlert_HEADROOM, alert.GetType())
+	}
+	s.Equal("2019-01-01T00:00:00Z", resp.GetAlerts()[0].GetTime())
+	s.Equal("2019-01-01T03:00:00Z", resp.GetAlerts()[1].GetTime())
+}
+
+// TestForecastResPool tests the forecast of a resource pool whose usage
+// is its allocation and demand
+func (s *ForecasterTestSuite) TestForecastResPool() {
+	for i := 0; i < 2; i++ {
+		s.expectSample(40, 10, 5)
+		s.NoError(s.sample(
+			s.start.Add(time.Duration(i) * time.Hour)))
+	}
+
+	resp, err := s.forecaster.GetForecast(
+		&peloton.ResourcePoolID{Value: _childID})
+	s.NoError(err)
+	s.Len(resp.GetForecasts(), 1)
+	forecast := resp.GetForecasts()[0]
+	s.Equal(40.0, forecast.GetSupply())
+	s.Equal(15.0, forecast.GetUsage())
+	s.Equal(25.0, forecast.GetHeadroom())
+	s.Equal(0.0, forecast.GetGrowthPerHour())
+	s.Equal(_notExhausting, forecast.GetSecondsToExhaustion())
+	s.Empty(resp.GetAlerts())
+}
+
+// TestForecastExhausted tests that the time to exhaustion is zero once
+// the usage exceeds the supply
+func (s *ForecasterTestSuite) TestForecastExhausted() {
+	s.expectSample(40, 30, 20)
+	s.NoError(s.sample(s.start))
+
+	resp, err := s.forecaster.GetForecast(
+		&peloton.ResourcePoolID{Value: _childID})
+	s.NoError(err)
+	s.Equal(-10.0, resp.GetForecasts()[0].GetHeadroom())
+	s.Equal(0.0, resp.GetForecasts()[0].GetSecondsToExhaustion())
+}
+
+// TestSampleWindow tests that the samples and alerts older than the
+// window are dropped along with the deleted resource pools
+func (s *ForecasterTestSuite) TestSampleWindow() {
+	s.forecaster.cfg.Window = 2 * time.Hour
+	for i := 0; i < 3; i++ {
+		s.expectSample(95, 0, 0)
+		s.NoError(s.sample(
+			s.start.Add(time.Duration(i) * time.Hour)))
+	}
+
+	resp, err := s.forecaster.GetForecast(nil)
+	s.NoError(err)
+	s.Len(resp.GetSamples(), 2)
+	s.Equal("2019-01-01T01:00:00Z", resp.GetSamples()[0].GetTime())
+	// the headroom alert raised by the first sample is dropped with it
+	s.Empty(resp.GetAlerts())
+
+	nodes := list.New()
+	nodes.PushBack(s.root)
+	s.tree.EXPECT().GetAllNodes(false).Return(nodes)
+	s.root.EXPECT().Resources().Return(
+		map[string]*pb_respool.ResourceConfig{
+			common.CPU: {Kind: common.CPU, Reservation: _cpu, Limit: _cpu},
+		})
+	s.root.EXPECT().GetTotalAllocatedResources().Return(&scalar.Resources{})
+	s.root.EXPECT().GetDemand().Return(&scalar.Resources{})
+	s.root.EXPECT().AggregatedChildrenReservations().
+		Return(map[string]float64{}, nil)
+	s.NoError(s.sample(s.start.Add(3 * time.Hour)))
+
+	_, err = s.forecaster.GetForecast(
+		&peloton.ResourcePoolID{Value: _childID})
+	s.True(yarpcerrors.IsNotFound(err))
+}
+
+// TestSampleFailures tests the failures to sample the resource pools
+func (s *ForecasterTestSuite) TestSampleFailures() {
+	s.tree.EXPECT().GetAllNodes(false).Return(nil)
+	s.Error(s.sample(s.start))
+
+	nodes := list.New()
+	nodes.PushBack(s.root)
+	s.tree.EXPECT().GetAllNodes(false).Return(nodes)
+	s.root.EXPECT().Resources().Return(
+		map[string]*pb_respool.ResourceConfig{})
+	s.root.EXPECT().GetTotalAllocatedResources().Return(&scalar.Resources{})
+	s.root.EXPECT().GetDemand().Return(&scalar.Resources{})
+	s.root.EXPECT().AggregatedChildrenReservations().
+		Return(nil, errors.New("failed to aggregate"))
+	s.Error(s.sample(s.start))
+}
+
+// TestGetForecastNotFound tests getting the forecast of an unknown
+// resource pool
+func (s *ForecasterTestSuite) TestGetForecastNotFound() {
+	_, err := s.forecaster.GetForecast(nil)
+	s.True(yarpcerrors.IsNotFound(err))
+}
+
+// TestStartStop tests starting and stopping the forecaster, which loads
+// the history on start and drops it on stop
+func (s *ForecasterTestSuite) TestStartStop() {
+	s.expectSample(50, 0, 0)
+	s.NoError(s.sample(s.start))
+
+	nodes := list.New()
+	nodes.PushBack(s.root)
+	s.tree.EXPECT().GetAllNodes(false).Return(nodes)
+	s.store.EXPECT().
+		GetSamples(gomock.Any(), common.RootResPoolID, gomock.Any()).
+		Return(nil, nil)
+	s.store.EXPECT().
+		GetAlerts(gomock.Any(), common.RootResPoolID, gomock.Any()).
+		Return(nil, nil)
+
+	s.NoError(s.forecaster.Start())
+	s.NoError(s.forecaster.Start())
+	s.NoError(s.forecaster.Stop())
+	s.NoError(s.forecaster.Stop())
+
+	_, err := s.forecaster.GetForecast(nil)
+	s.True(yarpcerrors.IsNotFound(err))
+}
+
+// TestLoad tests loading the history and the alerts of the previous
+// leader, whose alerts are not raised again while their condition holds
+func (s *ForecasterTestSuite) TestLoad() {
+	s.forecaster.cfg.ExhaustionThreshold = 0
+	since := s.start.Add(time.Hour - s.forecaster.cfg.Window)
+
+	nodes := list.New()
+	nodes.PushBack(s.root)
+	nodes.PushBack(s.child)
+	s.tree.EXPECT().GetAllNodes(false).Return(nodes)
+	s.store.EXPECT().GetSamples(gomock.Any(), common.RootResPoolID, since).
+		Return([]*pb_respool.CapacitySample{
+			{
+				Time: "2019-01-01T00:00:00Z",
+				Usages: []*pb_respool.CapacityUsage{
+					{Kind: common.CPU, Capacity: _cpu, Reservation: 95},
+				},
+			},
+		}, nil)
+	s.store.EXPECT().GetSamples(gomock.Any(), _childID, since).
+		Return(nil, nil)
+	s.store.EXPECT().GetAlerts(gomock.Any(), common.RootResPoolID, since).
+		Return([]*pb_respool.CapacityAlert{
+			{
+				Time: "2019-01-01T00:00:00Z",
+				Kind: common.CPU,
+				Type: pb_respool.CapacityAlert_HEADROOM,
+			},
+		}, nil)
+	s.NoError(s.forecaster.load(s.start.Add(time.Hour)))
+	s.Equal(s.start, s.forecaster.persisted)
+
+	s.expectSample(95, 0, 0)
+	alerts, err := s.forecaster.sample(s.start.Add(time.Hour))
+	s.NoError(err)
+	s.Empty(alerts)
+
+	resp, err := s.forecaster.GetForecast(nil)
+	s.NoError(err)
+	s.Len(resp.GetSamples(), 2)
+	s.Equal("2019-01-01T00:00:00Z", resp.GetSamples()[0].GetTime())
+	s.Equal(95.0, resp.GetSamples()[0].GetUsages()[0].GetReservation())
+	s.Len(resp.GetAlerts(), 1)
+
+	resp, err = s.forecaster.GetForecast(
+		&peloton.ResourcePoolID{Value: _childID})
+	s.NoError(err)
+	s.Len(resp.GetSamples(), 1)
+}
+
+// TestLoadFailures tests the failures to load the history, which leave
+// the history empty
+func (s *ForecasterTestSuite) TestLoadFailures() {
+	s.tree.EXPECT().GetAllNodes(false).Return(nil)
+	s.Error(s.forecaster.load(s.start))
+
+	nodes := list.New()
+	nodes.PushBack(s.root)
+	s.tree.EXPECT().GetAllNodes(false).Return(nodes).Times(3)
+	s.store.EXPECT().
+		GetSamples(gomock.Any(), common.RootResPoolID, gomock.Any()).
+		Return(nil, errors.New("failed to get samples"))
+	s.Error(s.forecaster.load(s.start))
+
+	s.store.EXPECT().
+		GetSamples(gomock.Any(), common.RootResPoolID, gomock.Any()).
+		Return([]*pb_respool.CapacitySample{{Time: "invalid"}}, nil)
+	s.Error(s.forecaster.load(s.start))
+
+	s.store.EXPECT().
+		GetSamples(gomock.Any(), common.RootResPoolID, gomock.Any()).
+		Return(nil, nil)
+	s.store.EXPECT().
+		GetAlerts(gomock.Any(), common.RootResPoolID, gomock.Any()).
+		Return(nil, errors.New("failed to get alerts"))
+	s.Error(s.forecaster.load(s.start))
+
+	_, err := s.forecaster.GetForecast(nil)
+	s.True(yarpcerrors.IsNotFound(err))
+}
+
+// testAlertListener records the alerts it is notified of
+type testAlertListener struct {
+	alerts []*pb_respool.CapacityAlert
+}
+
+func (l *testAlertListener) CapacityAlertRaised(
+	alert *pb_respool.CapacityAlert) {
+	l.alerts = append(l.alerts, alert)
+}
+
+// TestPublishPersist tests notifying the listener of the alerts raised
+// and persisting the alerts and the downsampled history
+func (s *ForecasterTestSuite) TestPublishPersist() {
+	listener := &testAlertListener{}
+	s.forecaster.SetAlertListener(listener)
+	s.forecaster.cfg.ExhaustionThreshold = 0
+
+	s.expectSample(95, 0, 0)
+	alerts, err := s.forecaster.sample(s.start)
+	s.NoError(err)
+	s.Len(alerts, 1)
+
+	s.store.EXPECT().
+		AddAlert(gomock.Any(), common.RootResPoolID, s.start, alerts[0]).
+		Return(errors.New("failed to add alert"))
+	s.forecaster.publish(s.start, alerts)
+	s.Equal(alerts, listener.alerts)
+
+	s.store.EXPECT().
+		AddSample(gomock.Any(), common.RootResPoolID, s.start, gomock.Any()).
+		Do(func(
+			_ interface{},
+			_ string,
+			_ time.Time,
+			sample *pb_respool.CapacitySample) {
+			s.Equal("2019-01-01T00:00:00Z", sample.GetTime())
+			s.Equal(_cpu, sample.GetUsages()[0].GetCapacity())
+		}).
+		Return(nil)
+	s.store.EXPECT().
+		AddSample(gomock.Any(), _childID, s.start, gomock.Any()).
+		Return(errors.New("failed to add sample"))
+	s.forecaster.persist(s.start)
+
+	// the samples are only persisted once per persist period
+	s.expectSample(95, 0, 0)
+	s.NoError(s.sample(s.start.Add(time.Hour)))
+	s.forecaster.persist(s.start.Add(time.Hour))
+}
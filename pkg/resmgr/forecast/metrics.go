@@ -0,0 +1,59 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forecast
+
+import "github.com/uber-go/tally"
+
+// metrics tracks the capacity forecasting metrics.
+type metrics struct {
+	scope tally.Scope
+
+	// Tracks the failure count of the sampling cycle.
+	samplingFailed tally.Counter
+	// Tracks the duration of the sampling cycle.
+	samplingDuration tally.Timer
+	// Tracks the failure count of loading the history.
+	loadFailed tally.Counter
+	// Tracks the failure count of persisting the samples and alerts.
+	persistFailed tally.Counter
+	// Tracks the number of headroom alerts raised.
+	headroomAlert tally.Counter
+	// Tracks the number of exhaustion alerts raised.
+	exhaustionAlert tally.Counter
+}
+
+// newMetrics returns a new instance of forecast.metrics.
+func newMetrics(scope tally.Scope) *metrics {
+	fScope := scope.SubScope("forecast")
+
+	return &metrics{
+		scope:            fScope,
+		samplingFailed:   fScope.Counter("sampling_failed"),
+		samplingDuration: fScope.Timer("sampling_duration"),
+		loadFailed:       fScope.Counter("load_failed"),
+		persistFailed:    fScope.Counter("persist_failed"),
+		headroomAlert:    fScope.Counter("headroom_alert"),
+		exhaustionAlert:  fScope.Counter("exhaustion_alert"),
+	}
+}
+
+// updateForecast updates the gauges of the cluster forecast of a
+// resource kind.
+func (m *metrics) updateForecast(kind string, headroom, growthPerHour, secondsToExhaustion float64) {
+	kScope := m.scope.Tagged(map[string]string{"kind": kind})
+	kScope.Gauge("headroom").Update(headroom)
+	kScope.Gauge("growth_per_hour").Update(growthPerHour)
+	kScope.Gauge("seconds_to_exhaustion").Update(secondsToExhaustion)
+}
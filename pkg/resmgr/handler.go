@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	pb_respool "github.com/uber/peloton/.gen/peloton/api/v0/respool"
 	t "github.com/uber/peloton/.gen/peloton/api/v0/task"
 	pb_eventstream "github.com/uber/peloton/.gen/peloton/private/eventstream"
 	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
@@ -143,6 +144,36 @@ func (h *ServiceHandler) GetStreamHandler() *eventstream.Handler {
 	return h.eventStreamHandler
 }
 
+// ResourcePoolMoved implements respool.MoveListener. It adds an event to
+// the event stream, so that job manager refreshes the resource pool paths
+// it has cached.
+func (h *ServiceHandler) ResourcePoolMoved(ID *peloton.ResourcePoolID) {
+	err := h.eventStreamHandler.AddEvent(&pb_eventstream.Event{
+		Type:      pb_eventstream.Event_RESOURCE_POOL_MOVED,
+		ResPoolID: ID,
+	})
+	if err != nil {
+		log.WithError(err).
+			WithField("respool_id", ID.GetValue()).
+			Error("Failed to add resource pool moved event")
+	}
+}
+
+// CapacityAlertRaised implements forecast.AlertListener. It adds an event
+// to the event stream, so that the capacity alerts can be consumed along
+// with the other resource manager events.
+func (h *ServiceHandler) CapacityAlertRaised(alert *pb_respool.CapacityAlert) {
+	err := h.eventStreamHandler.AddEvent(&pb_eventstream.Event{
+		Type:          pb_eventstream.Event_CAPACITY_ALERT,
+		CapacityAlert: alert,
+	})
+	if err != nil {
+		log.WithError(err).
+			WithField("alert", alert).
+			Error("Failed to add capacity alert event")
+	}
+}
+
 // EnqueueGangs implements ResourceManagerService.EnqueueGangs
 func (h *ServiceHandler) EnqueueGangs(
 	ctx context.Context,
@@ -804,11 +835,12 @@ func (h *ServiceHandler) fillTaskEntry(task *rmtask.RMTask,
 ) *resmgrsvc.GetActiveTasksResponse_TaskEntry {
 	rmTaskState := task.GetCurrentState()
 	taskEntry := &resmgrsvc.GetActiveTasksResponse_TaskEntry{
-		TaskID:         task.Task().GetTaskId().GetValue(),
-		TaskState:      rmTaskState.State.String(),
-		Reason:         rmTaskState.Reason,
-		LastUpdateTime: rmTaskState.LastUpdateTime.String(),
-		Hostname:       task.Task().GetHostname(),
+		TaskID:                 task.Task().GetTaskId().GetValue(),
+		TaskState:              rmTaskState.State.String(),
+		Reason:                 rmTaskState.Reason,
+		LastUpdateTime:         rmTaskState.LastUpdateTime.String(),
+		Hostname:               task.Task().GetHostname(),
+		LastUpdateTimeUnixNano: rmTaskState.LastUpdateTime.UnixNano(),
 	}
 	return taskEntry
 }
@@ -820,13 +852,18 @@ func (h *ServiceHandler) GetActiveTasks(
 	req *resmgrsvc.GetActiveTasksRequest,
 ) (*resmgrsvc.GetActiveTasksResponse, error) {
 	var taskStates = map[string]*resmgrsvc.GetActiveTasksResponse_TaskEntries{}
-	log.WithField("req", req).Info("GetActiveTasks called")
-
-	taskStateMap := h.rmTracker.GetActiveTasks(
-		req.GetJobID(),
-		req.GetRespoolID(),
-		req.GetStates(),
-	)
+	log.WithField("req", req).Debug("GetActiveTasks called")
+
+	var taskStateMap map[string][]*rmtask.RMTask
+	if len(req.GetTaskIDs()) > 0 {
+		taskStateMap = h.getActiveTasksByID(req)
+	} else {
+		taskStateMap = h.rmTracker.GetActiveTasks(
+			req.GetJobID(),
+			req.GetRespoolID(),
+			req.GetStates(),
+		)
+	}
 	for state, tasks := range taskStateMap {
 		for _, task := range tasks {
 			taskEntry := h.fillTaskEntry(task)
@@ -841,12 +878,39 @@ func (h *ServiceHandler) GetActiveTasks(
 		}
 	}
 
-	log.Info("GetActiveTasks returned")
+	log.Debug("GetActiveTasks returned")
 	return &resmgrsvc.GetActiveTasksResponse{
 		TasksByState: taskStates,
 	}, nil
 }
 
+// getActiveTasksByID returns the active tasks with the task IDs of the
+// request by their state, filtered by the other filters of the request
+func (h *ServiceHandler) getActiveTasksByID(
+	req *resmgrsvc.GetActiveTasksRequest,
+) map[string][]*rmtask.RMTask {
+	taskStates := make(map[string][]*rmtask.RMTask)
+	for _, taskID := range req.GetTaskIDs() {
+		t := h.rmTracker.GetTask(&peloton.TaskID{Value: taskID})
+		if t == nil {
+			continue
+		}
+		if req.GetJobID() != "" &&
+			t.Task().GetJobId().GetValue() != req.GetJobID() {
+			continue
+		}
+		if req.GetRespoolID() != "" && t.Respool().ID() != req.GetRespoolID() {
+			continue
+		}
+		state := t.GetCurrentState().State.String()
+		if len(req.GetStates()) > 0 && !util.Contains(req.GetStates(), state) {
+			continue
+		}
+		taskStates[state] = append(taskStates[state], t)
+	}
+	return taskStates
+}
+
 // GetPendingTasks returns the pending tasks from a resource pool in the
 // order in which they were added up to a max limit number of gangs.
 // Eg specifying a limit of 10 would return pending tasks from the first 10
@@ -1158,3 +1222,56 @@ func (h *ServiceHandler) UpdateTasksState(
 	}
 	return &resmgrsvc.UpdateTasksStateResponse{}, nil
 }
+
+// CheckAdmission returns whether the gangs in the request would be admitted
+// to the resource pool right now. The gangs are checked together, and are
+// neither enqueued nor accounted for in the resource pool.
+func (h *ServiceHandler) CheckAdmission(
+	ctx context.Context,
+	req *resmgrsvc.CheckAdmissionRequest,
+) (*resmgrsvc.CheckAdmissionResponse, error) {
+	respoolID := req.GetResPool()
+
+	log.WithFields(log.Fields{
+		"respool_id": respoolID,
+		"num_gangs":  len(req.GetGangs()),
+	}).Debug("CheckAdmission called")
+
+	if respoolID == nil {
+		return &resmgrsvc.CheckAdmissionResponse{
+			Error: &resmgrsvc.CheckAdmissionResponse_Error{
+				NotFound: &resmgrsvc.ResourcePoolNotFound{
+					Id:      respoolID,
+					Message: "resource pool ID can't be nil",
+				},
+			},
+		}, nil
+	}
+
+	resourcePool, err := h.resPoolTree.Get(respoolID)
+	if err != nil {
+		return &resmgrsvc.CheckAdmissionResponse{
+			Error: &resmgrsvc.CheckAdmissionResponse_Error{
+				NotFound: &resmgrsvc.ResourcePoolNotFound{
+					Id:      respoolID,
+					Message: err.Error(),
+				},
+			},
+		}, nil
+	}
+
+	// merge all gangs into one so the resources of all the tasks are
+	// checked against the resource pool together.
+	merged := &resmgrsvc.Gang{}
+	for _, gang := range req.GetGangs() {
+		merged.Tasks = append(merged.Tasks, gang.GetTasks()...)
+	}
+
+	if err := resourcePool.CheckAdmission(merged); err != nil {
+		return &resmgrsvc.CheckAdmissionResponse{
+			Admitted: false,
+			Reason:   err.Error(),
+		}, nil
+	}
+	return &resmgrsvc.CheckAdmissionResponse{Admitted: true}, nil
+}
@@ -518,6 +518,53 @@ func (s *HandlerTestSuite) TestEnqueueGangsResPoolNotFound() {
 	}
 }
 
+func (s *HandlerTestSuite) TestCheckAdmission() {
+	respoolID := &peloton.ResourcePoolID{Value: "respool3"}
+	node, err := s.resTree.Get(respoolID)
+	s.NoError(err)
+
+	req := &resmgrsvc.CheckAdmissionRequest{
+		ResPool: respoolID,
+		Gangs:   s.pendingGangs(),
+	}
+
+	node.SetNonSlackEntitlement(scalar.ZeroResource)
+	resp, err := s.handler.CheckAdmission(s.context, req)
+	s.NoError(err)
+	s.Nil(resp.GetError())
+	s.False(resp.GetAdmitted())
+	s.NotEmpty(resp.GetReason())
+
+	node.SetNonSlackEntitlement(s.getEntitlement())
+	resp, err = s.handler.CheckAdmission(s.context, req)
+	s.NoError(err)
+	s.Nil(resp.GetError())
+	s.True(resp.GetAdmitted())
+	s.Empty(resp.GetReason())
+
+	// nothing is enqueued by the check
+	gangs, err := node.PeekGangs(respool.PendingQueue, 10)
+	s.Error(err)
+	s.Empty(gangs)
+}
+
+func (s *HandlerTestSuite) TestCheckAdmissionResPoolNotFound() {
+	for _, respoolID := range []*peloton.ResourcePoolID{
+		nil,
+		{Value: "respool10"},
+	} {
+		resp, err := s.handler.CheckAdmission(
+			s.context,
+			&resmgrsvc.CheckAdmissionRequest{
+				ResPool: respoolID,
+				Gangs:   s.pendingGangs(),
+			})
+		s.NoError(err)
+		s.NotNil(resp.GetError().GetNotFound())
+		s.False(resp.GetAdmitted())
+	}
+}
+
 func (s *HandlerTestSuite) TestEnqueueGangsFailure() {
 	// TODO: Mock ResPool.Enqueue task to simulate task enqueue failures
 	s.True(true)
@@ -1189,6 +1236,43 @@ func (s *HandlerTestSuite) TestGetActiveTasksValidateResponseFields() {
 	}
 }
 
+func (s *HandlerTestSuite) TestGetActiveTasksByID() {
+	placements := s.getPlacements(1, 2)
+	for _, t := range placements[0].GetTaskIDs() {
+		rmTask := s.handler.rmTracker.GetTask(t.GetPelotonTaskID())
+		tasktestutil.ValidateStateTransitions(rmTask, []task.TaskState{
+			task.TaskState_PENDING,
+			task.TaskState_READY,
+			task.TaskState_PLACING,
+		})
+	}
+	setResp, err := s.handler.SetPlacements(
+		s.context,
+		&resmgrsvc.SetPlacementsRequest{Placements: placements})
+	s.NoError(err)
+	s.Nil(setResp.GetError())
+
+	taskID := placements[0].GetTaskIDs()[0]
+	req := &resmgrsvc.GetActiveTasksRequest{
+		TaskIDs: []string{taskID.GetPelotonTaskID().GetValue(), "unknown-0"},
+		States:  []string{task.TaskState_PLACED.String()},
+	}
+	res, err := s.handler.GetActiveTasks(context.Background(), req)
+	s.NoError(err)
+	s.Len(res.GetTasksByState(), 1)
+	entries := res.GetTasksByState()[task.TaskState_PLACED.String()]
+	s.Len(entries.GetTaskEntry(), 1)
+	s.Equal(
+		taskID.GetMesosTaskID().GetValue(),
+		entries.GetTaskEntry()[0].GetTaskID())
+
+	// the task is filtered out by its state
+	req.States = []string{task.TaskState_PENDING.String()}
+	res, err = s.handler.GetActiveTasks(context.Background(), req)
+	s.NoError(err)
+	s.Empty(res.GetTasksByState())
+}
+
 func (s *HandlerTestSuite) TestGetPreemptibleTasks() {
 	defer s.handler.rmTracker.Clear()
 
@@ -1350,6 +1434,52 @@ func (s *HandlerTestSuite) TestGetPreemptibleTasksError() {
 	s.handler.rmTracker = rm_task.GetTracker()
 }
 
+// TestResourcePoolMoved tests that moving a resource pool adds an event
+// to the event stream
+func (s *HandlerTestSuite) TestResourcePoolMoved() {
+	handler := &ServiceHandler{
+		eventStreamHandler: eventstream.NewEventStreamHandler(
+			10,
+			[]string{common.PelotonJobManager},
+			nil,
+			tally.NoopScope),
+	}
+	respoolID := &peloton.ResourcePoolID{Value: "respool11"}
+
+	handler.ResourcePoolMoved(respoolID)
+
+	events, err := handler.eventStreamHandler.GetEvents()
+	s.NoError(err)
+	s.Len(events, 1)
+	s.Equal(pb_eventstream.Event_RESOURCE_POOL_MOVED, events[0].GetType())
+	s.Equal(respoolID, events[0].GetResPoolID())
+}
+
+// TestCapacityAlertRaised tests that raising a capacity alert adds an
+// event to the event stream
+func (s *HandlerTestSuite) TestCapacityAlertRaised() {
+	handler := &ServiceHandler{
+		eventStreamHandler: eventstream.NewEventStreamHandler(
+			10,
+			[]string{common.PelotonJobManager},
+			nil,
+			tally.NoopScope),
+	}
+	alert := &pb_respool.CapacityAlert{
+		Time: "2019-01-01T00:00:00Z",
+		Kind: common.CPU,
+		Type: pb_respool.CapacityAlert_HEADROOM,
+	}
+
+	handler.CapacityAlertRaised(alert)
+
+	events, err := handler.eventStreamHandler.GetEvents()
+	s.NoError(err)
+	s.Len(events, 1)
+	s.Equal(pb_eventstream.Event_CAPACITY_ALERT, events[0].GetType())
+	s.Equal(alert, events[0].GetCapacityAlert())
+}
+
 func (s *HandlerTestSuite) TestAddTaskError() {
 	tracker := task_mocks.NewMockTracker(s.ctrl)
 	s.handler.rmTracker = tracker
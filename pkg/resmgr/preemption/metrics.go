@@ -28,6 +28,8 @@ type Metrics struct {
 	NonRevocableRunningTasksToPreempt    tally.Counter
 	NonRevocableNonRunningTasksToPreempt tally.Counter
 
+	PriorityTasksToPreempt tally.Counter
+
 	PreemptionQueueSize tally.Gauge
 	TasksToEvict        tally.Gauge
 
@@ -53,6 +55,8 @@ func NewMetrics(scope tally.Scope) *Metrics {
 		NonRevocableRunningTasksToPreempt:    scope.Counter("non_revocable_running_tasks"),
 		NonRevocableNonRunningTasksToPreempt: scope.Counter("non_revocable_non_running_tasks"),
 
+		PriorityTasksToPreempt: scope.Counter("priority_tasks"),
+
 		TasksFailedPreemption: scope.Counter("num_tasks_failed"),
 
 		PreemptionQueueSize: scope.Gauge("preemption_queue_size"),
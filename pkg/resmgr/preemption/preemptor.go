@@ -20,6 +20,7 @@ import (
 
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
 	peloton_task "github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
 	"github.com/uber/peloton/.gen/peloton/private/resmgr"
 	"github.com/uber/peloton/.gen/peloton/private/resmgrsvc"
 
@@ -85,6 +86,12 @@ type Preemptor struct {
 	// The task tracker
 	tracker task.Tracker
 
+	// config for preempting lower priority tasks on the hosts reserved
+	// for higher priority tasks
+	priorityPreemption common.PriorityPreemptionConfig
+	// host manager client to get the host reservations
+	hostMgrClient hostsvc.InternalHostServiceYARPCClient
+
 	// The metrics scope
 	scope tally.Scope
 	// lazily populated map keyed by the resource pool ID
@@ -97,6 +104,7 @@ func NewPreemptor(
 	cfg *common.PreemptionConfig,
 	tracker task.Tracker,
 	resTree respool.Tree,
+	hostMgrClient hostsvc.InternalHostServiceYARPCClient,
 ) *Preemptor {
 
 	return &Preemptor{
@@ -112,10 +120,12 @@ func NewPreemptor(
 			reflect.TypeOf(resmgr.PreemptionCandidate{}),
 			maxPreemptionQueueSize,
 		),
-		ranker:  newStatePriorityRuntimeRanker(tracker),
-		tracker: tracker,
-		scope:   parent.SubScope("preemption"),
-		m:       make(map[string]*Metrics),
+		ranker:             newStatePriorityRuntimeRanker(tracker),
+		tracker:            tracker,
+		priorityPreemption: cfg.PriorityPreemption,
+		hostMgrClient:      hostMgrClient,
+		scope:              parent.SubScope("preemption"),
+		m:                  make(map[string]*Metrics),
 	}
 }
 
@@ -204,6 +214,13 @@ func (p *Preemptor) EnqueueTasks(
 	return p.processTasks(tasks, reason)
 }
 
+// RunOnce runs a single preemption cycle synchronously. It is meant for
+// callers which drive the preemption themselves rather than relying on the
+// periodic preemption started by Start.
+func (p *Preemptor) RunOnce() error {
+	return p.preemptOnce()
+}
+
 func (p *Preemptor) preemptOnce() error {
 	// collect resource allocation from all resource pools
 	p.updateResourcePoolsState()
@@ -218,6 +235,15 @@ func (p *Preemptor) preemptOnce() error {
 					"resource pool :%s", respoolID))
 		}
 	}
+
+	// preempt lower priority tasks on the hosts reserved for
+	// higher priority tasks
+	if p.priorityPreemption.Enabled {
+		err := p.preemptForHostReservations()
+		if err != nil {
+			combinedErr = multierr.Append(combinedErr, err)
+		}
+	}
 	return combinedErr
 }
 
@@ -385,7 +411,7 @@ func (p *Preemptor) processRunningTask(
 	// ToDo: ResourcesFreed are speculated to get free if preemption
 	// runs uninterrupted. Fix it to track that running tasks reached
 	// terminal state and then emit metrics.
-	resourcesFreed := scalar.ConvertToResmgrResource(t.Task().Resource)
+	resourcesFreed := scalar.GetTaskResources(t.Task())
 	if t.Task().GetRevocable() {
 		p.metrics(t.Respool()).RevocableRunningTasksToPreempt.Inc(1)
 		p.metrics(t.Respool()).SlackRunningTasksResourcesToFreed.Inc(resourcesFreed)
@@ -447,7 +473,7 @@ func (p *Preemptor) processNonRunningTask(
 			"resource pool")
 	}
 
-	resourcesFreed := scalar.ConvertToResmgrResource(rmTask.Task().Resource)
+	resourcesFreed := scalar.GetTaskResources(rmTask.Task())
 	if rmTask.Task().GetRevocable() {
 		p.metrics(resPool).RevocableNonRunningTasksToPreempt.Inc(1)
 		p.metrics(resPool).SlackNonRunningTasksResourcesFreed.Inc(resourcesFreed)
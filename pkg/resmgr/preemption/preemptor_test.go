@@ -847,6 +847,7 @@ func (suite *PreemptorTestSuite) TestNewPreemptor() {
 	},
 		suite.tracker,
 		suite.getResourceTree(),
+		nil,
 	)
 	suite.NotNil(p)
 }
@@ -0,0 +1,237 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preemption
+
+import (
+	"context"
+	"time"
+
+	peloton_task "github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
+	"github.com/uber/peloton/.gen/peloton/private/resmgr"
+
+	"github.com/uber/peloton/pkg/common/util"
+	"github.com/uber/peloton/pkg/resmgr/scalar"
+	"github.com/uber/peloton/pkg/resmgr/task"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"go.uber.org/multierr"
+)
+
+// timeout for fetching the host reservations from host manager
+const _hostReservationsTimeout = 10 * time.Second
+
+// preemptForHostReservations preempts lower priority tasks running on the
+// hosts which are reserved for high priority tasks, irrespective of the
+// resource pools of those tasks, so that the reservations get fulfilled.
+func (p *Preemptor) preemptForHostReservations() error {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		_hostReservationsTimeout)
+	defer cancel()
+
+	resp, err := p.hostMgrClient.GetHostReservations(
+		ctx,
+		&hostsvc.GetHostReservationsRequest{
+			MinPriority: p.priorityPreemption.MinPriority,
+		})
+	if err != nil {
+		return errors.Wrap(err, "unable to get host reservations")
+	}
+
+	reservations := resp.GetReservations()
+	if len(reservations) == 0 {
+		return nil
+	}
+
+	hostnames := make([]string, 0, len(reservations))
+	for _, res := range reservations {
+		hostnames = append(hostnames, res.GetHostname())
+	}
+	tasksByHost := p.tracker.TasksByHosts(hostnames, resmgr.TaskType_UNKNOWN)
+	preempted := p.getPreemptedInstancesByJob()
+
+	var errs error
+	for _, res := range reservations {
+		tasks := p.selectHostVictims(
+			res.GetTask(),
+			scalar.ConvertToResmgrResource(res.GetShortfall()),
+			tasksByHost[res.GetHostname()],
+			preempted)
+		if len(tasks) == 0 {
+			continue
+		}
+
+		var taskIDs []string
+		for _, t := range tasks {
+			taskIDs = append(taskIDs, t.Task().GetId().GetValue())
+			p.metrics(t.Respool()).PriorityTasksToPreempt.Inc(1)
+		}
+		log.WithFields(log.Fields{
+			"hostname":       res.GetHostname(),
+			"task_id":        res.GetTask().GetId().GetValue(),
+			"priority":       res.GetTask().GetPriority(),
+			"shortfall":      res.GetShortfall(),
+			"tasks_to_evict": taskIDs,
+		}).Info("Preempting tasks on host reserved for higher priority task")
+
+		err := p.processTasks(
+			tasks,
+			resmgr.PreemptionReason_PREEMPTION_REASON_PRIORITY)
+		if err != nil {
+			errs = multierr.Append(errs,
+				errors.Wrapf(err, "unable to preempt tasks on host:%s",
+					res.GetHostname()))
+		}
+	}
+	return errs
+}
+
+// selectHostVictims returns the tasks running on a reserved host which
+// should be preempted to free up the shortfall of the reservation for the
+// given task. Only running preemptible tasks with a lower priority than the
+// task are selected. Tasks in the gang of the minimum running instances of
+// their job are not selected, and no more instances of a job than allowed
+// by the config and the job SLA are preempted at a time. Resources of the tasks on the host which
+// are already being preempted are accounted against the shortfall. Returns
+// no tasks if the shortfall can not be freed up in full, since preempting
+// some of the tasks would not make room for the task.
+func (p *Preemptor) selectHostVictims(
+	t *resmgr.Task,
+	shortfall *scalar.Resources,
+	hostTasks []*task.RMTask,
+	preempted map[string]uint32) []*task.RMTask {
+	maxPerJob := p.priorityPreemption.MaxPreemptedInstancesPerJob
+	if maxPerJob == 0 {
+		maxPerJob = 1
+	}
+
+	var candidates []*task.RMTask
+	for _, hostTask := range hostTasks {
+		if p.isBeingPreempted(hostTask) {
+			shortfall = shortfall.Subtract(
+				scalar.GetTaskResources(hostTask.Task()))
+			continue
+		}
+		if hostTask.GetCurrentState().State != peloton_task.TaskState_RUNNING ||
+			!hostTask.Task().GetPreemptible() ||
+			hostTask.Task().GetMinInstances() > 1 ||
+			hostTask.Task().GetPriority() >= t.GetPriority() {
+			continue
+		}
+		candidates = append(candidates, hostTask)
+	}
+
+	if shortfall.Equal(scalar.ZeroResource) {
+		// the tasks which are being preempted free up enough resources
+		return nil
+	}
+
+	// rank revocable tasks first, then on the task priority and how long
+	// the task has been running
+	sorter := taskSorter{
+		cmpFuncs: []cmpFunc{
+			revocableCmp,
+			priorityCmp,
+			startTimeCmp,
+		},
+	}
+	sorter.Sort(candidates)
+
+	// number of instances selected per job in this round
+	selected := make(map[string]uint32)
+	var victims []*task.RMTask
+	for _, candidate := range candidates {
+		if shortfall.Equal(scalar.ZeroResource) {
+			break
+		}
+		jobID := candidate.Task().GetJobId().GetValue()
+		maxPreempted := maxPerJob
+		maxUnavailable := candidate.Task().GetMaxUnavailableInstances()
+		if maxUnavailable > 0 && maxUnavailable < maxPreempted {
+			maxPreempted = maxUnavailable
+		}
+		if preempted[jobID]+selected[jobID] >= maxPreempted {
+			// preempting the task would violate the SLA of the job
+			continue
+		}
+		taskResources := scalar.GetTaskResources(candidate.Task())
+		remaining := shortfall.Subtract(taskResources)
+		if remaining.Equal(shortfall) {
+			// this task doesn't help with freeing up the shortfall
+			continue
+		}
+		victims = append(victims, candidate)
+		selected[jobID]++
+		shortfall = remaining
+	}
+
+	if !shortfall.Equal(scalar.ZeroResource) {
+		log.WithFields(log.Fields{
+			"task_id":   t.GetId().GetValue(),
+			"shortfall": shortfall.String(),
+		}).Debug("Not enough preemptible tasks on reserved host")
+		return nil
+	}
+
+	for jobID, count := range selected {
+		preempted[jobID] += count
+	}
+	return victims
+}
+
+// isBeingPreempted returns true if the task is in the preemption queue or
+// is being preempted by job manager
+func (p *Preemptor) isBeingPreempted(t *task.RMTask) bool {
+	return p.taskSet.Contains(t.Task().GetId().GetValue()) ||
+		t.GetCurrentState().State == peloton_task.TaskState_PREEMPTING
+}
+
+// getPreemptedInstancesByJob returns the number of instances of each job
+// which are either in the preemption queue or being preempted
+func (p *Preemptor) getPreemptedInstancesByJob() map[string]uint32 {
+	preempted := make(map[string]uint32)
+	for _, taskID := range p.taskSet.ToSlice() {
+		jobID, _, err := util.ParseTaskID(taskID)
+		if err != nil {
+			continue
+		}
+		preempted[jobID]++
+	}
+
+	preemptingTasks := p.tracker.GetActiveTasks(
+		"",
+		"",
+		[]string{peloton_task.TaskState_PREEMPTING.String()})
+	for _, tasks := range preemptingTasks {
+		for _, t := range tasks {
+			preempted[t.Task().GetJobId().GetValue()]++
+		}
+	}
+	return preempted
+}
+
+// compares tasks based on whether they are revocable, revocable tasks
+// are preempted first
+func revocableCmp(t1, t2 *task.RMTask) int {
+	if t1.Task().GetRevocable() == t2.Task().GetRevocable() {
+		return 0
+	}
+	if t1.Task().GetRevocable() {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preemption
+
+import (
+	"fmt"
+	"time"
+
+	mesos "github.com/uber/peloton/.gen/mesos/v1"
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
+	host_mocks "github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc/mocks"
+	"github.com/uber/peloton/.gen/peloton/private/resmgr"
+
+	res_common "github.com/uber/peloton/pkg/resmgr/common"
+	"github.com/uber/peloton/pkg/resmgr/respool/mocks"
+	"github.com/uber/peloton/pkg/resmgr/tasktestutil"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+)
+
+const _reservedHost = "reserved-host"
+
+// returns a host reservation for a task with the given priority
+func createHostReservation(
+	priority uint32,
+	shortfall *task.ResourceConfig) *hostsvc.HostReservation {
+	return &hostsvc.HostReservation{
+		Hostname: _reservedHost,
+		Task: &resmgr.Task{
+			Id:       &peloton.TaskID{Value: uuid.New() + "-0"},
+			Priority: priority,
+			Resource: _taskResources,
+		},
+		Shortfall: shortfall,
+	}
+}
+
+// adds a running task on the reserved host to the tracker
+func (suite *PreemptorTestSuite) addRunningHostTask(
+	jobID string,
+	instance int,
+	priority uint32,
+	preemptible bool,
+	revocable bool) *resmgr.Task {
+	mockResPool := mocks.NewMockResPool(suite.mockCtrl)
+	mockResPool.EXPECT().ID().Return("respool-1").AnyTimes()
+	mockResPool.EXPECT().GetPath().Return("/respool-1").AnyTimes()
+
+	taskID := fmt.Sprintf("%s-%d", jobID, instance)
+	mesosTaskID := fmt.Sprintf("%s-%d", taskID, 1)
+	t := &resmgr.Task{
+		Name:        taskID,
+		Priority:    priority,
+		JobId:       &peloton.JobID{Value: jobID},
+		Id:          &peloton.TaskID{Value: taskID},
+		TaskId:      &mesos.TaskID{Value: &mesosTaskID},
+		Hostname:    _reservedHost,
+		Resource:    _taskResources,
+		Preemptible: preemptible,
+		Revocable:   revocable,
+	}
+	suite.tracker.AddTask(
+		t,
+		suite.eventStreamHandler,
+		mockResPool,
+		tasktestutil.CreateTaskConfig())
+	suite.transitToRunning(t.Id)
+	return t
+}
+
+// sets up the preemptor for priority preemption and returns the
+// mock host manager client
+func (suite *PreemptorTestSuite) setupPriorityPreemption() *host_mocks.MockInternalHostServiceYARPCClient {
+	mockHostMgr := host_mocks.NewMockInternalHostServiceYARPCClient(suite.mockCtrl)
+	suite.preemptor.hostMgrClient = mockHostMgr
+	suite.preemptor.priorityPreemption = res_common.PriorityPreemptionConfig{
+		Enabled:     true,
+		MinPriority: 10,
+	}
+	return mockHostMgr
+}
+
+// returns the IDs of the tasks in the preemption queue
+func (suite *PreemptorTestSuite) dequeuePreemptedTasks() []string {
+	var taskIDs []string
+	for suite.preemptor.preemptionQueue.Length() > 0 {
+		candidate, err := suite.preemptor.DequeueTask(1 * time.Second)
+		suite.NoError(err)
+		suite.Equal(
+			resmgr.PreemptionReason_PREEMPTION_REASON_PRIORITY,
+			candidate.GetReason())
+		taskIDs = append(taskIDs, candidate.GetId().GetValue())
+	}
+	return taskIDs
+}
+
+func (suite *PreemptorTestSuite) TestPreemptForHostReservations() {
+	mockHostMgr := suite.setupPriorityPreemption()
+
+	job1, job2, job3, job4 := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	revocableTask := suite.addRunningHostTask(job1, 0, 1, true, true)
+	// can't be preempted along with the revocable task of the same job
+	suite.addRunningHostTask(job1, 1, 0, true, false)
+	preemptibleTask := suite.addRunningHostTask(job2, 0, 1, true, false)
+	// higher priority than the reserved task
+	suite.addRunningHostTask(job3, 0, 20, true, false)
+	// non-preemptible task
+	suite.addRunningHostTask(job4, 0, 0, false, false)
+
+	mockHostMgr.EXPECT().GetHostReservations(
+		gomock.Any(),
+		&hostsvc.GetHostReservationsRequest{MinPriority: 10}).
+		Return(&hostsvc.GetHostReservationsResponse{
+			Reservations: []*hostsvc.HostReservation{
+				createHostReservation(10, &task.ResourceConfig{CpuLimit: 4}),
+			},
+		}, nil)
+
+	err := suite.preemptor.preemptForHostReservations()
+	suite.NoError(err)
+	suite.ElementsMatch([]string{
+		revocableTask.GetId().GetValue(),
+		preemptibleTask.GetId().GetValue(),
+	}, suite.dequeuePreemptedTasks())
+}
+
+func (suite *PreemptorTestSuite) TestPreemptForHostReservationsNotEnoughTasks() {
+	mockHostMgr := suite.setupPriorityPreemption()
+	suite.addRunningHostTask(uuid.New(), 0, 1, true, false)
+
+	mockHostMgr.EXPECT().GetHostReservations(gomock.Any(), gomock.Any()).
+		Return(&hostsvc.GetHostReservationsResponse{
+			Reservations: []*hostsvc.HostReservation{
+				createHostReservation(10, &task.ResourceConfig{CpuLimit: 4}),
+			},
+		}, nil)
+
+	err := suite.preemptor.preemptForHostReservations()
+	suite.NoError(err)
+	suite.Equal(0, suite.preemptor.preemptionQueue.Length())
+}
+
+func (suite *PreemptorTestSuite) TestPreemptForHostReservationsAlreadyPreempting() {
+	mockHostMgr := suite.setupPriorityPreemption()
+	t1 := suite.addRunningHostTask(uuid.New(), 0, 1, true, false)
+	suite.addRunningHostTask(uuid.New(), 0, 1, true, false)
+	suite.preemptor.taskSet.Add(t1.GetId().GetValue())
+
+	mockHostMgr.EXPECT().GetHostReservations(gomock.Any(), gomock.Any()).
+		Return(&hostsvc.GetHostReservationsResponse{
+			Reservations: []*hostsvc.HostReservation{
+				createHostReservation(10, &task.ResourceConfig{CpuLimit: 2}),
+			},
+		}, nil)
+
+	// the task which is already being preempted frees up the shortfall
+	err := suite.preemptor.preemptForHostReservations()
+	suite.NoError(err)
+	suite.Equal(0, suite.preemptor.preemptionQueue.Length())
+}
+
+func (suite *PreemptorTestSuite) TestPreemptForHostReservationsMaxInstancesPerJob() {
+	mockHostMgr := suite.setupPriorityPreemption()
+	suite.preemptor.priorityPreemption.MaxPreemptedInstancesPerJob = 2
+	jobID := uuid.New()
+	t1 := suite.addRunningHostTask(jobID, 0, 1, true, false)
+	t2 := suite.addRunningHostTask(jobID, 1, 1, true, false)
+
+	mockHostMgr.EXPECT().GetHostReservations(gomock.Any(), gomock.Any()).
+		Return(&hostsvc.GetHostReservationsResponse{
+			Reservations: []*hostsvc.HostReservation{
+				createHostReservation(10, &task.ResourceConfig{CpuLimit: 4}),
+			},
+		}, nil)
+
+	err := suite.preemptor.preemptForHostReservations()
+	suite.NoError(err)
+	suite.ElementsMatch([]string{
+		t1.GetId().GetValue(),
+		t2.GetId().GetValue(),
+	}, suite.dequeuePreemptedTasks())
+}
+
+func (suite *PreemptorTestSuite) TestPreemptForHostReservationsJobSLA() {
+	mockHostMgr := suite.setupPriorityPreemption()
+	suite.preemptor.priorityPreemption.MaxPreemptedInstancesPerJob = 2
+
+	// the job SLA allows only one unavailable instance
+	job1, job2, job3 := uuid.New(), uuid.New(), uuid.New()
+	t1 := suite.addRunningHostTask(job1, 0, 1, true, false)
+	t1.MaxUnavailableInstances = 1
+	t2 := suite.addRunningHostTask(job1, 1, 1, true, false)
+	t2.MaxUnavailableInstances = 1
+	// part of the gang of the minimum running instances of the job
+	gangTask := suite.addRunningHostTask(job2, 0, 1, true, false)
+	gangTask.MinInstances = 2
+	t3 := suite.addRunningHostTask(job3, 0, 1, true, false)
+
+	mockHostMgr.EXPECT().GetHostReservations(gomock.Any(), gomock.Any()).
+		Return(&hostsvc.GetHostReservationsResponse{
+			Reservations: []*hostsvc.HostReservation{
+				createHostReservation(10, &task.ResourceConfig{CpuLimit: 4}),
+			},
+		}, nil)
+
+	err := suite.preemptor.preemptForHostReservations()
+	suite.NoError(err)
+	taskIDs := suite.dequeuePreemptedTasks()
+	suite.Len(taskIDs, 2)
+	suite.Contains(taskIDs, t3.GetId().GetValue())
+	suite.NotContains(taskIDs, gangTask.GetId().GetValue())
+}
+
+func (suite *PreemptorTestSuite) TestPreemptForHostReservationsError() {
+	mockHostMgr := suite.setupPriorityPreemption()
+	mockHostMgr.EXPECT().GetHostReservations(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("unavailable"))
+
+	err := suite.preemptor.preemptForHostReservations()
+	suite.Error(err)
+}
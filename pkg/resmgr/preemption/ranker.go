@@ -162,7 +162,7 @@ func filterTasks(
 			break
 		}
 		// get task resources
-		taskResources := scalar.ConvertToResmgrResource(task.Task().Resource)
+		taskResources := scalar.GetTaskResources(task.Task())
 
 		// check if the task resource helps in satisfying resourceToFree
 		newResourceToFree := resourcesLimit.Subtract(taskResources)
@@ -18,6 +18,7 @@ import (
 	"github.com/uber/peloton/.gen/peloton/private/resmgr"
 	"github.com/uber/peloton/.gen/peloton/private/resmgrsvc"
 
+	"github.com/uber/peloton/pkg/common/gpu"
 	"github.com/uber/peloton/pkg/resmgr/scalar"
 
 	"github.com/pkg/errors"
@@ -35,6 +36,15 @@ var (
 		"skipping non-preemptible gang from admitting")
 	errSkipRevocableGang = errors.New(
 		"skipping revocable gang from admitting")
+
+	errEntitlementExceeded = errors.New(
+		"gang resources exceed resource pool entitlement")
+	errControllerLimitExceeded = errors.New(
+		"gang resources exceed resource pool controller limit")
+	errReservationExceeded = errors.New(
+		"non-preemptible gang resources exceed resource pool reservation")
+	errGPUModelLimitExceeded = errors.New(
+		"gang gpus exceed resource pool gpu model limit or reservation")
 )
 
 // QueueType defines the different queues of the resource pool from which
@@ -85,7 +95,7 @@ func entitlementAdmitter(gang *resmgrsvc.Gang, pool *resPool) bool {
 		currentAllocation = pool.allocation.GetByType(scalar.SlackAllocation)
 	}
 
-	neededResources := scalar.GetGangResources(gang)
+	neededResources := pool.filterUnconfigured(scalar.GetGangResources(gang))
 	log.WithFields(log.Fields{
 		"respool_id":         pool.id,
 		"entitlement":        currentEntitlement,
@@ -115,7 +125,7 @@ func controllerAdmitter(gang *resmgrsvc.Gang, pool *resPool) bool {
 	// check controller limit and allocation
 	controllerLimit := pool.controllerLimit
 	controllerAllocation := pool.allocation.GetByType(scalar.ControllerAllocation)
-	neededResources := scalar.GetGangResources(gang)
+	neededResources := pool.filterUnconfigured(scalar.GetGangResources(gang))
 
 	log.WithFields(log.Fields{
 		"respool_id":         pool.id,
@@ -147,7 +157,7 @@ func reservationAdmitter(gang *resmgrsvc.Gang, pool *resPool) bool {
 	}
 
 	npAllocation := pool.allocation.GetByType(scalar.NonPreemptibleAllocation)
-	neededResources := scalar.GetGangResources(gang)
+	neededResources := pool.filterUnconfigured(scalar.GetGangResources(gang))
 	reservation := pool.reservation
 
 	log.WithFields(log.Fields{
@@ -162,19 +172,84 @@ func reservationAdmitter(gang *resmgrsvc.Gang, pool *resPool) bool {
 		LessThanOrEqual(reservation)
 }
 
+// returns true if the GPUs per GPU model of the gang fit into the limits of
+// the GPU model resource kinds configured for the pool, and for
+// non-preemptible gangs into their reservations if preemption is enabled.
+// The entitlement is only calculated for the aggregate gpu kind, which is
+// checked by the entitlement admitter.
+func gpuModelAdmitter(gang *resmgrsvc.Gang, pool *resPool) bool {
+	if isRevocable(gang) {
+		return true
+	}
+
+	neededGPUs := pool.filterUnconfiguredGPUModels(
+		scalar.GetGangAllocation(gang).GPUModels)[scalar.TotalAllocation]
+	if len(neededGPUs) == 0 {
+		return true
+	}
+
+	checkReservation := pool.isPreemptionEnabled() && !isPreemptible(gang)
+	totalGPUs := pool.allocation.GetGPUModelsByType(scalar.TotalAllocation)
+	npGPUs := pool.allocation.GetGPUModelsByType(scalar.NonPreemptibleAllocation)
+	for model, gpus := range neededGPUs {
+		config := pool.resourceConfigs[gpu.ModelKind(model)]
+
+		log.WithFields(log.Fields{
+			"respool_id":     pool.id,
+			"gpu_model":      model,
+			"limit":          config.GetLimit(),
+			"reservation":    config.GetReservation(),
+			"allocation":     totalGPUs[model],
+			"gpus_required":  gpus,
+			"np_allocation":  npGPUs[model],
+			"np_reservation": checkReservation,
+		}).Debug("checking gpu model limit")
+
+		if config.GetLimit() > 0 &&
+			totalGPUs[model]+gpus > config.GetLimit() {
+			return false
+		}
+		if checkReservation &&
+			npGPUs[model]+gpus > config.GetReservation() {
+			return false
+		}
+	}
+	return true
+}
+
+// namedAdmitter is an admitter along with the error returned when the
+// admitter rejects a gang.
+type namedAdmitter struct {
+	admit admitter
+	err   error
+}
+
 type admissionController struct {
-	admitters []admitter
+	admitters []namedAdmitter
 }
 
 // the global admission controller for all resource pool
 var admission = admissionController{
-	admitters: []admitter{
-		entitlementAdmitter,
-		controllerAdmitter,
-		reservationAdmitter,
+	admitters: []namedAdmitter{
+		{admit: entitlementAdmitter, err: errEntitlementExceeded},
+		{admit: controllerAdmitter, err: errControllerLimitExceeded},
+		{admit: reservationAdmitter, err: errReservationExceeded},
+		{admit: gpuModelAdmitter, err: errGPUModelLimitExceeded},
 	},
 }
 
+// CheckAdmission returns nil if the gang can be admitted to the resource
+// pool right now, otherwise the error of the first admitter which rejects
+// the gang. The resource pool is not changed.
+func (ac admissionController) CheckAdmission(
+	gang *resmgrsvc.Gang,
+	pool *resPool) error {
+	pool.RLock()
+	defer pool.RUnlock()
+
+	return ac.checkAdmitters(gang, pool)
+}
+
 // TryAdmit, tries to admit the gang into the resource pool.
 // Returns an error if there was some error in the admission control
 func (ac admissionController) TryAdmit(
@@ -243,7 +318,8 @@ func (ac admissionController) TryAdmit(
 		return err
 	}
 
-	pool.allocation = pool.allocation.Add(scalar.GetGangAllocation(gang))
+	pool.allocation = pool.allocation.Add(
+		pool.filterUnconfiguredAllocation(scalar.GetGangAllocation(gang)))
 	return nil
 }
 
@@ -365,16 +441,24 @@ func (ac admissionController) validateGang(
 func (ac admissionController) canAdmit(
 	gang *resmgrsvc.Gang,
 	pool *resPool) bool {
+	return ac.checkAdmitters(gang, pool) == nil
+}
+
+// checkAdmitters returns the error of the first admitter which rejects the
+// gang, or nil if all admitters can admit.
+func (ac admissionController) checkAdmitters(
+	gang *resmgrsvc.Gang,
+	pool *resPool) error {
 
 	// loop through the admitters
 	for _, admitter := range ac.admitters {
-		if !admitter(gang, pool) {
+		if !admitter.admit(gang, pool) {
 			// bail out fast
-			return false
+			return admitter.err
 		}
 	}
 	// all admitters can admit
-	return true
+	return nil
 }
 
 // removeGangFromQueue removes a gang from a queue (pending/np/controller/revocable)
@@ -390,10 +474,10 @@ func removeGangFromQueue(
 
 	if !isRevocable(gang) {
 		pool.demand = pool.demand.Subtract(
-			scalar.GetGangResources(gang))
+			pool.filterUnconfigured(scalar.GetGangResources(gang)))
 	} else {
 		pool.slackDemand = pool.slackDemand.Subtract(
-			scalar.GetGangResources(gang))
+			pool.filterUnconfigured(scalar.GetGangResources(gang)))
 	}
 
 	return nil
@@ -412,10 +496,10 @@ func addGangToQueue(
 
 	if !isRevocable(gang) {
 		pool.demand = pool.demand.Add(
-			scalar.GetGangResources(gang))
+			pool.filterUnconfigured(scalar.GetGangResources(gang)))
 	} else {
 		pool.slackDemand = pool.slackDemand.Add(
-			scalar.GetGangResources(gang))
+			pool.filterUnconfigured(scalar.GetGangResources(gang)))
 	}
 	return nil
 }
@@ -175,6 +175,26 @@ func (s *ResPoolSuite) TestBatchAdmissionController_TryAdmitFailure() {
 	s.Equal(float64(0), resPool.GetTotalAllocatedResources().GPU)
 }
 
+func (s *ResPoolSuite) TestCheckAdmission() {
+	pool := s.createTestResourcePool()
+	resPool, ok := pool.(*resPool)
+	s.True(ok)
+
+	task := s.getTasks()[0]
+	gang := makeTaskGang(task)
+
+	// no entitlement yet, gang has to wait
+	s.Equal(errEntitlementExceeded, resPool.CheckAdmission(gang))
+
+	resPool.SetNonSlackEntitlement(s.getEntitlement())
+	s.NoError(resPool.CheckAdmission(gang))
+
+	// the check neither queues the gang nor changes allocation and demand
+	s.Equal(0, resPool.pendingQueue.Size())
+	s.Equal(float64(0), resPool.GetTotalAllocatedResources().CPU)
+	s.Equal(float64(0), resPool.GetDemand().GetCPU())
+}
+
 // Test adds 9 revocable tasks and 2 non-revocable tasks.
 // 8 revocable and 2 non-revocable tasks are admitted based,
 // on their entitlement for the resource pool.
@@ -664,6 +684,97 @@ func (s *ResPoolSuite) TestBatchAdmissionController_NPAdmitter() {
 	}
 }
 
+func (s *ResPoolSuite) TestBatchAdmissionController_GPUModelAdmitter() {
+	poolConfig := &respool.ResourcePoolConfig{
+		Name:   _testResPoolName,
+		Parent: &_rootResPoolID,
+		Resources: append(s.getResources(), &respool.ResourceConfig{
+			Share:       1,
+			Kind:        "gpu.tesla-v100",
+			Reservation: 1,
+			Limit:       2,
+		}),
+		Policy: respool.SchedulingPolicy_PriorityFIFO,
+	}
+
+	gpuTask := func(preemptible bool, gpus float64, model string) *resmgr.Task {
+		rmTask := s.getTasks()[0]
+		rmTask.Preemptible = preemptible
+		rmTask.Resource.GpuLimit = gpus
+		rmTask.Resource.Gpu = &task.GPUConfig{Models: []string{model}}
+		return rmTask
+	}
+
+	tt := []struct {
+		msg         string
+		allocated   float64
+		preemptible bool
+		gpus        float64
+		model       string
+		wantErr     error
+	}{
+		{
+			msg:         "preemptible gang within gpu model limit",
+			preemptible: true,
+			gpus:        2,
+			model:       "tesla-v100",
+		},
+		{
+			msg:         "preemptible gang exceeding gpu model limit",
+			allocated:   1,
+			preemptible: true,
+			gpus:        2,
+			model:       "tesla-v100",
+			wantErr:     errGPUModelLimitExceeded,
+		},
+		{
+			msg:     "non-preemptible gang exceeding gpu model reservation",
+			gpus:    2,
+			model:   "tesla-v100",
+			wantErr: errGPUModelLimitExceeded,
+		},
+		{
+			msg:         "unconfigured gpu model is not enforced",
+			allocated:   2,
+			preemptible: true,
+			gpus:        2,
+			model:       "p100",
+		},
+	}
+
+	for _, t := range tt {
+		rp := s.respoolWithConfig(poolConfig)
+		resPool, ok := rp.(*resPool)
+		s.True(ok)
+		resPool.SetNonSlackEntitlement(s.getEntitlement())
+		resPool.reservation = s.getEntitlement()
+		if t.allocated > 0 {
+			resPool.allocation.GPUModels = map[scalar.AllocationType]map[string]float64{
+				scalar.TotalAllocation: {"tesla-v100": t.allocated},
+			}
+		}
+
+		gang := makeTaskGang(gpuTask(t.preemptible, t.gpus, t.model))
+		s.Equal(t.wantErr, resPool.CheckAdmission(gang), t.msg)
+	}
+
+	// the gpus of an admitted gang are accounted for per gpu model
+	rp := s.respoolWithConfig(poolConfig)
+	resPool, ok := rp.(*resPool)
+	s.True(ok)
+	resPool.SetNonSlackEntitlement(s.getEntitlement())
+
+	gang := makeTaskGang(gpuTask(true, 2, "tesla-v100"))
+	s.NoError(resPool.pendingQueue.Enqueue(gang))
+	s.NoError(admission.TryAdmit(gang, resPool, PendingQueue))
+	s.Equal(map[string]float64{"tesla-v100": 2},
+		resPool.allocation.GetGPUModelsByType(scalar.TotalAllocation))
+	s.Equal("gpu.tesla-v100",
+		resPool.ToResourcePoolInfo().GetUsage()[4].GetKind())
+	s.Equal(2.0,
+		resPool.ToResourcePoolInfo().GetUsage()[4].GetAllocation())
+}
+
 func assertFailedAdmission(s *ResPoolSuite, resPool *resPool,
 	controller bool, preemptible bool) {
 	// gang resources shouldn't account for respool allocation
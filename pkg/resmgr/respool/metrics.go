@@ -48,6 +48,10 @@ type Metrics struct {
 	QueryResourcePoolsSuccess tally.Counter
 	QueryResourcePoolsFail    tally.Counter
 
+	APIGetCapacityForecast     tally.Counter
+	GetCapacityForecastSuccess tally.Counter
+	GetCapacityForecastFail    tally.Counter
+
 	PendingQueueSize    tally.Gauge
 	RevocableQueueSize  tally.Gauge
 	ControllerQueueSize tally.Gauge
@@ -122,6 +126,10 @@ func NewMetrics(scope tally.Scope) *Metrics {
 		QueryResourcePoolsSuccess: successScope.Counter("query_resource_pools"),
 		QueryResourcePoolsFail:    failScope.Counter("query_resource_pools"),
 
+		APIGetCapacityForecast:     apiScope.Counter("get_capacity_forecast"),
+		GetCapacityForecastSuccess: successScope.Counter("get_capacity_forecast"),
+		GetCapacityForecastFail:    failScope.Counter("get_capacity_forecast"),
+
 		PendingQueueSize:    queueScope.Gauge("pending_queue_size"),
 		RevocableQueueSize:  queueScope.Gauge("revocable_queue_size"),
 		ControllerQueueSize: queueScope.Gauge("controller_queue_size"),
@@ -17,6 +17,7 @@ package respool
 import (
 	"container/list"
 	"math"
+	"sort"
 	"sync"
 
 	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
@@ -24,6 +25,7 @@ import (
 	"github.com/uber/peloton/.gen/peloton/private/resmgrsvc"
 
 	"github.com/uber/peloton/pkg/common"
+	"github.com/uber/peloton/pkg/common/gpu"
 	rc "github.com/uber/peloton/pkg/resmgr/common"
 	"github.com/uber/peloton/pkg/resmgr/queue"
 	"github.com/uber/peloton/pkg/resmgr/scalar"
@@ -86,6 +88,10 @@ type ResPool interface {
 	EnqueueGang(gang *resmgrsvc.Gang) error
 	// Dequeues gangs (task list) from the resource pool.
 	DequeueGangs(int) ([]*resmgrsvc.Gang, error)
+	// CheckAdmission returns nil if the gang can be admitted to the
+	// resource pool right now, or the reason it can't otherwise.
+	// The resource pool is not changed.
+	CheckAdmission(gang *resmgrsvc.Gang) error
 	// PeekGangs returns a list of gangs from the resource pool's queue based
 	// on the queue type. limit determines the max number of gangs to be
 	// returned.
@@ -463,6 +469,15 @@ func (n *resPool) DequeueGangs(limit int) ([]*resmgrsvc.Gang, error) {
 	return gangList, err
 }
 
+// CheckAdmission returns nil if the gang can be admitted to the resource
+// pool right now, or the reason it can't otherwise.
+func (n *resPool) CheckAdmission(gang *resmgrsvc.Gang) error {
+	if !n.isLeaf() {
+		return errors.Errorf("resource pool %s is not a leaf node", n.id)
+	}
+	return admission.CheckAdmission(gang, n)
+}
+
 // dequeues limit number of gangs from the respool for admission.
 func (n *resPool) dequeue(
 	qt QueueType,
@@ -565,9 +580,11 @@ func (n *resPool) ToResourcePoolInfo() *respool.ResourcePoolInfo {
 		Config:   n.poolConfig,
 		Children: childrenResourcePoolIDs,
 		Path:     &respool.ResourcePoolPath{Value: n.path},
-		Usage: n.createRespoolUsage(
-			n.allocation.GetByType(scalar.TotalAllocation),
-			n.allocation.GetByType(scalar.SlackAllocation)),
+		Usage: append(
+			n.createRespoolUsage(
+				n.allocation.GetByType(scalar.TotalAllocation),
+				n.allocation.GetByType(scalar.SlackAllocation)),
+			n.createGPUModelUsage()...),
 	}
 }
 
@@ -668,7 +685,7 @@ func (n *resPool) queue(qt QueueType) queue.Queue {
 func (n *resPool) createRespoolUsage(
 	allocation *scalar.Resources,
 	slackAllocation *scalar.Resources) []*respool.ResourceUsage {
-	resUsage := make([]*respool.ResourceUsage, 0, 4)
+	resUsage := make([]*respool.ResourceUsage, 0, 6)
 	ru := &respool.ResourceUsage{
 		Kind:       common.CPU,
 		Allocation: allocation.CPU - slackAllocation.CPU,
@@ -693,9 +710,92 @@ func (n *resPool) createRespoolUsage(
 		Slack:      slackAllocation.DISK,
 	}
 	resUsage = append(resUsage, ru)
+	for _, kind := range scalar.OptionalKinds {
+		if _, ok := n.resourceConfigs[kind]; !ok {
+			continue
+		}
+		ru = &respool.ResourceUsage{
+			Kind:       kind,
+			Allocation: allocation.Get(kind) - slackAllocation.Get(kind),
+			Slack:      slackAllocation.Get(kind),
+		}
+		resUsage = append(resUsage, ru)
+	}
+	return resUsage
+}
+
+// createGPUModelUsage returns the usage of the GPU model resource kinds
+// configured for this pool, sorted by kind. GPUs are never revocable.
+func (n *resPool) createGPUModelUsage() []*respool.ResourceUsage {
+	var resUsage []*respool.ResourceUsage
+	allocation := n.allocation.GetGPUModelsByType(scalar.TotalAllocation)
+	for kind := range n.resourceConfigs {
+		model, ok := gpu.ModelFromKind(kind)
+		if !ok {
+			continue
+		}
+		resUsage = append(resUsage, &respool.ResourceUsage{
+			Kind:       kind,
+			Allocation: allocation[model],
+		})
+	}
+	sort.Slice(resUsage, func(i, j int) bool {
+		return resUsage[i].GetKind() < resUsage[j].GetKind()
+	})
 	return resUsage
 }
 
+// filterUnconfigured returns a copy of the resources with the optional
+// resource kinds which are not configured for this pool set to zero, so
+// that they are neither accounted for nor enforced in this pool.
+func (n *resPool) filterUnconfigured(res *scalar.Resources) *scalar.Resources {
+	if res == nil {
+		return nil
+	}
+	filtered := res.Clone()
+	for _, kind := range scalar.OptionalKinds {
+		if _, ok := n.resourceConfigs[kind]; !ok {
+			filtered.Set(kind, 0)
+		}
+	}
+	return filtered
+}
+
+// filterUnconfiguredAllocation filters the optional resource kinds which are
+// not configured for this pool across all the allocation dimensions.
+func (n *resPool) filterUnconfiguredAllocation(
+	allocation *scalar.Allocation) *scalar.Allocation {
+	filtered := scalar.NewAllocation()
+	for allocationType, res := range allocation.Value {
+		filtered.Value[allocationType] = n.filterUnconfigured(res)
+	}
+	filtered.GPUModels = n.filterUnconfiguredGPUModels(allocation.GPUModels)
+	return filtered
+}
+
+// filterUnconfiguredGPUModels returns a copy of the GPUs per model with only
+// the GPU models which are configured as resource kinds for this pool.
+func (n *resPool) filterUnconfiguredGPUModels(
+	models map[scalar.AllocationType]map[string]float64,
+) map[scalar.AllocationType]map[string]float64 {
+	var filtered map[scalar.AllocationType]map[string]float64
+	for allocationType, gpus := range models {
+		for model, value := range gpus {
+			if _, ok := n.resourceConfigs[gpu.ModelKind(model)]; !ok {
+				continue
+			}
+			if filtered == nil {
+				filtered = make(map[scalar.AllocationType]map[string]float64)
+			}
+			if filtered[allocationType] == nil {
+				filtered[allocationType] = make(map[string]float64)
+			}
+			filtered[allocationType][model] = value
+		}
+	}
+	return filtered
+}
+
 // isLeaf checks if the current resource pool has child resource or not.
 func (n *resPool) isLeaf() bool {
 	return n.children.Len() == 0
@@ -722,6 +822,10 @@ func (n *resPool) initReservation(cfg *respool.ResourcePoolConfig) {
 			n.reservation.GPU = res.Reservation
 		case common.DISK:
 			n.reservation.DISK = res.Reservation
+		case common.PORTS:
+			n.reservation.PORTS = res.Reservation
+		case common.NETWORK:
+			n.reservation.NETWORK = res.Reservation
 		}
 	}
 	log.WithField("reservation", n.reservation).
@@ -730,6 +834,7 @@ func (n *resPool) initReservation(cfg *respool.ResourcePoolConfig) {
 }
 
 func (n *resPool) initResConfig(cfg *respool.ResourcePoolConfig) {
+	n.resourceConfigs = make(map[string]*respool.ResourceConfig)
 	for _, res := range cfg.Resources {
 		n.resourceConfigs[res.Kind] = res
 	}
@@ -755,6 +860,10 @@ func (n *resPool) initControllerLimit(cfg *respool.ResourcePoolConfig) {
 			controllerLimit.GPU = res.Reservation * multiplier
 		case common.DISK:
 			controllerLimit.DISK = res.Reservation * multiplier
+		case common.PORTS:
+			controllerLimit.PORTS = res.Reservation * multiplier
+		case common.NETWORK:
+			controllerLimit.NETWORK = res.Reservation * multiplier
 		}
 	}
 	n.controllerLimit = controllerLimit
@@ -785,6 +894,10 @@ func (n *resPool) initSlackLimit(cfg *respool.ResourcePoolConfig) {
 			slackLimit.MEMORY = res.Reservation * multiplier
 		case common.DISK:
 			slackLimit.DISK = res.Reservation * multiplier
+		case common.PORTS:
+			slackLimit.PORTS = res.Reservation * multiplier
+		case common.NETWORK:
+			slackLimit.NETWORK = res.Reservation * multiplier
 		}
 	}
 	n.slackLimit = slackLimit
@@ -903,7 +1016,8 @@ func (n *resPool) SubtractFromAllocation(allocation *scalar.Allocation) error {
 	n.Lock()
 	defer n.Unlock()
 
-	newAllocation := n.allocation.Subtract(allocation)
+	newAllocation := n.allocation.Subtract(
+		n.filterUnconfiguredAllocation(allocation))
 
 	if newAllocation == nil {
 		return errors.Errorf("couldn't update the resources")
@@ -967,7 +1081,7 @@ func (n *resPool) AddToAllocation(allocation *scalar.Allocation) error {
 	n.Lock()
 	defer n.Unlock()
 
-	n.allocation = n.allocation.Add(allocation)
+	n.allocation = n.allocation.Add(n.filterUnconfiguredAllocation(allocation))
 
 	log.WithFields(log.Fields{
 		"respool_id": n.id,
@@ -990,7 +1104,7 @@ func (n *resPool) AddToDemand(res *scalar.Resources) error {
 	n.Lock()
 	defer n.Unlock()
 
-	n.demand = n.demand.Add(res)
+	n.demand = n.demand.Add(n.filterUnconfigured(res))
 
 	log.WithFields(log.Fields{
 		"respool_id": n.id,
@@ -1006,7 +1120,7 @@ func (n *resPool) AddToSlackDemand(res *scalar.Resources) error {
 	n.Lock()
 	defer n.Unlock()
 
-	n.slackDemand = n.slackDemand.Add(res)
+	n.slackDemand = n.slackDemand.Add(n.filterUnconfigured(res))
 
 	log.WithFields(log.Fields{
 		"respool_id": n.id,
@@ -1022,7 +1136,7 @@ func (n *resPool) SubtractFromDemand(res *scalar.Resources) error {
 	n.Lock()
 	defer n.Unlock()
 
-	newDemand := n.demand.Subtract(res)
+	newDemand := n.demand.Subtract(n.filterUnconfigured(res))
 	if newDemand == nil {
 		return errors.Errorf("Couldn't update the resources")
 	}
@@ -1043,7 +1157,7 @@ func (n *resPool) SubtractFromSlackDemand(res *scalar.Resources) error {
 	n.Lock()
 	defer n.Unlock()
 
-	newDemand := n.slackDemand.Subtract(res)
+	newDemand := n.slackDemand.Subtract(n.filterUnconfigured(res))
 	if newDemand == nil {
 		return errors.Errorf("Couldn't update the resources")
 	}
@@ -1172,6 +1286,10 @@ func getLimits(resourceConfigs map[string]*respool.ResourceConfig) *scalar.Resou
 			resources.MEMORY = res.Limit
 		case common.DISK:
 			resources.DISK = res.Limit
+		case common.PORTS:
+			resources.PORTS = res.Limit
+		case common.NETWORK:
+			resources.NETWORK = res.Limit
 		}
 	}
 	return &resources
@@ -1189,6 +1307,10 @@ func getShare(resourceConfigs map[string]*respool.ResourceConfig) *scalar.Resour
 			resources.MEMORY = res.Share
 		case common.DISK:
 			resources.DISK = res.Share
+		case common.PORTS:
+			resources.PORTS = res.Share
+		case common.NETWORK:
+			resources.NETWORK = res.Share
 		}
 	}
 	return &resources
@@ -546,6 +546,66 @@ func (s *ResPoolSuite) TestAllocation() {
 	s.Equal(float64(0), resourceAlloc.GPU)
 }
 
+// TestOptionalKindsAccounting tests that ports and network are only
+// accounted for in the resource pools which configure them.
+func (s *ResPoolSuite) TestOptionalKindsAccounting() {
+	alloc := scalar.GetTaskAllocation(&resmgr.Task{
+		Resource: &task.ResourceConfig{
+			CpuLimit:    1,
+			NetworkMbps: 100,
+		},
+		NumPorts: 2,
+	})
+
+	// pool without ports and network configured
+	resPoolNode := s.createTestResourcePool()
+	s.NoError(resPoolNode.AddToAllocation(alloc))
+	s.NoError(resPoolNode.AddToDemand(alloc.GetByType(scalar.TotalAllocation)))
+	total := resPoolNode.GetTotalAllocatedResources()
+	s.Equal(float64(1), total.CPU)
+	s.Equal(float64(0), total.PORTS)
+	s.Equal(float64(0), total.NETWORK)
+	s.Equal(float64(0), resPoolNode.GetDemand().PORTS)
+	s.Equal(float64(0), resPoolNode.GetDemand().NETWORK)
+
+	// pool with ports and network configured
+	resources := append(s.getResources(),
+		&pb_respool.ResourceConfig{
+			Share:       1,
+			Kind:        common.PORTS,
+			Reservation: 10,
+			Limit:       100,
+		},
+		&pb_respool.ResourceConfig{
+			Share:       1,
+			Kind:        common.NETWORK,
+			Reservation: 1000,
+			Limit:       10000,
+		})
+	resPoolNode, err := NewRespool(tally.NoopScope, uuid.New(), s.root,
+		&pb_respool.ResourcePoolConfig{
+			Name:      _testResPoolName,
+			Parent:    &_rootResPoolID,
+			Resources: resources,
+			Policy:    pb_respool.SchedulingPolicy_PriorityFIFO,
+		}, s.cfg)
+	s.NoError(err)
+	limits := getLimits(resPoolNode.Resources())
+	s.Equal(float64(100), limits.PORTS)
+	s.Equal(float64(10000), limits.NETWORK)
+
+	s.NoError(resPoolNode.AddToAllocation(alloc))
+	total = resPoolNode.GetTotalAllocatedResources()
+	s.Equal(float64(1), total.CPU)
+	s.Equal(float64(2), total.PORTS)
+	s.Equal(float64(100), total.NETWORK)
+
+	s.NoError(resPoolNode.SubtractFromAllocation(alloc))
+	total = resPoolNode.GetTotalAllocatedResources()
+	s.Equal(float64(0), total.PORTS)
+	s.Equal(float64(0), total.NETWORK)
+}
+
 func (s *ResPoolSuite) TestCalculateAllocation() {
 	rootID := peloton.ResourcePoolID{Value: "root"}
 	respool1ID := peloton.ResourcePoolID{Value: "respool1"}
@@ -23,6 +23,8 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/uber/peloton/pkg/common"
+	"github.com/uber/peloton/pkg/common/gpu"
+	"github.com/uber/peloton/pkg/resmgr/scalar"
 )
 
 // Validator performs validations on the resource config pool
@@ -113,17 +115,14 @@ func ValidateParent(resTree Tree, resourcePoolConfigData ResourcePoolConfigData)
 		return errors.WithStack(err)
 	}
 
-	// for existing resource pool check if parent changed
-	if existingResourcePool != nil {
-		existingParentID := existingResourcePool.Parent().ID()
-
-		// avoid overriding child's parent
-		if newParentID.Value != existingParentID {
-			return errors.Errorf(
-				"parent override not allowed, actual %s, override %s",
-				existingParentID,
-				newParentID.Value)
-		}
+	// a leaf pool which already has tasks admitted or pending can't become
+	// a parent, since only leaf pools hold tasks.
+	isChild := existingResourcePool != nil &&
+		existingResourcePool.Parent().ID() == newParentID.Value
+	if !isChild && parent.IsLeaf() && hasTasks(parent) {
+		return errors.Errorf(
+			"parent %s is a leaf resource pool with tasks",
+			newParentID.Value)
 	}
 
 	// get parent resources
@@ -171,9 +170,10 @@ func ValidateSiblings(resTree Tree, resourcePoolConfigData ResourcePoolConfigDat
 		siblingNames[sibling.Name()] = true
 	}
 	existingResPool, _ := resTree.Get(resourcePoolID)
-	if existingResPool != nil {
+	if existingResPool != nil &&
+		existingResPool.Parent().ID() == parentID.GetValue() {
 		// In case of update API, we need to remove the existing node before
-		// performing the check
+		// performing the check, unless it is being moved to a new parent
 		delete(siblingNames, existingResPool.Name())
 	}
 	log.WithField("siblingNames", siblingNames).
@@ -219,7 +219,9 @@ func ValidateChildrenReservations(resTree Tree, resourcePoolConfigData ResourceP
 		}
 
 		// remove self reservations if we are updating resource pool config
-		if existingResPool != nil {
+		// without moving it to a new parent
+		if existingResPool != nil &&
+			existingResPool.Parent().ID() == parentID.GetValue() {
 
 			if existingResourceConfig, ok := existingResPool.Resources()[cResource.Kind]; ok {
 				cResourceReservations -= existingResourceConfig.Reservation
@@ -277,16 +279,18 @@ func ValidateResourcePool(_ Tree,
 	}
 
 	resconfigSet := map[string]bool{
-		common.CPU:    false,
-		common.GPU:    false,
-		common.MEMORY: false,
-		common.DISK:   false,
+		common.CPU:     false,
+		common.GPU:     false,
+		common.MEMORY:  false,
+		common.DISK:    false,
+		common.PORTS:   false,
+		common.NETWORK: false,
 	}
 	cResources := resPoolConfig.Resources
 	for _, cResource := range cResources {
 		kind := cResource.Kind
 		configed, ok := resconfigSet[kind]
-		if !ok {
+		if _, isGPUModel := gpu.ModelFromKind(kind); !ok && !isGPUModel {
 			return errors.Errorf("resource pool config has unknown resource type %s", kind)
 		}
 		if configed {
@@ -316,7 +320,9 @@ func ValidateResourcePool(_ Tree,
 
 	resUpdated := false
 	for k, set := range resconfigSet {
-		if !set {
+		// optional resource kinds are only accounted for in the resource
+		// pools which configure them, so they are not set to defaults.
+		if !set && !scalar.IsOptionalKind(k) {
 			log.WithFields(log.Fields{
 				"Respool":     ID.Value,
 				"Kind":        k,
@@ -340,7 +346,7 @@ func ValidateResourcePool(_ Tree,
 }
 
 // ValidateCycle if adding/updating current pool would result in a cycle
-func ValidateCycle(_ Tree,
+func ValidateCycle(resTree Tree,
 	resourcePoolConfigData ResourcePoolConfigData) error {
 	resPoolConfig := resourcePoolConfigData.ResourcePoolConfig
 	ID := resourcePoolConfigData.ID
@@ -355,9 +361,37 @@ func ValidateCycle(_ Tree,
 			ID.Value,
 			parentID.Value)
 	}
+
+	if resTree == nil {
+		return nil
+	}
+
+	// check if the new parent is a descendant of the current pool, which
+	// is only possible when an existing pool is moved.
+	parent, err := resTree.Get(parentID)
+	if err != nil {
+		// missing parent is reported by ValidateParent
+		return nil
+	}
+	for p := parent; p != nil; p = p.Parent() {
+		if p.ID() == ID.Value {
+			return errors.Errorf(
+				"resource pool ID: %s cannot be moved under its "+
+					"descendant %s",
+				ID.Value,
+				parentID.Value)
+		}
+	}
 	return nil
 }
 
+// hasTasks returns true if the resource pool has tasks which are either
+// admitted (allocation) or waiting to be admitted (demand).
+func hasTasks(resPool ResPool) bool {
+	return !resPool.GetTotalAllocatedResources().Equal(scalar.ZeroResource) ||
+		!resPool.GetDemand().Equal(scalar.ZeroResource)
+}
+
 // ValidateResourcePoolPath validates the resource pool path
 func ValidateResourcePoolPath(_ Tree,
 	resourcePoolConfigData ResourcePoolConfigData) error {
@@ -23,6 +23,7 @@ import (
 	pb_respool "github.com/uber/peloton/.gen/peloton/api/v0/respool"
 
 	"github.com/uber/peloton/pkg/common"
+	"github.com/uber/peloton/pkg/resmgr/scalar"
 	store_mocks "github.com/uber/peloton/pkg/storage/mocks"
 
 	"github.com/golang/mock/gomock"
@@ -271,6 +272,36 @@ func (s *resPoolConfigValidatorSuite) TestValidateCycle() {
 		"ID: respool33 cannot be same")
 }
 
+func (s *resPoolConfigValidatorSuite) TestValidateCycleMoveUnderDescendant() {
+	mockResourcePoolID := &peloton.ResourcePoolID{
+		Value: "respool2",
+	}
+	mockParentPoolID := &peloton.ResourcePoolID{
+		Value: "respool23",
+	}
+
+	mockResourcePoolConfig := &pb_respool.ResourcePoolConfig{
+		Parent:    mockParentPoolID,
+		Resources: s.getResourceConfig(),
+		Policy:    pb_respool.SchedulingPolicy_PriorityFIFO,
+		Name:      mockResourcePoolID.Value,
+	}
+
+	resourcePoolConfigData := ResourcePoolConfigData{
+		ID:                 mockResourcePoolID,
+		ResourcePoolConfig: mockResourcePoolConfig,
+	}
+
+	rv := &resourcePoolConfigValidator{resTree: s.resourceTree}
+	_, err := rv.Register(
+		[]ResourcePoolConfigValidatorFunc{ValidateCycle})
+	s.NoError(err)
+
+	err = rv.Validate(resourcePoolConfigData)
+	s.EqualError(err, "resource pool ID: respool2 cannot be moved under "+
+		"its descendant respool23")
+}
+
 func (s *resPoolConfigValidatorSuite) TestValidateParentLookupError() {
 	mockResourcePoolID := &peloton.ResourcePoolID{
 		Value: "respool33",
@@ -338,9 +369,46 @@ func (s *resPoolConfigValidatorSuite) TestValidateParentChanged() {
 	s.NoError(err)
 
 	err = rv.Validate(resourcePoolConfigData)
+	s.NoError(err)
+}
 
-	s.EqualError(err, "parent override not allowed, "+
-		"actual root, override respool2")
+func (s *resPoolConfigValidatorSuite) TestValidateParentLeafWithTasks() {
+	mockResourcePoolID := &peloton.ResourcePoolID{
+		Value: "respool33",
+	}
+	mockParentPoolID := &peloton.ResourcePoolID{
+		Value: "respool11",
+	}
+
+	mockResourcePoolConfig := &pb_respool.ResourcePoolConfig{
+		Parent: mockParentPoolID,
+		Resources: []*pb_respool.ResourceConfig{
+			{
+				Reservation: 50,
+				Kind:        "cpu",
+				Limit:       100,
+				Share:       2,
+			},
+		},
+		Policy: pb_respool.SchedulingPolicy_PriorityFIFO,
+		Name:   mockResourcePoolID.Value,
+	}
+
+	resourcePoolConfigData := ResourcePoolConfigData{
+		ID:                 mockResourcePoolID,
+		ResourcePoolConfig: mockResourcePoolConfig,
+	}
+	rv := &resourcePoolConfigValidator{resTree: s.resourceTree}
+	_, err := rv.Register(
+		[]ResourcePoolConfigValidatorFunc{ValidateParent})
+	s.NoError(err)
+
+	parent, err := s.resourceTree.Get(mockParentPoolID)
+	s.NoError(err)
+	s.NoError(parent.AddToDemand(&scalar.Resources{CPU: 1}))
+
+	err = rv.Validate(resourcePoolConfigData)
+	s.EqualError(err, "parent respool11 is a leaf resource pool with tasks")
 }
 
 func (s *resPoolConfigValidatorSuite) TestValidateParentExceedLimit() {
@@ -454,6 +522,47 @@ func (s *resPoolConfigValidatorSuite) TestValidateChildrenReservationsError() {
 	)
 }
 
+func (s *resPoolConfigValidatorSuite) TestValidateChildrenReservationsMove() {
+	mockResourcePoolID := &peloton.ResourcePoolID{
+		Value: "respool23",
+	}
+	mockParentPoolID := &peloton.ResourcePoolID{
+		Value: "respool21",
+	}
+
+	mockResourcePoolConfig := &pb_respool.ResourcePoolConfig{
+		Parent: mockParentPoolID,
+		Resources: []*pb_respool.ResourceConfig{
+			{
+				Reservation: 51,
+				Kind:        "cpu",
+				Limit:       100,
+				Share:       1,
+			},
+		},
+		Policy: pb_respool.SchedulingPolicy_PriorityFIFO,
+		Name:   mockResourcePoolID.Value,
+	}
+
+	resourcePoolConfigData := ResourcePoolConfigData{
+		ID:                 mockResourcePoolID,
+		ResourcePoolConfig: mockResourcePoolConfig,
+	}
+	rv := &resourcePoolConfigValidator{resTree: s.resourceTree}
+	_, err := rv.Register(
+		[]ResourcePoolConfigValidatorFunc{ValidateChildrenReservations})
+	s.NoError(err)
+
+	// the reservation of the moved pool is not yet accounted for under the
+	// new parent, so it should not be subtracted.
+	err = rv.Validate(resourcePoolConfigData)
+	s.EqualError(
+		err,
+		"Aggregated child reservation 101 of kind `cpu` "+
+			"exceed parent `respool21` reservations 100",
+	)
+}
+
 func (s *resPoolConfigValidatorSuite) TestRootValidationReservations() {
 	mockResourcePoolID := &peloton.ResourcePoolID{
 		Value: "respool3",
@@ -876,6 +985,34 @@ func (s *resPoolConfigValidatorSuite) TestValidateResourcePoolOnWrongResources()
 			},
 			expectedErr: "resource pool config has unknown resource type aaa",
 		},
+		{
+			resources: []*pb_respool.ResourceConfig{
+				{
+					Reservation: 5,
+					Kind:        "gpu.",
+					Limit:       10,
+					Share:       2,
+				},
+			},
+			expectedErr: "resource pool config has unknown resource type gpu.",
+		},
+		{
+			resources: []*pb_respool.ResourceConfig{
+				{
+					Reservation: 5,
+					Kind:        "gpu.tesla-v100",
+					Limit:       10,
+					Share:       2,
+				},
+				{
+					Reservation: 6,
+					Kind:        "gpu.tesla-v100",
+					Limit:       10,
+					Share:       2,
+				},
+			},
+			expectedErr: "resource pool config has multiple configurations for resource type gpu.tesla-v100",
+		},
 	}
 	for _, t := range test {
 		err := s.validateOnWrongResources(t.resources)
@@ -884,6 +1021,43 @@ func (s *resPoolConfigValidatorSuite) TestValidateResourcePoolOnWrongResources()
 	}
 }
 
+// TestValidateResourcePoolGPUModel tests that GPU model resource kinds are
+// accepted and not set to defaults.
+func (s *resPoolConfigValidatorSuite) TestValidateResourcePoolGPUModel() {
+	config := &pb_respool.ResourcePoolConfig{
+		Parent: &peloton.ResourcePoolID{Value: "respoolp"},
+		Resources: []*pb_respool.ResourceConfig{
+			{
+				Reservation: 2,
+				Kind:        "gpu.tesla-v100",
+				Limit:       4,
+				Share:       1,
+			},
+		},
+		Policy: pb_respool.SchedulingPolicy_PriorityFIFO,
+		Name:   "respoolc",
+	}
+
+	s.NoError(ValidateResourcePool(s.resourceTree, ResourcePoolConfigData{
+		ID:                 &peloton.ResourcePoolID{Value: "respoolc"},
+		ResourcePoolConfig: config,
+	}))
+
+	// cpu, gpu, memory and disk are set to defaults, the gpu model kind is
+	// kept as configured
+	kinds := map[string]float64{}
+	for _, res := range config.GetResources() {
+		kinds[res.GetKind()] = res.GetLimit()
+	}
+	s.Equal(map[string]float64{
+		"cpu":            _defaultLimit,
+		"gpu":            _defaultLimit,
+		"memory":         _defaultLimit,
+		"disk":           _defaultLimit,
+		"gpu.tesla-v100": 4,
+	}, kinds)
+}
+
 func TestResPoolConfigValidator(t *testing.T) {
 	suite.Run(t, new(resPoolConfigValidatorSuite))
 }
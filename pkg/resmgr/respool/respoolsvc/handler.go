@@ -23,6 +23,7 @@ import (
 
 	"github.com/uber/peloton/pkg/common"
 	rc "github.com/uber/peloton/pkg/resmgr/common"
+	"github.com/uber/peloton/pkg/resmgr/forecast"
 	res "github.com/uber/peloton/pkg/resmgr/respool"
 	"github.com/uber/peloton/pkg/resmgr/scalar"
 	"github.com/uber/peloton/pkg/storage"
@@ -52,6 +53,7 @@ type ServiceHandler struct {
 
 	resPoolTree            res.Tree
 	resPoolConfigValidator res.Validator
+	forecaster             forecast.Forecaster
 }
 
 // InitServiceHandler returns a new handler for ResourcePoolService.
@@ -60,6 +62,7 @@ func InitServiceHandler(
 	parent tally.Scope,
 	tree res.Tree,
 	store storage.ResourcePoolStore,
+	forecaster forecast.Forecaster,
 ) *ServiceHandler {
 
 	scope := parent.SubScope("respool")
@@ -81,6 +84,7 @@ func InitServiceHandler(
 		resPoolTree:            tree,
 		resPoolConfigValidator: resPoolConfigValidator,
 		store:                  store,
+		forecaster:             forecaster,
 	}
 
 	d.Register(respool.BuildResourceManagerYARPCProcedures(handler))
@@ -135,9 +139,6 @@ func (h *ServiceHandler) CreateResourcePool(
 		}, nil
 	}
 
-	// TODO Handle parent of the new_resource_pool_config
-	// already has tasks added running, drain, distinguish?
-
 	// insert persistent store.
 	if err := h.store.CreateResourcePool(ctx, resPoolID, resPoolConfig, "peloton"); err != nil {
 		h.metrics.CreateResourcePoolFail.Inc(1)
@@ -609,3 +610,32 @@ func (h *ServiceHandler) Query(
 	log.WithField("response", resp).Debug("Query returned")
 	return resp, nil
 }
+
+// GetCapacityForecast returns the capacity history, the forecast and the
+// alerts of a resource pool, or of the cluster if the resource pool is
+// not set.
+func (h *ServiceHandler) GetCapacityForecast(
+	ctx context.Context,
+	req *respool.GetCapacityForecastRequest) (
+	*respool.GetCapacityForecastResponse,
+	error) {
+
+	h.metrics.APIGetCapacityForecast.Inc(1)
+	log.WithField(
+		"request",
+		req,
+	).Debug("GetCapacityForecast called")
+
+	resp, err := h.forecaster.GetForecast(req.GetId())
+	if err != nil {
+		h.metrics.GetCapacityForecastFail.Inc(1)
+		log.WithError(err).
+			WithField("respool_id", req.GetId().GetValue()).
+			Info("GetCapacityForecast failed")
+		return nil, err
+	}
+
+	h.metrics.GetCapacityForecastSuccess.Inc(1)
+	log.WithField("response", resp).Debug("GetCapacityForecast returned")
+	return resp, nil
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/uber/peloton/pkg/common"
 	rc "github.com/uber/peloton/pkg/resmgr/common"
+	forecast_mocks "github.com/uber/peloton/pkg/resmgr/forecast/mocks"
 	res "github.com/uber/peloton/pkg/resmgr/respool"
 	"github.com/uber/peloton/pkg/resmgr/respool/mocks"
 	"github.com/uber/peloton/pkg/resmgr/scalar"
@@ -34,6 +35,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"github.com/uber-go/tally"
 	"go.uber.org/yarpc"
+	"go.uber.org/yarpc/yarpcerrors"
 )
 
 type resPoolHandlerTestSuite struct {
@@ -233,6 +235,7 @@ func (s *resPoolHandlerTestSuite) TestNewServiceHandler() {
 		tally.NoopScope,
 		s.resourceTree,
 		s.mockResPoolStore,
+		forecast_mocks.NewMockForecaster(s.mockCtrl),
 	)
 	s.NotNil(handler)
 }
@@ -952,6 +955,49 @@ func (s *resPoolHandlerTestSuite) DeleteResourcePoolStoreError(resTree *mocks.Mo
 	s.mockResPoolStore.EXPECT().DeleteResourcePool(gomock.Any(), gomock.Any()).Return(errors.New("Error in DB"))
 }
 
+func (s *resPoolHandlerTestSuite) TestGetCapacityForecast() {
+	forecaster := forecast_mocks.NewMockForecaster(s.mockCtrl)
+	s.handler.forecaster = forecaster
+
+	resPoolID := &peloton.ResourcePoolID{Value: "respool1"}
+	forecaster.EXPECT().GetForecast(resPoolID).Return(
+		&pb_respool.GetCapacityForecastResponse{
+			Id: resPoolID,
+			Forecasts: []*pb_respool.CapacityForecast{
+				{
+					Kind:     common.CPU,
+					Supply:   100,
+					Usage:    40,
+					Headroom: 60,
+				},
+			},
+		}, nil)
+
+	resp, err := s.handler.GetCapacityForecast(
+		s.context,
+		&pb_respool.GetCapacityForecastRequest{Id: resPoolID},
+	)
+	s.NoError(err)
+	s.Equal(resPoolID, resp.GetId())
+	s.Len(resp.GetForecasts(), 1)
+	s.Equal(60.0, resp.GetForecasts()[0].GetHeadroom())
+}
+
+func (s *resPoolHandlerTestSuite) TestGetCapacityForecastNotFound() {
+	forecaster := forecast_mocks.NewMockForecaster(s.mockCtrl)
+	s.handler.forecaster = forecaster
+
+	forecaster.EXPECT().GetForecast(gomock.Nil()).Return(
+		nil, yarpcerrors.NotFoundErrorf("no capacity history"))
+
+	resp, err := s.handler.GetCapacityForecast(
+		s.context,
+		&pb_respool.GetCapacityForecastRequest{},
+	)
+	s.True(yarpcerrors.IsNotFound(err))
+	s.Nil(resp)
+}
+
 func TestResPoolHandler(t *testing.T) {
 	suite.Run(t, new(resPoolHandlerTestSuite))
 }
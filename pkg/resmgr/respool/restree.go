@@ -64,6 +64,31 @@ type Tree interface {
 
 	// Delete deletes the resource pool from the tree
 	Delete(ID *peloton.ResourcePoolID) error
+
+	// SetEntitlementCalculator sets the calculator which recalculates the
+	// entitlement when a resource pool is moved to a new parent.
+	SetEntitlementCalculator(calculator EntitlementCalculator)
+
+	// SetMoveListener sets the listener which is notified when a resource
+	// pool is moved to a new parent.
+	SetMoveListener(listener MoveListener)
+}
+
+// MoveListener is notified when a resource pool is moved to a new parent,
+// which changes the paths of the resource pool and of its descendants.
+type MoveListener interface {
+	// ResourcePoolMoved is called with the tree lock held after the
+	// resource pool has been moved, so it must not call back into the tree.
+	ResourcePoolMoved(ID *peloton.ResourcePoolID)
+}
+
+// EntitlementCalculator recalculates the entitlement of the resource pool
+// tree. It is defined here rather than in the entitlement package to avoid
+// circular imports.
+type EntitlementCalculator interface {
+	// Recalculate redistributes the entitlement of the tree rooted at the
+	// provided resource pool. It is called with the tree lock held.
+	Recalculate(root ResPool)
 }
 
 // tree implements the Tree interface
@@ -81,6 +106,8 @@ type tree struct {
 	taskStore   storage.TaskStore  // Keeping Task store object within tree
 	scope       tally.Scope        // Parent scope for the metrics
 	updatedChan chan struct{}      // Channel to update all the changes in tree
+	calculator  EntitlementCalculator
+	listener    MoveListener
 }
 
 // NewTree will initializing the respool tree
@@ -139,6 +166,22 @@ func (t *tree) UpdatedChannel() <-chan struct{} {
 	return t.updatedChan
 }
 
+// SetEntitlementCalculator sets the calculator which recalculates the
+// entitlement when a resource pool is moved to a new parent.
+func (t *tree) SetEntitlementCalculator(calculator EntitlementCalculator) {
+	t.Lock()
+	defer t.Unlock()
+	t.calculator = calculator
+}
+
+// SetMoveListener sets the listener which is notified when a resource
+// pool is moved to a new parent.
+func (t *tree) SetMoveListener(listener MoveListener) {
+	t.Lock()
+	defer t.Unlock()
+	t.listener = listener
+}
+
 // initTree will initialize all the resource pools from Storage
 func (t *tree) initTree(
 	resPoolConfigs map[string]*respool.ResourcePoolConfig) (ResPool, error) {
@@ -160,6 +203,12 @@ func (t *tree) initTree(
 		Policy: respool.SchedulingPolicy_PriorityFIFO,
 	}
 
+	if err := detectCycle(resPoolConfigs); err != nil {
+		return nil, errors.Wrap(
+			err,
+			"failed to initialize tree")
+	}
+
 	root, err := t.buildTree(common.RootResPoolID, nil, resPoolConfigs)
 	if err != nil {
 		return nil, errors.Wrap(
@@ -186,7 +235,6 @@ func (t *tree) buildTree(
 	node.SetParent(parent)
 	childConfigs := t.getChildResPoolConfigs(ID, resPoolConfigs)
 	var childResourcePools = list.New()
-	// TODO: We need to detect cycle here.
 	for childResPoolID := range childConfigs {
 		childNode, err := t.buildTree(childResPoolID, node, resPoolConfigs)
 		if err != nil {
@@ -201,6 +249,25 @@ func (t *tree) buildTree(
 	return node, nil
 }
 
+// detectCycle returns an error if following the parents of any resource pool
+// config leads back to the same resource pool. Such pools are unreachable
+// from the root and would otherwise be silently dropped from the tree.
+func detectCycle(resPoolConfigs map[string]*respool.ResourcePoolConfig) error {
+	for ID := range resPoolConfigs {
+		visited := map[string]bool{ID: true}
+		parentID := resPoolConfigs[ID].GetParent().GetValue()
+		for parentID != "" {
+			if visited[parentID] {
+				return errors.Errorf(
+					"cycle detected in resource pool: %s", ID)
+			}
+			visited[parentID] = true
+			parentID = resPoolConfigs[parentID].GetParent().GetValue()
+		}
+	}
+	return nil
+}
+
 // getChildResPoolConfigs will return map[respoolid] = respoolConfig for a
 // parent resource pool
 func (t *tree) getChildResPoolConfigs(
@@ -293,8 +360,30 @@ func (t *tree) Upsert(ID *peloton.ResourcePoolID, resPoolConfig *respool.Resourc
 			"respool_ID": ID.Value,
 		}).Debug("Updating resource pool")
 
+		// move the resource pool if the parent has changed
+		moved := false
+		if resourcePool.Parent().ID() != parentID.GetValue() {
+			if err := t.move(resourcePool, parent); err != nil {
+				return errors.Wrapf(
+					err,
+					"failed to move resource pool: %s",
+					ID.Value)
+			}
+			moved = true
+		}
+
 		// TODO update only if leaf node ???
 		resourcePool.SetResourcePoolConfig(resPoolConfig)
+
+		// the entitlement of the old and new ancestors is recalculated
+		// before the tree lock is released, so that no admission happens
+		// against the entitlement of the old hierarchy.
+		if moved && t.calculator != nil {
+			t.calculator.Recalculate(t.root)
+		}
+		if moved && t.listener != nil {
+			t.listener.ResourcePoolMoved(ID)
+		}
 	} else {
 		// add resource pool
 		log.WithFields(log.Fields{
@@ -326,6 +415,62 @@ func (t *tree) Upsert(ID *peloton.ResourcePoolID, resPoolConfig *respool.Resourc
 	return nil
 }
 
+// move re-links the resource pool under the new parent. The pending queues
+// and allocation of the subtree move along with it; the aggregates of the old
+// and new ancestors are derived from the leaves when the entitlement is
+// recalculated by Upsert. The caller must hold the tree lock.
+func (t *tree) move(resPool ResPool, newParent ResPool) error {
+	oldParent := resPool.Parent()
+	if oldParent == nil {
+		return errors.New("cannot move the root resource pool")
+	}
+
+	// the new parent must not be in the subtree being moved.
+	for p := newParent; p != nil; p = p.Parent() {
+		if p.ID() == resPool.ID() {
+			return errors.Errorf(
+				"resource pool %s is a descendant of %s",
+				newParent.ID(),
+				resPool.ID())
+		}
+	}
+
+	oldChildren := list.New()
+	for e := oldParent.Children().Front(); e != nil; e = e.Next() {
+		child, _ := e.Value.(ResPool)
+		if child.ID() != resPool.ID() {
+			oldChildren.PushBack(child)
+		}
+	}
+
+	newChildren := list.New()
+	newChildren.PushBackList(newParent.Children())
+	newChildren.PushBack(resPool)
+
+	oldParent.SetChildren(oldChildren)
+	newParent.SetChildren(newChildren)
+	resPool.SetParent(newParent)
+	updatePaths(resPool)
+
+	log.WithFields(log.Fields{
+		"respool_ID":    resPool.ID(),
+		"old_parent_ID": oldParent.ID(),
+		"new_parent_ID": newParent.ID(),
+		"respool_path":  resPool.GetPath(),
+	}).Info("Moved resource pool")
+	return nil
+}
+
+// updatePaths recalculates the paths of all the descendants of the resource
+// pool after its path has changed.
+func updatePaths(resPool ResPool) {
+	for e := resPool.Children().Front(); e != nil; e = e.Next() {
+		child, _ := e.Value.(ResPool)
+		child.SetParent(resPool)
+		updatePaths(child)
+	}
+}
+
 // Returns the resource pool for the given resource pool ID
 func (t *tree) lookupResPool(ID *peloton.ResourcePoolID) (ResPool, error) {
 	if val, ok := t.resPools[ID.Value]; ok {
@@ -390,3 +535,26 @@ func (t *tree) Delete(respoolID *peloton.ResourcePoolID) error {
 
 	return nil
 }
+
+// PathResolver resolves the IDs of the resource pools in a tree to their
+// paths.
+type PathResolver struct {
+	tree Tree
+}
+
+// NewPathResolver returns a PathResolver of the resource pools in tree.
+func NewPathResolver(tree Tree) *PathResolver {
+	return &PathResolver{tree: tree}
+}
+
+// GetRespoolPath returns the path of the resource pool with the ID.
+func (r *PathResolver) GetRespoolPath(
+	_ context.Context,
+	respoolID string,
+) (string, error) {
+	pool, err := r.tree.Get(&peloton.ResourcePoolID{Value: respoolID})
+	if err != nil {
+		return "", err
+	}
+	return pool.GetPath(), nil
+}
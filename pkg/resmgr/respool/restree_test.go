@@ -467,6 +467,115 @@ func (s *resTreeTestSuite) TestDelete() {
 	s.Equal(3, resourceTree.GetAllNodes(true).Len())
 }
 
+// fakeCalculator records the resource pool trees it recalculates.
+type fakeCalculator struct {
+	roots []ResPool
+}
+
+func (c *fakeCalculator) Recalculate(root ResPool) {
+	c.roots = append(c.roots, root)
+}
+
+// fakeMoveListener records the resource pools which are moved.
+type fakeMoveListener struct {
+	moved []string
+}
+
+func (l *fakeMoveListener) ResourcePoolMoved(ID *peloton.ResourcePoolID) {
+	l.moved = append(l.moved, ID.GetValue())
+}
+
+func (s *resTreeTestSuite) TestUpsertMoveResourcePool() {
+	resourceTree := s.getTree(s.withStore(s.getResPools(), nil))
+	s.NoError(resourceTree.Start())
+	calculator := &fakeCalculator{}
+	resourceTree.SetEntitlementCalculator(calculator)
+	listener := &fakeMoveListener{}
+	resourceTree.SetMoveListener(listener)
+
+	// enqueue a gang into a leaf under the pool being moved
+	respool23, err := resourceTree.Get(&peloton.ResourcePoolID{Value: "respool23"})
+	s.NoError(err)
+	s.NoError(respool23.EnqueueGang(makeTaskGang(&resmgr.Task{
+		Name:     "job1-1",
+		Priority: 0,
+		JobId:    &peloton.JobID{Value: "job1"},
+		Id:       &peloton.TaskID{Value: "job1-1"},
+		Resource: &task.ResourceConfig{CpuLimit: 1},
+	})))
+
+	// move respool22 from respool2 to respool1
+	respool22ID := &peloton.ResourcePoolID{Value: "respool22"}
+	respool22, err := resourceTree.Get(respool22ID)
+	s.NoError(err)
+	config := respool22.ResourcePoolConfig()
+	s.NoError(resourceTree.Upsert(respool22ID, &respool.ResourcePoolConfig{
+		Name:      config.GetName(),
+		Parent:    &peloton.ResourcePoolID{Value: "respool1"},
+		Resources: config.GetResources(),
+		Policy:    config.GetPolicy(),
+	}))
+	<-resourceTree.UpdatedChannel()
+
+	s.Equal("respool1", respool22.Parent().ID())
+	s.Equal("/respool1/respool22", respool22.GetPath())
+	s.Equal("/respool1/respool22/respool23", respool23.GetPath())
+	s.Equal(3, respool22.Parent().Children().Len())
+	respool2, err := resourceTree.Get(&peloton.ResourcePoolID{Value: "respool2"})
+	s.NoError(err)
+	s.Equal(1, respool2.Children().Len())
+
+	// the entitlement is recalculated for the whole tree
+	root, err := resourceTree.Get(&peloton.ResourcePoolID{Value: common.RootResPoolID})
+	s.NoError(err)
+	s.Equal([]ResPool{root}, calculator.roots)
+	s.Equal([]string{respool22ID.GetValue()}, listener.moved)
+
+	moved, err := resourceTree.GetByPath(&respool.ResourcePoolPath{
+		Value: "/respool1/respool22/respool23",
+	})
+	s.NoError(err)
+	s.Equal(respool23, moved)
+
+	// the pending gang moves along with the pool
+	gangs, err := moved.PeekGangs(PendingQueue, 1)
+	s.NoError(err)
+	s.Len(gangs, 1)
+
+	// moving a pool under its own descendant fails
+	err = resourceTree.Upsert(respool22ID, &respool.ResourcePoolConfig{
+		Name:      config.GetName(),
+		Parent:    &peloton.ResourcePoolID{Value: "respool23"},
+		Resources: config.GetResources(),
+		Policy:    config.GetPolicy(),
+	})
+	s.Error(err)
+	s.Contains(err.Error(), "is a descendant of")
+	s.Equal("respool1", respool22.Parent().ID())
+	s.Len(calculator.roots, 1)
+	s.Len(listener.moved, 1)
+
+	// updating a pool without moving it does not recalculate
+	s.NoError(resourceTree.Upsert(respool22ID, &respool.ResourcePoolConfig{
+		Name:      config.GetName(),
+		Parent:    &peloton.ResourcePoolID{Value: "respool1"},
+		Resources: config.GetResources(),
+		Policy:    config.GetPolicy(),
+	}))
+	s.Len(calculator.roots, 1)
+	s.Len(listener.moved, 1)
+}
+
+func (s *resTreeTestSuite) TestStartWithCycle() {
+	resPools := s.getResPools()
+	resPools["respool1"].Parent = &peloton.ResourcePoolID{Value: "respool11"}
+
+	resourceTree := s.getTree(s.withStore(resPools, nil))
+	err := resourceTree.Start()
+	s.Error(err)
+	s.Contains(err.Error(), "cycle detected in resource pool")
+}
+
 func TestPelotonResPool(t *testing.T) {
 	suite.Run(t, new(resTreeTestSuite))
 }
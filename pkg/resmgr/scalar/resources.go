@@ -51,6 +51,11 @@ const (
 // Allocation is the container to track allocation across different dimensions
 type Allocation struct {
 	Value map[AllocationType]*Resources
+
+	// GPUModels tracks the GPUs per GPU model across the different
+	// dimensions, for the tasks which request specific GPU models.
+	// It is nil if no such task is accounted for.
+	GPUModels map[AllocationType]map[string]float64
 }
 
 // NewAllocation returns a new Allocation
@@ -63,12 +68,19 @@ func (a *Allocation) GetByType(allocationType AllocationType) *Resources {
 	return a.Value[allocationType]
 }
 
+// GetGPUModelsByType returns the GPUs per GPU model by type
+func (a *Allocation) GetGPUModelsByType(
+	allocationType AllocationType) map[string]float64 {
+	return a.GPUModels[allocationType]
+}
+
 // Add adds one allocation to another
 func (a *Allocation) Add(other *Allocation) *Allocation {
 	result := initializeZeroAlloc()
 	for t, v := range a.Value {
 		result.Value[t] = v.Add(other.Value[t])
 	}
+	result.GPUModels = mergeGPUModels(a.GPUModels, other.GPUModels, 1)
 	return result
 }
 
@@ -78,6 +90,45 @@ func (a *Allocation) Subtract(other *Allocation) *Allocation {
 	for t, v := range a.Value {
 		result.Value[t] = v.Subtract(other.Value[t])
 	}
+	result.GPUModels = mergeGPUModels(a.GPUModels, other.GPUModels, -1)
+	return result
+}
+
+// mergeGPUModels returns the GPUs per model of a plus sign times the GPUs
+// per model of b. Models which end up with no GPUs are dropped, and nil is
+// returned if no model is left.
+func mergeGPUModels(
+	a, b map[AllocationType]map[string]float64,
+	sign float64) map[AllocationType]map[string]float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	result := make(map[AllocationType]map[string]float64)
+	add := func(models map[AllocationType]map[string]float64, sign float64) {
+		for t, gpus := range models {
+			for model, value := range gpus {
+				if result[t] == nil {
+					result[t] = make(map[string]float64)
+				}
+				result[t][model] += sign * value
+			}
+		}
+	}
+	add(a, 1)
+	add(b, sign)
+	for t, gpus := range result {
+		for model, value := range gpus {
+			if math.Abs(value) < util.ResourceEpsilon {
+				delete(gpus, model)
+			}
+		}
+		if len(gpus) == 0 {
+			delete(result, t)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
 	return result
 }
 
@@ -113,7 +164,7 @@ func GetGangAllocation(gang *resmgrsvc.Gang) *Allocation {
 func GetTaskAllocation(rmTask *resmgr.Task) *Allocation {
 	alloc := initializeZeroAlloc()
 
-	taskResource := ConvertToResmgrResource(rmTask.Resource)
+	taskResource := GetTaskResources(rmTask)
 
 	// check if the task is non-preemptible
 	if rmTask.GetPreemptible() {
@@ -136,15 +187,52 @@ func GetTaskAllocation(rmTask *resmgr.Task) *Allocation {
 	// every task account for total allocation
 	alloc.Value[TotalAllocation] = taskResource
 
+	// The GPU model a task is going to run on is only known after placement,
+	// so a task requesting GPUs of several models is conservatively
+	// accounted for against each of them.
+	models := rmTask.GetResource().GetGpu().GetModels()
+	if len(models) > 0 && taskResource.GPU > 0 {
+		alloc.GPUModels = make(map[AllocationType]map[string]float64)
+		for t, res := range alloc.Value {
+			if res.GPU <= 0 {
+				continue
+			}
+			gpus := make(map[string]float64)
+			for _, model := range models {
+				gpus[model] = res.GPU
+			}
+			alloc.GPUModels[t] = gpus
+		}
+	}
+
 	return alloc
 }
 
 // ZeroResource represents the minimum Value of a resource
 var ZeroResource = &Resources{
-	CPU:    float64(0),
-	GPU:    float64(0),
-	DISK:   float64(0),
-	MEMORY: float64(0),
+	CPU:     float64(0),
+	GPU:     float64(0),
+	DISK:    float64(0),
+	MEMORY:  float64(0),
+	PORTS:   float64(0),
+	NETWORK: float64(0),
+}
+
+// OptionalKinds are the resource kinds which are only accounted for and
+// enforced in the resource pools which configure them explicitly.
+var OptionalKinds = []string{
+	common.PORTS,
+	common.NETWORK,
+}
+
+// IsOptionalKind returns true if the resource kind is an optional kind.
+func IsOptionalKind(kind string) bool {
+	for _, k := range OptionalKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
 }
 
 // Resources is a non-thread safe helper struct holding recognized resources.
@@ -153,6 +241,10 @@ type Resources struct {
 	MEMORY float64
 	DISK   float64
 	GPU    float64
+	// PORTS is the number of dynamic ports
+	PORTS float64
+	// NETWORK is the network bandwidth in Mbps
+	NETWORK float64
 }
 
 // GetCPU returns the CPU resource
@@ -175,6 +267,16 @@ func (r *Resources) GetGPU() float64 {
 	return r.GPU
 }
 
+// GetPorts returns the number of ports
+func (r *Resources) GetPorts() float64 {
+	return r.PORTS
+}
+
+// GetNetwork returns the network bandwidth resource
+func (r *Resources) GetNetwork() float64 {
+	return r.NETWORK
+}
+
 // Get returns the kind of resource
 func (r *Resources) Get(kind string) float64 {
 	switch kind {
@@ -186,6 +288,10 @@ func (r *Resources) Get(kind string) float64 {
 		return r.GetMem()
 	case common.DISK:
 		return r.GetDisk()
+	case common.PORTS:
+		return r.GetPorts()
+	case common.NETWORK:
+		return r.GetNetwork()
 	}
 	return float64(0)
 }
@@ -201,16 +307,22 @@ func (r *Resources) Set(kind string, value float64) {
 		r.MEMORY = value
 	case common.DISK:
 		r.DISK = value
+	case common.PORTS:
+		r.PORTS = value
+	case common.NETWORK:
+		r.NETWORK = value
 	}
 }
 
 // Add atomically add another scalar resources onto current one.
 func (r *Resources) Add(other *Resources) *Resources {
 	return &Resources{
-		CPU:    r.CPU + other.CPU,
-		MEMORY: r.MEMORY + other.MEMORY,
-		DISK:   r.DISK + other.DISK,
-		GPU:    r.GPU + other.GPU,
+		CPU:     r.CPU + other.CPU,
+		MEMORY:  r.MEMORY + other.MEMORY,
+		DISK:    r.DISK + other.DISK,
+		GPU:     r.GPU + other.GPU,
+		PORTS:   r.PORTS + other.PORTS,
+		NETWORK: r.NETWORK + other.NETWORK,
 	}
 }
 
@@ -228,7 +340,9 @@ func (r *Resources) LessThanOrEqual(other *Resources) bool {
 	return lessThanOrEqual(r.CPU, other.CPU) &&
 		lessThanOrEqual(r.MEMORY, other.MEMORY) &&
 		lessThanOrEqual(r.DISK, other.DISK) &&
-		lessThanOrEqual(r.GPU, other.GPU)
+		lessThanOrEqual(r.GPU, other.GPU) &&
+		lessThanOrEqual(r.PORTS, other.PORTS) &&
+		lessThanOrEqual(r.NETWORK, other.NETWORK)
 }
 
 func equal(f1, f2 float64) bool {
@@ -241,19 +355,30 @@ func (r *Resources) Equal(other *Resources) bool {
 	return equal(r.CPU, other.CPU) &&
 		equal(r.MEMORY, other.MEMORY) &&
 		equal(r.DISK, other.DISK) &&
-		equal(r.GPU, other.GPU)
+		equal(r.GPU, other.GPU) &&
+		equal(r.PORTS, other.PORTS) &&
+		equal(r.NETWORK, other.NETWORK)
 }
 
 // ConvertToResmgrResource converts task resource config to scalar.Resources
 func ConvertToResmgrResource(resource *task.ResourceConfig) *Resources {
 	return &Resources{
-		CPU:    resource.GetCpuLimit(),
-		DISK:   resource.GetDiskLimitMb(),
-		GPU:    resource.GetGpuLimit(),
-		MEMORY: resource.GetMemLimitMb(),
+		CPU:     resource.GetCpuLimit(),
+		DISK:    resource.GetDiskLimitMb(),
+		GPU:     resource.GetGpuLimit(),
+		MEMORY:  resource.GetMemLimitMb(),
+		NETWORK: resource.GetNetworkMbps(),
 	}
 }
 
+// GetTaskResources returns the resources of a task including the dynamic
+// ports it needs, which are not part of the task resource config.
+func GetTaskResources(rmTask *resmgr.Task) *Resources {
+	res := ConvertToResmgrResource(rmTask.GetResource())
+	res.PORTS = float64(rmTask.GetNumPorts())
+	return res
+}
+
 // GetGangResources aggregates gang resources to resmgr resources
 func GetGangResources(gang *resmgrsvc.Gang) *Resources {
 	if gang == nil {
@@ -261,24 +386,26 @@ func GetGangResources(gang *resmgrsvc.Gang) *Resources {
 	}
 	totalRes := &Resources{}
 	for _, task := range gang.GetTasks() {
-		totalRes = totalRes.Add(
-			ConvertToResmgrResource(task.GetResource()))
+		totalRes = totalRes.Add(GetTaskResources(task))
 	}
 	return totalRes
 }
 
 func (r *Resources) String() string {
-	return fmt.Sprintf("CPU:%.2f MEM:%.2f DISK:%.2f GPU:%.2f",
-		r.GetCPU(), r.GetMem(), r.GetDisk(), r.GetGPU())
+	return fmt.Sprintf("CPU:%.2f MEM:%.2f DISK:%.2f GPU:%.2f PORTS:%.0f NETWORK:%.2f",
+		r.GetCPU(), r.GetMem(), r.GetDisk(), r.GetGPU(), r.GetPorts(),
+		r.GetNetwork())
 }
 
 // Min Gets the minimum value for each resource type
 func Min(r1, r2 *Resources) *Resources {
 	return &Resources{
-		CPU:    math.Min(r1.GetCPU(), r2.GetCPU()),
-		MEMORY: math.Min(r1.GetMem(), r2.GetMem()),
-		DISK:   math.Min(r1.GetDisk(), r2.GetDisk()),
-		GPU:    math.Min(r1.GetGPU(), r2.GetGPU()),
+		CPU:     math.Min(r1.GetCPU(), r2.GetCPU()),
+		MEMORY:  math.Min(r1.GetMem(), r2.GetMem()),
+		DISK:    math.Min(r1.GetDisk(), r2.GetDisk()),
+		GPU:     math.Min(r1.GetGPU(), r2.GetGPU()),
+		PORTS:   math.Min(r1.GetPorts(), r2.GetPorts()),
+		NETWORK: math.Min(r1.GetNetwork(), r2.GetNetwork()),
 	}
 }
 
@@ -336,6 +463,32 @@ func (r *Resources) Subtract(other *Resources) *Resources {
 			result.DISK = float64(0)
 		}
 	}
+
+	if r.PORTS < other.PORTS {
+		log.WithFields(log.Fields{
+			"from_ports":  r.PORTS,
+			"value_ports": other.PORTS,
+		}).Debug("Subtracted Value is Greater")
+		result.PORTS = float64(0)
+	} else {
+		result.PORTS = r.PORTS - other.PORTS
+		if result.PORTS < util.ResourceEpsilon {
+			result.PORTS = float64(0)
+		}
+	}
+
+	if r.NETWORK < other.NETWORK {
+		log.WithFields(log.Fields{
+			"from_network":  r.NETWORK,
+			"value_network": other.NETWORK,
+		}).Debug("Subtracted Value is Greater")
+		result.NETWORK = float64(0)
+	} else {
+		result.NETWORK = r.NETWORK - other.NETWORK
+		if result.NETWORK < util.ResourceEpsilon {
+			result.NETWORK = float64(0)
+		}
+	}
 	return &result
 }
 
@@ -344,10 +497,12 @@ func (r *Resources) Subtract(other *Resources) *Resources {
 // the new object
 func (r *Resources) Clone() *Resources {
 	return &Resources{
-		CPU:    r.CPU,
-		DISK:   r.DISK,
-		MEMORY: r.MEMORY,
-		GPU:    r.GPU,
+		CPU:     r.CPU,
+		DISK:    r.DISK,
+		MEMORY:  r.MEMORY,
+		GPU:     r.GPU,
+		PORTS:   r.PORTS,
+		NETWORK: r.NETWORK,
 	}
 }
 
@@ -358,4 +513,6 @@ func (r *Resources) Copy(other *Resources) {
 	r.DISK = other.DISK
 	r.MEMORY = other.MEMORY
 	r.GPU = other.GPU
+	r.PORTS = other.PORTS
+	r.NETWORK = other.NETWORK
 }
@@ -35,10 +35,10 @@ func TestAdd(t *testing.T) {
 	}
 
 	result := empty.Add(&empty)
-	assertEqual(t, &Resources{0.0, 0.0, 0.0, 0.0}, result)
+	assertEqual(t, &Resources{0.0, 0.0, 0.0, 0.0, 0.0, 0.0}, result)
 
 	result = r1.Add(&Resources{})
-	assertEqual(t, &Resources{1.0, 0.0, 0.0, 0.0}, result)
+	assertEqual(t, &Resources{1.0, 0.0, 0.0, 0.0, 0.0, 0.0}, result)
 
 	r2 := Resources{
 		CPU:    4.0,
@@ -47,7 +47,7 @@ func TestAdd(t *testing.T) {
 		GPU:    1.0,
 	}
 	result = r1.Add(&r2)
-	assertEqual(t, &Resources{5.0, 3.0, 2.0, 1.0}, result)
+	assertEqual(t, &Resources{5.0, 3.0, 2.0, 1.0, 0.0, 0.0}, result)
 }
 
 func assertEqual(t *testing.T, expected *Resources, result *Resources) {
@@ -55,7 +55,9 @@ func assertEqual(t *testing.T, expected *Resources, result *Resources) {
 		common.CPU,
 		common.MEMORY,
 		common.DISK,
-		common.GPU} {
+		common.GPU,
+		common.PORTS,
+		common.NETWORK} {
 		assert.InDelta(t, expected.Get(typeRes), result.Get(typeRes), _zeroDelta)
 	}
 }
@@ -71,7 +73,7 @@ func TestSubtract(t *testing.T) {
 
 	res := r1.Subtract(&empty)
 	assert.NotNil(t, res)
-	assertEqual(t, &Resources{1.0, 2.0, 3.0, 4.0}, res)
+	assertEqual(t, &Resources{1.0, 2.0, 3.0, 4.0, 0.0, 0.0}, res)
 
 	r2 := Resources{
 		CPU:    2.0,
@@ -82,10 +84,10 @@ func TestSubtract(t *testing.T) {
 	res = r2.Subtract(&r1)
 
 	assert.NotNil(t, res)
-	assertEqual(t, &Resources{1.0, 3.0, 1.0, 3.0}, res)
+	assertEqual(t, &Resources{1.0, 3.0, 1.0, 3.0, 0.0, 0.0}, res)
 
 	res = r1.Subtract(&r2)
-	assertEqual(t, &Resources{0.0, 0.0, 0.0, 0.0}, res)
+	assertEqual(t, &Resources{0.0, 0.0, 0.0, 0.0, 0.0, 0.0}, res)
 }
 
 func TestSubtractLessThanEpsilon(t *testing.T) {
@@ -103,7 +105,7 @@ func TestSubtractLessThanEpsilon(t *testing.T) {
 	}
 	res := r2.Subtract(&r1)
 	assert.NotNil(t, res)
-	assertEqual(t, &Resources{0.0, 0.0, 0.0, 0.0}, res)
+	assertEqual(t, &Resources{0.0, 0.0, 0.0, 0.0, 0.0, 0.0}, res)
 }
 
 func TestLessThanOrEqual(t *testing.T) {
@@ -154,7 +156,7 @@ func TestConvertToResmgrResource(t *testing.T) {
 		MemLimitMb:  10.0,
 	}
 	res := ConvertToResmgrResource(taskConfig)
-	assertEqual(t, &Resources{4.0, 10.0, 5.0, 1.0}, res)
+	assertEqual(t, &Resources{4.0, 10.0, 5.0, 1.0, 0.0, 0.0}, res)
 }
 
 func TestSet(t *testing.T) {
@@ -164,12 +166,12 @@ func TestSet(t *testing.T) {
 		DISK:   3.0,
 		GPU:    4.0,
 	}
-	assertEqual(t, &Resources{1.0, 2.0, 3.0, 4.0}, &r1)
+	assertEqual(t, &Resources{1.0, 2.0, 3.0, 4.0, 0.0, 0.0}, &r1)
 	r1.Set(common.CPU, float64(2.0))
 	r1.Set(common.MEMORY, float64(3.0))
 	r1.Set(common.DISK, float64(4.0))
 	r1.Set(common.GPU, float64(5.0))
-	assertEqual(t, &Resources{2.0, 3.0, 4.0, 5.0}, &r1)
+	assertEqual(t, &Resources{2.0, 3.0, 4.0, 5.0, 0.0, 0.0}, &r1)
 }
 
 func TestClone(t *testing.T) {
@@ -359,12 +361,12 @@ func TestGetTaskAllocation(t *testing.T) {
 
 		// total should always be equal to the taskConfig
 		res := alloc.GetByType(TotalAllocation)
-		assertEqual(t, &Resources{4.0, 10.0, 5.0, 1.0}, res)
+		assertEqual(t, &Resources{4.0, 10.0, 5.0, 1.0, 0.0, 0.0}, res)
 
 		// these should be equal to the taskConfig
 		for _, allocType := range test.hasAlloc {
 			res := alloc.GetByType(allocType)
-			assertEqual(t, &Resources{4.0, 10.0, 5.0, 1.0}, res)
+			assertEqual(t, &Resources{4.0, 10.0, 5.0, 1.0, 0.0, 0.0}, res)
 		}
 
 		// these should be equal to zero
@@ -390,8 +392,54 @@ func TestGetGangResources(t *testing.T) {
 			},
 		},
 	})
-	assertEqual(t, &Resources{1.0, 1.0, 1.0, 1.0}, res)
-	assert.Equal(t, "CPU:1.00 MEM:1.00 DISK:1.00 GPU:1.00", res.String())
+	assertEqual(t, &Resources{1.0, 1.0, 1.0, 1.0, 0.0, 0.0}, res)
+	assert.Equal(t,
+		"CPU:1.00 MEM:1.00 DISK:1.00 GPU:1.00 PORTS:0 NETWORK:0.00",
+		res.String())
+}
+
+func TestGetTaskResourcesWithPortsAndNetwork(t *testing.T) {
+	res := GetTaskResources(&resmgr.Task{
+		Resource: &task.ResourceConfig{
+			CpuLimit:    1,
+			DiskLimitMb: 1,
+			GpuLimit:    1,
+			MemLimitMb:  1,
+			NetworkMbps: 100,
+		},
+		NumPorts: 3,
+	})
+	assertEqual(t, &Resources{1.0, 1.0, 1.0, 1.0, 3.0, 100.0}, res)
+
+	alloc := GetTaskAllocation(&resmgr.Task{
+		Resource: &task.ResourceConfig{
+			NetworkMbps: 100,
+		},
+		NumPorts: 3,
+	})
+	assertEqual(t, &Resources{PORTS: 3.0, NETWORK: 100.0},
+		alloc.GetByType(TotalAllocation))
+}
+
+func TestOptionalKinds(t *testing.T) {
+	assert.True(t, IsOptionalKind(common.PORTS))
+	assert.True(t, IsOptionalKind(common.NETWORK))
+	assert.False(t, IsOptionalKind(common.CPU))
+	assert.False(t, IsOptionalKind(common.DISK))
+
+	r := &Resources{}
+	r.Set(common.PORTS, 10)
+	r.Set(common.NETWORK, 1000)
+	assert.Equal(t, 10.0, r.Get(common.PORTS))
+	assert.Equal(t, 1000.0, r.Get(common.NETWORK))
+
+	assert.True(t, (&Resources{PORTS: 5, NETWORK: 500}).LessThanOrEqual(r))
+	assert.False(t, (&Resources{PORTS: 11}).LessThanOrEqual(r))
+	assert.False(t, (&Resources{NETWORK: 1001}).LessThanOrEqual(r))
+	assertEqual(t, &Resources{PORTS: 5, NETWORK: 500},
+		r.Subtract(&Resources{PORTS: 5, NETWORK: 500}))
+	assertEqual(t, &Resources{PORTS: 5},
+		Min(r, &Resources{PORTS: 5, NETWORK: 0}))
 }
 
 func TestGetGangAllocation(t *testing.T) {
@@ -407,5 +455,53 @@ func TestGetGangAllocation(t *testing.T) {
 			},
 		},
 	})
-	assertEqual(t, &Resources{1.0, 1.0, 1.0, 1.0}, res.GetByType(TotalAllocation))
+	assertEqual(t, &Resources{1.0, 1.0, 1.0, 1.0, 0.0, 0.0}, res.GetByType(TotalAllocation))
+}
+
+func TestGetTaskAllocationGPUModels(t *testing.T) {
+	rmTask := &resmgr.Task{
+		Preemptible: true,
+		Resource: &task.ResourceConfig{
+			CpuLimit: 1,
+			GpuLimit: 2,
+			Gpu: &task.GPUConfig{
+				Models: []string{"p100", "tesla-v100"},
+			},
+		},
+	}
+
+	alloc := GetTaskAllocation(rmTask)
+	expected := map[string]float64{"p100": 2, "tesla-v100": 2}
+	assert.Equal(t, expected, alloc.GetGPUModelsByType(TotalAllocation))
+	assert.Equal(t, expected, alloc.GetGPUModelsByType(PreemptibleAllocation))
+	assert.Equal(t, expected, alloc.GetGPUModelsByType(NonSlackAllocation))
+	assert.Nil(t, alloc.GetGPUModelsByType(NonPreemptibleAllocation))
+
+	// tasks without GPU models are not accounted for
+	rmTask.Resource.Gpu = nil
+	assert.Nil(t, GetTaskAllocation(rmTask).GPUModels)
+}
+
+func TestAddSubtractAllocationGPUModels(t *testing.T) {
+	alloc := NewAllocation()
+	alloc.GPUModels = map[AllocationType]map[string]float64{
+		TotalAllocation: {"tesla-v100": 4},
+	}
+	other := NewAllocation()
+	other.GPUModels = map[AllocationType]map[string]float64{
+		TotalAllocation: {"tesla-v100": 2, "p100": 1},
+	}
+
+	sum := alloc.Add(other)
+	assert.Equal(t,
+		map[string]float64{"tesla-v100": 6, "p100": 1},
+		sum.GetGPUModelsByType(TotalAllocation))
+	// the operands are not modified
+	assert.Equal(t,
+		map[string]float64{"tesla-v100": 4},
+		alloc.GetGPUModelsByType(TotalAllocation))
+
+	assert.Equal(t, alloc.GPUModels, sum.Subtract(other).GPUModels)
+	assert.Nil(t, sum.Subtract(sum).GPUModels)
+	assert.Nil(t, NewAllocation().Add(NewAllocation()).GPUModels)
 }
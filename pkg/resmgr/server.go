@@ -48,6 +48,7 @@ type Server struct {
 	reconciler            ServerProcess
 	drainer               ServerProcess
 	preemptor             ServerProcess
+	forecaster            ServerProcess
 
 	// TODO move these to use ServerProcess
 	getTaskScheduler func() task.Scheduler
@@ -66,7 +67,8 @@ func NewServer(
 	entitlementCalculator ServerProcess,
 	reconciler ServerProcess,
 	preemptor ServerProcess,
-	drainer ServerProcess) *Server {
+	drainer ServerProcess,
+	forecaster ServerProcess) *Server {
 	return &Server{
 		ID:                    leader.NewID(httpPort, grpcPort),
 		role:                  common.ResourceManagerRole,
@@ -77,6 +79,7 @@ func NewServer(
 		reconciler:            reconciler,
 		preemptor:             preemptor,
 		drainer:               drainer,
+		forecaster:            forecaster,
 		metrics:               NewMetrics(parent),
 	}
 }
@@ -150,6 +153,13 @@ func (s *Server) GainedLeadershipCallback() (err error) {
 		return err
 	}
 
+	// Start the capacity forecaster
+	if err = s.forecaster.Start(); err != nil {
+		log.WithError(err).
+			Error("Failed to start capacity forecaster")
+		return err
+	}
+
 	return nil
 }
 
@@ -164,6 +174,11 @@ func (s *Server) LostLeadershipCallback() error {
 	// we set the node as anon-leader before we stop the services
 	s.isLeader = false
 
+	if err := s.forecaster.Stop(); err != nil {
+		log.Errorf("Failed to stop capacity forecaster")
+		return err
+	}
+
 	if err := s.drainer.Stop(); err != nil {
 		log.Errorf("Failed to stop host drainer")
 		return err
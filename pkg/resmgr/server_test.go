@@ -139,6 +139,22 @@ func TestServer_GainedLeadershipCallback(t *testing.T) {
 				reconciler:            &FakeServerProcess{nil},
 				preemptor:             &FakeServerProcess{nil},
 				drainer:               &FakeServerProcess{nil},
+				forecaster:            &FakeServerProcess{errFake},
+			},
+			wantErr: errFake,
+		},
+		{
+			s: &Server{
+				role:                  "testResMgr",
+				metrics:               NewMetrics(tally.NoopScope),
+				resTree:               &FakeServerProcess{nil},
+				recoveryHandler:       &FakeServerProcess{nil},
+				entitlementCalculator: &FakeServerProcess{nil},
+				getTaskScheduler:      mockSchedulerWithErr(nil, t),
+				reconciler:            &FakeServerProcess{nil},
+				preemptor:             &FakeServerProcess{nil},
+				drainer:               &FakeServerProcess{nil},
+				forecaster:            &FakeServerProcess{nil},
 			},
 			wantErr: nil,
 		},
@@ -163,18 +179,18 @@ func TestServer_LostLeadershipCallback(t *testing.T) {
 	}{
 		{
 			s: &Server{
-				role:    "testResMgr",
-				metrics: NewMetrics(tally.NoopScope),
-				drainer: &FakeServerProcess{errFake},
+				role:       "testResMgr",
+				metrics:    NewMetrics(tally.NoopScope),
+				forecaster: &FakeServerProcess{errFake},
 			},
 			wantErr: errFake,
 		},
 		{
 			s: &Server{
-				role:      "testResMgr",
-				metrics:   NewMetrics(tally.NoopScope),
-				drainer:   &FakeServerProcess{nil},
-				preemptor: &FakeServerProcess{errFake},
+				role:       "testResMgr",
+				metrics:    NewMetrics(tally.NoopScope),
+				forecaster: &FakeServerProcess{nil},
+				drainer:    &FakeServerProcess{errFake},
 			},
 			wantErr: errFake,
 		},
@@ -182,6 +198,17 @@ func TestServer_LostLeadershipCallback(t *testing.T) {
 			s: &Server{
 				role:       "testResMgr",
 				metrics:    NewMetrics(tally.NoopScope),
+				forecaster: &FakeServerProcess{nil},
+				drainer:    &FakeServerProcess{nil},
+				preemptor:  &FakeServerProcess{errFake},
+			},
+			wantErr: errFake,
+		},
+		{
+			s: &Server{
+				role:       "testResMgr",
+				metrics:    NewMetrics(tally.NoopScope),
+				forecaster: &FakeServerProcess{nil},
 				drainer:    &FakeServerProcess{nil},
 				preemptor:  &FakeServerProcess{nil},
 				reconciler: &FakeServerProcess{errFake},
@@ -192,6 +219,7 @@ func TestServer_LostLeadershipCallback(t *testing.T) {
 			s: &Server{
 				role:             "testResMgr",
 				metrics:          NewMetrics(tally.NoopScope),
+				forecaster:       &FakeServerProcess{nil},
 				drainer:          &FakeServerProcess{nil},
 				preemptor:        &FakeServerProcess{nil},
 				reconciler:       &FakeServerProcess{nil},
@@ -203,6 +231,7 @@ func TestServer_LostLeadershipCallback(t *testing.T) {
 			s: &Server{
 				role:                  "testResMgr",
 				metrics:               NewMetrics(tally.NoopScope),
+				forecaster:            &FakeServerProcess{nil},
 				drainer:               &FakeServerProcess{nil},
 				preemptor:             &FakeServerProcess{nil},
 				reconciler:            &FakeServerProcess{nil},
@@ -215,6 +244,7 @@ func TestServer_LostLeadershipCallback(t *testing.T) {
 			s: &Server{
 				role:                  "testResMgr",
 				metrics:               NewMetrics(tally.NoopScope),
+				forecaster:            &FakeServerProcess{nil},
 				drainer:               &FakeServerProcess{nil},
 				preemptor:             &FakeServerProcess{nil},
 				reconciler:            &FakeServerProcess{nil},
@@ -228,6 +258,7 @@ func TestServer_LostLeadershipCallback(t *testing.T) {
 			s: &Server{
 				role:                  "testResMgr",
 				metrics:               NewMetrics(tally.NoopScope),
+				forecaster:            &FakeServerProcess{nil},
 				drainer:               &FakeServerProcess{nil},
 				preemptor:             &FakeServerProcess{nil},
 				reconciler:            &FakeServerProcess{nil},
@@ -242,6 +273,7 @@ func TestServer_LostLeadershipCallback(t *testing.T) {
 			s: &Server{
 				role:                  "testResMgr",
 				metrics:               NewMetrics(tally.NoopScope),
+				forecaster:            &FakeServerProcess{nil},
 				drainer:               &FakeServerProcess{nil},
 				preemptor:             &FakeServerProcess{nil},
 				reconciler:            &FakeServerProcess{nil},
@@ -275,6 +307,7 @@ func TestServer_GetID(t *testing.T) {
 		&FakeServerProcess{nil},
 		&FakeServerProcess{nil},
 		&FakeServerProcess{nil},
+		&FakeServerProcess{nil},
 	)
 
 	assert.NotNil(t, s)
@@ -299,6 +332,7 @@ func TestServer_ShutDownCallback(t *testing.T) {
 		&FakeServerProcess{nil},
 		&FakeServerProcess{nil},
 		&FakeServerProcess{nil},
+		&FakeServerProcess{nil},
 	)
 
 	assert.NoError(t, s.ShutDownCallback())
@@ -201,8 +201,8 @@ func (t *Reconciler) getTasksToReconcile(activeTasks map[string][]*RMTask) (
 					pelotonTask.Task().GetTaskId().GetValue()
 
 				// update leaked resources
-				leakedResource.Add(scalar.ConvertToResmgrResource(
-					pelotonTask.task.GetResource(),
+				leakedResource.Add(scalar.GetTaskResources(
+					pelotonTask.task,
 				))
 			}
 		}
@@ -401,7 +401,7 @@ func (tr *tracker) AddResources(
 	if rmTask == nil {
 		return errors.Errorf("rmTask %s is not in tracker", tID)
 	}
-	res := scalar.ConvertToResmgrResource(rmTask.Task().GetResource())
+	res := scalar.GetTaskResources(rmTask.Task())
 	err := rmTask.respool.AddToAllocation(scalar.GetTaskAllocation(rmTask.Task()))
 	if err != nil {
 		return errors.Errorf("Not able to add resources for "+
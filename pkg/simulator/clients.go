@@ -0,0 +1,131 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator
+
+import (
+	"context"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	pb_respool "github.com/uber/peloton/.gen/peloton/api/v0/respool"
+	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
+
+	"github.com/uber/peloton/pkg/common"
+
+	"github.com/pkg/errors"
+	"go.uber.org/yarpc"
+)
+
+// respoolStore is an in-memory storage.ResourcePoolStore which serves the
+// resource pools of a trace to the resource pool tree.
+type respoolStore struct {
+	configs map[string]*pb_respool.ResourcePoolConfig
+}
+
+// newRespoolStore creates the resource pool configs of the trace.
+func newRespoolStore(specs []*ResourcePoolSpec) *respoolStore {
+	configs := make(map[string]*pb_respool.ResourcePoolConfig)
+	for _, spec := range specs {
+		parent := spec.Parent
+		if parent == "" {
+			parent = common.RootResPoolID
+		}
+		var resources []*pb_respool.ResourceConfig
+		for _, r := range spec.Resources {
+			resources = append(resources, &pb_respool.ResourceConfig{
+				Kind:        r.Kind,
+				Reservation: r.Reservation,
+				Limit:       r.Limit,
+				Share:       r.Share,
+			})
+		}
+		configs[spec.Name] = &pb_respool.ResourcePoolConfig{
+			Name:      spec.Name,
+			Parent:    &peloton.ResourcePoolID{Value: parent},
+			Resources: resources,
+			Policy:    pb_respool.SchedulingPolicy_PriorityFIFO,
+		}
+	}
+	return &respoolStore{configs: configs}
+}
+
+func (s *respoolStore) CreateResourcePool(
+	ctx context.Context,
+	id *peloton.ResourcePoolID,
+	config *pb_respool.ResourcePoolConfig,
+	createdBy string) error {
+	return errors.New("resource pools are read-only in the simulator")
+}
+
+func (s *respoolStore) DeleteResourcePool(
+	ctx context.Context,
+	id *peloton.ResourcePoolID) error {
+	return errors.New("resource pools are read-only in the simulator")
+}
+
+func (s *respoolStore) UpdateResourcePool(
+	ctx context.Context,
+	id *peloton.ResourcePoolID,
+	config *pb_respool.ResourcePoolConfig) error {
+	return errors.New("resource pools are read-only in the simulator")
+}
+
+// GetAllResourcePools returns a copy of the configs since the tree
+// adds the root pool to the returned map.
+func (s *respoolStore) GetAllResourcePools(
+	ctx context.Context) (map[string]*pb_respool.ResourcePoolConfig, error) {
+	configs := make(map[string]*pb_respool.ResourcePoolConfig, len(s.configs))
+	for id, config := range s.configs {
+		configs[id] = config
+	}
+	return configs, nil
+}
+
+// capacityClient serves the physical capacity of the simulated hosts to
+// the entitlement calculator. The calculator only calls ClusterCapacity,
+// the other methods of the embedded interface are left unimplemented.
+type capacityClient struct {
+	hostsvc.InternalHostServiceYARPCClient
+
+	resources []*hostsvc.Resource
+}
+
+// newCapacityClient sums up the capacity of the hosts.
+func newCapacityClient(hosts []*simHost) *capacityClient {
+	capacity := make(map[string]float64)
+	for _, h := range hosts {
+		capacity[common.CPU] += h.capacity.CPU
+		capacity[common.MEMORY] += h.capacity.Mem
+		capacity[common.DISK] += h.capacity.Disk
+		capacity[common.GPU] += h.capacity.GPU
+	}
+	var resources []*hostsvc.Resource
+	for _, kind := range []string{
+		common.CPU, common.MEMORY, common.DISK, common.GPU} {
+		resources = append(resources, &hostsvc.Resource{
+			Kind:     kind,
+			Capacity: capacity[kind],
+		})
+	}
+	return &capacityClient{resources: resources}
+}
+
+func (c *capacityClient) ClusterCapacity(
+	ctx context.Context,
+	request *hostsvc.ClusterCapacityRequest,
+	opts ...yarpc.CallOption) (*hostsvc.ClusterCapacityResponse, error) {
+	return &hostsvc.ClusterCapacityResponse{
+		PhysicalResources: c.resources,
+	}, nil
+}
@@ -0,0 +1,342 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	mesos "github.com/uber/peloton/.gen/mesos/v1"
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
+	"github.com/uber/peloton/.gen/peloton/private/resmgr"
+
+	"github.com/uber/peloton/pkg/common"
+	"github.com/uber/peloton/pkg/common/util"
+	"github.com/uber/peloton/pkg/hostmgr/binpacking"
+	"github.com/uber/peloton/pkg/hostmgr/offer/offerpool"
+	hmscalar "github.com/uber/peloton/pkg/hostmgr/scalar"
+	"github.com/uber/peloton/pkg/placement/models"
+
+	"github.com/pkg/errors"
+	"github.com/uber-go/tally"
+)
+
+const (
+	// _offerHoldTime is how long offers are held by the offer pool.
+	// Offers are never expired by the simulator so the value only
+	// needs to be long enough not to matter.
+	_offerHoldTime = 24 * time.Hour
+)
+
+// simHost is a simulated Mesos agent.
+type simHost struct {
+	name       string
+	agentID    string
+	attributes []*mesos.Attribute
+	capacity   hmscalar.Resources
+	used       hmscalar.Resources
+
+	// tasks running on the host keyed by task id.
+	tasks map[string]*simTask
+
+	// offerID is the id of the outstanding offer of the host in the
+	// offer pool, empty if the host has no outstanding offer.
+	offerID string
+	// dirty is set when the free resources of the host changed since
+	// its outstanding offer was made.
+	dirty bool
+}
+
+// free returns the resources of the host which are not used by tasks.
+func (h *simHost) free() hmscalar.Resources {
+	return h.capacity.Subtract(h.used)
+}
+
+// cluster simulates the Mesos agents and master by feeding offers for the
+// free resources of the hosts to the host manager offer pool.
+type cluster struct {
+	hosts     []*simHost
+	hostIndex map[string]*simHost
+
+	pool      offerpool.Pool
+	refresher offerpool.Refresher
+
+	// offerSeq is used to generate unique offer ids.
+	offerSeq int
+}
+
+// newCluster creates the hosts of the trace and an offer pool which ranks
+// the hosts with the given bin packing ranker.
+func newCluster(
+	specs []*HostSpec,
+	ranker binpacking.Ranker,
+	scope tally.Scope) *cluster {
+	c := &cluster{
+		hostIndex: make(map[string]*simHost),
+	}
+	for _, spec := range specs {
+		var attributes []*mesos.Attribute
+		for name, value := range spec.Attributes {
+			attributes = append(attributes, newTextAttribute(name, value))
+		}
+		sort.Slice(attributes, func(i, j int) bool {
+			return attributes[i].GetName() < attributes[j].GetName()
+		})
+		for _, name := range spec.hostnames() {
+			h := &simHost{
+				name:       name,
+				agentID:    fmt.Sprintf("%s-agent", name),
+				attributes: attributes,
+				capacity: hmscalar.Resources{
+					CPU:  spec.CPU,
+					Mem:  spec.MemMb,
+					Disk: spec.DiskMb,
+					GPU:  spec.GPU,
+				},
+				tasks: make(map[string]*simTask),
+				dirty: true,
+			}
+			c.hosts = append(c.hosts, h)
+			c.hostIndex[name] = h
+		}
+	}
+	sort.Slice(c.hosts, func(i, j int) bool {
+		return c.hosts[i].name < c.hosts[j].name
+	})
+
+	c.pool = offerpool.NewOfferPool(
+		_offerHoldTime,
+		nil,
+		offerpool.NewMetrics(scope.SubScope("offer")),
+		nil,
+		nil,
+		[]string{"GPU"},
+		[]string{common.MesosCPU},
+		ranker,
+		_offerHoldTime,
+	)
+	c.refresher = offerpool.NewRefresher(c.pool)
+	return c
+}
+
+// offer replaces the outstanding offer of every host whose free
+// resources changed, like the Mesos master does after tasks are
+// launched or terminated, and refreshes the bin packing ranking.
+func (c *cluster) offer(ctx context.Context) {
+	var offers []*mesos.Offer
+	for _, h := range c.hosts {
+		if !h.dirty {
+			continue
+		}
+		h.dirty = false
+		if h.offerID != "" {
+			offerID := h.offerID
+			c.pool.RescindOffer(&mesos.OfferID{Value: &offerID})
+			h.offerID = ""
+		}
+		free := h.free()
+		if free.Empty() {
+			continue
+		}
+		c.offerSeq++
+		h.offerID = fmt.Sprintf("%s-offer-%d", h.name, c.offerSeq)
+		offers = append(offers, c.newOffer(h, free))
+	}
+	if len(offers) > 0 {
+		c.pool.AddOffers(ctx, offers)
+	}
+	c.refresher.Refresh(nil)
+}
+
+// acquire claims the hosts matching the filter from the offer pool and
+// converts them to host offers for the placement strategy, sorted by
+// hostname so that runs are reproducible.
+func (c *cluster) acquire(
+	filter *hostsvc.HostFilter,
+	now time.Time) ([]*models.HostOffers, error) {
+	result, _, err := c.pool.ClaimForPlace(filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "claim for place failed")
+	}
+
+	var hostOffers []*models.HostOffers
+	for hostname, hostOffer := range result {
+		offers := hostOffer.Offers
+		if len(offers) == 0 {
+			continue
+		}
+		var resources []*mesos.Resource
+		for _, offer := range offers {
+			resources = append(resources, offer.GetResources()...)
+		}
+		h := c.hostIndex[hostname]
+		var tasks []*resmgr.Task
+		for _, t := range h.sortedTasks() {
+			tasks = append(tasks, t.task)
+		}
+		hostOffers = append(hostOffers, models.NewHostOffers(
+			&hostsvc.HostOffer{
+				Hostname:   hostname,
+				AgentId:    offers[0].GetAgentId(),
+				Attributes: offers[0].GetAttributes(),
+				Resources:  resources,
+				Id:         &peloton.HostOfferID{Value: hostOffer.ID},
+			},
+			tasks,
+			now))
+	}
+	sort.Slice(hostOffers, func(i, j int) bool {
+		return hostOffers[i].GetOffer().GetHostname() <
+			hostOffers[j].GetOffer().GetHostname()
+	})
+	return hostOffers, nil
+}
+
+// jobHosts returns the hosts running tasks of the jobs, with all the tasks
+// running on them.
+func (c *cluster) jobHosts(jobIDs map[string]struct{}) []*models.Host {
+	if len(jobIDs) == 0 {
+		return nil
+	}
+
+	var result []*models.Host
+	for _, h := range c.hosts {
+		found := false
+		for _, t := range h.tasks {
+			if _, ok := jobIDs[t.task.GetJobId().GetValue()]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+
+		var tasks []*resmgr.Task
+		for _, t := range h.sortedTasks() {
+			tasks = append(tasks, t.task)
+		}
+		result = append(result, models.NewHosts(
+			&hostsvc.HostInfo{
+				Hostname:   h.name,
+				Attributes: h.attributes,
+			},
+			tasks))
+	}
+	return result
+}
+
+// launch claims the offers of the host for the tasks and starts them.
+func (c *cluster) launch(
+	hostOffer *models.HostOffers,
+	tasks []*simTask) error {
+	hostname := hostOffer.GetOffer().GetHostname()
+	var taskIDs []*peloton.TaskID
+	for _, t := range tasks {
+		taskIDs = append(taskIDs, t.task.GetId())
+	}
+	if _, err := c.pool.ClaimForLaunch(
+		hostname,
+		false,
+		hostOffer.GetOffer().GetId().GetValue(),
+		taskIDs...); err != nil {
+		return errors.Wrapf(err, "failed to claim host %s for launch", hostname)
+	}
+
+	h := c.hostIndex[hostname]
+	// the offer is consumed by the launch
+	h.offerID = ""
+	h.dirty = true
+	for _, t := range tasks {
+		h.used = h.used.Add(hmscalar.FromResourceConfig(t.task.GetResource()))
+		h.tasks[t.task.GetId().GetValue()] = t
+		t.host = h
+	}
+	return nil
+}
+
+// release returns a claimed host without launching on it.
+func (c *cluster) release(hostOffer *models.HostOffers) error {
+	return c.pool.ReturnUnusedOffers(hostOffer.GetOffer().GetHostname())
+}
+
+// kill removes a task from its host.
+func (c *cluster) kill(t *simTask) {
+	h := t.host
+	if h == nil {
+		return
+	}
+	h.used = h.used.Subtract(hmscalar.FromResourceConfig(t.task.GetResource()))
+	delete(h.tasks, t.task.GetId().GetValue())
+	h.dirty = true
+	t.host = nil
+}
+
+// newOffer creates a Mesos offer for the free resources of a host.
+func (c *cluster) newOffer(h *simHost, free hmscalar.Resources) *mesos.Offer {
+	var resources []*mesos.Resource
+	for _, r := range []struct {
+		name  string
+		value float64
+	}{
+		{common.MesosCPU, free.CPU},
+		{common.MesosMem, free.Mem},
+		{common.MesosDisk, free.Disk},
+		{common.MesosGPU, free.GPU},
+	} {
+		if r.value <= 0 {
+			continue
+		}
+		resources = append(resources, util.NewMesosResourceBuilder().
+			WithName(r.name).
+			WithValue(r.value).
+			Build())
+	}
+	offerID := h.offerID
+	hostname := h.name
+	agentID := h.agentID
+	return &mesos.Offer{
+		Id:         &mesos.OfferID{Value: &offerID},
+		AgentId:    &mesos.AgentID{Value: &agentID},
+		Hostname:   &hostname,
+		Attributes: h.attributes,
+		Resources:  resources,
+	}
+}
+
+// sortedTasks returns the tasks running on the host sorted by task id.
+func (h *simHost) sortedTasks() []*simTask {
+	tasks := make([]*simTask, 0, len(h.tasks))
+	for _, t := range h.tasks {
+		tasks = append(tasks, t)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].task.GetId().GetValue() < tasks[j].task.GetId().GetValue()
+	})
+	return tasks
+}
+
+// newTextAttribute creates a Mesos text attribute.
+func newTextAttribute(name, value string) *mesos.Attribute {
+	attrType := mesos.Value_TEXT
+	return &mesos.Attribute{
+		Name: &name,
+		Type: &attrType,
+		Text: &mesos.Value_Text{Value: &value},
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Report summarizes the outcome of a simulation.
+type Report struct {
+	Strategy string `json:"strategy"`
+	Ranker   string `json:"ranker"`
+
+	// Makespan is the virtual time from the start of the simulation
+	// until it ended.
+	Makespan time.Duration `json:"makespan"`
+
+	TasksSubmitted int `json:"tasks_submitted"`
+	TasksCompleted int `json:"tasks_completed"`
+	// TasksUnfinished is the number of tasks which were still waiting or
+	// running when the simulation ended.
+	TasksUnfinished int `json:"tasks_unfinished"`
+	// Preemptions is the number of tasks evicted from over-allocated
+	// resource pools.
+	Preemptions int `json:"preemptions"`
+
+	// CPUUtilization and MemoryUtilization are the time-weighted average
+	// fractions of the cluster capacity used by running tasks.
+	CPUUtilization    float64 `json:"cpu_utilization"`
+	MemoryUtilization float64 `json:"memory_utilization"`
+	// Fragmentation is the time-weighted average fraction of the free
+	// CPU of the cluster which is stranded on partially used hosts.
+	Fragmentation float64 `json:"fragmentation"`
+
+	// QueueingDelay is the time from enqueuing a task until it started
+	// running, over all tasks and per resource pool.
+	QueueingDelay             DelayStats            `json:"queueing_delay"`
+	ResourcePoolQueueingDelay map[string]DelayStats `json:"respool_queueing_delay"`
+}
+
+// DelayStats summarizes a set of delays.
+type DelayStats struct {
+	Count int           `json:"count"`
+	Mean  time.Duration `json:"mean"`
+	P50   time.Duration `json:"p50"`
+	P99   time.Duration `json:"p99"`
+	Max   time.Duration `json:"max"`
+}
+
+// Print writes the report in a human readable format.
+func (r *Report) Print(out io.Writer) {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Strategy:\t%s\n", r.Strategy)
+	fmt.Fprintf(tw, "Ranker:\t%s\n", r.Ranker)
+	fmt.Fprintf(tw, "Makespan:\t%s\n", r.Makespan)
+	fmt.Fprintf(tw, "Tasks submitted:\t%d\n", r.TasksSubmitted)
+	fmt.Fprintf(tw, "Tasks completed:\t%d\n", r.TasksCompleted)
+	fmt.Fprintf(tw, "Tasks unfinished:\t%d\n", r.TasksUnfinished)
+	fmt.Fprintf(tw, "Preemptions:\t%d\n", r.Preemptions)
+	fmt.Fprintf(tw, "CPU utilization:\t%.2f%%\n", r.CPUUtilization*100)
+	fmt.Fprintf(tw, "Memory utilization:\t%.2f%%\n", r.MemoryUtilization*100)
+	fmt.Fprintf(tw, "Fragmentation:\t%.2f%%\n", r.Fragmentation*100)
+	tw.Flush()
+
+	fmt.Fprintln(out)
+	tw = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Queueing delay\tTasks\tMean\tP50\tP99\tMax\t")
+	printDelayStats(tw, "all", r.QueueingDelay)
+	var pools []string
+	for pool := range r.ResourcePoolQueueingDelay {
+		pools = append(pools, pool)
+	}
+	sort.Strings(pools)
+	for _, pool := range pools {
+		printDelayStats(tw, pool, r.ResourcePoolQueueingDelay[pool])
+	}
+	tw.Flush()
+}
+
+func printDelayStats(out io.Writer, name string, d DelayStats) {
+	fmt.Fprintf(out, "%s\t%d\t%s\t%s\t%s\t%s\t\n",
+		name, d.Count, d.Mean, d.P50, d.P99, d.Max)
+}
+
+// stats collects the measurements of a simulation.
+type stats struct {
+	submitted   int
+	completed   int
+	preemptions int
+
+	// time-weighted sums of the samples, and the total sampled time
+	cpuUtilization float64
+	memUtilization float64
+	sampledTime    float64
+	fragmentation  float64
+	fragmentedTime float64
+
+	delays     []time.Duration
+	poolDelays map[string][]time.Duration
+}
+
+func newStats() *stats {
+	return &stats{
+		poolDelays: make(map[string][]time.Duration),
+	}
+}
+
+// start records the queueing delay of a task which started running.
+func (s *stats) start(t *simTask) {
+	delay := t.started.Sub(t.enqueued)
+	s.delays = append(s.delays, delay)
+	pool := t.pool.Name()
+	s.poolDelays[pool] = append(s.poolDelays[pool], delay)
+}
+
+// sample records the utilization and fragmentation of the cluster, which
+// stays the same for the given duration.
+func (s *stats) sample(c *cluster, d time.Duration) {
+	var capacityCPU, capacityMem, usedCPU, usedMem float64
+	var freeCPU, strandedCPU float64
+	for _, h := range c.hosts {
+		capacityCPU += h.capacity.CPU
+		capacityMem += h.capacity.Mem
+		usedCPU += h.used.CPU
+		usedMem += h.used.Mem
+
+		free := h.free().CPU
+		freeCPU += free
+		if len(h.tasks) > 0 {
+			strandedCPU += free
+		}
+	}
+
+	seconds := d.Seconds()
+	s.sampledTime += seconds
+	if capacityCPU > 0 {
+		s.cpuUtilization += usedCPU / capacityCPU * seconds
+	}
+	if capacityMem > 0 {
+		s.memUtilization += usedMem / capacityMem * seconds
+	}
+	if freeCPU > 0 {
+		s.fragmentation += strandedCPU / freeCPU * seconds
+		s.fragmentedTime += seconds
+	}
+}
+
+// report creates the report from the collected stats.
+func (s *stats) report(
+	config Config,
+	makespan time.Duration,
+	unfinished int) *Report {
+	r := &Report{
+		Strategy:                  string(config.Strategy),
+		Ranker:                    config.Ranker,
+		Makespan:                  makespan,
+		TasksSubmitted:            s.submitted,
+		TasksCompleted:            s.completed,
+		TasksUnfinished:           unfinished,
+		Preemptions:               s.preemptions,
+		QueueingDelay:             newDelayStats(s.delays),
+		ResourcePoolQueueingDelay: make(map[string]DelayStats),
+	}
+	if s.sampledTime > 0 {
+		r.CPUUtilization = s.cpuUtilization / s.sampledTime
+		r.MemoryUtilization = s.memUtilization / s.sampledTime
+	}
+	if s.fragmentedTime > 0 {
+		r.Fragmentation = s.fragmentation / s.fragmentedTime
+	}
+	for pool, delays := range s.poolDelays {
+		r.ResourcePoolQueueingDelay[pool] = newDelayStats(delays)
+	}
+	return r
+}
+
+// newDelayStats summarizes the delays.
+func newDelayStats(delays []time.Duration) DelayStats {
+	if len(delays) == 0 {
+		return DelayStats{}
+	}
+	sorted := make([]time.Duration, len(delays))
+	copy(sorted, delays)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	return DelayStats{
+		Count: len(sorted),
+		Mean:  total / time.Duration(len(sorted)),
+		P50:   percentile(sorted, 0.5),
+		P99:   percentile(sorted, 0.99),
+		Max:   sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted delays.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
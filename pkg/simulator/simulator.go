@@ -0,0 +1,724 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package simulator replays recorded cluster traces against the resource
+// manager, placement and host manager policies in virtual time, so that
+// policies can be compared offline before they are deployed.
+//
+// The simulator drives the real resource pool tree, entitlement
+// calculator and admission control of the resource manager, the real
+// offer pool and bin packing ranker of the host manager and the real
+// placement strategies. The periodic loops of those components run on
+// wall clock timers, so the simulator runs their individual steps itself:
+// one entitlement calculation per entitlement period, admission and one
+// placement round per tick and one preemption cycle per preemption period.
+// The tasks are tracked by the resource manager task tracker and move
+// through its state machine as the simulator enqueues, admits, launches
+// and completes them, so that the real preemptor ranks and evicts them.
+// The task tracker is a process wide singleton, so only one simulation
+// can run at a time in a process. Preemption for host reservations is not
+// simulated.
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/api/v0/job"
+	"github.com/uber/peloton/.gen/peloton/api/v0/peloton"
+	"github.com/uber/peloton/.gen/peloton/api/v0/task"
+	"github.com/uber/peloton/.gen/peloton/private/hostmgr/hostsvc"
+	"github.com/uber/peloton/.gen/peloton/private/resmgr"
+	"github.com/uber/peloton/.gen/peloton/private/resmgrsvc"
+
+	"github.com/uber/peloton/pkg/common/constraints"
+	"github.com/uber/peloton/pkg/common/queue"
+	taskutil "github.com/uber/peloton/pkg/common/util/task"
+	"github.com/uber/peloton/pkg/hostmgr/binpacking"
+	placementconfig "github.com/uber/peloton/pkg/placement/config"
+	"github.com/uber/peloton/pkg/placement/models"
+	"github.com/uber/peloton/pkg/placement/plugins"
+	"github.com/uber/peloton/pkg/placement/plugins/registry"
+	rc "github.com/uber/peloton/pkg/resmgr/common"
+	"github.com/uber/peloton/pkg/resmgr/entitlement"
+	"github.com/uber/peloton/pkg/resmgr/preemption"
+	"github.com/uber/peloton/pkg/resmgr/respool"
+	rmtask "github.com/uber/peloton/pkg/resmgr/task"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/uber-go/tally"
+)
+
+// Config is the policy configuration the simulator runs with.
+type Config struct {
+	// Strategy is the placement strategy, batch, mimir or scoring.
+	Strategy placementconfig.PlacementStrategy
+	// Scoring is the config of the scoring strategy.
+	Scoring placementconfig.ScoringConfig
+	// Ranker is the host manager bin packing ranker, DEFRAG or FIRST_FIT.
+	Ranker string
+	// OfferDequeueLimit is the maximum number of hosts the mimir strategy
+	// asks for per placement round.
+	OfferDequeueLimit int
+
+	// Tick is the virtual time between admission and placement rounds.
+	Tick time.Duration
+	// EntitlementPeriod is the virtual time between entitlement
+	// calculations.
+	EntitlementPeriod time.Duration
+	// DequeueLimit is the maximum number of gangs admitted from each
+	// resource pool per tick.
+	DequeueLimit int
+	// Preemption configures the preemption of tasks from resource pools
+	// whose allocation exceeds their entitlement.
+	Preemption rc.PreemptionConfig
+
+	// MaxDuration bounds the virtual time of the simulation.
+	MaxDuration time.Duration
+}
+
+// _preemptionDequeueWait is how long the simulator waits for the next
+// running task in the preemption queue once a preemption cycle is done.
+const _preemptionDequeueWait = time.Millisecond
+
+// _rmTaskConfig configures the resource manager tasks. The timeouts only
+// keep the wall clock timers of the task state machine from firing, the
+// tasks never wait in the states with timeouts across ticks.
+var _rmTaskConfig = &rmtask.Config{
+	LaunchingTimeout: 24 * time.Hour,
+	PlacingTimeout:   24 * time.Hour,
+	ReservingTimeout: 24 * time.Hour,
+	PolicyName:       rmtask.ExponentialBackOffPolicy,
+}
+
+// simTask is a task of the trace.
+type simTask struct {
+	task     *resmgr.Task
+	gang     *resmgrsvc.Gang
+	pool     respool.ResPool
+	duration time.Duration
+
+	// enqueued is when the current attempt to run the task was enqueued,
+	// started is when it started running.
+	enqueued time.Time
+	started  time.Time
+	host     *simHost
+}
+
+// finish returns when the running task completes.
+func (t *simTask) finish() time.Time {
+	return t.started.Add(t.duration)
+}
+
+// Simulator replays a trace.
+type Simulator struct {
+	config Config
+
+	tree       respool.Tree
+	calculator *entitlement.Calculator
+	tracker    rmtask.Tracker
+	preemptor  *preemption.Preemptor
+	strategy   plugins.Strategy
+	cluster    *cluster
+
+	start time.Time
+	now   time.Time
+
+	// jobs sorted by submit time, nextJob is the index of the next job
+	// to be submitted.
+	jobs    []*JobSpec
+	nextJob int
+
+	// queued tasks are enqueued in their resource pool, ready tasks are
+	// admitted and waiting to be placed, running tasks are launched.
+	queued  map[string]*simTask
+	ready   []*simTask
+	running map[string]*simTask
+
+	stats *stats
+}
+
+// New creates a simulator for the trace. The bin packing rankers must
+// have been registered with binpacking.Init.
+func New(trace *Trace, config Config, scope tally.Scope) (*Simulator, error) {
+	if config.Tick <= 0 {
+		return nil, errors.New("tick must be positive")
+	}
+	if config.EntitlementPeriod <= 0 {
+		return nil, errors.New("entitlement period must be positive")
+	}
+	if config.DequeueLimit <= 0 {
+		return nil, errors.New("dequeue limit must be positive")
+	}
+	if config.Preemption.Enabled && config.Preemption.TaskPreemptionPeriod <= 0 {
+		return nil, errors.New("task preemption period must be positive")
+	}
+
+	ranker := binpacking.CreateRanker(config.Ranker)
+	if ranker == nil {
+		return nil, errors.Errorf("unknown bin packing ranker %s", config.Ranker)
+	}
+
+	strategy := registry.CreateStrategy(&placementconfig.PlacementConfig{
+		Strategy:          config.Strategy,
+		TaskType:          resmgr.TaskType_BATCH,
+		OfferDequeueLimit: config.OfferDequeueLimit,
+		FetchOfferTasks:   config.Strategy == placementconfig.Mimir,
+		Scoring:           config.Scoring,
+	})
+	if strategy == nil {
+		return nil, errors.Errorf("unknown placement strategy %s", config.Strategy)
+	}
+
+	c := newCluster(trace.Hosts, ranker, scope)
+
+	tree := respool.NewTree(
+		scope,
+		newRespoolStore(trace.ResourcePools),
+		nil,
+		nil,
+		config.Preemption)
+	if err := tree.Start(); err != nil {
+		return nil, errors.Wrap(err, "failed to start resource pool tree")
+	}
+
+	rmtask.InitTaskTracker(scope, _rmTaskConfig)
+	tracker := rmtask.GetTracker()
+	tracker.Clear()
+
+	// Host reservations are not simulated, so there are no reserved hosts
+	// to preempt tasks from.
+	preemptionConfig := config.Preemption
+	preemptionConfig.PriorityPreemption.Enabled = false
+
+	jobs := make([]*JobSpec, len(trace.Jobs))
+	copy(jobs, trace.Jobs)
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].SubmitTime < jobs[j].SubmitTime
+	})
+
+	start := time.Unix(0, 0).UTC()
+	return &Simulator{
+		config: config,
+		tree:   tree,
+		calculator: entitlement.NewCalculator(
+			config.EntitlementPeriod,
+			scope,
+			newCapacityClient(c.hosts),
+			tree),
+		tracker: tracker,
+		preemptor: preemption.NewPreemptor(
+			scope,
+			&preemptionConfig,
+			tracker,
+			tree,
+			nil),
+		strategy: strategy,
+		cluster:  c,
+		start:    start,
+		now:      start,
+		jobs:     jobs,
+		queued:   make(map[string]*simTask),
+		running:  make(map[string]*simTask),
+		stats:    newStats(),
+	}, nil
+}
+
+// Run replays the trace until all tasks completed, the remaining tasks
+// can't make progress any more or the maximum duration is reached.
+func (s *Simulator) Run(ctx context.Context) (*Report, error) {
+	nextEntitlement := s.now
+	nextPreemption := s.now.Add(s.config.Preemption.TaskPreemptionPeriod)
+
+	for {
+		if err := s.completeTasks(); err != nil {
+			return nil, err
+		}
+		if err := s.submitJobs(); err != nil {
+			return nil, err
+		}
+
+		entitlementCalculated := false
+		if !s.now.Before(nextEntitlement) {
+			if err := s.calculator.RunOnce(ctx); err != nil {
+				return nil, errors.Wrap(err, "entitlement calculation failed")
+			}
+			nextEntitlement = s.now.Add(s.config.EntitlementPeriod)
+			entitlementCalculated = true
+		}
+
+		if s.config.Preemption.Enabled && !s.now.Before(nextPreemption) {
+			if err := s.preempt(); err != nil {
+				return nil, err
+			}
+			nextPreemption = s.now.Add(s.config.Preemption.TaskPreemptionPeriod)
+		}
+
+		s.cluster.offer(ctx)
+		admitted, err := s.admit()
+		if err != nil {
+			return nil, err
+		}
+		placed, err := s.place(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.nextJob == len(s.jobs) && len(s.running) == 0 {
+			if len(s.queued) == 0 && len(s.ready) == 0 {
+				break
+			}
+			// Nothing will change the state of the cluster any more,
+			// the remaining tasks can't be admitted or placed.
+			if entitlementCalculated && admitted == 0 && placed == 0 {
+				log.WithFields(log.Fields{
+					"queued": len(s.queued),
+					"ready":  len(s.ready),
+				}).Warn("Remaining tasks can't be admitted or placed")
+				break
+			}
+		}
+		if s.config.MaxDuration > 0 &&
+			s.now.Sub(s.start) >= s.config.MaxDuration {
+			log.WithField("max_duration", s.config.MaxDuration).
+				Warn("Simulation reached the maximum duration")
+			break
+		}
+
+		next := s.nextTick()
+		s.stats.sample(s.cluster, next.Sub(s.now))
+		s.now = next
+	}
+
+	return s.report(), nil
+}
+
+// nextTick returns the virtual time of the next round. While no tasks
+// are waiting to be admitted or placed the simulation skips ahead to
+// the next job submission or task completion.
+func (s *Simulator) nextTick() time.Time {
+	next := s.now.Add(s.config.Tick)
+	if len(s.queued) > 0 || len(s.ready) > 0 {
+		return next
+	}
+
+	var event time.Time
+	if s.nextJob < len(s.jobs) {
+		event = s.start.Add(s.jobs[s.nextJob].SubmitTime)
+	}
+	for _, t := range s.running {
+		if event.IsZero() || t.finish().Before(event) {
+			event = t.finish()
+		}
+	}
+	if event.After(next) {
+		// align the event to the tick
+		ticks := (event.Sub(s.now) + s.config.Tick - 1) / s.config.Tick
+		next = s.now.Add(ticks * s.config.Tick)
+	}
+	return next
+}
+
+// submitJobs enqueues the tasks of the jobs submitted by now into their
+// resource pools, as the job manager does on job creation.
+func (s *Simulator) submitJobs() error {
+	for ; s.nextJob < len(s.jobs); s.nextJob++ {
+		spec := s.jobs[s.nextJob]
+		if s.start.Add(spec.SubmitTime).After(s.now) {
+			return nil
+		}
+		if err := s.submitJob(spec, s.nextJob); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Simulator) submitJob(spec *JobSpec, index int) error {
+	pool, err := s.tree.Get(&peloton.ResourcePoolID{Value: spec.ResourcePool})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get resource pool of job %s", spec.Name)
+	}
+
+	jobID := &peloton.JobID{Value: fmt.Sprintf("%s.%d", spec.Name, index)}
+	jobConfig := &job.JobConfig{
+		Name: spec.Name,
+		Type: job.JobType_BATCH,
+		SLA: &job.SlaConfig{
+			Priority:                spec.Priority,
+			Preemptible:             spec.Preemptible,
+			MinimumRunningInstances: spec.MinInstances,
+		},
+		RespoolID:     &peloton.ResourcePoolID{Value: spec.ResourcePool},
+		InstanceCount: spec.Instances,
+		DefaultConfig: &task.TaskConfig{
+			Name: spec.Name,
+			Resource: &task.ResourceConfig{
+				CpuLimit:    spec.CPU,
+				MemLimitMb:  spec.MemMb,
+				DiskLimitMb: spec.DiskMb,
+				GpuLimit:    spec.GPU,
+			},
+		},
+	}
+
+	taskInfos := make([]*task.TaskInfo, 0, spec.Instances)
+	durations := make(map[string]time.Duration)
+	for i := uint32(0); i < spec.Instances; i++ {
+		taskInfos = append(taskInfos, &task.TaskInfo{
+			JobId:      jobID,
+			InstanceId: i,
+			Config:     jobConfig.GetDefaultConfig(),
+			Runtime:    &task.RuntimeInfo{},
+		})
+		durations[fmt.Sprintf("%s-%d", jobID.GetValue(), i)] =
+			spec.instanceDuration(i)
+	}
+
+	for _, gang := range taskutil.ConvertToResMgrGangs(taskInfos, jobConfig) {
+		if err := s.enqueue(gang, pool); err != nil {
+			return errors.Wrapf(err, "failed to enqueue gang of job %s", spec.Name)
+		}
+		for _, rmTask := range gang.GetTasks() {
+			s.queued[rmTask.GetId().GetValue()] = &simTask{
+				task:     rmTask,
+				gang:     gang,
+				pool:     pool,
+				duration: durations[rmTask.GetId().GetValue()],
+				enqueued: s.now,
+			}
+			s.stats.submitted++
+		}
+	}
+	return nil
+}
+
+// enqueue adds the tasks of the gang to the task tracker and enqueues the
+// gang in the resource pool, as the resource manager does on EnqueueGangs.
+func (s *Simulator) enqueue(gang *resmgrsvc.Gang, pool respool.ResPool) error {
+	for _, t := range gang.GetTasks() {
+		if err := s.tracker.AddTask(t, nil, pool, _rmTaskConfig); err != nil {
+			return errors.Wrapf(err,
+				"failed to add task %s to tracker", t.GetId().GetValue())
+		}
+		if err := s.transit(t, task.TaskState_PENDING); err != nil {
+			return err
+		}
+	}
+	return pool.EnqueueGang(gang)
+}
+
+// transit moves the tracked task through the given states.
+func (s *Simulator) transit(t *resmgr.Task, states ...task.TaskState) error {
+	rmTask := s.tracker.GetTask(t.GetId())
+	if rmTask == nil {
+		return errors.Errorf("task %s is not tracked", t.GetId().GetValue())
+	}
+	for _, state := range states {
+		if err := rmTask.TransitTo(state.String()); err != nil {
+			return errors.Wrapf(err,
+				"failed to transit task %s to %s", t.GetId().GetValue(), state)
+		}
+	}
+	return nil
+}
+
+// completeTasks terminates the running tasks which finished by now and
+// releases their resources.
+func (s *Simulator) completeTasks() error {
+	for _, t := range s.sortedRunning() {
+		if t.finish().After(s.now) {
+			continue
+		}
+		if err := s.stop(t); err != nil {
+			return err
+		}
+		s.stats.completed++
+	}
+	return nil
+}
+
+// stop kills a running task and removes it from the task tracker, which
+// removes its allocation from its pool.
+func (s *Simulator) stop(t *simTask) error {
+	s.cluster.kill(t)
+	delete(s.running, t.task.GetId().GetValue())
+	if err := s.tracker.MarkItDone(
+		t.task.GetId(),
+		t.task.GetTaskId().GetValue()); err != nil {
+		return errors.Wrapf(err,
+			"failed to remove task %s from tracker", t.task.GetId().GetValue())
+	}
+	return nil
+}
+
+// admit dequeues the gangs which are admitted by their resource pools,
+// returning the number of admitted tasks.
+func (s *Simulator) admit() (int, error) {
+	admitted := 0
+	for _, pool := range s.leafPools() {
+		gangs, err := pool.DequeueGangs(s.config.DequeueLimit)
+		if err != nil {
+			return admitted, errors.Wrapf(err,
+				"failed to dequeue gangs from resource pool %s", pool.ID())
+		}
+		for _, gang := range gangs {
+			for _, rmTask := range gang.GetTasks() {
+				id := rmTask.GetId().GetValue()
+				t, ok := s.queued[id]
+				if !ok {
+					return admitted, errors.Errorf("unknown task %s admitted", id)
+				}
+				if err := s.transit(rmTask, task.TaskState_READY); err != nil {
+					return admitted, err
+				}
+				delete(s.queued, id)
+				t.gang = gang
+				s.ready = append(s.ready, t)
+				admitted++
+			}
+		}
+	}
+	return admitted, nil
+}
+
+// jobHosts returns the hosts running the tasks of the jobs whose tasks
+// have topology constraints with limits. Like the placement engine, they
+// are only fetched for strategies which honor constraints spanning several
+// hosts.
+func (s *Simulator) jobHosts(assignments []*models.Assignment) []*models.Host {
+	if _, ok := s.strategy.(plugins.ScopedStrategy); !ok {
+		return nil
+	}
+
+	jobIDs := make(map[string]struct{})
+	for _, assignment := range assignments {
+		task := assignment.GetTask().GetTask()
+		if constraints.HasTopologyLimits(task.GetConstraint()) {
+			jobIDs[task.GetJobId().GetValue()] = struct{}{}
+		}
+	}
+	return s.cluster.jobHosts(jobIDs)
+}
+
+// placeOnce places the assignments on the hosts with the placement
+// strategy, strategies which honor constraints spanning several hosts also
+// get the hosts of the jobs.
+func (s *Simulator) placeOnce(
+	assignments []*models.Assignment,
+	hosts []*models.HostOffers,
+	scope []*models.Host) {
+	strategy, ok := s.strategy.(plugins.ScopedStrategy)
+	if !ok {
+		s.strategy.PlaceOnce(assignments, hosts)
+		return
+	}
+	strategy.PlaceOnceInScope(assignments, hosts, scope)
+}
+
+// place runs one placement round with the placement strategy for the
+// admitted tasks, returning the number of launched tasks.
+func (s *Simulator) place(ctx context.Context) (int, error) {
+	if len(s.ready) == 0 {
+		return 0, nil
+	}
+
+	tasks := make(map[string]*simTask, len(s.ready))
+	order := make(map[*models.Assignment]int, len(s.ready))
+	var assignments []*models.Assignment
+	for i, t := range s.ready {
+		assignment := models.NewAssignment(models.NewTask(
+			t.gang,
+			t.task,
+			s.now.Add(s.config.Tick),
+			s.now.Add(s.config.Tick),
+			1))
+		tasks[t.task.GetId().GetValue()] = t
+		order[assignment] = i
+		assignments = append(assignments, assignment)
+	}
+
+	// Filters returns a map, so order the groups by their first task to
+	// make runs reproducible.
+	filters := s.strategy.Filters(assignments)
+	groups := make([]*hostsvc.HostFilter, 0, len(filters))
+	for filter := range filters {
+		groups = append(groups, filter)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return order[filters[groups[i]][0]] < order[filters[groups[j]][0]]
+	})
+
+	placed := 0
+	for _, filter := range groups {
+		batch := filters[filter]
+		scope := s.jobHosts(batch)
+		hosts, err := s.cluster.acquire(
+			plugins.WithTopologyDomainCounts(filter, batch, scope), s.now)
+		if err != nil {
+			return placed, err
+		}
+		if len(hosts) == 0 {
+			continue
+		}
+		s.placeOnce(batch, hosts, scope)
+
+		launches := make(map[string][]*simTask)
+		for _, assignment := range batch {
+			host := assignment.GetHost()
+			if host == nil {
+				continue
+			}
+			hostname := host.GetOffer().GetHostname()
+			launches[hostname] = append(
+				launches[hostname],
+				tasks[assignment.GetTask().GetTask().GetId().GetValue()])
+		}
+
+		for _, host := range hosts {
+			launched := launches[host.GetOffer().GetHostname()]
+			if len(launched) == 0 {
+				if err := s.cluster.release(host); err != nil {
+					return placed, errors.Wrap(err, "failed to return host")
+				}
+				continue
+			}
+			if err := s.cluster.launch(host, launched); err != nil {
+				return placed, err
+			}
+			for _, t := range launched {
+				if err := s.transit(
+					t.task,
+					task.TaskState_PLACED,
+					task.TaskState_LAUNCHING,
+					task.TaskState_RUNNING); err != nil {
+					return placed, err
+				}
+				// the state machine records the wall clock start time
+				s.tracker.GetTask(t.task.GetId()).UpdateStartTime(s.now)
+				t.started = s.now
+				s.running[t.task.GetId().GetValue()] = t
+				s.stats.start(t)
+				placed++
+			}
+		}
+		// offer the resources left on the launched hosts to the
+		// following groups
+		s.cluster.offer(ctx)
+	}
+
+	var ready []*simTask
+	for _, t := range s.ready {
+		if t.host == nil {
+			ready = append(ready, t)
+		}
+	}
+	s.ready = ready
+	return placed, nil
+}
+
+// preempt runs one cycle of the resource manager preemptor. The preemptor
+// moves the admitted tasks it evicts back to the pending queue of their
+// pool itself and hands the running tasks to the job manager through the
+// preemption queue, so the simulator kills and enqueues those again.
+func (s *Simulator) preempt() error {
+	if err := s.preemptor.RunOnce(); err != nil {
+		return errors.Wrap(err, "preemption cycle failed")
+	}
+
+	var ready []*simTask
+	for _, t := range s.ready {
+		rmTask := s.tracker.GetTask(t.task.GetId())
+		if rmTask.GetCurrentState().State != task.TaskState_PENDING {
+			ready = append(ready, t)
+			continue
+		}
+		t.gang = &resmgrsvc.Gang{Tasks: []*resmgr.Task{t.task}}
+		t.enqueued = s.now
+		s.queued[t.task.GetId().GetValue()] = t
+		s.stats.preemptions++
+	}
+	s.ready = ready
+
+	for {
+		candidate, err := s.preemptor.DequeueTask(_preemptionDequeueWait)
+		if err != nil {
+			if _, ok := err.(queue.DequeueTimeOutError); ok {
+				return nil
+			}
+			return errors.Wrap(err, "failed to dequeue preempted task")
+		}
+		t, ok := s.running[candidate.GetId().GetValue()]
+		if !ok {
+			continue
+		}
+		if err := s.evict(t); err != nil {
+			return err
+		}
+	}
+}
+
+// evict kills a preempted running task and enqueues it in its resource pool
+// again, as the job manager does for the tasks in the preemption queue.
+func (s *Simulator) evict(t *simTask) error {
+	if err := s.stop(t); err != nil {
+		return err
+	}
+
+	t.gang = &resmgrsvc.Gang{Tasks: []*resmgr.Task{t.task}}
+	t.enqueued = s.now
+	if err := s.enqueue(t.gang, t.pool); err != nil {
+		return errors.Wrapf(err,
+			"failed to enqueue preempted task %s", t.task.GetId().GetValue())
+	}
+	s.queued[t.task.GetId().GetValue()] = t
+	s.stats.preemptions++
+	return nil
+}
+
+// leafPools returns the leaf resource pools sorted by id.
+func (s *Simulator) leafPools() []respool.ResPool {
+	var pools []respool.ResPool
+	nodes := s.tree.GetAllNodes(true)
+	for e := nodes.Front(); e != nil; e = e.Next() {
+		pools = append(pools, e.Value.(respool.ResPool))
+	}
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].ID() < pools[j].ID()
+	})
+	return pools
+}
+
+// sortedRunning returns the running tasks sorted by task id.
+func (s *Simulator) sortedRunning() []*simTask {
+	tasks := make([]*simTask, 0, len(s.running))
+	for _, t := range s.running {
+		tasks = append(tasks, t)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].task.GetId().GetValue() < tasks[j].task.GetId().GetValue()
+	})
+	return tasks
+}
+
+// report builds the report of the simulation.
+func (s *Simulator) report() *Report {
+	return s.stats.report(
+		s.config,
+		s.now.Sub(s.start),
+		len(s.queued)+len(s.ready)+len(s.running))
+}
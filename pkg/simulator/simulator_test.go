@@ -0,0 +1,297 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/uber/peloton/pkg/common"
+	"github.com/uber/peloton/pkg/hostmgr/binpacking"
+	placementconfig "github.com/uber/peloton/pkg/placement/config"
+	"github.com/uber/peloton/pkg/placement/plugins/registry"
+	rc "github.com/uber/peloton/pkg/resmgr/common"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+)
+
+type SimulatorTestSuite struct {
+	suite.Suite
+}
+
+func TestSimulator(t *testing.T) {
+	suite.Run(t, new(SimulatorTestSuite))
+}
+
+func (suite *SimulatorTestSuite) SetupSuite() {
+	binpacking.Init()
+	registry.Init()
+}
+
+func (suite *SimulatorTestSuite) config() Config {
+	return Config{
+		Strategy:          placementconfig.Batch,
+		Ranker:            binpacking.FirstFit,
+		OfferDequeueLimit: 10,
+		Tick:              time.Second,
+		EntitlementPeriod: 5 * time.Second,
+		DequeueLimit:      100,
+		MaxDuration:       time.Hour,
+	}
+}
+
+func (suite *SimulatorTestSuite) trace(jobs ...*JobSpec) *Trace {
+	var resources []*ResourceSpec
+	for _, kind := range []string{
+		common.CPU, common.MEMORY, common.DISK, common.GPU} {
+		resources = append(resources, &ResourceSpec{
+			Kind:        kind,
+			Reservation: 0,
+			Limit:       100000,
+			Share:       1,
+		})
+	}
+	resources[0].Reservation = 8
+	resources[1].Reservation = 16384
+	resources[2].Reservation = 2000
+
+	return &Trace{
+		Hosts: []*HostSpec{
+			{
+				Name:   "host",
+				Count:  2,
+				CPU:    4,
+				MemMb:  8192,
+				DiskMb: 1000,
+			},
+		},
+		ResourcePools: []*ResourcePoolSpec{
+			{
+				Name:      "batch",
+				Resources: resources,
+			},
+		},
+		Jobs: jobs,
+	}
+}
+
+func (suite *SimulatorTestSuite) run(
+	trace *Trace,
+	config Config) *Report {
+	suite.NoError(trace.validate())
+	sim, err := New(trace, config, tally.NoopScope)
+	suite.NoError(err)
+	report, err := sim.Run(context.Background())
+	suite.NoError(err)
+	return report
+}
+
+// TestRunCompletesAllTasks tests that a job which fits the cluster is
+// placed right away and runs to completion.
+func (suite *SimulatorTestSuite) TestRunCompletesAllTasks() {
+	report := suite.run(suite.trace(&JobSpec{
+		Name:         "job",
+		ResourcePool: "batch",
+		Instances:    4,
+		Preemptible:  true,
+		CPU:          2,
+		MemMb:        1024,
+		DiskMb:       100,
+		Duration:     10 * time.Second,
+	}), suite.config())
+
+	suite.Equal(4, report.TasksSubmitted)
+	suite.Equal(4, report.TasksCompleted)
+	suite.Equal(0, report.TasksUnfinished)
+	suite.Equal(0, report.Preemptions)
+	suite.Equal(10*time.Second, report.Makespan)
+	suite.Equal(4, report.QueueingDelay.Count)
+	suite.Equal(time.Duration(0), report.QueueingDelay.Max)
+	suite.Equal(4, report.ResourcePoolQueueingDelay["batch"].Count)
+	suite.InDelta(1.0, report.CPUUtilization, 0.001)
+}
+
+// TestRunScoringStrategy tests that the scoring strategy places all tasks.
+func (suite *SimulatorTestSuite) TestRunScoringStrategy() {
+	config := suite.config()
+	config.Strategy = placementconfig.Scoring
+	config.Scoring.Policy = placementconfig.LeastAllocated
+	report := suite.run(suite.trace(&JobSpec{
+		Name:         "job",
+		ResourcePool: "batch",
+		Instances:    4,
+		Preemptible:  true,
+		CPU:          2,
+		MemMb:        1024,
+		DiskMb:       100,
+		Duration:     10 * time.Second,
+	}), config)
+
+	suite.Equal(4, report.TasksSubmitted)
+	suite.Equal(4, report.TasksCompleted)
+	suite.Equal(0, report.TasksUnfinished)
+}
+
+// TestRunQueuesTasksUntilResourcesAreFree tests that tasks which don't fit
+// the cluster wait for running tasks to complete.
+func (suite *SimulatorTestSuite) TestRunQueuesTasksUntilResourcesAreFree() {
+	report := suite.run(suite.trace(&JobSpec{
+		Name:         "job",
+		ResourcePool: "batch",
+		Instances:    8,
+		Preemptible:  true,
+		CPU:          2,
+		MemMb:        1024,
+		DiskMb:       100,
+		Duration:     10 * time.Second,
+	}), suite.config())
+
+	suite.Equal(8, report.TasksCompleted)
+	suite.Equal(0, report.TasksUnfinished)
+	suite.True(report.Makespan >= 20*time.Second)
+	suite.True(report.QueueingDelay.Max >= 10*time.Second)
+}
+
+// TestRunStopsWhenTasksCantBePlaced tests that the simulation ends when the
+// remaining tasks can't be placed on any host.
+func (suite *SimulatorTestSuite) TestRunStopsWhenTasksCantBePlaced() {
+	report := suite.run(suite.trace(&JobSpec{
+		Name:         "job",
+		ResourcePool: "batch",
+		Instances:    1,
+		Preemptible:  true,
+		CPU:          6,
+		MemMb:        1024,
+		Duration:     10 * time.Second,
+	}), suite.config())
+
+	suite.Equal(1, report.TasksSubmitted)
+	suite.Equal(0, report.TasksCompleted)
+	suite.Equal(1, report.TasksUnfinished)
+	suite.True(report.Makespan < time.Hour)
+}
+
+// TestRunPreemptsOverAllocatedPool tests that the preemptor evicts running
+// tasks from a pool which lost its entitlement to a pool with new demand,
+// and that the evicted tasks run again later.
+func (suite *SimulatorTestSuite) TestRunPreemptsOverAllocatedPool() {
+	trace := suite.trace(
+		&JobSpec{
+			Name:         "batch-job",
+			ResourcePool: "batch",
+			Instances:    4,
+			Preemptible:  true,
+			CPU:          2,
+			MemMb:        1024,
+			DiskMb:       100,
+			Duration:     100 * time.Second,
+		},
+		&JobSpec{
+			Name:         "service-job",
+			ResourcePool: "service",
+			SubmitTime:   10 * time.Second,
+			Instances:    2,
+			CPU:          2,
+			MemMb:        1024,
+			DiskMb:       100,
+			Duration:     10 * time.Second,
+		})
+
+	// split the reservation of the cluster between the two pools
+	var resources []*ResourceSpec
+	for _, r := range trace.ResourcePools[0].Resources {
+		r.Reservation /= 2
+		resources = append(resources, &ResourceSpec{
+			Kind:        r.Kind,
+			Reservation: r.Reservation,
+			Limit:       r.Limit,
+			Share:       r.Share,
+		})
+	}
+	trace.ResourcePools = append(trace.ResourcePools, &ResourcePoolSpec{
+		Name:      "service",
+		Resources: resources,
+	})
+
+	config := suite.config()
+	config.Preemption = rc.PreemptionConfig{
+		Enabled:                      true,
+		TaskPreemptionPeriod:         5 * time.Second,
+		SustainedOverAllocationCount: 1,
+	}
+	report := suite.run(trace, config)
+
+	suite.Equal(6, report.TasksSubmitted)
+	suite.Equal(6, report.TasksCompleted)
+	suite.Equal(0, report.TasksUnfinished)
+	suite.True(report.Preemptions > 0)
+	suite.True(report.Makespan > 100*time.Second)
+}
+
+// TestNewInvalidConfig tests that invalid configs are rejected.
+func (suite *SimulatorTestSuite) TestNewInvalidConfig() {
+	trace := suite.trace()
+
+	config := suite.config()
+	config.Tick = 0
+	_, err := New(trace, config, tally.NoopScope)
+	suite.Error(err)
+
+	config = suite.config()
+	config.Ranker = "UNKNOWN"
+	_, err = New(trace, config, tally.NoopScope)
+	suite.Error(err)
+
+	config = suite.config()
+	config.Strategy = placementconfig.PlacementStrategy("unknown")
+	_, err = New(trace, config, tally.NoopScope)
+	suite.Error(err)
+}
+
+// TestNewDelayStats tests the summary of queueing delays.
+func (suite *SimulatorTestSuite) TestNewDelayStats() {
+	suite.Equal(DelayStats{}, newDelayStats(nil))
+
+	var delays []time.Duration
+	for i := 100; i > 0; i-- {
+		delays = append(delays, time.Duration(i)*time.Second)
+	}
+	stats := newDelayStats(delays)
+	suite.Equal(100, stats.Count)
+	suite.Equal(50500*time.Millisecond, stats.Mean)
+	suite.Equal(50*time.Second, stats.P50)
+	suite.Equal(99*time.Second, stats.P99)
+	suite.Equal(100*time.Second, stats.Max)
+}
+
+// TestReportPrint tests that the report is printed with the delays of
+// every resource pool.
+func (suite *SimulatorTestSuite) TestReportPrint() {
+	report := &Report{
+		Strategy: "batch",
+		Ranker:   binpacking.FirstFit,
+		ResourcePoolQueueingDelay: map[string]DelayStats{
+			"batch": {Count: 1},
+		},
+	}
+	var out bytes.Buffer
+	report.Print(&out)
+	suite.Contains(out.String(), "Strategy:")
+	suite.Contains(out.String(), "batch")
+	suite.Contains(out.String(), "Queueing delay")
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Trace is a recorded cluster workload which is replayed by the simulator.
+type Trace struct {
+	// Hosts is the host inventory of the cluster.
+	Hosts []*HostSpec `yaml:"hosts"`
+	// ResourcePools is the resource pool hierarchy of the cluster.
+	ResourcePools []*ResourcePoolSpec `yaml:"respools"`
+	// Jobs are the job submissions, in any order.
+	Jobs []*JobSpec `yaml:"jobs"`
+}
+
+// HostSpec describes one host, or Count identical hosts, of the inventory.
+type HostSpec struct {
+	// Name of the host. If Count is larger than one, the hosts are
+	// named <name>-<index>.
+	Name  string `yaml:"name"`
+	Count int    `yaml:"count"`
+
+	CPU    float64 `yaml:"cpu"`
+	MemMb  float64 `yaml:"mem_mb"`
+	DiskMb float64 `yaml:"disk_mb"`
+	GPU    float64 `yaml:"gpu"`
+
+	// Attributes are the text attributes of the host which can be
+	// matched by task constraints.
+	Attributes map[string]string `yaml:"attributes"`
+}
+
+// ResourcePoolSpec describes a resource pool.
+type ResourcePoolSpec struct {
+	// Name is also used as the resource pool ID.
+	Name string `yaml:"name"`
+	// Parent is the name of the parent pool, the root pool if empty.
+	Parent    string          `yaml:"parent"`
+	Resources []*ResourceSpec `yaml:"resources"`
+}
+
+// ResourceSpec is the reservation, limit and share of one resource kind.
+type ResourceSpec struct {
+	Kind        string  `yaml:"kind"`
+	Reservation float64 `yaml:"reservation"`
+	Limit       float64 `yaml:"limit"`
+	Share       float64 `yaml:"share"`
+}
+
+// JobSpec describes a recorded job submission.
+type JobSpec struct {
+	Name         string        `yaml:"name"`
+	ResourcePool string        `yaml:"respool"`
+	SubmitTime   time.Duration `yaml:"submit_time"`
+	Instances    uint32        `yaml:"instances"`
+	MinInstances uint32        `yaml:"min_instances"`
+	Priority     uint32        `yaml:"priority"`
+	Preemptible  bool          `yaml:"preemptible"`
+
+	CPU    float64 `yaml:"cpu"`
+	MemMb  float64 `yaml:"mem_mb"`
+	DiskMb float64 `yaml:"disk_mb"`
+	GPU    float64 `yaml:"gpu"`
+
+	// Duration is the run time of every task of the job, unless it is
+	// overridden for an instance by Durations.
+	Duration  time.Duration   `yaml:"duration"`
+	Durations []time.Duration `yaml:"durations"`
+}
+
+// instanceDuration returns the recorded run time of an instance.
+func (j *JobSpec) instanceDuration(instanceID uint32) time.Duration {
+	if int(instanceID) < len(j.Durations) {
+		return j.Durations[instanceID]
+	}
+	return j.Duration
+}
+
+// hostnames returns the names of the hosts described by the spec.
+func (h *HostSpec) hostnames() []string {
+	if h.Count <= 1 {
+		return []string{h.Name}
+	}
+	names := make([]string, 0, h.Count)
+	for i := 0; i < h.Count; i++ {
+		names = append(names, fmt.Sprintf("%s-%d", h.Name, i))
+	}
+	return names
+}
+
+// LoadTrace reads a trace from a YAML file.
+func LoadTrace(path string) (*Trace, error) {
+	buffer, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read trace %s", path)
+	}
+	var trace Trace
+	if err := yaml.Unmarshal(buffer, &trace); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse trace %s", path)
+	}
+	if err := trace.validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid trace %s", path)
+	}
+	return &trace, nil
+}
+
+// validate checks that the trace is self-consistent.
+func (t *Trace) validate() error {
+	if len(t.Hosts) == 0 {
+		return errors.New("trace has no hosts")
+	}
+	hostnames := make(map[string]bool)
+	for _, h := range t.Hosts {
+		for _, name := range h.hostnames() {
+			if name == "" {
+				return errors.New("host has no name")
+			}
+			if hostnames[name] {
+				return errors.Errorf("duplicate host %s", name)
+			}
+			hostnames[name] = true
+		}
+	}
+
+	pools := make(map[string]*ResourcePoolSpec)
+	for _, p := range t.ResourcePools {
+		if p.Name == "" {
+			return errors.New("resource pool has no name")
+		}
+		if _, ok := pools[p.Name]; ok {
+			return errors.Errorf("duplicate resource pool %s", p.Name)
+		}
+		pools[p.Name] = p
+	}
+	parents := make(map[string]bool)
+	for _, p := range t.ResourcePools {
+		if p.Parent == "" {
+			continue
+		}
+		if _, ok := pools[p.Parent]; !ok {
+			return errors.Errorf(
+				"resource pool %s has unknown parent %s", p.Name, p.Parent)
+		}
+		parents[p.Parent] = true
+	}
+
+	for _, j := range t.Jobs {
+		if _, ok := pools[j.ResourcePool]; !ok {
+			return errors.Errorf(
+				"job %s has unknown resource pool %s", j.Name, j.ResourcePool)
+		}
+		if parents[j.ResourcePool] {
+			return errors.Errorf(
+				"job %s resource pool %s is not a leaf", j.Name, j.ResourcePool)
+		}
+		if j.Instances == 0 {
+			return errors.Errorf("job %s has no instances", j.Name)
+		}
+	}
+	return nil
+}
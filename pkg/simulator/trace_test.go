@@ -0,0 +1,148 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const _testTrace = `
+hosts:
+  - name: host
+    count: 3
+    cpu: 32
+    mem_mb: 65536
+    disk_mb: 100000
+    attributes:
+      rack: r1
+respools:
+  - name: org
+    resources:
+      - kind: cpu
+        reservation: 96
+        limit: 96
+        share: 1
+  - name: batch
+    parent: org
+    resources:
+      - kind: cpu
+        reservation: 48
+        limit: 96
+        share: 1
+jobs:
+  - name: job
+    respool: batch
+    submit_time: 30s
+    instances: 3
+    priority: 1
+    preemptible: true
+    cpu: 1
+    mem_mb: 128
+    duration: 5m
+    durations: [1m]
+`
+
+type TraceTestSuite struct {
+	suite.Suite
+}
+
+func TestTrace(t *testing.T) {
+	suite.Run(t, new(TraceTestSuite))
+}
+
+func (suite *TraceTestSuite) writeTrace(content string) string {
+	f, err := ioutil.TempFile("", "trace")
+	suite.NoError(err)
+	defer f.Close()
+	_, err = f.WriteString(content)
+	suite.NoError(err)
+	return f.Name()
+}
+
+// TestLoadTrace tests loading a trace from a file.
+func (suite *TraceTestSuite) TestLoadTrace() {
+	path := suite.writeTrace(_testTrace)
+	defer os.Remove(path)
+
+	trace, err := LoadTrace(path)
+	suite.NoError(err)
+	suite.Len(trace.Hosts, 1)
+	suite.Equal(
+		[]string{"host-0", "host-1", "host-2"},
+		trace.Hosts[0].hostnames())
+	suite.Equal("r1", trace.Hosts[0].Attributes["rack"])
+	suite.Len(trace.ResourcePools, 2)
+	suite.Equal("org", trace.ResourcePools[1].Parent)
+
+	suite.Len(trace.Jobs, 1)
+	job := trace.Jobs[0]
+	suite.Equal(30*time.Second, job.SubmitTime)
+	suite.Equal(time.Minute, job.instanceDuration(0))
+	suite.Equal(5*time.Minute, job.instanceDuration(1))
+}
+
+// TestLoadTraceErrors tests loading invalid and missing traces.
+func (suite *TraceTestSuite) TestLoadTraceErrors() {
+	_, err := LoadTrace("/does/not/exist")
+	suite.Error(err)
+
+	path := suite.writeTrace("hosts: [")
+	defer os.Remove(path)
+	_, err = LoadTrace(path)
+	suite.Error(err)
+}
+
+// TestValidate tests the validation of traces.
+func (suite *TraceTestSuite) TestValidate() {
+	tt := map[string]*Trace{
+		"no hosts": {},
+		"duplicate host": {
+			Hosts: []*HostSpec{{Name: "host"}, {Name: "host"}},
+		},
+		"unknown parent": {
+			Hosts:         []*HostSpec{{Name: "host"}},
+			ResourcePools: []*ResourcePoolSpec{{Name: "pool", Parent: "org"}},
+		},
+		"unknown resource pool": {
+			Hosts: []*HostSpec{{Name: "host"}},
+			Jobs: []*JobSpec{
+				{Name: "job", ResourcePool: "pool", Instances: 1},
+			},
+		},
+		"non-leaf resource pool": {
+			Hosts: []*HostSpec{{Name: "host"}},
+			ResourcePools: []*ResourcePoolSpec{
+				{Name: "org"},
+				{Name: "pool", Parent: "org"},
+			},
+			Jobs: []*JobSpec{
+				{Name: "job", ResourcePool: "org", Instances: 1},
+			},
+		},
+		"no instances": {
+			Hosts:         []*HostSpec{{Name: "host"}},
+			ResourcePools: []*ResourcePoolSpec{{Name: "pool"}},
+			Jobs:          []*JobSpec{{Name: "job", ResourcePool: "pool"}},
+		},
+	}
+	for name, trace := range tt {
+		suite.Error(trace.validate(), name)
+	}
+}
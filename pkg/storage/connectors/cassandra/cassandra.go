@@ -305,11 +305,13 @@ func (c *cassandraConnector) create(
 	return nil
 }
 
-// buildSelectQuery builds a select query using base object and key columns
+// buildSelectQuery builds a select query using base object, key columns
+// and the clustering columns to read from
 func (c *cassandraConnector) buildSelectQuery(
 	ctx context.Context,
 	e *base.Definition,
 	keyCols []base.Column,
+	fromCols []base.Column,
 	colNamesToRead []string,
 ) (*gocql.Query, error) {
 
@@ -317,18 +319,21 @@ func (c *cassandraConnector) buildSelectQuery(
 	// names and use values in the session query call, so the order needs to be
 	// maintained.
 	keyColNames, keyColValues := splitColumnNameValue(keyCols)
+	fromColNames, fromColValues := splitColumnNameValue(fromCols)
 
 	// Prepare select statement
 	stmt, err := SelectStmt(
 		Table(e.Name),
 		Columns(colNamesToRead),
 		Conditions(keyColNames),
+		FromConditions(fromColNames),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.Session.Query(stmt, keyColValues...).WithContext(ctx), nil
+	return c.Session.Query(
+		stmt, append(keyColValues, fromColValues...)...).WithContext(ctx), nil
 }
 
 // Get fetches a record from DB using primary keys
@@ -340,7 +345,7 @@ func (c *cassandraConnector) Get(
 
 	colNamesToRead := e.GetColumnsToRead()
 
-	q, err := c.buildSelectQuery(ctx, e, keyCols, colNamesToRead)
+	q, err := c.buildSelectQuery(ctx, e, keyCols, nil, colNamesToRead)
 	if err != nil {
 		return nil, err
 	}
@@ -389,10 +394,21 @@ func (c *cassandraConnector) GetAllIter(
 	ctx context.Context,
 	e *base.Definition,
 	keyCols []base.Column,
+) (iter orm.Iterator, err error) {
+	return c.GetAllIterFrom(ctx, e, keyCols, nil)
+}
+
+// GetAllIterFrom gives an iterator to fetch all rows from DB whose
+// clustering columns are greater than or equal to the ones provided
+func (c *cassandraConnector) GetAllIterFrom(
+	ctx context.Context,
+	e *base.Definition,
+	keyCols []base.Column,
+	fromCols []base.Column,
 ) (iter orm.Iterator, err error) {
 	colNamesToRead := e.GetColumnsToRead()
 
-	q, err := c.buildSelectQuery(ctx, e, keyCols, colNamesToRead)
+	q, err := c.buildSelectQuery(ctx, e, keyCols, fromCols, colNamesToRead)
 	if err != nil {
 		return nil, err
 	}
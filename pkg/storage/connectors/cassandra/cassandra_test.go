@@ -374,6 +374,59 @@ func (suite *CassandraConnSuite) TestCreateGetAllIter() {
 	}
 }
 
+// TestCreateGetAllIterFrom tests the GetAllIterFrom operation
+func (suite *CassandraConnSuite) TestCreateGetAllIterFrom() {
+	// Definition stores schema information about an Object
+	obj := &base.Definition{
+		Name: testTableName2,
+		Key: &base.PrimaryKey{
+			PartitionKeys: []string{"id"},
+			ClusteringKeys: []*base.ClusteringKey{
+				{
+					Name:       "ck",
+					Descending: true,
+				},
+			},
+		},
+		// Column name to data type mapping of the object
+		ColumnToType: map[string]reflect.Type{
+			"id":   reflect.TypeOf(1),
+			"ck":   reflect.TypeOf(1),
+			"data": reflect.TypeOf("data"),
+			"name": reflect.TypeOf("name"),
+		},
+	}
+
+	// create the test rows in C*
+	for _, row := range testRowsWithCK {
+		err := connector.Create(context.Background(), obj, row)
+		suite.NoError(err)
+	}
+
+	// read the rows from C* test table for given keys from ck 15
+	iter, err := connector.GetAllIterFrom(
+		context.Background(),
+		obj,
+		keyRow,
+		[]base.Column{{Name: "ck", Value: uint64(15)}},
+	)
+	suite.NoError(err)
+	defer iter.Close()
+
+	row, err := iter.Next()
+	suite.NoError(err)
+	for _, col := range row {
+		if col.Name == "ck" {
+			suite.Equal(20, *col.Value.(*int))
+		}
+	}
+
+	// only the row from ck 15 is read
+	row, err = iter.Next()
+	suite.NoError(err)
+	suite.Nil(row)
+}
+
 // TestCreateIfNotExists tests the CreateIfNotExists operation
 func (suite *CassandraConnSuite) TestCreateIfNotExists() {
 	// Definition stores schema information about an Object
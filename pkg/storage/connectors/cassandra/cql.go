@@ -31,6 +31,8 @@ const (
 	columns = "Columns"
 	// conditions is used to indicate <,>,= conditions in the query
 	conditions = "Conditions"
+	// fromConditions is used to indicate >= conditions in the query
+	fromConditions = "FromConditions"
 	// updateCols is used to indicate update column names in the query
 	updates = "Updates"
 	// ifNotExist is used to indicate CAS write in the insert query
@@ -42,7 +44,8 @@ const (
 
 	// selectTemplate is used to construct a select query
 	selectTemplate = `SELECT {{ColumnFunc .Columns ", "}} FROM {{.Table}}` +
-		`{{WhereFunc .Conditions}}{{ConditionsFunc .Conditions " AND "}};`
+		`{{WhereFunc .Conditions}}{{ConditionsFunc .Conditions " AND "}}` +
+		`{{FromConditionsFunc .FromConditions}};`
 
 	// deleteTemplate is used to construct a delete query
 	deleteTemplate = `DELETE FROM {{.Table}} WHERE ` +
@@ -56,11 +59,12 @@ const (
 var (
 	// function map for populating CQL templates
 	funcMap = template.FuncMap{
-		"ColumnFunc":     strings.Join,
-		"QuestionMark":   questionMarkFunc,
-		"ConditionsFunc": conditionsFunc,
-		"WhereFunc":      whereFunc,
-		"ExistsFunc":     existsFunc,
+		"ColumnFunc":         strings.Join,
+		"QuestionMark":       questionMarkFunc,
+		"ConditionsFunc":     conditionsFunc,
+		"FromConditionsFunc": fromConditionsFunc,
+		"WhereFunc":          whereFunc,
+		"ExistsFunc":         existsFunc,
 	}
 
 	// insert CQL query template implementation
@@ -95,6 +99,16 @@ func conditionsFunc(conds []string, sep string) string {
 	return strings.Join(cstrs, sep)
 }
 
+// fromConditionsFunc adds a >=? condition to the select query for each
+// of the clustering columns to read from
+func fromConditionsFunc(conds []string) string {
+	var bb bytes.Buffer
+	for _, cond := range conds {
+		bb.WriteString(fmt.Sprintf(" AND %s>=?", cond))
+	}
+	return bb.String()
+}
+
 // whereFunc adds where clause to the select query
 func whereFunc(conds []string) string {
 	if len(conds) > 0 {
@@ -149,6 +163,14 @@ func Conditions(v interface{}) OptFunc {
 	}
 }
 
+// FromConditions sets the `>=` conditions of the `where` clause to the
+// cql statement
+func FromConditions(v interface{}) OptFunc {
+	return func(opt Option) {
+		opt[fromConditions] = v
+	}
+}
+
 // Updates set the `SET` clause to the cql statement
 func Updates(v interface{}) OptFunc {
 	return func(opt Option) {
@@ -108,6 +108,20 @@ func (suite *CassandraConnSuite) TestSelectStmt() {
 	}
 }
 
+// TestSelectFromStmt tests constructing select CQL query with >= conditions
+func (suite *CassandraConnSuite) TestSelectFromStmt() {
+	stmt, err := SelectStmt(
+		Table("table1"),
+		Columns([]string{"c1", "c2"}),
+		Conditions([]string{"c3"}),
+		FromConditions([]string{"c4", "c5"}),
+	)
+	suite.NoError(err)
+	suite.Equal(
+		"SELECT \"c1\", \"c2\" FROM \"table1\" WHERE c3=? AND c4>=? AND c5>=?;",
+		stmt)
+}
+
 // TestDeleteStmt tests constructing delete CQL query
 func (suite *CassandraConnSuite) TestDeleteStmt() {
 
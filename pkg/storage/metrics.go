@@ -118,6 +118,38 @@ type OrmJobMetrics struct {
 	SecretInfoUpdateFail tally.Counter
 	SecretInfoDelete     tally.Counter
 	SecretInfoDeleteFail tally.Counter
+
+	// job_template
+	JobTemplateCreate     tally.Counter
+	JobTemplateCreateFail tally.Counter
+	JobTemplateGet        tally.Counter
+	JobTemplateGetFail    tally.Counter
+	JobTemplateGetAll     tally.Counter
+	JobTemplateGetAllFail tally.Counter
+	JobTemplateDelete     tally.Counter
+	JobTemplateDeleteFail tally.Counter
+
+	// job_template_instance
+	JobTemplateInstanceCreate     tally.Counter
+	JobTemplateInstanceCreateFail tally.Counter
+	JobTemplateInstanceGetAll     tally.Counter
+	JobTemplateInstanceGetAllFail tally.Counter
+	JobTemplateInstanceDelete     tally.Counter
+	JobTemplateInstanceDeleteFail tally.Counter
+
+	// audit_log
+	AuditLogAdd        tally.Counter
+	AuditLogAddFail    tally.Counter
+	AuditLogGetAll     tally.Counter
+	AuditLogGetAllFail tally.Counter
+
+	// job_disruptions
+	JobDisruptionCreate     tally.Counter
+	JobDisruptionCreateFail tally.Counter
+	JobDisruptionUpdate     tally.Counter
+	JobDisruptionUpdateFail tally.Counter
+	JobDisruptionGetAll     tally.Counter
+	JobDisruptionGetAllFail tally.Counter
 }
 
 // TaskMetrics is a struct for tracking all the task related counters in the storage layer
@@ -296,6 +328,22 @@ type OrmTaskMetrics struct {
 	PodEventsGetFail tally.Counter
 }
 
+// OrmRespoolMetrics tracks counters for resource pool related tables
+// accessed through ORM layer
+type OrmRespoolMetrics struct {
+	// capacity_samples
+	CapacitySampleAdd        tally.Counter
+	CapacitySampleAddFail    tally.Counter
+	CapacitySampleGetAll     tally.Counter
+	CapacitySampleGetAllFail tally.Counter
+
+	// capacity_alerts
+	CapacityAlertAdd        tally.Counter
+	CapacityAlertAddFail    tally.Counter
+	CapacityAlertGetAll     tally.Counter
+	CapacityAlertGetAllFail tally.Counter
+}
+
 // Metrics is a struct for tracking all the general purpose counters that have relevance to the storage
 // layer, i.e. how many jobs and tasks were created/deleted in the storage layer
 type Metrics struct {
@@ -309,6 +357,7 @@ type Metrics struct {
 	WorkflowMetrics       *WorkflowMetrics
 	OrmJobMetrics         *OrmJobMetrics
 	OrmTaskMetrics        *OrmTaskMetrics
+	OrmRespoolMetrics     *OrmRespoolMetrics
 }
 
 // NewMetrics returns a new Metrics struct, with all metrics initialized and rooted at the given tally.Scope
@@ -576,6 +625,42 @@ func NewMetrics(scope tally.Scope) *Metrics {
 	secretInfoFailScope := secretInfoScope.Tagged(
 		map[string]string{"result": "fail"})
 
+	jobTemplateScope := ormScope.SubScope("job_template")
+	jobTemplateSuccessScope := jobTemplateScope.Tagged(
+		map[string]string{"result": "success"})
+	jobTemplateFailScope := jobTemplateScope.Tagged(
+		map[string]string{"result": "fail"})
+
+	jobTemplateInstanceScope := ormScope.SubScope("job_template_instance")
+	jobTemplateInstanceSuccessScope := jobTemplateInstanceScope.Tagged(
+		map[string]string{"result": "success"})
+	jobTemplateInstanceFailScope := jobTemplateInstanceScope.Tagged(
+		map[string]string{"result": "fail"})
+
+	auditLogScope := ormScope.SubScope("audit_log")
+	auditLogSuccessScope := auditLogScope.Tagged(
+		map[string]string{"result": "success"})
+	auditLogFailScope := auditLogScope.Tagged(
+		map[string]string{"result": "fail"})
+
+	jobDisruptionScope := ormScope.SubScope("job_disruptions")
+	jobDisruptionSuccessScope := jobDisruptionScope.Tagged(
+		map[string]string{"result": "success"})
+	jobDisruptionFailScope := jobDisruptionScope.Tagged(
+		map[string]string{"result": "fail"})
+
+	capacitySampleScope := ormScope.SubScope("capacity_samples")
+	capacitySampleSuccessScope := capacitySampleScope.Tagged(
+		map[string]string{"result": "success"})
+	capacitySampleFailScope := capacitySampleScope.Tagged(
+		map[string]string{"result": "fail"})
+
+	capacityAlertScope := ormScope.SubScope("capacity_alerts")
+	capacityAlertSuccessScope := capacityAlertScope.Tagged(
+		map[string]string{"result": "success"})
+	capacityAlertFailScope := capacityAlertScope.Tagged(
+		map[string]string{"result": "fail"})
+
 	ormJobMetrics := &OrmJobMetrics{
 		JobIndexCreate:     jobIndexSuccessScope.Counter("create"),
 		JobIndexCreateFail: jobIndexFailScope.Counter("create"),
@@ -606,6 +691,34 @@ func NewMetrics(scope tally.Scope) *Metrics {
 		SecretInfoUpdateFail: secretInfoFailScope.Counter("update"),
 		SecretInfoDelete:     secretInfoSuccessScope.Counter("delete"),
 		SecretInfoDeleteFail: secretInfoFailScope.Counter("delete"),
+
+		JobTemplateCreate:     jobTemplateSuccessScope.Counter("create"),
+		JobTemplateCreateFail: jobTemplateFailScope.Counter("create"),
+		JobTemplateGet:        jobTemplateSuccessScope.Counter("get"),
+		JobTemplateGetFail:    jobTemplateFailScope.Counter("get"),
+		JobTemplateGetAll:     jobTemplateSuccessScope.Counter("get_all"),
+		JobTemplateGetAllFail: jobTemplateFailScope.Counter("get_all"),
+		JobTemplateDelete:     jobTemplateSuccessScope.Counter("delete"),
+		JobTemplateDeleteFail: jobTemplateFailScope.Counter("delete"),
+
+		JobTemplateInstanceCreate:     jobTemplateInstanceSuccessScope.Counter("create"),
+		JobTemplateInstanceCreateFail: jobTemplateInstanceFailScope.Counter("create"),
+		JobTemplateInstanceGetAll:     jobTemplateInstanceSuccessScope.Counter("get_all"),
+		JobTemplateInstanceGetAllFail: jobTemplateInstanceFailScope.Counter("get_all"),
+		JobTemplateInstanceDelete:     jobTemplateInstanceSuccessScope.Counter("delete"),
+		JobTemplateInstanceDeleteFail: jobTemplateInstanceFailScope.Counter("delete"),
+
+		AuditLogAdd:        auditLogSuccessScope.Counter("add"),
+		AuditLogAddFail:    auditLogFailScope.Counter("add"),
+		AuditLogGetAll:     auditLogSuccessScope.Counter("get_all"),
+		AuditLogGetAllFail: auditLogFailScope.Counter("get_all"),
+
+		JobDisruptionCreate:     jobDisruptionSuccessScope.Counter("create"),
+		JobDisruptionCreateFail: jobDisruptionFailScope.Counter("create"),
+		JobDisruptionUpdate:     jobDisruptionSuccessScope.Counter("update"),
+		JobDisruptionUpdateFail: jobDisruptionFailScope.Counter("update"),
+		JobDisruptionGetAll:     jobDisruptionSuccessScope.Counter("get_all"),
+		JobDisruptionGetAllFail: jobDisruptionFailScope.Counter("get_all"),
 	}
 
 	ormTaskMetrics := &OrmTaskMetrics{
@@ -615,6 +728,18 @@ func NewMetrics(scope tally.Scope) *Metrics {
 		PodEventsGetFail: podEventsFailScope.Counter("get"),
 	}
 
+	ormRespoolMetrics := &OrmRespoolMetrics{
+		CapacitySampleAdd:        capacitySampleSuccessScope.Counter("add"),
+		CapacitySampleAddFail:    capacitySampleFailScope.Counter("add"),
+		CapacitySampleGetAll:     capacitySampleSuccessScope.Counter("get_all"),
+		CapacitySampleGetAllFail: capacitySampleFailScope.Counter("get_all"),
+
+		CapacityAlertAdd:        capacityAlertSuccessScope.Counter("add"),
+		CapacityAlertAddFail:    capacityAlertFailScope.Counter("add"),
+		CapacityAlertGetAll:     capacityAlertSuccessScope.Counter("get_all"),
+		CapacityAlertGetAllFail: capacityAlertFailScope.Counter("get_all"),
+	}
+
 	metrics := &Metrics{
 		JobMetrics:            jobMetrics,
 		TaskMetrics:           taskMetrics,
@@ -626,6 +751,7 @@ func NewMetrics(scope tally.Scope) *Metrics {
 		WorkflowMetrics:       workflowMetrics,
 		OrmJobMetrics:         ormJobMetrics,
 		OrmTaskMetrics:        ormTaskMetrics,
+		OrmRespoolMetrics:     ormRespoolMetrics,
 	}
 
 	return metrics
@@ -0,0 +1,257 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objects
+
+import (
+	"context"
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/private/auditsvc"
+
+	"github.com/uber/peloton/pkg/storage/objects/base"
+	"github.com/uber/peloton/pkg/storage/orm"
+
+	"github.com/gocql/gocql"
+	"github.com/pkg/errors"
+)
+
+const (
+	// auditLogUnknownTarget is the target of the audit records whose
+	// target could not be determined, since the partition key cannot
+	// be empty.
+	auditLogUnknownTarget = "unknown"
+	// auditLogDayFormat is the format of the day the audit records of a
+	// target are partitioned by, so that the partitions stay bounded.
+	auditLogDayFormat = "2006-01-02"
+)
+
+// AuditLogTTL is the time after which the audit records expire, which is
+// the default TTL of the audit_log table.
+const AuditLogTTL = 90 * 24 * time.Hour
+
+// init adds an AuditLogObject instance to the global list of storage objects
+func init() {
+	Objs = append(Objs, &AuditLogObject{})
+}
+
+// AuditLogObject corresponds to a row in audit_log table.
+type AuditLogObject struct {
+	// DB specific annotations
+	base.Object `cassandra:"name=audit_log, primaryKey=((target,day), record_time)"`
+
+	// Target is the resource targeted by the call
+	Target string `column:"name=target"`
+	// Day is the UTC day at which the call was received
+	Day string `column:"name=day"`
+	// RecordTime is the time at which the call was received
+	RecordTime gocql.UUID `column:"name=record_time"`
+	// Principal which made the call
+	Principal string `column:"name=principal"`
+	// Caller is the yarpc caller of the call
+	Caller string `column:"name=caller"`
+	// Service is the yarpc service which served the call
+	Service string `column:"name=service"`
+	// Procedure which was called
+	Procedure string `column:"name=procedure"`
+	// RequestDigest is the digest of the request body
+	RequestDigest string `column:"name=request_digest"`
+	// Result of the call
+	Result string `column:"name=result"`
+	// LatencyMs is the time taken to serve the call in milliseconds
+	LatencyMs int64 `column:"name=latency_ms"`
+}
+
+// AuditLogOps provides methods for manipulating audit_log table.
+type AuditLogOps interface {
+	// Add adds an audit record to the audit log of its target.
+	Add(ctx context.Context, record *auditsvc.AuditRecord) error
+
+	// GetAll returns the audit records of a target received between
+	// startTime and endTime, most recent first. At most limit records
+	// are returned if limit is not 0.
+	GetAll(
+		ctx context.Context,
+		target string,
+		startTime time.Time,
+		endTime time.Time,
+		limit uint32,
+	) ([]*auditsvc.AuditRecord, error)
+}
+
+// ensure that default implementation (auditLogOps) satisfies the interface
+var _ AuditLogOps = (*auditLogOps)(nil)
+
+// auditLogOps implements AuditLogOps using a particular Store
+type auditLogOps struct {
+	store *Store
+}
+
+// NewAuditLogOps constructs an AuditLogOps object for provided Store.
+func NewAuditLogOps(s *Store) AuditLogOps {
+	return &auditLogOps{store: s}
+}
+
+// toAuditRecord converts the AuditLogObject to an audit record
+func (a *AuditLogObject) toAuditRecord() *auditsvc.AuditRecord {
+	target := a.Target
+	if target == auditLogUnknownTarget {
+		target = ""
+	}
+	return &auditsvc.AuditRecord{
+		Principal:     a.Principal,
+		Caller:        a.Caller,
+		Service:       a.Service,
+		Procedure:     a.Procedure,
+		Target:        target,
+		RequestDigest: a.RequestDigest,
+		Result:        a.Result,
+		Time:          a.RecordTime.Time().UTC().Format(time.RFC3339Nano),
+		LatencyMs:     a.LatencyMs,
+	}
+}
+
+// auditLogTarget returns the partition key used for a target
+func auditLogTarget(target string) string {
+	if len(target) == 0 {
+		return auditLogUnknownTarget
+	}
+	return target
+}
+
+// Add adds an audit record in db
+func (d *auditLogOps) Add(
+	ctx context.Context,
+	record *auditsvc.AuditRecord,
+) error {
+	recordTime := time.Now()
+	if len(record.GetTime()) != 0 {
+		t, err := time.Parse(time.RFC3339Nano, record.GetTime())
+		if err != nil {
+			d.store.metrics.OrmJobMetrics.AuditLogAddFail.Inc(1)
+			return errors.Wrap(err, "Failed to parse audit record time")
+		}
+		recordTime = t
+	}
+
+	obj := &AuditLogObject{
+		Target:        auditLogTarget(record.GetTarget()),
+		Day:           recordTime.UTC().Format(auditLogDayFormat),
+		RecordTime:    gocql.UUIDFromTime(recordTime),
+		Principal:     record.GetPrincipal(),
+		Caller:        record.GetCaller(),
+		Service:       record.GetService(),
+		Procedure:     record.GetProcedure(),
+		RequestDigest: record.GetRequestDigest(),
+		Result:        record.GetResult(),
+		LatencyMs:     record.GetLatencyMs(),
+	}
+	if err := d.store.oClient.Create(ctx, obj); err != nil {
+		d.store.metrics.OrmJobMetrics.AuditLogAddFail.Inc(1)
+		return err
+	}
+
+	d.store.metrics.OrmJobMetrics.AuditLogAdd.Inc(1)
+	return nil
+}
+
+// GetAll gets the audit records of a target from db. Only the day
+// partitions of the target between startTime and endTime are read, from
+// the most recent one, and the reads stop once limit records are read.
+// startTime is moved up to the expiry of the audit records.
+func (d *auditLogOps) GetAll(
+	ctx context.Context,
+	target string,
+	startTime time.Time,
+	endTime time.Time,
+	limit uint32,
+) ([]*auditsvc.AuditRecord, error) {
+	table, err := orm.TableFromObject(&AuditLogObject{})
+	if err != nil {
+		d.store.metrics.OrmJobMetrics.AuditLogGetAllFail.Inc(1)
+		return nil, err
+	}
+
+	startTime = startTime.UTC()
+	endTime = endTime.UTC()
+	if expiry := time.Now().UTC().Add(-AuditLogTTL); startTime.Before(expiry) {
+		startTime = expiry
+	}
+	startDay := startTime.Format(auditLogDayFormat)
+
+	var records []*auditsvc.AuditRecord
+	for day := endTime; day.Format(auditLogDayFormat) >= startDay; day = day.AddDate(0, 0, -1) {
+		done, err := d.getDay(
+			ctx, table, target, day, startTime, endTime, limit, &records)
+		if err != nil {
+			d.store.metrics.OrmJobMetrics.AuditLogGetAllFail.Inc(1)
+			return nil, err
+		}
+		if done {
+			break
+		}
+	}
+
+	d.store.metrics.OrmJobMetrics.AuditLogGetAll.Inc(1)
+	return records, nil
+}
+
+// getDay appends the audit records of a target in a day partition
+// received between startTime and endTime to records, most recent first,
+// and returns whether limit records are read.
+func (d *auditLogOps) getDay(
+	ctx context.Context,
+	table *orm.Table,
+	target string,
+	day time.Time,
+	startTime time.Time,
+	endTime time.Time,
+	limit uint32,
+	records *[]*auditsvc.AuditRecord,
+) (bool, error) {
+	iter, err := d.store.oClient.GetAllIter(ctx, &AuditLogObject{
+		Target: auditLogTarget(target),
+		Day:    day.Format(auditLogDayFormat),
+	})
+	if err != nil {
+		return false, err
+	}
+	defer iter.Close()
+
+	for {
+		if limit != 0 && uint32(len(*records)) >= limit {
+			return true, nil
+		}
+
+		row, err := iter.Next()
+		if err != nil {
+			return false, err
+		}
+		if row == nil {
+			return false, nil
+		}
+
+		obj := &AuditLogObject{}
+		table.SetObjectFromRow(obj, row)
+		recordTime := obj.RecordTime.Time()
+		if recordTime.After(endTime) {
+			continue
+		}
+		if recordTime.Before(startTime) {
+			// the records of the partition are ordered from the most recent
+			return true, nil
+		}
+		*records = append(*records, obj.toAuditRecord())
+	}
+}
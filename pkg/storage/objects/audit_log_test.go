@@ -0,0 +1,147 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objects
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/private/auditsvc"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditLogObjectTestSuite struct {
+	suite.Suite
+}
+
+func TestAuditLogObjectSuite(t *testing.T) {
+	suite.Run(t, new(AuditLogObjectTestSuite))
+}
+
+// TestAddGetAllAuditLog tests adding and getting audit records in DB
+func (s *AuditLogObjectTestSuite) TestAddGetAllAuditLog() {
+	db := NewAuditLogOps(testStore)
+	ctx := context.Background()
+	target := uuid.New()
+	now := time.Now().UTC()
+
+	records := []*auditsvc.AuditRecord{
+		{
+			Principal:     "alice",
+			Caller:        "peloton-cli",
+			Service:       "peloton-jobmgr",
+			Procedure:     "peloton.api.v1alpha.job.stateless.svc.JobService::CreateJob",
+			Target:        target,
+			RequestDigest: "digest1",
+			Result:        "ok",
+			Time:          now.Add(-time.Minute).Format(time.RFC3339Nano),
+			LatencyMs:     10,
+		},
+		{
+			Principal:     "carol",
+			Caller:        "peloton-cli",
+			Service:       "peloton-jobmgr",
+			Procedure:     "peloton.api.v1alpha.job.stateless.svc.JobService::StartJob",
+			Target:        target,
+			RequestDigest: "digest0",
+			Result:        "ok",
+			Time:          now.AddDate(0, 0, -2).Format(time.RFC3339Nano),
+			LatencyMs:     5,
+		},
+		{
+			Principal:     "bob",
+			Caller:        "peloton-cli",
+			Service:       "peloton-jobmgr",
+			Procedure:     "peloton.api.v1alpha.job.stateless.svc.JobService::StopJob",
+			Target:        target,
+			RequestDigest: "digest2",
+			Result:        "ok",
+			Time:          now.Format(time.RFC3339Nano),
+			LatencyMs:     20,
+		},
+	}
+	for _, record := range records {
+		s.NoError(db.Add(ctx, record))
+	}
+
+	start := now.Add(-AuditLogTTL)
+	end := now.Add(time.Minute)
+	result, err := db.GetAll(ctx, target, start, end, 0)
+	s.NoError(err)
+	s.Len(result, 3)
+	// most recent record is returned first, across the day partitions
+	s.Equal("bob", result[0].GetPrincipal())
+	s.Equal(records[2].GetProcedure(), result[0].GetProcedure())
+	s.Equal(records[2].GetRequestDigest(), result[0].GetRequestDigest())
+	s.Equal(int64(20), result[0].GetLatencyMs())
+	s.Equal("alice", result[1].GetPrincipal())
+	s.Equal("carol", result[2].GetPrincipal())
+
+	// the reads stop at the limit
+	result, err = db.GetAll(ctx, target, start, end, 2)
+	s.NoError(err)
+	s.Len(result, 2)
+	s.Equal("bob", result[0].GetPrincipal())
+	s.Equal("alice", result[1].GetPrincipal())
+
+	// only the records within the time range are returned
+	result, err = db.GetAll(
+		ctx, target, now.AddDate(0, 0, -3), now.Add(-time.Second), 0)
+	s.NoError(err)
+	s.Len(result, 2)
+	s.Equal("alice", result[0].GetPrincipal())
+	s.Equal("carol", result[1].GetPrincipal())
+
+	result, err = db.GetAll(ctx, target, now.Add(-time.Hour), end, 0)
+	s.NoError(err)
+	s.Len(result, 2)
+	s.Equal("bob", result[0].GetPrincipal())
+	s.Equal("alice", result[1].GetPrincipal())
+
+	result, err = db.GetAll(ctx, uuid.New(), start, end, 0)
+	s.NoError(err)
+	s.Empty(result)
+
+	// invalid record time
+	s.Error(db.Add(ctx, &auditsvc.AuditRecord{Target: target, Time: "invalid"}))
+}
+
+// TestAddUnknownTarget tests adding an audit record without target
+func (s *AuditLogObjectTestSuite) TestAddUnknownTarget() {
+	db := NewAuditLogOps(testStore)
+	ctx := context.Background()
+	principal := "user_" + uuid.New()[:8]
+
+	s.NoError(db.Add(ctx, &auditsvc.AuditRecord{
+		Principal: principal,
+		Procedure: "peloton.api.v0.respool.ResourceManager::CreateResourcePool",
+		Result:    "ok",
+	}))
+
+	now := time.Now()
+	result, err := db.GetAll(ctx, "", now.Add(-time.Hour), now.Add(time.Minute), 0)
+	s.NoError(err)
+	found := false
+	for _, record := range result {
+		if record.GetPrincipal() == principal {
+			found = true
+			s.Empty(record.GetTarget())
+		}
+	}
+	s.True(found)
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objects
+
+import (
+	"context"
+	"time"
+
+	pb_respool "github.com/uber/peloton/.gen/peloton/api/v0/respool"
+
+	"github.com/uber/peloton/pkg/storage/objects/base"
+	"github.com/uber/peloton/pkg/storage/orm"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// CapacityHistoryTTL is the time after which the capacity samples and
+// alerts expire in db, it must match the default_time_to_live of the
+// capacity_samples and capacity_alerts tables.
+const CapacityHistoryTTL = 30 * 24 * time.Hour
+
+// init adds the capacity history object instances to the global list of
+// storage objects
+func init() {
+	Objs = append(Objs, &CapacitySampleObject{})
+	Objs = append(Objs, &CapacityAlertObject{})
+}
+
+// CapacitySampleObject corresponds to a row in capacity_samples table.
+type CapacitySampleObject struct {
+	// DB specific annotations
+	base.Object `cassandra:"name=capacity_samples, primaryKey=((respool_id), sample_time)"`
+
+	// RespoolID of the sampled resource pool
+	RespoolID string `column:"name=respool_id"`
+	// SampleTime is the time at which the resource pool was sampled
+	SampleTime time.Time `column:"name=sample_time"`
+	// Sample is the serialized capacity sample
+	Sample []byte `column:"name=sample"`
+}
+
+// CapacityAlertObject corresponds to a row in capacity_alerts table.
+type CapacityAlertObject struct {
+	// DB specific annotations
+	base.Object `cassandra:"name=capacity_alerts, primaryKey=((respool_id), alert_time, alert_type, kind)"`
+
+	// RespoolID of the resource pool the alert was raised for
+	RespoolID string `column:"name=respool_id"`
+	// AlertTime is the time at which the alert was raised
+	AlertTime time.Time `column:"name=alert_time"`
+	// AlertType is the type of the alert
+	AlertType string `column:"name=alert_type"`
+	// Kind is the resource kind of the alert
+	Kind string `column:"name=kind"`
+	// Message is the description of the alert
+	Message string `column:"name=message"`
+}
+
+// CapacityHistoryOps provides methods for manipulating capacity_samples
+// and capacity_alerts tables.
+type CapacityHistoryOps interface {
+	// AddSample adds a capacity sample of a resource pool.
+	AddSample(
+		ctx context.Context,
+		respoolID string,
+		sampleTime time.Time,
+		sample *pb_respool.CapacitySample,
+	) error
+
+	// GetSamples returns the capacity samples of a resource pool since
+	// the time provided, oldest first.
+	GetSamples(
+		ctx context.Context,
+		respoolID string,
+		since time.Time,
+	) ([]*pb_respool.CapacitySample, error)
+
+	// AddAlert adds a capacity alert raised for a resource pool.
+	AddAlert(
+		ctx context.Context,
+		respoolID string,
+		alertTime time.Time,
+		alert *pb_respool.CapacityAlert,
+	) error
+
+	// GetAlerts returns the capacity alerts raised for a resource pool
+	// since the time provided, oldest first.
+	GetAlerts(
+		ctx context.Context,
+		respoolID string,
+		since time.Time,
+	) ([]*pb_respool.CapacityAlert, error)
+}
+
+// ensure that default implementation (capacityHistoryOps) satisfies the interface
+var _ CapacityHistoryOps = (*capacityHistoryOps)(nil)
+
+// capacityHistoryOps implements CapacityHistoryOps using a particular Store
+type capacityHistoryOps struct {
+	store *Store
+}
+
+// NewCapacityHistoryOps constructs a CapacityHistoryOps object for
+// provided Store.
+func NewCapacityHistoryOps(s *Store) CapacityHistoryOps {
+	return &capacityHistoryOps{store: s}
+}
+
+// AddSample adds a capacity sample of a resource pool in db
+func (d *capacityHistoryOps) AddSample(
+	ctx context.Context,
+	respoolID string,
+	sampleTime time.Time,
+	sample *pb_respool.CapacitySample,
+) error {
+	sampleBuffer, err := proto.Marshal(sample)
+	if err != nil {
+		d.store.metrics.OrmRespoolMetrics.CapacitySampleAddFail.Inc(1)
+		return errors.Wrap(err, "Failed to marshal capacity sample")
+	}
+
+	obj := &CapacitySampleObject{
+		RespoolID:  respoolID,
+		SampleTime: sampleTime.UTC(),
+		Sample:     sampleBuffer,
+	}
+	if err := d.store.oClient.Create(ctx, obj); err != nil {
+		d.store.metrics.OrmRespoolMetrics.CapacitySampleAddFail.Inc(1)
+		return err
+	}
+
+	d.store.metrics.OrmRespoolMetrics.CapacitySampleAdd.Inc(1)
+	return nil
+}
+
+// GetSamples gets the capacity samples of a resource pool since the time
+// provided from db. Only the rows of the partition from that time are read.
+func (d *capacityHistoryOps) GetSamples(
+	ctx context.Context,
+	respoolID string,
+	since time.Time,
+) ([]*pb_respool.CapacitySample, error) {
+	table, err := orm.TableFromObject(&CapacitySampleObject{})
+	if err != nil {
+		d.store.metrics.OrmRespoolMetrics.CapacitySampleGetAllFail.Inc(1)
+		return nil, err
+	}
+
+	iter, err := d.store.oClient.GetAllIterFrom(ctx, &CapacitySampleObject{
+		RespoolID:  respoolID,
+		SampleTime: since.UTC(),
+	}, "SampleTime")
+	if err != nil {
+		d.store.metrics.OrmRespoolMetrics.CapacitySampleGetAllFail.Inc(1)
+		return nil, err
+	}
+	defer iter.Close()
+
+	var samples []*pb_respool.CapacitySample
+	for {
+		row, err := iter.Next()
+		if err != nil {
+			d.store.metrics.OrmRespoolMetrics.CapacitySampleGetAllFail.Inc(1)
+			return nil, err
+		}
+		if row == nil {
+			break
+		}
+
+		o := &CapacitySampleObject{}
+		table.SetObjectFromRow(o, row)
+		sample := &pb_respool.CapacitySample{}
+		if err := proto.Unmarshal(o.Sample, sample); err != nil {
+			d.store.metrics.OrmRespoolMetrics.CapacitySampleGetAllFail.Inc(1)
+			return nil, errors.Wrap(err, "Failed to unmarshal capacity sample")
+		}
+		samples = append(samples, sample)
+	}
+
+	d.store.metrics.OrmRespoolMetrics.CapacitySampleGetAll.Inc(1)
+	return samples, nil
+}
+
+// AddAlert adds a capacity alert raised for a resource pool in db
+func (d *capacityHistoryOps) AddAlert(
+	ctx context.Context,
+	respoolID string,
+	alertTime time.Time,
+	alert *pb_respool.CapacityAlert,
+) error {
+	obj := &CapacityAlertObject{
+		RespoolID: respoolID,
+		AlertTime: alertTime.UTC(),
+		AlertType: alert.GetType().String(),
+		Kind:      alert.GetKind(),
+		Message:   alert.GetMessage(),
+	}
+	if err := d.store.oClient.Create(ctx, obj); err != nil {
+		d.store.metrics.OrmRespoolMetrics.CapacityAlertAddFail.Inc(1)
+		return err
+	}
+
+	d.store.metrics.OrmRespoolMetrics.CapacityAlertAdd.Inc(1)
+	return nil
+}
+
+// GetAlerts gets the capacity alerts raised for a resource pool since the
+// time provided from db. Only the rows of the partition from that time
+// are read.
+func (d *capacityHistoryOps) GetAlerts(
+	ctx context.Context,
+	respoolID string,
+	since time.Time,
+) ([]*pb_respool.CapacityAlert, error) {
+	table, err := orm.TableFromObject(&CapacityAlertObject{})
+	if err != nil {
+		d.store.metrics.OrmRespoolMetrics.CapacityAlertGetAllFail.Inc(1)
+		return nil, err
+	}
+
+	iter, err := d.store.oClient.GetAllIterFrom(ctx, &CapacityAlertObject{
+		RespoolID: respoolID,
+		AlertTime: since.UTC(),
+	}, "AlertTime")
+	if err != nil {
+		d.store.metrics.OrmRespoolMetrics.CapacityAlertGetAllFail.Inc(1)
+		return nil, err
+	}
+	defer iter.Close()
+
+	var alerts []*pb_respool.CapacityAlert
+	for {
+		row, err := iter.Next()
+		if err != nil {
+			d.store.metrics.OrmRespoolMetrics.CapacityAlertGetAllFail.Inc(1)
+			return nil, err
+		}
+		if row == nil {
+			break
+		}
+
+		o := &CapacityAlertObject{}
+		table.SetObjectFromRow(o, row)
+		alerts = append(alerts, &pb_respool.CapacityAlert{
+			Time: o.AlertTime.UTC().Format(time.RFC3339),
+			Kind: o.Kind,
+			Type: pb_respool.CapacityAlert_Type(
+				pb_respool.CapacityAlert_Type_value[o.AlertType]),
+			Message: o.Message,
+		})
+	}
+
+	d.store.metrics.OrmRespoolMetrics.CapacityAlertGetAll.Inc(1)
+	return alerts, nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objects
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb_respool "github.com/uber/peloton/.gen/peloton/api/v0/respool"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type CapacityHistoryObjectTestSuite struct {
+	suite.Suite
+}
+
+func TestCapacityHistoryObjectSuite(t *testing.T) {
+	suite.Run(t, new(CapacityHistoryObjectTestSuite))
+}
+
+// TestAddGetSamples tests adding and getting the capacity samples of a
+// resource pool in DB
+func (s *CapacityHistoryObjectTestSuite) TestAddGetSamples() {
+	db := NewCapacityHistoryOps(testStore)
+	ctx := context.Background()
+	respoolID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for i, reservation := range []float64{10, 20} {
+		sampleTime := now.Add(time.Duration(i-1) * time.Hour)
+		s.NoError(db.AddSample(ctx, respoolID, sampleTime,
+			&pb_respool.CapacitySample{
+				Time: sampleTime.Format(time.RFC3339),
+				Usages: []*pb_respool.CapacityUsage{
+					{Kind: "cpu", Reservation: reservation},
+				},
+			}))
+	}
+
+	samples, err := db.GetSamples(ctx, respoolID, now.Add(-2*time.Hour))
+	s.NoError(err)
+	s.Len(samples, 2)
+	// oldest sample is returned first
+	s.Equal(now.Add(-time.Hour).Format(time.RFC3339), samples[0].GetTime())
+	s.Equal(10.0, samples[0].GetUsages()[0].GetReservation())
+	s.Equal(20.0, samples[1].GetUsages()[0].GetReservation())
+
+	// only the samples since the time provided are read
+	samples, err = db.GetSamples(ctx, respoolID, now.Add(-time.Minute))
+	s.NoError(err)
+	s.Len(samples, 1)
+	s.Equal(20.0, samples[0].GetUsages()[0].GetReservation())
+
+	samples, err = db.GetSamples(ctx, uuid.New(), now.Add(-2*time.Hour))
+	s.NoError(err)
+	s.Empty(samples)
+}
+
+// TestAddGetAlerts tests adding and getting the capacity alerts raised for
+// a resource pool in DB
+func (s *CapacityHistoryObjectTestSuite) TestAddGetAlerts() {
+	db := NewCapacityHistoryOps(testStore)
+	ctx := context.Background()
+	respoolID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// alerts of different types raised at the same time are all kept
+	for _, alertType := range []pb_respool.CapacityAlert_Type{
+		pb_respool.CapacityAlert_HEADROOM,
+		pb_respool.CapacityAlert_EXHAUSTION,
+	} {
+		s.NoError(db.AddAlert(ctx, respoolID, now,
+			&pb_respool.CapacityAlert{
+				Time:    now.Format(time.RFC3339),
+				Kind:    "cpu",
+				Type:    alertType,
+				Message: "alert",
+			}))
+	}
+
+	alerts, err := db.GetAlerts(ctx, respoolID, now.Add(-time.Hour))
+	s.NoError(err)
+	s.Len(alerts, 2)
+	s.Equal(pb_respool.CapacityAlert_EXHAUSTION, alerts[0].GetType())
+	s.Equal(pb_respool.CapacityAlert_HEADROOM, alerts[1].GetType())
+	s.Equal(now.Format(time.RFC3339), alerts[0].GetTime())
+	s.Equal("cpu", alerts[0].GetKind())
+	s.Equal("alert", alerts[0].GetMessage())
+
+	alerts, err = db.GetAlerts(ctx, respoolID, now.Add(time.Minute))
+	s.NoError(err)
+	s.Empty(alerts)
+}
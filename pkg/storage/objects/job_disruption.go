@@ -0,0 +1,226 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objects
+
+import (
+	"context"
+	"time"
+
+	"github.com/uber/peloton/pkg/storage/objects/base"
+	"github.com/uber/peloton/pkg/storage/orm"
+
+	"github.com/gocql/gocql"
+	log "github.com/sirupsen/logrus"
+)
+
+// JobDisruptionTTL is the time after which the disruptions of a job expire
+// in db, it must match the default_time_to_live of job_disruptions table.
+const JobDisruptionTTL = 90 * 24 * time.Hour
+
+// init adds a JobDisruptionObject instance to the global list of storage objects
+func init() {
+	Objs = append(Objs, &JobDisruptionObject{})
+}
+
+// JobDisruptionObject corresponds to a row in job_disruptions table.
+type JobDisruptionObject struct {
+	// DB specific annotations
+	base.Object `cassandra:"name=job_disruptions, primaryKey=((job_id), disruption_time)"`
+
+	// JobID of the disrupted instance
+	JobID string `column:"name=job_id"`
+	// DisruptionTime is the time at which the instance was disrupted
+	DisruptionTime gocql.UUID `column:"name=disruption_time"`
+	// InstanceID of the disrupted instance
+	InstanceID uint32 `column:"name=instance_id"`
+	// Cause is the termination reason of the disrupted instance
+	Cause string `column:"name=cause"`
+	// DurationMs is the time taken by the instance to run again
+	// in milliseconds, 0 if it is not running again yet
+	DurationMs int64 `column:"name=duration_ms"`
+	// Resolved is true once the instance is running again
+	Resolved bool `column:"name=resolved"`
+}
+
+// JobDisruption is a disruption of an instance of a job.
+type JobDisruption struct {
+	// Key of the disruption in db
+	Key gocql.UUID
+	// InstanceID of the disrupted instance
+	InstanceID uint32
+	// Cause is the termination reason of the disrupted instance
+	Cause string
+	// Time at which the instance was disrupted
+	Time time.Time
+	// Duration taken by the instance to run again
+	Duration time.Duration
+	// Resolved is true once the instance is running again
+	Resolved bool
+}
+
+// JobDisruptionOps provides methods for manipulating job_disruptions table.
+type JobDisruptionOps interface {
+	// Create records a disruption of an instance of a job, and returns
+	// the key of the disruption which can be used to update its duration.
+	Create(
+		ctx context.Context,
+		jobID string,
+		instanceID uint32,
+		cause string,
+		disruptionTime time.Time,
+	) (gocql.UUID, error)
+
+	// UpdateDuration updates the time taken by the instance to run again
+	// after the disruption identified by the key, and marks the disruption
+	// as resolved.
+	UpdateDuration(
+		ctx context.Context,
+		jobID string,
+		key gocql.UUID,
+		duration time.Duration,
+	) error
+
+	// GetAll returns the disruptions of a job since the time provided,
+	// most recent first.
+	GetAll(
+		ctx context.Context,
+		jobID string,
+		since time.Time,
+	) ([]*JobDisruption, error)
+}
+
+// ensure that default implementation (jobDisruptionOps) satisfies the interface
+var _ JobDisruptionOps = (*jobDisruptionOps)(nil)
+
+// jobDisruptionOps implements JobDisruptionOps using a particular Store
+type jobDisruptionOps struct {
+	store *Store
+}
+
+// NewJobDisruptionOps constructs a JobDisruptionOps object for provided Store.
+func NewJobDisruptionOps(s *Store) JobDisruptionOps {
+	return &jobDisruptionOps{store: s}
+}
+
+// Create adds a disruption of an instance of a job in db
+func (d *jobDisruptionOps) Create(
+	ctx context.Context,
+	jobID string,
+	instanceID uint32,
+	cause string,
+	disruptionTime time.Time,
+) (gocql.UUID, error) {
+	obj := &JobDisruptionObject{
+		JobID:          jobID,
+		DisruptionTime: gocql.UUIDFromTime(disruptionTime),
+		InstanceID:     instanceID,
+		Cause:          cause,
+	}
+	if err := d.store.oClient.Create(ctx, obj); err != nil {
+		d.store.metrics.OrmJobMetrics.JobDisruptionCreateFail.Inc(1)
+		return gocql.UUID{}, err
+	}
+
+	d.store.metrics.OrmJobMetrics.JobDisruptionCreate.Inc(1)
+	return obj.DisruptionTime, nil
+}
+
+// UpdateDuration updates the duration of a disruption in db. The columns
+// written by an update expire after the table TTL from the time of the
+// update, so the whole row is read and rewritten for all its columns to
+// expire together instead of leaving a partial row behind.
+func (d *jobDisruptionOps) UpdateDuration(
+	ctx context.Context,
+	jobID string,
+	key gocql.UUID,
+	duration time.Duration,
+) error {
+	obj := &JobDisruptionObject{
+		JobID:          jobID,
+		DisruptionTime: key,
+	}
+	if err := d.store.oClient.Get(ctx, obj); err != nil {
+		log.WithField("job_id", jobID).
+			WithField("disruption_time", key.String()).
+			WithError(err).
+			Error("Failed to get job_disruptions")
+		d.store.metrics.OrmJobMetrics.JobDisruptionUpdateFail.Inc(1)
+		return err
+	}
+
+	obj.DurationMs = int64(duration / time.Millisecond)
+	obj.Resolved = true
+	if err := d.store.oClient.Create(ctx, obj); err != nil {
+		log.WithField("job_id", jobID).
+			WithField("disruption_time", key.String()).
+			WithError(err).
+			Error("Failed to update job_disruptions")
+		d.store.metrics.OrmJobMetrics.JobDisruptionUpdateFail.Inc(1)
+		return err
+	}
+
+	d.store.metrics.OrmJobMetrics.JobDisruptionUpdate.Inc(1)
+	return nil
+}
+
+// GetAll gets the disruptions of a job since the time provided from db.
+// Only the rows of the partition from that time are read.
+func (d *jobDisruptionOps) GetAll(
+	ctx context.Context,
+	jobID string,
+	since time.Time,
+) ([]*JobDisruption, error) {
+	table, err := orm.TableFromObject(&JobDisruptionObject{})
+	if err != nil {
+		d.store.metrics.OrmJobMetrics.JobDisruptionGetAllFail.Inc(1)
+		return nil, err
+	}
+
+	iter, err := d.store.oClient.GetAllIterFrom(ctx, &JobDisruptionObject{
+		JobID:          jobID,
+		DisruptionTime: gocql.UUIDFromTime(since),
+	}, "DisruptionTime")
+	if err != nil {
+		d.store.metrics.OrmJobMetrics.JobDisruptionGetAllFail.Inc(1)
+		return nil, err
+	}
+	defer iter.Close()
+
+	var disruptions []*JobDisruption
+	for {
+		row, err := iter.Next()
+		if err != nil {
+			d.store.metrics.OrmJobMetrics.JobDisruptionGetAllFail.Inc(1)
+			return nil, err
+		}
+		if row == nil {
+			break
+		}
+
+		o := &JobDisruptionObject{}
+		table.SetObjectFromRow(o, row)
+		disruptions = append(disruptions, &JobDisruption{
+			Key:        o.DisruptionTime,
+			InstanceID: o.InstanceID,
+			Cause:      o.Cause,
+			Time:       o.DisruptionTime.Time(),
+			Duration:   time.Duration(o.DurationMs) * time.Millisecond,
+			Resolved:   o.Resolved,
+		})
+	}
+
+	d.store.metrics.OrmJobMetrics.JobDisruptionGetAll.Inc(1)
+	return disruptions, nil
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objects
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gocql/gocql"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type JobDisruptionObjectTestSuite struct {
+	suite.Suite
+}
+
+func TestJobDisruptionObjectSuite(t *testing.T) {
+	suite.Run(t, new(JobDisruptionObjectTestSuite))
+}
+
+// TestCreateUpdateGetAllJobDisruption tests creating, updating and
+// getting the disruptions of a job in DB
+func (s *JobDisruptionObjectTestSuite) TestCreateUpdateGetAllJobDisruption() {
+	db := NewJobDisruptionOps(testStore)
+	ctx := context.Background()
+	jobID := uuid.New()
+	now := time.Now()
+
+	key, err := db.Create(
+		ctx, jobID, 0, "TERMINATION_REASON_KILLED_HOST_MAINTENANCE",
+		now.Add(-time.Hour))
+	s.NoError(err)
+	_, err = db.Create(
+		ctx, jobID, 1, "TERMINATION_REASON_PREEMPTED_RESOURCES", now)
+	s.NoError(err)
+
+	s.NoError(db.UpdateDuration(ctx, jobID, key, 90*time.Second))
+
+	result, err := db.GetAll(ctx, jobID, now.Add(-2*time.Hour))
+	s.NoError(err)
+	s.Len(result, 2)
+	// most recent disruption is returned first
+	s.Equal(uint32(1), result[0].InstanceID)
+	s.Equal("TERMINATION_REASON_PREEMPTED_RESOURCES", result[0].Cause)
+	s.Equal(time.Duration(0), result[0].Duration)
+	s.False(result[0].Resolved)
+	s.Equal(key, result[1].Key)
+	s.Equal(uint32(0), result[1].InstanceID)
+	s.Equal("TERMINATION_REASON_KILLED_HOST_MAINTENANCE", result[1].Cause)
+	s.Equal(90*time.Second, result[1].Duration)
+	s.True(result[1].Resolved)
+
+	// only the disruptions since the time provided are read
+	result, err = db.GetAll(ctx, jobID, now.Add(-time.Minute))
+	s.NoError(err)
+	s.Len(result, 1)
+	s.Equal(uint32(1), result[0].InstanceID)
+
+	result, err = db.GetAll(ctx, uuid.New(), now.Add(-2*time.Hour))
+	s.NoError(err)
+	s.Empty(result)
+
+	// a disruption which expired is not written again
+	s.Error(db.UpdateDuration(
+		ctx, jobID, gocql.UUIDFromTime(now.Add(-time.Minute)), time.Second))
+	result, err = db.GetAll(ctx, jobID, now.Add(-2*time.Hour))
+	s.NoError(err)
+	s.Len(result, 2)
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objects
+
+import (
+	"context"
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template"
+
+	"github.com/uber/peloton/pkg/storage/objects/base"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// jobTemplateIndexKey is the partition key of all the rows in
+// job_template_index table.
+const jobTemplateIndexKey = "job_templates"
+
+// init adds the job template object instances to the global list of
+// storage objects
+func init() {
+	Objs = append(Objs, &JobTemplateObject{})
+	Objs = append(Objs, &JobTemplateIndexObject{})
+}
+
+// JobTemplateObject corresponds to a row in job_template table.
+type JobTemplateObject struct {
+	// DB specific annotations
+	base.Object `cassandra:"name=job_template, primaryKey=((name), version)"`
+
+	// Name of the template
+	Name string `column:"name=name"`
+	// Version of the template
+	Version uint64 `column:"name=version"`
+	// Spec of the template
+	Spec []byte `column:"name=spec"`
+	// Creation time of the template version
+	CreationTime time.Time `column:"name=creation_time"`
+}
+
+// JobTemplateIndexObject corresponds to a row in job_template_index table.
+type JobTemplateIndexObject struct {
+	// DB specific annotations
+	base.Object `cassandra:"name=job_template_index, primaryKey=((index_key), name)"`
+
+	// IndexKey is the partition key shared by all the templates
+	IndexKey string `column:"name=index_key"`
+	// Name of the template
+	Name string `column:"name=name"`
+	// LatestVersion is the latest version of the template
+	LatestVersion uint64 `column:"name=latest_version"`
+	// Update time of the template
+	UpdateTime time.Time `column:"name=update_time"`
+}
+
+// JobTemplateOps provides methods for manipulating job_template and
+// job_template_index tables.
+type JobTemplateOps interface {
+	// Create inserts a new version of a template and makes it the latest
+	// version of the template. It fails if the version already exists.
+	Create(
+		ctx context.Context,
+		spec *template.TemplateSpec,
+		version uint64,
+	) error
+
+	// Get retrieves a version of a template. The latest version is
+	// retrieved if version is 0.
+	Get(
+		ctx context.Context,
+		name string,
+		version uint64,
+	) (*template.TemplateInfo, error)
+
+	// GetAll retrieves the latest version of all the templates.
+	GetAll(ctx context.Context) ([]*template.TemplateInfo, error)
+
+	// Delete removes all the versions of a template.
+	Delete(ctx context.Context, name string) error
+}
+
+// ensure that default implementation (jobTemplateOps) satisfies the interface
+var _ JobTemplateOps = (*jobTemplateOps)(nil)
+
+// jobTemplateOps implements JobTemplateOps using a particular Store
+type jobTemplateOps struct {
+	store *Store
+}
+
+// NewJobTemplateOps constructs a JobTemplateOps object for provided Store.
+func NewJobTemplateOps(s *Store) JobTemplateOps {
+	return &jobTemplateOps{store: s}
+}
+
+// toTemplateInfo converts the JobTemplateObject to template info
+func (j *JobTemplateObject) toTemplateInfo() (*template.TemplateInfo, error) {
+	spec := &template.TemplateSpec{}
+	if err := proto.Unmarshal(j.Spec, spec); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal template spec")
+	}
+	return &template.TemplateInfo{
+		Spec:         spec,
+		Version:      j.Version,
+		CreationTime: j.CreationTime.Format(time.RFC3339Nano),
+	}, nil
+}
+
+// Create creates a new template version in db
+func (d *jobTemplateOps) Create(
+	ctx context.Context,
+	spec *template.TemplateSpec,
+	version uint64,
+) error {
+	specBuffer, err := proto.Marshal(spec)
+	if err != nil {
+		d.store.metrics.OrmJobMetrics.JobTemplateCreateFail.Inc(1)
+		return errors.Wrap(err, "Failed to marshal template spec")
+	}
+
+	now := time.Now().UTC()
+	obj := &JobTemplateObject{
+		Name:         spec.GetName(),
+		Version:      version,
+		Spec:         specBuffer,
+		CreationTime: now,
+	}
+	if err := d.store.oClient.CreateIfNotExists(ctx, obj); err != nil {
+		d.store.metrics.OrmJobMetrics.JobTemplateCreateFail.Inc(1)
+		return err
+	}
+
+	indexObj := &JobTemplateIndexObject{
+		IndexKey:      jobTemplateIndexKey,
+		Name:          spec.GetName(),
+		LatestVersion: version,
+		UpdateTime:    now,
+	}
+	if err := d.store.oClient.Create(ctx, indexObj); err != nil {
+		d.store.metrics.OrmJobMetrics.JobTemplateCreateFail.Inc(1)
+		return err
+	}
+
+	d.store.metrics.OrmJobMetrics.JobTemplateCreate.Inc(1)
+	return nil
+}
+
+// Get gets a template version from db
+func (d *jobTemplateOps) Get(
+	ctx context.Context,
+	name string,
+	version uint64,
+) (*template.TemplateInfo, error) {
+	if version == 0 {
+		indexObj := &JobTemplateIndexObject{
+			IndexKey: jobTemplateIndexKey,
+			Name:     name,
+		}
+		if err := d.store.oClient.Get(ctx, indexObj); err != nil {
+			d.store.metrics.OrmJobMetrics.JobTemplateGetFail.Inc(1)
+			return nil, err
+		}
+		version = indexObj.LatestVersion
+	}
+
+	obj := &JobTemplateObject{
+		Name:    name,
+		Version: version,
+	}
+	if err := d.store.oClient.Get(ctx, obj); err != nil {
+		d.store.metrics.OrmJobMetrics.JobTemplateGetFail.Inc(1)
+		return nil, err
+	}
+
+	info, err := obj.toTemplateInfo()
+	if err != nil {
+		d.store.metrics.OrmJobMetrics.JobTemplateGetFail.Inc(1)
+		return nil, err
+	}
+
+	d.store.metrics.OrmJobMetrics.JobTemplateGet.Inc(1)
+	return info, nil
+}
+
+// GetAll gets the latest version of all the templates from db
+func (d *jobTemplateOps) GetAll(
+	ctx context.Context,
+) ([]*template.TemplateInfo, error) {
+	objs, err := d.store.oClient.GetAll(
+		ctx,
+		&JobTemplateIndexObject{IndexKey: jobTemplateIndexKey},
+	)
+	if err != nil {
+		d.store.metrics.OrmJobMetrics.JobTemplateGetAllFail.Inc(1)
+		return nil, err
+	}
+
+	var infos []*template.TemplateInfo
+	for _, obj := range objs {
+		indexObj := obj.(*JobTemplateIndexObject)
+		info, err := d.Get(ctx, indexObj.Name, indexObj.LatestVersion)
+		if err != nil {
+			d.store.metrics.OrmJobMetrics.JobTemplateGetAllFail.Inc(1)
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+
+	d.store.metrics.OrmJobMetrics.JobTemplateGetAll.Inc(1)
+	return infos, nil
+}
+
+// Delete deletes all the versions of a template from db
+func (d *jobTemplateOps) Delete(ctx context.Context, name string) error {
+	indexObj := &JobTemplateIndexObject{
+		IndexKey: jobTemplateIndexKey,
+		Name:     name,
+	}
+	if err := d.store.oClient.Get(ctx, indexObj); err != nil {
+		d.store.metrics.OrmJobMetrics.JobTemplateDeleteFail.Inc(1)
+		return err
+	}
+
+	// Remove the template from the index first so that a partially
+	// deleted template is not visible.
+	if err := d.store.oClient.Delete(ctx, indexObj); err != nil {
+		d.store.metrics.OrmJobMetrics.JobTemplateDeleteFail.Inc(1)
+		return err
+	}
+
+	for v := uint64(1); v <= indexObj.LatestVersion; v++ {
+		obj := &JobTemplateObject{
+			Name:    name,
+			Version: v,
+		}
+		if err := d.store.oClient.Delete(ctx, obj); err != nil {
+			d.store.metrics.OrmJobMetrics.JobTemplateDeleteFail.Inc(1)
+			return err
+		}
+	}
+
+	d.store.metrics.OrmJobMetrics.JobTemplateDelete.Inc(1)
+	return nil
+}
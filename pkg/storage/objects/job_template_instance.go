@@ -0,0 +1,175 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objects
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+
+	"github.com/uber/peloton/pkg/storage/objects/base"
+
+	"github.com/pkg/errors"
+)
+
+// init adds a JobTemplateInstanceObject instance to the global list of
+// storage objects
+func init() {
+	Objs = append(Objs, &JobTemplateInstanceObject{})
+}
+
+// JobTemplateInstanceObject corresponds to a row in job_template_instance
+// table.
+type JobTemplateInstanceObject struct {
+	// DB specific annotations
+	base.Object `cassandra:"name=job_template_instance, primaryKey=((template_name), job_id)"`
+
+	// Name of the template
+	TemplateName string `column:"name=template_name"`
+	// JobID of the job instantiated from the template
+	JobID string `column:"name=job_id"`
+	// Version of the template the job is running
+	Version uint64 `column:"name=version"`
+	// Parameters used to instantiate the job (JSON encoded)
+	Parameters string `column:"name=parameters"`
+	// Creation time of the row
+	CreationTime time.Time `column:"name=creation_time"`
+}
+
+// JobTemplateInstanceOps provides methods for manipulating
+// job_template_instance table.
+type JobTemplateInstanceOps interface {
+	// Create inserts or overwrites the row of a job instantiated
+	// from a template.
+	Create(
+		ctx context.Context,
+		id *v1alphapeloton.JobID,
+		ref *template.TemplateReference,
+	) error
+
+	// GetAll retrieves the template references of all the jobs
+	// instantiated from a template, keyed by job ID.
+	GetAll(
+		ctx context.Context,
+		templateName string,
+	) (map[string]*template.TemplateReference, error)
+
+	// Delete removes the row of a job instantiated from a template.
+	Delete(
+		ctx context.Context,
+		templateName string,
+		id *v1alphapeloton.JobID,
+	) error
+}
+
+// ensure that default implementation (jobTemplateInstanceOps) satisfies
+// the interface
+var _ JobTemplateInstanceOps = (*jobTemplateInstanceOps)(nil)
+
+// jobTemplateInstanceOps implements JobTemplateInstanceOps using a
+// particular Store
+type jobTemplateInstanceOps struct {
+	store *Store
+}
+
+// NewJobTemplateInstanceOps constructs a JobTemplateInstanceOps object for
+// provided Store.
+func NewJobTemplateInstanceOps(s *Store) JobTemplateInstanceOps {
+	return &jobTemplateInstanceOps{store: s}
+}
+
+// Create creates a JobTemplateInstanceObject in db
+func (d *jobTemplateInstanceOps) Create(
+	ctx context.Context,
+	id *v1alphapeloton.JobID,
+	ref *template.TemplateReference,
+) error {
+	params, err := json.Marshal(ref.GetParameters())
+	if err != nil {
+		d.store.metrics.OrmJobMetrics.JobTemplateInstanceCreateFail.Inc(1)
+		return errors.Wrap(err, "Failed to marshal template parameters")
+	}
+
+	obj := &JobTemplateInstanceObject{
+		TemplateName: ref.GetName(),
+		JobID:        id.GetValue(),
+		Version:      ref.GetVersion(),
+		Parameters:   string(params),
+		CreationTime: time.Now().UTC(),
+	}
+	if err := d.store.oClient.Create(ctx, obj); err != nil {
+		d.store.metrics.OrmJobMetrics.JobTemplateInstanceCreateFail.Inc(1)
+		return err
+	}
+
+	d.store.metrics.OrmJobMetrics.JobTemplateInstanceCreate.Inc(1)
+	return nil
+}
+
+// GetAll gets all the jobs instantiated from a template from db
+func (d *jobTemplateInstanceOps) GetAll(
+	ctx context.Context,
+	templateName string,
+) (map[string]*template.TemplateReference, error) {
+	objs, err := d.store.oClient.GetAll(
+		ctx,
+		&JobTemplateInstanceObject{TemplateName: templateName},
+	)
+	if err != nil {
+		d.store.metrics.OrmJobMetrics.JobTemplateInstanceGetAllFail.Inc(1)
+		return nil, err
+	}
+
+	refs := make(map[string]*template.TemplateReference)
+	for _, obj := range objs {
+		instanceObj := obj.(*JobTemplateInstanceObject)
+		params := make(map[string]string)
+		if err := json.Unmarshal(
+			[]byte(instanceObj.Parameters), &params); err != nil {
+			d.store.metrics.OrmJobMetrics.JobTemplateInstanceGetAllFail.Inc(1)
+			return nil, errors.Wrap(err, "Failed to unmarshal template parameters")
+		}
+		refs[instanceObj.JobID] = &template.TemplateReference{
+			Name:       instanceObj.TemplateName,
+			Version:    instanceObj.Version,
+			Parameters: params,
+		}
+	}
+
+	d.store.metrics.OrmJobMetrics.JobTemplateInstanceGetAll.Inc(1)
+	return refs, nil
+}
+
+// Delete deletes a JobTemplateInstanceObject from db
+func (d *jobTemplateInstanceOps) Delete(
+	ctx context.Context,
+	templateName string,
+	id *v1alphapeloton.JobID,
+) error {
+	obj := &JobTemplateInstanceObject{
+		TemplateName: templateName,
+		JobID:        id.GetValue(),
+	}
+	if err := d.store.oClient.Delete(ctx, obj); err != nil {
+		d.store.metrics.OrmJobMetrics.JobTemplateInstanceDeleteFail.Inc(1)
+		return err
+	}
+
+	d.store.metrics.OrmJobMetrics.JobTemplateInstanceDelete.Inc(1)
+	return nil
+}
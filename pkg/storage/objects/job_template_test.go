@@ -0,0 +1,125 @@
+// Copyright (c) 2019 Uber Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objects
+
+import (
+	"context"
+	"testing"
+
+	"github.com/uber/peloton/.gen/peloton/api/v1alpha/job/template"
+	v1alphapeloton "github.com/uber/peloton/.gen/peloton/api/v1alpha/peloton"
+
+	"github.com/gocql/gocql"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type JobTemplateObjectTestSuite struct {
+	suite.Suite
+}
+
+func TestJobTemplateObjectSuite(t *testing.T) {
+	suite.Run(t, new(JobTemplateObjectTestSuite))
+}
+
+// TestCreateGetDeleteJobTemplate tests creating, getting and deleting
+// template versions in DB
+func (s *JobTemplateObjectTestSuite) TestCreateGetDeleteJobTemplate() {
+	db := NewJobTemplateOps(testStore)
+	ctx := context.Background()
+	name := "template_" + uuid.New()[:8]
+
+	spec := &template.TemplateSpec{
+		Name: name,
+		Parameters: []*template.ParameterSpec{
+			{
+				Name:         "instances",
+				Type:         template.ParameterType_PARAMETER_TYPE_INT,
+				DefaultValue: "1",
+			},
+		},
+		Body: "instancecount: {{.instances}}",
+	}
+	s.NoError(db.Create(ctx, spec, 1))
+
+	// creating an existing version fails
+	s.Error(db.Create(ctx, spec, 1))
+
+	spec2 := *spec
+	spec2.Body = "instancecount: 2"
+	s.NoError(db.Create(ctx, &spec2, 2))
+
+	info, err := db.Get(ctx, name, 0)
+	s.NoError(err)
+	s.Equal(uint64(2), info.GetVersion())
+	s.Equal(spec2.GetBody(), info.GetSpec().GetBody())
+
+	info, err = db.Get(ctx, name, 1)
+	s.NoError(err)
+	s.Equal(uint64(1), info.GetVersion())
+	s.Equal(spec.GetBody(), info.GetSpec().GetBody())
+	s.Equal(spec.GetParameters(), info.GetSpec().GetParameters())
+
+	infos, err := db.GetAll(ctx)
+	s.NoError(err)
+	found := false
+	for _, info := range infos {
+		if info.GetSpec().GetName() == name {
+			found = true
+			s.Equal(uint64(2), info.GetVersion())
+		}
+	}
+	s.True(found)
+
+	s.NoError(db.Delete(ctx, name))
+	_, err = db.Get(ctx, name, 0)
+	s.Equal(gocql.ErrNotFound, err)
+	_, err = db.Get(ctx, name, 1)
+	s.Equal(gocql.ErrNotFound, err)
+}
+
+// TestCreateGetAllDeleteJobTemplateInstance tests recording the jobs
+// instantiated from a template in DB
+func (s *JobTemplateObjectTestSuite) TestCreateGetAllDeleteJobTemplateInstance() {
+	db := NewJobTemplateInstanceOps(testStore)
+	ctx := context.Background()
+	name := "template_" + uuid.New()[:8]
+	jobID := &v1alphapeloton.JobID{Value: uuid.New()}
+
+	ref := &template.TemplateReference{
+		Name:       name,
+		Version:    1,
+		Parameters: map[string]string{"instances": "3"},
+	}
+	s.NoError(db.Create(ctx, jobID, ref))
+
+	refs, err := db.GetAll(ctx, name)
+	s.NoError(err)
+	s.Equal(map[string]*template.TemplateReference{
+		jobID.GetValue(): ref,
+	}, refs)
+
+	// the record is overwritten on rollout
+	ref.Version = 2
+	s.NoError(db.Create(ctx, jobID, ref))
+	refs, err = db.GetAll(ctx, name)
+	s.NoError(err)
+	s.Equal(uint64(2), refs[jobID.GetValue()].GetVersion())
+
+	s.NoError(db.Delete(ctx, name, jobID))
+	refs, err = db.GetAll(ctx, name)
+	s.NoError(err)
+	s.Empty(refs)
+}
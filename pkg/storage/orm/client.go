@@ -38,6 +38,14 @@ type Client interface {
 	// GetAllIter provides an iterative way to fetch all storage objects
 	// for the partition key
 	GetAllIter(ctx context.Context, e base.Object) (Iterator, error)
+	// GetAllIterFrom provides an iterative way to fetch the storage objects
+	// for the partition key whose fromFields are greater than or equal to
+	// the ones of the object provided
+	GetAllIterFrom(
+		ctx context.Context,
+		e base.Object,
+		fromFields ...string,
+	) (Iterator, error)
 	// Update updates the storage object in the database
 	// The fields to be updated can be specified as fieldsToUpdate which is
 	// a variable list of field names and is to be optionally specified by
@@ -176,6 +184,30 @@ func (c *client) GetAllIter(
 	return c.connector.GetAllIter(ctx, &table.Definition, keyRow)
 }
 
+// GetAllIterFrom fetches a list of base objects for the given partition key
+// using an iterator, starting from the values of the clustering fields
+// provided. The base object provided must contain the value of its
+// partition key and of the fromFields
+func (c *client) GetAllIterFrom(
+	ctx context.Context,
+	e base.Object,
+	fromFields ...string,
+) (Iterator, error) {
+
+	// lookup if a table exists for this object, return error if not found
+	table, err := c.getTable(e)
+	if err != nil {
+		return nil, err
+	}
+
+	// build a partition key row and a row of the clustering columns to read
+	// from, from storage object
+	keyRow := table.GetPartitionKeyRowFromObject(e)
+	fromRow := table.GetRowFromObject(e, fromFields...)
+
+	return c.connector.GetAllIterFrom(ctx, &table.Definition, keyRow, fromRow)
+}
+
 // Update updates the storage object in the database
 func (c *client) Update(
 	ctx context.Context,
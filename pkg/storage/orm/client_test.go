@@ -273,6 +273,43 @@ func (suite *ORMTestSuite) TestClientGetAllIter() {
 	suite.Error(err)
 }
 
+// TestClientGetAllIterFrom tests client GetAllIterFrom operation on valid and
+// invalid entities
+func (suite *ORMTestSuite) TestClientGetAllIterFrom() {
+	defer suite.ctrl.Finish()
+	conn := ormmocks.NewMockConnector(suite.ctrl)
+	iter := ormmocks.NewMockIterator(suite.ctrl)
+
+	// ValidObject instance with the partition key and the clustering
+	// column to read from set
+	e := &ValidObject{
+		ID:   uint64(1),
+		Name: "test",
+	}
+
+	conn.EXPECT().GetAllIterFrom(
+		suite.ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, _ *base.Definition,
+			row []base.Column, from []base.Column) {
+			suite.Len(row, 1)
+			suite.Equal("id", row[0].Name)
+			suite.Equal(e.ID, row[0].Value)
+			suite.Len(from, 1)
+			suite.Equal("name", from[0].Name)
+			suite.Equal(e.Name, from[0].Value)
+		}).Return(iter, nil)
+
+	client, err := orm.NewClient(conn, &ValidObject{})
+	suite.NoError(err)
+
+	it, err := client.GetAllIterFrom(suite.ctx, e, "Name")
+	suite.NoError(err)
+	suite.Equal(iter, it)
+
+	_, err = client.GetAllIterFrom(suite.ctx, &InvalidObject1{}, "Name")
+	suite.Error(err)
+}
+
 // TestClientUpdate tests client update operation on valid and invalid entities
 func (suite *ORMTestSuite) TestClientUpdate() {
 	defer suite.ctrl.Finish()
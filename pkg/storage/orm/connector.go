@@ -53,6 +53,16 @@ type Connector interface {
 		keys []base.Column,
 	) (Iterator, error)
 
+	// GetAllIterFrom gives an iterator over the rows by partition key of
+	// base object whose clustering columns are greater than or equal to
+	// the ones provided
+	GetAllIterFrom(
+		ctx context.Context,
+		e *base.Definition,
+		keys []base.Column,
+		from []base.Column,
+	) (Iterator, error)
+
 	// Update updates a row in the DB for the base object
 	Update(
 		ctx context.Context,